@@ -5,32 +5,26 @@ import (
 	"encoding/json"
 	"fmt"
 	"log/slog"
+	"maps"
+	"regexp"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/drpaneas/devlica/internal/ghcrawl"
 	"github.com/drpaneas/devlica/internal/llm"
+	"github.com/drpaneas/devlica/internal/progress"
+	"github.com/drpaneas/devlica/internal/prompts"
+	"github.com/drpaneas/devlica/internal/stats"
 	"github.com/drpaneas/devlica/internal/textutil"
 	"golang.org/x/sync/errgroup"
 )
 
-const maxChunkSize = 30000 // bytes per LLM input chunk
-
-const evidenceCompressionPrompt = `You are preparing evidence for a downstream persona analysis.
-Summarize this %s chunk into high-signal bullet points.
-
-Requirements:
-- Preserve concrete examples and exact phrasing when possible.
-- Keep what is distinctive or repeated.
-- Include counts/pattern frequencies if visible in this chunk.
-- Do not add speculation.
-
-Chunk %d/%d:
-%s`
-
 // SynthesisResult holds the structured fields produced by the LLM synthesis step.
 type SynthesisResult struct {
 	CodingPhilosophy      string `json:"coding_philosophy"`
 	CodeStyleRules        string `json:"code_style_rules"`
+	CommitMessageStyle    string `json:"commit_message_style"`
 	ReviewPriorities      string `json:"review_priorities"`
 	ReviewDecisionStyle   string `json:"review_decision_style"`
 	ReviewNonBlockingNits string `json:"review_non_blocking_nits"`
@@ -39,26 +33,81 @@ type SynthesisResult struct {
 	CommunicationPatterns string `json:"communication_patterns"`
 	TestingPhilosophy     string `json:"testing_philosophy"`
 	DistinctiveTraits     string `json:"distinctive_traits"`
+	AntiPatterns          string `json:"anti_patterns"`
 	DeveloperInterests    string `json:"developer_interests"`
 	ActivityPatterns      string `json:"activity_patterns"`
 	ProjectPatterns       string `json:"project_patterns"`
+	Automation            string `json:"automation"`
 	CollaborationStyle    string `json:"collaboration_style"`
+	MaintainerBehavior    string `json:"maintainer_behavior"`
+	StyleEvolution        string `json:"style_evolution"`
 	CodeExamples          string `json:"code_examples"`
+	// Evidence maps a field's JSON name to permalinks of crawled activity
+	// that back it up.
+	Evidence map[string][]string `json:"evidence,omitempty"`
+	// Examples maps each evidence link to the crawled text behind it.
+	Examples map[string]Example `json:"examples,omitempty"`
+	// Confidence maps a field's JSON name to how well the data supports it.
+	Confidence map[string]Confidence `json:"confidence,omitempty"`
+	// Unsupported maps a field's JSON name to the statements the grounding
+	// check removed from it.
+	Unsupported map[string][]string `json:"unsupported,omitempty"`
 }
 
 // Persona holds all analysis results for a developer.
 type Persona struct {
 	Username          string
 	CodeStyle         string
+	CommitMessages    string
 	ReviewStyle       string
 	Communication     string
 	DeveloperIdentity string
-	Synthesis         *SynthesisResult
+	StyleEvolution    string
+	Automation        string
+	// LanguageStyles holds rules per language for polyglot developers, most
+	// used language first. It is empty when one language dominates.
+	LanguageStyles []LanguageStyle
+	Synthesis      *SynthesisResult
+	// Suggestions records how often inline review comments carry a
+	// ```suggestion block, so review impersonation can match the habit.
+	Suggestions stats.SuggestionStats
+	// Phrases are the expressions, emoji, and sign-offs that recur in their
+	// comments, and generic reviewer phrases they avoid.
+	Phrases stats.PhraseStats
+	// Metrics are hard numbers computed from the crawl without an LLM.
+	Metrics stats.Metrics
+	// CommitStats describes the shape of their commit messages, computed
+	// from every crawled commit.
+	CommitStats stats.CommitMessageStats
+	// PRStats describes how they structure pull request descriptions.
+	PRStats stats.PRBodyStats
+	// Usage records how much of each data source reached the model.
+	Usage DataUsage
+	// Sparse lists why the crawl was too thin for a confident persona. It
+	// is empty for a normal profile.
+	Sparse []string
+	// Metadata records how and from what the persona was produced. The
+	// analyzer only knows the username; callers fill in the rest.
+	Metadata PersonaMetadata
 }
 
 // Analyzer uses an LLM provider to extract a developer persona from crawled data.
 type Analyzer struct {
-	provider llm.Provider
+	provider      llm.Provider
+	ensemble      llm.Provider
+	prompts       *prompts.Set
+	contextWindow int
+	// artifactDir, when set, receives each dimension's raw output.
+	artifactDir    string
+	artifactFilter func(string) string
+	progress       progress.Func
+	grounding      bool
+	// skillTokens, when positive, caps the synthesized sections of each
+	// skill in skillFields.
+	skillTokens int
+	skillFields map[string][]string
+	// recency, when set, weights each dated item by age for sampling.
+	recency func(age time.Duration) float64
 }
 
 // New returns an Analyzer that uses the given LLM provider.
@@ -66,169 +115,432 @@ func New(provider llm.Provider) *Analyzer {
 	return &Analyzer{provider: provider}
 }
 
+// SetPrompts makes the analyzer prefer the given prompt overrides over its
+// compiled-in prompts.
+func (a *Analyzer) SetPrompts(s *prompts.Set) {
+	a.prompts = s
+}
+
+// SetProgress makes the analyzer report each dimension, language, and the
+// synthesis to fn as they start and finish.
+func (a *Analyzer) SetProgress(fn progress.Func) {
+	a.progress = fn
+}
+
+// SetRecency makes the analyses read a sample of the crawl in which each
+// commit, review, and comment is kept with probability weight(age), so a
+// persona can favor who the developer is now. Hard metrics and the
+// style-evolution analysis still see every item.
+func (a *Analyzer) SetRecency(weight func(age time.Duration) float64) {
+	a.recency = weight
+}
+
+// PromptNames lists the analyzer prompts that can be overridden.
+var PromptNames = []string{
+	"system", "code-style", "commit-messages", "language-style", "review-style",
+	"communication", "developer-identity", "style-evolution", "synthesis",
+	"automation", "evidence-compression", "evidence-reduce", "reconcile",
+	"era-comparison", "grounding", "sparse-synthesis", "json-repair",
+	"skill-compression",
+}
+
+// complete renders the named prompt, falling back to def, and sends it with
+// the system prompt.
+func (a *Analyzer) complete(ctx context.Context, name, def string, vars ...prompts.Var) (string, error) {
+	return a.completeWith(ctx, a.provider, name, def, nil, vars...)
+}
+
+// completeJSON is complete for prompts whose reply is parsed as JSON: it
+// asks the provider for an object matching schema. Parsing still tolerates
+// fences and stray prose, since not every provider can be constrained.
+func (a *Analyzer) completeJSON(ctx context.Context, name, def string, schema json.RawMessage, vars ...prompts.Var) (string, error) {
+	return a.completeWith(ctx, a.provider, name, def, &llm.CompleteOptions{JSONSchema: schema}, vars...)
+}
+
+func (a *Analyzer) completeWith(ctx context.Context, provider llm.Provider, name, def string, opts *llm.CompleteOptions, vars ...prompts.Var) (string, error) {
+	system, err := a.prompts.Render("system", systemPrompt)
+	if err != nil {
+		return "", err
+	}
+	prompt, err := a.prompts.Render(name, def, vars...)
+	if err != nil {
+		return "", err
+	}
+	resp, err := provider.Complete(ctx, system, prompt, opts)
+	progress.Record(ctx, system+prompt, resp)
+	return resp, err
+}
+
+// Dimensions names the analyses Reanalyze can re-run, after the prompts
+// they use.
+var Dimensions = []string{
+	"code-style", "commit-messages", "review-style", "communication",
+	"developer-identity", "language-style", "style-evolution", "automation",
+}
+
 // Analyze runs parallel LLM analyses on the crawl data and synthesizes a Persona.
 func (a *Analyzer) Analyze(ctx context.Context, username string, data *ghcrawl.CrawlResult) (*Persona, error) {
-	persona := &Persona{Username: username}
+	return a.run(ctx, username, data, nil, nil)
+}
+
+// Reanalyze re-runs only the given dimensions on data, takes every other
+// analysis from previous, and synthesizes the persona again. It is how one
+// prompt can be iterated on without paying for the whole pipeline.
+func (a *Analyzer) Reanalyze(ctx context.Context, username string, data *ghcrawl.CrawlResult, previous *Persona, only []string) (*Persona, error) {
+	if len(only) == 0 {
+		return nil, fmt.Errorf("no dimensions to re-analyze")
+	}
+	for _, d := range only {
+		if !slices.Contains(Dimensions, d) {
+			return nil, fmt.Errorf("unknown dimension %q: must be one of %s", d, strings.Join(Dimensions, ", "))
+		}
+	}
+	return a.run(ctx, username, data, previous, only)
+}
+
+// run analyzes the dimensions in only, or all of them when previous is nil,
+// and synthesizes the result.
+func (a *Analyzer) run(ctx context.Context, username string, data *ghcrawl.CrawlResult, previous *Persona, only []string) (*Persona, error) {
+	persona := &Persona{
+		Username:    username,
+		Suggestions: stats.ReviewSuggestions(data),
+		Phrases:     stats.SignaturePhrases(data),
+		Metrics:     stats.Compute(data),
+		CommitStats: stats.CommitMessages(data),
+		PRStats:     stats.PRBodies(data),
+		Sparse:      Sparsity(CountData(data)),
+	}
+	if previous != nil {
+		persona.CodeStyle = previous.CodeStyle
+		persona.CommitMessages = previous.CommitMessages
+		persona.ReviewStyle = previous.ReviewStyle
+		persona.Communication = previous.Communication
+		persona.DeveloperIdentity = previous.DeveloperIdentity
+		persona.StyleEvolution = previous.StyleEvolution
+		persona.Automation = previous.Automation
+		persona.LanguageStyles = previous.LanguageStyles
+	}
+	want := func(dimension string) bool {
+		return previous == nil || slices.Contains(only, dimension)
+	}
+	// The analyses read a recency-weighted sample; the metrics above, the
+	// eras, and evidence checks keep the whole crawl.
+	full := data
+	if a.recency != nil {
+		data = full.SampleByAge(time.Now(), a.recency)
+		before, after := CountData(full), CountData(data)
+		slog.Info("sampled recent activity more heavily",
+			"commits", after.Commits, "of_commits", before.Commits,
+			"reviews", after.Reviews, "of_reviews", before.Reviews,
+			"issue_comments", after.IssueComments, "of_issue_comments", before.IssueComments,
+		)
+	}
 
 	codeSamples := buildCodeSamplesText(data)
 	commitDiffs := buildCommitDiffsText(data)
-	reviewActivity := buildReviewDataText(data)
+	commitStatsText := stats.CommitMessages(data).Format()
+	commitMessagesText := buildCommitMessagesText(data)
+	// Clustering calls the embeddings API, so it only runs when needed.
+	var reviewActivity string
+	if want("review-style") {
+		reviewActivity = buildReviewDataText(a.clusterReviewComments(ctx, data))
+	}
 	prDescriptions := buildPRDescriptionsText(data)
 	issueComments := buildIssueCommentsText(data)
 	authoredIssues := buildAuthoredIssuesText(data)
 	releaseNotes := buildReleasesText(data)
 	discussionsText := buildDiscussionsText(data)
+	reviewReplies := buildReviewRepliesText(data)
+	docsText := buildDocsText(data)
 	profileText := buildProfileText(data)
 	starredText := buildStarredReposText(data)
+	// Like review clustering, interest areas cost embedding calls.
+	var interestsText string
+	if want("developer-identity") {
+		interestsText = buildInterestsText(a.interestAreas(ctx, data))
+	}
 	gistsText := buildGistsText(data)
 	orgsText := buildOrgsText(data)
 	externalPRsText := buildExternalPRsText(data)
 	eventsText := buildEventsText(data)
+	timelineText := buildTimelineText(data)
 	projectsText := buildProjectsText(data)
 	wikiText := buildWikiPagesText(data)
+	receptionText := buildReceptionText(data)
+	dependenciesText := buildDependenciesText(data)
+	refNamesText := buildRefNamesText(data)
+	triageText := buildTriageText(data)
+	erasText := buildErasText(full)
+	toolingText := buildToolingText(data)
+	ciRunsText := buildWorkflowRunsText(data)
+	var languages []languageCode
+	if want("language-style") {
+		languages = groupCodeByLanguage(data)
+		persona.LanguageStyles = make([]LanguageStyle, len(languages))
+	}
+	usage := &usageLog{}
 
 	g, gCtx := errgroup.WithContext(ctx)
+	dimension := func(name string, f func(context.Context) error) {
+		if want(name) {
+			g.Go(func() error { return a.progress.Step(gCtx, name, f) })
+		}
+	}
 
-	g.Go(func() error {
+	dimension("code-style", func(ctx context.Context) error {
 		if codeSamples == "" && commitDiffs == "" {
 			slog.Warn("no code samples or commit diffs found, skipping code style analysis")
 			persona.CodeStyle = "Insufficient data for code style analysis."
 			return nil
 		}
-		codeSamplesPrepared, err := a.compressToFit(gCtx, "code samples", codeSamples)
-		if err != nil {
-			return fmt.Errorf("compressing code samples: %w", err)
-		}
-		commitDiffsPrepared, err := a.compressToFit(gCtx, "commit diffs", commitDiffs)
+		prepared, err := a.prepare(ctx, usage, "code-style", len(codeStylePrompt),
+			source{"code samples", codeSamples},
+			source{"commit diffs", commitDiffs},
+		)
 		if err != nil {
-			return fmt.Errorf("compressing commit diffs: %w", err)
+			return err
 		}
 		slog.Info("analyzing code style")
-		prompt := fmt.Sprintf(codeStylePrompt, username, codeSamplesPrepared, commitDiffsPrepared)
-		result, err := a.provider.Complete(gCtx, systemPrompt, prompt, nil)
+		result, err := a.analyze(ctx, "code style", username, "code-style", codeStylePrompt,
+			prompts.Arg("Username", username),
+			prompts.Arg("CodeSamples", prepared[0]),
+			prompts.Arg("CommitDiffs", prepared[1]),
+		)
 		if err != nil {
 			return fmt.Errorf("code style analysis: %w", err)
 		}
 		persona.CodeStyle = result
+		a.saveArtifact("code-style", result)
 		return nil
 	})
 
-	g.Go(func() error {
+	dimension("commit-messages", func(ctx context.Context) error {
+		if commitMessagesText == "" {
+			slog.Warn("no commits found, skipping commit message analysis")
+			persona.CommitMessages = "Insufficient data for commit message analysis."
+			return nil
+		}
+		prepared, err := a.prepare(ctx, usage, "commit-messages", len(commitMessagePrompt)+len(commitStatsText),
+			source{"commit messages", commitMessagesText},
+		)
+		if err != nil {
+			return err
+		}
+		slog.Info("analyzing commit messages")
+		result, err := a.analyze(ctx, "commit messages", username, "commit-messages", commitMessagePrompt,
+			prompts.Arg("Username", username),
+			prompts.Arg("CommitStats", commitStatsText),
+			prompts.Arg("CommitMessages", prepared[0]),
+		)
+		if err != nil {
+			return fmt.Errorf("commit message analysis: %w", err)
+		}
+		persona.CommitMessages = result
+		a.saveArtifact("commit-messages", result)
+		return nil
+	})
+
+	dimension("review-style", func(ctx context.Context) error {
 		if reviewActivity == "" {
 			slog.Warn("no review comments found, skipping review style analysis")
 			persona.ReviewStyle = "Insufficient data for review style analysis."
 			return nil
 		}
-		reviewPrepared, err := a.compressToFit(gCtx, "review activity", reviewActivity)
+		suggestionText := persona.Suggestions.Format()
+		if suggestionText == "" {
+			suggestionText = "(no inline review comments)"
+		}
+		toneText := persona.Metrics.Tone()
+		if toneText == "" {
+			toneText = "(no review comments)"
+		}
+		phrasesText := persona.Phrases.Format()
+		if phrasesText == "" {
+			phrasesText = "(no comments)"
+		}
+		prepared, err := a.prepare(ctx, usage, "review-style", len(reviewStylePrompt)+len(suggestionText)+len(toneText)+len(phrasesText),
+			source{"review activity", reviewActivity},
+		)
 		if err != nil {
-			return fmt.Errorf("compressing review activity: %w", err)
+			return err
 		}
 		slog.Info("analyzing review style")
-		prompt := fmt.Sprintf(reviewStylePrompt, username, reviewPrepared)
-		result, err := a.provider.Complete(gCtx, systemPrompt, prompt, nil)
+		result, err := a.analyze(ctx, "review style", username, "review-style", reviewStylePrompt,
+			prompts.Arg("Username", username),
+			prompts.Arg("ReviewActivity", prepared[0]),
+			prompts.Arg("Suggestions", suggestionText),
+			prompts.Arg("Tone", toneText),
+			prompts.Arg("Phrases", phrasesText),
+		)
 		if err != nil {
 			return fmt.Errorf("review style analysis: %w", err)
 		}
 		persona.ReviewStyle = result
+		a.saveArtifact("review-style", result)
 		return nil
 	})
 
-	g.Go(func() error {
-		if prDescriptions == "" && issueComments == "" && authoredIssues == "" && releaseNotes == "" && discussionsText == "" {
+	dimension("communication", func(ctx context.Context) error {
+		if prDescriptions == "" && issueComments == "" && authoredIssues == "" && releaseNotes == "" && discussionsText == "" && docsText == "" && reviewReplies == "" {
 			slog.Warn("no communication data found, skipping communication analysis")
 			persona.Communication = "Insufficient data for communication analysis."
 			return nil
 		}
-		prPrepared, err := a.compressToFit(gCtx, "pull request descriptions", prDescriptions)
-		if err != nil {
-			return fmt.Errorf("compressing PR descriptions: %w", err)
-		}
-		issueCommentsPrepared, err := a.compressToFit(gCtx, "issue comments", issueComments)
-		if err != nil {
-			return fmt.Errorf("compressing issue comments: %w", err)
-		}
-		authoredIssuesPrepared, err := a.compressToFit(gCtx, "authored issues", authoredIssues)
-		if err != nil {
-			return fmt.Errorf("compressing authored issues: %w", err)
-		}
-		releasesPrepared, err := a.compressToFit(gCtx, "release notes", releaseNotes)
-		if err != nil {
-			return fmt.Errorf("compressing release notes: %w", err)
-		}
-		discussionsPrepared, err := a.compressToFit(gCtx, "discussions", discussionsText)
+		prepared, err := a.prepare(ctx, usage, "communication", len(communicationPrompt),
+			source{"pull request descriptions", prDescriptions},
+			source{"issue comments", issueComments},
+			source{"authored issues", authoredIssues},
+			source{"release notes", releaseNotes},
+			source{"discussions", discussionsText},
+			source{"documentation", docsText},
+			source{"review thread replies", reviewReplies},
+		)
 		if err != nil {
-			return fmt.Errorf("compressing discussions: %w", err)
+			return err
 		}
 		slog.Info("analyzing communication style")
-		prompt := fmt.Sprintf(communicationPrompt, username,
-			prPrepared,
-			issueCommentsPrepared,
-			authoredIssuesPrepared,
-			releasesPrepared,
-			discussionsPrepared,
+		result, err := a.analyze(ctx, "communication", username, "communication", communicationPrompt,
+			prompts.Arg("Username", username),
+			prompts.Arg("PRDescriptions", prepared[0]),
+			prompts.Arg("IssueComments", prepared[1]),
+			prompts.Arg("AuthoredIssues", prepared[2]),
+			prompts.Arg("ReleaseNotes", prepared[3]),
+			prompts.Arg("Discussions", prepared[4]),
+			prompts.Arg("Docs", prepared[5]),
+			prompts.Arg("ReviewReplies", prepared[6]),
 		)
-		result, err := a.provider.Complete(gCtx, systemPrompt, prompt, nil)
 		if err != nil {
 			return fmt.Errorf("communication analysis: %w", err)
 		}
 		persona.Communication = result
+		a.saveArtifact("communication", result)
 		return nil
 	})
 
-	g.Go(func() error {
+	dimension("developer-identity", func(ctx context.Context) error {
 		if profileText == "" && starredText == "" && gistsText == "" && externalPRsText == "" {
 			slog.Warn("no identity data found, skipping developer identity analysis")
 			persona.DeveloperIdentity = "Insufficient data for developer identity analysis."
 			return nil
 		}
-		profilePrepared, err := a.compressToFit(gCtx, "profile", profileText)
-		if err != nil {
-			return fmt.Errorf("compressing profile: %w", err)
-		}
-		starredPrepared, err := a.compressToFit(gCtx, "starred repositories", starredText)
+		prepared, err := a.prepare(ctx, usage, "developer-identity", len(developerIdentityPrompt),
+			source{"profile", profileText},
+			source{"starred repositories", starredText},
+			source{"interest areas", interestsText},
+			source{"gists", gistsText},
+			source{"organizations", orgsText},
+			source{"external pull requests", externalPRsText},
+			source{"recent activity events", eventsText},
+			source{"contribution timeline", timelineText},
+			source{"projects", projectsText},
+			source{"wiki pages", wikiText},
+			source{"community reception", receptionText},
+			source{"dependencies", dependenciesText},
+			source{"branch and tag names", refNamesText},
+			source{"issue triage", triageText},
+		)
 		if err != nil {
-			return fmt.Errorf("compressing starred repositories: %w", err)
+			return err
 		}
-		gistsPrepared, err := a.compressToFit(gCtx, "gists", gistsText)
+		slog.Info("analyzing developer identity")
+		result, err := a.analyze(ctx, "developer identity", username, "developer-identity", developerIdentityPrompt,
+			prompts.Arg("Username", username),
+			prompts.Arg("Profile", prepared[0]),
+			prompts.Arg("Starred", prepared[1]),
+			prompts.Arg("Interests", prepared[2]),
+			prompts.Arg("Gists", prepared[3]),
+			prompts.Arg("Orgs", prepared[4]),
+			prompts.Arg("ExternalPRs", prepared[5]),
+			prompts.Arg("Events", prepared[6]),
+			prompts.Arg("Timeline", prepared[7]),
+			prompts.Arg("Projects", prepared[8]),
+			prompts.Arg("Wiki", prepared[9]),
+			prompts.Arg("Reception", prepared[10]),
+			prompts.Arg("Dependencies", prepared[11]),
+			prompts.Arg("RefNames", prepared[12]),
+			prompts.Arg("Triage", prepared[13]),
+		)
 		if err != nil {
-			return fmt.Errorf("compressing gists: %w", err)
+			return fmt.Errorf("developer identity analysis: %w", err)
 		}
-		orgsPrepared, err := a.compressToFit(gCtx, "organizations", orgsText)
-		if err != nil {
-			return fmt.Errorf("compressing organizations: %w", err)
+		persona.DeveloperIdentity = result
+		a.saveArtifact("developer-identity", result)
+		return nil
+	})
+
+	for i, lc := range languages {
+		g.Go(func() error {
+			return a.progress.Step(gCtx, "language-style:"+lc.language, func(ctx context.Context) error {
+				prepared, err := a.prepare(ctx, usage, "language-style", len(languageStylePrompt),
+					source{lc.language + " code", lc.text()},
+				)
+				if err != nil {
+					return err
+				}
+				slog.Info("analyzing language style", "language", lc.language)
+				result, err := a.analyze(ctx, lc.language+" style", username, "language-style", languageStylePrompt,
+					prompts.Arg("Username", username),
+					prompts.Arg("Language", lc.language),
+					prompts.Arg("Code", prepared[0]),
+				)
+				if err != nil {
+					return fmt.Errorf("%s style analysis: %w", lc.language, err)
+				}
+				persona.LanguageStyles[i] = LanguageStyle{Language: lc.language, Rules: result}
+				a.saveArtifact("language-style-"+lc.language, result)
+				return nil
+			})
+		})
+	}
+
+	dimension("style-evolution", func(ctx context.Context) error {
+		if erasText == "" {
+			slog.Warn("activity covers less than two years, skipping style evolution analysis")
+			persona.StyleEvolution = "Insufficient data for style evolution analysis."
+			return nil
 		}
-		externalPRsPrepared, err := a.compressToFit(gCtx, "external pull requests", externalPRsText)
+		prepared, err := a.prepare(ctx, usage, "style-evolution", len(styleEvolutionPrompt),
+			source{"eras", erasText},
+		)
 		if err != nil {
-			return fmt.Errorf("compressing external pull requests: %w", err)
+			return err
 		}
-		eventsPrepared, err := a.compressToFit(gCtx, "recent activity events", eventsText)
+		slog.Info("analyzing style evolution")
+		result, err := a.analyze(ctx, "style evolution", username, "style-evolution", styleEvolutionPrompt,
+			prompts.Arg("Username", username),
+			prompts.Arg("Eras", prepared[0]),
+		)
 		if err != nil {
-			return fmt.Errorf("compressing activity events: %w", err)
+			return fmt.Errorf("style evolution analysis: %w", err)
 		}
-		projectsPrepared, err := a.compressToFit(gCtx, "projects", projectsText)
-		if err != nil {
-			return fmt.Errorf("compressing projects: %w", err)
+		persona.StyleEvolution = result
+		a.saveArtifact("style-evolution", result)
+		return nil
+	})
+
+	dimension("automation", func(ctx context.Context) error {
+		if toolingText == "" && ciRunsText == "" {
+			slog.Warn("no automation files or CI runs found, skipping automation analysis")
+			persona.Automation = "Insufficient data for automation analysis."
+			return nil
 		}
-		wikiPrepared, err := a.compressToFit(gCtx, "wiki pages", wikiText)
+		prepared, err := a.prepare(ctx, usage, "automation", len(automationPrompt)+len(ciRunsText),
+			source{"automation files", toolingText},
+		)
 		if err != nil {
-			return fmt.Errorf("compressing wiki pages: %w", err)
+			return err
 		}
-		slog.Info("analyzing developer identity")
-		prompt := fmt.Sprintf(developerIdentityPrompt, username,
-			profilePrepared,
-			starredPrepared,
-			gistsPrepared,
-			orgsPrepared,
-			externalPRsPrepared,
-			eventsPrepared,
-			projectsPrepared,
-			wikiPrepared,
+		slog.Info("analyzing automation")
+		result, err := a.analyze(ctx, "automation", username, "automation", automationPrompt,
+			prompts.Arg("Username", username),
+			prompts.Arg("Tooling", prepared[0]),
+			prompts.Arg("CIRuns", ciRunsText),
 		)
-		result, err := a.provider.Complete(gCtx, systemPrompt, prompt, nil)
 		if err != nil {
-			return fmt.Errorf("developer identity analysis: %w", err)
+			return fmt.Errorf("automation analysis: %w", err)
 		}
-		persona.DeveloperIdentity = result
+		persona.Automation = result
+		a.saveArtifact("automation", result)
 		return nil
 	})
 
@@ -236,31 +548,87 @@ func (a *Analyzer) Analyze(ctx context.Context, username string, data *ghcrawl.C
 		return nil, err
 	}
 
-	slog.Info("synthesizing developer persona")
-	synthesisInput := fmt.Sprintf(synthesisPrompt,
-		username,
-		truncateChunk(persona.CodeStyle),
-		truncateChunk(persona.ReviewStyle),
-		truncateChunk(persona.Communication),
-		truncateChunk(persona.DeveloperIdentity),
+	err := a.progress.Step(ctx, "synthesis", func(ctx context.Context) error {
+		return a.synthesize(ctx, persona, usage, full)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if a.grounding {
+		err := a.progress.Step(ctx, "grounding", func(ctx context.Context) error {
+			return a.ground(ctx, persona, usage, data)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	if a.skillTokens > 0 {
+		err := a.progress.Step(ctx, "skill compression", func(ctx context.Context) error {
+			return a.FitSkillBudget(ctx, persona)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return persona, nil
+}
+
+// synthesize merges the dimension analyses in persona into its Synthesis.
+func (a *Analyzer) synthesize(ctx context.Context, persona *Persona, usage *usageLog, data *ghcrawl.CrawlResult) error {
+	metricsText := persona.Metrics.Format()
+	if metricsText == "" {
+		metricsText = "(no metrics available)"
+	}
+	names := []string{"CodeStyle", "CommitMessages", "ReviewStyle", "Communication", "DeveloperIdentity", "StyleEvolution", "Automation"}
+	prepared, err := a.prepare(ctx, usage, "synthesis", len(synthesisPrompt)+len(metricsText),
+		source{"code style analysis", persona.CodeStyle},
+		source{"commit message analysis", persona.CommitMessages},
+		source{"review style analysis", persona.ReviewStyle},
+		source{"communication analysis", persona.Communication},
+		source{"developer identity analysis", persona.DeveloperIdentity},
+		source{"style evolution analysis", persona.StyleEvolution},
+		source{"automation analysis", persona.Automation},
 	)
-	raw, err := a.provider.Complete(ctx, systemPrompt, synthesisInput, nil)
 	if err != nil {
-		return nil, fmt.Errorf("persona synthesis: %w", err)
+		return err
+	}
+	persona.Usage = usage.usage()
+	vars := []prompts.Var{prompts.Arg("Username", persona.Username)}
+	for i, name := range names {
+		vars = append(vars, prompts.Arg(name, prepared[i]))
+	}
+	vars = append(vars, prompts.Arg("Metrics", metricsText))
+	name, def := "synthesis", synthesisPrompt
+	if len(persona.Sparse) > 0 {
+		name, def = "sparse-synthesis", sparseSynthesisPrompt
+		vars = append(vars, prompts.Arg("Sparsity", formatSparsity(persona.Sparse)))
+		slog.Warn("sparse profile, the persona will be a best-effort sketch", "reasons", strings.Join(persona.Sparse, "; "))
 	}
 
-	synthesis, err := ParseSynthesis(raw)
+	slog.Info("synthesizing developer persona")
+	raw, err := a.completeJSON(ctx, name, def, SynthesisSchema, vars...)
 	if err != nil {
-		return nil, fmt.Errorf("parsing synthesis JSON: %w", err)
+		return fmt.Errorf("persona synthesis: %w", err)
 	}
-	persona.Synthesis = synthesis
+	a.saveArtifact("synthesis", raw)
 
-	return persona, nil
+	synthesis, err := parseRepairing(ctx, a, "synthesis", raw, SynthesisSchema, ParseSynthesis)
+	if err != nil {
+		return fmt.Errorf("parsing synthesis JSON: %w", err)
+	}
+	known := knownActivity(data)
+	synthesis.Evidence = groundEvidence(synthesis.Evidence, known)
+	synthesis.Examples = examplesFor(synthesis.Evidence, known)
+	if len(persona.Sparse) > 0 {
+		capConfidence(synthesis)
+	}
+	persona.Synthesis = synthesis
+	return nil
 }
 
-// ParseSynthesis extracts a SynthesisResult from the LLM response. It handles
-// both raw JSON and JSON wrapped in markdown code fences.
-func ParseSynthesis(raw string) (*SynthesisResult, error) {
+// decodeJSONObject parses a JSON object from an LLM response, tolerating a
+// markdown code fence around it and invalid escapes inside strings.
+func decodeJSONObject(raw string) (map[string]json.RawMessage, error) {
 	text := strings.TrimSpace(raw)
 	if text == "" {
 		return nil, fmt.Errorf("invalid JSON from LLM: empty response")
@@ -288,6 +656,27 @@ func ParseSynthesis(raw string) (*SynthesisResult, error) {
 				err, textutil.Truncate(raw, 500, "..."))
 		}
 	}
+	return rawMap, nil
+}
+
+// ParseSynthesis extracts a SynthesisResult from the LLM response. It handles
+// both raw JSON and JSON wrapped in markdown code fences.
+func ParseSynthesis(raw string) (*SynthesisResult, error) {
+	rawMap, err := decodeJSONObject(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	var evidence map[string][]string
+	if v, ok := rawMap["evidence"]; ok {
+		evidence = parseEvidence(v)
+		delete(rawMap, "evidence")
+	}
+	var confidence map[string]Confidence
+	if v, ok := rawMap["confidence"]; ok {
+		confidence = parseConfidence(v)
+		delete(rawMap, "confidence")
+	}
 
 	for k, v := range rawMap {
 		trimmed := strings.TrimSpace(string(v))
@@ -310,6 +699,8 @@ func ParseSynthesis(raw string) (*SynthesisResult, error) {
 		return nil, fmt.Errorf("invalid JSON from LLM after normalization: %w\nraw response (first 500 bytes): %s",
 			err, textutil.Truncate(raw, 500, "..."))
 	}
+	result.Evidence = evidence
+	result.Confidence = confidence
 	return &result, nil
 }
 
@@ -326,11 +717,30 @@ func buildCodeSamplesText(data *ghcrawl.CrawlResult) string {
 			buckets = append(buckets, items)
 		}
 	}
+	// Gists often show the developer's quick-script style, so they get
+	// their own bucket alongside repo samples.
+	var gistItems []string
+	for _, g := range data.Gists {
+		for _, f := range g.Files {
+			if f.Content == "" {
+				continue
+			}
+			gistItems = append(gistItems, fmt.Sprintf("=== gist %s/%s ===\n%s\n\n", g.ID, f.Name, f.Content))
+		}
+	}
+	if len(gistItems) > 0 {
+		buckets = append(buckets, gistItems)
+	}
 	return interleave(buckets)
 }
 
+// buildCommitDiffsText interleaves commit diffs across repos, weighted so
+// repos in the developer's dominant languages and their flagship repos fill
+// more of a truncated chunk than one-off experiments.
 func buildCommitDiffsText(data *ghcrawl.CrawlResult) string {
 	var buckets [][]string
+	var weights []int
+	repoWeight := repoWeights(data)
 	for _, repo := range data.Repos {
 		var items []string
 		for _, commit := range repo.Commits {
@@ -345,8 +755,34 @@ func buildCommitDiffsText(data *ghcrawl.CrawlResult) string {
 			if commit.Additions > 0 || commit.Deletions > 0 {
 				stats = fmt.Sprintf(" (+%d/-%d, %d files)", commit.Additions, commit.Deletions, commit.FilesChanged)
 			}
-			items = append(items, fmt.Sprintf("=== %s - %s%s ===\nMessage: %s\n%s\n\n",
-				repo.FullName, sha, stats, commit.Message, commit.Patch))
+			items = append(items, fmt.Sprintf("=== %s - %s%s ===\n%sMessage: %s\n%s\n\n",
+				repo.FullName, sha, stats, urlLine(commitURL(repo.FullName, commit.SHA)), commit.Message, commit.Patch))
+		}
+		if len(items) > 0 {
+			buckets = append(buckets, items)
+			weights = append(weights, repoWeight[repo.FullName])
+		}
+	}
+	return interleaveWeighted(buckets, weights)
+}
+
+// buildCommitMessagesText lists full commit messages without patches, so
+// many more fit than in the commit diffs. Merge commits are left out since
+// their messages are usually generated.
+func buildCommitMessagesText(data *ghcrawl.CrawlResult) string {
+	var buckets [][]string
+	for _, repo := range data.Repos {
+		var items []string
+		for _, commit := range repo.Commits {
+			msg := strings.TrimSpace(commit.Message)
+			if msg == "" || strings.HasPrefix(msg, "Merge pull request ") || strings.HasPrefix(msg, "Merge branch ") {
+				continue
+			}
+			sha := commit.SHA
+			if len(sha) > 8 {
+				sha = sha[:8]
+			}
+			items = append(items, fmt.Sprintf("=== %s - %s ===\n%s%s\n\n", repo.FullName, sha, urlLine(commitURL(repo.FullName, commit.SHA)), msg))
 		}
 		if len(items) > 0 {
 			buckets = append(buckets, items)
@@ -374,10 +810,11 @@ func buildReviewDataText(data *ghcrawl.CrawlResult) string {
 				body = "(no summary text)"
 			}
 			items = append(items, fmt.Sprintf(
-				"=== %s PR #%d: %s ===\nAuthor: %s\nState: %s%s%s\nSummary:\n%s\n\n",
+				"=== %s PR #%d: %s ===\n%sAuthor: %s\nState: %s%s%s\nSummary:\n%s\n\n",
 				review.Repo,
 				review.PRNumber,
 				review.PRTitle,
+				urlLine(review.URL),
 				review.PRAuthor,
 				review.State,
 				stats,
@@ -385,7 +822,7 @@ func buildReviewDataText(data *ghcrawl.CrawlResult) string {
 				body,
 			))
 		}
-		for _, rc := range repo.ReviewComments {
+		for _, rc := range byReception(repo.ReviewComments, func(rc ghcrawl.ReviewComment) int { return rc.Reactions.Positive() }) {
 			title := rc.PRTitle
 			if title == "" {
 				title = "(unknown PR title)"
@@ -395,19 +832,21 @@ func buildReviewDataText(data *ghcrawl.CrawlResult) string {
 				diff = "(no diff hunk available)"
 			}
 			items = append(items, fmt.Sprintf(
-				"=== %s PR #%d: %s (file: %s) ===\nAuthor: %s\nDiff hunk:\n%s\n\nComment:\n%s\n\n",
+				"=== %s PR #%d: %s (file: %s)%s ===\n%sAuthor: %s\nDiff hunk:\n%s\n\nComment:\n%s\n\n",
 				repo.FullName,
 				rc.PRNumber,
 				title,
 				rc.Path,
+				formatReactions(rc.Reactions),
+				urlLine(rc.URL),
 				rc.PRAuthor,
 				diff,
 				rc.Body,
 			))
 		}
 		if len(items) == 0 {
-			for _, cm := range repo.PRComments {
-				items = append(items, fmt.Sprintf("=== %s (PR comment) ===\n%s\n\n", repo.FullName, cm.Body))
+			for _, cm := range byReception(repo.PRComments, commentReception) {
+				items = append(items, fmt.Sprintf("=== %s (PR comment)%s ===\n%s%s\n\n", repo.FullName, formatReactions(cm.Reactions), urlLine(cm.URL), cm.Body))
 			}
 		}
 		if len(items) > 0 {
@@ -425,7 +864,7 @@ func buildPRDescriptionsText(data *ghcrawl.CrawlResult) string {
 			if pr.Body == "" {
 				continue
 			}
-			items = append(items, fmt.Sprintf("=== %s #%d: %s ===\n%s\n\n", repo.FullName, pr.Number, pr.Title, pr.Body))
+			items = append(items, fmt.Sprintf("=== %s #%d: %s%s ===\n%s%s\n\n", repo.FullName, pr.Number, pr.Title, formatReactions(pr.Reactions), urlLine(pr.URL), pr.Body))
 		}
 		if len(items) > 0 {
 			buckets = append(buckets, items)
@@ -443,12 +882,14 @@ func buildPRDescriptionsText(data *ghcrawl.CrawlResult) string {
 			body = "(no description)"
 		}
 		extItems = append(extItems, fmt.Sprintf(
-			"=== %s #%d: %s [%s]%s ===\nAuthor: %s\n%s\n\n",
+			"=== %s #%d: %s [%s]%s%s ===\n%sAuthor: %s\n%s\n\n",
 			pr.Repo,
 			pr.Number,
 			pr.Title,
 			pr.State,
 			stats,
+			formatReactions(pr.Reactions),
+			urlLine(pr.URL),
 			pr.Author,
 			body,
 		))
@@ -460,11 +901,18 @@ func buildPRDescriptionsText(data *ghcrawl.CrawlResult) string {
 }
 
 func buildIssueCommentsText(data *ghcrawl.CrawlResult) string {
-	// Group issue comments by repo, then interleave.
+	// Group issue comments by repo, then interleave. Comments the community
+	// reacted to come first so they survive truncation.
 	repoComments := make(map[string][]string)
-	for _, cm := range data.IssueComments {
+	for _, cm := range byReception(data.IssueComments, commentReception) {
 		repoComments[cm.Repo] = append(repoComments[cm.Repo],
-			fmt.Sprintf("=== %s ===\n%s\n\n", cm.Repo, cm.Body))
+			fmt.Sprintf("=== %s%s ===\n%s%s\n\n", cm.Repo, formatReactions(cm.Reactions), urlLine(cm.URL), cm.Body))
+	}
+	for _, repo := range data.Repos {
+		for _, cm := range repo.CommitComments {
+			repoComments[repo.FullName] = append(repoComments[repo.FullName],
+				fmt.Sprintf("=== %s (commit comment) ===\n%s%s\n\n", repo.FullName, urlLine(cm.URL), cm.Body))
+		}
 	}
 	var buckets [][]string
 	for _, items := range repoComments {
@@ -473,6 +921,158 @@ func buildIssueCommentsText(data *ghcrawl.CrawlResult) string {
 	return interleave(buckets)
 }
 
+// buildReviewRepliesText lists the developer's replies in inline review
+// threads next to the comment they answer, so the analysis sees how they
+// handle pushback rather than only how they open a conversation. Replies on
+// their own pull requests, which answer reviewers, come first.
+func buildReviewRepliesText(data *ghcrawl.CrawlResult) string {
+	var buckets [][]string
+	for _, repo := range data.Repos {
+		replies := slices.Clone(repo.ReviewReplies)
+		slices.SortStableFunc(replies, func(a, b ghcrawl.ReviewReply) int {
+			switch {
+			case a.OwnPR == b.OwnPR:
+				return 0
+			case a.OwnPR:
+				return -1
+			default:
+				return 1
+			}
+		})
+		var items []string
+		for _, rr := range replies {
+			title := rr.PRTitle
+			if title == "" {
+				title = "(unknown PR title)"
+			}
+			role := "reviewing someone else's PR"
+			if rr.OwnPR {
+				role = "on their own PR"
+			}
+			items = append(items, fmt.Sprintf(
+				"=== %s PR #%d: %s (file: %s, %s) ===\n%s%s wrote:\n%s\n\nThey replied:\n%s\n\n",
+				repo.FullName,
+				rr.PRNumber,
+				title,
+				rr.Path,
+				role,
+				urlLine(rr.URL),
+				rr.ParentAuthor,
+				rr.Parent,
+				rr.Body,
+			))
+		}
+		if len(items) > 0 {
+			buckets = append(buckets, items)
+		}
+	}
+	return interleave(buckets)
+}
+
+// urlLine labels a permalink so analyses can cite it, or returns "" when
+// there is none.
+func urlLine(url string) string {
+	if url == "" {
+		return ""
+	}
+	return "URL: " + url + "\n"
+}
+
+func commitURL(fullName, sha string) string {
+	if fullName == "" || sha == "" {
+		return ""
+	}
+	return "https://github.com/" + fullName + "/commit/" + sha
+}
+
+// byReception returns a copy of items stably sorted by descending positive
+// reactions, so community-valued comments are seen first.
+func byReception[T any](items []T, positive func(T) int) []T {
+	sorted := slices.Clone(items)
+	slices.SortStableFunc(sorted, func(a, b T) int {
+		return positive(b) - positive(a)
+	})
+	return sorted
+}
+
+func commentReception(cm ghcrawl.Comment) int { return cm.Reactions.Positive() }
+
+func formatReactions(r ghcrawl.ReactionCounts) string {
+	if r.Total == 0 {
+		return ""
+	}
+	var parts []string
+	for _, p := range []struct {
+		emoji string
+		n     int
+	}{
+		{"👍", r.PlusOne}, {"❤️", r.Heart}, {"🎉", r.Hooray}, {"🚀", r.Rocket}, {"👎", r.MinusOne},
+	} {
+		if p.n > 0 {
+			parts = append(parts, fmt.Sprintf("%s%d", p.emoji, p.n))
+		}
+	}
+	parts = append(parts, fmt.Sprintf("%d total", r.Total))
+	return " [reactions: " + strings.Join(parts, " ") + "]"
+}
+
+// buildReceptionText summarizes the reactions the community left on the
+// developer's comments and pull requests.
+func buildReceptionText(data *ghcrawl.CrawlResult) string {
+	type reacted struct {
+		label string
+		body  string
+		r     ghcrawl.ReactionCounts
+	}
+	var items []reacted
+	total := 0
+	add := func(label, body string, r ghcrawl.ReactionCounts) {
+		total++
+		if r.Total > 0 {
+			items = append(items, reacted{label: label, body: body, r: r})
+		}
+	}
+	for _, cm := range data.IssueComments {
+		add(cm.Repo+" issue comment", cm.Body, cm.Reactions)
+	}
+	for _, repo := range data.Repos {
+		for _, rc := range repo.ReviewComments {
+			add(repo.FullName+" review comment", rc.Body, rc.Reactions)
+		}
+		for _, cm := range repo.PRComments {
+			add(repo.FullName+" PR comment", cm.Body, cm.Reactions)
+		}
+		for _, pr := range repo.PRs {
+			add(fmt.Sprintf("%s PR #%d", pr.Repo, pr.Number), pr.Title, pr.Reactions)
+		}
+	}
+	for _, pr := range data.ExternalPRs {
+		add(fmt.Sprintf("%s PR #%d", pr.Repo, pr.Number), pr.Title, pr.Reactions)
+	}
+	if len(items) == 0 {
+		return ""
+	}
+
+	var sum ghcrawl.ReactionCounts
+	for _, item := range items {
+		sum.Total += item.r.Total
+		sum.PlusOne += item.r.PlusOne
+		sum.MinusOne += item.r.MinusOne
+		sum.Heart += item.r.Heart
+		sum.Hooray += item.r.Hooray
+		sum.Rocket += item.r.Rocket
+	}
+	items = byReception(items, func(item reacted) int { return item.r.Positive() })
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d of %d comments and PRs received reactions%s\n", len(items), total, formatReactions(sum))
+	b.WriteString("\nMost appreciated:\n")
+	for _, item := range items[:min(10, len(items))] {
+		fmt.Fprintf(&b, "- %s%s: %s\n", item.label, formatReactions(item.r), textutil.Truncate(item.body, 200, "..."))
+	}
+	return b.String()
+}
+
 // interleave round-robins across buckets so each source gets fair
 // representation. Takes one item from each bucket per round.
 func interleave(buckets [][]string) string {
@@ -582,13 +1182,30 @@ func buildProfileText(data *ghcrawl.CrawlResult) string {
 			fmt.Fprintf(&repoSummary, "  %s: %d repos\n", lic, count)
 		}
 	}
+	var maintained []string
+	for _, repo := range data.Repos {
+		if repo.IsMaintainer {
+			maintained = append(maintained, repo.FullName)
+		}
+	}
+	if len(maintained) > 0 {
+		fmt.Fprintf(&repoSummary, "\nMaintainer of (not owned): %s\n", strings.Join(maintained, ", "))
+	}
 	b.WriteString(repoSummary.String())
 
+	if total := data.TotalCommits(); total > 0 {
+		signed, verified, byMethod := data.SigningStats()
+		fmt.Fprintf(&b, "\nCommit signing: %d of %d commits signed, %d verified by GitHub\n", signed, total, verified)
+		for _, method := range sortedByCount(byMethod) {
+			fmt.Fprintf(&b, "  %s: %d commits\n", method, byMethod[method])
+		}
+	}
+
 	return b.String()
 }
 
 func buildStarredReposText(data *ghcrawl.CrawlResult) string {
-	if len(data.StarredRepos) == 0 {
+	if len(data.StarredRepos) == 0 && len(data.WatchedRepos) == 0 && len(data.StarredGists) == 0 {
 		return ""
 	}
 	var b strings.Builder
@@ -623,6 +1240,61 @@ func buildStarredReposText(data *ghcrawl.CrawlResult) string {
 	if len(data.StarredRepos) > limit {
 		fmt.Fprintf(&b, "... and %d more starred repos\n", len(data.StarredRepos)-limit)
 	}
+	b.WriteString(buildWatchedReposText(data))
+	b.WriteString(buildStarredGistsText(data))
+	return b.String()
+}
+
+// buildWatchedReposText lists watched repos that are not also starred.
+// Watching without starring usually means following a project closely,
+// often a niche one.
+func buildWatchedReposText(data *ghcrawl.CrawlResult) string {
+	starred := make(map[string]bool, len(data.StarredRepos))
+	for _, sr := range data.StarredRepos {
+		starred[sr.FullName] = true
+	}
+	var b strings.Builder
+	listed := 0
+	for _, wr := range data.WatchedRepos {
+		if starred[wr.FullName] {
+			continue
+		}
+		if listed == 0 {
+			b.WriteString("\nWatched repos (subscribed, not starred):\n")
+		}
+		listed++
+		if listed > 30 {
+			continue
+		}
+		fmt.Fprintf(&b, "- %s (%s, %d stars): %s\n", wr.FullName, wr.Language, wr.Stars, textutil.Truncate(wr.Description, 100, "..."))
+	}
+	if listed > 30 {
+		fmt.Fprintf(&b, "... and %d more watched repos\n", listed-30)
+	}
+	return b.String()
+}
+
+func buildStarredGistsText(data *ghcrawl.CrawlResult) string {
+	if len(data.StarredGists) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("\nStarred gists:\n")
+	for _, g := range data.StarredGists {
+		var files []string
+		for _, f := range g.Files {
+			if f.Language != "" {
+				files = append(files, fmt.Sprintf("%s (%s)", f.Name, f.Language))
+			} else {
+				files = append(files, f.Name)
+			}
+		}
+		desc := g.Description
+		if desc == "" {
+			desc = "(no description)"
+		}
+		fmt.Fprintf(&b, "- by %s: %s [%s]\n", g.Owner, textutil.Truncate(desc, 100, "..."), strings.Join(files, ", "))
+	}
 	return b.String()
 }
 
@@ -654,10 +1326,49 @@ func buildGistsText(data *ghcrawl.CrawlResult) string {
 }
 
 func buildOrgsText(data *ghcrawl.CrawlResult) string {
-	if len(data.Orgs) == 0 {
-		return "No public organization memberships."
+	text := "No public organization memberships."
+	if len(data.Orgs) > 0 {
+		text = "Member of: " + strings.Join(data.Orgs, ", ")
+	}
+	if sp := buildSponsorshipText(data.Sponsorship); sp != "" {
+		text += "\n\n" + sp
 	}
-	return "Member of: " + strings.Join(data.Orgs, ", ")
+	return text
+}
+
+// buildSponsorshipText reports both sides of GitHub Sponsors and how many
+// owned repos ask for funding, as signals of community engagement.
+func buildSponsorshipText(sp *ghcrawl.SponsorshipData) string {
+	if sp == nil {
+		return ""
+	}
+	var b strings.Builder
+	if sp.Sponsorable {
+		fmt.Fprintf(&b, "Has a GitHub Sponsors profile with %d sponsors\n", sp.SponsorCount)
+	} else {
+		b.WriteString("No GitHub Sponsors profile\n")
+	}
+	if sp.SponsoringCount > 0 {
+		fmt.Fprintf(&b, "Publicly sponsors %d accounts: %s\n", sp.SponsoringCount, strings.Join(sp.Sponsoring, ", "))
+	} else {
+		b.WriteString("Does not publicly sponsor anyone\n")
+	}
+	fmt.Fprintf(&b, "Owned repos with funding links (FUNDING.yml): %d of %d", len(sp.FundedRepos), sp.OwnedRepos)
+	if len(sp.FundedRepos) > 0 {
+		platforms := make(map[string]int)
+		for _, fd := range sp.FundedRepos {
+			for _, p := range fd.Platforms {
+				platforms[p]++
+			}
+		}
+		var parts []string
+		for _, p := range sortedByCount(platforms) {
+			parts = append(parts, fmt.Sprintf("%s %d", strings.ToLower(p), platforms[p]))
+		}
+		fmt.Fprintf(&b, " (platforms: %s)", strings.Join(parts, ", "))
+	}
+	b.WriteString("\n")
+	return b.String()
 }
 
 func buildExternalPRsText(data *ghcrawl.CrawlResult) string {
@@ -688,6 +1399,16 @@ func buildEventsText(data *ghcrawl.CrawlResult) string {
 	for t, count := range typeCount {
 		fmt.Fprintf(&b, "  %s: %d\n", t, count)
 	}
+	yearCount := make(map[int]int)
+	for _, ev := range data.Events {
+		yearCount[ev.CreatedAt.Year()]++
+	}
+	if len(yearCount) > 1 {
+		b.WriteString("\nEvents per year:\n")
+		for _, year := range slices.Sorted(maps.Keys(yearCount)) {
+			fmt.Fprintf(&b, "  %d: %d\n", year, yearCount[year])
+		}
+	}
 	b.WriteString("\nRecent events:\n")
 	limit := 30
 	if len(data.Events) < limit {
@@ -738,19 +1459,140 @@ func buildProjectsText(data *ghcrawl.CrawlResult) string {
 		if !p.Public {
 			visibility = "private"
 		}
-		fmt.Fprintf(&b, "- [%s] %s (%d items): %s\n",
-			visibility, p.Title, p.ItemCount, p.Body)
+		closed := ""
+		if p.Closed {
+			closed = ", closed"
+		}
+		fmt.Fprintf(&b, "- [%s] %s (%d items%s): %s\n",
+			visibility, p.Title, p.ItemCount, closed, p.Body)
+		if len(p.Fields) > 0 {
+			var fields []string
+			for _, f := range p.Fields {
+				fields = append(fields, fmt.Sprintf("%s (%s)", f.Name, projectEnum(f.DataType)))
+			}
+			fmt.Fprintf(&b, "    Fields: %s\n", strings.Join(fields, ", "))
+		}
+		if len(p.Views) > 0 {
+			var views []string
+			for _, v := range p.Views {
+				views = append(views, fmt.Sprintf("%s (%s)", v.Name, projectEnum(strings.TrimSuffix(v.Layout, "_LAYOUT"))))
+			}
+			fmt.Fprintf(&b, "    Views: %s\n", strings.Join(views, ", "))
+		}
+		if len(p.Items) > 0 {
+			fmt.Fprintf(&b, "    Recent items: %s\n", summarizeProjectItems(p.Items))
+		}
+		if p.Readme != "" {
+			fmt.Fprintf(&b, "    Readme: %s\n", strings.ReplaceAll(textutil.Truncate(p.Readme, 300, "..."), "\n", " "))
+		}
 	}
 	return b.String()
 }
 
-func buildWikiPagesText(data *ghcrawl.CrawlResult) string {
+// summarizeProjectItems counts item types and statuses, which show whether
+// a board tracks real issues or scratch drafts and how work flows across it.
+func summarizeProjectItems(items []ghcrawl.ProjectItem) string {
+	types := make(map[string]int)
+	statuses := make(map[string]int)
+	archived := 0
+	var last time.Time
+	for _, it := range items {
+		types[projectEnum(it.Type)]++
+		status := it.Status
+		if status == "" {
+			status = "(no status)"
+		}
+		statuses[status]++
+		if it.Archived {
+			archived++
+		}
+		if it.UpdatedAt.After(last) {
+			last = it.UpdatedAt
+		}
+	}
+	var parts []string
+	for _, t := range sortedByCount(types) {
+		parts = append(parts, fmt.Sprintf("%s %d", t, types[t]))
+	}
+	s := strings.Join(parts, ", ")
+	parts = parts[:0]
+	for _, st := range sortedByCount(statuses) {
+		parts = append(parts, fmt.Sprintf("%s %d", st, statuses[st]))
+	}
+	s += "; status: " + strings.Join(parts, ", ")
+	if archived > 0 {
+		s += fmt.Sprintf("; %d archived", archived)
+	}
+	if !last.IsZero() {
+		s += "; last updated " + last.Format("2006-01-02")
+	}
+	return s
+}
+
+// projectEnum turns a GraphQL enum such as SINGLE_SELECT into "single select".
+func projectEnum(s string) string {
+	return strings.ToLower(strings.ReplaceAll(s, "_", " "))
+}
+
+// buildDependenciesText ranks the libraries declared in dependency manifests
+// by how many repos use them, then lists each manifest.
+func buildDependenciesText(data *ghcrawl.CrawlResult) string {
+	type usage struct {
+		name      string
+		ecosystem string
+		repos     map[string]bool
+	}
+	usages := make(map[string]*usage)
+	var manifests []string
+	for _, repo := range data.Repos {
+		for _, dd := range repo.Dependencies {
+			for _, dep := range dd.Dependencies {
+				key := dd.Ecosystem + "/" + dep
+				u := usages[key]
+				if u == nil {
+					u = &usage{name: dep, ecosystem: dd.Ecosystem, repos: make(map[string]bool)}
+					usages[key] = u
+				}
+				u.repos[dd.Repo] = true
+			}
+			manifests = append(manifests, fmt.Sprintf("- %s/%s (%s): %s\n",
+				dd.Repo, dd.Path, dd.Ecosystem, strings.Join(dd.Dependencies, ", ")))
+		}
+	}
+	if len(manifests) == 0 {
+		return ""
+	}
+
+	ranked := make([]*usage, 0, len(usages))
+	for _, u := range usages {
+		ranked = append(ranked, u)
+	}
+	slices.SortFunc(ranked, func(a, b *usage) int {
+		if n := len(b.repos) - len(a.repos); n != 0 {
+			return n
+		}
+		return strings.Compare(a.name, b.name)
+	})
+
+	var b strings.Builder
+	b.WriteString("Most used dependencies (by number of repos):\n")
+	for _, u := range ranked[:min(40, len(ranked))] {
+		fmt.Fprintf(&b, "  %s (%s): %d repos\n", u.name, u.ecosystem, len(u.repos))
+	}
+	b.WriteString("\nManifests:\n")
+	for _, m := range manifests {
+		b.WriteString(m)
+	}
+	return b.String()
+}
+
+func buildDocsText(data *ghcrawl.CrawlResult) string {
 	var buckets [][]string
 	for _, repo := range data.Repos {
 		var items []string
-		for _, wp := range repo.WikiPages {
-			items = append(items, fmt.Sprintf("=== %s - %s ===\n%s\n\n",
-				wp.Repo, wp.Title, textutil.Truncate(wp.Content, 2000, "\n... (truncated)")))
+		for _, doc := range repo.Docs {
+			items = append(items, fmt.Sprintf("=== %s %s (%s) ===\n%s\n\n",
+				repo.FullName, doc.Path, doc.Kind, doc.Content))
 		}
 		if len(items) > 0 {
 			buckets = append(buckets, items)
@@ -759,33 +1601,223 @@ func buildWikiPagesText(data *ghcrawl.CrawlResult) string {
 	return interleave(buckets)
 }
 
-func truncateChunk(s string) string {
-	return textutil.Truncate(s, maxChunkSize, "\n... (data truncated to fit context window)")
+var (
+	semverTag = regexp.MustCompile(`^v?\d+\.\d+(\.\d+)?([-+].*)?$`)
+	dateTag   = regexp.MustCompile(`^v?\d{4}[-.]?\d{2}[-.]?\d{2}`)
+)
+
+// tagScheme classifies a tag name as semver (with or without a "v" prefix),
+// date-based, or other.
+func tagScheme(tag string) string {
+	switch {
+	case dateTag.MatchString(tag):
+		return "date"
+	case semverTag.MatchString(tag) && strings.HasPrefix(tag, "v"):
+		return "semver with v prefix"
+	case semverTag.MatchString(tag):
+		return "semver without prefix"
+	}
+	return "other"
 }
 
-func (a *Analyzer) compressToFit(ctx context.Context, label, input string) (string, error) {
-	if input == "" || len(input) <= maxChunkSize {
-		return input, nil
+// branchPrefix returns the conventional prefix of a branch name such as
+// "feature" in "feature/login", or "" when there is none.
+func branchPrefix(branch string) string {
+	for _, sep := range []string{"/", "-", "_"} {
+		if prefix, _, ok := strings.Cut(branch, sep); ok && prefix != "" {
+			return prefix + sep
+		}
 	}
-	current := input
-	for pass := 0; pass < 4; pass++ {
-		if len(current) <= maxChunkSize {
-			return current, nil
+	return ""
+}
+
+func buildRefNamesText(data *ghcrawl.CrawlResult) string {
+	prefixCount := make(map[string]int)
+	schemeCount := make(map[string]int)
+	var b strings.Builder
+	var listing strings.Builder
+	for _, repo := range data.Repos {
+		if len(repo.Branches) == 0 && len(repo.Tags) == 0 {
+			continue
 		}
-		chunks := splitChunks(current, maxChunkSize)
-		summaries := make([]string, 0, len(chunks))
-		for i, chunk := range chunks {
-			prompt := fmt.Sprintf(evidenceCompressionPrompt, label, i+1, len(chunks), chunk)
-			out, err := a.provider.Complete(ctx, systemPrompt, prompt, nil)
-			if err != nil {
-				return "", err
+		for _, br := range repo.Branches {
+			if p := branchPrefix(br); p != "" {
+				prefixCount[p]++
 			}
-			summaries = append(summaries, out)
 		}
-		current = strings.Join(summaries, "\n\n")
-		label = label + " summary"
+		for _, tag := range repo.Tags {
+			schemeCount[tagScheme(tag)]++
+		}
+		fmt.Fprintf(&listing, "- %s (default %s)\n", repo.FullName, repo.DefaultBranch)
+		if len(repo.Branches) > 0 {
+			fmt.Fprintf(&listing, "  branches: %s\n", strings.Join(repo.Branches[:min(20, len(repo.Branches))], ", "))
+		}
+		if len(repo.Tags) > 0 {
+			fmt.Fprintf(&listing, "  tags: %s\n", strings.Join(repo.Tags[:min(20, len(repo.Tags))], ", "))
+		}
+	}
+	if listing.Len() == 0 {
+		return ""
+	}
+	if len(prefixCount) > 0 {
+		b.WriteString("Branch name prefixes:\n")
+		for _, p := range sortedByCount(prefixCount) {
+			fmt.Fprintf(&b, "  %s: %d\n", p, prefixCount[p])
+		}
+	}
+	if len(schemeCount) > 0 {
+		b.WriteString("Tag schemes:\n")
+		for _, s := range sortedByCount(schemeCount) {
+			fmt.Fprintf(&b, "  %s: %d\n", s, schemeCount[s])
+		}
+	}
+	b.WriteString("\n")
+	b.WriteString(listing.String())
+	return b.String()
+}
+
+// sortedByCount returns the keys of counts ordered by descending count, then
+// name.
+func sortedByCount(counts map[string]int) []string {
+	keys := make([]string, 0, len(counts))
+	for k := range counts {
+		keys = append(keys, k)
+	}
+	slices.SortFunc(keys, func(a, b string) int {
+		if n := counts[b] - counts[a]; n != 0 {
+			return n
+		}
+		return strings.Compare(a, b)
+	})
+	return keys
+}
+
+// buildToolingText lists each repo's automation configs, interleaved so
+// every repo is represented.
+func buildToolingText(data *ghcrawl.CrawlResult) string {
+	var buckets [][]string
+	for _, repo := range data.Repos {
+		var items []string
+		for _, f := range repo.Tooling {
+			items = append(items, fmt.Sprintf("=== %s/%s ===\n%s\n\n", repo.FullName, f.Path, f.Content))
+		}
+		if len(items) > 0 {
+			buckets = append(buckets, items)
+		}
+	}
+	return interleave(buckets)
+}
+
+// buildWorkflowRunsText reports recent CI outcomes per owned repo. The output
+// is a short summary line per repo, so it is never compressed.
+func buildWorkflowRunsText(data *ghcrawl.CrawlResult) string {
+	var b strings.Builder
+	for _, repo := range data.Repos {
+		s := repo.WorkflowRuns
+		if s.Total == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "- %s: %d recent runs, %.0f%% success (%d failed, %d cancelled)",
+			repo.FullName, s.Total, s.SuccessRate()*100, s.Failure, s.Cancelled)
+		if s.DefaultBranchTotal > 0 {
+			fmt.Fprintf(&b, "; %s: %d of %d runs failed", repo.DefaultBranch, s.DefaultBranchFailures, s.DefaultBranchTotal)
+			if s.FailureStreak > 0 {
+				fmt.Fprintf(&b, ", currently red for %d runs", s.FailureStreak)
+			}
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// buildTimelineText summarizes the multi-year contribution timeline: yearly
+// totals, active-week streaks and gaps, and how concentrated commits are in
+// the busiest weeks.
+func buildTimelineText(data *ghcrawl.CrawlResult) string {
+	if len(data.Timeline) == 0 {
+		return ""
+	}
+	type yearTotals struct {
+		weeks, commits, additions, deletions int
+	}
+	years := make(map[int]*yearTotals)
+	var commitsPerWeek []int
+	totalCommits := 0
+	longestStreak, streak := 0, 0
+	longestGap := 0
+	var prev time.Time
+	for _, w := range data.Timeline {
+		y := years[w.Week.Year()]
+		if y == nil {
+			y = &yearTotals{}
+			years[w.Week.Year()] = y
+		}
+		y.weeks++
+		y.commits += w.Commits
+		y.additions += w.Additions
+		y.deletions += w.Deletions
+		commitsPerWeek = append(commitsPerWeek, w.Commits)
+		totalCommits += w.Commits
+
+		gap := 0
+		if !prev.IsZero() {
+			gap = int(w.Week.Sub(prev).Hours()/(24*7)) - 1
+		}
+		if prev.IsZero() || gap > 0 {
+			streak = 1
+		} else {
+			streak++
+		}
+		longestStreak = max(longestStreak, streak)
+		longestGap = max(longestGap, gap)
+		prev = w.Week
+	}
+
+	var b strings.Builder
+	first, last := data.Timeline[0].Week, data.Timeline[len(data.Timeline)-1].Week
+	spanWeeks := int(last.Sub(first).Hours()/(24*7)) + 1
+	fmt.Fprintf(&b, "Active %d of %d weeks between %s and %s\n",
+		len(data.Timeline), spanWeeks, first.Format("2006-01-02"), last.Format("2006-01-02"))
+	fmt.Fprintf(&b, "Longest streak of consecutive active weeks: %d\n", longestStreak)
+	fmt.Fprintf(&b, "Longest gap between active weeks: %d weeks\n", longestGap)
+	if totalCommits > 0 {
+		slices.SortFunc(commitsPerWeek, func(a, b int) int { return b - a })
+		top := max(1, len(commitsPerWeek)/10)
+		topCommits := 0
+		for _, c := range commitsPerWeek[:top] {
+			topCommits += c
+		}
+		fmt.Fprintf(&b, "Busiest 10%% of active weeks hold %.0f%% of commits\n",
+			float64(topCommits)/float64(totalCommits)*100)
+	}
+
+	b.WriteString("\nPer year:\n")
+	yearKeys := make([]int, 0, len(years))
+	for y := range years {
+		yearKeys = append(yearKeys, y)
+	}
+	slices.Sort(yearKeys)
+	for _, year := range yearKeys {
+		y := years[year]
+		fmt.Fprintf(&b, "  %d: %d active weeks, %d commits, +%d/-%d lines\n",
+			year, y.weeks, y.commits, y.additions, y.deletions)
+	}
+	return b.String()
+}
+
+func buildWikiPagesText(data *ghcrawl.CrawlResult) string {
+	var buckets [][]string
+	for _, repo := range data.Repos {
+		var items []string
+		for _, wp := range repo.WikiPages {
+			items = append(items, fmt.Sprintf("=== %s - %s ===\n%s\n\n",
+				wp.Repo, wp.Title, textutil.Truncate(wp.Content, 2000, "\n... (truncated)")))
+		}
+		if len(items) > 0 {
+			buckets = append(buckets, items)
+		}
 	}
-	return truncateChunk(current), nil
+	return interleave(buckets)
 }
 
 func splitChunks(s string, max int) []string {
@@ -813,3 +1845,89 @@ func splitChunks(s string, max int) []string {
 	}
 	return chunks
 }
+
+// buildTriageText summarizes how the developer handles issues other people
+// open on their repos: response rate and latency, labeling, and whether
+// closes come with an explanation.
+func buildTriageText(data *ghcrawl.CrawlResult) string {
+	var all []ghcrawl.TriageData
+	for _, repo := range data.Repos {
+		all = append(all, repo.Triage...)
+	}
+	if len(all) == 0 {
+		return ""
+	}
+
+	var latencies []time.Duration
+	labeled, closed, closedWithComment, ignored := 0, 0, 0, 0
+	labelCount := make(map[string]int)
+	repos := make(map[string]bool)
+	for _, td := range all {
+		repos[td.Repo] = true
+		if td.Responded {
+			latencies = append(latencies, td.FirstResponse)
+		} else if td.Open {
+			ignored++
+		}
+		if len(td.Labels) > 0 {
+			labeled++
+		}
+		for _, l := range td.Labels {
+			labelCount[l]++
+		}
+		if td.ClosedByUser {
+			closed++
+			if td.ClosedWithComment {
+				closedWithComment++
+			}
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Issues opened by others on their repos: %d (across %d repos)\n", len(all), len(repos))
+	fmt.Fprintf(&b, "Responded to: %d of %d (%.0f%%)", len(latencies), len(all), 100*float64(len(latencies))/float64(len(all)))
+	if len(latencies) > 0 {
+		slices.Sort(latencies)
+		fmt.Fprintf(&b, "; median first response %s (fastest %s, slowest %s)",
+			formatLatency(latencies[len(latencies)/2]), formatLatency(latencies[0]), formatLatency(latencies[len(latencies)-1]))
+	}
+	b.WriteString("\n")
+	fmt.Fprintf(&b, "Labeled by them: %d of %d", labeled, len(all))
+	if len(labelCount) > 0 {
+		var parts []string
+		for _, l := range sortedByCount(labelCount) {
+			parts = append(parts, fmt.Sprintf("%s %d", l, labelCount[l]))
+		}
+		fmt.Fprintf(&b, "; labels used: %s", strings.Join(parts, ", "))
+	}
+	b.WriteString("\n")
+	fmt.Fprintf(&b, "Closed by them: %d (with a comment: %d, silently: %d)\n", closed, closedWithComment, closed-closedWithComment)
+	fmt.Fprintf(&b, "Still open with no response from them: %d\n", ignored)
+
+	var replies []ghcrawl.TriageData
+	for _, td := range all {
+		if td.FirstReply != "" {
+			replies = append(replies, td)
+		}
+	}
+	if len(replies) > 0 {
+		b.WriteString("\nExample first replies:\n")
+		for _, td := range replies[:min(10, len(replies))] {
+			fmt.Fprintf(&b, "- %s#%d %q (after %s): %s\n", td.Repo, td.Number, td.Title,
+				formatLatency(td.FirstResponse), strings.ReplaceAll(td.FirstReply, "\n", " "))
+		}
+	}
+	return b.String()
+}
+
+// formatLatency renders a response time at a human scale.
+func formatLatency(d time.Duration) string {
+	switch {
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 48*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	}
+}
@@ -3,6 +3,7 @@ package analyzer
 import (
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/drpaneas/devlica/internal/ghcrawl"
 )
@@ -239,6 +240,31 @@ func TestBuildDiscussionsText(t *testing.T) {
 	})
 }
 
+func TestBuildReviewRepliesText(t *testing.T) {
+	data := &ghcrawl.CrawlResult{Repos: []ghcrawl.RepoData{{
+		FullName: "acme/project",
+		ReviewReplies: []ghcrawl.ReviewReply{
+			{PRNumber: 3, PRTitle: "Tidy docs", Path: "README.md", ParentAuthor: "bob", Parent: "Why not a table?", Body: "Good point, done."},
+			{PRNumber: 7, PRTitle: "Add cache", Path: "cache.go", ParentAuthor: "carol", Parent: "This needs a benchmark.", Body: "Fair, numbers below.", OwnPR: true},
+		},
+	}}}
+	got := buildReviewRepliesText(data)
+	own := strings.Index(got, "PR #7: Add cache (file: cache.go, on their own PR)")
+	other := strings.Index(got, "PR #3: Tidy docs (file: README.md, reviewing someone else's PR)")
+	if own < 0 || other < 0 {
+		t.Fatalf("missing reply headers in %q", got)
+	}
+	if own > other {
+		t.Errorf("replies on their own PR should come first, got %q", got)
+	}
+	if !strings.Contains(got, "carol wrote:\nThis needs a benchmark.\n\nThey replied:\nFair, numbers below.") {
+		t.Errorf("expected parent comment before the reply, got %q", got)
+	}
+	if got := buildReviewRepliesText(&ghcrawl.CrawlResult{}); got != "" {
+		t.Errorf("expected empty text without replies, got %q", got)
+	}
+}
+
 func TestBuildProjectsText(t *testing.T) {
 	t.Run("empty", func(t *testing.T) {
 		data := &ghcrawl.CrawlResult{}
@@ -262,6 +288,33 @@ func TestBuildProjectsText(t *testing.T) {
 			t.Errorf("expected item count, got %q", got)
 		}
 	})
+	t.Run("with fields, views, and items", func(t *testing.T) {
+		data := &ghcrawl.CrawlResult{
+			Projects: []ghcrawl.ProjectData{{
+				Title:     "Sprint",
+				ItemCount: 40,
+				Closed:    true,
+				Fields:    []ghcrawl.ProjectField{{Name: "Status", DataType: "SINGLE_SELECT"}, {Name: "Estimate", DataType: "NUMBER"}},
+				Views:     []ghcrawl.ProjectView{{Name: "Board", Layout: "BOARD_LAYOUT"}},
+				Items: []ghcrawl.ProjectItem{
+					{Type: "ISSUE", Status: "Done", UpdatedAt: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)},
+					{Type: "ISSUE", Status: "Done"},
+					{Type: "DRAFT_ISSUE", Archived: true},
+				},
+			}},
+		}
+		got := buildProjectsText(data)
+		for _, want := range []string{
+			"Sprint (40 items, closed)",
+			"Fields: Status (single select), Estimate (number)",
+			"Views: Board (board)",
+			"Recent items: issue 2, draft issue 1; status: Done 2, (no status) 1; 1 archived; last updated 2024-05-01",
+		} {
+			if !strings.Contains(got, want) {
+				t.Errorf("expected %q in %q", want, got)
+			}
+		}
+	})
 }
 
 func TestBuildWikiPagesText(t *testing.T) {
@@ -314,3 +367,363 @@ func TestBuildGistsTextIncludesContentSnippet(t *testing.T) {
 		t.Fatalf("expected gist content in output, got %q", got)
 	}
 }
+
+func TestBuildCodeSamplesTextIncludesGists(t *testing.T) {
+	data := &ghcrawl.CrawlResult{
+		Repos: []ghcrawl.RepoData{
+			{
+				FullName:    "acme/project",
+				CodeSamples: []ghcrawl.CodeSample{{Path: "main.go", Content: "package main"}},
+			},
+		},
+		Gists: []ghcrawl.GistData{
+			{
+				ID: "abc123",
+				Files: []ghcrawl.GistFile{
+					{Name: "deploy.sh", Content: "set -euo pipefail"},
+					{Name: "notes.txt"},
+				},
+			},
+		},
+	}
+
+	got := buildCodeSamplesText(data)
+	if !strings.Contains(got, "=== gist abc123/deploy.sh ===") {
+		t.Fatalf("expected gist sample header, got %q", got)
+	}
+	if !strings.Contains(got, "set -euo pipefail") {
+		t.Fatalf("expected gist content, got %q", got)
+	}
+	if strings.Contains(got, "notes.txt") {
+		t.Fatalf("gist files without content should be skipped, got %q", got)
+	}
+}
+
+func TestBuildStarredReposTextIncludesWatchedAndStarredGists(t *testing.T) {
+	data := &ghcrawl.CrawlResult{
+		StarredRepos: []ghcrawl.StarredRepo{{FullName: "golang/go", Language: "Go"}},
+		WatchedRepos: []ghcrawl.StarredRepo{
+			{FullName: "golang/go", Language: "Go"},
+			{FullName: "tiny/emulator", Language: "C", Description: "cycle-accurate 6502"},
+		},
+		StarredGists: []ghcrawl.GistData{
+			{Owner: "bob", Description: "zsh prompt", Files: []ghcrawl.GistFile{{Name: "prompt.zsh", Language: "Shell"}}},
+		},
+	}
+
+	got := buildStarredReposText(data)
+	for _, want := range []string{
+		"Watched repos (subscribed, not starred):",
+		"- tiny/emulator (C, 0 stars): cycle-accurate 6502",
+		"- by bob: zsh prompt [prompt.zsh (Shell)]",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q in %q", want, got)
+		}
+	}
+	if strings.Count(got, "golang/go") != 1 {
+		t.Errorf("starred repo should not be repeated as watched: %q", got)
+	}
+
+	if got := buildStarredReposText(&ghcrawl.CrawlResult{WatchedRepos: data.WatchedRepos[1:]}); !strings.Contains(got, "tiny/emulator") {
+		t.Errorf("watched repos should show without starred repos, got %q", got)
+	}
+}
+
+func TestBuildReceptionText(t *testing.T) {
+	t.Run("no reactions", func(t *testing.T) {
+		data := &ghcrawl.CrawlResult{
+			IssueComments: []ghcrawl.Comment{{Repo: "acme/project", Body: "lgtm"}},
+		}
+		if got := buildReceptionText(data); got != "" {
+			t.Errorf("expected empty, got %q", got)
+		}
+	})
+
+	t.Run("most appreciated first", func(t *testing.T) {
+		data := &ghcrawl.CrawlResult{
+			IssueComments: []ghcrawl.Comment{
+				{Repo: "acme/project", Body: "minor", Reactions: ghcrawl.ReactionCounts{Total: 1, PlusOne: 1}},
+				{Repo: "acme/project", Body: "unreacted"},
+			},
+			ExternalPRs: []ghcrawl.PullRequestData{
+				{Repo: "other/lib", Number: 7, Title: "Fix data race", Reactions: ghcrawl.ReactionCounts{Total: 4, Heart: 2, Rocket: 2}},
+			},
+		}
+		got := buildReceptionText(data)
+		if !strings.Contains(got, "2 of 3 comments and PRs received reactions") {
+			t.Errorf("expected reacted ratio, got %q", got)
+		}
+		if strings.Index(got, "Fix data race") > strings.Index(got, "minor") {
+			t.Errorf("expected most reacted item first, got %q", got)
+		}
+		if strings.Contains(got, "unreacted") {
+			t.Errorf("items without reactions should be omitted, got %q", got)
+		}
+	})
+}
+
+func TestFormatReactions(t *testing.T) {
+	if got := formatReactions(ghcrawl.ReactionCounts{}); got != "" {
+		t.Errorf("expected empty for no reactions, got %q", got)
+	}
+	got := formatReactions(ghcrawl.ReactionCounts{Total: 3, PlusOne: 2, MinusOne: 1})
+	if got != " [reactions: 👍2 👎1 3 total]" {
+		t.Errorf("unexpected format %q", got)
+	}
+}
+
+func TestBuildDependenciesText(t *testing.T) {
+	if got := buildDependenciesText(&ghcrawl.CrawlResult{}); got != "" {
+		t.Errorf("expected empty, got %q", got)
+	}
+
+	data := &ghcrawl.CrawlResult{
+		Repos: []ghcrawl.RepoData{
+			{Dependencies: []ghcrawl.DependencyData{
+				{Repo: "acme/api", Path: "go.mod", Ecosystem: "go", Dependencies: []string{"github.com/spf13/cobra", "golang.org/x/sync"}},
+			}},
+			{Dependencies: []ghcrawl.DependencyData{
+				{Repo: "acme/cli", Path: "go.mod", Ecosystem: "go", Dependencies: []string{"github.com/spf13/cobra"}},
+			}},
+		},
+	}
+	got := buildDependenciesText(data)
+	if !strings.Contains(got, "github.com/spf13/cobra (go): 2 repos") {
+		t.Errorf("expected shared dependency ranked with repo count, got %q", got)
+	}
+	if strings.Index(got, "cobra") > strings.Index(got, "golang.org/x/sync") {
+		t.Errorf("expected most used dependency first, got %q", got)
+	}
+	if !strings.Contains(got, "acme/cli/go.mod (go)") {
+		t.Errorf("expected manifest listing, got %q", got)
+	}
+}
+
+func TestBuildDocsText(t *testing.T) {
+	if got := buildDocsText(&ghcrawl.CrawlResult{}); got != "" {
+		t.Errorf("expected empty, got %q", got)
+	}
+
+	data := &ghcrawl.CrawlResult{
+		Repos: []ghcrawl.RepoData{
+			{
+				FullName: "acme/project",
+				Docs: []ghcrawl.RepoDoc{
+					{Path: "README.md", Kind: "readme", Content: "# project\nQuick start"},
+				},
+			},
+		},
+	}
+	got := buildDocsText(data)
+	if !strings.Contains(got, "=== acme/project README.md (readme) ===") {
+		t.Errorf("expected doc header, got %q", got)
+	}
+	if !strings.Contains(got, "Quick start") {
+		t.Errorf("expected doc content, got %q", got)
+	}
+}
+
+func TestTagScheme(t *testing.T) {
+	tests := []struct {
+		tag  string
+		want string
+	}{
+		{"v1.2.3", "semver with v prefix"},
+		{"v0.4", "semver with v prefix"},
+		{"1.2.3-rc.1", "semver without prefix"},
+		{"2024.05.01", "date"},
+		{"v20240501", "date"},
+		{"nightly", "other"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			if got := tagScheme(tt.tag); got != tt.want {
+				t.Errorf("tagScheme(%q) = %q, want %q", tt.tag, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildRefNamesText(t *testing.T) {
+	if got := buildRefNamesText(&ghcrawl.CrawlResult{}); got != "" {
+		t.Errorf("expected empty, got %q", got)
+	}
+
+	data := &ghcrawl.CrawlResult{
+		Repos: []ghcrawl.RepoData{
+			{
+				FullName:      "acme/project",
+				DefaultBranch: "main",
+				Branches:      []string{"main", "feature/login", "feature/logout", "fix-typo"},
+				Tags:          []string{"v1.0.0", "v1.1.0"},
+			},
+		},
+	}
+	got := buildRefNamesText(data)
+	for _, want := range []string{"feature/: 2", "fix-: 1", "semver with v prefix: 2", "tags: v1.0.0, v1.1.0"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q in output, got %q", want, got)
+		}
+	}
+}
+
+func TestBuildWorkflowRunsText(t *testing.T) {
+	data := &ghcrawl.CrawlResult{
+		Repos: []ghcrawl.RepoData{
+			{FullName: "acme/quiet"},
+			{
+				FullName:      "acme/project",
+				DefaultBranch: "main",
+				WorkflowRuns: ghcrawl.WorkflowRunStats{
+					Total: 10, Success: 8, Failure: 2,
+					DefaultBranchTotal: 5, DefaultBranchFailures: 1, FailureStreak: 1,
+				},
+			},
+		},
+	}
+	got := buildWorkflowRunsText(data)
+	if strings.Contains(got, "acme/quiet") {
+		t.Errorf("repos without runs should be skipped, got %q", got)
+	}
+	for _, want := range []string{"10 recent runs, 80% success", "main: 1 of 5 runs failed", "currently red for 1 runs"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q in output, got %q", want, got)
+		}
+	}
+}
+
+func TestBuildToolingText(t *testing.T) {
+	data := &ghcrawl.CrawlResult{
+		Repos: []ghcrawl.RepoData{
+			{FullName: "acme/quiet"},
+			{
+				FullName: "acme/project",
+				Tooling: []ghcrawl.CodeSample{
+					{Path: ".github/workflows/ci.yml", Content: "on: push"},
+					{Path: "Makefile", Content: "lint:\n\tgolangci-lint run"},
+				},
+			},
+		},
+	}
+	got := buildToolingText(data)
+	if strings.Contains(got, "acme/quiet") {
+		t.Errorf("repos without tooling should be skipped, got %q", got)
+	}
+	for _, want := range []string{"=== acme/project/.github/workflows/ci.yml ===\non: push", "=== acme/project/Makefile ===\nlint:"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q in output, got %q", want, got)
+		}
+	}
+}
+
+func TestBuildTimelineText(t *testing.T) {
+	if got := buildTimelineText(&ghcrawl.CrawlResult{}); got != "" {
+		t.Errorf("expected empty, got %q", got)
+	}
+
+	start := time.Date(2022, 12, 19, 0, 0, 0, 0, time.UTC)
+	week := func(n, commits int) ghcrawl.WeeklyActivity {
+		return ghcrawl.WeeklyActivity{Week: start.AddDate(0, 0, 7*n), Commits: commits, Additions: commits * 10}
+	}
+	data := &ghcrawl.CrawlResult{
+		Timeline: []ghcrawl.WeeklyActivity{week(0, 1), week(1, 1), week(2, 1), week(6, 7)},
+	}
+	got := buildTimelineText(data)
+	for _, want := range []string{
+		"Active 4 of 7 weeks",
+		"Longest streak of consecutive active weeks: 3",
+		"Longest gap between active weeks: 3 weeks",
+		"2022: 2 active weeks, 2 commits",
+		"2023: 2 active weeks, 8 commits",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q in %q", want, got)
+		}
+	}
+}
+
+func TestBuildTriageText(t *testing.T) {
+	if got := buildTriageText(&ghcrawl.CrawlResult{}); got != "" {
+		t.Errorf("expected empty, got %q", got)
+	}
+
+	data := &ghcrawl.CrawlResult{
+		Repos: []ghcrawl.RepoData{
+			{
+				FullName: "alice/tool",
+				Triage: []ghcrawl.TriageData{
+					{Repo: "alice/tool", Number: 1, Title: "crash", Responded: true, FirstResponse: 30 * time.Minute,
+						FirstReply: "Thanks, can you share\nthe log?", Labels: []string{"bug"}, ClosedByUser: true, ClosedWithComment: true},
+					{Repo: "alice/tool", Number: 2, Title: "question", Responded: true, FirstResponse: 3 * time.Hour,
+						Labels: []string{"question"}, ClosedByUser: true},
+					{Repo: "alice/tool", Number: 3, Title: "feature", Open: true},
+				},
+			},
+		},
+	}
+	got := buildTriageText(data)
+	for _, want := range []string{
+		"Issues opened by others on their repos: 3 (across 1 repos)",
+		"Responded to: 2 of 3 (67%); median first response 3h (fastest 30m, slowest 3h)",
+		"Labeled by them: 2 of 3",
+		"Closed by them: 2 (with a comment: 1, silently: 1)",
+		"Still open with no response from them: 1",
+		`alice/tool#1 "crash" (after 30m): Thanks, can you share the log?`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q in %q", want, got)
+		}
+	}
+}
+
+func TestBuildOrgsTextSponsorship(t *testing.T) {
+	data := &ghcrawl.CrawlResult{
+		Orgs: []string{"acme"},
+		Sponsorship: &ghcrawl.SponsorshipData{
+			Sponsorable:     true,
+			SponsorCount:    3,
+			SponsoringCount: 2,
+			Sponsoring:      []string{"bob", "golang"},
+			OwnedRepos:      5,
+			FundedRepos: []ghcrawl.FundingData{
+				{Repo: "alice/tool", Platforms: []string{"GITHUB", "KO_FI"}},
+				{Repo: "alice/lib", Platforms: []string{"GITHUB"}},
+			},
+		},
+	}
+	got := buildOrgsText(data)
+	for _, want := range []string{
+		"Member of: acme",
+		"GitHub Sponsors profile with 3 sponsors",
+		"Publicly sponsors 2 accounts: bob, golang",
+		"funding links (FUNDING.yml): 2 of 5 (platforms: github 2, ko_fi 1)",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q in %q", want, got)
+		}
+	}
+
+	if got := buildOrgsText(&ghcrawl.CrawlResult{}); got != "No public organization memberships." {
+		t.Errorf("without sponsorship data got %q", got)
+	}
+}
+
+func TestBuildCommitMessagesText(t *testing.T) {
+	data := &ghcrawl.CrawlResult{
+		Repos: []ghcrawl.RepoData{{
+			FullName: "alice/tool",
+			Commits: []ghcrawl.CommitData{
+				{SHA: "0123456789abcdef", Message: "fix(cli): handle empty input\n\nPreviously we panicked."},
+				{SHA: "fedcba9876543210", Message: "Merge pull request #4 from bob/patch"},
+			},
+		}},
+	}
+	got := buildCommitMessagesText(data)
+	if !strings.Contains(got, "=== alice/tool - 01234567 ===\nURL: https://github.com/alice/tool/commit/0123456789abcdef\nfix(cli): handle empty input\n\nPreviously we panicked.") {
+		t.Errorf("expected full message with body, got %q", got)
+	}
+	if strings.Contains(got, "Merge pull request") {
+		t.Errorf("merge commits should be skipped, got %q", got)
+	}
+}
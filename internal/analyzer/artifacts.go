@@ -0,0 +1,43 @@
+package analyzer
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// SetArtifactDir makes the analyzer write each dimension's raw output to
+// dir/<dimension>.md as soon as it completes, so a failure later in the run
+// does not lose finished analyses. filter, when not nil, is applied to the
+// output before it is written, e.g. to redact it.
+func (a *Analyzer) SetArtifactDir(dir string, filter func(string) string) {
+	a.artifactDir = dir
+	a.artifactFilter = filter
+}
+
+var unsafeArtifactChars = regexp.MustCompile(`[^a-z0-9+#.-]+`)
+
+// saveArtifact writes one analysis to the artifact directory. Errors are
+// logged rather than returned: the artifacts are a convenience and must not
+// fail an otherwise good run.
+func (a *Analyzer) saveArtifact(name, content string) {
+	if a.artifactDir == "" {
+		return
+	}
+	name = strings.Trim(unsafeArtifactChars.ReplaceAllString(strings.ToLower(name), "-"), "-.")
+	if a.artifactFilter != nil {
+		content = a.artifactFilter(content)
+	}
+	if err := os.MkdirAll(a.artifactDir, 0o755); err != nil {
+		slog.Warn("creating analysis directory", "dir", a.artifactDir, "error", err)
+		return
+	}
+	path := filepath.Join(a.artifactDir, name+".md")
+	if err := os.WriteFile(path, []byte(strings.TrimSpace(content)+"\n"), 0o644); err != nil {
+		slog.Warn("writing analysis", "path", path, "error", err)
+		return
+	}
+	slog.Debug("wrote analysis", "path", path)
+}
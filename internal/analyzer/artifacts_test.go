@@ -0,0 +1,48 @@
+package analyzer
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestArtifactsSurviveFailedSynthesis(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "analysis")
+	a := New(&recordingProvider{reply: "alice prefers small diffs"})
+	a.SetArtifactDir(dir, func(s string) string { return strings.ReplaceAll(s, "alice", "[user]") })
+
+	_, err := a.Reanalyze(context.Background(), "alice", promptFixture(), &Persona{}, []string{"review-style"})
+	if err == nil {
+		t.Fatal("expected the synthesis to fail on a non-JSON reply")
+	}
+
+	for _, name := range []string{"review-style.md", "synthesis.md"} {
+		got, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatalf("%s was not written: %v", name, err)
+		}
+		if string(got) != "[user] prefers small diffs\n" {
+			t.Errorf("%s = %q, want the filtered reply", name, got)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "code-style.md")); !os.IsNotExist(err) {
+		t.Errorf("code-style.md should not be written for a dimension that did not run: %v", err)
+	}
+}
+
+func TestSaveArtifactSanitizesName(t *testing.T) {
+	dir := t.TempDir()
+	a := New(&recordingProvider{})
+	a.SetArtifactDir(dir, nil)
+
+	a.saveArtifact("language-style-C++", "rules")
+	a.saveArtifact("language-style-Objective C", "rules")
+
+	for _, name := range []string{"language-style-c++.md", "language-style-objective-c.md"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Errorf("%s: %v", name, err)
+		}
+	}
+}
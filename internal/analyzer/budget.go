@@ -0,0 +1,179 @@
+package analyzer
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+)
+
+const (
+	// defaultContextWindow is used until SetContextWindow is called.
+	defaultContextWindow = 32_000
+	// maxInputTokens caps the data in one prompt even for models with huge
+	// windows, since cost grows with every token sent.
+	maxInputTokens = 128_000
+	// maxOutputReserve is kept free for the model's reply.
+	maxOutputReserve = 16_384
+	// bytesPerToken is a conservative estimate for mixed prose and code.
+	bytesPerToken = 3
+	// minChunkBytes keeps tiny windows from splitting input into slivers.
+	minChunkBytes = 2_000
+)
+
+// SetContextWindow sizes prompts for a model with the given context window
+// in tokens.
+func (a *Analyzer) SetContextWindow(tokens int) {
+	a.contextWindow = tokens
+}
+
+// promptBytes returns how many bytes of data one prompt may carry.
+func (a *Analyzer) promptBytes() int {
+	window := a.contextWindow
+	if window <= 0 {
+		window = defaultContextWindow
+	}
+	input := min(window-min(maxOutputReserve, window/4), maxInputTokens)
+	return input * bytesPerToken
+}
+
+// chunkBytes returns the size of one map or reduce chunk, leaving room for
+// the summarization prompt around it.
+func (a *Analyzer) chunkBytes() int {
+	return max(a.promptBytes()-len(evidenceReducePrompt), minChunkBytes)
+}
+
+// source is one data section of an analysis prompt.
+type source struct {
+	label string
+	text  string
+}
+
+// prepare fits the sources of one prompt into the prompt budget, leaving
+// overhead bytes for the prompt text and any sections that are sent as is.
+// Sources over their share of the budget (see allocate) are summarized down
+// to it.
+func (a *Analyzer) prepare(ctx context.Context, usage *usageLog, prompt string, overhead int, sources ...source) ([]string, error) {
+	sizes := make([]int, len(sources))
+	for i, s := range sources {
+		sizes[i] = len(s.text)
+	}
+	limits := allocate(sizes, max(a.promptBytes()-overhead, minChunkBytes))
+
+	prepared := make([]string, len(sources))
+	for i, s := range sources {
+		out, discarded, err := a.compressToFit(ctx, s.label, s.text, limits[i])
+		if err != nil {
+			return nil, fmt.Errorf("compressing %s: %w", s.label, err)
+		}
+		prepared[i] = out
+		if s.text != "" {
+			usage.add(SourceUsage{
+				Prompt:     prompt,
+				Source:     s.label,
+				Bytes:      len(s.text),
+				Budget:     limits[i],
+				Sent:       len(out),
+				Summarized: len(s.text) > limits[i],
+				Discarded:  discarded,
+			})
+		}
+	}
+	return prepared, nil
+}
+
+// allocate splits total among sources of the given sizes. Every source is
+// first guaranteed a floor of half an even share, so small sources are not
+// squeezed to nothing, and the rest is split in proportion to what each
+// still needs. Sources that fit are never given more than their size.
+func allocate(sizes []int, total int) []int {
+	limits := make([]int, len(sizes))
+	if len(sizes) == 0 {
+		return limits
+	}
+	floor := total / (2 * len(sizes))
+	remaining, need := total, 0
+	for i, size := range sizes {
+		limits[i] = min(size, floor)
+		remaining -= limits[i]
+		need += size - limits[i]
+	}
+	if need <= remaining {
+		return slices.Clone(sizes)
+	}
+	for i, size := range sizes {
+		limits[i] += int(int64(remaining) * int64(size-limits[i]) / int64(need))
+	}
+	return limits
+}
+
+// SourceUsage records how one data source fit into its prompt.
+type SourceUsage struct {
+	Prompt string `json:"prompt"`
+	Source string `json:"source"`
+	// Bytes is the size of the source before fitting.
+	Bytes int `json:"bytes"`
+	// Budget is the share of the prompt the source was given.
+	Budget int `json:"budget"`
+	// Sent is the size of what went into the prompt.
+	Sent int `json:"sent"`
+	// Summarized reports whether the source was condensed by the LLM
+	// instead of sent verbatim.
+	Summarized bool `json:"summarized"`
+	// Discarded counts bytes cut because summaries stopped shrinking.
+	Discarded int `json:"discarded"`
+}
+
+// DataUsage totals how much crawled data reached the model.
+type DataUsage struct {
+	Sources []SourceUsage `json:"sources"`
+}
+
+// Totals returns the bytes sent verbatim, the bytes condensed by
+// summarization, and the bytes discarded by truncation.
+func (u DataUsage) Totals() (verbatim, summarized, discarded int) {
+	for _, s := range u.Sources {
+		if s.Summarized {
+			summarized += s.Bytes
+		} else {
+			verbatim += s.Bytes
+		}
+		discarded += s.Discarded
+	}
+	return verbatim, summarized, discarded
+}
+
+// Log writes the totals at info level and each source at debug level.
+func (u DataUsage) Log() {
+	verbatim, summarized, discarded := u.Totals()
+	slog.Info("data usage", "sources", len(u.Sources), "verbatim_bytes", verbatim, "summarized_bytes", summarized, "discarded_bytes", discarded)
+	for _, s := range u.Sources {
+		slog.Debug("data usage", "prompt", s.Prompt, "source", s.Source, "bytes", s.Bytes, "budget", s.Budget, "sent", s.Sent, "summarized", s.Summarized, "discarded", s.Discarded)
+	}
+}
+
+// usageLog collects SourceUsage from concurrent analyses.
+type usageLog struct {
+	mu      sync.Mutex
+	sources []SourceUsage
+}
+
+func (l *usageLog) add(s SourceUsage) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.sources = append(l.sources, s)
+}
+
+// usage returns the collected records sorted by prompt, in the order each
+// prompt listed its sources.
+func (l *usageLog) usage() DataUsage {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	sources := slices.Clone(l.sources)
+	slices.SortStableFunc(sources, func(a, b SourceUsage) int {
+		return strings.Compare(a.Prompt, b.Prompt)
+	})
+	return DataUsage{Sources: sources}
+}
@@ -0,0 +1,78 @@
+package analyzer
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestAllocate(t *testing.T) {
+	tests := []struct {
+		name  string
+		sizes []int
+		total int
+		want  []int
+	}{
+		{"everything fits", []int{10, 20}, 100, []int{10, 20}},
+		{"proportional above the floor", []int{300, 100}, 200, []int{133, 66}},
+		{"small sources keep all and give the rest away", []int{1000, 10, 1000}, 510, []int{250, 10, 250}},
+		{"empty sources get nothing", []int{0, 500}, 100, []int{0, 100}},
+		{"no sources", nil, 100, []int{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := allocate(tt.sizes, tt.total)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("allocate(%v, %d) = %v, want %v", tt.sizes, tt.total, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPromptBytesFollowsContextWindow(t *testing.T) {
+	a := New(&recordingProvider{})
+	small := a.promptBytes()
+	a.SetContextWindow(200_000)
+	large := a.promptBytes()
+	if large <= small {
+		t.Errorf("promptBytes() = %d for a 200k window, want more than the default %d", large, small)
+	}
+	a.SetContextWindow(2_000_000)
+	if got := a.promptBytes(); got != maxInputTokens*bytesPerToken {
+		t.Errorf("promptBytes() = %d for a 2M window, want the %d cap", got, maxInputTokens*bytesPerToken)
+	}
+}
+
+func TestPrepareRecordsUsage(t *testing.T) {
+	p := &recordingProvider{reply: "summary"}
+	a := New(p)
+	a.SetContextWindow(8192)
+	budget := a.promptBytes()
+	big := strings.Repeat("b", budget*2)
+	usage := &usageLog{}
+
+	got, err := a.prepare(context.Background(), usage, "test", 0,
+		source{"small", "tiny"},
+		source{"empty", ""},
+		source{"big", big},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got[0] != "tiny" || got[1] != "" || !strings.HasPrefix(got[2], "summary") {
+		t.Errorf("prepare() = %q, want small kept, empty empty, big summarized", got)
+	}
+
+	u := usage.usage()
+	if len(u.Sources) != 2 {
+		t.Fatalf("recorded %d sources, want 2 (empty ones skipped)", len(u.Sources))
+	}
+	verbatim, summarized, discarded := u.Totals()
+	if verbatim != len("tiny") || summarized != len(big) || discarded != 0 {
+		t.Errorf("Totals() = %d, %d, %d; want %d, %d, 0", verbatim, summarized, discarded, len("tiny"), len(big))
+	}
+	if b := u.Sources[1]; b.Source != "big" || !b.Summarized || b.Budget != budget-len("tiny") || b.Sent != len(got[2]) {
+		t.Errorf("big source usage = %+v", b)
+	}
+}
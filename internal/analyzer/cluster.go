@@ -0,0 +1,143 @@
+package analyzer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+
+	"github.com/drpaneas/devlica/internal/ghcrawl"
+	"github.com/drpaneas/devlica/internal/llm"
+)
+
+const (
+	// duplicateSimilarity is the cosine similarity at which two review
+	// comments count as the same remark ("nit: typo" vs "typo nit").
+	duplicateSimilarity = 0.92
+	// embedBatchSize bounds how many texts go into one embeddings request.
+	embedBatchSize = 256
+)
+
+// clusterReviewComments folds near-identical inline review comments into one
+// exemplar per cluster so a remark posted hundreds of times takes one slot in
+// the prompt instead of crowding out the rest. The exemplar is the comment
+// with the best reception, and its body notes how many comments it stands
+// for. When the provider cannot embed, data is returned unchanged.
+func (a *Analyzer) clusterReviewComments(ctx context.Context, data *ghcrawl.CrawlResult) *ghcrawl.CrawlResult {
+	embedder, ok := a.provider.(llm.Embedder)
+	if !ok {
+		return data
+	}
+	var comments []ghcrawl.ReviewComment
+	var repoOf []int
+	for i, repo := range data.Repos {
+		comments = append(comments, repo.ReviewComments...)
+		for range repo.ReviewComments {
+			repoOf = append(repoOf, i)
+		}
+	}
+	if len(comments) < 2 {
+		return data
+	}
+
+	texts := make([]string, len(comments))
+	for i, rc := range comments {
+		texts[i] = rc.Body
+	}
+	vectors, err := embedAll(ctx, embedder, texts)
+	if errors.Is(err, llm.ErrNoEmbedModel) {
+		return data
+	}
+	if err != nil {
+		slog.Warn("could not embed review comments, keeping duplicates", "error", err)
+		return data
+	}
+
+	clusters := clusterVectors(vectors, duplicateSimilarity)
+	kept := make([][]ghcrawl.ReviewComment, len(data.Repos))
+	for _, members := range clusters {
+		best := members[0]
+		for _, m := range members[1:] {
+			if comments[m].Reactions.Positive() > comments[best].Reactions.Positive() {
+				best = m
+			}
+		}
+		rc := comments[best]
+		if len(members) > 1 {
+			rc.Body += fmt.Sprintf("\n(posted %d times in near-identical form)", len(members))
+		}
+		kept[repoOf[best]] = append(kept[repoOf[best]], rc)
+	}
+	slog.Info("deduplicated review comments", "comments", len(comments), "clusters", len(clusters))
+
+	out := *data
+	out.Repos = make([]ghcrawl.RepoData, len(data.Repos))
+	for i, repo := range data.Repos {
+		repo.ReviewComments = kept[i]
+		out.Repos[i] = repo
+	}
+	return &out
+}
+
+// embedAll embeds texts in batches of embedBatchSize.
+func embedAll(ctx context.Context, e llm.Embedder, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, 0, len(texts))
+	for start := 0; start < len(texts); start += embedBatchSize {
+		batch, err := e.Embed(ctx, texts[start:min(start+embedBatchSize, len(texts))])
+		if err != nil {
+			return nil, err
+		}
+		vectors = append(vectors, batch...)
+	}
+	return vectors, nil
+}
+
+// clusterVectors groups vectors greedily: each joins the first cluster whose
+// leader it is at least threshold-similar to, or starts a new one. Clusters
+// and their members keep input order.
+func clusterVectors(vectors [][]float32, threshold float64) [][]int {
+	normalized := make([][]float32, len(vectors))
+	for i, v := range vectors {
+		normalized[i] = normalize(v)
+	}
+	var clusters [][]int
+	for i, v := range normalized {
+		placed := false
+		for c, members := range clusters {
+			if dot(normalized[members[0]], v) >= threshold {
+				clusters[c] = append(clusters[c], i)
+				placed = true
+				break
+			}
+		}
+		if !placed {
+			clusters = append(clusters, []int{i})
+		}
+	}
+	return clusters
+}
+
+func normalize(v []float32) []float32 {
+	var sum float64
+	for _, x := range v {
+		sum += float64(x) * float64(x)
+	}
+	if sum == 0 {
+		return v
+	}
+	norm := float32(math.Sqrt(sum))
+	out := make([]float32, len(v))
+	for i, x := range v {
+		out[i] = x / norm
+	}
+	return out
+}
+
+func dot(a, b []float32) float64 {
+	var sum float64
+	for i := range min(len(a), len(b)) {
+		sum += float64(a[i]) * float64(b[i])
+	}
+	return sum
+}
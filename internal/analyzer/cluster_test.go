@@ -0,0 +1,78 @@
+package analyzer
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/drpaneas/devlica/internal/ghcrawl"
+	"github.com/drpaneas/devlica/internal/llm"
+)
+
+// embeddingProvider embeds each text as a fixed vector keyed by its body.
+type embeddingProvider struct {
+	recordingProvider
+	vectors map[string][]float32
+}
+
+func (p *embeddingProvider) Embed(_ context.Context, texts []string) ([][]float32, error) {
+	out := make([][]float32, len(texts))
+	for i, t := range texts {
+		out[i] = p.vectors[t]
+	}
+	return out, nil
+}
+
+var _ llm.Embedder = (*embeddingProvider)(nil)
+
+func TestClusterVectors(t *testing.T) {
+	vectors := [][]float32{{1, 0}, {0, 1}, {0.99, 0.05}, {0, 2}}
+	got := clusterVectors(vectors, 0.9)
+	want := [][]int{{0, 2}, {1, 3}}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range want {
+		if len(got[i]) != len(want[i]) || got[i][0] != want[i][0] || got[i][1] != want[i][1] {
+			t.Errorf("cluster %d = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestClusterReviewComments(t *testing.T) {
+	p := &embeddingProvider{vectors: map[string][]float32{
+		"nit: typo":       {1, 0},
+		"typo nit":        {0.98, 0.1},
+		"this leaks a fd": {0, 1},
+	}}
+	data := &ghcrawl.CrawlResult{
+		Repos: []ghcrawl.RepoData{
+			{FullName: "acme/a", ReviewComments: []ghcrawl.ReviewComment{
+				{Repo: "acme/a", Body: "nit: typo"},
+				{Repo: "acme/a", Body: "this leaks a fd"},
+			}},
+			{FullName: "acme/b", ReviewComments: []ghcrawl.ReviewComment{
+				{Repo: "acme/b", Body: "typo nit", Reactions: ghcrawl.ReactionCounts{PlusOne: 2}},
+			}},
+		},
+	}
+
+	got := New(p).clusterReviewComments(context.Background(), data)
+	if len(got.Repos[0].ReviewComments) != 1 || got.Repos[0].ReviewComments[0].Body != "this leaks a fd" {
+		t.Errorf("acme/a comments = %+v", got.Repos[0].ReviewComments)
+	}
+	b := got.Repos[1].ReviewComments
+	if len(b) != 1 || !strings.HasPrefix(b[0].Body, "typo nit") || !strings.Contains(b[0].Body, "posted 2 times") {
+		t.Errorf("acme/b comments = %+v, want the better-received duplicate as exemplar", b)
+	}
+	if len(data.Repos[0].ReviewComments) != 2 {
+		t.Error("input data must not be modified")
+	}
+}
+
+func TestClusterReviewCommentsWithoutEmbedder(t *testing.T) {
+	data := &ghcrawl.CrawlResult{Repos: []ghcrawl.RepoData{{ReviewComments: make([]ghcrawl.ReviewComment, 3)}}}
+	if got := New(&recordingProvider{}).clusterReviewComments(context.Background(), data); got != data {
+		t.Error("expected data unchanged when the provider cannot embed")
+	}
+}
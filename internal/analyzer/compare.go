@@ -0,0 +1,84 @@
+package analyzer
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"strings"
+
+	"github.com/drpaneas/devlica/internal/prompts"
+)
+
+const eraComparisonPrompt = `Below are two personas of developer %s, each built only from their GitHub activity in one period.
+
+PERSONA FOR %s:
+%s
+
+PERSONA FOR %s:
+%s
+
+Write a markdown report on how the developer evolved from the first period to the second, citing years
+and quoting examples from the personas. Use these sections:
+## Summary (three to five sentences)
+## Coding Style
+## Commit Messages
+## Code Review
+## Communication
+## Unchanged Habits
+## Data Caveats (where a period has too little data to support a comparison)
+
+Only describe a change when both personas support it. If a persona says a dimension had insufficient
+data, say the comparison is not possible for that dimension instead of guessing.`
+
+// Era is a persona built from one period of a developer's activity.
+type Era struct {
+	Label   string
+	Persona *Persona
+}
+
+// CompareEras asks the LLM for a markdown report on how the developer
+// changed from the first era to the second.
+func (a *Analyzer) CompareEras(ctx context.Context, username string, first, second Era) (string, error) {
+	firstText, secondText := formatEra(first.Persona), formatEra(second.Persona)
+	prepared, err := a.prepare(ctx, &usageLog{}, "era-comparison", len(eraComparisonPrompt),
+		source{first.Label + " persona", firstText},
+		source{second.Label + " persona", secondText},
+	)
+	if err != nil {
+		return "", err
+	}
+	slog.Info("comparing eras", "first", first.Label, "second", second.Label)
+	report, err := a.complete(ctx, "era-comparison", eraComparisonPrompt,
+		prompts.Arg("Username", username),
+		prompts.Arg("FirstLabel", first.Label),
+		prompts.Arg("First", prepared[0]),
+		prompts.Arg("SecondLabel", second.Label),
+		prompts.Arg("Second", prepared[1]),
+	)
+	if err != nil {
+		return "", fmt.Errorf("era comparison: %w", err)
+	}
+	return report, nil
+}
+
+// formatEra renders every synthesis field under its name, followed by the
+// hard metrics, which anchor the comparison in numbers.
+func formatEra(p *Persona) string {
+	var b strings.Builder
+	if p.Synthesis != nil {
+		v := reflect.ValueOf(p.Synthesis).Elem()
+		for i := range v.NumField() {
+			f := v.Field(i)
+			if f.Kind() != reflect.String || f.String() == "" {
+				continue
+			}
+			name, _, _ := strings.Cut(v.Type().Field(i).Tag.Get("json"), ",")
+			fmt.Fprintf(&b, "%s:\n%s\n\n", strings.ToUpper(strings.ReplaceAll(name, "_", " ")), f.String())
+		}
+	}
+	if m := p.Metrics.Format(); m != "" {
+		fmt.Fprintf(&b, "HARD METRICS:\n%s", m)
+	}
+	return b.String()
+}
@@ -0,0 +1,40 @@
+package analyzer
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/drpaneas/devlica/internal/stats"
+)
+
+func TestCompareEras(t *testing.T) {
+	p := &recordingProvider{reply: "## Summary\nThey got terser."}
+	first := Era{Label: "2019-2021", Persona: &Persona{
+		Synthesis: &SynthesisResult{ReviewVoice: "Long, polite explanations."},
+		Metrics:   stats.Metrics{ReviewComments: 40, ReviewLenMean: 310},
+	}}
+	second := Era{Label: "2022-2024", Persona: &Persona{
+		Synthesis: &SynthesisResult{ReviewVoice: "One-line nits."},
+	}}
+
+	got, err := New(p).CompareEras(context.Background(), "alice", first, second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != p.reply {
+		t.Errorf("CompareEras() = %q, want the model's report", got)
+	}
+	if len(p.prompts) != 1 {
+		t.Fatalf("sent %d prompts, want 1", len(p.prompts))
+	}
+	prompt := p.prompts[0]
+	for _, want := range []string{"PERSONA FOR 2019-2021:", "REVIEW VOICE:\nLong, polite explanations.", "PERSONA FOR 2022-2024:", "One-line nits.", "HARD METRICS:"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("prompt missing %q:\n%s", want, prompt)
+		}
+	}
+	if strings.Contains(prompt, "%!") {
+		t.Errorf("prompt has a formatting error:\n%s", prompt)
+	}
+}
@@ -0,0 +1,171 @@
+package analyzer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/drpaneas/devlica/internal/prompts"
+)
+
+const skillCompressionPrompt = `You are condensing sections of a developer persona so the skills built from it fit an AI agent's context budget. The agent impersonates %s from these sections, so what makes them this developer must survive.
+
+SECTIONS TO CONDENSE (JSON: each field's text and the most characters it may use):
+%s
+
+Rewrite each section within its character limit. Keep:
+- concrete rules, names, thresholds, and tools
+- quoted phrases and short examples of how they write or review
+- anything that sets this developer apart from a generic senior engineer
+
+Drop repetition, hedging, generic advice any engineer would follow, and sentences that restate another section. Keep the section's Markdown structure where it helps, but prefer fewer, denser bullets. Do not invent anything that is not in the original.
+
+Respond with a single JSON object (no markdown, no commentary) that maps each field name above to its condensed text.`
+
+// skillCompressionSchema is the JSON Schema of a compression reply.
+var skillCompressionSchema = mustMarshal(map[string]any{
+	"type":                 "object",
+	"additionalProperties": map[string]any{"type": "string"},
+})
+
+// SetSkillBudget makes Analyze condense the synthesis when the sections of
+// any one skill add up to more than tokens. skills maps each skill to the
+// synthesis fields, by JSON name, it is built from. Zero tokens disables
+// the budget.
+func (a *Analyzer) SetSkillBudget(tokens int, skills map[string][]string) {
+	a.skillTokens = tokens
+	a.skillFields = skills
+}
+
+// skillTokens estimates the tokens of each skill's synthesized sections.
+func skillTokens(s *SynthesisResult, skills map[string][]string) map[string]int {
+	text := synthesisText(s)
+	tokens := make(map[string]int, len(skills))
+	for name, fields := range skills {
+		n := 0
+		for _, f := range fields {
+			n += len(text[f])
+		}
+		tokens[name] = n / bytesPerToken
+	}
+	return tokens
+}
+
+// compressionTarget returns the most characters each field of an
+// over-budget skill may keep: every such field shrinks by the ratio its
+// largest skill needs. It is empty when every skill fits.
+func compressionTarget(s *SynthesisResult, skills map[string][]string, budget int) map[string]int {
+	text := synthesisText(s)
+	ratios := make(map[string]float64)
+	tokens := skillTokens(s, skills)
+	for name, n := range tokens {
+		if n <= budget {
+			continue
+		}
+		ratio := float64(budget) / float64(n)
+		for _, f := range skills[name] {
+			if text[f] == "" {
+				continue
+			}
+			if r, ok := ratios[f]; !ok || ratio < r {
+				ratios[f] = ratio
+			}
+		}
+	}
+	targets := make(map[string]int, len(ratios))
+	for f, r := range ratios {
+		targets[f] = int(float64(len(text[f])) * r)
+	}
+	return targets
+}
+
+// FitSkillBudget condenses the synthesis fields of the skills over the
+// SetSkillBudget budget with one LLM call, and does nothing when they all
+// fit. Analyze calls it after the synthesis; call it again after anything
+// else rewrites the synthesis. Like the grounding check, a failed call is
+// logged and leaves the persona as it was.
+func (a *Analyzer) FitSkillBudget(ctx context.Context, persona *Persona) error {
+	if a.skillTokens <= 0 || persona.Synthesis == nil {
+		return nil
+	}
+	targets := compressionTarget(persona.Synthesis, a.skillFields, a.skillTokens)
+	if len(targets) == 0 {
+		return nil
+	}
+	text := synthesisText(persona.Synthesis)
+	type section struct {
+		Text     string `json:"text"`
+		MaxChars int    `json:"max_chars"`
+	}
+	sections := make(map[string]section, len(targets))
+	for f, n := range targets {
+		sections[f] = section{Text: text[f], MaxChars: n}
+	}
+	encoded, err := json.MarshalIndent(sections, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding persona for compression: %w", err)
+	}
+
+	before := skillTokens(persona.Synthesis, a.skillFields)
+	slog.Info("condensing persona to the skill token budget", "max_tokens", a.skillTokens, "fields", strings.Join(slices.Sorted(maps.Keys(targets)), ","))
+	raw, err := a.completeJSON(ctx, "skill-compression", skillCompressionPrompt, skillCompressionSchema,
+		prompts.Arg("Username", persona.Username),
+		prompts.Arg("Sections", string(encoded)),
+	)
+	if err != nil {
+		slog.Warn("skill compression failed, keeping the persona as synthesized", "error", err)
+		return nil
+	}
+	a.saveArtifact("skill-compression", raw)
+	condensed, err := parseRepairing(ctx, a, "skill-compression", raw, skillCompressionSchema, parseCompression)
+	if err != nil {
+		slog.Warn("skill compression returned invalid JSON, keeping the persona as synthesized", "error", err)
+		return nil
+	}
+	applyCompression(persona.Synthesis, condensed, targets)
+
+	after := skillTokens(persona.Synthesis, a.skillFields)
+	for _, name := range slices.Sorted(maps.Keys(after)) {
+		if before[name] > a.skillTokens {
+			slog.Info("condensed skill", "skill", name, "tokens_before", before[name], "tokens_after", after[name])
+		}
+		if after[name] > a.skillTokens {
+			slog.Warn("skill is still over the token budget after compression", "skill", name, "tokens", after[name], "max_tokens", a.skillTokens)
+		}
+	}
+	return nil
+}
+
+// parseCompression reads the condensed fields, skipping anything that is
+// not a string.
+func parseCompression(raw string) (map[string]string, error) {
+	rawMap, err := decodeJSONObject(raw)
+	if err != nil {
+		return nil, err
+	}
+	condensed := make(map[string]string, len(rawMap))
+	for field, v := range rawMap {
+		var s string
+		if json.Unmarshal(v, &s) == nil {
+			condensed[field] = strings.TrimSpace(s)
+		}
+	}
+	return condensed, nil
+}
+
+// applyCompression replaces each targeted field with its condensed text.
+// A field the model left out, emptied, or lengthened is kept as it was.
+func applyCompression(s *SynthesisResult, condensed map[string]string, targets map[string]int) {
+	text := synthesisText(s)
+	fixes := make(map[string]string)
+	for f := range targets {
+		if c := condensed[f]; c != "" && len(c) < len(text[f]) {
+			fixes[f] = c
+		}
+	}
+	setSynthesisText(s, fixes)
+}
@@ -0,0 +1,69 @@
+package analyzer
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestCompressionTarget(t *testing.T) {
+	s := &SynthesisResult{
+		CodeStyleRules:    strings.Repeat("a", 3000), // 1000 tokens
+		TestingPhilosophy: strings.Repeat("b", 1500), // 500 tokens
+		ReviewVoice:       strings.Repeat("c", 600),  // 200 tokens
+	}
+	skills := map[string][]string{
+		"coding-style":  {"code_style_rules", "testing_philosophy", "code_examples"},
+		"code-reviewer": {"review_voice", "testing_philosophy"},
+	}
+
+	got := compressionTarget(s, skills, 750)
+	// Only coding-style (1500 tokens) is over, so its non-empty fields
+	// shrink by half and the reviewer's own field is left alone.
+	want := map[string]int{"code_style_rules": 1500, "testing_philosophy": 750}
+	if len(got) != len(want) || got["code_style_rules"] != want["code_style_rules"] || got["testing_philosophy"] != want["testing_philosophy"] {
+		t.Errorf("compressionTarget() = %v, want %v", got, want)
+	}
+	if got := compressionTarget(s, skills, 1500); len(got) != 0 {
+		t.Errorf("compressionTarget() = %v, want nothing when every skill fits", got)
+	}
+}
+
+func TestFitSkillBudget(t *testing.T) {
+	long := strings.Repeat("Wraps every error with context. ", 100)
+	p := &recordingProvider{reply: `{"code_style_rules": "Wraps errors with context.", "review_voice": "` + long + long + `"}`}
+	a := New(p)
+	a.SetSkillBudget(500, map[string][]string{"coding-style": {"code_style_rules"}, "code-reviewer": {"review_voice"}})
+	persona := &Persona{Username: "alice", Synthesis: &SynthesisResult{CodeStyleRules: long + long, ReviewVoice: "Terse."}}
+
+	if err := a.FitSkillBudget(context.Background(), persona); err != nil {
+		t.Fatal(err)
+	}
+	if len(p.prompts) != 1 || !strings.Contains(p.prompts[0], `"max_chars"`) || strings.Contains(p.prompts[0], "Terse.") {
+		t.Fatalf("want one compression prompt with only the over-budget field, got %q", p.prompts)
+	}
+	if persona.Synthesis.CodeStyleRules != "Wraps errors with context." {
+		t.Errorf("CodeStyleRules = %q, want the condensed text", persona.Synthesis.CodeStyleRules)
+	}
+	if persona.Synthesis.ReviewVoice != "Terse." {
+		t.Errorf("ReviewVoice = %q, want a field that was not targeted left alone", persona.Synthesis.ReviewVoice)
+	}
+
+	// Everything fits now, so a second call sends nothing.
+	if err := a.FitSkillBudget(context.Background(), persona); err != nil || len(p.prompts) != 1 {
+		t.Errorf("FitSkillBudget() = %v after %d prompts, want no new call", err, len(p.prompts))
+	}
+}
+
+func TestFitSkillBudgetFailureKeepsPersona(t *testing.T) {
+	a := New(&recordingProvider{reply: "I cannot shorten this."})
+	a.SetSkillBudget(500, map[string][]string{"coding-style": {"code_style_rules"}})
+	rules := strings.Repeat("x", 3000)
+	persona := &Persona{Synthesis: &SynthesisResult{CodeStyleRules: rules}}
+	if err := a.FitSkillBudget(context.Background(), persona); err != nil {
+		t.Fatalf("a failed compression should not fail the run: %v", err)
+	}
+	if persona.Synthesis.CodeStyleRules != rules {
+		t.Error("a failed compression should keep the persona as it was")
+	}
+}
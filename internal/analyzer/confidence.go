@@ -0,0 +1,55 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"log/slog"
+	"slices"
+	"strings"
+)
+
+// Confidence levels for synthesis fields.
+const (
+	ConfidenceHigh   = "high"
+	ConfidenceMedium = "medium"
+	ConfidenceLow    = "low"
+)
+
+// Confidence rates how well the crawled data backs one synthesis field.
+type Confidence struct {
+	Level     string `json:"level"`
+	Rationale string `json:"rationale"`
+}
+
+// parseConfidence reads the synthesis "confidence" object leniently. Entries
+// for unknown fields or with an unknown level are dropped, and a bare level
+// string is accepted in place of an object.
+func parseConfidence(raw json.RawMessage) map[string]Confidence {
+	var loose map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &loose); err != nil {
+		slog.Debug("ignoring malformed synthesis confidence", "error", err)
+		return nil
+	}
+	fields := synthesisFields()
+	result := make(map[string]Confidence, len(loose))
+	for field, v := range loose {
+		if !slices.Contains(fields, field) {
+			continue
+		}
+		var c Confidence
+		if err := json.Unmarshal(v, &c); err != nil {
+			if err := json.Unmarshal(v, &c.Level); err != nil {
+				continue
+			}
+		}
+		c.Level = strings.ToLower(strings.TrimSpace(c.Level))
+		c.Rationale = strings.TrimSpace(c.Rationale)
+		switch c.Level {
+		case ConfidenceHigh, ConfidenceMedium, ConfidenceLow:
+			result[field] = c
+		}
+	}
+	if len(result) == 0 {
+		return nil
+	}
+	return result
+}
@@ -0,0 +1,76 @@
+package analyzer
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/drpaneas/devlica/internal/llm"
+	"github.com/drpaneas/devlica/internal/prompts"
+	"golang.org/x/sync/errgroup"
+)
+
+const reconcilePrompt = `Two analysts independently analyzed the %s of developer %s from the same data.
+Merge their analyses into one.
+
+Requirements:
+- Keep every finding both analyses agree on, with the most concrete examples and URLs from either.
+- Keep a finding only one analysis makes if it cites concrete evidence; drop unsupported generalities.
+- Do not add findings that neither analysis makes.
+- Keep the structure and numbering of the questions the analyses answer.
+- Never settle a contradiction silently. End with a section headed "CONTRADICTIONS" that lists each
+  point where the analyses disagree, both claims, and the evidence each cites. Write "None." if they agree.
+
+ANALYSIS A:
+%s
+
+ANALYSIS B:
+%s`
+
+// SetEnsemble makes every analysis dimension also run on a second provider.
+// The two analyses are merged by a reconciliation prompt on the primary
+// provider, which keeps agreements and flags contradictions.
+func (a *Analyzer) SetEnsemble(p llm.Provider) {
+	a.ensemble = p
+}
+
+// analyze runs one analysis dimension. With an ensemble it runs the prompt
+// on both providers and reconciles the results. If only the second provider
+// fails, the primary analysis is used alone.
+func (a *Analyzer) analyze(ctx context.Context, dimension, username, name, def string, vars ...prompts.Var) (string, error) {
+	if a.ensemble == nil {
+		return a.complete(ctx, name, def, vars...)
+	}
+
+	var first, second string
+	var secondErr error
+	g, gCtx := errgroup.WithContext(ctx)
+	g.Go(func() error {
+		var err error
+		first, err = a.complete(gCtx, name, def, vars...)
+		return err
+	})
+	g.Go(func() error {
+		second, secondErr = a.completeWith(gCtx, a.ensemble, name, def, nil, vars...)
+		return nil
+	})
+	if err := g.Wait(); err != nil {
+		return "", err
+	}
+	if secondErr != nil {
+		slog.Warn("ensemble provider failed, using the primary analysis alone", "dimension", dimension, "error", secondErr)
+		return first, nil
+	}
+
+	slog.Info("reconciling ensemble analyses", "dimension", dimension)
+	merged, err := a.complete(ctx, "reconcile", reconcilePrompt,
+		prompts.Arg("Dimension", dimension),
+		prompts.Arg("Username", username),
+		prompts.Arg("First", first),
+		prompts.Arg("Second", second),
+	)
+	if err != nil {
+		return "", fmt.Errorf("reconciling %s analyses: %w", dimension, err)
+	}
+	return merged, nil
+}
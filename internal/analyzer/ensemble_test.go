@@ -0,0 +1,66 @@
+package analyzer
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/drpaneas/devlica/internal/llm"
+	"github.com/drpaneas/devlica/internal/prompts"
+)
+
+type failingProvider struct{}
+
+func (failingProvider) Complete(context.Context, string, string, *llm.CompleteOptions) (string, error) {
+	return "", errors.New("model unavailable")
+}
+
+func TestAnalyzeEnsembleReconciles(t *testing.T) {
+	primary := &recordingProvider{reply: "primary says tabs"}
+	second := &recordingProvider{reply: "second says spaces"}
+	a := New(primary)
+	a.SetEnsemble(second)
+
+	got, err := a.analyze(context.Background(), "code style", "alice", "code-style", "Analyze %s.", prompts.Arg("Username", "alice"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "primary says tabs" {
+		t.Errorf("analyze() = %q, want the reconciled reply from the primary provider", got)
+	}
+	if len(second.prompts) != 1 || second.prompts[0] != "Analyze alice." {
+		t.Errorf("second provider saw %q, want the analysis prompt once", second.prompts)
+	}
+	if len(primary.prompts) != 2 {
+		t.Fatalf("primary provider saw %d prompts, want analysis and reconciliation", len(primary.prompts))
+	}
+	reconcile := primary.prompts[1]
+	for _, want := range []string{"code style of developer alice", "ANALYSIS A:\nprimary says tabs", "ANALYSIS B:\nsecond says spaces", "CONTRADICTIONS"} {
+		if !strings.Contains(reconcile, want) {
+			t.Errorf("reconciliation prompt is missing %q:\n%s", want, reconcile)
+		}
+	}
+}
+
+func TestAnalyzeEnsembleFallsBackToPrimary(t *testing.T) {
+	primary := &recordingProvider{reply: "primary only"}
+	a := New(primary)
+	a.SetEnsemble(failingProvider{})
+
+	got, err := a.analyze(context.Background(), "code style", "alice", "code-style", "Analyze.")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "primary only" || len(primary.prompts) != 1 {
+		t.Errorf("analyze() = %q after %d primary calls, want the primary analysis without reconciliation", got, len(primary.prompts))
+	}
+}
+
+func TestAnalyzeEnsemblePrimaryFailure(t *testing.T) {
+	a := New(failingProvider{})
+	a.SetEnsemble(&recordingProvider{reply: "second"})
+	if _, err := a.analyze(context.Background(), "code style", "alice", "code-style", "Analyze."); err == nil {
+		t.Error("expected the primary provider's error")
+	}
+}
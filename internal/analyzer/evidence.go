@@ -0,0 +1,165 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/drpaneas/devlica/internal/ghcrawl"
+	"github.com/drpaneas/devlica/internal/textutil"
+)
+
+// maxEvidencePerField caps how many links back one persona claim.
+const maxEvidencePerField = 3
+
+// maxExampleChars caps the text quoted from one piece of evidence.
+const maxExampleChars = 600
+
+// Example is the crawled text an evidence link points at, quoted in the
+// skills so agents can learn from authentic samples.
+type Example struct {
+	// Kind says what the text is, such as "commit message" or "review
+	// comment".
+	Kind string `json:"kind"`
+	// Text is the quoted text, truncated to maxExampleChars.
+	Text string `json:"text"`
+	// Context says where the text was written, such as the PR and file
+	// a review comment is on. It may be empty.
+	Context string `json:"context,omitempty"`
+}
+
+// knownActivity collects every permalink the analyses were shown with the
+// text behind it, so evidence the model made up can be told apart from
+// evidence it copied, and the real evidence can be quoted.
+func knownActivity(data *ghcrawl.CrawlResult) map[string]Example {
+	known := make(map[string]Example)
+	add := func(url, kind, text, context string) {
+		if url != "" {
+			text = textutil.Truncate(strings.TrimSpace(text), maxExampleChars, "...")
+			known[url] = Example{Kind: kind, Text: text, Context: context}
+		}
+	}
+	for _, repo := range data.Repos {
+		for _, c := range repo.Commits {
+			add(commitURL(repo.FullName, c.SHA), "commit message", c.Message, repo.FullName)
+		}
+		for _, pr := range repo.PRs {
+			add(pr.URL, "pull request", pr.Title+"\n\n"+pr.Body, pr.Repo)
+		}
+		for _, r := range repo.Reviews {
+			add(r.URL, "review", r.Body, fmt.Sprintf("%s#%d (%s)", r.Repo, r.PRNumber, r.PRTitle))
+		}
+		for _, rc := range repo.ReviewComments {
+			add(rc.URL, "review comment", rc.Body, fmt.Sprintf("%s#%d (%s), %s", rc.Repo, rc.PRNumber, rc.PRTitle, rc.Path))
+		}
+		for _, cm := range repo.PRComments {
+			add(cm.URL, "pull request comment", cm.Body, cm.Repo)
+		}
+		for _, cm := range repo.CommitComments {
+			add(cm.URL, "commit comment", cm.Body, cm.Repo)
+		}
+		for _, rr := range repo.ReviewReplies {
+			add(rr.URL, "review reply", rr.Body, fmt.Sprintf("%s#%d (%s), replying to %s", rr.Repo, rr.PRNumber, rr.PRTitle, rr.ParentAuthor))
+		}
+	}
+	for _, cm := range data.IssueComments {
+		add(cm.URL, "issue comment", cm.Body, cm.Repo)
+	}
+	for _, pr := range data.ExternalPRs {
+		add(pr.URL, "pull request", pr.Title+"\n\n"+pr.Body, pr.Repo)
+	}
+	return known
+}
+
+// examplesFor returns the known text behind each evidence link.
+func examplesFor(evidence map[string][]string, known map[string]Example) map[string]Example {
+	examples := make(map[string]Example)
+	for _, urls := range evidence {
+		for _, url := range urls {
+			if ex, ok := known[url]; ok && ex.Text != "" {
+				examples[url] = ex
+			}
+		}
+	}
+	if len(examples) == 0 {
+		return nil
+	}
+	return examples
+}
+
+// groundEvidence drops links that do not point at crawled data, duplicates,
+// and fields the synthesis does not have, keeping at most
+// maxEvidencePerField links per field.
+func groundEvidence(evidence map[string][]string, known map[string]Example) map[string][]string {
+	fields := synthesisFields()
+	grounded := make(map[string][]string)
+	dropped := 0
+	for field, urls := range evidence {
+		if !slices.Contains(fields, field) {
+			dropped += len(urls)
+			continue
+		}
+		var kept []string
+		for _, url := range urls {
+			url = strings.TrimSpace(url)
+			if _, ok := known[url]; !ok || slices.Contains(kept, url) || len(kept) == maxEvidencePerField {
+				dropped++
+				continue
+			}
+			kept = append(kept, url)
+		}
+		if len(kept) > 0 {
+			grounded[field] = kept
+		}
+	}
+	if dropped > 0 {
+		slog.Debug("dropped evidence links that do not match crawled data", "count", dropped)
+	}
+	if len(grounded) == 0 {
+		return nil
+	}
+	return grounded
+}
+
+// synthesisFields returns the JSON names of the SynthesisResult text fields.
+func synthesisFields() []string {
+	t := reflect.TypeFor[SynthesisResult]()
+	var fields []string
+	for i := range t.NumField() {
+		if f := t.Field(i); f.Type.Kind() == reflect.String {
+			name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+			fields = append(fields, name)
+		}
+	}
+	return fields
+}
+
+// parseEvidence reads the synthesis "evidence" object leniently: a
+// malformed evidence map should cost the links, not the whole persona.
+func parseEvidence(raw json.RawMessage) map[string][]string {
+	var evidence map[string][]string
+	if err := json.Unmarshal(raw, &evidence); err == nil {
+		return evidence
+	}
+	var loose map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &loose); err != nil {
+		slog.Debug("ignoring malformed synthesis evidence", "error", err)
+		return nil
+	}
+	evidence = make(map[string][]string, len(loose))
+	for field, v := range loose {
+		var urls []string
+		if err := json.Unmarshal(v, &urls); err == nil {
+			evidence[field] = urls
+			continue
+		}
+		var url string
+		if err := json.Unmarshal(v, &url); err == nil {
+			evidence[field] = []string{url}
+		}
+	}
+	return evidence
+}
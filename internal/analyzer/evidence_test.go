@@ -0,0 +1,141 @@
+package analyzer
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/drpaneas/devlica/internal/ghcrawl"
+)
+
+func TestParseSynthesisEvidence(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  map[string][]string
+	}{
+		{
+			name:  "lists",
+			input: `{"coding_philosophy":"x","evidence":{"coding_philosophy":["u1","u2"]}}`,
+			want:  map[string][]string{"coding_philosophy": {"u1", "u2"}},
+		},
+		{
+			name:  "single url as string",
+			input: `{"coding_philosophy":"x","evidence":{"coding_philosophy":"u1","review_voice":42}}`,
+			want:  map[string][]string{"coding_philosophy": {"u1"}},
+		},
+		{
+			name:  "malformed evidence keeps the persona",
+			input: `{"coding_philosophy":"x","evidence":"see above"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseSynthesis(tt.input)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got.CodingPhilosophy != "x" {
+				t.Errorf("CodingPhilosophy = %q, want %q", got.CodingPhilosophy, "x")
+			}
+			if len(tt.want) == 0 && len(got.Evidence) == 0 {
+				return
+			}
+			if !reflect.DeepEqual(got.Evidence, tt.want) {
+				t.Errorf("Evidence = %v, want %v", got.Evidence, tt.want)
+			}
+		})
+	}
+}
+
+func TestGroundEvidence(t *testing.T) {
+	data := &ghcrawl.CrawlResult{
+		Repos: []ghcrawl.RepoData{{
+			FullName:       "alice/tool",
+			Commits:        []ghcrawl.CommitData{{SHA: "abc"}},
+			ReviewComments: []ghcrawl.ReviewComment{{URL: "https://github.com/alice/tool/pull/1#discussion_r1"}},
+		}},
+		IssueComments: []ghcrawl.Comment{{URL: "https://github.com/bob/lib/issues/2#issuecomment-3"}},
+	}
+	commit := "https://github.com/alice/tool/commit/abc"
+	review := "https://github.com/alice/tool/pull/1#discussion_r1"
+	issue := "https://github.com/bob/lib/issues/2#issuecomment-3"
+
+	got := groundEvidence(map[string][]string{
+		"code_style_rules":   {commit, "https://github.com/alice/tool/commit/made-up", commit},
+		"review_voice":       {review, issue, commit, review, " " + issue},
+		"not_a_field":        {commit},
+		"testing_philosophy": {"https://example.com"},
+	}, knownActivity(data))
+
+	want := map[string][]string{
+		"code_style_rules": {commit},
+		"review_voice":     {review, issue, commit},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("groundEvidence() = %v, want %v", got, want)
+	}
+
+	if got := groundEvidence(map[string][]string{"review_voice": {"https://example.com"}}, knownActivity(data)); got != nil {
+		t.Errorf("expected nil when nothing is grounded, got %v", got)
+	}
+}
+
+func TestExamplesFor(t *testing.T) {
+	data := &ghcrawl.CrawlResult{
+		Repos: []ghcrawl.RepoData{{
+			FullName: "alice/tool",
+			Commits:  []ghcrawl.CommitData{{SHA: "abc", Message: "fix: close the body on error\n\nLeaked on retries."}},
+			ReviewComments: []ghcrawl.ReviewComment{{
+				Repo: "alice/tool", PRNumber: 1, PRTitle: "Add retries", Path: "client.go",
+				URL: "https://github.com/alice/tool/pull/1#discussion_r1", Body: "  Wrap this error.  ",
+			}},
+		}},
+		IssueComments: []ghcrawl.Comment{{URL: "https://github.com/bob/lib/issues/2#issuecomment-3"}},
+	}
+	commit := "https://github.com/alice/tool/commit/abc"
+	review := "https://github.com/alice/tool/pull/1#discussion_r1"
+	issue := "https://github.com/bob/lib/issues/2#issuecomment-3"
+
+	got := examplesFor(map[string][]string{
+		"code_style_rules": {commit},
+		"review_voice":     {review, issue, "https://example.com"},
+	}, knownActivity(data))
+	want := map[string]Example{
+		commit: {Kind: "commit message", Text: "fix: close the body on error\n\nLeaked on retries.", Context: "alice/tool"},
+		review: {Kind: "review comment", Text: "Wrap this error.", Context: "alice/tool#1 (Add retries), client.go"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("examplesFor() = %v, want %v", got, want)
+	}
+	if got := examplesFor(map[string][]string{"review_voice": {issue}}, knownActivity(data)); got != nil {
+		t.Errorf("expected nil when no evidence has text, got %v", got)
+	}
+}
+
+func TestParseSynthesisConfidence(t *testing.T) {
+	input := `{"coding_philosophy":"x","confidence":{
+		"coding_philosophy":{"level":"High","rationale":" many commits "},
+		"review_voice":"low",
+		"testing_philosophy":{"level":"certain"},
+		"made_up":{"level":"high"}
+	}}`
+	got, err := ParseSynthesis(input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := map[string]Confidence{
+		"coding_philosophy": {Level: ConfidenceHigh, Rationale: "many commits"},
+		"review_voice":      {Level: ConfidenceLow},
+	}
+	if !reflect.DeepEqual(got.Confidence, want) {
+		t.Errorf("Confidence = %v, want %v", got.Confidence, want)
+	}
+
+	got, err = ParseSynthesis(`{"coding_philosophy":"x","confidence":["high"]}`)
+	if err != nil {
+		t.Fatalf("malformed confidence should not fail parsing: %v", err)
+	}
+	if got.Confidence != nil {
+		t.Errorf("Confidence = %v, want nil", got.Confidence)
+	}
+}
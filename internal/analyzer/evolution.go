@@ -0,0 +1,123 @@
+package analyzer
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/drpaneas/devlica/internal/ghcrawl"
+	"github.com/drpaneas/devlica/internal/stats"
+	"github.com/drpaneas/devlica/internal/textutil"
+)
+
+const (
+	// eraCommitSamples and eraCommentSamples bound how many examples of each
+	// kind represent one era, spread evenly across it.
+	eraCommitSamples  = 12
+	eraCommentSamples = 10
+)
+
+// era holds one calendar year of the developer's commits and comments.
+type era struct {
+	year     int
+	commits  []ghcrawl.CommitData
+	comments []eraComment
+}
+
+type eraComment struct {
+	kind string
+	repo string
+	body string
+	date time.Time
+}
+
+// splitEras groups commits and comments by calendar year, oldest first.
+func splitEras(data *ghcrawl.CrawlResult) []era {
+	byYear := make(map[int]*era)
+	get := func(t time.Time) *era {
+		y := t.Year()
+		if byYear[y] == nil {
+			byYear[y] = &era{year: y}
+		}
+		return byYear[y]
+	}
+	addComment := func(kind, repo, body string, date time.Time) {
+		if date.IsZero() || strings.TrimSpace(body) == "" {
+			return
+		}
+		e := get(date)
+		e.comments = append(e.comments, eraComment{kind: kind, repo: repo, body: body, date: date})
+	}
+	for _, repo := range data.Repos {
+		for _, cm := range repo.Commits {
+			if !cm.Date.IsZero() {
+				e := get(cm.Date)
+				e.commits = append(e.commits, cm)
+			}
+		}
+		for _, rc := range repo.ReviewComments {
+			addComment("review comment", repo.FullName, rc.Body, rc.Date)
+		}
+		for _, cm := range repo.PRComments {
+			addComment("PR comment", repo.FullName, cm.Body, cm.Date)
+		}
+		for _, cm := range repo.CommitComments {
+			addComment("commit comment", repo.FullName, cm.Body, cm.Date)
+		}
+	}
+	for _, cm := range data.IssueComments {
+		addComment("issue comment", cm.Repo, cm.Body, cm.Date)
+	}
+
+	eras := make([]era, 0, len(byYear))
+	for _, e := range byYear {
+		slices.SortFunc(e.commits, func(a, b ghcrawl.CommitData) int { return a.Date.Compare(b.Date) })
+		slices.SortFunc(e.comments, func(a, b eraComment) int { return a.date.Compare(b.date) })
+		eras = append(eras, *e)
+	}
+	slices.SortFunc(eras, func(a, b era) int { return a.year - b.year })
+	return eras
+}
+
+// buildErasText renders each year's commit message statistics and an even
+// sample of commits and comments, so the LLM can compare eras. It returns ""
+// when the data covers fewer than two years, since there is nothing to
+// compare.
+func buildErasText(data *ghcrawl.CrawlResult) string {
+	eras := splitEras(data)
+	if len(eras) < 2 {
+		return ""
+	}
+	var b strings.Builder
+	for _, e := range eras {
+		fmt.Fprintf(&b, "=== %d: %d commits, %d comments ===\n", e.year, len(e.commits), len(e.comments))
+		yearData := &ghcrawl.CrawlResult{Repos: []ghcrawl.RepoData{{Commits: e.commits}}}
+		if s := stats.CommitMessages(yearData).Format(); s != "" {
+			b.WriteString(s)
+		}
+		for _, cm := range spreadSample(e.commits, eraCommitSamples) {
+			fmt.Fprintf(&b, "\nCommit %s:\n%s\n", cm.Date.Format("2006-01-02"), strings.TrimSpace(cm.Message))
+			if cm.Patch != "" {
+				fmt.Fprintf(&b, "%s\n", textutil.Truncate(cm.Patch, 600, "\n..."))
+			}
+		}
+		for _, cm := range spreadSample(e.comments, eraCommentSamples) {
+			fmt.Fprintf(&b, "\n%s on %s (%s):\n%s\n", cm.kind, cm.repo, cm.date.Format("2006-01-02"), textutil.Truncate(cm.body, 400, "..."))
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// spreadSample picks k items evenly spaced across items, keeping order.
+func spreadSample[T any](items []T, k int) []T {
+	if len(items) <= k {
+		return items
+	}
+	out := make([]T, 0, k)
+	for i := range k {
+		out = append(out, items[i*len(items)/k])
+	}
+	return out
+}
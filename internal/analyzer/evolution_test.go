@@ -0,0 +1,55 @@
+package analyzer
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/drpaneas/devlica/internal/ghcrawl"
+)
+
+func TestBuildErasText(t *testing.T) {
+	day := func(y int) time.Time { return time.Date(y, 6, 1, 0, 0, 0, 0, time.UTC) }
+	oneYear := &ghcrawl.CrawlResult{Repos: []ghcrawl.RepoData{{
+		Commits: []ghcrawl.CommitData{{Message: "Fix bug", Date: day(2024)}},
+	}}}
+	if got := buildErasText(oneYear); got != "" {
+		t.Errorf("a single era should produce no text, got %q", got)
+	}
+
+	data := &ghcrawl.CrawlResult{
+		Repos: []ghcrawl.RepoData{{
+			FullName: "alice/tool",
+			Commits: []ghcrawl.CommitData{
+				{Message: "feat: add cache", Date: day(2024)},
+				{Message: "updated stuff", Date: day(2019)},
+			},
+			ReviewComments: []ghcrawl.ReviewComment{{Body: "Please add a test.", Date: day(2024)}},
+		}},
+		IssueComments: []ghcrawl.Comment{{Repo: "bob/lib", Body: "+1", Date: day(2019)}},
+	}
+	got := buildErasText(data)
+	old, recent := strings.Index(got, "=== 2019: 1 commits, 1 comments ==="), strings.Index(got, "=== 2024: 1 commits, 1 comments ===")
+	if old < 0 || recent < 0 || old > recent {
+		t.Fatalf("expected 2019 then 2024 eras, got %q", got)
+	}
+	for _, want := range []string{"updated stuff", "Conventional commits: 100%", "review comment on alice/tool (2024-06-01)", "issue comment on bob/lib"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q in %q", want, got)
+		}
+	}
+}
+
+func TestSpreadSample(t *testing.T) {
+	items := []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}
+	got := spreadSample(items, 3)
+	want := []int{0, 3, 6}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("spreadSample() = %v, want %v", got, want)
+		}
+	}
+	if got := spreadSample(items[:2], 3); len(got) != 2 {
+		t.Errorf("short input should be returned whole, got %v", got)
+	}
+}
@@ -0,0 +1,198 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/drpaneas/devlica/internal/ghcrawl"
+	"github.com/drpaneas/devlica/internal/stats"
+)
+
+// PersonaSchemaVersion identifies the layout of PersonaDocument. It is
+// bumped when a field is renamed, removed, or changes meaning; new fields
+// are added without a bump, so consumers should ignore unknown fields.
+const PersonaSchemaVersion = 1
+
+// PersonaDocument is the JSON form of a Persona, for tools that consume the
+// analysis without parsing skill files.
+type PersonaDocument struct {
+	SchemaVersion  int                      `json:"schema_version"`
+	Metadata       PersonaMetadata          `json:"metadata"`
+	Analyses       PersonaAnalyses          `json:"analyses"`
+	LanguageStyles []LanguageStyle          `json:"language_styles"`
+	Synthesis      *SynthesisResult         `json:"synthesis"`
+	Metrics        stats.Metrics            `json:"metrics"`
+	CommitStats    stats.CommitMessageStats `json:"commit_stats"`
+	PRStats        stats.PRBodyStats        `json:"pr_stats"`
+	Suggestions    stats.SuggestionStats    `json:"suggestions"`
+	Phrases        stats.PhraseStats        `json:"phrases"`
+	Usage          DataUsage                `json:"data_usage"`
+	Sparse         []string                 `json:"sparse,omitempty"`
+}
+
+// PersonaMetadata records how and from what a persona was produced.
+type PersonaMetadata struct {
+	Username         string     `json:"username"`
+	Provider         string     `json:"provider"`
+	Model            string     `json:"model"`
+	EnsembleProvider string     `json:"ensemble_provider,omitempty"`
+	EnsembleModel    string     `json:"ensemble_model,omitempty"`
+	CrawledAt        time.Time  `json:"crawled_at"`
+	GeneratedAt      time.Time  `json:"generated_at"`
+	Anonymized       bool       `json:"anonymized"`
+	DataCounts       DataCounts `json:"data_counts"`
+	// CrawlHash is ghcrawl.Hash of the crawl as collected, before
+	// anonymization and benchmark hold-outs, so personas built from
+	// different crawls can be told apart.
+	CrawlHash string `json:"crawl_hash,omitempty"`
+	// DevlicaVersion is the devlica build that produced the persona.
+	DevlicaVersion string `json:"devlica_version,omitempty"`
+	// JudgeProvider and JudgeModel scored and refined the persona in the
+	// benchmark, when that was not the primary model.
+	JudgeProvider string `json:"judge_provider,omitempty"`
+	JudgeModel    string `json:"judge_model,omitempty"`
+	// BenchmarkScore is the final benchmark score out of 100, or nil when
+	// the persona was not benchmarked.
+	BenchmarkScore *float64 `json:"benchmark_score,omitempty"`
+}
+
+// DataCounts is how much of each kind of activity the crawl collected.
+type DataCounts struct {
+	Repos          int `json:"repos"`
+	Commits        int `json:"commits"`
+	Reviews        int `json:"reviews"`
+	IssueComments  int `json:"issue_comments"`
+	AuthoredIssues int `json:"authored_issues"`
+	ExternalPRs    int `json:"external_prs"`
+	StarredRepos   int `json:"starred_repos"`
+	Gists          int `json:"gists"`
+	Releases       int `json:"releases"`
+	Docs           int `json:"docs"`
+	TriagedIssues  int `json:"triaged_issues"`
+	Events         int `json:"events"`
+	Discussions    int `json:"discussions"`
+	Projects       int `json:"projects"`
+}
+
+// CountData tallies the crawl. Call it before reviews are held out for the
+// benchmark to count everything that was collected.
+func CountData(data *ghcrawl.CrawlResult) DataCounts {
+	return DataCounts{
+		Repos:          data.TotalRepos(),
+		Commits:        data.TotalCommits(),
+		Reviews:        data.TotalReviews(),
+		IssueComments:  len(data.IssueComments),
+		AuthoredIssues: data.TotalIssues(),
+		ExternalPRs:    data.TotalExternalPRs(),
+		StarredRepos:   data.TotalStarred(),
+		Gists:          data.TotalGists(),
+		Releases:       data.TotalReleases(),
+		Docs:           data.TotalDocs(),
+		TriagedIssues:  data.TotalTriage(),
+		Events:         len(data.Events),
+		Discussions:    data.TotalDiscussions(),
+		Projects:       data.TotalProjects(),
+	}
+}
+
+// PersonaAnalyses holds the raw output of each analysis dimension.
+type PersonaAnalyses struct {
+	CodeStyle         string `json:"code_style"`
+	CommitMessages    string `json:"commit_messages"`
+	ReviewStyle       string `json:"review_style"`
+	Communication     string `json:"communication"`
+	DeveloperIdentity string `json:"developer_identity"`
+	StyleEvolution    string `json:"style_evolution"`
+	Automation        string `json:"automation"`
+}
+
+// NewPersonaDocument wraps p and meta in the current schema version.
+func NewPersonaDocument(p *Persona, meta PersonaMetadata) PersonaDocument {
+	return PersonaDocument{
+		SchemaVersion: PersonaSchemaVersion,
+		Metadata:      meta,
+		Analyses: PersonaAnalyses{
+			CodeStyle:         p.CodeStyle,
+			CommitMessages:    p.CommitMessages,
+			ReviewStyle:       p.ReviewStyle,
+			Communication:     p.Communication,
+			DeveloperIdentity: p.DeveloperIdentity,
+			StyleEvolution:    p.StyleEvolution,
+			Automation:        p.Automation,
+		},
+		LanguageStyles: p.LanguageStyles,
+		Synthesis:      p.Synthesis,
+		Metrics:        p.Metrics,
+		CommitStats:    p.CommitStats,
+		PRStats:        p.PRStats,
+		Suggestions:    p.Suggestions,
+		Phrases:        p.Phrases,
+		Usage:          p.Usage,
+		Sparse:         p.Sparse,
+	}
+}
+
+// WritePersona writes doc to path as indented JSON, creating parent
+// directories as needed.
+func WritePersona(path string, doc PersonaDocument) error {
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding persona: %w", err)
+	}
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return fmt.Errorf("creating persona directory: %w", err)
+		}
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("writing persona: %w", err)
+	}
+	return nil
+}
+
+// ReadPersona reads a document written by WritePersona. Documents from a
+// newer schema version are rejected rather than half understood.
+func ReadPersona(path string) (PersonaDocument, error) {
+	var doc PersonaDocument
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return doc, fmt.Errorf("reading persona: %w", err)
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return doc, fmt.Errorf("decoding persona %s: %w", path, err)
+	}
+	switch {
+	case doc.SchemaVersion == 0:
+		return doc, fmt.Errorf("persona %s has no schema_version", path)
+	case doc.SchemaVersion > PersonaSchemaVersion:
+		return doc, fmt.Errorf("persona %s uses schema version %d, this build reads up to %d", path, doc.SchemaVersion, PersonaSchemaVersion)
+	}
+	return doc, nil
+}
+
+// Persona rebuilds the Persona the document was made from.
+func (d PersonaDocument) Persona() *Persona {
+	return &Persona{
+		Username:          d.Metadata.Username,
+		CodeStyle:         d.Analyses.CodeStyle,
+		CommitMessages:    d.Analyses.CommitMessages,
+		ReviewStyle:       d.Analyses.ReviewStyle,
+		Communication:     d.Analyses.Communication,
+		DeveloperIdentity: d.Analyses.DeveloperIdentity,
+		StyleEvolution:    d.Analyses.StyleEvolution,
+		Automation:        d.Analyses.Automation,
+		LanguageStyles:    d.LanguageStyles,
+		Synthesis:         d.Synthesis,
+		Suggestions:       d.Suggestions,
+		Phrases:           d.Phrases,
+		Metrics:           d.Metrics,
+		CommitStats:       d.CommitStats,
+		PRStats:           d.PRStats,
+		Usage:             d.Usage,
+		Sparse:            d.Sparse,
+		Metadata:          d.Metadata,
+	}
+}
@@ -0,0 +1,132 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/drpaneas/devlica/internal/ghcrawl"
+	"github.com/drpaneas/devlica/internal/stats"
+)
+
+func TestWritePersona(t *testing.T) {
+	persona := &Persona{
+		Username:       "alice",
+		CodeStyle:      "Short functions.",
+		Automation:     "make lint before every push.",
+		LanguageStyles: []LanguageStyle{{Language: "Go", Rules: "- Wrap errors."}},
+		Synthesis: &SynthesisResult{
+			CodingPhilosophy: "Simplicity first.",
+			Confidence:       map[string]Confidence{"coding_philosophy": {Level: "high", Rationale: "Many commits."}},
+		},
+		Metrics:     stats.Metrics{ReviewComments: 12},
+		CommitStats: stats.CommitMessageStats{Total: 40, ConventionalTypes: map[string]int{"fix": 3}},
+	}
+	meta := PersonaMetadata{
+		Username:  "alice",
+		Provider:  "openai",
+		Model:     "gpt-4o",
+		CrawledAt: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		DataCounts: DataCounts{
+			Repos:   2,
+			Commits: 40,
+		},
+	}
+	path := filepath.Join(t.TempDir(), "out", "persona.json")
+	if err := WritePersona(path, NewPersonaDocument(persona, meta)); err != nil {
+		t.Fatal(err)
+	}
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		SchemaVersion int `json:"schema_version"`
+		Metadata      struct {
+			Model      string         `json:"model"`
+			CrawledAt  string         `json:"crawled_at"`
+			DataCounts map[string]int `json:"data_counts"`
+		} `json:"metadata"`
+		Analyses       map[string]string   `json:"analyses"`
+		LanguageStyles []map[string]string `json:"language_styles"`
+		Synthesis      map[string]any      `json:"synthesis"`
+		Metrics        map[string]any      `json:"metrics"`
+		CommitStats    map[string]any      `json:"commit_stats"`
+	}
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		t.Fatalf("persona is not valid JSON: %v", err)
+	}
+	if doc.SchemaVersion != PersonaSchemaVersion {
+		t.Errorf("schema_version = %d, want %d", doc.SchemaVersion, PersonaSchemaVersion)
+	}
+	if doc.Metadata.Model != "gpt-4o" || doc.Metadata.CrawledAt != "2026-01-02T03:04:05Z" || doc.Metadata.DataCounts["commits"] != 40 {
+		t.Errorf("metadata = %+v", doc.Metadata)
+	}
+	if doc.Analyses["code_style"] != "Short functions." || doc.Analyses["automation"] != "make lint before every push." {
+		t.Errorf("analyses = %v", doc.Analyses)
+	}
+	if len(doc.LanguageStyles) != 1 || doc.LanguageStyles[0]["language"] != "Go" {
+		t.Errorf("language_styles = %v", doc.LanguageStyles)
+	}
+	if doc.Synthesis["coding_philosophy"] != "Simplicity first." || doc.Synthesis["confidence"] == nil {
+		t.Errorf("synthesis = %v", doc.Synthesis)
+	}
+	if doc.Metrics["review_comments"] != float64(12) {
+		t.Errorf("metrics = %v", doc.Metrics)
+	}
+	if doc.CommitStats["total"] != float64(40) || doc.CommitStats["conventional_types"] == nil {
+		t.Errorf("commit_stats = %v", doc.CommitStats)
+	}
+}
+
+func TestCountData(t *testing.T) {
+	data := &ghcrawl.CrawlResult{
+		Repos: []ghcrawl.RepoData{{
+			Commits:        []ghcrawl.CommitData{{SHA: "a"}, {SHA: "b"}},
+			ReviewComments: []ghcrawl.ReviewComment{{Body: "nit"}},
+		}},
+		IssueComments: []ghcrawl.Comment{{Body: "thanks"}},
+	}
+	got := CountData(data)
+	want := DataCounts{Repos: 1, Commits: 2, Reviews: 1, IssueComments: 1}
+	if got != want {
+		t.Errorf("CountData() = %+v, want %+v", got, want)
+	}
+}
+
+func TestReadPersona(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "persona.json")
+	persona := &Persona{
+		ReviewStyle: "Asks for tests.",
+		Synthesis:   &SynthesisResult{ReviewVoice: "Direct."},
+	}
+	if err := WritePersona(path, NewPersonaDocument(persona, PersonaMetadata{Username: "alice"})); err != nil {
+		t.Fatal(err)
+	}
+	doc, err := ReadPersona(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := doc.Persona()
+	if got.Username != "alice" || got.ReviewStyle != "Asks for tests." || got.Synthesis.ReviewVoice != "Direct." {
+		t.Errorf("round trip = %+v", got)
+	}
+
+	for name, content := range map[string]string{
+		"unversioned": `{"metadata": {"username": "alice"}}`,
+		"newer":       `{"schema_version": 99}`,
+		"invalid":     `{`,
+	} {
+		p := filepath.Join(dir, name+".json")
+		if err := os.WriteFile(p, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := ReadPersona(p); err == nil {
+			t.Errorf("ReadPersona(%s) succeeded, want an error", name)
+		}
+	}
+}
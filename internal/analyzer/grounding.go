@@ -0,0 +1,197 @@
+package analyzer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/drpaneas/devlica/internal/ghcrawl"
+	"github.com/drpaneas/devlica/internal/prompts"
+)
+
+const groundingPrompt = `You are fact-checking a developer persona that another model synthesized from analyses of the developer's GitHub activity. An AI agent will impersonate the developer from it, so every statement it makes must be backed by the data.
+
+Developer: %s
+
+PERSONA (JSON, one field per section of the generated skills):
+%s
+
+ANALYSES THE PERSONA WAS SYNTHESIZED FROM:
+%s
+
+RAW ACTIVITY (their actual commits, reviews, comments, and code):
+%s
+
+HARD METRICS (computed directly from their activity, not by an LLM):
+%s
+
+Check every statement in every persona field against the raw activity, the analyses, and the metrics. A statement is unsupported when nothing above backs it, when the data contradicts it, or when it presents a phrasing or example as theirs that appears nowhere above. General advice that follows from supported statements is fine; claims about this developer's habits, preferences, or history are not unless the data shows them.
+
+Respond with a single JSON object (no markdown, no commentary) that contains only the fields with unsupported statements:
+
+{
+  "<field name>": {
+    "unsupported": ["each unsupported statement, quoted from the field"],
+    "revised": "The field rewritten without the unsupported statements and otherwise unchanged. Use an empty string if nothing supported remains."
+  }
+}
+
+Respond with {} when every statement is supported.`
+
+// SetGrounding makes the analyzer cross-check the synthesized persona
+// against the data it came from and drop statements nothing supports.
+func (a *Analyzer) SetGrounding(enabled bool) {
+	a.grounding = enabled
+}
+
+// groundingFix is the grounding check's verdict on one synthesis field.
+type groundingFix struct {
+	Unsupported []string `json:"unsupported"`
+	Revised     string   `json:"revised"`
+}
+
+// ground runs the grounding check on persona.Synthesis. A failed check is
+// logged and leaves the persona as it was: it is a refinement, and the
+// synthesis it would refine is already paid for.
+func (a *Analyzer) ground(ctx context.Context, persona *Persona, usage *usageLog, data *ghcrawl.CrawlResult) error {
+	fields, err := json.MarshalIndent(synthesisText(persona.Synthesis), "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding persona for grounding check: %w", err)
+	}
+	metricsText := persona.Metrics.Format()
+	if metricsText == "" {
+		metricsText = "(no metrics available)"
+	}
+	analyses := strings.Join([]string{
+		"CODE STYLE:\n" + persona.CodeStyle,
+		"COMMIT MESSAGES:\n" + persona.CommitMessages,
+		"REVIEW STYLE:\n" + persona.ReviewStyle,
+		"COMMUNICATION:\n" + persona.Communication,
+		"DEVELOPER IDENTITY:\n" + persona.DeveloperIdentity,
+		"STYLE EVOLUTION:\n" + persona.StyleEvolution,
+		"AUTOMATION:\n" + persona.Automation,
+	}, "\n\n")
+	activity := strings.Join(slices.DeleteFunc([]string{
+		buildReviewDataText(data),
+		buildCommitMessagesText(data),
+		buildPRDescriptionsText(data),
+		buildIssueCommentsText(data),
+		buildCodeSamplesText(data),
+	}, func(s string) bool { return s == "" }), "\n\n")
+	prepared, err := a.prepare(ctx, usage, "grounding", len(groundingPrompt)+len(fields)+len(metricsText),
+		source{"analyses", analyses},
+		source{"raw activity", activity},
+	)
+	if err != nil {
+		return err
+	}
+	persona.Usage = usage.usage()
+
+	slog.Info("checking persona against the data")
+	raw, err := a.completeJSON(ctx, "grounding", groundingPrompt, groundingSchema,
+		prompts.Arg("Username", persona.Username),
+		prompts.Arg("Persona", string(fields)),
+		prompts.Arg("Analyses", prepared[0]),
+		prompts.Arg("Activity", prepared[1]),
+		prompts.Arg("Metrics", metricsText),
+	)
+	if err != nil {
+		slog.Warn("grounding check failed, keeping the persona unchecked", "error", err)
+		return nil
+	}
+	a.saveArtifact("grounding", raw)
+	fixes, err := parseRepairing(ctx, a, "grounding", raw, groundingSchema, parseGrounding)
+	if err != nil {
+		slog.Warn("grounding check returned invalid JSON, keeping the persona unchecked", "error", err)
+		return nil
+	}
+	applyGrounding(persona.Synthesis, fixes)
+	return nil
+}
+
+// parseGrounding reads the grounding verdicts, skipping unknown fields and
+// fields without unsupported statements.
+func parseGrounding(raw string) (map[string]groundingFix, error) {
+	rawMap, err := decodeJSONObject(raw)
+	if err != nil {
+		return nil, err
+	}
+	known := synthesisFields()
+	fixes := make(map[string]groundingFix)
+	for field, v := range rawMap {
+		var fix groundingFix
+		if !slices.Contains(known, field) || json.Unmarshal(v, &fix) != nil {
+			continue
+		}
+		fix.Unsupported = slices.DeleteFunc(fix.Unsupported, func(s string) bool { return strings.TrimSpace(s) == "" })
+		if len(fix.Unsupported) > 0 {
+			fixes[field] = fix
+		}
+	}
+	return fixes, nil
+}
+
+// applyGrounding replaces each flagged field with its revision, lowers its
+// confidence one level, and records what was removed in s.Unsupported.
+func applyGrounding(s *SynthesisResult, fixes map[string]groundingFix) {
+	v := reflect.ValueOf(s).Elem()
+	for i := range v.NumField() {
+		name, _, _ := strings.Cut(v.Type().Field(i).Tag.Get("json"), ",")
+		fix, ok := fixes[name]
+		if !ok || v.Field(i).Kind() != reflect.String {
+			continue
+		}
+		v.Field(i).SetString(strings.TrimSpace(fix.Revised))
+
+		c, rated := s.Confidence[name]
+		switch {
+		case !rated:
+			c.Level = ConfidenceLow
+		case c.Level == ConfidenceHigh:
+			c.Level = ConfidenceMedium
+		default:
+			c.Level = ConfidenceLow
+		}
+		note := fmt.Sprintf("The grounding check removed %d unsupported statement(s).", len(fix.Unsupported))
+		c.Rationale = strings.TrimSpace(c.Rationale + " " + note)
+		if s.Confidence == nil {
+			s.Confidence = make(map[string]Confidence)
+		}
+		s.Confidence[name] = c
+
+		if s.Unsupported == nil {
+			s.Unsupported = make(map[string][]string)
+		}
+		s.Unsupported[name] = fix.Unsupported
+		slog.Info("removed unsupported statements", "field", name, "count", len(fix.Unsupported))
+	}
+}
+
+// synthesisText returns the synthesis prose fields by JSON name.
+func synthesisText(s *SynthesisResult) map[string]string {
+	v := reflect.ValueOf(s).Elem()
+	fields := make(map[string]string)
+	for i := range v.NumField() {
+		if v.Field(i).Kind() != reflect.String {
+			continue
+		}
+		name, _, _ := strings.Cut(v.Type().Field(i).Tag.Get("json"), ",")
+		fields[name] = v.Field(i).String()
+	}
+	return fields
+}
+
+// setSynthesisText sets the synthesis prose fields named in fields.
+func setSynthesisText(s *SynthesisResult, fields map[string]string) {
+	v := reflect.ValueOf(s).Elem()
+	for i := range v.NumField() {
+		name, _, _ := strings.Cut(v.Type().Field(i).Tag.Get("json"), ",")
+		if text, ok := fields[name]; ok && v.Field(i).Kind() == reflect.String {
+			v.Field(i).SetString(text)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package analyzer
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/drpaneas/devlica/internal/ghcrawl"
+	"github.com/drpaneas/devlica/internal/llm"
+)
+
+// groundingProvider answers the grounding prompt with verdict and every
+// other prompt with reply.
+type groundingProvider struct {
+	reply, verdict string
+}
+
+func (p *groundingProvider) Complete(_ context.Context, _, prompt string, _ *llm.CompleteOptions) (string, error) {
+	if strings.Contains(prompt, "fact-checking a developer persona") {
+		return p.verdict, nil
+	}
+	return p.reply, nil
+}
+
+func TestGroundingDropsUnsupportedStatements(t *testing.T) {
+	p := &groundingProvider{
+		reply: `{"review_voice": "Terse. Always signs off with 'cheers'.", "testing_philosophy": "Table-driven tests.",
+			"confidence": {"review_voice": {"level": "high", "rationale": "Many reviews."}}}`,
+		verdict: "```json\n" + `{
+			"review_voice": {"unsupported": ["Always signs off with 'cheers'."], "revised": "Terse."},
+			"testing_philosophy": {"unsupported": []},
+			"made_up_field": {"unsupported": ["x"], "revised": "y"}
+		}` + "\n```",
+	}
+	a := New(p)
+	a.SetGrounding(true)
+	// Enough issue comments that the profile is not sparse, which would cap
+	// the confidence before the grounding check lowers it.
+	data := promptFixture()
+	for range sparseComments {
+		data.IssueComments = append(data.IssueComments, ghcrawl.Comment{Repo: "alice/tool", Body: "Fixed in the next release."})
+	}
+
+	persona, err := a.Analyze(context.Background(), "alice", data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := persona.Synthesis
+	if s.ReviewVoice != "Terse." {
+		t.Errorf("ReviewVoice = %q, want the revision", s.ReviewVoice)
+	}
+	if s.TestingPhilosophy != "Table-driven tests." {
+		t.Errorf("TestingPhilosophy = %q, want it untouched", s.TestingPhilosophy)
+	}
+	if c := s.Confidence["review_voice"]; c.Level != ConfidenceMedium || !strings.Contains(c.Rationale, "removed 1 unsupported") {
+		t.Errorf("review_voice confidence = %+v, want it downgraded to medium with a note", c)
+	}
+	if len(s.Unsupported) != 1 || s.Unsupported["review_voice"][0] != "Always signs off with 'cheers'." {
+		t.Errorf("Unsupported = %v, want the removed review_voice statement", s.Unsupported)
+	}
+}
+
+func TestGroundingFailureKeepsPersona(t *testing.T) {
+	a := New(&groundingProvider{reply: `{"review_voice": "Terse."}`, verdict: "I could not check this."})
+	a.SetGrounding(true)
+
+	persona, err := a.Analyze(context.Background(), "alice", promptFixture())
+	if err != nil {
+		t.Fatalf("a failed grounding check should not fail the run: %v", err)
+	}
+	if persona.Synthesis.ReviewVoice != "Terse." || persona.Synthesis.Unsupported != nil {
+		t.Errorf("synthesis = %+v, want it unchanged", persona.Synthesis)
+	}
+}
+
+func TestApplyGroundingRatesUnratedFieldLow(t *testing.T) {
+	s := &SynthesisResult{CodeExamples: "invented snippet"}
+	applyGrounding(s, map[string]groundingFix{"code_examples": {Unsupported: []string{"invented snippet"}}})
+	if s.CodeExamples != "" || s.Confidence["code_examples"].Level != ConfidenceLow {
+		t.Errorf("got %q rated %+v, want an empty field rated low", s.CodeExamples, s.Confidence["code_examples"])
+	}
+}
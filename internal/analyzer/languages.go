@@ -0,0 +1,178 @@
+package analyzer
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/drpaneas/devlica/internal/ghcrawl"
+)
+
+const (
+	// maxLanguageProfiles caps how many languages get their own analysis.
+	maxLanguageProfiles = 4
+	// minLanguageItems is how many samples and diff excerpts a language needs
+	// before a dedicated analysis is worth an LLM call.
+	minLanguageItems = 3
+)
+
+// LanguageStyle holds the style rules specific to one programming language.
+type LanguageStyle struct {
+	Language string `json:"language"`
+	Rules    string `json:"rules"`
+}
+
+var languageByExt = map[string]string{
+	".go":    "Go",
+	".py":    "Python",
+	".ts":    "TypeScript",
+	".tsx":   "TypeScript",
+	".js":    "JavaScript",
+	".jsx":   "JavaScript",
+	".mjs":   "JavaScript",
+	".rs":    "Rust",
+	".java":  "Java",
+	".kt":    "Kotlin",
+	".rb":    "Ruby",
+	".c":     "C",
+	".h":     "C",
+	".cc":    "C++",
+	".cpp":   "C++",
+	".hpp":   "C++",
+	".cs":    "C#",
+	".swift": "Swift",
+	".php":   "PHP",
+	".scala": "Scala",
+	".ex":    "Elixir",
+	".exs":   "Elixir",
+	".hs":    "Haskell",
+	".lua":   "Lua",
+	".sh":    "Shell",
+	".bash":  "Shell",
+	".zig":   "Zig",
+}
+
+// LanguageExtensions returns the file extensions of language, such as
+// ".ts" and ".tsx" for "TypeScript", sorted; none for a language devlica
+// does not recognize.
+func LanguageExtensions(language string) []string {
+	var exts []string
+	for ext, lang := range languageByExt {
+		if strings.EqualFold(lang, language) {
+			exts = append(exts, ext)
+		}
+	}
+	slices.Sort(exts)
+	return exts
+}
+
+// languageOf returns the programming language of a file, or "" for
+// configuration, documentation, and unknown files.
+func languageOf(p string) string {
+	return languageByExt[strings.ToLower(path.Ext(p))]
+}
+
+// filePatch is the part of a commit patch that touches one file.
+type filePatch struct {
+	path  string
+	patch string
+}
+
+// splitPatchByFile splits a commit patch on the "--- path ---" headers the
+// crawler writes before each file.
+func splitPatchByFile(patch string) []filePatch {
+	var files []filePatch
+	for _, line := range strings.SplitAfter(patch, "\n") {
+		trimmed := strings.TrimRight(line, "\n")
+		if strings.HasPrefix(trimmed, "--- ") && strings.HasSuffix(trimmed, " ---") && len(trimmed) > 8 {
+			files = append(files, filePatch{path: trimmed[4 : len(trimmed)-4]})
+			continue
+		}
+		if len(files) > 0 {
+			files[len(files)-1].patch += line
+		}
+	}
+	return files
+}
+
+// languageCode is the code evidence for one language.
+type languageCode struct {
+	language string
+	samples  []string
+	diffs    []string
+}
+
+func (lc languageCode) items() int { return len(lc.samples) + len(lc.diffs) }
+
+// text renders the evidence with diffs first, since they are the code the
+// developer certainly wrote.
+func (lc languageCode) text() string {
+	var b strings.Builder
+	b.WriteString("COMMIT DIFFS:\n")
+	for _, d := range lc.diffs {
+		b.WriteString(d)
+	}
+	b.WriteString("\nCODE SAMPLES:\n")
+	for _, s := range lc.samples {
+		b.WriteString(s)
+	}
+	return b.String()
+}
+
+// groupCodeByLanguage sorts code samples and per-file commit diffs by
+// language. It returns the languages with at least minLanguageItems pieces
+// of evidence, most evidence first, capped at maxLanguageProfiles, and only
+// when at least two languages qualify: a single-language developer is fully
+// covered by the main code style analysis.
+func groupCodeByLanguage(data *ghcrawl.CrawlResult) []languageCode {
+	byLang := make(map[string]*languageCode)
+	get := func(lang string) *languageCode {
+		if byLang[lang] == nil {
+			byLang[lang] = &languageCode{language: lang}
+		}
+		return byLang[lang]
+	}
+	for _, repo := range data.Repos {
+		for _, sample := range repo.CodeSamples {
+			if lang := languageOf(sample.Path); lang != "" {
+				lc := get(lang)
+				lc.samples = append(lc.samples, fmt.Sprintf("=== %s/%s ===\n%s\n\n", repo.FullName, sample.Path, sample.Content))
+			}
+		}
+		for _, commit := range repo.Commits {
+			msg, _, _ := strings.Cut(commit.Message, "\n")
+			for _, fp := range splitPatchByFile(commit.Patch) {
+				if lang := languageOf(fp.path); lang != "" {
+					lc := get(lang)
+					lc.diffs = append(lc.diffs, fmt.Sprintf("=== %s %s (%s) ===\n%s\n", repo.FullName, fp.path, msg, fp.patch))
+				}
+			}
+		}
+	}
+	for _, g := range data.Gists {
+		for _, f := range g.Files {
+			if lang := languageOf(f.Name); lang != "" && f.Content != "" {
+				lc := get(lang)
+				lc.samples = append(lc.samples, fmt.Sprintf("=== gist %s/%s ===\n%s\n\n", g.ID, f.Name, f.Content))
+			}
+		}
+	}
+
+	var langs []languageCode
+	for _, lc := range byLang {
+		if lc.items() >= minLanguageItems {
+			langs = append(langs, *lc)
+		}
+	}
+	if len(langs) < 2 {
+		return nil
+	}
+	slices.SortFunc(langs, func(a, b languageCode) int {
+		if n := b.items() - a.items(); n != 0 {
+			return n
+		}
+		return strings.Compare(a.language, b.language)
+	})
+	return langs[:min(maxLanguageProfiles, len(langs))]
+}
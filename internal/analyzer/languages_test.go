@@ -0,0 +1,49 @@
+package analyzer
+
+import (
+	"testing"
+
+	"github.com/drpaneas/devlica/internal/ghcrawl"
+)
+
+func TestSplitPatchByFile(t *testing.T) {
+	patch := "--- main.go ---\n@@ -1 +1 @@\n-a\n+b\n--- web/app.ts ---\n@@ -1 +1 @@\n+c\n"
+	got := splitPatchByFile(patch)
+	if len(got) != 2 {
+		t.Fatalf("got %d files, want 2: %+v", len(got), got)
+	}
+	if got[0].path != "main.go" || got[0].patch != "@@ -1 +1 @@\n-a\n+b\n" {
+		t.Errorf("file 0 = %+v", got[0])
+	}
+	if got[1].path != "web/app.ts" || got[1].patch != "@@ -1 +1 @@\n+c\n" {
+		t.Errorf("file 1 = %+v", got[1])
+	}
+}
+
+func TestGroupCodeByLanguage(t *testing.T) {
+	goOnly := &ghcrawl.CrawlResult{Repos: []ghcrawl.RepoData{{
+		CodeSamples: []ghcrawl.CodeSample{{Path: "a.go"}, {Path: "b.go"}, {Path: "c.go"}},
+	}}}
+	if got := groupCodeByLanguage(goOnly); got != nil {
+		t.Errorf("single language should not be split, got %d groups", len(got))
+	}
+
+	data := &ghcrawl.CrawlResult{
+		Repos: []ghcrawl.RepoData{{
+			FullName:    "alice/tool",
+			CodeSamples: []ghcrawl.CodeSample{{Path: "a.go"}, {Path: "b.go"}, {Path: "x.py"}, {Path: "README.md"}},
+			Commits: []ghcrawl.CommitData{
+				{Message: "Add cli", Patch: "--- c.go ---\n+x\n--- y.py ---\n+y\n--- go.mod ---\n+z\n"},
+				{Message: "Tweak", Patch: "--- z.py ---\n+z\n--- d.go ---\n+d\n"},
+				{Message: "Docs", Patch: "--- w.rb ---\n+w\n"},
+			},
+		}},
+	}
+	got := groupCodeByLanguage(data)
+	if len(got) != 2 {
+		t.Fatalf("got %d languages, want Go and Python (Ruby lacks evidence)", len(got))
+	}
+	if got[0].language != "Go" || got[0].items() != 4 || got[1].language != "Python" || got[1].items() != 3 {
+		t.Errorf("got %s:%d %s:%d, want Go:4 Python:3", got[0].language, got[0].items(), got[1].language, got[1].items())
+	}
+}
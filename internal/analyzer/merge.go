@@ -0,0 +1,128 @@
+package analyzer
+
+import (
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/drpaneas/devlica/internal/stats"
+)
+
+// MergePersona overlays a partial persona on base and returns the result;
+// neither input is modified. Every non-empty analysis, synthesis field,
+// and language style in update replaces its counterpart in base, and
+// everything update leaves empty is kept from base. A replaced synthesis
+// field takes its evidence and confidence from update too, so links and
+// ratings never describe text they were not produced for.
+func MergePersona(base, update *Persona) *Persona {
+	merged := *base
+	for _, f := range []struct{ dst, src *string }{
+		{&merged.CodeStyle, &update.CodeStyle},
+		{&merged.CommitMessages, &update.CommitMessages},
+		{&merged.ReviewStyle, &update.ReviewStyle},
+		{&merged.Communication, &update.Communication},
+		{&merged.DeveloperIdentity, &update.DeveloperIdentity},
+		{&merged.StyleEvolution, &update.StyleEvolution},
+		{&merged.Automation, &update.Automation},
+	} {
+		if *f.src != "" {
+			*f.dst = *f.src
+		}
+	}
+	merged.LanguageStyles = mergeLanguageStyles(base.LanguageStyles, update.LanguageStyles)
+	merged.Synthesis = mergeSynthesis(base.Synthesis, update.Synthesis)
+	if update.Metrics != (stats.Metrics{}) {
+		merged.Metrics = update.Metrics
+	}
+	if update.CommitStats.Total > 0 {
+		merged.CommitStats = update.CommitStats
+	}
+	if update.PRStats.Total > 0 {
+		merged.PRStats = update.PRStats
+	}
+	if update.Suggestions != (stats.SuggestionStats{}) {
+		merged.Suggestions = update.Suggestions
+	}
+	if update.Phrases.Comments > 0 {
+		merged.Phrases = update.Phrases
+	}
+	if len(update.Usage.Sources) > 0 {
+		merged.Usage = update.Usage
+	}
+	if len(update.Sparse) > 0 {
+		merged.Sparse = update.Sparse
+	}
+	return &merged
+}
+
+// mergeLanguageStyles replaces base rules per language and appends
+// languages base did not have, keeping base's order.
+func mergeLanguageStyles(base, update []LanguageStyle) []LanguageStyle {
+	merged := slices.Clone(base)
+	for _, u := range update {
+		if u.Rules == "" {
+			continue
+		}
+		i := slices.IndexFunc(merged, func(ls LanguageStyle) bool { return ls.Language == u.Language })
+		if i < 0 {
+			merged = append(merged, u)
+		} else {
+			merged[i] = u
+		}
+	}
+	return merged
+}
+
+func mergeSynthesis(base, update *SynthesisResult) *SynthesisResult {
+	switch {
+	case update == nil:
+		return base
+	case base == nil:
+		return update
+	}
+	merged := *base
+	merged.Evidence = maps.Clone(base.Evidence)
+	merged.Examples = maps.Clone(base.Examples)
+	merged.Confidence = maps.Clone(base.Confidence)
+	merged.Unsupported = maps.Clone(base.Unsupported)
+	dst := reflect.ValueOf(&merged).Elem()
+	src := reflect.ValueOf(update).Elem()
+	for i := range dst.NumField() {
+		if dst.Field(i).Kind() != reflect.String {
+			continue
+		}
+		name, _, _ := strings.Cut(dst.Type().Field(i).Tag.Get("json"), ",")
+		if v := src.Field(i).String(); v != "" {
+			dst.Field(i).SetString(v)
+			delete(merged.Evidence, name)
+			delete(merged.Confidence, name)
+			delete(merged.Unsupported, name)
+		}
+	}
+	for name, urls := range update.Evidence {
+		if merged.Evidence == nil {
+			merged.Evidence = make(map[string][]string)
+		}
+		merged.Evidence[name] = urls
+	}
+	for url, ex := range update.Examples {
+		if merged.Examples == nil {
+			merged.Examples = make(map[string]Example)
+		}
+		merged.Examples[url] = ex
+	}
+	for name, statements := range update.Unsupported {
+		if merged.Unsupported == nil {
+			merged.Unsupported = make(map[string][]string)
+		}
+		merged.Unsupported[name] = statements
+	}
+	for name, c := range update.Confidence {
+		if merged.Confidence == nil {
+			merged.Confidence = make(map[string]Confidence)
+		}
+		merged.Confidence[name] = c
+	}
+	return &merged
+}
@@ -0,0 +1,71 @@
+package analyzer
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/drpaneas/devlica/internal/stats"
+)
+
+func TestMergePersona(t *testing.T) {
+	base := &Persona{
+		Username:    "alice",
+		CodeStyle:   "old code style",
+		ReviewStyle: "old review style",
+		LanguageStyles: []LanguageStyle{
+			{Language: "Go", Rules: "old go"},
+			{Language: "Python", Rules: "old python"},
+		},
+		Synthesis: &SynthesisResult{
+			CodingPhilosophy: "old philosophy",
+			ReviewVoice:      "old voice",
+			Evidence: map[string][]string{
+				"coding_philosophy": {"https://github.com/alice/tool/commit/1"},
+				"review_voice":      {"https://github.com/alice/tool/pull/1"},
+			},
+			Confidence: map[string]Confidence{
+				"coding_philosophy": {Level: "high"},
+				"review_voice":      {Level: "low"},
+			},
+		},
+		Metrics: stats.Metrics{ReviewComments: 3},
+	}
+	update := &Persona{
+		Username:       "alice",
+		ReviewStyle:    "new review style",
+		LanguageStyles: []LanguageStyle{{Language: "Go", Rules: "new go"}, {Language: "Rust", Rules: "new rust"}},
+		Synthesis: &SynthesisResult{
+			ReviewVoice: "new voice",
+			Confidence:  map[string]Confidence{"review_voice": {Level: "medium"}},
+		},
+	}
+
+	got := MergePersona(base, update)
+
+	if got.CodeStyle != "old code style" || got.ReviewStyle != "new review style" {
+		t.Errorf("analyses = %q, %q", got.CodeStyle, got.ReviewStyle)
+	}
+	want := []LanguageStyle{{"Go", "new go"}, {"Python", "old python"}, {"Rust", "new rust"}}
+	if !slices.Equal(got.LanguageStyles, want) {
+		t.Errorf("LanguageStyles = %v, want %v", got.LanguageStyles, want)
+	}
+	s := got.Synthesis
+	if s.CodingPhilosophy != "old philosophy" || s.ReviewVoice != "new voice" {
+		t.Errorf("synthesis = %+v", s)
+	}
+	if _, ok := s.Evidence["review_voice"]; ok {
+		t.Error("evidence for a replaced field should be dropped")
+	}
+	if len(s.Evidence["coding_philosophy"]) != 1 || s.Confidence["coding_philosophy"].Level != "high" {
+		t.Error("evidence and confidence of unchanged fields should be kept")
+	}
+	if s.Confidence["review_voice"].Level != "medium" {
+		t.Errorf("review_voice confidence = %q, want the update's", s.Confidence["review_voice"].Level)
+	}
+	if got.Metrics.ReviewComments != 3 {
+		t.Error("empty metrics in the update should keep base metrics")
+	}
+	if base.Synthesis.ReviewVoice != "old voice" || len(base.Synthesis.Evidence) != 2 || base.LanguageStyles[0].Rules != "old go" {
+		t.Error("MergePersona modified base")
+	}
+}
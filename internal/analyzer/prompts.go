@@ -2,10 +2,11 @@ package analyzer
 
 const systemPrompt = `You are an expert developer analyst. You analyze GitHub activity data to extract 
 a developer's unique persona - their coding style, values, review patterns, and philosophy.
-Be specific and cite concrete examples from the data. Avoid generic statements.
+Be specific and cite concrete examples from the data, keeping the URL of each example you cite
+when the data gives one. Avoid generic statements.
 Write in third person about the developer.`
 
-const codeStylePrompt = `Analyze this developer's coding style based on their code samples, commit diffs, and CI/CD configurations.
+const codeStylePrompt = `Analyze this developer's coding style based on their code samples and commit diffs.
 
 Developer: %s
 
@@ -27,13 +28,41 @@ Extract the following with CONCRETE examples from their code:
 6. Language-specific idioms they prefer
 7. Formatting preferences visible in their code
 8. Any distinctive patterns that make their code recognizable
-9. CI/CD and automation patterns (if workflow files are present)
-10. Commit size patterns (do they make small surgical changes or large sweeping ones?)
-
-11. Tradeoff patterns (where they accept verbosity, duplication, or pragmatism instead of abstraction)
+9. Commit size patterns (do they make small surgical changes or large sweeping ones?)
+10. Tradeoff patterns (where they accept verbosity, duplication, or pragmatism instead of abstraction)
 
 Be specific. Quote actual code snippets. Do not be generic.`
 
+const commitMessagePrompt = `Analyze how this developer writes commit messages, based on the full messages of their commits.
+
+Developer: %s
+
+COMMIT MESSAGE STATISTICS (computed directly from their commits; treat these numbers as ground truth):
+%s
+
+COMMIT MESSAGES:
+%s
+
+Extract the following with CONCRETE examples quoted from their messages:
+1. Subject line format (conventional commits and which types/scopes, prefixes like "pkg:", tickets, emoji)
+2. Subject length, capitalization, trailing punctuation, and mood (imperative, past tense, descriptive)
+3. When they write a body, and what it contains (motivation, what changed, alternatives considered, testing)
+4. Body formatting (wrapped prose, bullet lists, paragraphs) and typical length
+5. Trailers and references (Signed-off-by, Co-authored-by, Fixes #123, links)
+6. How the message changes with the kind of change (fixes, features, refactors, dependency bumps, reverts)
+7. A template an agent can follow to write a commit message they would write, with two realistic examples
+
+Be specific. Quote actual subjects and bodies. Do not be generic.`
+
+const languageStylePrompt = `Analyze how this developer writes %[2]s specifically. The evidence below is only their %[2]s code: per-file commit diffs (code they certainly wrote) and code samples from their repos.
+
+Developer: %[1]s
+
+%[3]s
+
+Write concrete, actionable rules for writing %[2]s the way they do, as a markdown bullet list of imperative statements. Cover naming, error handling, code organization, testing, comments, formatting, and the %[2]s idioms and libraries they prefer.
+Focus on what is specific to their %[2]s; skip generic advice that would apply to any language. Back each rule with a short quoted snippet where possible. Output only the list.`
+
 const reviewStylePrompt = `Analyze this developer's code review style based on submitted PR reviews, inline review comments, diff hunks, and fallback PR discussion comments.
 
 Developer: %s
@@ -41,9 +70,18 @@ Developer: %s
 REVIEW ACTIVITY:
 %s
 
+CODE SUGGESTIONS (inline comments with a GitHub suggestion block vs prose only):
+%s
+
+REVIEW TONE (counted over all their review comments; treat these numbers as ground truth):
+%s
+
+SIGNATURE PHRASES (counted over all their comments, outside quotes and code):
+%s
+
 Extract the following with CONCRETE examples from their reviews:
 1. What do they focus on most? (correctness, style, performance, security, tests, readability)
-2. How do they deliver feedback? (direct, diplomatic, questioning, teaching)
+2. How do they deliver feedback? (direct, diplomatic, questioning, teaching; check your impression against the tone numbers above)
 3. What recurring themes appear in their reviews?
 4. Do they suggest alternatives or just point out problems?
 5. How detailed are their reviews? (one-liners vs thorough explanations)
@@ -52,10 +90,13 @@ Extract the following with CONCRETE examples from their reviews:
 8. What issues do they treat as nits versus real blockers?
 9. How does their review style change with PR size, labels, risk, or code area?
 10. How selective are they? (many comments vs one high-signal comment)
+11. Do they propose concrete patches with suggestion blocks, or describe changes in prose? (use the counts above; quote a suggestion if one appears)
+12. What do they object to every time it appears? State each as a rule they enforce on others ("never X", "rejects Y"), quoting the comment where they objected.
+13. Which of the signature phrases above belong to their review voice, and in which situations do they use them? (approving, nitpicking, asking, signing off)
 
 Quote actual review summaries/comments and refer to diff or PR context when relevant. Be specific.`
 
-const communicationPrompt = `Analyze this developer's communication style based on their PR descriptions, issue reports, issue comments, and release notes.
+const communicationPrompt = `Analyze this developer's communication style based on their PR descriptions, issue reports, issue comments, release notes, and repository documentation.
 
 Developer: %s
 
@@ -74,6 +115,12 @@ RELEASE NOTES:
 DISCUSSIONS:
 %s
 
+DOCUMENTATION STYLE (README, CONTRIBUTING, and issue/PR templates of their repos):
+%s
+
+REVIEW THREAD REPLIES (their replies in inline review threads, each after the comment it answers):
+%s
+
 Extract the following:
 1. How do they describe problems? (concise vs verbose, structured vs narrative)
 2. How do they structure PR descriptions? (bullet points, paragraphs, checklists)
@@ -84,6 +131,8 @@ Extract the following:
 7. How do they report bugs or request features? (structured, minimal reproduction, detailed context)
 8. How do they write release notes? (technical, user-facing, changelog style)
 9. How do they participate in discussions? (asking questions, proposing solutions, facilitating conversation)
+10. How do they write project documentation? (README structure, badges, quick starts, examples, tone toward contributors, what their templates ask for)
+11. How do they respond to pushback in review threads? (concede and fix, defend with reasoning, ask for data or a benchmark, escalate or defer to a maintainer, compromise) Contrast replies on their own PRs with replies while reviewing, and quote both sides of a thread.
 
 Quote actual excerpts as examples. Be specific.`
 
@@ -94,13 +143,16 @@ Developer: %s
 PROFILE:
 %s
 
-STARRED REPOSITORIES (showing their interests):
+STARRED AND WATCHED REPOSITORIES, STARRED GISTS (showing their interests):
+%s
+
+INTEREST AREAS (starred repos, authored issues, and issue comments clustered by topic, largest first):
 %s
 
 GISTS:
 %s
 
-ORGANIZATIONS:
+ORGANIZATIONS AND SPONSORSHIP:
 %s
 
 EXTERNAL CONTRIBUTIONS (PRs to repos they don't own):
@@ -109,28 +161,86 @@ EXTERNAL CONTRIBUTIONS (PRs to repos they don't own):
 RECENT ACTIVITY EVENTS:
 %s
 
+CONTRIBUTION TIMELINE (weekly commits and line changes across their full repo history):
+%s
+
 PROJECTS:
 %s
 
 WIKI PAGES:
 %s
 
+COMMUNITY RECEPTION (reactions on their comments and PRs):
+%s
+
+DEPENDENCIES (libraries and frameworks declared in their repos' manifests):
+%s
+
+BRANCH AND TAG NAMES (of their own repos):
+%s
+
+ISSUE TRIAGE (how they handle issues others open on repos they own or maintain):
+%s
+
 Extract the following:
-1. What technologies and domains are they most interested in? (based on starred repos and activity)
+1. What technologies and domains are they most interested in? (based on starred and watched repos, starred gists, and activity; rank them using the interest areas when given, naming each area's representative repos)
 2. What kind of projects do they build? (tools, libraries, applications, infrastructure)
 3. What open-source communities do they participate in?
 4. How actively do they contribute to projects they don't own?
-5. What is their contribution cadence? (burst vs steady, weekday vs weekend patterns)
-6. What organizations are they affiliated with and what does that suggest?
+5. What is their contribution cadence? (burst vs steady, weekday vs weekend patterns; use the contribution timeline for long-term trends)
+6. What organizations are they affiliated with, do they sponsor others or ask for sponsorship, and what does that suggest about their community engagement?
 7. What does their profile say about how they want to be perceived professionally?
 8. What licensing preferences do they show?
 9. What recurring contribution patterns show up over time? (maintainer work, tooling, docs, CI, releases, upstream fixes)
-10. How do they use GitHub Projects for planning and organization?
+10. How do they use GitHub Projects for planning and organization? (board vs table views, custom fields, status workflow, draft items vs linked issues and PRs)
 11. What documentation patterns show up in their wiki pages?
+12. How does the community receive their comments and PRs? Which kinds of contributions draw the most positive reactions?
+13. Which frameworks and libraries do they reach for repeatedly? Name them, and note whether they prefer a lean dependency set or lean on the ecosystem.
+14. What branch and tag naming conventions do they follow? (feature/x, fix-x, release/vX.Y, semver vs date tags)
+15. Do they sign their commits (GPG, SSH, or S/MIME)? What does that suggest about how security-conscious they are?
+16. How do they behave as a maintainer when others open issues? (response speed, labeling discipline, closing with an explanation vs silently, tone of first replies)
 
 Be specific and data-driven. Avoid speculation without evidence.`
 
-const synthesisPrompt = `You have analyzed a developer's GitHub activity across four dimensions. 
+const styleEvolutionPrompt = `Analyze how this developer's style has changed over time. Their commits and comments are grouped into eras by year, oldest first, each with commit message statistics and evenly spaced samples.
+
+Developer: %s
+
+ERAS:
+%s
+
+Extract the following, citing years and quoting examples:
+1. How did their commit messages change? (length, conventions, mood, bodies, issue references)
+2. How did their code change? (languages, naming, error handling, tests, level of abstraction)
+3. How did the tone and depth of their comments and reviews change?
+4. Which habits stayed constant across every era?
+5. What does their most recent era look like? That is the style to emulate today; name older habits they have abandoned.
+
+If an era has too little data to judge, say so instead of guessing.`
+
+const automationPrompt = `Analyze how this developer automates their projects, based on the CI workflows, task runners, linter and formatter configs, git hooks, dependency bots, and release tooling in their repos.
+
+Developer: %s
+
+AUTOMATION FILES:
+%s
+
+CI RUN OUTCOMES (recent GitHub Actions runs of their repos):
+%s
+
+Extract the following, quoting the config that shows each point:
+1. Build and task entry points (Makefile or justfile targets, scripts) and what they wrap
+2. Linters and formatters, which rules they enable or disable, and how strict they are
+3. Pre-commit or other git hooks, and what they enforce before code leaves the machine
+4. CI workflow structure: triggers, job layout, matrices, caching, pinned action versions
+5. Release automation: tagging, changelogs, goreleaser or similar, artifact publishing
+6. Dependency update bots and how they are configured
+7. CI discipline: do they keep the default branch green, or tolerate frequent red builds?
+8. What a new repo set up in their style would include on day one
+
+If a point has no data, say so instead of guessing.`
+
+const synthesisPrompt = `You have analyzed a developer's GitHub activity across seven dimensions. 
 Now synthesize these analyses into a unified developer persona.
 
 Developer: %s
@@ -138,6 +248,9 @@ Developer: %s
 CODE STYLE ANALYSIS:
 %s
 
+COMMIT MESSAGE ANALYSIS:
+%s
+
 REVIEW STYLE ANALYSIS:
 %s
 
@@ -147,26 +260,59 @@ COMMUNICATION ANALYSIS:
 DEVELOPER IDENTITY ANALYSIS:
 %s
 
+STYLE EVOLUTION ANALYSIS:
+%s
+
+AUTOMATION ANALYSIS:
+%s
+
+HARD METRICS (computed directly from their activity, not by an LLM; treat these numbers as ground truth and prefer them over impressions in the analyses):
+%s
+
 Respond with a single JSON object (no markdown, no commentary) with these fields:
 
 {
   "coding_philosophy": "What they value most in code and what tradeoffs they consistently make.",
   "code_style_rules": "Concrete, actionable rules that capture how they write code. Format each as an imperative statement.",
+  "commit_message_style": "How to write a commit message the way they do: subject format and length, mood, when and how they write a body, trailers and references. Include a template and two example messages in their style.",
   "review_priorities": "Ordered list of what they care about when reviewing code.",
   "review_decision_style": "What makes them approve, request changes, or leave non-blocking feedback.",
   "review_non_blocking_nits": "The kinds of issues they notice but usually treat as non-blocking, if any.",
   "review_context_sensitivity": "How their review expectations change depending on risk, repo type, language, PR size, or change category.",
-  "review_voice": "How to give feedback in their style. Include example phrasings.",
+  "review_voice": "How to give feedback in their style. Include example phrasings that reuse the signature phrases the review style analysis found, and calibrate how often to ask questions, hedge, sound harsh, or sound warm to the review tone rates in HARD METRICS.",
   "communication_patterns": "How they write PR descriptions, comments, and explanations.",
   "testing_philosophy": "Their approach to testing (if data exists). Write 'No specific testing data was identified.' if none.",
   "distinctive_traits": "What makes this developer unique compared to a generic senior engineer.",
-  "developer_interests": "Technologies, domains, and communities they engage with. What topics excite them.",
+  "anti_patterns": "What this developer would never do or let through review, each as an imperative negative rule (e.g. 'Never use panic for control flow.', 'Reject pull requests over 1000 lines.'). Derive them from what they criticize in reviews and what their own code consistently avoids, and cite the criticism behind each. Write 'No specific anti-pattern data was identified.' if none.",
+  "developer_interests": "Technologies, domains, and communities they engage with, as a ranked list of interest areas with representative repos for each. What topics excite them.",
   "activity_patterns": "Their contribution cadence, preferred kinds of contributions, and where they spend energy in GitHub activity.",
-  "project_patterns": "How they structure projects, what they build, licensing choices, CI/CD preferences.",
-  "collaboration_style": "How they interact with the community - issue reporting, mentoring, contributing upstream.",
-  "code_examples": "3-5 representative code snippets from their repos that best demonstrate their coding style. Each example should be an actual code block (use markdown fenced code blocks with the language tag) followed by a one-line explanation of what style pattern it demonstrates. Pick examples that show naming conventions, error handling, testing style, or other distinctive patterns."
+  "project_patterns": "How they structure projects, what they build, the frameworks and libraries they prefer, branch and tag naming conventions, licensing choices, and the automation the AUTOMATION ANALYSIS found them setting up.",
+  "collaboration_style": "How they interact with the community - issue reporting, mentoring, contributing upstream, and how they respond when reviewers push back.",
+  "automation": "How to automate a project the way they do: task runner targets, linters and formatters with their settings, git hooks, CI workflow layout, dependency bots, and release tooling. Write 'No specific automation data was identified.' if none.",
+  "maintainer_behavior": "How they triage issues others open on their repos: how fast and how they first respond, how they label, and whether they close with an explanation. Write 'No specific issue-triage data was identified.' if none.",
+  "style_evolution": "How their style changed across eras (years) and which current habits supersede older ones, so an agent emulates who they are now. Write 'No style-evolution data was identified.' if none.",
+  "code_examples": "3-5 representative code snippets from their repos that best demonstrate their coding style. Each example should be an actual code block (use markdown fenced code blocks with the language tag) followed by a one-line explanation of what style pattern it demonstrates. Pick examples that show naming conventions, error handling, testing style, or other distinctive patterns.",
+  "evidence": {"<field name from above>": ["2-3 URLs copied verbatim from the analyses that best support that field"]},
+  "confidence": {"<field name from above>": {"level": "high, medium, or low", "rationale": "One sentence on how much data backed the field."}}
 }
 
-All values must be non-empty strings. Be extremely specific. Every statement should be backed
+All values except "evidence" and "confidence" must be non-empty strings. In "evidence", include only
+fields with supporting URLs and never invent or modify a URL. In "confidence", rate every field above:
+"high" when many independent examples agree, "medium" when the pattern rests on a handful of examples,
+and "low" when it is inferred from little or indirect data or the analyses found none. An analysis may end
+with a CONTRADICTIONS section listing points two independent analysts disagreed on; do not state a
+contested claim as fact, and rate the confidence of fields that rest on one no higher than "medium". Be extremely specific. Every statement should be backed
 by evidence from the analyses. Use concrete examples and actual phrasings from their GitHub activity.
 This persona will be used to make an AI agent emulate this developer, so precision matters.`
+
+// sparseSynthesisPrompt replaces synthesisPrompt when the crawl is sparse.
+// It takes the same arguments plus the reasons the profile is sparse.
+const sparseSynthesisPrompt = synthesisPrompt + `
+
+THIS PROFILE IS SPARSE. The crawl found too little of the developer's own writing for a confident persona:
+%s
+Write a best-effort persona under these rules instead of filling gaps with generic senior-engineer advice:
+- State only what the analyses actually observed, and say how little it rests on (e.g. "In the 4 commits seen, ...").
+- Where the data says nothing about a field, write "Not enough public activity to tell." rather than guessing.
+- Never present a habit as typical of them on the strength of one or two examples.
+- Rate every field's confidence "low" unless it rests on several independent examples, and never "high".`
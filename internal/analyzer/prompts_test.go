@@ -0,0 +1,84 @@
+package analyzer
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/drpaneas/devlica/internal/ghcrawl"
+	"github.com/drpaneas/devlica/internal/prompts"
+)
+
+// promptFixture has enough data to run every analysis.
+func promptFixture() *ghcrawl.CrawlResult {
+	repo := ghcrawl.RepoData{Name: "tool", FullName: "alice/tool", IsOwner: true}
+	for i, year := range []int{2020, 2021, 2022, 2023} {
+		repo.Commits = append(repo.Commits, ghcrawl.CommitData{
+			SHA:     strings.Repeat("a", 40),
+			Message: "Fix parser edge case",
+			Date:    time.Date(year, 3, 1, 0, 0, 0, 0, time.UTC),
+			Patch:   "+x := 1",
+		})
+		repo.CodeSamples = append(repo.CodeSamples,
+			ghcrawl.CodeSample{Path: "pkg/file" + string(rune('a'+i)) + ".go", Content: "package pkg"},
+			ghcrawl.CodeSample{Path: "scripts/file" + string(rune('a'+i)) + ".py", Content: "import os"},
+		)
+	}
+	repo.Tooling = []ghcrawl.CodeSample{{Path: "Makefile", Content: "test:\n\tgo test ./..."}}
+	repo.PRs = []ghcrawl.PullRequestData{{Repo: "alice/tool", Number: 1, Title: "Add parser", Body: "Adds a parser."}}
+	repo.ReviewComments = []ghcrawl.ReviewComment{{Repo: "alice/tool", PRNumber: 2, Body: "Please add a test.", Path: "main.go", DiffHunk: "@@ -1 +1 @@"}}
+	return &ghcrawl.CrawlResult{User: ghcrawl.UserProfile{Login: "alice"}, Repos: []ghcrawl.RepoData{repo}}
+}
+
+// Without the compile-time printf check, a prompt whose verbs drift from its
+// arguments would only show up as %!(...) noise in what the model reads.
+func TestAnalyzeDefaultPromptsFormatCleanly(t *testing.T) {
+	p := &recordingProvider{reply: `{"coding_philosophy": "x"}`}
+	if _, err := New(p).Analyze(context.Background(), "alice", promptFixture()); err != nil {
+		t.Fatal(err)
+	}
+	// Code style, commit messages, two languages, review style,
+	// communication, identity, evolution, automation, and synthesis.
+	if len(p.prompts) < 10 {
+		t.Fatalf("sent %d prompts, want every analysis to run", len(p.prompts))
+	}
+	for _, prompt := range p.prompts {
+		if strings.Contains(prompt, "%!") {
+			t.Errorf("prompt has a formatting error:\n%s", prompt)
+		}
+	}
+}
+
+func TestAnalyzeUsesPromptOverride(t *testing.T) {
+	dir := t.TempDir()
+	tmpl := "Describe {{.Username}}'s reviews.\n{{.ReviewActivity}}\n"
+	if err := os.WriteFile(filepath.Join(dir, "review-style"+prompts.Ext), []byte(tmpl), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	set, err := prompts.Load(dir, PromptNames)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := &recordingProvider{reply: `{"coding_philosophy": "x"}`}
+	a := New(p)
+	a.SetPrompts(set)
+	if _, err := a.Analyze(context.Background(), "alice", promptFixture()); err != nil {
+		t.Fatal(err)
+	}
+	var found bool
+	for _, prompt := range p.prompts {
+		if strings.HasPrefix(prompt, "Describe alice's reviews.") {
+			found = true
+		}
+		if strings.Contains(prompt, "You are analyzing the code review style") {
+			t.Error("built-in review style prompt was sent despite the override")
+		}
+	}
+	if !found {
+		t.Error("override prompt was not sent")
+	}
+}
@@ -0,0 +1,75 @@
+package analyzer
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/drpaneas/devlica/internal/progress"
+)
+
+func TestReanalyzeRunsOnlyRequestedDimensions(t *testing.T) {
+	p := &recordingProvider{reply: `{"review_voice": "fresh"}`}
+	previous := &Persona{
+		CodeStyle:      "previous code style",
+		ReviewStyle:    "previous review style",
+		LanguageStyles: []LanguageStyle{{Language: "Go", Rules: "previous go rules"}},
+	}
+
+	got, err := New(p).Reanalyze(context.Background(), "alice", promptFixture(), previous, []string{"review-style"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// One review style analysis, then the synthesis.
+	if len(p.prompts) != 2 {
+		t.Fatalf("sent %d prompts, want 2", len(p.prompts))
+	}
+	if !strings.Contains(p.prompts[0], "code review style") {
+		t.Errorf("first prompt should be the review style analysis:\n%s", p.prompts[0])
+	}
+	if !strings.Contains(p.prompts[1], "previous code style") {
+		t.Error("synthesis should use the previous code style analysis")
+	}
+	if got.CodeStyle != "previous code style" || got.ReviewStyle == "previous review style" {
+		t.Errorf("CodeStyle = %q, ReviewStyle = %q", got.CodeStyle, got.ReviewStyle)
+	}
+	if len(got.LanguageStyles) != 1 || got.LanguageStyles[0].Rules != "previous go rules" {
+		t.Errorf("LanguageStyles = %+v, want the previous ones", got.LanguageStyles)
+	}
+	if got.Synthesis.ReviewVoice != "fresh" {
+		t.Errorf("synthesis was not redone: %+v", got.Synthesis)
+	}
+}
+
+func TestReanalyzeRejectsUnknownDimension(t *testing.T) {
+	_, err := New(&recordingProvider{}).Reanalyze(context.Background(), "alice", promptFixture(), &Persona{}, []string{"vibes"})
+	if err == nil {
+		t.Fatal("expected an error for an unknown dimension")
+	}
+}
+
+func TestReanalyzeReportsProgress(t *testing.T) {
+	var mu sync.Mutex
+	var finished []string
+	a := New(&recordingProvider{reply: `{"review_voice": "fresh"}`})
+	a.SetProgress(func(e progress.Event) {
+		mu.Lock()
+		defer mu.Unlock()
+		if e.Phase == progress.Finished {
+			finished = append(finished, e.Step)
+			if e.Tokens == 0 {
+				t.Errorf("step %s reported no tokens", e.Step)
+			}
+		}
+	})
+
+	if _, err := a.Reanalyze(context.Background(), "alice", promptFixture(), &Persona{}, []string{"review-style"}); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(finished, []string{"review-style", "synthesis"}) {
+		t.Errorf("finished steps = %v, want review-style then synthesis", finished)
+	}
+}
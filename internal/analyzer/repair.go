@@ -0,0 +1,48 @@
+package analyzer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	"github.com/drpaneas/devlica/internal/prompts"
+)
+
+// maxJSONRepairs bounds how many times a reply that is not valid JSON is
+// sent back to the model for correction.
+const maxJSONRepairs = 2
+
+const jsonRepairPrompt = `Your previous reply was supposed to be a single JSON object, but it could not be parsed.
+
+PREVIOUS REPLY:
+%s
+
+PARSE ERROR:
+%s
+
+Respond with the same content as a single valid JSON object: the same fields with the same values, only with the syntax fixed (escape quotes, backslashes, and newlines inside strings, remove trailing commas, and close every string, array, and object). No markdown, no commentary.`
+
+// parseRepairing parses raw, the reply to the prompt called name that was
+// asked to match schema. When
+// parsing fails, it sends the reply and the parse error back to the model
+// and parses the corrected reply, up to maxJSONRepairs times, so one
+// malformed reply does not throw away the analyses it was built from. It
+// returns the last parse error if no reply parses.
+func parseRepairing[T any](ctx context.Context, a *Analyzer, name, raw string, schema json.RawMessage, parse func(string) (T, error)) (T, error) {
+	result, err := parse(raw)
+	for attempt := 1; err != nil && attempt <= maxJSONRepairs; attempt++ {
+		slog.Warn("reply is not valid JSON, asking the model to repair it", "prompt", name, "attempt", attempt, "error", err)
+		repaired, cerr := a.completeJSON(ctx, "json-repair", jsonRepairPrompt, schema,
+			prompts.Arg("Reply", raw),
+			prompts.Arg("Error", err.Error()),
+		)
+		if cerr != nil {
+			return result, fmt.Errorf("repairing %s JSON: %w", name, cerr)
+		}
+		a.saveArtifact(fmt.Sprintf("%s-repair-%d", name, attempt), repaired)
+		raw = repaired
+		result, err = parse(raw)
+	}
+	return result, err
+}
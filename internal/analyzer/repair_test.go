@@ -0,0 +1,62 @@
+package analyzer
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/drpaneas/devlica/internal/llm"
+)
+
+// repairProvider answers the synthesis prompt with synthesis, JSON repair
+// prompts with the next of repairs, and every other prompt with an analysis.
+type repairProvider struct {
+	synthesis string
+	repairs   []string
+
+	mu      sync.Mutex
+	repairN int
+}
+
+func (p *repairProvider) Complete(_ context.Context, _, prompt string, _ *llm.CompleteOptions) (string, error) {
+	switch {
+	case strings.Contains(prompt, "could not be parsed"):
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		reply := p.repairs[min(p.repairN, len(p.repairs)-1)]
+		p.repairN++
+		return reply, nil
+	case strings.Contains(prompt, "synthesize these analyses"):
+		return p.synthesis, nil
+	}
+	return "Prefers small functions.", nil
+}
+
+func TestSynthesisJSONRepair(t *testing.T) {
+	tests := []struct {
+		name        string
+		repairs     []string
+		wantErr     bool
+		wantRepairs int
+	}{
+		{"repaired on first attempt", []string{`{"coding_philosophy": "Simplicity."}`}, false, 1},
+		{"repaired on second attempt", []string{`{"coding_philosophy": `, `{"coding_philosophy": "Simplicity."}`}, false, 2},
+		{"never repaired", []string{`still not JSON`}, true, maxJSONRepairs},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &repairProvider{synthesis: `{"coding_philosophy": "Simplicity.",,}`, repairs: tt.repairs}
+			persona, err := New(p).Analyze(context.Background(), "alice", promptFixture())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Analyze() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if p.repairN != tt.wantRepairs {
+				t.Errorf("sent %d repair prompts, want %d", p.repairN, tt.wantRepairs)
+			}
+			if !tt.wantErr && persona.Synthesis.CodingPhilosophy != "Simplicity." {
+				t.Errorf("CodingPhilosophy = %q, want the repaired value", persona.Synthesis.CodingPhilosophy)
+			}
+		})
+	}
+}
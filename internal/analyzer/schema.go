@@ -0,0 +1,56 @@
+package analyzer
+
+import "encoding/json"
+
+// SynthesisSchema is the JSON Schema of a synthesis reply, for providers
+// that can constrain their output to one. Every persona field is a string;
+// evidence and confidence are keyed by field name.
+var SynthesisSchema = mustMarshal(map[string]any{
+	"type":       "object",
+	"properties": synthesisSchemaProperties(),
+	"required":   synthesisFields(),
+})
+
+// groundingSchema is the JSON Schema of a grounding reply.
+var groundingSchema = mustMarshal(map[string]any{
+	"type": "object",
+	"additionalProperties": map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"unsupported": stringArray,
+			"revised":     map[string]any{"type": "string"},
+		},
+		"required": []string{"unsupported", "revised"},
+	},
+})
+
+var stringArray = map[string]any{"type": "array", "items": map[string]any{"type": "string"}}
+
+func synthesisSchemaProperties() map[string]any {
+	props := make(map[string]any)
+	for _, f := range synthesisFields() {
+		props[f] = map[string]any{"type": "string"}
+	}
+	props["evidence"] = map[string]any{"type": "object", "additionalProperties": stringArray}
+	props["confidence"] = map[string]any{
+		"type": "object",
+		"additionalProperties": map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"level":     map[string]any{"type": "string", "enum": []string{ConfidenceHigh, ConfidenceMedium, ConfidenceLow}},
+				"rationale": map[string]any{"type": "string"},
+			},
+			"required": []string{"level", "rationale"},
+		},
+	}
+	return props
+}
+
+// mustMarshal encodes a schema built from literals, which cannot fail.
+func mustMarshal(v any) json.RawMessage {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		panic(err)
+	}
+	return raw
+}
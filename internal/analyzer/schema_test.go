@@ -0,0 +1,70 @@
+package analyzer
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/drpaneas/devlica/internal/llm"
+)
+
+// schemaProvider records the schema each prompt was sent with.
+type schemaProvider struct {
+	mu      sync.Mutex
+	schemas map[string]json.RawMessage
+}
+
+func (p *schemaProvider) Complete(_ context.Context, _, prompt string, opts *llm.CompleteOptions) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var schema json.RawMessage
+	if opts != nil {
+		schema = opts.JSONSchema
+	}
+	switch {
+	case strings.Contains(prompt, "synthesize these analyses"):
+		p.schemas["synthesis"] = schema
+		return `{"coding_philosophy": "Simplicity."}`, nil
+	case strings.Contains(prompt, "fact-checking a developer persona"):
+		p.schemas["grounding"] = schema
+		return `{}`, nil
+	}
+	if schema != nil {
+		p.schemas["analysis"] = schema
+	}
+	return "Prefers small functions.", nil
+}
+
+func TestJSONPromptsSendSchema(t *testing.T) {
+	p := &schemaProvider{schemas: make(map[string]json.RawMessage)}
+	a := New(p)
+	a.SetGrounding(true)
+	if _, err := a.Analyze(context.Background(), "alice", promptFixture()); err != nil {
+		t.Fatal(err)
+	}
+
+	var synthesis struct {
+		Properties map[string]json.RawMessage `json:"properties"`
+		Required   []string                   `json:"required"`
+	}
+	if err := json.Unmarshal(p.schemas["synthesis"], &synthesis); err != nil {
+		t.Fatalf("synthesis schema %s: %v", p.schemas["synthesis"], err)
+	}
+	for _, f := range append(synthesisFields(), "evidence", "confidence") {
+		if synthesis.Properties[f] == nil {
+			t.Errorf("synthesis schema has no %q property", f)
+		}
+	}
+	if !slices.Contains(synthesis.Required, "coding_philosophy") || slices.Contains(synthesis.Required, "evidence") {
+		t.Errorf("synthesis schema requires %v, want every persona field and no maps", synthesis.Required)
+	}
+	if !json.Valid(p.schemas["grounding"]) {
+		t.Errorf("grounding schema = %s, want valid JSON", p.schemas["grounding"])
+	}
+	if p.schemas["analysis"] != nil {
+		t.Error("a prose analysis was sent with a JSON schema")
+	}
+}
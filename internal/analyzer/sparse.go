@@ -0,0 +1,58 @@
+package analyzer
+
+import (
+	"fmt"
+	"strings"
+)
+
+// A crawl is sparse when every source of the developer's own writing falls
+// below its threshold: with a handful of commits, reviews, and comments the
+// analyses can only restate what any senior engineer would do.
+const (
+	sparseCommits  = 20
+	sparseReviews  = 5
+	sparseComments = 10
+)
+
+// Sparsity reports why counts are too thin for a confident persona, one
+// reason per source, or nil when at least one source has enough data.
+func Sparsity(counts DataCounts) []string {
+	thin := []struct {
+		n, min int
+		what   string
+	}{
+		{counts.Commits, sparseCommits, "commits"},
+		{counts.Reviews, sparseReviews, "pull request reviews"},
+		{counts.IssueComments, sparseComments, "issue comments"},
+	}
+	var reasons []string
+	for _, t := range thin {
+		if t.n >= t.min {
+			return nil
+		}
+		reasons = append(reasons, fmt.Sprintf("only %d %s (%d or more needed)", t.n, t.what, t.min))
+	}
+	return reasons
+}
+
+// formatSparsity renders the reasons as a list for the sparse synthesis
+// prompt.
+func formatSparsity(reasons []string) string {
+	var b strings.Builder
+	for _, r := range reasons {
+		fmt.Fprintf(&b, "- %s\n", r)
+	}
+	return b.String()
+}
+
+// capConfidence lowers every "high" rating to "medium": on a sparse profile
+// no field rests on many independent examples, whatever the model says.
+func capConfidence(s *SynthesisResult) {
+	for field, c := range s.Confidence {
+		if c.Level == ConfidenceHigh {
+			c.Level = ConfidenceMedium
+			c.Rationale = strings.TrimSpace(c.Rationale + " Capped at medium because the profile is sparse.")
+			s.Confidence[field] = c
+		}
+	}
+}
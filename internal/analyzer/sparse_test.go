@@ -0,0 +1,66 @@
+package analyzer
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/drpaneas/devlica/internal/llm"
+)
+
+func TestSparsity(t *testing.T) {
+	tests := []struct {
+		name   string
+		counts DataCounts
+		want   int
+	}{
+		{"empty crawl", DataCounts{}, 3},
+		{"a few of everything", DataCounts{Commits: 5, Reviews: 2, IssueComments: 3}, 3},
+		{"enough commits", DataCounts{Commits: 20}, 0},
+		{"enough reviews", DataCounts{Reviews: 5}, 0},
+		{"enough issue comments", DataCounts{IssueComments: 10}, 0},
+		{"only stars", DataCounts{StarredRepos: 500}, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Sparsity(tt.counts); len(got) != tt.want {
+				t.Errorf("Sparsity() = %q, want %d reasons", got, tt.want)
+			}
+		})
+	}
+}
+
+// sparseProvider records whether the sparse synthesis prompt was used and
+// answers every prompt with reply.
+type sparseProvider struct {
+	reply  string
+	sparse bool
+}
+
+func (p *sparseProvider) Complete(_ context.Context, _, prompt string, _ *llm.CompleteOptions) (string, error) {
+	if strings.Contains(prompt, "THIS PROFILE IS SPARSE") {
+		p.sparse = true
+	}
+	return p.reply, nil
+}
+
+func TestSparseProfileUsesSparseSynthesis(t *testing.T) {
+	p := &sparseProvider{reply: `{"review_voice": "Terse.", "confidence": {"review_voice": {"level": "high", "rationale": "Many reviews."}, "code_style_rules": {"level": "low", "rationale": "Four commits."}}}`}
+	persona, err := New(p).Analyze(context.Background(), "alice", promptFixture())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(persona.Sparse) == 0 {
+		t.Fatal("Sparse is empty for a four-commit crawl")
+	}
+	if !p.sparse {
+		t.Error("synthesis did not use the sparse prompt")
+	}
+	c := persona.Synthesis.Confidence
+	if c["review_voice"].Level != ConfidenceMedium || !strings.Contains(c["review_voice"].Rationale, "sparse") {
+		t.Errorf("review_voice confidence = %+v, want it capped at medium with a note", c["review_voice"])
+	}
+	if c["code_style_rules"].Level != ConfidenceLow {
+		t.Errorf("code_style_rules confidence = %+v, want it left low", c["code_style_rules"])
+	}
+}
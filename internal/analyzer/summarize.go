@@ -0,0 +1,140 @@
+package analyzer
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/drpaneas/devlica/internal/prompts"
+	"github.com/drpaneas/devlica/internal/textutil"
+	"golang.org/x/sync/errgroup"
+)
+
+// mapConcurrency bounds how many chunks of one input are summarized at once.
+// Each analysis dimension runs its own pipeline, so the total is higher.
+const mapConcurrency = 4
+
+const evidenceCompressionPrompt = `You are preparing evidence for a downstream persona analysis.
+Summarize this %s chunk into high-signal bullet points.
+
+Requirements:
+- Preserve concrete examples and exact phrasing when possible.
+- Keep what is distinctive or repeated.
+- Include counts/pattern frequencies if visible in this chunk.
+- Do not add speculation.
+
+Chunk %d/%d:
+%s`
+
+const evidenceReducePrompt = `You are preparing evidence for a downstream persona analysis.
+Merge these partial summaries of %s into one set of high-signal bullet points.
+
+Requirements:
+- Combine duplicate observations and add up their counts/frequencies.
+- Keep the most concrete examples and exact phrasing; drop weaker duplicates.
+- Keep patterns that appear in only one summary if they are distinctive.
+- Do not add speculation.
+
+Partial summaries (group %d/%d):
+%s`
+
+// compressToFit makes input fit in limit bytes without discarding data.
+// Inputs that already fit are returned unchanged. Larger inputs are split
+// into chunks that are summarized in parallel (map), then the summaries are
+// merged in batches until they fit (reduce), so every chunk informs the
+// result. It only truncates if a reduce round stops shrinking the text, says
+// so in the log, and returns how many bytes were cut.
+func (a *Analyzer) compressToFit(ctx context.Context, label, input string, limit int) (string, int, error) {
+	if input == "" || len(input) <= limit {
+		return input, 0, nil
+	}
+
+	chunkSize := a.chunkBytes()
+	chunks := splitChunks(input, chunkSize)
+	slog.Info("summarizing oversized input", "section", label, "bytes", len(input), "chunks", len(chunks))
+	summaries, err := a.completeAll(ctx, chunks, func(i int, chunk string) []prompts.Var {
+		return chunkVars(label, i, len(chunks), chunk)
+	}, "evidence-compression", evidenceCompressionPrompt)
+	if err != nil {
+		return "", 0, fmt.Errorf("summarizing %s: %w", label, err)
+	}
+
+	current := strings.Join(summaries, "\n\n")
+	for len(current) > limit {
+		groups := groupSummaries(summaries, chunkSize)
+		merged, err := a.completeAll(ctx, groups, func(i int, group string) []prompts.Var {
+			return chunkVars(label, i, len(groups), group)
+		}, "evidence-reduce", evidenceReducePrompt)
+		if err != nil {
+			return "", 0, fmt.Errorf("merging %s summaries: %w", label, err)
+		}
+		next := strings.Join(merged, "\n\n")
+		if len(next) >= len(current) {
+			slog.Warn("summaries stopped shrinking, truncating", "section", label, "bytes", len(next), "limit", limit)
+			return textutil.Truncate(next, limit, "\n... (data truncated to fit context window)"), len(next) - limit, nil
+		}
+		summaries, current = merged, next
+	}
+	return current, 0, nil
+}
+
+func chunkVars(label string, i, n int, text string) []prompts.Var {
+	return []prompts.Var{
+		prompts.Arg("Label", label),
+		prompts.Arg("Index", i+1),
+		prompts.Arg("Count", n),
+		prompts.Arg("Text", text),
+	}
+}
+
+// completeAll runs the named prompt once per input with bounded concurrency
+// and returns the outputs in input order.
+func (a *Analyzer) completeAll(ctx context.Context, inputs []string, vars func(i int, input string) []prompts.Var, name, def string) ([]string, error) {
+	out := make([]string, len(inputs))
+	g, gCtx := errgroup.WithContext(ctx)
+	g.SetLimit(mapConcurrency)
+	for i, input := range inputs {
+		g.Go(func() error {
+			res, err := a.complete(gCtx, name, def, vars(i, input)...)
+			if err != nil {
+				return err
+			}
+			out[i] = res
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// groupSummaries packs consecutive summaries into groups of at most max
+// bytes for the reduce step. A summary larger than max is split on its own.
+func groupSummaries(summaries []string, max int) []string {
+	var groups []string
+	var cur strings.Builder
+	flush := func() {
+		if cur.Len() > 0 {
+			groups = append(groups, cur.String())
+			cur.Reset()
+		}
+	}
+	for _, s := range summaries {
+		if len(s) > max {
+			flush()
+			groups = append(groups, splitChunks(s, max)...)
+			continue
+		}
+		if cur.Len() > 0 && cur.Len()+2+len(s) > max {
+			flush()
+		}
+		if cur.Len() > 0 {
+			cur.WriteString("\n\n")
+		}
+		cur.WriteString(s)
+	}
+	flush()
+	return groups
+}
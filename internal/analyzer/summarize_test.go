@@ -0,0 +1,120 @@
+package analyzer
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/drpaneas/devlica/internal/llm"
+)
+
+// recordingProvider answers every prompt with a fixed-size reply and keeps
+// the prompts it saw.
+type recordingProvider struct {
+	mu      sync.Mutex
+	prompts []string
+	reply   string
+}
+
+func (p *recordingProvider) Complete(_ context.Context, _, prompt string, _ *llm.CompleteOptions) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.prompts = append(p.prompts, prompt)
+	return p.reply, nil
+}
+
+func TestCompressToFitSmallInputUnchanged(t *testing.T) {
+	p := &recordingProvider{}
+	a := New(p)
+	got, _, err := a.compressToFit(context.Background(), "test", "short", 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "short" || len(p.prompts) != 0 {
+		t.Errorf("got %q after %d calls, want input unchanged without calls", got, len(p.prompts))
+	}
+}
+
+func TestCompressToFitMapReduce(t *testing.T) {
+	// Ten chunks whose summaries are each a third of the limit force at
+	// least one reduce round.
+	p := &recordingProvider{}
+	a := New(p)
+	limit := a.chunkBytes()
+	var parts []string
+	for i := range 10 {
+		parts = append(parts, strings.Repeat(string(rune('a'+i)), limit-10))
+	}
+	input := strings.Join(parts, "\n\n")
+	p.reply = strings.Repeat("s", limit/3)
+
+	got, discarded, err := a.compressToFit(context.Background(), "commits", input, limit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) > limit || discarded != 0 {
+		t.Errorf("result is %d bytes with %d discarded, want at most %d with none discarded", len(got), discarded, limit)
+	}
+
+	var mapped, reduced int
+	for _, prompt := range p.prompts {
+		switch {
+		case strings.Contains(prompt, "Summarize this commits chunk"):
+			mapped++
+		case strings.Contains(prompt, "Merge these partial summaries of commits"):
+			reduced++
+		}
+	}
+	if mapped < 10 {
+		t.Errorf("mapped %d chunks, want every chunk (>= 10)", mapped)
+	}
+	if reduced == 0 {
+		t.Error("expected at least one reduce call")
+	}
+	for i := range 10 {
+		marker := strings.Repeat(string(rune('a'+i)), 100)
+		found := false
+		for _, prompt := range p.prompts {
+			if strings.Contains(prompt, marker) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Errorf("chunk %d never reached the LLM", i)
+		}
+	}
+}
+
+func TestCompressToFitStopsWhenNotShrinking(t *testing.T) {
+	p := &recordingProvider{}
+	a := New(p)
+	limit := a.chunkBytes()
+	input := strings.Repeat("x\n", limit)
+	p.reply = strings.Repeat("y", limit)
+
+	got, discarded, err := a.compressToFit(context.Background(), "test", input, limit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) > limit+100 {
+		t.Errorf("result is %d bytes, want it truncated near %d", len(got), limit)
+	}
+	if discarded == 0 {
+		t.Error("expected the truncation to be reported as discarded bytes")
+	}
+}
+
+func TestGroupSummaries(t *testing.T) {
+	got := groupSummaries([]string{"aaaa", "bbbb", "cccc", strings.Repeat("d", 25)}, 10)
+	want := []string{"aaaa\n\nbbbb", "cccc", "dddddddddd", "dddddddddd", "ddddd"}
+	if len(got) != len(want) {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("group %d = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
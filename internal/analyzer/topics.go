@@ -0,0 +1,179 @@
+package analyzer
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/drpaneas/devlica/internal/ghcrawl"
+	"github.com/drpaneas/devlica/internal/llm"
+	"github.com/drpaneas/devlica/internal/textutil"
+)
+
+const (
+	// topicSimilarity is the cosine similarity at which two starred repos
+	// or issues count as the same interest. It is far looser than
+	// duplicateSimilarity: "kubernetes operator" and "helm chart" belong
+	// together, two typo nits are merely the same remark.
+	topicSimilarity = 0.6
+	// maxTopicItems bounds how many repos and issues are embedded.
+	maxTopicItems = 500
+	// maxInterestAreas is how many interest areas reach the prompt.
+	maxInterestAreas = 8
+	// representativeRepos is how many repos name each interest area.
+	representativeRepos = 3
+)
+
+// topicItem is one starred repo, authored issue, or issue comment to place
+// in an interest area.
+type topicItem struct {
+	kind   string
+	repo   string
+	text   string
+	stars  int
+	topics []string
+}
+
+// interestArea summarizes one cluster of topic items.
+type interestArea struct {
+	label   string
+	repos   []string
+	kinds   map[string]int
+	samples []string
+	size    int
+}
+
+// topicItems collects what the developer chose to follow or discuss:
+// starred repos first, then authored issues, then issue comments.
+func topicItems(data *ghcrawl.CrawlResult) []topicItem {
+	var items []topicItem
+	for _, sr := range data.StarredRepos {
+		text := sr.FullName
+		if sr.Description != "" {
+			text += ": " + sr.Description
+		}
+		if len(sr.Topics) > 0 {
+			text += " (topics: " + strings.Join(sr.Topics, ", ") + ")"
+		}
+		items = append(items, topicItem{kind: "starred repo", repo: sr.FullName, text: text, stars: sr.Stars, topics: sr.Topics})
+	}
+	for _, is := range data.AuthoredIssues {
+		items = append(items, topicItem{kind: "issue", repo: is.Repo, text: is.Repo + ": " + is.Title})
+	}
+	for _, c := range data.IssueComments {
+		body := textutil.Truncate(strings.TrimSpace(c.Body), 300, "...")
+		if body == "" {
+			continue
+		}
+		items = append(items, topicItem{kind: "issue comment", repo: c.Repo, text: c.Repo + ": " + body})
+	}
+	return items[:min(len(items), maxTopicItems)]
+}
+
+// interestAreas clusters starred repos and issue activity by embedding
+// similarity into ranked interest areas. It returns nil when the provider
+// cannot embed or there is too little to cluster.
+func (a *Analyzer) interestAreas(ctx context.Context, data *ghcrawl.CrawlResult) []interestArea {
+	embedder, ok := a.provider.(llm.Embedder)
+	if !ok {
+		return nil
+	}
+	items := topicItems(data)
+	if len(items) < 2 {
+		return nil
+	}
+	texts := make([]string, len(items))
+	for i, it := range items {
+		texts[i] = it.text
+	}
+	vectors, err := embedAll(ctx, embedder, texts)
+	if errors.Is(err, llm.ErrNoEmbedModel) {
+		return nil
+	}
+	if err != nil {
+		slog.Warn("could not embed starred repos and issues, skipping interest areas", "error", err)
+		return nil
+	}
+
+	var areas []interestArea
+	for _, members := range clusterVectors(vectors, topicSimilarity) {
+		if len(members) < 2 {
+			continue
+		}
+		areas = append(areas, newInterestArea(items, members))
+	}
+	slices.SortStableFunc(areas, func(x, y interestArea) int { return cmp.Compare(y.size, x.size) })
+	slog.Info("clustered interest areas", "items", len(items), "areas", len(areas))
+	return areas[:min(len(areas), maxInterestAreas)]
+}
+
+// newInterestArea summarizes one cluster: it is labeled by the GitHub
+// topics its starred repos share most, named by the repos that appear in it
+// most often (then by stars), and illustrated by a couple of issue titles
+// or comments.
+func newInterestArea(items []topicItem, members []int) interestArea {
+	area := interestArea{kinds: make(map[string]int), size: len(members)}
+	topicCount := make(map[string]int)
+	repoCount := make(map[string]int)
+	repoStars := make(map[string]int)
+	for _, m := range members {
+		it := items[m]
+		area.kinds[it.kind]++
+		repoCount[it.repo]++
+		repoStars[it.repo] = max(repoStars[it.repo], it.stars)
+		for _, t := range it.topics {
+			topicCount[t]++
+		}
+		if it.kind != "starred repo" && len(area.samples) < 2 {
+			area.samples = append(area.samples, it.text)
+		}
+	}
+
+	topics := slices.Collect(maps.Keys(topicCount))
+	slices.SortFunc(topics, func(x, y string) int {
+		return cmp.Or(cmp.Compare(topicCount[y], topicCount[x]), cmp.Compare(x, y))
+	})
+	area.label = strings.Join(topics[:min(len(topics), 3)], ", ")
+
+	repos := slices.Collect(maps.Keys(repoCount))
+	slices.SortFunc(repos, func(x, y string) int {
+		return cmp.Or(cmp.Compare(repoCount[y], repoCount[x]), cmp.Compare(repoStars[y], repoStars[x]), cmp.Compare(x, y))
+	})
+	area.repos = repos[:min(len(repos), representativeRepos)]
+	if area.label == "" {
+		area.label = "around " + area.repos[0]
+	}
+	return area
+}
+
+// buildInterestsText renders interest areas for the developer identity
+// prompt, largest first.
+func buildInterestsText(areas []interestArea) string {
+	if len(areas) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	for i, area := range areas {
+		var parts []string
+		for _, kind := range []string{"starred repo", "issue", "issue comment"} {
+			switch n := area.kinds[kind]; n {
+			case 0:
+			case 1:
+				parts = append(parts, "1 "+kind)
+			default:
+				parts = append(parts, fmt.Sprintf("%d %ss", n, kind))
+			}
+		}
+		fmt.Fprintf(&b, "%d. %s (%s)\n", i+1, area.label, strings.Join(parts, ", "))
+		fmt.Fprintf(&b, "   representative repos: %s\n", strings.Join(area.repos, ", "))
+		for _, s := range area.samples {
+			fmt.Fprintf(&b, "   e.g. %s\n", textutil.Truncate(s, 150, "..."))
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,62 @@
+package analyzer
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/drpaneas/devlica/internal/ghcrawl"
+)
+
+func TestInterestAreas(t *testing.T) {
+	p := &embeddingProvider{vectors: map[string][]float32{
+		"kubernetes/kubernetes: Production-Grade Container Orchestration (topics: kubernetes, containers)": {1, 0},
+		"helm/helm: The Kubernetes Package Manager (topics: kubernetes, helm)":                             {0.9, 0.2},
+		"me/operator: Operator fails to reconcile after restart":                                           {0.95, 0.1},
+		"rust-lang/rust: Empowering everyone (topics: rust, compiler)":                                     {0, 1},
+		"ziglang/zig: General-purpose language (topics: zig, compiler)":                                    {0.1, 0.9},
+		"me/lonely: something unrelated":                                                                   {-1, 0},
+	}}
+	data := &ghcrawl.CrawlResult{
+		StarredRepos: []ghcrawl.StarredRepo{
+			{FullName: "rust-lang/rust", Description: "Empowering everyone", Topics: []string{"rust", "compiler"}, Stars: 90000},
+			{FullName: "kubernetes/kubernetes", Description: "Production-Grade Container Orchestration", Topics: []string{"kubernetes", "containers"}, Stars: 100000},
+			{FullName: "helm/helm", Description: "The Kubernetes Package Manager", Topics: []string{"kubernetes", "helm"}, Stars: 25000},
+			{FullName: "ziglang/zig", Description: "General-purpose language", Topics: []string{"zig", "compiler"}, Stars: 30000},
+		},
+		AuthoredIssues: []ghcrawl.IssueData{
+			{Repo: "me/operator", Title: "Operator fails to reconcile after restart"},
+		},
+		IssueComments: []ghcrawl.Comment{{Repo: "me/lonely", Body: "something unrelated"}},
+	}
+
+	areas := New(p).interestAreas(context.Background(), data)
+	if len(areas) != 2 {
+		t.Fatalf("got %d areas, want 2 (singletons dropped): %+v", len(areas), areas)
+	}
+	k8s := areas[0]
+	if k8s.size != 3 || !strings.HasPrefix(k8s.label, "kubernetes") {
+		t.Errorf("first area = %+v, want the three kubernetes items labeled kubernetes", k8s)
+	}
+	if k8s.repos[0] != "kubernetes/kubernetes" {
+		t.Errorf("representative repos = %v, want the most starred first", k8s.repos)
+	}
+
+	text := buildInterestsText(areas)
+	for _, want := range []string{
+		"1. kubernetes, containers, helm (2 starred repos, 1 issue)",
+		"e.g. me/operator: Operator fails to reconcile after restart",
+		"2. compiler, rust, zig (2 starred repos)",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("interests text missing %q:\n%s", want, text)
+		}
+	}
+}
+
+func TestInterestAreasWithoutEmbedder(t *testing.T) {
+	data := &ghcrawl.CrawlResult{StarredRepos: []ghcrawl.StarredRepo{{FullName: "a/b"}, {FullName: "c/d"}}}
+	if areas := New(&recordingProvider{}).interestAreas(context.Background(), data); areas != nil {
+		t.Errorf("got %+v, want nil without an embedder", areas)
+	}
+}
@@ -0,0 +1,74 @@
+package analyzer
+
+import (
+	"math"
+	"strings"
+
+	"github.com/drpaneas/devlica/internal/ghcrawl"
+)
+
+// maxRepoWeight caps how many items one repo contributes per interleave
+// round, so a flagship repo gets more room without crowding the rest out.
+const maxRepoWeight = 4
+
+// repoWeights ranks repos for sharing a truncated chunk. Every repo starts
+// at 1. A repo gains up to 2 for how much of the developer's code is in its
+// primary language, and 1 more for being a flagship: the repo with the most
+// stars or the one with the most of their commits. One-off experiments in a
+// language they rarely use stay at 1.
+func repoWeights(data *ghcrawl.CrawlResult) map[string]int {
+	langBytes := make(map[string]int)
+	total := 0
+	for _, repo := range data.Repos {
+		for lang, n := range repo.Languages {
+			langBytes[strings.ToLower(lang)] += n
+			total += n
+		}
+	}
+	var mostStars, mostCommits string
+	stars, commits := 0, 0
+	for _, repo := range data.Repos {
+		if repo.Stars > stars {
+			mostStars, stars = repo.FullName, repo.Stars
+		}
+		if len(repo.Commits) > commits {
+			mostCommits, commits = repo.FullName, len(repo.Commits)
+		}
+	}
+
+	weights := make(map[string]int, len(data.Repos))
+	for _, repo := range data.Repos {
+		w := 1
+		if total > 0 && repo.Language != "" {
+			share := float64(langBytes[strings.ToLower(repo.Language)]) / float64(total)
+			w += int(math.Round(2 * share))
+		}
+		if repo.FullName == mostStars || repo.FullName == mostCommits {
+			w++
+		}
+		weights[repo.FullName] = min(w, maxRepoWeight)
+	}
+	return weights
+}
+
+// interleaveWeighted is interleave where each round takes up to weights[i]
+// items from bucket i instead of one. A weight below 1 counts as 1.
+func interleaveWeighted(buckets [][]string, weights []int) string {
+	var b strings.Builder
+	next := make([]int, len(buckets))
+	for remaining := true; remaining; {
+		remaining = false
+		for i, bucket := range buckets {
+			take := max(weights[i], 1)
+			end := min(next[i]+take, len(bucket))
+			for _, item := range bucket[next[i]:end] {
+				b.WriteString(item)
+			}
+			next[i] = end
+			if end < len(bucket) {
+				remaining = true
+			}
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,74 @@
+package analyzer
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/drpaneas/devlica/internal/ghcrawl"
+)
+
+func TestRepoWeights(t *testing.T) {
+	data := &ghcrawl.CrawlResult{Repos: []ghcrawl.RepoData{
+		{FullName: "alice/flagship", Language: "Go", Languages: map[string]int{"Go": 9000}, Stars: 500},
+		{FullName: "alice/tool", Language: "Go", Languages: map[string]int{"Go": 800}},
+		{FullName: "alice/experiment", Language: "Haskell", Languages: map[string]int{"Haskell": 200}},
+		{FullName: "alice/dotfiles"},
+	}}
+	want := map[string]int{
+		"alice/flagship":   4,
+		"alice/tool":       3,
+		"alice/experiment": 1,
+		"alice/dotfiles":   1,
+	}
+	got := repoWeights(data)
+	for repo, w := range want {
+		if got[repo] != w {
+			t.Errorf("weight of %s = %d, want %d", repo, got[repo], w)
+		}
+	}
+}
+
+func TestInterleaveWeighted(t *testing.T) {
+	buckets := [][]string{
+		{"A1-", "A2-", "A3-", "A4-"},
+		{"B1-", "B2-"},
+		{"C1-"},
+	}
+	got := interleaveWeighted(buckets, []int{3, 1, 0})
+	want := "A1-A2-A3-B1-C1-A4-B2-"
+	if got != want {
+		t.Errorf("interleaveWeighted = %q, want %q", got, want)
+	}
+	if got := interleaveWeighted(buckets, []int{1, 1, 1}); got != interleave(buckets) {
+		t.Errorf("equal weights = %q, want plain interleave %q", got, interleave(buckets))
+	}
+}
+
+func TestBuildCommitDiffsTextFavorsDominantRepos(t *testing.T) {
+	commits := func(n int) []ghcrawl.CommitData {
+		var cs []ghcrawl.CommitData
+		for range n {
+			cs = append(cs, ghcrawl.CommitData{SHA: "abc", Message: "change", Patch: "+x"})
+		}
+		return cs
+	}
+	data := &ghcrawl.CrawlResult{Repos: []ghcrawl.RepoData{
+		{FullName: "alice/experiment", Language: "Haskell", Languages: map[string]int{"Haskell": 100}, Commits: commits(4)},
+		{FullName: "alice/main", Language: "Go", Languages: map[string]int{"Go": 9900}, Stars: 10, Commits: commits(6)},
+	}}
+	got := buildCommitDiffsText(data)
+	headers := strings.Split(got, "=== ")[1:]
+	var firstFive []string
+	for _, h := range headers[:5] {
+		repo, _, _ := strings.Cut(h, " ")
+		firstFive = append(firstFive, repo)
+	}
+	want := []string{"alice/experiment", "alice/main", "alice/main", "alice/main", "alice/main"}
+	if !slices.Equal(firstFive, want) {
+		t.Errorf("first five diffs come from %v, want %v", firstFive, want)
+	}
+	if len(headers) != 10 {
+		t.Errorf("got %d diffs, want all 10", len(headers))
+	}
+}
@@ -0,0 +1,49 @@
+package benchmark
+
+import (
+	"fmt"
+
+	"github.com/drpaneas/devlica/internal/analyzer"
+)
+
+// baselineName stands in for the developer's username in baseline
+// prompts, so the model cannot fall back on what it knows about them.
+const baselineName = "senior-engineer"
+
+// baselinePersona is a generic senior engineer with none of the
+// developer's traits. Scored on the same samples, it shows how much of the
+// persona's score any competent imitation would get.
+func baselinePersona() *analyzer.Persona {
+	return &analyzer.Persona{
+		Username: baselineName,
+		Synthesis: &analyzer.SynthesisResult{
+			CodingPhilosophy:      "Pragmatic: correct, readable, maintainable code over cleverness.",
+			CodeStyleRules:        "Follows the project's existing conventions and the common idioms of the language.",
+			CommitMessageStyle:    "Imperative subject line under 72 characters, with a short body explaining why when it helps.",
+			ReviewPriorities:      "Correctness, edge cases, error handling, tests, and readability.",
+			ReviewDecisionStyle:   "Approves sound changes; requests changes for bugs or missing tests.",
+			ReviewNonBlockingNits: "Marks naming and style suggestions as optional.",
+			ReviewVoice:           "Polite, constructive, and professional.",
+			CommunicationPatterns: "Clear, courteous, and to the point.",
+			TestingPhilosophy:     "New behavior comes with unit tests.",
+		},
+	}
+}
+
+// Lift is how many points the final persona scored above the baseline, or
+// 0 when no baseline was scored.
+func (r *Result) Lift() float64 {
+	if r.Baseline == nil {
+		return 0
+	}
+	return r.FinalScore - r.Baseline.Score
+}
+
+// FormatBaseline renders the baseline score and the persona's lift over
+// it as "baseline=52.0 lift=+18.5", or "" when no baseline was scored.
+func (r *Result) FormatBaseline() string {
+	if r.Baseline == nil {
+		return ""
+	}
+	return fmt.Sprintf("baseline=%.1f lift=%+.1f", r.Baseline.Score, r.Lift())
+}
@@ -0,0 +1,52 @@
+package benchmark
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/drpaneas/devlica/internal/analyzer"
+	"github.com/drpaneas/devlica/internal/llm"
+)
+
+// baselineProvider reviews as whoever the prompt impersonates and scores
+// the generic reviews lower.
+type baselineProvider struct{}
+
+func (baselineProvider) Complete(_ context.Context, system, prompt string, _ *llm.CompleteOptions) (string, error) {
+	switch {
+	case strings.Contains(system, "two code review comments") && strings.Contains(prompt, "generic"):
+		return `{"score":40,"feedback":"bland"}`, nil
+	case strings.Contains(system, "two code review comments"):
+		return `{"score":75,"feedback":"close"}`, nil
+	case strings.Contains(prompt, "impersonating developer "+baselineName):
+		return `{"decision":"comment","concerns":[],"comment":"generic"}`, nil
+	default:
+		return `{"decision":"comment","concerns":[],"comment":"nit: rename"}`, nil
+	}
+}
+
+func TestRunScoresBaseline(t *testing.T) {
+	b := New(baselineProvider{})
+	b.SetMaxIterations(1)
+	b.SetBaseline(true)
+	persona := &analyzer.Persona{Username: "alice", Synthesis: &analyzer.SynthesisResult{ReviewVoice: "terse"}}
+	heldOut := HeldOut{Reviews: []HeldOutReview{{Path: "main.go", DiffHunk: "@@ -1 +1 @@", Body: "nit: rename"}}}
+
+	result, _, err := b.Run(context.Background(), persona, heldOut)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Baseline == nil || result.Baseline.Score != 40 {
+		t.Fatalf("baseline = %+v, want a score of 40", result.Baseline)
+	}
+	if result.Lift() != 35 {
+		t.Errorf("Lift() = %v, want 35", result.Lift())
+	}
+	if got := result.FormatBaseline(); got != "baseline=40.0 lift=+35.0" {
+		t.Errorf("FormatBaseline() = %q", got)
+	}
+	if len(result.History) != 1 {
+		t.Errorf("baseline should not count as an iteration: %d iterations", len(result.History))
+	}
+}
@@ -10,15 +10,35 @@ import (
 	"github.com/drpaneas/devlica/internal/analyzer"
 	"github.com/drpaneas/devlica/internal/ghcrawl"
 	"github.com/drpaneas/devlica/internal/llm"
+	"github.com/drpaneas/devlica/internal/progress"
+	"github.com/drpaneas/devlica/internal/prompts"
 	"github.com/drpaneas/devlica/internal/textutil"
+	"golang.org/x/sync/errgroup"
 )
 
+// Defaults for how many samples of each facet are held out at most, how
+// many times the persona is scored at most, and the score that ends
+// refinement early.
 const (
-	MaxHeldOut    = 3
+	MaxHeldOut    = 10
 	MaxIterations = 5
 	TargetScore   = 80.0
+	// PlateauGain is the least score gain over two iterations that keeps
+	// refining worthwhile.
+	PlateauGain = 1.0
 )
 
+// Reasons the benchmark loop stops.
+const (
+	StopTarget     = "target"
+	StopPlateau    = "plateau"
+	StopIterations = "iterations"
+)
+
+// evalConcurrency bounds how many held-out samples are generated and
+// judged at once.
+const evalConcurrency = 4
+
 // HeldOutReview is a review comment withheld from persona building for validation.
 type HeldOutReview struct {
 	RepoFullName string
@@ -27,33 +47,84 @@ type HeldOutReview struct {
 	DiffHunk     string
 }
 
-// ReviewPair pairs a held-out original review with its dry-run counterpart.
+// ReviewPair pairs a held-out original with its dry-run counterpart. Path
+// is the reviewed file for a review, or a short label for other facets.
 type ReviewPair struct {
-	Original  string
-	Generated string
-	Path      string
-	Score     float64
+	Original  string  `json:"original"`
+	Generated string  `json:"generated"`
+	Path      string  `json:"path"`
+	Score     float64 `json:"score"`
+	Facet     Facet   `json:"facet"`
+	// JudgeScores holds each judge's score when several judged the pair.
+	JudgeScores map[string]float64 `json:"judge_scores,omitempty"`
+	// Feedback is the judges' explanation of Score.
+	Feedback string `json:"feedback"`
+	// Human is a person's rating, when one was given; Score then blends
+	// it with LLMScore, the judges' score.
+	Human    *HumanScore `json:"human,omitempty"`
+	LLMScore float64     `json:"llm_score,omitempty"`
+	// Subscores holds a review's score on each rubric dimension.
+	Subscores map[string]float64 `json:"subscores,omitempty"`
 }
 
+// Schemas of the JSON replies the benchmark parses, for providers that can
+// constrain their output to one.
+var (
+	dryRunSchema = json.RawMessage(`{"type":"object","properties":{` +
+		`"decision":{"type":"string","enum":["approve","request_changes","comment"]},` +
+		`"concerns":{"type":"array","items":{"type":"string"}},` +
+		`"comment":{"type":"string"}},"required":["decision","concerns","comment"]}`)
+	comparisonSchema = json.RawMessage(`{"type":"object","properties":{` +
+		`"score":{"type":"number","minimum":0,"maximum":100},` +
+		`"feedback":{"type":"string"}},"required":["score","feedback"]}`)
+	reviewComparisonSchema = json.RawMessage(`{"type":"object","properties":{` +
+		`"subscores":{"type":"object","properties":{` +
+		`"concern":{"type":"number"},"severity":{"type":"number"},"actionability":{"type":"number"},` +
+		`"tone":{"type":"number"},"accuracy":{"type":"number"}},` +
+		`"required":["concern","severity","actionability","tone","accuracy"]},` +
+		`"score":{"type":"number","minimum":0,"maximum":100},` +
+		`"feedback":{"type":"string"}},"required":["subscores","score","feedback"]}`)
+)
+
 type dryRunReview struct {
 	Decision string   `json:"decision"`
 	Concerns []string `json:"concerns"`
 	Comment  string   `json:"comment"`
 }
 
-// IterationResult holds the outcome of a single benchmark iteration.
+// IterationResult holds the outcome of a single benchmark iteration. Score
+// is the mean of FacetScores, so each facet with samples counts equally.
 type IterationResult struct {
-	Iteration int
-	Score     float64
-	Feedback  string
-	Pairs     []ReviewPair
+	Iteration   int               `json:"iteration"`
+	Score       float64           `json:"score"`
+	Feedback    string            `json:"feedback"`
+	Pairs       []ReviewPair      `json:"pairs"`
+	FacetScores map[Facet]float64 `json:"facet_scores"`
+	// JudgeScores holds the score each judge alone would have given, by
+	// judge name, when several judges scored the iteration.
+	JudgeScores map[string]float64 `json:"judge_scores,omitempty"`
+	// Synthesis is the persona version the iteration scored.
+	Synthesis *analyzer.SynthesisResult `json:"synthesis,omitempty"`
+	// Subscores holds the mean review score on each rubric dimension.
+	Subscores map[string]float64 `json:"subscores,omitempty"`
 }
 
-// Result holds the overall benchmark outcome.
+// Result holds the overall benchmark outcome. FinalScore is the score of
+// the Selected iteration, whose persona Run returns.
 type Result struct {
-	FinalScore float64
-	Iterations int
-	History    []IterationResult
+	FinalScore float64           `json:"final_score"`
+	Iterations int               `json:"iterations"`
+	History    []IterationResult `json:"history"`
+	// Selected is the best-scoring iteration, the earliest on a tie.
+	Selected int `json:"selected"`
+	// StopReason says why the loop ended: StopTarget, StopPlateau, or
+	// StopIterations.
+	StopReason string `json:"stop_reason,omitempty"`
+	// Baseline, when scored, is a generic persona's result on the same
+	// samples.
+	Baseline *IterationResult `json:"baseline,omitempty"`
+	// Calibration, when several judges scored, compares their scores.
+	Calibration *Calibration `json:"calibration,omitempty"`
 }
 
 // SplitReviews removes up to max reviews that have non-empty DiffHunks from data
@@ -84,99 +155,326 @@ func SplitReviews(data *ghcrawl.CrawlResult, max int) []HeldOutReview {
 // Benchmarker validates persona quality by generating dry-run reviews and
 // comparing them against held-out originals.
 type Benchmarker struct {
-	provider llm.Provider
+	provider      llm.Provider
+	prompts       *prompts.Set
+	progress      progress.Func
+	targetScore   float64
+	maxIterations int
+	// plateau is the least gain over two iterations that keeps refining.
+	plateau float64
+	// judges, when set, score the dry runs instead of provider, so a model
+	// does not grade its own impersonations. The first also refines.
+	judges    []Judge
+	aggregate Aggregate
+	// baseline scores a generic persona first, for comparison.
+	baseline bool
+	// rate, when set, asks a person to score each pair as well.
+	rate        Rater
+	humanWeight float64
+	// rubric weights the review dimensions; nil weighs them equally.
+	rubric Rubric
 }
 
-// New returns a Benchmarker that uses the given LLM provider.
+// New returns a Benchmarker that uses the given LLM provider, with the
+// default TargetScore, MaxIterations, and PlateauGain.
 func New(provider llm.Provider) *Benchmarker {
-	return &Benchmarker{provider: provider}
+	return &Benchmarker{provider: provider, targetScore: TargetScore, maxIterations: MaxIterations, plateau: PlateauGain}
+}
+
+// SetTarget sets the score, out of 100, at which refinement stops.
+func (b *Benchmarker) SetTarget(score float64) {
+	b.targetScore = score
+}
+
+// SetMaxIterations bounds how many times the persona is scored; every
+// iteration but the last also refines it. n below 1 counts as 1.
+func (b *Benchmarker) SetMaxIterations(n int) {
+	b.maxIterations = max(n, 1)
+}
+
+// SetBaseline makes Run also score a generic senior engineer persona on
+// the held-out samples, so the result reports the persona's lift over it.
+func (b *Benchmarker) SetBaseline(on bool) {
+	b.baseline = on
+}
+
+// SetPlateau sets the least score gain over two iterations that keeps the
+// loop refining; 0 never stops early on a plateau.
+func (b *Benchmarker) SetPlateau(gain float64) {
+	b.plateau = gain
+}
+
+// SetPrompts makes the benchmarker prefer the given prompt overrides over
+// its compiled-in prompts.
+func (b *Benchmarker) SetPrompts(s *prompts.Set) {
+	b.prompts = s
+}
+
+// SetProgress makes the benchmarker report each scoring and refinement
+// iteration to fn as it starts and finishes.
+func (b *Benchmarker) SetProgress(fn progress.Func) {
+	b.progress = fn
+}
+
+// PromptNames lists the benchmark prompts that can be overridden.
+var PromptNames = []string{
+	"dry-run-system", "dry-run-review", "compare-system", "compare", "refine-system", "refine",
+	"write-system", "write", "compare-writing-system", "compare-writing", "reply", "compare-reply",
+}
+
+// complete renders the named system and user prompts, falling back to the
+// given defaults, and sends them to provider.
+func (b *Benchmarker) complete(ctx context.Context, provider llm.Provider, systemName, systemDef, name, def string, schema json.RawMessage, vars ...prompts.Var) (string, error) {
+	system, err := b.prompts.Render(systemName, systemDef)
+	if err != nil {
+		return "", err
+	}
+	prompt, err := b.prompts.Render(name, def, vars...)
+	if err != nil {
+		return "", err
+	}
+	resp, err := provider.Complete(ctx, system, prompt, &llm.CompleteOptions{JSONSchema: schema})
+	progress.Record(ctx, system+prompt, resp)
+	return resp, err
 }
 
 // Run performs the benchmark loop: for each iteration it generates dry-run
-// reviews using the persona, compares them with the originals, scores the
+// reviews, PR descriptions, commit messages, and issue replies using the persona, compares
+// them with the originals, scores the
 // match, and refines the persona if the score is below the target. It runs
-// at most MaxIterations times. Returns the benchmark result and a potentially
-// refined persona.
-func (b *Benchmarker) Run(ctx context.Context, persona *analyzer.Persona, heldOut []HeldOutReview) (*Result, *analyzer.Persona, error) {
-	if len(heldOut) == 0 {
-		slog.Warn("no held-out reviews available, skipping benchmark")
+// at most MaxIterations times unless SetMaxIterations says otherwise. Returns the benchmark result and the
+// best-scoring persona, which a refinement that made things worse does not
+// replace.
+func (b *Benchmarker) Run(ctx context.Context, persona *analyzer.Persona, heldOut HeldOut) (*Result, *analyzer.Persona, error) {
+	if heldOut.Len() == 0 {
+		slog.Warn("no held-out samples available, skipping benchmark")
 		return &Result{FinalScore: -1}, persona, nil
 	}
 
 	result := &Result{}
 	current := clonePersona(persona)
+	best := current
 
-	for iter := 1; iter <= MaxIterations; iter++ {
-		slog.Info("benchmark iteration", "iteration", iter, "max", MaxIterations)
+	if b.baseline {
+		err := b.progress.Step(ctx, "benchmark baseline", func(ctx context.Context) error {
+			var err error
+			result.Baseline, err = b.runIteration(ctx, baselinePersona(), heldOut, 0)
+			return err
+		})
+		if err != nil {
+			return nil, nil, fmt.Errorf("benchmark baseline: %w", err)
+		}
+		slog.Info("benchmark baseline score", "score", fmt.Sprintf("%.1f", result.Baseline.Score))
+	}
+
+	for iter := 1; iter <= b.maxIterations; iter++ {
+		slog.Info("benchmark iteration", "iteration", iter, "max", b.maxIterations)
 
-		iterResult, err := b.runIteration(ctx, current, heldOut, iter)
+		var iterResult *IterationResult
+		err := b.progress.Step(ctx, fmt.Sprintf("benchmark iteration %d", iter), func(ctx context.Context) error {
+			var err error
+			iterResult, err = b.runIteration(ctx, current, heldOut, iter)
+			return err
+		})
 		if err != nil {
 			return nil, nil, fmt.Errorf("benchmark iteration %d: %w", iter, err)
 		}
 
 		result.History = append(result.History, *iterResult)
-		result.FinalScore = iterResult.Score
 		result.Iterations = iter
+		if result.Selected == 0 || iterResult.Score > result.FinalScore {
+			result.FinalScore = iterResult.Score
+			result.Selected = iter
+			best = current
+		}
 
 		slog.Info("benchmark score", "iteration", iter, "score", fmt.Sprintf("%.1f", iterResult.Score))
 
-		if iterResult.Score >= TargetScore {
-			slog.Info("benchmark target reached", "score", fmt.Sprintf("%.1f", iterResult.Score))
+		if stop, why := b.stopReason(result.History); stop != "" {
+			result.StopReason = stop
+			slog.Info("benchmark stopped", "iteration", iter, "reason", why)
 			break
 		}
 
-		if iter < MaxIterations {
-			slog.Info("refining persona", "iteration", iter)
-			refined, err := b.refinePersona(ctx, current, iterResult)
-			if err != nil {
-				return nil, nil, fmt.Errorf("refining persona (iter %d): %w", iter, err)
-			}
-			current = refined
+		slog.Info("refining persona", "iteration", iter)
+		var refined *analyzer.Persona
+		err = b.progress.Step(ctx, fmt.Sprintf("refine iteration %d", iter), func(ctx context.Context) error {
+			var err error
+			refined, err = b.refinePersona(ctx, current, iterResult)
+			return err
+		})
+		if err != nil {
+			return nil, nil, fmt.Errorf("refining persona (iter %d): %w", iter, err)
 		}
+		current = refined
 	}
 
-	return result, current, nil
+	if result.Selected != result.Iterations {
+		slog.Info("keeping best-scoring persona", "iteration", result.Selected, "score", fmt.Sprintf("%.1f", result.FinalScore))
+	}
+	if result.Calibration = b.calibrate(result); result.Calibration != nil {
+		slog.Info("benchmark judge calibration", "calibrated_score", fmt.Sprintf("%.1f", result.Calibration.Score),
+			"spread", fmt.Sprintf("%.1f", result.Calibration.Spread), "offsets", result.Calibration.formatOffsets())
+	}
+	return result, best, nil
 }
 
-func (b *Benchmarker) runIteration(ctx context.Context, persona *analyzer.Persona, heldOut []HeldOutReview, iter int) (*IterationResult, error) {
-	iterResult := &IterationResult{Iteration: iter}
-	var totalScore float64
-	var feedbackParts []string
-
-	for _, ho := range heldOut {
-		generated, err := b.generateDryRunReview(ctx, persona, ho)
-		if err != nil {
-			return nil, fmt.Errorf("dry-run review: %w", err)
+// stopReason reports whether the loop should stop after the last scored
+// iteration in history, as one of the Stop reasons and an explanation
+// for the log, or "" to refine and go on.
+func (b *Benchmarker) stopReason(history []IterationResult) (string, string) {
+	last := history[len(history)-1]
+	switch {
+	case last.Score >= b.targetScore:
+		return StopTarget, fmt.Sprintf("score %.1f reached the target of %.1f", last.Score, b.targetScore)
+	case len(history) >= b.maxIterations:
+		return StopIterations, fmt.Sprintf("ran all %d iterations", b.maxIterations)
+	case b.plateau > 0 && len(history) >= 3:
+		gain := last.Score - history[len(history)-3].Score
+		if gain < b.plateau {
+			return StopPlateau, fmt.Sprintf("score plateaued: %+.1f over the last two refinements, under %.1f", gain, b.plateau)
 		}
+	}
+	return "", ""
+}
 
-		comp, err := b.compareReviews(ctx, ho, generated)
-		if err != nil {
-			return nil, fmt.Errorf("comparison: %w", err)
+func (b *Benchmarker) runIteration(ctx context.Context, persona *analyzer.Persona, heldOut HeldOut, iter int) (*IterationResult, error) {
+	iterResult := &IterationResult{Iteration: iter, Synthesis: persona.Synthesis}
+	samples := heldOut.writingSamples()
+	pairs := make([]ReviewPair, len(heldOut.Reviews)+len(samples))
+	g, gCtx := errgroup.WithContext(ctx)
+	g.SetLimit(evalConcurrency)
+	for i, ho := range heldOut.Reviews {
+		g.Go(func() error {
+			generated, err := b.generateDryRunReview(gCtx, persona, ho)
+			if err != nil {
+				return fmt.Errorf("dry-run review: %w", err)
+			}
+			comp, err := b.judge(func(judge llm.Provider) (*comparisonResult, error) {
+				return b.compareReviews(gCtx, judge, ho, generated)
+			})
+			if err != nil {
+				return fmt.Errorf("comparison: %w", err)
+			}
+			pairs[i] = ReviewPair{
+				Original:    ho.Body,
+				Generated:   formatGeneratedReview(generated),
+				Path:        ho.Path,
+				Score:       comp.score,
+				Facet:       FacetReview,
+				JudgeScores: comp.byJudge,
+				Feedback:    comp.feedback,
+				Subscores:   comp.subscores,
+			}
+			return nil
+		})
+	}
+	for i, s := range samples {
+		g.Go(func() error {
+			generated, err := b.generateWriting(gCtx, persona, s)
+			if err != nil {
+				return fmt.Errorf("dry-run %s: %w", s.kind, err)
+			}
+			comp, err := b.judge(func(judge llm.Provider) (*comparisonResult, error) {
+				return b.compareWriting(gCtx, judge, s, generated)
+			})
+			if err != nil {
+				return fmt.Errorf("%s comparison: %w", s.kind, err)
+			}
+			pairs[len(heldOut.Reviews)+i] = ReviewPair{
+				Original:    s.original,
+				Generated:   generated,
+				Path:        s.label,
+				Score:       comp.score,
+				Facet:       s.facet,
+				JudgeScores: comp.byJudge,
+				Feedback:    comp.feedback,
+			}
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	if b.rate != nil {
+		if err := b.rateHuman(ctx, pairs); err != nil {
+			return nil, err
 		}
+	}
 
-		iterResult.Pairs = append(iterResult.Pairs, ReviewPair{
-			Original:  ho.Body,
-			Generated: formatGeneratedReview(generated),
-			Path:      ho.Path,
-			Score:     comp.score,
-		})
-		totalScore += comp.score
-		feedbackParts = append(feedbackParts, comp.feedback)
+	totals := make(map[Facet]float64)
+	counts := make(map[Facet]int)
+	judgeTotals := make(map[string]map[Facet]float64)
+	subTotals := make(map[string]float64)
+	subCounts := make(map[string]int)
+	var feedbackParts []string
+	for _, pair := range pairs {
+		for d, s := range pair.Subscores {
+			subTotals[d] += s
+			subCounts[d]++
+		}
+		slog.Info("benchmark sample", "iteration", iter, "facet", pair.Facet, "sample", pair.Path, "score", fmt.Sprintf("%.0f", pair.Score))
+		iterResult.Pairs = append(iterResult.Pairs, pair)
+		totals[pair.Facet] += pair.Score
+		counts[pair.Facet]++
+		for name, score := range pair.JudgeScores {
+			if judgeTotals[name] == nil {
+				judgeTotals[name] = make(map[Facet]float64)
+			}
+			judgeTotals[name][pair.Facet] += score
+		}
+		feedbackParts = append(feedbackParts, pair.Feedback)
 	}
 
-	iterResult.Score = totalScore / float64(len(heldOut))
+	iterResult.FacetScores = make(map[Facet]float64, len(counts))
+	var sum float64
+	for facet, n := range counts {
+		iterResult.FacetScores[facet] = totals[facet] / float64(n)
+		sum += iterResult.FacetScores[facet]
+	}
+	iterResult.Score = sum / float64(len(counts))
+	if len(judgeTotals) > 0 {
+		iterResult.JudgeScores = make(map[string]float64, len(judgeTotals))
+		for name, byFacet := range judgeTotals {
+			var judgeSum float64
+			for facet, total := range byFacet {
+				judgeSum += total / float64(counts[facet])
+			}
+			iterResult.JudgeScores[name] = judgeSum / float64(len(counts))
+		}
+	}
+	if len(subTotals) > 0 {
+		iterResult.Subscores = make(map[string]float64, len(subTotals))
+		for d, total := range subTotals {
+			iterResult.Subscores[d] = total / float64(subCounts[d])
+		}
+	}
 	iterResult.Feedback = strings.Join(feedbackParts, "\n---\n")
 	return iterResult, nil
 }
 
+// FormatFacets renders the per-facet scores as "review=72.0 commit_message=55.5"
+// in Facets order, or "" when only one facet was scored.
+func (r IterationResult) FormatFacets() string {
+	if len(r.FacetScores) < 2 {
+		return ""
+	}
+	var parts []string
+	for _, f := range Facets {
+		if score, ok := r.FacetScores[f]; ok {
+			parts = append(parts, fmt.Sprintf("%s=%.1f", f, score))
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
 func (b *Benchmarker) generateDryRunReview(ctx context.Context, persona *analyzer.Persona, ho HeldOutReview) (*dryRunReview, error) {
-	prompt := fmt.Sprintf(dryRunReviewPrompt,
-		persona.Username,
-		formatPersonaContext(persona),
-		ho.Path,
-		ho.DiffHunk,
+	raw, err := b.complete(ctx, b.provider, "dry-run-system", dryRunSystemPrompt, "dry-run-review", dryRunReviewPrompt, dryRunSchema,
+		prompts.Arg("Username", persona.Username),
+		prompts.Arg("Persona", formatPersonaContext(persona)),
+		prompts.Arg("Path", ho.Path),
+		prompts.Arg("DiffHunk", ho.DiffHunk),
 	)
-	raw, err := b.provider.Complete(ctx, dryRunSystemPrompt, prompt, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -186,52 +484,70 @@ func (b *Benchmarker) generateDryRunReview(ctx context.Context, persona *analyze
 type comparisonResult struct {
 	score    float64
 	feedback string
+	// subscores holds a review comparison's rubric scores, if any.
+	subscores map[string]float64
 }
 
-func (b *Benchmarker) compareReviews(ctx context.Context, ho HeldOutReview, generated *dryRunReview) (*comparisonResult, error) {
-	prompt := fmt.Sprintf(comparePrompt,
-		ho.Path,
-		ho.DiffHunk,
-		ho.Body,
-		formatGeneratedReview(generated),
+func (b *Benchmarker) compareReviews(ctx context.Context, judge llm.Provider, ho HeldOutReview, generated *dryRunReview) (*comparisonResult, error) {
+	raw, err := b.complete(ctx, judge, "compare-system", compareSystemPrompt, "compare", comparePrompt, reviewComparisonSchema,
+		prompts.Arg("Path", ho.Path),
+		prompts.Arg("DiffHunk", ho.DiffHunk),
+		prompts.Arg("Original", ho.Body),
+		prompts.Arg("Generated", formatGeneratedReview(generated)),
 	)
-	raw, err := b.provider.Complete(ctx, compareSystemPrompt, prompt, nil)
 	if err != nil {
 		return nil, err
 	}
-	return parseComparisonResult(raw)
+	comp, err := parseComparisonResult(raw)
+	if err != nil {
+		return nil, err
+	}
+	// The judge's overall score is only a fallback: the rubric decides how
+	// much each dimension counts.
+	if score, ok := b.rubric.combine(comp.subscores); ok {
+		comp.score = score
+	}
+	return comp, nil
 }
 
 func (b *Benchmarker) refinePersona(ctx context.Context, persona *analyzer.Persona, iter *IterationResult) (*analyzer.Persona, error) {
 	var pairsSummary strings.Builder
 	for i, pair := range iter.Pairs {
-		fmt.Fprintf(&pairsSummary, "--- Review Pair %d (file: %s, score: %.0f) ---\n", i+1, pair.Path, pair.Score)
+		if pair.Facet == FacetReview || pair.Facet == "" {
+			fmt.Fprintf(&pairsSummary, "--- Review Pair %d (file: %s, score: %.0f) ---\n", i+1, pair.Path, pair.Score)
+		} else {
+			fmt.Fprintf(&pairsSummary, "--- Writing Pair %d (%s, score: %.0f) ---\n", i+1, pair.Path, pair.Score)
+		}
 		fmt.Fprintf(&pairsSummary, "ORIGINAL:\n%s\n\nGENERATED:\n%s\n\n", pair.Original, pair.Generated)
 	}
 
 	s := persona.Synthesis
-	prompt := fmt.Sprintf(refinePrompt,
-		persona.Username,
-		iter.Score,
-		s.CodingPhilosophy,
-		s.CodeStyleRules,
-		s.ReviewPriorities,
-		s.ReviewDecisionStyle,
-		s.ReviewNonBlockingNits,
-		s.ReviewContext,
-		s.ReviewVoice,
-		s.CommunicationPatterns,
-		s.TestingPhilosophy,
-		s.DistinctiveTraits,
-		s.DeveloperInterests,
-		s.ActivityPatterns,
-		s.ProjectPatterns,
-		s.CollaborationStyle,
-		iter.Feedback,
-		pairsSummary.String(),
+	raw, err := b.complete(ctx, b.judgeProvider(), "refine-system", refineSystemPrompt, "refine", refinePrompt, analyzer.SynthesisSchema,
+		prompts.Arg("Username", persona.Username),
+		prompts.Arg("Score", iter.Score),
+		prompts.Arg("CodingPhilosophy", s.CodingPhilosophy),
+		prompts.Arg("CodeStyleRules", s.CodeStyleRules),
+		prompts.Arg("CommitMessageStyle", s.CommitMessageStyle),
+		prompts.Arg("ReviewPriorities", s.ReviewPriorities),
+		prompts.Arg("ReviewDecisionStyle", s.ReviewDecisionStyle),
+		prompts.Arg("ReviewNonBlockingNits", s.ReviewNonBlockingNits),
+		prompts.Arg("ReviewContext", s.ReviewContext),
+		prompts.Arg("ReviewVoice", s.ReviewVoice),
+		prompts.Arg("CommunicationPatterns", s.CommunicationPatterns),
+		prompts.Arg("TestingPhilosophy", s.TestingPhilosophy),
+		prompts.Arg("DistinctiveTraits", s.DistinctiveTraits),
+		prompts.Arg("AntiPatterns", s.AntiPatterns),
+		prompts.Arg("DeveloperInterests", s.DeveloperInterests),
+		prompts.Arg("ActivityPatterns", s.ActivityPatterns),
+		prompts.Arg("ProjectPatterns", s.ProjectPatterns),
+		prompts.Arg("Automation", s.Automation),
+		prompts.Arg("CollaborationStyle", s.CollaborationStyle),
+		prompts.Arg("MaintainerBehavior", s.MaintainerBehavior),
+		prompts.Arg("StyleEvolution", s.StyleEvolution),
+		prompts.Arg("Feedback", iter.Feedback),
+		prompts.Arg("Dimensions", b.dimensionsText(iter)),
+		prompts.Arg("Pairs", pairsSummary.String()),
 	)
-
-	raw, err := b.provider.Complete(ctx, refineSystemPrompt, prompt, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -241,6 +557,14 @@ func (b *Benchmarker) refinePersona(ctx context.Context, persona *analyzer.Perso
 		return nil, fmt.Errorf("parsing refined synthesis: %w", err)
 	}
 
+	// Refinement rewrites the prose, not the activity behind it, so the
+	// evidence links and the confidence they justify still apply, as does
+	// the record of what the grounding check removed.
+	synthesis.Evidence = persona.Synthesis.Evidence
+	synthesis.Examples = persona.Synthesis.Examples
+	synthesis.Confidence = persona.Synthesis.Confidence
+	synthesis.Unsupported = persona.Synthesis.Unsupported
+
 	refined := clonePersona(persona)
 	refined.Synthesis = synthesis
 	return refined, nil
@@ -251,6 +575,7 @@ func formatPersonaContext(p *analyzer.Persona) string {
 	var b strings.Builder
 	fmt.Fprintf(&b, "CODING PHILOSOPHY:\n%s\n\n", s.CodingPhilosophy)
 	fmt.Fprintf(&b, "CODE STYLE RULES:\n%s\n\n", s.CodeStyleRules)
+	fmt.Fprintf(&b, "COMMIT MESSAGE STYLE:\n%s\n\n", s.CommitMessageStyle)
 	fmt.Fprintf(&b, "REVIEW PRIORITIES:\n%s\n\n", s.ReviewPriorities)
 	fmt.Fprintf(&b, "REVIEW DECISION STYLE:\n%s\n\n", s.ReviewDecisionStyle)
 	fmt.Fprintf(&b, "REVIEW NON-BLOCKING NITS:\n%s\n\n", s.ReviewNonBlockingNits)
@@ -259,10 +584,21 @@ func formatPersonaContext(p *analyzer.Persona) string {
 	fmt.Fprintf(&b, "COMMUNICATION PATTERNS:\n%s\n\n", s.CommunicationPatterns)
 	fmt.Fprintf(&b, "TESTING PHILOSOPHY:\n%s\n\n", s.TestingPhilosophy)
 	fmt.Fprintf(&b, "DISTINCTIVE TRAITS:\n%s\n\n", s.DistinctiveTraits)
+	fmt.Fprintf(&b, "ANTI-PATTERNS (never do these):\n%s\n\n", s.AntiPatterns)
 	fmt.Fprintf(&b, "DEVELOPER INTERESTS:\n%s\n\n", s.DeveloperInterests)
 	fmt.Fprintf(&b, "ACTIVITY PATTERNS:\n%s\n\n", s.ActivityPatterns)
 	fmt.Fprintf(&b, "PROJECT PATTERNS:\n%s\n\n", s.ProjectPatterns)
-	fmt.Fprintf(&b, "COLLABORATION STYLE:\n%s\n", s.CollaborationStyle)
+	fmt.Fprintf(&b, "AUTOMATION:\n%s\n\n", s.Automation)
+	fmt.Fprintf(&b, "COLLABORATION STYLE:\n%s\n\n", s.CollaborationStyle)
+	fmt.Fprintf(&b, "MAINTAINER BEHAVIOR:\n%s\n\n", s.MaintainerBehavior)
+	fmt.Fprintf(&b, "STYLE EVOLUTION:\n%s\n", s.StyleEvolution)
+	if p.Suggestions.Comments > 0 {
+		fmt.Fprintf(&b, "\nCODE SUGGESTIONS:\n%.0f%% of their inline review comments include a ```suggestion block with a concrete patch; the rest are prose only.\n",
+			100*p.Suggestions.Rate())
+	}
+	if phrases := p.Phrases.Format(); phrases != "" {
+		fmt.Fprintf(&b, "\nSIGNATURE PHRASES:\n%s", phrases)
+	}
 	return b.String()
 }
 
@@ -279,8 +615,9 @@ func parseComparisonResult(raw string) (*comparisonResult, error) {
 	text := stripCodeFences(raw)
 
 	var parsed struct {
-		Score    float64 `json:"score"`
-		Feedback string  `json:"feedback"`
+		Score     float64            `json:"score"`
+		Feedback  string             `json:"feedback"`
+		Subscores map[string]float64 `json:"subscores"`
 	}
 	// Use Decoder to parse the first JSON object, ignoring any trailing
 	// commentary the LLM may append after the closing brace.
@@ -293,7 +630,7 @@ func parseComparisonResult(raw string) (*comparisonResult, error) {
 				err, textutil.Truncate(raw, 500, "..."))
 		}
 	}
-	return &comparisonResult{score: parsed.Score, feedback: parsed.Feedback}, nil
+	return &comparisonResult{score: parsed.Score, feedback: parsed.Feedback, subscores: parsed.Subscores}, nil
 }
 
 func parseDryRunReview(raw string) (*dryRunReview, error) {
@@ -331,12 +668,18 @@ func formatGeneratedReview(review *dryRunReview) string {
 	return b.String()
 }
 
+// stripCodeFences returns the text inside the first code fence of a reply
+// that is not a bare JSON object, without the fence's language tag.
 func stripCodeFences(s string) string {
 	text := strings.TrimSpace(s)
 	if len(text) > 0 && text[0] != '{' {
 		if idx := strings.Index(text, "```"); idx >= 0 {
 			text = text[idx+3:]
-			text = strings.TrimPrefix(text, "json")
+			if nl := strings.IndexByte(text, '\n'); nl >= 0 && !strings.ContainsAny(text[:nl], " \t") {
+				text = text[nl+1:]
+			} else {
+				text = strings.TrimPrefix(text, "json")
+			}
 			if end := strings.LastIndex(text, "```"); end >= 0 {
 				text = text[:end]
 			}
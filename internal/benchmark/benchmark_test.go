@@ -1,8 +1,13 @@
 package benchmark
 
 import (
+	"context"
 	"strings"
 	"testing"
+
+	"github.com/drpaneas/devlica/internal/analyzer"
+	"github.com/drpaneas/devlica/internal/llm"
+	"github.com/drpaneas/devlica/internal/stats"
 )
 
 func TestParseDryRunReview(t *testing.T) {
@@ -40,3 +45,152 @@ func TestFormatGeneratedReview(t *testing.T) {
 		t.Fatalf("expected comment in formatted output, got %q", got)
 	}
 }
+
+func TestFormatPersonaContextSuggestions(t *testing.T) {
+	p := &analyzer.Persona{Synthesis: &analyzer.SynthesisResult{}}
+	if got := formatPersonaContext(p); strings.Contains(got, "CODE SUGGESTIONS") {
+		t.Errorf("no review comments should omit the suggestion line, got %q", got)
+	}
+	p.Suggestions = stats.SuggestionStats{Comments: 4, WithSuggestion: 1, Blocks: 1}
+	if got := formatPersonaContext(p); !strings.Contains(got, "25% of their inline review comments") {
+		t.Errorf("expected suggestion rate in %q", got)
+	}
+}
+
+// recordingProvider replies with a fixed answer and keeps every prompt.
+type recordingProvider struct {
+	prompts []string
+	reply   string
+}
+
+func (p *recordingProvider) Complete(_ context.Context, _, prompt string, _ *llm.CompleteOptions) (string, error) {
+	p.prompts = append(p.prompts, prompt)
+	return p.reply, nil
+}
+
+func TestDefaultPromptsFormatCleanly(t *testing.T) {
+	ctx := context.Background()
+	p := &recordingProvider{reply: `{"decision":"comment","comment":"ok","score":50,"coding_philosophy":"x"}`}
+	b := New(p)
+	persona := &analyzer.Persona{Username: "alice", Synthesis: &analyzer.SynthesisResult{}}
+	ho := HeldOutReview{Path: "main.go", DiffHunk: "@@ -1 +1 @@", Body: "nit"}
+
+	generated, err := b.generateDryRunReview(ctx, persona, ho)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.compareReviews(ctx, p, ho, generated); err != nil {
+		t.Fatal(err)
+	}
+	iter := &IterationResult{Score: 42.5, Feedback: "too terse", Pairs: []ReviewPair{{Original: "nit", Generated: "ok", Path: "main.go"}}}
+	if _, err := b.refinePersona(ctx, persona, iter); err != nil {
+		t.Fatal(err)
+	}
+	if len(p.prompts) != 3 {
+		t.Fatalf("sent %d prompts, want 3", len(p.prompts))
+	}
+	for _, prompt := range p.prompts {
+		if strings.Contains(prompt, "%!") {
+			t.Errorf("prompt has a formatting error:\n%s", prompt)
+		}
+	}
+}
+
+func TestRunHonorsTargetAndIterations(t *testing.T) {
+	for _, tt := range []struct {
+		name       string
+		target     float64
+		iterations int
+		plateau    float64
+		want       int
+		stop       string
+	}{
+		{"target reached", 40, 5, PlateauGain, 1, StopTarget},
+		{"iteration bound", 90, 2, PlateauGain, 2, StopIterations},
+		{"plateau", TargetScore, MaxIterations, PlateauGain, 3, StopPlateau},
+		{"no plateau check", TargetScore, MaxIterations, 0, MaxIterations, StopIterations},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &recordingProvider{reply: `{"decision":"comment","comment":"ok","score":50,"coding_philosophy":"x"}`}
+			b := New(p)
+			b.SetTarget(tt.target)
+			b.SetMaxIterations(tt.iterations)
+			b.SetPlateau(tt.plateau)
+			persona := &analyzer.Persona{Username: "alice", Synthesis: &analyzer.SynthesisResult{}}
+			heldOut := HeldOut{Reviews: []HeldOutReview{{Path: "main.go", DiffHunk: "@@ -1 +1 @@", Body: "nit"}}}
+			result, _, err := b.Run(context.Background(), persona, heldOut)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.Iterations != tt.want || result.StopReason != tt.stop {
+				t.Errorf("ran %d iterations, stopped on %q; want %d, %q", result.Iterations, result.StopReason, tt.want, tt.stop)
+			}
+		})
+	}
+}
+
+func TestJudgeScoresAndRefines(t *testing.T) {
+	reply := `{"decision":"comment","comment":"ok","score":50,"feedback":"meh","coding_philosophy":"x"}`
+	actor := &recordingProvider{reply: reply}
+	judge := &recordingProvider{reply: reply}
+	b := New(actor)
+	b.SetJudges([]Judge{{Name: "ollama/judge", Provider: judge}}, AggregateMedian)
+	b.SetMaxIterations(2)
+	persona := &analyzer.Persona{Username: "alice", Synthesis: &analyzer.SynthesisResult{}}
+	held := HeldOut{
+		Reviews: []HeldOutReview{{Path: "main.go", DiffHunk: "@@ -1 +1 @@", Body: "nit"}},
+		Commits: []HeldOutCommit{{Message: "Fix", Patch: "-bug"}},
+	}
+	if _, _, err := b.Run(context.Background(), persona, held); err != nil {
+		t.Fatal(err)
+	}
+	// Two iterations of one review and one commit, refined once in between.
+	if len(actor.prompts) != 4 {
+		t.Errorf("actor got %d prompts, want the 4 dry runs", len(actor.prompts))
+	}
+	if len(judge.prompts) != 5 {
+		t.Errorf("judge got %d prompts, want 4 comparisons and 1 refinement", len(judge.prompts))
+	}
+	for _, p := range actor.prompts {
+		if strings.Contains(p, "mimicry benchmark") || strings.Contains(p, "ORIGINAL") {
+			t.Errorf("actor was asked to judge or refine:\n%s", p)
+		}
+	}
+}
+
+// regressingProvider scores the original persona 60 and any refinement of
+// it 50.
+type regressingProvider struct{}
+
+func (regressingProvider) Complete(_ context.Context, system, prompt string, _ *llm.CompleteOptions) (string, error) {
+	switch {
+	case strings.Contains(prompt, "mimicry benchmark"):
+		return `{"coding_philosophy":"refined"}`, nil
+	case strings.Contains(system, "two code review comments") && strings.Contains(prompt, "refined"):
+		return `{"score":50,"feedback":"worse"}`, nil
+	case strings.Contains(system, "two code review comments"):
+		return `{"score":60,"feedback":"ok"}`, nil
+	case strings.Contains(prompt, "CODING PHILOSOPHY:\nrefined"):
+		return `{"decision":"comment","concerns":[],"comment":"refined"}`, nil
+	default:
+		return `{"decision":"comment","concerns":[],"comment":"original"}`, nil
+	}
+}
+
+func TestRunKeepsBestPersona(t *testing.T) {
+	b := New(regressingProvider{})
+	b.SetMaxIterations(3)
+	persona := &analyzer.Persona{Username: "alice", Synthesis: &analyzer.SynthesisResult{CodingPhilosophy: "original"}}
+	heldOut := HeldOut{Reviews: []HeldOutReview{{Path: "main.go", DiffHunk: "@@ -1 +1 @@", Body: "nit"}}}
+
+	result, kept, err := b.Run(context.Background(), persona, heldOut)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Iterations != 3 || result.Selected != 1 || result.FinalScore != 60 {
+		t.Errorf("result = %d iterations, selected %d, score %v; want 3, 1, 60", result.Iterations, result.Selected, result.FinalScore)
+	}
+	if kept.Synthesis.CodingPhilosophy != "original" {
+		t.Errorf("kept persona %q, want the first, best-scoring one", kept.Synthesis.CodingPhilosophy)
+	}
+}
@@ -0,0 +1,177 @@
+package benchmark
+
+import (
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"strings"
+)
+
+// Calibration describes how the judges of a panel agreed on the pairs they
+// all scored. A judge's leniency moves the panel score with it, so the
+// calibrated Score moves every judge onto the first judge's scale first:
+// scores from panels that share a first judge can then be compared however
+// the other judges change.
+type Calibration struct {
+	// Pairs is how many pairs every judge scored, across all iterations.
+	Pairs int `json:"pairs"`
+	// Spread is the mean gap, in points, between two judges' scores for
+	// the same pair.
+	Spread float64 `json:"spread"`
+	// Correlation is the mean Pearson correlation between two judges'
+	// scores: near 1 they rank the samples alike, near 0 they do not. It
+	// is nil when no judge's scores varied.
+	Correlation *float64 `json:"correlation,omitempty"`
+	// Offsets holds how far each judge scores above the first judge on
+	// average, negative for a harsher judge; the first judge's is zero.
+	Offsets map[string]float64 `json:"offsets"`
+	// Score is the selected iteration's score with each judge's offset
+	// removed before their scores are combined.
+	Score float64 `json:"score"`
+}
+
+// calibrate compares the judges' scores on every pair in r, or returns nil
+// when fewer than two judges scored.
+func (b *Benchmarker) calibrate(r *Result) *Calibration {
+	if len(b.judges) < 2 || r.Selected == 0 {
+		return nil
+	}
+	names := make([]string, len(b.judges))
+	for i, j := range b.judges {
+		names[i] = j.Name
+	}
+	// rows holds each pair's scores in judge order.
+	var rows [][]float64
+	collect := func(iter *IterationResult) {
+		for _, pair := range iter.Pairs {
+			if row, ok := judgeRow(pair, names); ok {
+				rows = append(rows, row)
+			}
+		}
+	}
+	for i := range r.History {
+		collect(&r.History[i])
+	}
+	if r.Baseline != nil {
+		collect(r.Baseline)
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+
+	c := &Calibration{Pairs: len(rows), Offsets: make(map[string]float64, len(names))}
+	for j, name := range names {
+		var sum float64
+		for _, row := range rows {
+			sum += row[j] - row[0]
+		}
+		c.Offsets[name] = sum / float64(len(rows))
+	}
+
+	var gaps, correlations []float64
+	for j := range names {
+		for k := j + 1; k < len(names); k++ {
+			var gap float64
+			xs, ys := make([]float64, len(rows)), make([]float64, len(rows))
+			for i, row := range rows {
+				gap += math.Abs(row[j] - row[k])
+				xs[i], ys[i] = row[j], row[k]
+			}
+			gaps = append(gaps, gap/float64(len(rows)))
+			if corr, ok := pearson(xs, ys); ok {
+				correlations = append(correlations, corr)
+			}
+		}
+	}
+	c.Spread = mean(gaps)
+	if len(correlations) > 0 {
+		corr := mean(correlations)
+		c.Correlation = &corr
+	}
+
+	selected := r.History[r.Selected-1]
+	c.Score = facetMean(selected.Pairs, func(pair ReviewPair) float64 {
+		row, ok := judgeRow(pair, names)
+		if !ok {
+			return pair.Score
+		}
+		adjusted := make([]float64, len(row))
+		for j, score := range row {
+			adjusted[j] = score - c.Offsets[names[j]]
+		}
+		shift := b.aggregate.combine(row) - b.aggregate.combine(adjusted)
+		if pair.Human != nil {
+			// Only the judges' share of a blended score is adjusted.
+			shift *= 1 - b.humanWeight
+		}
+		return pair.Score - shift
+	})
+	return c
+}
+
+// judgeRow returns pair's score from each named judge, in order, if every
+// one of them scored it.
+func judgeRow(pair ReviewPair, names []string) ([]float64, bool) {
+	row := make([]float64, len(names))
+	for i, name := range names {
+		score, ok := pair.JudgeScores[name]
+		if !ok {
+			return nil, false
+		}
+		row[i] = score
+	}
+	return row, true
+}
+
+// pearson returns the correlation of xs and ys, which is undefined when
+// either does not vary.
+func pearson(xs, ys []float64) (float64, bool) {
+	mx, my := mean(xs), mean(ys)
+	var cov, vx, vy float64
+	for i := range xs {
+		dx, dy := xs[i]-mx, ys[i]-my
+		cov += dx * dy
+		vx += dx * dx
+		vy += dy * dy
+	}
+	if vx == 0 || vy == 0 {
+		return 0, false
+	}
+	return cov / math.Sqrt(vx*vy), true
+}
+
+// facetMean averages score over the pairs of each facet, then over the
+// facets, so each facet counts equally as in an iteration's Score.
+func facetMean(pairs []ReviewPair, score func(ReviewPair) float64) float64 {
+	totals := make(map[Facet]float64)
+	counts := make(map[Facet]int)
+	for _, pair := range pairs {
+		totals[pair.Facet] += score(pair)
+		counts[pair.Facet]++
+	}
+	var sum float64
+	for facet, n := range counts {
+		sum += totals[facet] / float64(n)
+	}
+	return sum / float64(len(counts))
+}
+
+// FormatCalibration renders the calibrated score and the judges' spread
+// as "calibrated=63.3 judge_spread=12.5", or "" without a calibration.
+func (r *Result) FormatCalibration() string {
+	if r.Calibration == nil {
+		return ""
+	}
+	return fmt.Sprintf("calibrated=%.1f judge_spread=%.1f", r.Calibration.Score, r.Calibration.Spread)
+}
+
+// formatOffsets renders each judge's offset as "openai/gpt-4o=+4.2",
+// sorted by name.
+func (c *Calibration) formatOffsets() string {
+	var parts []string
+	for _, name := range slices.Sorted(maps.Keys(c.Offsets)) {
+		parts = append(parts, fmt.Sprintf("%s=%+.1f", name, c.Offsets[name]))
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,53 @@
+package benchmark
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestCalibrate(t *testing.T) {
+	b := New(nil)
+	b.SetJudges([]Judge{{Name: "lenient"}, {Name: "fair"}, {Name: "harsh"}}, AggregateMedian)
+	// lenient always scores 20 above fair and harsh 5 below, so the median
+	// is fair's score while on lenient's scale it is 20 points higher.
+	pair := func(score float64) ReviewPair {
+		return ReviewPair{Facet: FacetReview, Score: score, JudgeScores: map[string]float64{
+			"lenient": score + 20, "fair": score, "harsh": score - 5,
+		}}
+	}
+	result := &Result{
+		Selected: 1,
+		History:  []IterationResult{{Pairs: []ReviewPair{pair(50), pair(70)}}},
+	}
+
+	c := b.calibrate(result)
+	if c == nil {
+		t.Fatal("calibrate() = nil, want a calibration for three judges")
+	}
+	near := func(got, want float64) bool { return math.Abs(got-want) < 1e-9 }
+	if c.Pairs != 2 || !near(c.Spread, 50.0/3) {
+		t.Errorf("pairs = %d, spread = %v, want 2 and 16.7", c.Pairs, c.Spread)
+	}
+	if c.Correlation == nil || !near(*c.Correlation, 1) {
+		t.Errorf("correlation = %v, want 1", c.Correlation)
+	}
+	if !near(c.Offsets["lenient"], 0) || !near(c.Offsets["fair"], -20) || !near(c.Offsets["harsh"], -25) {
+		t.Errorf("offsets = %v, want lenient 0, fair -20, harsh -25", c.Offsets)
+	}
+	if !near(c.Score, 80) {
+		t.Errorf("calibrated score = %v, want 80", c.Score)
+	}
+	result.Calibration = c
+	if got := result.FormatCalibration(); got != "calibrated=80.0 judge_spread=16.7" {
+		t.Errorf("FormatCalibration() = %q", got)
+	}
+	if md := result.Markdown("alice"); !strings.Contains(md, "## Judge calibration") || !strings.Contains(md, "fair=-20.0 harsh=-25.0 lenient=+0.0") {
+		t.Errorf("report is missing the calibration:\n%s", md)
+	}
+
+	b.SetJudges([]Judge{{Name: "fair"}}, AggregateMedian)
+	if c := b.calibrate(result); c != nil {
+		t.Errorf("calibrate() with one judge = %+v, want nil", c)
+	}
+}
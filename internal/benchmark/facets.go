@@ -0,0 +1,312 @@
+package benchmark
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/drpaneas/devlica/internal/analyzer"
+	"github.com/drpaneas/devlica/internal/ghcrawl"
+	"github.com/drpaneas/devlica/internal/llm"
+	"github.com/drpaneas/devlica/internal/prompts"
+	"github.com/drpaneas/devlica/internal/stats"
+	"github.com/drpaneas/devlica/internal/textutil"
+)
+
+// Facet names a kind of artifact the persona is asked to reproduce.
+type Facet string
+
+// The facets a benchmark scores.
+const (
+	FacetReview        Facet = "review"
+	FacetPRDescription Facet = "pr_description"
+	FacetCommitMessage Facet = "commit_message"
+	FacetIssueReply    Facet = "issue_reply"
+)
+
+// Facets lists every facet in the order scores are reported.
+var Facets = []Facet{FacetReview, FacetPRDescription, FacetCommitMessage, FacetIssueReply}
+
+const (
+	// minHeldOutPRBody skips PR descriptions too short to say anything
+	// about how the developer writes.
+	minHeldOutPRBody = 80
+	// maxHeldOutDiff bounds the commit diff shown to the persona.
+	maxHeldOutDiff = 4000
+	// minHeldOutReply skips issue replies too short to show how the
+	// developer talks to reporters, such as "Thanks!".
+	minHeldOutReply = 40
+)
+
+// HeldOutPR is a pull request description withheld from persona building.
+type HeldOutPR struct {
+	RepoFullName string
+	Title        string
+	Body         string
+	Additions    int
+	Deletions    int
+	ChangedFiles int
+	Labels       []string
+}
+
+// HeldOutCommit is a commit message withheld from persona building, with
+// the diff it describes.
+type HeldOutCommit struct {
+	RepoFullName string
+	Message      string
+	Patch        string
+}
+
+// HeldOutIssue is the developer's first reply to an issue someone else
+// opened on their repo, withheld from persona building, with the issue it
+// answers.
+type HeldOutIssue struct {
+	RepoFullName string
+	Number       int
+	Title        string
+	Author       string
+	Body         string
+	Reply        string
+}
+
+// HeldOut holds the samples of every facet withheld for a benchmark.
+type HeldOut struct {
+	Reviews []HeldOutReview
+	PRs     []HeldOutPR
+	Commits []HeldOutCommit
+	Issues  []HeldOutIssue
+}
+
+// Len returns the number of held-out samples across all facets.
+func (h HeldOut) Len() int {
+	return len(h.Reviews) + len(h.PRs) + len(h.Commits) + len(h.Issues)
+}
+
+// Held-out samples scale with the data: HeldOutShare of each facet's
+// eligible samples, but at least MinHeldOut, within the caller's cap.
+const (
+	MinHeldOut   = 3
+	HeldOutShare = 0.1
+)
+
+// heldOutCount returns how many of available samples to hold out, at most
+// limit.
+func heldOutCount(available, limit int) int {
+	share := int(math.Round(float64(available) * HeldOutShare))
+	return min(limit, available, max(MinHeldOut, share))
+}
+
+// SplitHeldOut removes samples of each facet from data: review comments
+// with a diff hunk, PR descriptions of some length, commit messages with a
+// patch, and first replies to issues opened on the developer's repos. It
+// holds out HeldOutShare of each, at least MinHeldOut and
+// at most max. Like SplitReviews, it modifies data in place so the
+// held-out samples are not visible during persona analysis, and samples
+// that are visible anyway, duplicated in another comment, PR, or commit,
+// are left out of scoring.
+func SplitHeldOut(data *ghcrawl.CrawlResult, max int) HeldOut {
+	var reviews, prs, commits, issues int
+	for _, repo := range data.Repos {
+		for _, rc := range repo.ReviewComments {
+			if rc.DiffHunk != "" {
+				reviews++
+			}
+		}
+		for _, pr := range repo.PRs {
+			if heldOutPR(pr) {
+				prs++
+			}
+		}
+		for _, c := range repo.Commits {
+			if heldOutCommit(c) {
+				commits++
+			}
+		}
+		for _, td := range repo.Triage {
+			if heldOutIssue(td) {
+				issues++
+			}
+		}
+	}
+	maxPRs, maxCommits, maxIssues := heldOutCount(prs, max), heldOutCount(commits, max), heldOutCount(issues, max)
+
+	held := HeldOut{Reviews: SplitReviews(data, heldOutCount(reviews, max))}
+	for i := range data.Repos {
+		repo := &data.Repos[i]
+		var keptPRs []ghcrawl.PullRequestData
+		for _, pr := range repo.PRs {
+			if len(held.PRs) < maxPRs && heldOutPR(pr) {
+				held.PRs = append(held.PRs, HeldOutPR{
+					RepoFullName: repo.FullName,
+					Title:        pr.Title,
+					Body:         pr.Body,
+					Additions:    pr.Additions,
+					Deletions:    pr.Deletions,
+					ChangedFiles: pr.ChangedFiles,
+					Labels:       pr.Labels,
+				})
+				continue
+			}
+			keptPRs = append(keptPRs, pr)
+		}
+		repo.PRs = keptPRs
+
+		var keptCommits []ghcrawl.CommitData
+		for _, c := range repo.Commits {
+			if len(held.Commits) < maxCommits && heldOutCommit(c) {
+				held.Commits = append(held.Commits, HeldOutCommit{
+					RepoFullName: repo.FullName,
+					Message:      strings.TrimSpace(c.Message),
+					Patch:        c.Patch,
+				})
+				continue
+			}
+			keptCommits = append(keptCommits, c)
+		}
+		repo.Commits = keptCommits
+
+		// Only the reply is withheld: the response time and labels still
+		// describe how the developer triages.
+		for j, td := range repo.Triage {
+			if len(held.Issues) < maxIssues && heldOutIssue(td) {
+				held.Issues = append(held.Issues, HeldOutIssue{
+					RepoFullName: repo.FullName,
+					Number:       td.Number,
+					Title:        td.Title,
+					Author:       td.Author,
+					Body:         td.Body,
+					Reply:        strings.TrimSpace(td.FirstReply),
+				})
+				repo.Triage[j].FirstReply = ""
+			}
+		}
+	}
+	data.IssueComments = dropWhere(data.IssueComments, held.onHeldOutIssue)
+	held.dropLeaked(data)
+	return held
+}
+
+func heldOutPR(pr ghcrawl.PullRequestData) bool {
+	return len(strings.TrimSpace(pr.Body)) >= minHeldOutPRBody
+}
+
+func heldOutCommit(c ghcrawl.CommitData) bool {
+	subject, _, _ := strings.Cut(strings.TrimSpace(c.Message), "\n")
+	return c.Patch != "" && !stats.IsMergeSubject(subject)
+}
+
+func heldOutIssue(td ghcrawl.TriageData) bool {
+	return len(strings.TrimSpace(td.FirstReply)) >= minHeldOutReply
+}
+
+// onHeldOutIssue reports whether cm was posted on a held-out issue. The
+// crawl also collects the developer's issue comments on their own repos, so
+// the held-out reply and any follow-up quoting it would stay visible.
+func (h HeldOut) onHeldOutIssue(cm ghcrawl.Comment) bool {
+	for _, issue := range h.Issues {
+		if strings.Contains(cm.URL, fmt.Sprintf("/%s/issues/%d#", issue.RepoFullName, issue.Number)) {
+			return true
+		}
+	}
+	return false
+}
+
+// writingSample is one held-out PR description, commit message, or issue
+// reply with the context the persona writes it from.
+type writingSample struct {
+	facet    Facet
+	label    string
+	kind     string
+	context  string
+	original string
+}
+
+func (h HeldOut) writingSamples() []writingSample {
+	var samples []writingSample
+	for _, pr := range h.PRs {
+		ctx := fmt.Sprintf("Repository: %s\nTitle: %s\nSize: +%d/-%d in %d files\n", pr.RepoFullName, pr.Title, pr.Additions, pr.Deletions, pr.ChangedFiles)
+		if len(pr.Labels) > 0 {
+			ctx += "Labels: " + strings.Join(pr.Labels, ", ") + "\n"
+		}
+		samples = append(samples, writingSample{
+			facet:    FacetPRDescription,
+			label:    "PR: " + pr.Title,
+			kind:     "pull request description",
+			context:  ctx,
+			original: pr.Body,
+		})
+	}
+	for _, c := range h.Commits {
+		subject, _, _ := strings.Cut(c.Message, "\n")
+		samples = append(samples, writingSample{
+			facet:    FacetCommitMessage,
+			label:    "commit: " + subject,
+			kind:     "commit message",
+			context:  fmt.Sprintf("Repository: %s\nDiff:\n%s\n", c.RepoFullName, textutil.Truncate(c.Patch, maxHeldOutDiff, "\n... (diff truncated)")),
+			original: c.Message,
+		})
+	}
+	for _, issue := range h.Issues {
+		samples = append(samples, writingSample{
+			facet:    FacetIssueReply,
+			label:    fmt.Sprintf("issue: %s#%d", issue.RepoFullName, issue.Number),
+			kind:     "issue reply",
+			context:  fmt.Sprintf("Repository: %s\nIssue #%d: %s\nOpened by: %s\n\n%s\n", issue.RepoFullName, issue.Number, issue.Title, issue.Author, issue.Body),
+			original: issue.Reply,
+		})
+	}
+	return samples
+}
+
+// generateWriting asks the persona to write the held-out artifact.
+func (b *Benchmarker) generateWriting(ctx context.Context, persona *analyzer.Persona, s writingSample) (string, error) {
+	var raw string
+	var err error
+	if s.facet == FacetIssueReply {
+		raw, err = b.complete(ctx, b.provider, "write-system", writeSystemPrompt, "reply", replyPrompt, nil,
+			prompts.Arg("Username", persona.Username),
+			prompts.Arg("Persona", formatPersonaContext(persona)),
+			prompts.Arg("Issue", s.context),
+		)
+	} else {
+		raw, err = b.complete(ctx, b.provider, "write-system", writeSystemPrompt, "write", writePrompt, nil,
+			prompts.Arg("Username", persona.Username),
+			prompts.Arg("Persona", formatPersonaContext(persona)),
+			prompts.Arg("Kind", s.kind),
+			prompts.Arg("Context", s.context),
+		)
+	}
+	if err != nil {
+		return "", err
+	}
+	// A model sometimes wraps its whole reply in a code fence.
+	if text := strings.TrimSpace(raw); strings.HasPrefix(text, "```") && strings.HasSuffix(text, "```") {
+		raw = stripCodeFences(text)
+	}
+	return strings.TrimSpace(raw), nil
+}
+
+func (b *Benchmarker) compareWriting(ctx context.Context, judge llm.Provider, s writingSample, generated string) (*comparisonResult, error) {
+	if s.facet == FacetIssueReply {
+		raw, err := b.complete(ctx, judge, "compare-writing-system", compareWritingSystemPrompt, "compare-reply", compareReplyPrompt, comparisonSchema,
+			prompts.Arg("Issue", s.context),
+			prompts.Arg("Original", s.original),
+			prompts.Arg("Generated", generated),
+		)
+		if err != nil {
+			return nil, err
+		}
+		return parseComparisonResult(raw)
+	}
+	raw, err := b.complete(ctx, judge, "compare-writing-system", compareWritingSystemPrompt, "compare-writing", compareWritingPrompt, comparisonSchema,
+		prompts.Arg("Kind", s.kind),
+		prompts.Arg("Context", s.context),
+		prompts.Arg("Original", s.original),
+		prompts.Arg("Generated", generated),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return parseComparisonResult(raw)
+}
@@ -0,0 +1,189 @@
+package benchmark
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/drpaneas/devlica/internal/analyzer"
+	"github.com/drpaneas/devlica/internal/ghcrawl"
+	"github.com/drpaneas/devlica/internal/llm"
+)
+
+func TestSplitHeldOut(t *testing.T) {
+	long := strings.Repeat("Explains the change in detail. ", 4)
+	data := &ghcrawl.CrawlResult{Repos: []ghcrawl.RepoData{{
+		FullName: "alice/tool",
+		PRs: []ghcrawl.PullRequestData{
+			{Title: "Short", Body: "fix"},
+			{Title: "Add cache", Body: long, Additions: 40, ChangedFiles: 2},
+			{Title: "Add retries", Body: strings.Repeat("Retries failed requests with backoff. ", 4)},
+		},
+		Commits: []ghcrawl.CommitData{
+			{Message: "Merge pull request #1 from bob/x", Patch: "+x"},
+			{Message: "Add cache\n\nKeeps lookups cheap.", Patch: "+cache"},
+			{Message: "No patch"},
+		},
+		ReviewComments: []ghcrawl.ReviewComment{{Body: "nit", DiffHunk: "@@"}},
+	}}}
+
+	held := SplitHeldOut(data, 1)
+	if held.Len() != 3 {
+		t.Fatalf("held out %d samples, want one per facet: %+v", held.Len(), held)
+	}
+	if held.PRs[0].Title != "Add cache" || held.PRs[0].Additions != 40 {
+		t.Errorf("held-out PR = %+v, want the first long description", held.PRs[0])
+	}
+	if held.Commits[0].Message != "Add cache\n\nKeeps lookups cheap." {
+		t.Errorf("held-out commit = %+v, want the first non-merge commit with a patch", held.Commits[0])
+	}
+	repo := data.Repos[0]
+	if len(repo.PRs) != 2 || len(repo.Commits) != 2 || len(repo.ReviewComments) != 0 {
+		t.Errorf("held-out samples left in data: %d PRs, %d commits, %d review comments", len(repo.PRs), len(repo.Commits), len(repo.ReviewComments))
+	}
+}
+
+func TestSplitHeldOutIssueReplies(t *testing.T) {
+	reply := "Thanks for the report. Can you share the config file and the full panic output?"
+	data := &ghcrawl.CrawlResult{
+		IssueComments: []ghcrawl.Comment{
+			{Repo: "alice/tool", Body: reply, URL: "https://github.com/alice/tool/issues/7#issuecomment-1"},
+			{Repo: "alice/tool", Body: "Fixed by upgrading the parser, closing.", URL: "https://github.com/alice/tool/issues/70#issuecomment-2"},
+		},
+		Repos: []ghcrawl.RepoData{{
+			FullName: "alice/tool",
+			Triage: []ghcrawl.TriageData{
+				{Number: 3, Title: "typo", Responded: true, FirstReply: "thanks!"},
+				{Number: 7, Title: "crash on start", Author: "bob", Body: "panics with a nil map", Responded: true, FirstReply: reply, Labels: []string{"bug"}},
+			},
+		}},
+	}
+
+	held := SplitHeldOut(data, 1)
+	want := HeldOutIssue{RepoFullName: "alice/tool", Number: 7, Title: "crash on start", Author: "bob", Body: "panics with a nil map", Reply: reply}
+	if len(held.Issues) != 1 || held.Issues[0] != want {
+		t.Fatalf("held-out issues = %+v, want %+v", held.Issues, want)
+	}
+	td := data.Repos[0].Triage[1]
+	if td.FirstReply != "" || !td.Responded || len(td.Labels) != 1 {
+		t.Errorf("triage = %+v, want only the reply withheld", td)
+	}
+	if len(data.IssueComments) != 1 || data.IssueComments[0].URL != "https://github.com/alice/tool/issues/70#issuecomment-2" {
+		t.Errorf("issue comments = %+v, want the held-out issue's comments removed", data.IssueComments)
+	}
+	samples := held.writingSamples()
+	if len(samples) != 1 || samples[0].facet != FacetIssueReply || !strings.Contains(samples[0].context, "panics with a nil map") {
+		t.Errorf("writing samples = %+v", samples)
+	}
+}
+
+func TestHeldOutCount(t *testing.T) {
+	tests := []struct {
+		available, limit, want int
+	}{
+		{0, 10, 0},
+		{2, 10, 2},
+		{20, 10, 3},
+		{64, 10, 6},
+		{500, 10, 10},
+		{500, 1, 1},
+	}
+	for _, tt := range tests {
+		if got := heldOutCount(tt.available, tt.limit); got != tt.want {
+			t.Errorf("heldOutCount(%d, %d) = %d, want %d", tt.available, tt.limit, got, tt.want)
+		}
+	}
+}
+
+func TestSplitHeldOutScalesWithData(t *testing.T) {
+	var reviews []ghcrawl.ReviewComment
+	for range 80 {
+		reviews = append(reviews, ghcrawl.ReviewComment{Body: "nit", DiffHunk: "@@"})
+	}
+	data := &ghcrawl.CrawlResult{Repos: []ghcrawl.RepoData{{FullName: "alice/tool", ReviewComments: reviews}}}
+
+	held := SplitHeldOut(data, MaxHeldOut)
+	if len(held.Reviews) != 8 {
+		t.Errorf("held out %d of 80 reviews, want 10%%", len(held.Reviews))
+	}
+	if got := len(data.Repos[0].ReviewComments); got != 72 {
+		t.Errorf("%d reviews left in data, want 72", got)
+	}
+}
+
+// facetProvider scores reviews and writing differently, so the facet
+// scores can be told apart.
+type facetProvider struct{}
+
+func (facetProvider) Complete(_ context.Context, system, prompt string, _ *llm.CompleteOptions) (string, error) {
+	switch {
+	case strings.Contains(system, "two pieces of developer writing"):
+		return `{"score":40,"feedback":"too long"}`, nil
+	case strings.Contains(system, "two code review comments"):
+		return `{"score":90,"feedback":"close"}`, nil
+	case strings.Contains(system, "writing exercise"):
+		return "Add cache", nil
+	default:
+		return `{"decision":"comment","comment":"ok"}`, nil
+	}
+}
+
+func TestRunScoresEachFacet(t *testing.T) {
+	b := New(facetProvider{})
+	b.SetMaxIterations(1)
+	persona := &analyzer.Persona{Username: "alice", Synthesis: &analyzer.SynthesisResult{}}
+	held := HeldOut{
+		Reviews: []HeldOutReview{{Path: "main.go", DiffHunk: "@@", Body: "nit"}},
+		Commits: []HeldOutCommit{{RepoFullName: "alice/tool", Message: "Add cache", Patch: "+cache"}, {Message: "Fix", Patch: "-bug"}},
+	}
+	result, _, err := b.Run(context.Background(), persona, held)
+	if err != nil {
+		t.Fatal(err)
+	}
+	iter := result.History[0]
+	if iter.FacetScores[FacetReview] != 90 || iter.FacetScores[FacetCommitMessage] != 40 {
+		t.Errorf("facet scores = %v, want review 90 and commit_message 40", iter.FacetScores)
+	}
+	// Facets count equally, however many samples each has.
+	if iter.Score != 65 {
+		t.Errorf("score = %v, want 65", iter.Score)
+	}
+	if got := iter.FormatFacets(); got != "review=90.0 commit_message=40.0" {
+		t.Errorf("FormatFacets() = %q", got)
+	}
+	if len(iter.Pairs) != 3 || iter.Pairs[1].Facet != FacetCommitMessage || iter.Pairs[1].Generated != "Add cache" {
+		t.Errorf("pairs = %+v", iter.Pairs)
+	}
+}
+
+func TestStripCodeFences(t *testing.T) {
+	for in, want := range map[string]string{
+		"Add cache":                         "Add cache",
+		"```\nAdd cache\n```":               "Add cache",
+		"```text\nAdd cache\n\nBody\n```":   "Add cache\n\nBody",
+		"```json\n{\"score\": 80}\n```":     `{"score": 80}`,
+		"Here:\n```json {\"score\": 80}```": `{"score": 80}`,
+		`{"score": 80}`:                     `{"score": 80}`,
+	} {
+		if got := stripCodeFences(in); got != want {
+			t.Errorf("stripCodeFences(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestGenerateWritingStripsWholeReplyFence(t *testing.T) {
+	persona := &analyzer.Persona{Username: "alice", Synthesis: &analyzer.SynthesisResult{}}
+	s := writingSample{facet: FacetCommitMessage, kind: "commit message"}
+	for reply, want := range map[string]string{
+		"```text\nAdd cache\n\nBody\n```": "Add cache\n\nBody",
+		"Uses ```code``` inline":          "Uses ```code``` inline",
+	} {
+		got, err := New(&recordingProvider{reply: reply}).generateWriting(context.Background(), persona, s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Errorf("generateWriting() with reply %q = %q, want %q", reply, got, want)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package benchmark
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// HistoryFile is the per-user file each benchmark run is appended to.
+const HistoryFile = "benchmark-history.jsonl"
+
+// RegressionDrop is how many points below the previous run a score must
+// fall to be flagged as a regression rather than judge noise.
+const RegressionDrop = 2.0
+
+// RunRecord summarizes one benchmark run and what produced it, so later
+// runs can tell whether a model or prompt change made the persona worse.
+type RunRecord struct {
+	Time     time.Time `json:"time"`
+	Score    float64   `json:"score"`
+	Baseline *float64  `json:"baseline,omitempty"`
+	// Calibrated is the score on the first judge's scale, when several
+	// judges scored.
+	Calibrated  *float64          `json:"calibrated,omitempty"`
+	FacetScores map[Facet]float64 `json:"facet_scores,omitempty"`
+	Iterations  int               `json:"iterations"`
+	Samples     int               `json:"samples"`
+	Provider    string            `json:"provider"`
+	Model       string            `json:"model"`
+	Judges      []string          `json:"judges,omitempty"`
+	// Prompts is the hash of the prompt overrides, or "" for the
+	// compiled-in prompts.
+	Prompts        string `json:"prompts,omitempty"`
+	DevlicaVersion string `json:"devlica_version"`
+}
+
+// NewRunRecord summarizes r. The caller fills in what produced it.
+func NewRunRecord(r *Result, samples int) RunRecord {
+	rec := RunRecord{Time: time.Now(), Score: r.FinalScore, Iterations: r.Iterations, Samples: samples}
+	if r.Selected > 0 {
+		rec.FacetScores = r.History[r.Selected-1].FacetScores
+	}
+	if r.Baseline != nil {
+		rec.Baseline = &r.Baseline.Score
+	}
+	if r.Calibration != nil {
+		rec.Calibrated = &r.Calibration.Score
+	}
+	return rec
+}
+
+// ReadHistory reads the runs recorded at path, oldest first. A missing
+// file is an empty history.
+func ReadHistory(path string) ([]RunRecord, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading benchmark history: %w", err)
+	}
+	defer f.Close()
+	var runs []RunRecord
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 1<<20)
+	for line := 1; scanner.Scan(); line++ {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+		var rec RunRecord
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			return nil, fmt.Errorf("decoding benchmark history %s line %d: %w", path, line, err)
+		}
+		runs = append(runs, rec)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading benchmark history: %w", err)
+	}
+	return runs, nil
+}
+
+// AppendHistory adds rec to the history at path, creating it as needed.
+func AppendHistory(path string, rec RunRecord) error {
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return fmt.Errorf("encoding benchmark history: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("creating benchmark history directory: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("opening benchmark history: %w", err)
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		f.Close()
+		return fmt.Errorf("writing benchmark history: %w", err)
+	}
+	return f.Close()
+}
+
+// Delta compares cur with the previous run prev: the score change, and
+// what changed between the runs that could explain it. The change is in
+// calibrated scores when both runs have one from the same first judge, so
+// swapping a lenient judge for a harsh one does not read as a regression.
+func Delta(prev, cur RunRecord) (float64, []string) {
+	var changes []string
+	changed := func(what, from, to string) {
+		if from != to {
+			changes = append(changes, fmt.Sprintf("%s %s -> %s", what, orNone(from), orNone(to)))
+		}
+	}
+	changed("provider", prev.Provider, cur.Provider)
+	changed("model", prev.Model, cur.Model)
+	changed("judges", strings.Join(prev.Judges, ","), strings.Join(cur.Judges, ","))
+	changed("devlica", prev.DevlicaVersion, cur.DevlicaVersion)
+	if prev.Prompts != cur.Prompts {
+		changes = append(changes, "prompt overrides")
+	}
+	if prev.Samples != cur.Samples {
+		changes = append(changes, fmt.Sprintf("samples %d -> %d", prev.Samples, cur.Samples))
+	}
+	if prev.Calibrated != nil && cur.Calibrated != nil && len(prev.Judges) > 0 && len(cur.Judges) > 0 && prev.Judges[0] == cur.Judges[0] {
+		return *cur.Calibrated - *prev.Calibrated, changes
+	}
+	return cur.Score - prev.Score, changes
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "none"
+	}
+	return s
+}
@@ -0,0 +1,70 @@
+package benchmark
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestHistoryRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "alice", HistoryFile)
+	runs, err := ReadHistory(path)
+	if err != nil || runs != nil {
+		t.Fatalf("ReadHistory() of a missing file = %v, %v", runs, err)
+	}
+
+	result := &Result{
+		FinalScore: 72,
+		Iterations: 2,
+		Selected:   1,
+		History:    []IterationResult{{Score: 72, FacetScores: map[Facet]float64{FacetReview: 72}}, {Score: 70}},
+		Baseline:   &IterationResult{Score: 50},
+	}
+	first := NewRunRecord(result, 6)
+	first.Model = "gpt-4o"
+	second := first
+	second.Score = 65
+	for _, rec := range []RunRecord{first, second} {
+		if err := AppendHistory(path, rec); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	runs, err = ReadHistory(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(runs) != 2 || runs[0].Score != 72 || runs[1].Score != 65 {
+		t.Fatalf("ReadHistory() = %+v", runs)
+	}
+	if *runs[0].Baseline != 50 || runs[0].FacetScores[FacetReview] != 72 || runs[0].Samples != 6 {
+		t.Errorf("record lost data: %+v", runs[0])
+	}
+}
+
+func TestDelta(t *testing.T) {
+	prev := RunRecord{Score: 70, Provider: "openai", Model: "gpt-4o", Samples: 6, DevlicaVersion: "v1.0.0"}
+	cur := RunRecord{Score: 64.5, Provider: "openai", Model: "gpt-4.1", Samples: 6, Prompts: "abc", DevlicaVersion: "v1.0.0", Judges: []string{"anthropic/claude"}}
+
+	delta, changes := Delta(prev, cur)
+	if delta != -5.5 {
+		t.Errorf("delta = %v, want -5.5", delta)
+	}
+	want := []string{"model gpt-4o -> gpt-4.1", "judges none -> anthropic/claude", "prompt overrides"}
+	if !reflect.DeepEqual(changes, want) {
+		t.Errorf("changes = %q, want %q", changes, want)
+	}
+	prevCalibrated, curCalibrated := 66.0, 67.0
+	prev.Calibrated, cur.Calibrated = &prevCalibrated, &curCalibrated
+	prev.Judges = []string{"anthropic/claude", "openai/gpt-4o"}
+	if delta, _ := Delta(prev, cur); delta != 1 {
+		t.Errorf("delta = %v, want the calibrated change 1", delta)
+	}
+	prev.Judges = []string{"openai/gpt-4o", "anthropic/claude"}
+	if delta, _ := Delta(prev, cur); delta != -5.5 {
+		t.Errorf("delta = %v, want the raw change -5.5 across first judges", delta)
+	}
+	if _, changes := Delta(prev, prev); len(changes) != 0 {
+		t.Errorf("identical runs should have no changes, got %q", changes)
+	}
+}
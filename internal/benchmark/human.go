@@ -0,0 +1,132 @@
+package benchmark
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// HumanScore is a person's rating of one dry run.
+type HumanScore struct {
+	Score float64 `json:"score"`
+	Note  string  `json:"note,omitempty"`
+}
+
+// Rater asks a person to rate a judged pair. It returns false when they
+// skip the pair, which then keeps the judges' score.
+type Rater func(ctx context.Context, pair ReviewPair) (HumanScore, bool, error)
+
+// SetHuman makes each iteration ask rate about every pair once the judges
+// have scored it. A rated pair's score becomes weight parts human to
+// 1-weight parts judges, and the person's note is passed to refinement
+// along with the judges' feedback.
+func (b *Benchmarker) SetHuman(rate Rater, weight float64) {
+	b.rate = rate
+	b.humanWeight = weight
+}
+
+// rateHuman asks b.rate about each pair in turn and blends in the scores
+// given.
+func (b *Benchmarker) rateHuman(ctx context.Context, pairs []ReviewPair) error {
+	for i := range pairs {
+		pair := &pairs[i]
+		human, ok, err := b.rate(ctx, *pair)
+		if err != nil {
+			return fmt.Errorf("rating sample %d: %w", i+1, err)
+		}
+		if !ok {
+			continue
+		}
+		pair.Human = &human
+		pair.LLMScore = pair.Score
+		pair.Score = b.humanWeight*human.Score + (1-b.humanWeight)*pair.Score
+		if human.Note != "" {
+			pair.Feedback = strings.TrimSpace(pair.Feedback + "\n[human] " + human.Note)
+		}
+	}
+	return nil
+}
+
+// columnWidth is the width of each side of a terminal comparison.
+const columnWidth = 48
+
+// TerminalRater returns a Rater that shows each pair's original and
+// generated text side by side on out and reads a score and a note from
+// in. An empty score skips the pair.
+func TerminalRater(in io.Reader, out io.Writer) Rater {
+	scanner := bufio.NewScanner(in)
+	readLine := func() (string, error) {
+		if !scanner.Scan() {
+			if err := scanner.Err(); err != nil {
+				return "", err
+			}
+			return "", io.ErrUnexpectedEOF
+		}
+		return strings.TrimSpace(scanner.Text()), nil
+	}
+	return func(ctx context.Context, pair ReviewPair) (HumanScore, bool, error) {
+		if err := ctx.Err(); err != nil {
+			return HumanScore{}, false, err
+		}
+		fmt.Fprintf(out, "\n== %s: %s (judges: %.0f)\n", pair.Facet, pair.Path, pair.Score)
+		fmt.Fprint(out, sideBySide("ORIGINAL", pair.Original, "GENERATED", pair.Generated, columnWidth))
+		for {
+			fmt.Fprint(out, "Your score 0-100 (enter skips): ")
+			line, err := readLine()
+			if err != nil {
+				return HumanScore{}, false, err
+			}
+			if line == "" {
+				return HumanScore{}, false, nil
+			}
+			score, err := strconv.ParseFloat(line, 64)
+			if err != nil || score < 0 || score > 100 {
+				fmt.Fprintln(out, "Enter a number from 0 to 100.")
+				continue
+			}
+			fmt.Fprint(out, "Note for refinement (optional): ")
+			note, err := readLine()
+			if err != nil {
+				return HumanScore{}, false, err
+			}
+			return HumanScore{Score: score, Note: note}, true, nil
+		}
+	}
+}
+
+// sideBySide lays out two texts in columns of width characters, wrapping
+// long lines.
+func sideBySide(leftTitle, left, rightTitle, right string, width int) string {
+	l := append([]string{leftTitle, strings.Repeat("-", len(leftTitle))}, wrapLines(left, width)...)
+	r := append([]string{rightTitle, strings.Repeat("-", len(rightTitle))}, wrapLines(right, width)...)
+	var b strings.Builder
+	for i := range max(len(l), len(r)) {
+		var a, c string
+		if i < len(l) {
+			a = l[i]
+		}
+		if i < len(r) {
+			c = r[i]
+		}
+		fmt.Fprintf(&b, "%-*s | %s\n", width, a, c)
+	}
+	return b.String()
+}
+
+// wrapLines splits s into lines of at most width runes.
+func wrapLines(s string, width int) []string {
+	var lines []string
+	for _, line := range strings.Split(strings.TrimSpace(s), "\n") {
+		line = strings.ReplaceAll(line, "\t", "    ")
+		runes := []rune(line)
+		for len(runes) > width {
+			lines = append(lines, string(runes[:width]))
+			runes = runes[width:]
+		}
+		lines = append(lines, string(runes))
+	}
+	return lines
+}
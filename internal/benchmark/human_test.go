@@ -0,0 +1,65 @@
+package benchmark
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/drpaneas/devlica/internal/analyzer"
+)
+
+func TestTerminalRater(t *testing.T) {
+	var out strings.Builder
+	rate := TerminalRater(strings.NewReader("abc\n90\ntoo polite\n\n"), &out)
+	pair := ReviewPair{Original: "nit: rename", Generated: "Decision: comment", Path: "main.go", Score: 60, Facet: FacetReview}
+
+	got, ok, err := rate(context.Background(), pair)
+	if err != nil || !ok {
+		t.Fatalf("rate() = %v, %v", ok, err)
+	}
+	if got.Score != 90 || got.Note != "too polite" {
+		t.Errorf("rate() = %+v, want 90 with a note", got)
+	}
+	if !strings.Contains(out.String(), "Enter a number from 0 to 100.") {
+		t.Error("an invalid score should be asked again")
+	}
+	if !strings.Contains(out.String(), "nit: rename") || !strings.Contains(out.String(), "| Decision: comment") {
+		t.Errorf("pair not shown side by side:\n%s", out.String())
+	}
+
+	if _, ok, err := rate(context.Background(), pair); err != nil || ok {
+		t.Errorf("empty score should skip: ok=%v err=%v", ok, err)
+	}
+}
+
+func TestRunBlendsHumanScores(t *testing.T) {
+	p := &recordingProvider{reply: `{"decision":"comment","comment":"ok","score":50,"feedback":"meh","coding_philosophy":"x"}`}
+	b := New(p)
+	b.SetMaxIterations(2)
+	b.SetHuman(func(context.Context, ReviewPair) (HumanScore, bool, error) {
+		return HumanScore{Score: 100, Note: "needs more nits"}, true, nil
+	}, 0.5)
+	persona := &analyzer.Persona{Username: "alice", Synthesis: &analyzer.SynthesisResult{}}
+	heldOut := HeldOut{Reviews: []HeldOutReview{{Path: "main.go", DiffHunk: "@@ -1 +1 @@", Body: "nit"}}}
+
+	result, _, err := b.Run(context.Background(), persona, heldOut)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pair := result.History[0].Pairs[0]
+	if pair.Score != 75 || pair.LLMScore != 50 || pair.Human == nil {
+		t.Errorf("pair = %+v, want 75 blended from 50 and 100", pair)
+	}
+	if !strings.Contains(result.History[0].Feedback, "[human] needs more nits") {
+		t.Errorf("feedback %q should carry the human note", result.History[0].Feedback)
+	}
+	var refined bool
+	for _, prompt := range p.prompts {
+		if strings.Contains(prompt, "mimicry benchmark") && strings.Contains(prompt, "[human] needs more nits") {
+			refined = true
+		}
+	}
+	if !refined {
+		t.Error("refinement should see the human note")
+	}
+}
@@ -0,0 +1,137 @@
+package benchmark
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/drpaneas/devlica/internal/llm"
+)
+
+// Judge is a model that scores dry runs against the originals. Name labels
+// its scores in the per-judge breakdown, such as "openai/gpt-4o".
+type Judge struct {
+	Name     string
+	Provider llm.Provider
+}
+
+// Aggregate names how the scores of several judges are combined into one.
+type Aggregate string
+
+// The ways judge scores can be combined.
+const (
+	// AggregateMedian takes the middle score, or the mean of the middle two.
+	AggregateMedian Aggregate = "median"
+	// AggregateTrimmedMean drops the top and bottom quarter of the scores,
+	// but at least the highest and lowest once there are three, and
+	// averages the rest; two scores are just averaged.
+	AggregateTrimmedMean Aggregate = "trimmed-mean"
+)
+
+// Aggregates lists the supported aggregations.
+var Aggregates = []Aggregate{AggregateMedian, AggregateTrimmedMean}
+
+// combine reduces scores, which must not be empty, to one.
+func (a Aggregate) combine(scores []float64) float64 {
+	sorted := slices.Clone(scores)
+	slices.Sort(sorted)
+	n := len(sorted)
+	if a == AggregateTrimmedMean {
+		trim := n / 4
+		if n >= 3 {
+			trim = max(trim, 1)
+		}
+		return mean(sorted[trim : n-trim])
+	}
+	if n%2 == 1 {
+		return sorted[n/2]
+	}
+	return (sorted[n/2-1] + sorted[n/2]) / 2
+}
+
+func mean(xs []float64) float64 {
+	var sum float64
+	for _, x := range xs {
+		sum += x
+	}
+	return sum / float64(len(xs))
+}
+
+// SetJudges makes judges score every dry run, combined with aggregate, while
+// the dry runs themselves still come from the persona's own provider. The
+// first judge also refines the persona. With no judges, the persona's
+// provider does everything.
+func (b *Benchmarker) SetJudges(judges []Judge, aggregate Aggregate) {
+	b.judges = judges
+	b.aggregate = aggregate
+}
+
+// judgeProvider returns the provider that refines the persona.
+func (b *Benchmarker) judgeProvider() llm.Provider {
+	if len(b.judges) > 0 {
+		return b.judges[0].Provider
+	}
+	return b.provider
+}
+
+// panelScore is one comparison as scored by every judge.
+type panelScore struct {
+	score float64
+	// byJudge holds each judge's score by name; it is nil with one judge.
+	byJudge  map[string]float64
+	feedback string
+	// subscores holds the rubric scores, each combined across judges.
+	subscores map[string]float64
+}
+
+// judge asks every judge to run compare and combines their scores. With
+// several judges the feedback of each is kept, labeled with its name.
+func (b *Benchmarker) judge(compare func(llm.Provider) (*comparisonResult, error)) (*panelScore, error) {
+	if len(b.judges) < 2 {
+		comp, err := compare(b.judgeProvider())
+		if err != nil {
+			return nil, err
+		}
+		return &panelScore{score: comp.score, feedback: comp.feedback, subscores: comp.subscores}, nil
+	}
+	ps := &panelScore{byJudge: make(map[string]float64, len(b.judges))}
+	var scores []float64
+	var feedback []string
+	bySubscore := make(map[string][]float64)
+	for _, j := range b.judges {
+		comp, err := compare(j.Provider)
+		if err != nil {
+			return nil, fmt.Errorf("judge %s: %w", j.Name, err)
+		}
+		ps.byJudge[j.Name] = comp.score
+		scores = append(scores, comp.score)
+		for d, s := range comp.subscores {
+			bySubscore[d] = append(bySubscore[d], s)
+		}
+		feedback = append(feedback, fmt.Sprintf("[%s] %s", j.Name, comp.feedback))
+	}
+	ps.score = b.aggregate.combine(scores)
+	if len(bySubscore) > 0 {
+		ps.subscores = make(map[string]float64, len(bySubscore))
+		for d, s := range bySubscore {
+			ps.subscores[d] = b.aggregate.combine(s)
+		}
+	}
+	ps.feedback = strings.Join(feedback, "\n")
+	return ps, nil
+}
+
+// FormatJudges renders each judge's score as "openai/gpt-4o=72.0", in the
+// order the judges were set, or "" with a single judge.
+func (r IterationResult) FormatJudges(judges []Judge) string {
+	if len(r.JudgeScores) == 0 {
+		return ""
+	}
+	var parts []string
+	for _, j := range judges {
+		if score, ok := r.JudgeScores[j.Name]; ok {
+			parts = append(parts, fmt.Sprintf("%s=%.1f", j.Name, score))
+		}
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,82 @@
+package benchmark
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/drpaneas/devlica/internal/analyzer"
+	"github.com/drpaneas/devlica/internal/llm"
+)
+
+func TestAggregateCombine(t *testing.T) {
+	tests := []struct {
+		agg    Aggregate
+		scores []float64
+		want   float64
+	}{
+		{AggregateMedian, []float64{90, 40, 60}, 60},
+		{AggregateMedian, []float64{90, 40}, 65},
+		{AggregateTrimmedMean, []float64{90, 40, 60}, 60},
+		{AggregateTrimmedMean, []float64{100, 40, 60, 70}, 65},
+		{AggregateTrimmedMean, []float64{100, 40, 60, 70, 75, 80, 20, 50}, 63.75},
+		{AggregateTrimmedMean, []float64{90, 40}, 65},
+	}
+	for _, tt := range tests {
+		if got := tt.agg.combine(tt.scores); got != tt.want {
+			t.Errorf("%s.combine(%v) = %v, want %v", tt.agg, tt.scores, got, tt.want)
+		}
+	}
+}
+
+// scoringProvider judges every comparison with a fixed score and counts
+// the refinements it is asked for.
+type scoringProvider struct {
+	score   float64
+	refines int
+}
+
+func (p *scoringProvider) Complete(_ context.Context, _, prompt string, _ *llm.CompleteOptions) (string, error) {
+	if strings.Contains(prompt, "mimicry benchmark") {
+		p.refines++
+		return `{"coding_philosophy":"x"}`, nil
+	}
+	return fmt.Sprintf(`{"score":%v,"feedback":"scored %v"}`, p.score, p.score), nil
+}
+
+func TestRunWithJudgePanel(t *testing.T) {
+	actor := &recordingProvider{reply: `{"decision":"comment","comment":"ok"}`}
+	judges := []Judge{
+		{Name: "openai/a", Provider: &scoringProvider{score: 90}},
+		{Name: "anthropic/b", Provider: &scoringProvider{score: 40}},
+		{Name: "ollama/c", Provider: &scoringProvider{score: 60}},
+	}
+	b := New(actor)
+	b.SetJudges(judges, AggregateMedian)
+	b.SetMaxIterations(2)
+	b.SetTarget(100)
+	persona := &analyzer.Persona{Username: "alice", Synthesis: &analyzer.SynthesisResult{}}
+	held := HeldOut{Reviews: []HeldOutReview{{Path: "main.go", DiffHunk: "@@", Body: "nit"}}}
+
+	result, _, err := b.Run(context.Background(), persona, held)
+	if err != nil {
+		t.Fatal(err)
+	}
+	iter := result.History[0]
+	if iter.Score != 60 {
+		t.Errorf("score = %v, want the median 60", iter.Score)
+	}
+	if got := iter.FormatJudges(judges); got != "openai/a=90.0 anthropic/b=40.0 ollama/c=60.0" {
+		t.Errorf("FormatJudges() = %q", got)
+	}
+	if !strings.Contains(iter.Feedback, "[anthropic/b] scored 40") {
+		t.Errorf("feedback should name each judge, got %q", iter.Feedback)
+	}
+	if got := judges[0].Provider.(*scoringProvider).refines; got != 1 {
+		t.Errorf("first judge refined %d times, want 1", got)
+	}
+	if got := judges[1].Provider.(*scoringProvider).refines; got != 0 {
+		t.Errorf("second judge refined %d times, want 0", got)
+	}
+}
@@ -0,0 +1,136 @@
+package benchmark
+
+import (
+	"log/slog"
+	"strings"
+	"unicode"
+
+	"github.com/drpaneas/devlica/internal/ghcrawl"
+)
+
+const (
+	// leakThreshold is the share of a held-out text's word trigrams that,
+	// all found in one analysis input, mark the text as leaked.
+	leakThreshold = 0.8
+	// minLeakWords skips texts too short to say anything specific, such
+	// as "LGTM", which appear everywhere without leaking anything.
+	minLeakWords = 5
+)
+
+// leakIndex maps the word trigrams of every text the analyzer reads to the
+// texts they occur in.
+type leakIndex map[string][]int
+
+// newLeakIndex indexes the comments, reviews, PR descriptions, commit
+// messages, and issue replies left in data.
+func newLeakIndex(data *ghcrawl.CrawlResult) leakIndex {
+	ix := make(leakIndex)
+	doc := 0
+	add := func(text string) {
+		for g := range trigrams(text) {
+			ix[g] = append(ix[g], doc)
+		}
+		doc++
+	}
+	for _, c := range data.IssueComments {
+		add(c.Body)
+	}
+	for _, pr := range data.ExternalPRs {
+		add(pr.Body)
+	}
+	for _, repo := range data.Repos {
+		for _, c := range repo.PRComments {
+			add(c.Body)
+		}
+		for _, c := range repo.CommitComments {
+			add(c.Body)
+		}
+		for _, rc := range repo.ReviewComments {
+			add(rc.Body)
+		}
+		for _, r := range repo.ReviewReplies {
+			add(r.Body)
+		}
+		for _, r := range repo.Reviews {
+			add(r.Body)
+		}
+		for _, pr := range repo.PRs {
+			add(pr.Body)
+		}
+		for _, c := range repo.Commits {
+			add(c.Message)
+		}
+		for _, td := range repo.Triage {
+			add(td.FirstReply)
+		}
+	}
+	return ix
+}
+
+// leaks reports whether most of text also appears in a single indexed
+// text, verbatim or nearly so.
+func (ix leakIndex) leaks(text string) bool {
+	grams := trigrams(text)
+	if len(grams) == 0 {
+		return false
+	}
+	hits := make(map[int]int)
+	for g := range grams {
+		for _, doc := range ix[g] {
+			hits[doc]++
+		}
+	}
+	for _, n := range hits {
+		if float64(n) >= leakThreshold*float64(len(grams)) {
+			return true
+		}
+	}
+	return false
+}
+
+// trigrams returns the distinct lowercase word trigrams of text, or none
+// when it has fewer than minLeakWords words.
+func trigrams(text string) map[string]bool {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(words) < minLeakWords {
+		return nil
+	}
+	grams := make(map[string]bool, len(words)-2)
+	for i := range len(words) - 2 {
+		grams[words[i]+" "+words[i+1]+" "+words[i+2]] = true
+	}
+	return grams
+}
+
+// dropLeaked removes the held-out samples whose text the analyzer still
+// sees elsewhere in data, such as a review comment pasted into a PR
+// conversation or a PR description reused as a squash commit message: the
+// persona could copy them rather than predict them.
+func (h *HeldOut) dropLeaked(data *ghcrawl.CrawlResult) {
+	ix := newLeakIndex(data)
+	reviews, prs, commits, issues := len(h.Reviews), len(h.PRs), len(h.Commits), len(h.Issues)
+	h.Reviews = dropWhere(h.Reviews, func(r HeldOutReview) bool { return ix.leaks(r.Body) })
+	h.PRs = dropWhere(h.PRs, func(pr HeldOutPR) bool { return ix.leaks(pr.Body) })
+	h.Commits = dropWhere(h.Commits, func(c HeldOutCommit) bool { return ix.leaks(c.Message) })
+	h.Issues = dropWhere(h.Issues, func(issue HeldOutIssue) bool { return ix.leaks(issue.Reply) })
+	if h.Len() < reviews+prs+commits+issues {
+		slog.Info("excluded held-out samples that also appear in the analysis inputs",
+			"reviews", reviews-len(h.Reviews),
+			"pr_descriptions", prs-len(h.PRs),
+			"commit_messages", commits-len(h.Commits),
+			"issue_replies", issues-len(h.Issues),
+		)
+	}
+}
+
+func dropWhere[T any](items []T, drop func(T) bool) []T {
+	var kept []T
+	for _, item := range items {
+		if !drop(item) {
+			kept = append(kept, item)
+		}
+	}
+	return kept
+}
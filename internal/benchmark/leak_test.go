@@ -0,0 +1,59 @@
+package benchmark
+
+import (
+	"testing"
+
+	"github.com/drpaneas/devlica/internal/ghcrawl"
+)
+
+func TestSplitHeldOutDropsLeakedSamples(t *testing.T) {
+	leaked := "Please wrap this error with the path so callers can tell which file failed."
+	data := &ghcrawl.CrawlResult{
+		IssueComments: []ghcrawl.Comment{{Body: "As I said on the PR: " + leaked}},
+		Repos: []ghcrawl.RepoData{{
+			FullName: "alice/tool",
+			ReviewComments: []ghcrawl.ReviewComment{
+				{Body: leaked, DiffHunk: "@@ -1 +1 @@"},
+				{Body: "This loop allocates on every iteration; hoist the buffer out of it.", DiffHunk: "@@ -2 +2 @@"},
+				{Body: "LGTM", DiffHunk: "@@ -3 +3 @@"},
+			},
+			Commits: []ghcrawl.CommitData{
+				{Message: "Add retry support\n\nRetries idempotent requests up to three times with backoff.", Patch: "+retry"},
+			},
+			PRComments: []ghcrawl.Comment{
+				{Body: "Squashed as: Add retry support. Retries idempotent requests up to three times with backoff."},
+			},
+		}},
+	}
+
+	held := SplitHeldOut(data, MaxHeldOut)
+	if len(held.Reviews) != 2 {
+		t.Fatalf("held-out reviews = %+v, want the two that did not leak", held.Reviews)
+	}
+	for _, r := range held.Reviews {
+		if r.Body == leaked {
+			t.Error("a review pasted into an issue comment should not be scored")
+		}
+	}
+	if len(held.Commits) != 0 {
+		t.Errorf("a commit message repeated in a PR comment should not be scored: %+v", held.Commits)
+	}
+}
+
+func TestLeakIndex(t *testing.T) {
+	data := &ghcrawl.CrawlResult{IssueComments: []ghcrawl.Comment{{Body: "We should cache the parsed config instead of reading the file on every request."}}}
+	ix := newLeakIndex(data)
+	tests := []struct {
+		text string
+		want bool
+	}{
+		{"we should cache the parsed config instead of reading the file on every request!", true},
+		{"Cache the parsed config; reading the file on every request is slow.", false},
+		{"cache the parsed config", false},
+	}
+	for _, tt := range tests {
+		if got := ix.leaks(tt.text); got != tt.want {
+			t.Errorf("leaks(%q) = %v, want %v", tt.text, got, tt.want)
+		}
+	}
+}
@@ -23,6 +23,7 @@ Rules:
 - Optimize for the same concerns and severity this developer would choose, not just wording.
 - The concerns field should be short, specific, and ordered by priority.
 - The comment field should sound like the developer, but only mention the highest-signal point(s).
+- If the profile reports how often they use suggestion blocks, match that habit: include a ` + "```suggestion" + ` block in the comment only when this developer would likely propose a concrete patch.
 - Do not include markdown fences or extra commentary.`
 
 const compareSystemPrompt = `You are an objective evaluator comparing two code review comments.
@@ -51,9 +52,9 @@ Evaluate the match on these dimensions:
 - Tone: Is the voice reasonably similar after matching the right concern and severity?
 - Technical accuracy: Does it raise a technically plausible point grounded in the diff?
 
-Respond with a single JSON object (no markdown fences, no commentary):
+Score each dimension from 0 to 100, then the match overall. Respond with a single JSON object (no markdown fences, no commentary):
 
-{"score": <number 0-100>, "feedback": "<specific feedback on what matched well and what differed>"}
+{"subscores": {"concern": <0-100>, "severity": <0-100>, "actionability": <0-100>, "tone": <0-100>, "accuracy": <0-100>}, "score": <number 0-100>, "feedback": "<specific feedback on what matched well and what differed>"}
 
 Scoring guide:
 - 0-25: Misses the real concern or invents irrelevant ones
@@ -62,10 +63,98 @@ Scoring guide:
 - 71-85: Good match in concern, severity, and usefulness with minor differences
 - 86-100: Excellent match in concern selection, severity, usefulness, and voice`
 
+const writeSystemPrompt = `You are impersonating a specific developer for a writing exercise.
+You must write the way this developer would - matching their structure, length, level of
+detail, conventions, and tone. Do NOT add any meta-commentary about the impersonation.`
+
+const writePrompt = `You are impersonating developer %s. Here is their persona profile:
+
+%s
+
+Write the %s this developer would write for the change below.
+
+%s
+Rules:
+- Follow the conventions the profile describes (format, length, structure, prefixes, references) rather than generic best practice.
+- Match their voice and level of detail; do not pad a terse writer's text or trim a thorough one's.
+- Output only the text itself, with no markdown fences, explanation, or commentary.`
+
+const replyPrompt = `You are impersonating developer %s. Here is their persona profile:
+
+%s
+
+Someone opened the issue below on a repository this developer maintains. Write the first
+reply this developer would post on it.
+
+%s
+Rules:
+- Respond the way the profile says they handle issues: ask for details, point to docs or a fix, propose a change, or decline.
+- Match their voice, length, and manner with reporters; do not pad a terse maintainer's reply or trim a thorough one's.
+- Output only the reply itself, with no markdown fences, explanation, or commentary.`
+
+const compareWritingSystemPrompt = `You are an objective evaluator comparing two pieces of developer writing.
+One is the original written by the actual developer, the other is an AI-generated impersonation.
+You must evaluate how well the generated text matches the original in structure, content, and voice.
+Be honest and specific in your evaluation. Do not inflate scores.`
+
+const compareWritingPrompt = `Compare these two versions of a %s written for the same change.
+
+Change:
+%s
+ORIGINAL (written by the actual developer):
+%s
+
+GENERATED (AI impersonation attempt):
+%s
+
+Evaluate the match on these dimensions:
+- Structure: Same format, such as subject line conventions, prefixes, sections, bullet lists, or checklists?
+- Length and detail: Similar length and level of technical detail?
+- Content: Does it explain the same things (what changed, why, how it was tested, references)?
+- Voice: Similar tone, word choice, and idioms?
+
+Respond with a single JSON object (no markdown fences, no commentary):
+
+{"score": <number 0-100>, "feedback": "<specific feedback on what matched well and what differed>"}
+
+Scoring guide:
+- 0-25: Different format and voice; could be anyone's
+- 26-50: Some conventions shared, but structure, length, or tone is clearly off
+- 51-70: Right conventions with noticeable differences in detail or voice
+- 71-85: Close match with minor differences
+- 86-100: Hard to tell apart from the original`
+
+const compareReplyPrompt = `Compare these two first replies to the same GitHub issue.
+
+Issue:
+%s
+ORIGINAL (written by the actual maintainer):
+%s
+
+GENERATED (AI impersonation attempt):
+%s
+
+Evaluate the match on these dimensions:
+- Response: Does it take the same action, such as asking for a reproduction, pointing to docs or a fix, closing as a duplicate, or declining?
+- Content: Does it raise the same technical points?
+- Length and structure: Similar length and formatting?
+- Voice: Similar tone toward the reporter, word choice, and idioms?
+
+Respond with a single JSON object (no markdown fences, no commentary):
+
+{"score": <number 0-100>, "feedback": "<specific feedback on what matched well and what differed>"}
+
+Scoring guide:
+- 0-25: Different response and voice; could be any maintainer
+- 26-50: Similar response, but length, content, or tone is clearly off
+- 51-70: Same response with noticeable differences in detail or voice
+- 71-85: Close match with minor differences
+- 86-100: Hard to tell apart from the original`
+
 const refineSystemPrompt = `You are an expert at analyzing developer personas and refining them for
 better accuracy. You will receive a persona profile, benchmark scores, and detailed comparison
 feedback. Your job is to modify the persona fields so an AI can more accurately impersonate
-this developer's review style. Focus on capturing specific patterns, phrasings, and priorities
+this developer's review style, pull request descriptions, commit messages, and issue replies. Focus on capturing specific patterns, phrasings, and priorities
 that the current persona misses.`
 
 const refinePrompt = `The persona for developer %s scored %.1f/100 on a mimicry benchmark.
@@ -73,6 +162,7 @@ const refinePrompt = `The persona for developer %s scored %.1f/100 on a mimicry
 Current persona fields:
 - coding_philosophy: %s
 - code_style_rules: %s
+- commit_message_style: %s
 - review_priorities: %s
 - review_decision_style: %s
 - review_non_blocking_nits: %s
@@ -81,19 +171,26 @@ Current persona fields:
 - communication_patterns: %s
 - testing_philosophy: %s
 - distinctive_traits: %s
+- anti_patterns: %s
 - developer_interests: %s
 - activity_patterns: %s
 - project_patterns: %s
+- automation: %s
 - collaboration_style: %s
+- maintainer_behavior: %s
+- style_evolution: %s
 
 Benchmark feedback:
 %s
 
-Actual review comparisons (original vs generated):
+Review scores by dimension (concern, severity, actionability, tone, technical accuracy):
+%s
+
+Actual comparisons (original vs generated reviews, PR descriptions, commit messages, and issue replies):
 %s
 
 Based on this feedback, output a refined version of the persona that better captures
-how this developer actually writes reviews. Focus your changes on the areas flagged
+how this developer actually writes reviews, PR descriptions, commit messages, and issue replies. Focus your changes on the areas flagged
 in the feedback. Keep what is already working well.
 
 Respond with a single JSON object (no markdown fences, no commentary):
@@ -101,6 +198,7 @@ Respond with a single JSON object (no markdown fences, no commentary):
 {
   "coding_philosophy": "...",
   "code_style_rules": "...",
+  "commit_message_style": "...",
   "review_priorities": "...",
   "review_decision_style": "...",
   "review_non_blocking_nits": "...",
@@ -109,11 +207,15 @@ Respond with a single JSON object (no markdown fences, no commentary):
   "communication_patterns": "...",
   "testing_philosophy": "...",
   "distinctive_traits": "...",
+  "anti_patterns": "...",
   "developer_interests": "...",
   "activity_patterns": "...",
   "project_patterns": "...",
-  "collaboration_style": "..."
+  "automation": "...",
+  "collaboration_style": "...",
+  "maintainer_behavior": "...",
+  "style_evolution": "..."
 }
 
 Every field must be a non-empty string. Be extremely specific - include concrete phrasing
-examples, formatting patterns, and characteristic word choices drawn from the original reviews.`
+examples, formatting patterns, and characteristic word choices drawn from the originals.`
@@ -0,0 +1,147 @@
+package benchmark
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/drpaneas/devlica/internal/analyzer"
+)
+
+// Report file names, written to the user's output directory.
+const (
+	ReportJSON     = "benchmark.json"
+	ReportMarkdown = "BENCHMARK.md"
+)
+
+// WriteReport writes r to dir as ReportJSON, with every pair and persona
+// version, and as the readable ReportMarkdown. It returns the paths written.
+func WriteReport(dir, username string, r *Result) ([]string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("creating benchmark report directory: %w", err)
+	}
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("encoding benchmark report: %w", err)
+	}
+	jsonPath := filepath.Join(dir, ReportJSON)
+	if err := os.WriteFile(jsonPath, append(data, '\n'), 0o644); err != nil {
+		return nil, fmt.Errorf("writing benchmark report: %w", err)
+	}
+	mdPath := filepath.Join(dir, ReportMarkdown)
+	if err := os.WriteFile(mdPath, []byte(r.Markdown(username)), 0o644); err != nil {
+		return nil, fmt.Errorf("writing benchmark report: %w", err)
+	}
+	return []string{jsonPath, mdPath}, nil
+}
+
+// Markdown renders r as a report: a score summary per iteration, then each
+// iteration's pairs side by side with the judges' feedback and the persona
+// fields its refinement rewrote.
+func (r *Result) Markdown(username string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Benchmark: %s\n\n", username)
+	fmt.Fprintf(&b, "Final score: **%.1f**/100 after %d iteration(s).\n\n", r.FinalScore, r.Iterations)
+	if r.StopReason == StopPlateau {
+		b.WriteString("Refinement stopped early: the score had stopped improving.\n\n")
+	}
+	if r.Selected != r.Iterations {
+		fmt.Fprintf(&b, "Iteration %d scored best, so its persona was kept.\n\n", r.Selected)
+	}
+	if r.Baseline != nil {
+		fmt.Fprintf(&b, "A generic senior engineer persona scored %.1f on the same samples, so the persona adds **%+.1f** points.\n\n", r.Baseline.Score, r.Lift())
+	}
+
+	b.WriteString("| Iteration | Score | Facets | Judges |\n|---|---|---|---|\n")
+	if r.Baseline != nil {
+		fmt.Fprintf(&b, "| baseline | %.1f | %s | %s |\n", r.Baseline.Score, r.Baseline.FormatFacets(), formatJudgeScores(r.Baseline.JudgeScores))
+	}
+	for _, iter := range r.History {
+		fmt.Fprintf(&b, "| %d | %.1f | %s | %s |\n", iter.Iteration, iter.Score, iter.FormatFacets(), formatJudgeScores(iter.JudgeScores))
+	}
+
+	if c := r.Calibration; c != nil {
+		b.WriteString("\n## Judge calibration\n\n")
+		fmt.Fprintf(&b, "Over %d pair(s) every judge scored, two judges' scores differ by %.1f points on average", c.Pairs, c.Spread)
+		if c.Correlation != nil {
+			fmt.Fprintf(&b, " and correlate at %.2f", *c.Correlation)
+		}
+		fmt.Fprintf(&b, ". Offsets from the first judge: %s. With the offsets removed, the final score is **%.1f**.\n", c.formatOffsets(), c.Score)
+	}
+
+	for i, iter := range r.History {
+		fmt.Fprintf(&b, "\n## Iteration %d: %.1f\n\n", iter.Iteration, iter.Score)
+		if dims := iter.FormatSubscores(); dims != "" {
+			fmt.Fprintf(&b, "Review dimensions: %s\n\n", dims)
+		}
+		if i > 0 {
+			if changed := changedFields(r.History[i-1].Synthesis, iter.Synthesis); len(changed) > 0 {
+				fmt.Fprintf(&b, "Refinement rewrote: %s.\n\n", strings.Join(changed, ", "))
+			}
+		}
+		for n, pair := range iter.Pairs {
+			facet := pair.Facet
+			if facet == "" {
+				facet = FacetReview
+			}
+			fmt.Fprintf(&b, "### %d. %s: %s (%.0f)\n\n", n+1, facet, pair.Path, pair.Score)
+			if dims := formatSubscores(pair.Subscores); dims != "" {
+				fmt.Fprintf(&b, "Dimensions: %s\n\n", dims)
+			}
+			if judges := formatJudgeScores(pair.JudgeScores); judges != "" {
+				fmt.Fprintf(&b, "Judges: %s\n\n", judges)
+			}
+			fmt.Fprintf(&b, "**Original**\n\n%s\n\n", quote(pair.Original))
+			fmt.Fprintf(&b, "**Generated**\n\n%s\n\n", quote(pair.Generated))
+			if pair.Human != nil {
+				fmt.Fprintf(&b, "Human: %.0f, judges: %.0f\n\n", pair.Human.Score, pair.LLMScore)
+			}
+			if pair.Feedback != "" {
+				fmt.Fprintf(&b, "**Feedback**\n\n%s\n\n", quote(pair.Feedback))
+			}
+		}
+	}
+	return strings.TrimRight(b.String(), "\n") + "\n"
+}
+
+// formatJudgeScores renders judge scores as "name=72.0", sorted by name.
+func formatJudgeScores(scores map[string]float64) string {
+	var parts []string
+	for _, name := range slices.Sorted(maps.Keys(scores)) {
+		parts = append(parts, fmt.Sprintf("%s=%.1f", name, scores[name]))
+	}
+	return strings.Join(parts, " ")
+}
+
+// quote renders s as a Markdown blockquote, so fences and headings in
+// the quoted text cannot break the report.
+func quote(s string) string {
+	lines := strings.Split(strings.TrimSpace(s), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight("> "+line, " ")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// changedFields returns the JSON names of the synthesis text fields that
+// differ between before and after.
+func changedFields(before, after *analyzer.SynthesisResult) []string {
+	if before == nil || after == nil {
+		return nil
+	}
+	a, b := reflect.ValueOf(before).Elem(), reflect.ValueOf(after).Elem()
+	var changed []string
+	for i := range a.NumField() {
+		if a.Field(i).Kind() != reflect.String || a.Field(i).String() == b.Field(i).String() {
+			continue
+		}
+		name, _, _ := strings.Cut(a.Type().Field(i).Tag.Get("json"), ",")
+		changed = append(changed, name)
+	}
+	return changed
+}
@@ -0,0 +1,79 @@
+package benchmark
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/drpaneas/devlica/internal/analyzer"
+)
+
+func TestWriteReport(t *testing.T) {
+	result := &Result{
+		FinalScore: 82,
+		Iterations: 2,
+		Selected:   2,
+		History: []IterationResult{
+			{
+				Iteration:   1,
+				Score:       60,
+				FacetScores: map[Facet]float64{FacetReview: 60},
+				Pairs: []ReviewPair{{
+					Original:  "nit: rename this\n# not a heading",
+					Generated: "Decision: comment",
+					Path:      "main.go",
+					Score:     60,
+					Facet:     FacetReview,
+					Feedback:  "too formal",
+				}},
+				Synthesis: &analyzer.SynthesisResult{ReviewVoice: "formal", CodingPhilosophy: "simple"},
+			},
+			{
+				Iteration:   2,
+				Score:       82,
+				FacetScores: map[Facet]float64{FacetReview: 82},
+				JudgeScores: map[string]float64{"openai/gpt-4o": 80, "ollama/qwen2.5": 84},
+				Pairs:       []ReviewPair{{Original: "nit: rename this", Generated: "nit: rename", Path: "main.go", Score: 82, Facet: FacetReview}},
+				Synthesis:   &analyzer.SynthesisResult{ReviewVoice: "terse", CodingPhilosophy: "simple"},
+			},
+		},
+	}
+	dir := filepath.Join(t.TempDir(), "octocat")
+	paths, err := WriteReport(dir, "octocat", result)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) != 2 {
+		t.Fatalf("paths = %v, want 2", paths)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, ReportJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got Result
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got.History) != 2 || got.History[0].Synthesis.ReviewVoice != "formal" || got.History[0].Pairs[0].Feedback != "too formal" {
+		t.Errorf("benchmark.json lost data: %s", data)
+	}
+
+	md, err := os.ReadFile(filepath.Join(dir, ReportMarkdown))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"Final score: **82.0**/100 after 2 iteration(s).",
+		"| 2 | 82.0 |  | ollama/qwen2.5=84.0 openai/gpt-4o=80.0 |",
+		"> # not a heading",
+		"Refinement rewrote: review_voice.",
+		"> too formal",
+	} {
+		if !strings.Contains(string(md), want) {
+			t.Errorf("BENCHMARK.md missing %q:\n%s", want, md)
+		}
+	}
+}
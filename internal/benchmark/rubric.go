@@ -0,0 +1,117 @@
+package benchmark
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Dimensions lists, in the order they are reported, the dimensions the
+// judge scores a dry-run review on: whether it raises the same concern,
+// with the same severity, as usefully, in the same tone, and correctly.
+var Dimensions = []string{"concern", "severity", "actionability", "tone", "accuracy"}
+
+// Rubric weights the review dimensions when a comparison's subscores are
+// combined into its score. A dimension it leaves out weighs 1, and one
+// weighted 0 is still reported but does not count.
+type Rubric map[string]float64
+
+// ParseRubric parses weights written as "concern=2,tone=0.5".
+func ParseRubric(s string) (Rubric, error) {
+	r := make(Rubric)
+	for _, part := range strings.Split(s, ",") {
+		name, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			return nil, fmt.Errorf("rubric weight %q: want dimension=weight", part)
+		}
+		name = strings.TrimSpace(name)
+		if !slices.Contains(Dimensions, name) {
+			return nil, fmt.Errorf("unknown rubric dimension %q: must be one of %s", name, strings.Join(Dimensions, ", "))
+		}
+		w, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil || w < 0 {
+			return nil, fmt.Errorf("rubric weight %q: weight must be a non-negative number", part)
+		}
+		r[name] = w
+	}
+	var total float64
+	for _, d := range Dimensions {
+		total += r.weight(d)
+	}
+	if total == 0 {
+		return nil, fmt.Errorf("rubric %q weighs every dimension 0", s)
+	}
+	return r, nil
+}
+
+func (r Rubric) weight(dim string) float64 {
+	if w, ok := r[dim]; ok {
+		return w
+	}
+	return 1
+}
+
+// combine returns the weighted mean of the subscores given, and false
+// when none of them counts.
+func (r Rubric) combine(subscores map[string]float64) (float64, bool) {
+	var sum, total float64
+	for _, d := range Dimensions {
+		if s, ok := subscores[d]; ok {
+			sum += r.weight(d) * s
+			total += r.weight(d)
+		}
+	}
+	if total == 0 {
+		return 0, false
+	}
+	return sum / total, true
+}
+
+// weakest returns the counted dimension with the lowest subscore, or ""
+// when there are none.
+func (r Rubric) weakest(subscores map[string]float64) string {
+	var worst string
+	for _, d := range Dimensions {
+		s, ok := subscores[d]
+		if !ok || r.weight(d) == 0 {
+			continue
+		}
+		if worst == "" || s < subscores[worst] {
+			worst = d
+		}
+	}
+	return worst
+}
+
+// SetRubric sets the weights of the review dimensions; nil weighs them
+// equally.
+func (b *Benchmarker) SetRubric(r Rubric) {
+	b.rubric = r
+}
+
+// FormatSubscores renders the mean review subscores as
+// "concern=72.0 severity=60.0 ...", or "" when the judges gave none.
+func (r IterationResult) FormatSubscores() string {
+	return formatSubscores(r.Subscores)
+}
+
+func formatSubscores(subscores map[string]float64) string {
+	var parts []string
+	for _, d := range Dimensions {
+		if s, ok := subscores[d]; ok {
+			parts = append(parts, fmt.Sprintf("%s=%.1f", d, s))
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// dimensionsText describes an iteration's subscores for the refine
+// prompt, naming the weakest dimension.
+func (b *Benchmarker) dimensionsText(iter *IterationResult) string {
+	scores := iter.FormatSubscores()
+	if scores == "" {
+		return "No per-dimension scores."
+	}
+	return fmt.Sprintf("%s\nWeakest: %s. Prioritize the persona fields that shape it.", scores, b.rubric.weakest(iter.Subscores))
+}
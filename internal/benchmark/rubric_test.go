@@ -0,0 +1,80 @@
+package benchmark
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/drpaneas/devlica/internal/analyzer"
+)
+
+func TestParseRubric(t *testing.T) {
+	r, err := ParseRubric("concern=2, tone=0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.weight("concern") != 2 || r.weight("tone") != 0 || r.weight("accuracy") != 1 {
+		t.Errorf("ParseRubric() = %v", r)
+	}
+	for _, bad := range []string{"voice=1", "concern", "concern=-1", "concern=x", "concern=0,severity=0,actionability=0,tone=0,accuracy=0"} {
+		if _, err := ParseRubric(bad); err == nil {
+			t.Errorf("ParseRubric(%q) should fail", bad)
+		}
+	}
+}
+
+func TestRubricCombine(t *testing.T) {
+	sub := map[string]float64{"concern": 90, "severity": 60, "actionability": 60, "tone": 20, "accuracy": 70}
+	tests := []struct {
+		rubric  Rubric
+		want    float64
+		weakest string
+	}{
+		{nil, 60, "tone"},
+		{Rubric{"concern": 6}, 75, "tone"},
+		{Rubric{"tone": 0}, 70, "severity"},
+	}
+	for _, tt := range tests {
+		got, ok := tt.rubric.combine(sub)
+		if !ok || got != tt.want {
+			t.Errorf("%v.combine() = %v, %v; want %v", tt.rubric, got, ok, tt.want)
+		}
+		if w := tt.rubric.weakest(sub); w != tt.weakest {
+			t.Errorf("%v.weakest() = %q, want %q", tt.rubric, w, tt.weakest)
+		}
+	}
+	if _, ok := Rubric(nil).combine(nil); ok {
+		t.Error("combine() without subscores should fall back to the judge's score")
+	}
+}
+
+func TestRunScoresRubricAndTargetsWeakest(t *testing.T) {
+	p := &recordingProvider{reply: `{"decision":"comment","comment":"ok","coding_philosophy":"x","feedback":"meh","score":99,` +
+		`"subscores":{"concern":80,"severity":80,"actionability":80,"tone":20,"accuracy":80}}`}
+	b := New(p)
+	b.SetMaxIterations(2)
+	b.SetRubric(Rubric{"concern": 3})
+	persona := &analyzer.Persona{Username: "alice", Synthesis: &analyzer.SynthesisResult{}}
+	heldOut := HeldOut{Reviews: []HeldOutReview{{Path: "main.go", DiffHunk: "@@ -1 +1 @@", Body: "nit"}}}
+
+	result, _, err := b.Run(context.Background(), persona, heldOut)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// (3*80 + 80 + 80 + 20 + 80) / 7
+	if got := result.History[0].Score; got != 500.0/7 {
+		t.Errorf("score = %v, want the rubric-weighted subscores, not the judge's 99", got)
+	}
+	if got := result.History[0].FormatSubscores(); got != "concern=80.0 severity=80.0 actionability=80.0 tone=20.0 accuracy=80.0" {
+		t.Errorf("FormatSubscores() = %q", got)
+	}
+	var refine string
+	for _, prompt := range p.prompts {
+		if strings.Contains(prompt, "mimicry benchmark") {
+			refine = prompt
+		}
+	}
+	if !strings.Contains(refine, "Weakest: tone.") {
+		t.Errorf("refine prompt should name the weakest dimension:\n%s", refine)
+	}
+}
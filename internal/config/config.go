@@ -4,49 +4,156 @@ import (
 	"fmt"
 	"os"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/drpaneas/devlica/internal/ghcrawl"
 	"github.com/drpaneas/devlica/internal/llm"
+	"github.com/drpaneas/devlica/internal/skill"
 )
 
 var validUsername = regexp.MustCompile(`^[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,37}[a-zA-Z0-9])?$`)
 
 // Config holds all runtime configuration for devlica.
 type Config struct {
-	Username        string
-	GitHubTokens    []string
-	PrivateToken    string
-	Provider        llm.ProviderName
-	Model           string
-	OllamaHost      string
-	APIKey          string
-	UseVertexAI     bool
-	VertexRegion    string
-	VertexProjectID string
-	OutputDir       string
-	MaxRepos        int
-	Exhaustive      bool
-	Verbose         bool
+	Username     string
+	GitHubTokens []string
+	PrivateToken string
+	Provider     llm.ProviderName
+	Model        string
+	// EnsembleProvider, when set, runs every analysis dimension a second
+	// time on this provider and reconciles the two results.
+	EnsembleProvider llm.ProviderName
+	EnsembleModel    string
+	EnsembleAPIKey   string
+	EmbedModel       string
+	ContextWindow    int
+	OllamaHost       string
+	APIKey           string
+	UseVertexAI      bool
+	VertexRegion     string
+	VertexProjectID  string
+	// OpenRouter routes the requests of every openrouter provider; its
+	// FallbackModels only apply to the primary one.
+	OpenRouter llm.OpenRouterRouting
+	// LLMRetries is how many times an LLM call that hit a rate limit, an
+	// overloaded server, or a network error is retried.
+	LLMRetries int
+	OutputDir  string
+	// Formats lists the assistants whose instruction files are written;
+	// empty writes Cursor skills.
+	Formats []skill.Format
+	// Skills lists the Cursor skills to write; empty writes the defaults.
+	Skills []string
+	// SkillData lists the structured files written next to each SKILL.md.
+	SkillData []skill.DataFormat
+	// Frontmatter is added to the frontmatter of every skill.
+	Frontmatter []skill.FrontmatterField
+	// Update keeps edits made outside the managed block of existing files.
+	Update bool
+	// Archive, when set, also packs the generated files into one archive.
+	Archive skill.ArchiveFormat
+	// Versioned writes into a new timestamped directory under
+	// OutputDir/<username> instead of overwriting the last run.
+	Versioned bool
+	// Stream, when set, writes the generated files to stdout instead of
+	// OutputDir, and skips the other files a run writes there.
+	Stream        skill.StreamFormat
+	PersonaOut    string
+	MaxRepos      int
+	Concurrency   int
+	Exhaustive    bool
+	UseGitClone   bool
+	GHArchive     string
+	RepoStrategy  string
+	ShowSelection bool
+	HTTPCacheDir  string
+	HTTPCacheTTL  time.Duration
+	PromptsDir    string
+	Anonymize     bool
+	Verbose       bool
+	// CompareEras, when set, holds the two windows whose separately
+	// analyzed personas are compared instead of generating skills.
+	CompareEras []EraWindow
+	// CrawlOut, when set, is where the crawl is saved for devlica analyze.
+	CrawlOut string
+	// Grounding enables the check of the synthesized persona against the
+	// crawled data.
+	Grounding bool
+	// MaxSkillTokens, when positive, caps the synthesized sections of each
+	// skill, condensing them with one more LLM call when they run over.
+	MaxSkillTokens int
+	// Recency, when enabled, samples recent activity more heavily for the
+	// analyses.
+	Recency RecencyCurve
+	// BenchSamples caps how many samples of each benchmark facet (reviews,
+	// PR descriptions, commit messages) are held out; 0 skips the benchmark.
+	BenchSamples int
+	// BenchTarget is the benchmark score, out of 100, that stops refinement.
+	BenchTarget float64
+	// BenchIterations bounds how many times the persona is scored.
+	BenchIterations int
+	// BenchPlateau is the least score gain over two iterations that keeps
+	// refining; 0 always runs BenchIterations unless the target is met.
+	BenchPlateau float64
+	// BenchRubric weights the review comparison dimensions by name; a
+	// dimension it leaves out weighs 1.
+	BenchRubric map[string]float64
+	// BenchBaseline also scores a generic persona to report the lift over.
+	BenchBaseline bool
+	// BenchHuman asks the user to score each benchmark sample too, their
+	// score counting BenchHumanWeight against the judges'.
+	BenchHuman       bool
+	BenchHumanWeight float64
+	// JudgeProvider, when set, scores the benchmark dry runs and refines
+	// the persona in place of Provider.
+	JudgeProvider llm.ProviderName
+	JudgeModel    string
+	JudgeAPIKey   string
+	// ExtraJudges also score the benchmark; their scores and the first
+	// judge's are combined with JudgeAggregate.
+	ExtraJudges    []JudgeConfig
+	JudgeAggregate string
 }
 
 // Validate checks that all required fields are set and consistent.
 func (c *Config) Validate() error {
-	if c.Username == "" {
-		return fmt.Errorf("github username is required")
+	if err := c.ValidateCrawl(); err != nil {
+		return err
 	}
-	if !validUsername.MatchString(c.Username) {
-		return fmt.Errorf("invalid github username %q", c.Username)
+	if !supportedProvider(c.Provider) {
+		return fmt.Errorf("unsupported LLM provider %q: must be %s", c.Provider, providerList)
 	}
-	if len(c.GitHubTokens) == 0 {
-		return fmt.Errorf("GITHUB_TOKEN environment variable is required")
+	if err := c.validateEnsemble(); err != nil {
+		return err
 	}
-	switch c.Provider {
-	case llm.ProviderOpenAI, llm.ProviderAnthropic, llm.ProviderOllama:
-	default:
-		return fmt.Errorf("unsupported LLM provider %q: must be openai, anthropic, or ollama", c.Provider)
+	if DefaultEmbedModel(c.Provider) == "" && c.EmbedModel != "" && c.EmbedModel != EmbedModelNone {
+		return fmt.Errorf("%s has no embeddings API: --embed-model is only supported with openai and ollama", c.Provider)
+	}
+	if err := c.validateOpenRouter(); err != nil {
+		return err
+	}
+	if err := c.validateBench(); err != nil {
+		return err
+	}
+	if err := ValidateOutput(c.Stream, c.Archive, c.Update, c.Versioned); err != nil {
+		return err
+	}
+	if err := c.validateJudge(); err != nil {
+		return err
+	}
+	if c.MaxSkillTokens != 0 && c.MaxSkillTokens < MinSkillTokens {
+		return fmt.Errorf("--max-skill-tokens must be at least %d", MinSkillTokens)
+	}
+	if c.LLMRetries < 0 {
+		return fmt.Errorf("--llm-retries must not be negative")
+	}
+	if c.ContextWindow != 0 && c.ContextWindow < MinContextWindow {
+		return fmt.Errorf("--context-window must be at least %d tokens", MinContextWindow)
 	}
-	if c.Provider == llm.ProviderOpenAI && c.APIKey == "" {
+	if needsAPIKey(c.Provider) && c.APIKey == "" {
 		return fmt.Errorf("%s requires an API key (set %s)", c.Provider, envKeyForProvider(c.Provider))
 	}
 	if c.Provider == llm.ProviderAnthropic {
@@ -61,28 +168,157 @@ func (c *Config) Validate() error {
 			return fmt.Errorf("anthropic requires ANTHROPIC_API_KEY or Vertex AI settings (CLAUDE_CODE_USE_VERTEX=1, ANTHROPIC_VERTEX_PROJECT_ID, CLOUD_ML_REGION)")
 		}
 	}
+	return nil
+}
+
+func (c *Config) validateEnsemble() error {
+	if c.EnsembleProvider == "" {
+		return nil
+	}
+	if !supportedProvider(c.EnsembleProvider) {
+		return fmt.Errorf("unsupported --ensemble-provider %q: must be %s", c.EnsembleProvider, providerList)
+	}
+	if c.EnsembleProvider == c.Provider && c.EnsembleModel == c.Model {
+		return fmt.Errorf("--ensemble-provider and --ensemble-model must differ from the primary provider and model")
+	}
+	if needsAPIKey(c.EnsembleProvider) && c.EnsembleAPIKey == "" {
+		return fmt.Errorf("ensemble provider %s requires an API key (set %s)", c.EnsembleProvider, envKeyForProvider(c.EnsembleProvider))
+	}
+	if c.EnsembleProvider == llm.ProviderAnthropic {
+		if c.UseVertexAI {
+			if c.VertexProjectID == "" || c.VertexRegion == "" {
+				return fmt.Errorf("anthropic Vertex AI mode requires ANTHROPIC_VERTEX_PROJECT_ID and CLOUD_ML_REGION")
+			}
+		} else if c.EnsembleAPIKey == "" {
+			return fmt.Errorf("ensemble provider anthropic requires ANTHROPIC_API_KEY or Vertex AI settings")
+		}
+	}
+	return nil
+}
+
+// validateOpenRouter checks that the OpenRouter routing flags have an
+// openrouter provider to route.
+func (c *Config) validateOpenRouter() error {
+	if len(c.OpenRouter.FallbackModels) > 0 && c.Provider != llm.ProviderOpenRouter {
+		return fmt.Errorf("--fallback-models requires --provider openrouter")
+	}
+	if c.OpenRouter.OnlyOrder && len(c.OpenRouter.Order) == 0 {
+		return fmt.Errorf("--openrouter-only requires --openrouter-order")
+	}
+	if len(c.OpenRouter.Order) == 0 {
+		return nil
+	}
+	uses := c.Provider == llm.ProviderOpenRouter || c.EnsembleProvider == llm.ProviderOpenRouter || c.JudgeProvider == llm.ProviderOpenRouter
+	for _, j := range c.ExtraJudges {
+		uses = uses || j.Provider == llm.ProviderOpenRouter
+	}
+	if !uses {
+		return fmt.Errorf("--openrouter-order requires an openrouter provider")
+	}
+	return nil
+}
+
+// ValidateOutput rejects output flags that cannot be combined: -stdout
+// leaves the filesystem alone, so nothing can be archived, updated, or
+// versioned, and a versioned run writes into a new directory, so there are
+// no files to update.
+func ValidateOutput(stream skill.StreamFormat, archive skill.ArchiveFormat, update, versioned bool) error {
+	if versioned && update {
+		return fmt.Errorf("--update cannot be combined with --versioned: each version is written to a new directory")
+	}
+	if stream == "" {
+		return nil
+	}
+	if archive != "" {
+		return fmt.Errorf("--archive cannot be combined with --stdout: there is no output directory to archive")
+	}
+	if update {
+		return fmt.Errorf("--update cannot be combined with --stdout: there are no existing files to update")
+	}
+	if versioned {
+		return fmt.Errorf("--versioned cannot be combined with --stdout: there is no output directory to version")
+	}
+	return nil
+}
+
+// validateBench checks the benchmark knobs. Iterations only matter when
+// reviews are held out.
+func (c *Config) validateBench() error {
+	if c.BenchSamples < 0 {
+		return fmt.Errorf("--bench-samples must not be negative")
+	}
+	if c.BenchTarget < 0 || c.BenchTarget > 100 {
+		return fmt.Errorf("--bench-target must be between 0 and 100")
+	}
+	if c.BenchSamples > 0 && c.BenchIterations < 1 {
+		return fmt.Errorf("--bench-iterations must be at least 1")
+	}
+	if c.BenchPlateau < 0 {
+		return fmt.Errorf("--bench-plateau must not be negative")
+	}
+	if c.BenchHumanWeight < 0 || c.BenchHumanWeight > 1 {
+		return fmt.Errorf("--bench-human-weight must be between 0 and 1")
+	}
+	return nil
+}
+
+// ValidateCrawl checks only the fields needed to talk to GitHub, so modes
+// that never call an LLM (such as estimate) do not require provider keys.
+func (c *Config) ValidateCrawl() error {
+	if c.Username == "" {
+		return fmt.Errorf("github username is required")
+	}
+	if !validUsername.MatchString(c.Username) {
+		return fmt.Errorf("invalid github username %q", c.Username)
+	}
 	if !c.Exhaustive && c.MaxRepos < 1 {
 		return fmt.Errorf("--max-repos must be at least 1")
 	}
 	if c.Exhaustive && c.MaxRepos < 0 {
 		return fmt.Errorf("--max-repos must be at least 0 when --exhaustive is enabled")
 	}
+	if c.Concurrency < 0 {
+		return fmt.Errorf("--concurrency must not be negative")
+	}
+	if c.HTTPCacheTTL < 0 {
+		return fmt.Errorf("--http-cache-ttl must not be negative")
+	}
+	if c.RepoStrategy != "" && !slices.Contains(ghcrawl.RepoStrategies, c.RepoStrategy) {
+		return fmt.Errorf("unknown --repo-strategy %q: must be one of %s", c.RepoStrategy, strings.Join(ghcrawl.RepoStrategies, ", "))
+	}
 	return nil
 }
 
 // LoadFromEnv populates environment-dependent fields (tokens, keys, hosts).
 func (c *Config) LoadFromEnv() {
 	c.GitHubTokens = loadGitHubTokens()
+	if len(c.GitHubTokens) == 0 {
+		if tok := ghCLIToken(); tok != "" {
+			c.GitHubTokens = []string{tok}
+		}
+	}
 	c.PrivateToken = os.Getenv("GITHUB_PRIVATE_TOKEN")
 	c.OllamaHost = os.Getenv("OLLAMA_HOST")
 	if c.OllamaHost == "" {
 		c.OllamaHost = "http://localhost:11434"
 	}
-	switch c.Provider {
-	case llm.ProviderOpenAI:
-		c.APIKey = os.Getenv("OPENAI_API_KEY")
-	case llm.ProviderAnthropic:
-		c.APIKey = os.Getenv("ANTHROPIC_API_KEY")
+	if key := envKeyForProvider(c.Provider); key != "" {
+		c.APIKey = os.Getenv(key)
+	}
+	if key := envKeyForProvider(c.EnsembleProvider); key != "" {
+		c.EnsembleAPIKey = os.Getenv(key)
+	}
+	if key := envKeyForProvider(c.JudgeProvider); key != "" {
+		c.JudgeAPIKey = os.Getenv(key)
+	}
+	usesAnthropic := c.Provider == llm.ProviderAnthropic || c.EnsembleProvider == llm.ProviderAnthropic || c.JudgeProvider == llm.ProviderAnthropic
+	for i, j := range c.ExtraJudges {
+		if key := envKeyForProvider(j.Provider); key != "" {
+			c.ExtraJudges[i].APIKey = os.Getenv(key)
+		}
+		usesAnthropic = usesAnthropic || j.Provider == llm.ProviderAnthropic
+	}
+	if usesAnthropic {
 		c.VertexProjectID = firstNonEmpty(
 			os.Getenv("ANTHROPIC_VERTEX_PROJECT_ID"),
 			os.Getenv("GCLOUD_PROJECT"),
@@ -119,17 +355,76 @@ func DefaultModel(provider llm.ProviderName) string {
 		return "claude-opus-4-6"
 	case llm.ProviderOllama:
 		return "llama3"
+	case llm.ProviderOpenRouter:
+		return "anthropic/claude-opus-4.6"
+	case llm.ProviderDeepSeek:
+		return "deepseek-chat"
+	default:
+		return ""
+	}
+}
+
+// DefaultLLMRetries is how many times a failed LLM call is retried unless
+// --llm-retries says otherwise.
+const DefaultLLMRetries = 3
+
+// MinContextWindow is the smallest --context-window accepted; smaller
+// windows cannot hold the analysis prompts themselves.
+const MinContextWindow = 4096
+
+// MinSkillTokens is the smallest --max-skill-tokens accepted; a skill any
+// smaller cannot describe a developer.
+const MinSkillTokens = 500
+
+// EmbedModelNone disables embedding-based deduplication.
+const EmbedModelNone = "none"
+
+// DefaultEmbedModel returns the default embedding model for the given
+// provider, or "" when the provider has no embeddings API.
+func DefaultEmbedModel(provider llm.ProviderName) string {
+	switch provider {
+	case llm.ProviderOpenAI:
+		return "text-embedding-3-small"
+	case llm.ProviderOllama:
+		return "nomic-embed-text"
 	default:
 		return ""
 	}
 }
 
+// providerList names the supported providers in error messages.
+const providerList = "openai, anthropic, ollama, openrouter, or deepseek"
+
+func supportedProvider(provider llm.ProviderName) bool {
+	switch provider {
+	case llm.ProviderOpenAI, llm.ProviderAnthropic, llm.ProviderOllama, llm.ProviderOpenRouter, llm.ProviderDeepSeek:
+		return true
+	default:
+		return false
+	}
+}
+
+// needsAPIKey reports whether provider cannot run without its API key.
+// Anthropic can use Vertex AI instead, and Ollama needs none.
+func needsAPIKey(provider llm.ProviderName) bool {
+	switch provider {
+	case llm.ProviderOpenAI, llm.ProviderOpenRouter, llm.ProviderDeepSeek:
+		return true
+	default:
+		return false
+	}
+}
+
 func envKeyForProvider(provider llm.ProviderName) string {
 	switch provider {
 	case llm.ProviderOpenAI:
 		return "OPENAI_API_KEY"
 	case llm.ProviderAnthropic:
 		return "ANTHROPIC_API_KEY"
+	case llm.ProviderOpenRouter:
+		return "OPENROUTER_API_KEY"
+	case llm.ProviderDeepSeek:
+		return "DEEPSEEK_API_KEY"
 	default:
 		return ""
 	}
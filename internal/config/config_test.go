@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/drpaneas/devlica/internal/llm"
+	"github.com/drpaneas/devlica/internal/skill"
 )
 
 func TestValidate(t *testing.T) {
@@ -122,14 +123,14 @@ func TestValidate(t *testing.T) {
 			wantErr: true,
 		},
 		{
-			name: "missing github token",
+			name: "missing github token runs degraded",
 			cfg: Config{
 				Username: "testuser",
 				Provider: llm.ProviderOpenAI,
 				APIKey:   "sk-fake",
 				MaxRepos: 10,
 			},
-			wantErr: true,
+			wantErr: false,
 		},
 		{
 			name: "invalid provider",
@@ -207,6 +208,399 @@ func TestValidate(t *testing.T) {
 				Exhaustive:   true,
 			},
 		},
+		{
+			name: "negative concurrency",
+			cfg: Config{
+				Username:     "testuser",
+				GitHubTokens: []string{"ghp_fake"},
+				Provider:     llm.ProviderOpenAI,
+				APIKey:       "sk-fake",
+				MaxRepos:     10,
+				Concurrency:  -1,
+			},
+			wantErr: true,
+		},
+		{
+			name: "known repo strategy",
+			cfg: Config{
+				Username:     "testuser",
+				GitHubTokens: []string{"ghp_fake"},
+				Provider:     llm.ProviderOpenAI,
+				APIKey:       "sk-fake",
+				MaxRepos:     10,
+				RepoStrategy: "pinned-first",
+			},
+		},
+		{
+			name: "unknown repo strategy",
+			cfg: Config{
+				Username:     "testuser",
+				GitHubTokens: []string{"ghp_fake"},
+				Provider:     llm.ProviderOpenAI,
+				APIKey:       "sk-fake",
+				MaxRepos:     10,
+				RepoStrategy: "random",
+			},
+			wantErr: true,
+		},
+		{
+			name: "anthropic with embed model",
+			cfg: Config{
+				Username:     "testuser",
+				GitHubTokens: []string{"ghp_fake"},
+				Provider:     llm.ProviderAnthropic,
+				APIKey:       "sk-ant-fake",
+				MaxRepos:     10,
+				EmbedModel:   "text-embedding-3-small",
+			},
+			wantErr: true,
+		},
+		{
+			name: "anthropic with embeddings disabled",
+			cfg: Config{
+				Username:     "testuser",
+				GitHubTokens: []string{"ghp_fake"},
+				Provider:     llm.ProviderAnthropic,
+				APIKey:       "sk-ant-fake",
+				MaxRepos:     10,
+				EmbedModel:   "none",
+			},
+		},
+		{
+			name: "ensemble with a second provider",
+			cfg: Config{
+				Username:         "testuser",
+				GitHubTokens:     []string{"ghp_fake"},
+				Provider:         llm.ProviderAnthropic,
+				Model:            "claude-opus-4-6",
+				APIKey:           "sk-ant-fake",
+				EnsembleProvider: llm.ProviderOpenAI,
+				EnsembleModel:    "gpt-4o",
+				EnsembleAPIKey:   "sk-fake",
+				MaxRepos:         10,
+			},
+		},
+		{
+			name: "valid openrouter config with routing",
+			cfg: Config{
+				Username:     "testuser",
+				GitHubTokens: []string{"ghp_fake"},
+				Provider:     llm.ProviderOpenRouter,
+				APIKey:       "sk-or-fake",
+				OpenRouter:   llm.OpenRouterRouting{Order: []string{"anthropic"}, OnlyOrder: true, FallbackModels: []string{"openai/gpt-4o"}},
+				MaxRepos:     10,
+			},
+		},
+		{
+			name: "negative llm retries",
+			cfg: Config{
+				Username:     "testuser",
+				GitHubTokens: []string{"ghp_fake"},
+				Provider:     llm.ProviderOllama,
+				LLMRetries:   -1,
+				MaxRepos:     10,
+			},
+			wantErr: true,
+		},
+		{
+			name: "valid deepseek config",
+			cfg: Config{
+				Username:     "testuser",
+				GitHubTokens: []string{"ghp_fake"},
+				Provider:     llm.ProviderDeepSeek,
+				APIKey:       "sk-fake",
+				MaxRepos:     10,
+			},
+		},
+		{
+			name: "deepseek without api key",
+			cfg: Config{
+				Username:     "testuser",
+				GitHubTokens: []string{"ghp_fake"},
+				Provider:     llm.ProviderDeepSeek,
+				MaxRepos:     10,
+			},
+			wantErr: true,
+		},
+		{
+			name: "openrouter without api key",
+			cfg: Config{
+				Username:     "testuser",
+				GitHubTokens: []string{"ghp_fake"},
+				Provider:     llm.ProviderOpenRouter,
+				MaxRepos:     10,
+			},
+			wantErr: true,
+		},
+		{
+			name: "openrouter with embed model",
+			cfg: Config{
+				Username:     "testuser",
+				GitHubTokens: []string{"ghp_fake"},
+				Provider:     llm.ProviderOpenRouter,
+				APIKey:       "sk-or-fake",
+				EmbedModel:   "text-embedding-3-small",
+				MaxRepos:     10,
+			},
+			wantErr: true,
+		},
+		{
+			name: "fallback models without openrouter",
+			cfg: Config{
+				Username:     "testuser",
+				GitHubTokens: []string{"ghp_fake"},
+				Provider:     llm.ProviderOllama,
+				OpenRouter:   llm.OpenRouterRouting{FallbackModels: []string{"openai/gpt-4o"}},
+				MaxRepos:     10,
+			},
+			wantErr: true,
+		},
+		{
+			name: "openrouter order for an openrouter judge",
+			cfg: Config{
+				Username:      "testuser",
+				GitHubTokens:  []string{"ghp_fake"},
+				Provider:      llm.ProviderOllama,
+				JudgeProvider: llm.ProviderOpenRouter,
+				JudgeModel:    "openai/gpt-4o",
+				JudgeAPIKey:   "sk-or-fake",
+				OpenRouter:    llm.OpenRouterRouting{Order: []string{"openai"}},
+				MaxRepos:      10,
+			},
+		},
+		{
+			name: "openrouter order without openrouter",
+			cfg: Config{
+				Username:     "testuser",
+				GitHubTokens: []string{"ghp_fake"},
+				Provider:     llm.ProviderOllama,
+				OpenRouter:   llm.OpenRouterRouting{Order: []string{"openai"}},
+				MaxRepos:     10,
+			},
+			wantErr: true,
+		},
+		{
+			name: "openrouter only without order",
+			cfg: Config{
+				Username:     "testuser",
+				GitHubTokens: []string{"ghp_fake"},
+				Provider:     llm.ProviderOpenRouter,
+				APIKey:       "sk-or-fake",
+				OpenRouter:   llm.OpenRouterRouting{OnlyOrder: true},
+				MaxRepos:     10,
+			},
+			wantErr: true,
+		},
+		{
+			name: "ensemble provider without api key",
+			cfg: Config{
+				Username:         "testuser",
+				GitHubTokens:     []string{"ghp_fake"},
+				Provider:         llm.ProviderAnthropic,
+				APIKey:           "sk-ant-fake",
+				EnsembleProvider: llm.ProviderOpenAI,
+				EnsembleModel:    "gpt-4o",
+				MaxRepos:         10,
+			},
+			wantErr: true,
+		},
+		{
+			name: "ensemble identical to primary",
+			cfg: Config{
+				Username:         "testuser",
+				GitHubTokens:     []string{"ghp_fake"},
+				Provider:         llm.ProviderOllama,
+				Model:            "llama3",
+				EnsembleProvider: llm.ProviderOllama,
+				EnsembleModel:    "llama3",
+				MaxRepos:         10,
+			},
+			wantErr: true,
+		},
+		{
+			name: "unknown ensemble provider",
+			cfg: Config{
+				Username:         "testuser",
+				GitHubTokens:     []string{"ghp_fake"},
+				Provider:         llm.ProviderOllama,
+				EnsembleProvider: "gemini",
+				MaxRepos:         10,
+			},
+			wantErr: true,
+		},
+		{
+			name: "context window override",
+			cfg: Config{
+				Username:      "testuser",
+				GitHubTokens:  []string{"ghp_fake"},
+				Provider:      llm.ProviderOllama,
+				MaxRepos:      10,
+				ContextWindow: 32768,
+			},
+		},
+		{
+			name: "context window too small",
+			cfg: Config{
+				Username:      "testuser",
+				GitHubTokens:  []string{"ghp_fake"},
+				Provider:      llm.ProviderOllama,
+				MaxRepos:      10,
+				ContextWindow: 1000,
+			},
+			wantErr: true,
+		},
+		{
+			name: "benchmark knobs",
+			cfg: Config{
+				Username:        "testuser",
+				Provider:        llm.ProviderOllama,
+				MaxRepos:        10,
+				BenchSamples:    10,
+				BenchTarget:     90,
+				BenchIterations: 3,
+			},
+		},
+		{
+			name: "benchmark disabled",
+			cfg: Config{
+				Username: "testuser",
+				Provider: llm.ProviderOllama,
+				MaxRepos: 10,
+			},
+		},
+		{
+			name: "negative bench samples",
+			cfg: Config{
+				Username:     "testuser",
+				Provider:     llm.ProviderOllama,
+				MaxRepos:     10,
+				BenchSamples: -1,
+			},
+			wantErr: true,
+		},
+		{
+			name: "bench target over 100",
+			cfg: Config{
+				Username:        "testuser",
+				Provider:        llm.ProviderOllama,
+				MaxRepos:        10,
+				BenchSamples:    3,
+				BenchTarget:     120,
+				BenchIterations: 5,
+			},
+			wantErr: true,
+		},
+		{
+			name: "judge on another provider",
+			cfg: Config{
+				Username:      "testuser",
+				Provider:      llm.ProviderOllama,
+				Model:         "llama3.1",
+				MaxRepos:      10,
+				JudgeProvider: llm.ProviderOpenAI,
+				JudgeModel:    "gpt-4o",
+				JudgeAPIKey:   "sk-fake",
+			},
+		},
+		{
+			name: "judge same as primary",
+			cfg: Config{
+				Username:      "testuser",
+				Provider:      llm.ProviderOllama,
+				Model:         "llama3.1",
+				MaxRepos:      10,
+				JudgeProvider: llm.ProviderOllama,
+				JudgeModel:    "llama3.1",
+			},
+			wantErr: true,
+		},
+		{
+			name: "judge openai without key",
+			cfg: Config{
+				Username:      "testuser",
+				Provider:      llm.ProviderOllama,
+				MaxRepos:      10,
+				JudgeProvider: llm.ProviderOpenAI,
+				JudgeModel:    "gpt-4o",
+			},
+			wantErr: true,
+		},
+		{
+			name: "bench without iterations",
+			cfg: Config{
+				Username:     "testuser",
+				Provider:     llm.ProviderOllama,
+				MaxRepos:     10,
+				BenchSamples: 3,
+			},
+			wantErr: true,
+		},
+		{
+			name: "human weight over 1",
+			cfg: Config{
+				Username:         "testuser",
+				Provider:         llm.ProviderOllama,
+				MaxRepos:         10,
+				BenchSamples:     3,
+				BenchIterations:  5,
+				BenchHuman:       true,
+				BenchHumanWeight: 1.5,
+			},
+			wantErr: true,
+		},
+		{
+			name: "stdout",
+			cfg: Config{
+				Username: "testuser",
+				Provider: llm.ProviderOllama,
+				MaxRepos: 10,
+				Stream:   skill.StreamJSON,
+			},
+		},
+		{
+			name: "stdout with archive",
+			cfg: Config{
+				Username: "testuser",
+				Provider: llm.ProviderOllama,
+				MaxRepos: 10,
+				Stream:   skill.StreamText,
+				Archive:  skill.ArchiveZip,
+			},
+			wantErr: true,
+		},
+		{
+			name: "stdout with update",
+			cfg: Config{
+				Username: "testuser",
+				Provider: llm.ProviderOllama,
+				MaxRepos: 10,
+				Stream:   skill.StreamText,
+				Update:   true,
+			},
+			wantErr: true,
+		},
+		{
+			name: "versioned with update",
+			cfg: Config{
+				Username:  "testuser",
+				Provider:  llm.ProviderOllama,
+				MaxRepos:  10,
+				Versioned: true,
+				Update:    true,
+			},
+			wantErr: true,
+		},
+		{
+			name: "versioned with stdout",
+			cfg: Config{
+				Username:  "testuser",
+				Provider:  llm.ProviderOllama,
+				MaxRepos:  10,
+				Versioned: true,
+				Stream:    skill.StreamJSON,
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -272,6 +666,8 @@ func TestDefaultModel(t *testing.T) {
 		{llm.ProviderOpenAI, "gpt-4o"},
 		{llm.ProviderAnthropic, "claude-opus-4-6"},
 		{llm.ProviderOllama, "llama3"},
+		{llm.ProviderOpenRouter, "anthropic/claude-opus-4.6"},
+		{llm.ProviderDeepSeek, "deepseek-chat"},
 		{"unknown", ""},
 	}
 
@@ -285,7 +681,29 @@ func TestDefaultModel(t *testing.T) {
 	}
 }
 
+func TestDefaultEmbedModel(t *testing.T) {
+	tests := []struct {
+		provider llm.ProviderName
+		want     string
+	}{
+		{llm.ProviderOpenAI, "text-embedding-3-small"},
+		{llm.ProviderAnthropic, ""},
+		{llm.ProviderOllama, "nomic-embed-text"},
+		{llm.ProviderOpenRouter, ""},
+		{llm.ProviderDeepSeek, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.provider), func(t *testing.T) {
+			if got := DefaultEmbedModel(tt.provider); got != tt.want {
+				t.Errorf("DefaultEmbedModel(%q) = %q, want %q", tt.provider, got, tt.want)
+			}
+		})
+	}
+}
+
 func TestLoadFromEnv_AnthropicVertex(t *testing.T) {
+	stubGHAuthToken(t, "")
 	t.Setenv("GITHUB_TOKEN", "tok-primary")
 	t.Setenv("ANTHROPIC_API_KEY", "")
 	t.Setenv("CLAUDE_CODE_USE_VERTEX", "1")
@@ -307,6 +725,7 @@ func TestLoadFromEnv_AnthropicVertex(t *testing.T) {
 }
 
 func TestLoadFromEnv_AnthropicVertexRequiresFlag(t *testing.T) {
+	stubGHAuthToken(t, "")
 	t.Setenv("GITHUB_TOKEN", "tok-primary")
 	t.Setenv("ANTHROPIC_API_KEY", "")
 	t.Setenv("CLAUDE_CODE_USE_VERTEX", "0")
@@ -320,3 +739,34 @@ func TestLoadFromEnv_AnthropicVertexRequiresFlag(t *testing.T) {
 		t.Fatalf("expected UseVertexAI to be false when CLAUDE_CODE_USE_VERTEX is not enabled")
 	}
 }
+
+func TestValidateCrawl_DoesNotRequireProvider(t *testing.T) {
+	cfg := Config{
+		Username:     "testuser",
+		GitHubTokens: []string{"ghp_fake"},
+		Provider:     llm.ProviderAnthropic,
+		MaxRepos:     10,
+	}
+	if err := cfg.ValidateCrawl(); err != nil {
+		t.Fatalf("ValidateCrawl() unexpected error: %v", err)
+	}
+	if err := cfg.Validate(); err == nil {
+		t.Fatal("Validate() should still require an anthropic API key")
+	}
+}
+
+func TestLoadFromEnv_OpenRouter(t *testing.T) {
+	stubGHAuthToken(t, "")
+	t.Setenv("GITHUB_TOKEN", "tok-primary")
+	t.Setenv("OPENROUTER_API_KEY", "sk-or-key")
+
+	cfg := Config{Provider: llm.ProviderOllama, JudgeProvider: llm.ProviderOpenRouter}
+	cfg.LoadFromEnv()
+
+	if cfg.JudgeAPIKey != "sk-or-key" {
+		t.Errorf("JudgeAPIKey = %q, want OPENROUTER_API_KEY", cfg.JudgeAPIKey)
+	}
+	if cfg.APIKey != "" {
+		t.Errorf("APIKey = %q, want none for ollama", cfg.APIKey)
+	}
+}
@@ -0,0 +1,72 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// EraWindow is a span of whole calendar years, [From, To).
+type EraWindow struct {
+	Label string
+	From  time.Time
+	To    time.Time
+}
+
+// ParseEraWindows parses the --compare-eras value: two comma-separated year
+// ranges such as "2019-2021,2022-2024", where a single year ("2020") is a
+// range of one. The ranges must not overlap; the older one comes first.
+func ParseEraWindows(s string) ([]EraWindow, error) {
+	parts := strings.Split(s, ",")
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("--compare-eras needs two year ranges, such as 2019-2021,2022-2024")
+	}
+	var windows []EraWindow
+	for _, p := range parts {
+		w, err := parseEraWindow(strings.TrimSpace(p))
+		if err != nil {
+			return nil, err
+		}
+		windows = append(windows, w)
+	}
+	if windows[0].From.After(windows[1].From) {
+		windows[0], windows[1] = windows[1], windows[0]
+	}
+	if windows[0].To.After(windows[1].From) {
+		return nil, fmt.Errorf("--compare-eras ranges %s and %s overlap", windows[0].Label, windows[1].Label)
+	}
+	return windows, nil
+}
+
+func parseEraWindow(s string) (EraWindow, error) {
+	first, last, isRange := strings.Cut(s, "-")
+	if !isRange {
+		last = first
+	}
+	from, err := parseYear(first)
+	if err != nil {
+		return EraWindow{}, fmt.Errorf("invalid --compare-eras range %q: %w", s, err)
+	}
+	to, err := parseYear(last)
+	if err != nil {
+		return EraWindow{}, fmt.Errorf("invalid --compare-eras range %q: %w", s, err)
+	}
+	if to < from {
+		return EraWindow{}, fmt.Errorf("invalid --compare-eras range %q: ends before it starts", s)
+	}
+	return EraWindow{
+		Label: s,
+		From:  time.Date(from, 1, 1, 0, 0, 0, 0, time.UTC),
+		To:    time.Date(to+1, 1, 1, 0, 0, 0, 0, time.UTC),
+	}, nil
+}
+
+// parseYear accepts years from GitHub's launch onwards.
+func parseYear(s string) (int, error) {
+	y, err := strconv.Atoi(s)
+	if err != nil || y < 2008 || y > 9999 {
+		return 0, fmt.Errorf("%q is not a year", s)
+	}
+	return y, nil
+}
@@ -0,0 +1,49 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseEraWindows(t *testing.T) {
+	year := func(y int) time.Time { return time.Date(y, 1, 1, 0, 0, 0, 0, time.UTC) }
+	tests := []struct {
+		in      string
+		want    []EraWindow
+		wantErr bool
+	}{
+		{in: "2019-2021,2022-2024", want: []EraWindow{
+			{Label: "2019-2021", From: year(2019), To: year(2022)},
+			{Label: "2022-2024", From: year(2022), To: year(2025)},
+		}},
+		{in: "2023, 2015-2016", want: []EraWindow{
+			{Label: "2015-2016", From: year(2015), To: year(2017)},
+			{Label: "2023", From: year(2023), To: year(2024)},
+		}},
+		{in: "2019-2021", wantErr: true},
+		{in: "2019-2021,2021-2023", wantErr: true},
+		{in: "2021-2019,2022", wantErr: true},
+		{in: "2019-x,2022", wantErr: true},
+		{in: "1999,2022", wantErr: true},
+		{in: "2019,2020,2021", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseEraWindows(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseEraWindows(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("ParseEraWindows(%q) = %+v, want %+v", tt.in, got, tt.want)
+			}
+			for i := range got {
+				if got[i].Label != tt.want[i].Label || !got[i].From.Equal(tt.want[i].From) || !got[i].To.Equal(tt.want[i].To) {
+					t.Errorf("window %d = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
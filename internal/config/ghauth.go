@@ -0,0 +1,87 @@
+package config
+
+import (
+	"bufio"
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ghAuthToken runs `gh auth token`. It is a variable so tests can stub it.
+var ghAuthToken = func() string {
+	if _, err := exec.LookPath("gh"); err != nil {
+		return ""
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, "gh", "auth", "token", "--hostname", "github.com").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// ghCLIToken discovers a token from an authenticated GitHub CLI: first
+// GH_TOKEN, then `gh auth token` (which also reads the system keyring), and
+// finally the oauth_token in gh's hosts.yml for older gh versions that store
+// it in plain text.
+func ghCLIToken() string {
+	if tok := strings.TrimSpace(os.Getenv("GH_TOKEN")); tok != "" {
+		return tok
+	}
+	if tok := ghAuthToken(); tok != "" {
+		return tok
+	}
+	return hostsFileToken(ghConfigDir())
+}
+
+func ghConfigDir() string {
+	if dir := os.Getenv("GH_CONFIG_DIR"); dir != "" {
+		return dir
+	}
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "gh")
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".config", "gh")
+}
+
+// hostsFileToken reads the github.com oauth_token from gh's hosts.yml. The
+// file layout is simple enough to scan line by line without a YAML parser.
+func hostsFileToken(dir string) string {
+	if dir == "" {
+		return ""
+	}
+	f, err := os.Open(filepath.Join(dir, "hosts.yml"))
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	inGitHub := false
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" || strings.HasPrefix(strings.TrimSpace(line), "#") {
+			continue
+		}
+		if !strings.HasPrefix(line, " ") && !strings.HasPrefix(line, "\t") {
+			inGitHub = strings.TrimSpace(line) == "github.com:"
+			continue
+		}
+		if !inGitHub {
+			continue
+		}
+		key, value, ok := strings.Cut(strings.TrimSpace(line), ":")
+		if ok && key == "oauth_token" {
+			return strings.Trim(strings.TrimSpace(value), `"'`)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,74 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func stubGHAuthToken(t *testing.T, token string) {
+	t.Helper()
+	orig := ghAuthToken
+	ghAuthToken = func() string { return token }
+	t.Cleanup(func() { ghAuthToken = orig })
+}
+
+func TestGHCLIToken(t *testing.T) {
+	t.Run("GH_TOKEN wins", func(t *testing.T) {
+		t.Setenv("GH_TOKEN", "gho_env")
+		stubGHAuthToken(t, "gho_cli")
+		if got := ghCLIToken(); got != "gho_env" {
+			t.Errorf("ghCLIToken() = %q, want gho_env", got)
+		}
+	})
+
+	t.Run("gh auth token", func(t *testing.T) {
+		t.Setenv("GH_TOKEN", "")
+		stubGHAuthToken(t, "gho_cli")
+		if got := ghCLIToken(); got != "gho_cli" {
+			t.Errorf("ghCLIToken() = %q, want gho_cli", got)
+		}
+	})
+
+	t.Run("hosts file fallback", func(t *testing.T) {
+		dir := t.TempDir()
+		hosts := `github.example.com:
+    oauth_token: gho_enterprise
+github.com:
+    user: octocat
+    oauth_token: gho_file
+    git_protocol: https
+`
+		if err := os.WriteFile(filepath.Join(dir, "hosts.yml"), []byte(hosts), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		t.Setenv("GH_TOKEN", "")
+		t.Setenv("GH_CONFIG_DIR", dir)
+		stubGHAuthToken(t, "")
+		if got := ghCLIToken(); got != "gho_file" {
+			t.Errorf("ghCLIToken() = %q, want gho_file", got)
+		}
+	})
+
+	t.Run("nothing configured", func(t *testing.T) {
+		t.Setenv("GH_TOKEN", "")
+		t.Setenv("GH_CONFIG_DIR", t.TempDir())
+		stubGHAuthToken(t, "")
+		if got := ghCLIToken(); got != "" {
+			t.Errorf("ghCLIToken() = %q, want empty", got)
+		}
+	})
+}
+
+func TestLoadFromEnv_FallsBackToGHCLI(t *testing.T) {
+	t.Setenv("GITHUB_TOKEN", "")
+	t.Setenv("GITHUB_TOKEN_1", "")
+	t.Setenv("GH_TOKEN", "")
+	stubGHAuthToken(t, "gho_cli")
+
+	var cfg Config
+	cfg.LoadFromEnv()
+	if len(cfg.GitHubTokens) != 1 || cfg.GitHubTokens[0] != "gho_cli" {
+		t.Errorf("GitHubTokens = %v, want [gho_cli]", cfg.GitHubTokens)
+	}
+}
@@ -0,0 +1,84 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/drpaneas/devlica/internal/llm"
+)
+
+// MaxExtraJudges bounds -extra-judge, so at most three judges score each
+// benchmark comparison.
+const MaxExtraJudges = 2
+
+// JudgeConfig is an additional benchmark judge given with -extra-judge.
+type JudgeConfig struct {
+	Provider llm.ProviderName
+	Model    string
+	APIKey   string
+}
+
+// Name labels the judge's scores, as "provider/model".
+func (j JudgeConfig) Name() string {
+	return string(j.Provider) + "/" + j.Model
+}
+
+// ParseJudge parses a judge written as "provider" or "provider:model". The
+// model may itself contain colons, as Ollama tags do ("ollama:llama3.1:8b").
+func ParseJudge(s string) (JudgeConfig, error) {
+	provider, model, _ := strings.Cut(strings.TrimSpace(s), ":")
+	j := JudgeConfig{Provider: llm.ProviderName(provider), Model: model}
+	if !supportedProvider(j.Provider) {
+		return JudgeConfig{}, fmt.Errorf("unsupported judge provider %q in %q: must be %s", provider, s, providerList)
+	}
+	return j, nil
+}
+
+// validateJudge checks the -judge-provider and -extra-judge settings: each
+// judge is a supported provider with credentials, and no two judges, nor a
+// judge and the primary model, are the same model.
+func (c *Config) validateJudge() error {
+	if len(c.ExtraJudges) > MaxExtraJudges {
+		return fmt.Errorf("--extra-judge can be given at most %d times", MaxExtraJudges)
+	}
+	switch c.JudgeAggregate {
+	case "", "median", "trimmed-mean":
+	default:
+		return fmt.Errorf("unknown --judge-aggregate %q: must be median or trimmed-mean", c.JudgeAggregate)
+	}
+	seen := map[string]string{string(c.Provider) + "/" + c.Model: "the primary provider and model"}
+	check := func(flag string, provider llm.ProviderName, model, apiKey string) error {
+		if !supportedProvider(provider) {
+			return fmt.Errorf("unsupported %s %q: must be %s", flag, provider, providerList)
+		}
+		name := string(provider) + "/" + model
+		if other, ok := seen[name]; ok {
+			return fmt.Errorf("%s %s must differ from %s", flag, name, other)
+		}
+		seen[name] = flag + " " + name
+		if needsAPIKey(provider) && apiKey == "" {
+			return fmt.Errorf("judge provider %s requires an API key (set %s)", provider, envKeyForProvider(provider))
+		}
+		if provider == llm.ProviderAnthropic {
+			if c.UseVertexAI {
+				if c.VertexProjectID == "" || c.VertexRegion == "" {
+					return fmt.Errorf("anthropic Vertex AI mode requires ANTHROPIC_VERTEX_PROJECT_ID and CLOUD_ML_REGION")
+				}
+			} else if apiKey == "" {
+				return fmt.Errorf("judge provider anthropic requires ANTHROPIC_API_KEY or Vertex AI settings")
+			}
+		}
+		return nil
+	}
+	if c.JudgeProvider != "" {
+		if err := check("--judge-provider", c.JudgeProvider, c.JudgeModel, c.JudgeAPIKey); err != nil {
+			return err
+		}
+	}
+	for _, j := range c.ExtraJudges {
+		if err := check("--extra-judge", j.Provider, j.Model, j.APIKey); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/drpaneas/devlica/internal/llm"
+)
+
+func TestParseJudge(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    JudgeConfig
+		wantErr bool
+	}{
+		{in: "openai", want: JudgeConfig{Provider: llm.ProviderOpenAI}},
+		{in: "anthropic:claude-sonnet-4-5", want: JudgeConfig{Provider: llm.ProviderAnthropic, Model: "claude-sonnet-4-5"}},
+		{in: "ollama:llama3.1:8b", want: JudgeConfig{Provider: llm.ProviderOllama, Model: "llama3.1:8b"}},
+		{in: "gemini:pro", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseJudge(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseJudge(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseJudge(%q) = %+v, want %+v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateJudge(t *testing.T) {
+	base := Config{Provider: llm.ProviderOllama, Model: "llama3.1"}
+	tests := []struct {
+		name    string
+		edit    func(*Config)
+		wantErr bool
+	}{
+		{"no judges", func(*Config) {}, false},
+		{"panel", func(c *Config) {
+			c.JudgeProvider, c.JudgeModel, c.JudgeAPIKey = llm.ProviderOpenAI, "gpt-4o", "sk"
+			c.ExtraJudges = []JudgeConfig{{Provider: llm.ProviderOllama, Model: "qwen2.5"}, {Provider: llm.ProviderOpenAI, Model: "gpt-4.1", APIKey: "sk"}}
+			c.JudgeAggregate = "trimmed-mean"
+		}, false},
+		{"extra judge with primary", func(c *Config) {
+			c.ExtraJudges = []JudgeConfig{{Provider: llm.ProviderOllama, Model: "qwen2.5"}}
+		}, false},
+		{"duplicate judges", func(c *Config) {
+			c.ExtraJudges = []JudgeConfig{{Provider: llm.ProviderOllama, Model: "qwen2.5"}, {Provider: llm.ProviderOllama, Model: "qwen2.5"}}
+		}, true},
+		{"extra judge is the primary model", func(c *Config) {
+			c.ExtraJudges = []JudgeConfig{{Provider: llm.ProviderOllama, Model: "llama3.1"}}
+		}, true},
+		{"too many judges", func(c *Config) {
+			c.ExtraJudges = []JudgeConfig{{Provider: llm.ProviderOllama, Model: "a"}, {Provider: llm.ProviderOllama, Model: "b"}, {Provider: llm.ProviderOllama, Model: "c"}}
+		}, true},
+		{"missing key", func(c *Config) {
+			c.ExtraJudges = []JudgeConfig{{Provider: llm.ProviderAnthropic, Model: "claude"}}
+		}, true},
+		{"unknown aggregate", func(c *Config) { c.JudgeAggregate = "mode" }, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := base
+			tt.edit(&cfg)
+			if err := cfg.validateJudge(); (err != nil) != tt.wantErr {
+				t.Errorf("validateJudge() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package config
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Recency curve shapes.
+const (
+	RecencyExp    = "exp"
+	RecencyLinear = "linear"
+	RecencyStep   = "step"
+)
+
+// MinRecencyWeight is the weight no item falls below, so even a decade-old
+// era keeps a few examples for the style-evolution analysis to contrast.
+const MinRecencyWeight = 0.05
+
+// RecencyCurve maps an item's age to the chance it is sampled. The zero
+// value is off: every item is kept.
+type RecencyCurve struct {
+	Shape string
+	// Span is the half-life of an exp curve, the age at which a linear
+	// curve reaches its floor, or the age a step curve drops at.
+	Span time.Duration
+}
+
+var recencySpan = regexp.MustCompile(`^(\d+(?:\.\d+)?)(d|w|mo|y)$`)
+
+var recencyUnits = map[string]time.Duration{
+	"d":  24 * time.Hour,
+	"w":  7 * 24 * time.Hour,
+	"mo": 30 * 24 * time.Hour,
+	"y":  365 * 24 * time.Hour,
+}
+
+// ParseRecencyCurve parses the --recency value, SHAPE:SPAN, such as
+// "exp:2y", "linear:10y", or "step:18mo". Spans take a d, w, mo, or y
+// suffix.
+func ParseRecencyCurve(s string) (RecencyCurve, error) {
+	shape, span, ok := strings.Cut(s, ":")
+	if !ok {
+		return RecencyCurve{}, fmt.Errorf("invalid --recency %q: want SHAPE:SPAN, such as exp:2y", s)
+	}
+	switch shape {
+	case RecencyExp, RecencyLinear, RecencyStep:
+	default:
+		return RecencyCurve{}, fmt.Errorf("invalid --recency shape %q: must be %s, %s, or %s", shape, RecencyExp, RecencyLinear, RecencyStep)
+	}
+	m := recencySpan.FindStringSubmatch(span)
+	if m == nil {
+		return RecencyCurve{}, fmt.Errorf("invalid --recency span %q: want a number with a d, w, mo, or y suffix", span)
+	}
+	n, err := strconv.ParseFloat(m[1], 64)
+	if err != nil || n <= 0 {
+		return RecencyCurve{}, fmt.Errorf("invalid --recency span %q: must be positive", span)
+	}
+	return RecencyCurve{Shape: shape, Span: time.Duration(n * float64(recencyUnits[m[2]]))}, nil
+}
+
+// Enabled reports whether the curve weights anything.
+func (c RecencyCurve) Enabled() bool {
+	return c.Shape != ""
+}
+
+// Weight returns the chance an item of the given age is sampled, between
+// MinRecencyWeight and 1. Items from the future count as new.
+func (c RecencyCurve) Weight(age time.Duration) float64 {
+	if !c.Enabled() || age <= 0 {
+		return 1
+	}
+	x := float64(age) / float64(c.Span)
+	var w float64
+	switch c.Shape {
+	case RecencyExp:
+		w = math.Pow(0.5, x)
+	case RecencyLinear:
+		w = 1 - x
+	case RecencyStep:
+		w = 1
+		if x >= 1 {
+			w = 0
+		}
+	}
+	return max(w, MinRecencyWeight)
+}
@@ -0,0 +1,64 @@
+package config
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestParseRecencyCurve(t *testing.T) {
+	const day = 24 * time.Hour
+	tests := []struct {
+		in      string
+		want    RecencyCurve
+		wantErr bool
+	}{
+		{in: "exp:2y", want: RecencyCurve{Shape: RecencyExp, Span: 730 * day}},
+		{in: "linear:18mo", want: RecencyCurve{Shape: RecencyLinear, Span: 540 * day}},
+		{in: "step:8w", want: RecencyCurve{Shape: RecencyStep, Span: 56 * day}},
+		{in: "exp:1.5y", want: RecencyCurve{Shape: RecencyExp, Span: 547*day + 12*time.Hour}},
+		{in: "exp", wantErr: true},
+		{in: "cubic:2y", wantErr: true},
+		{in: "exp:2", wantErr: true},
+		{in: "exp:0y", wantErr: true},
+		{in: "exp:2h", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseRecencyCurve(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseRecencyCurve(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseRecencyCurve(%q) = %+v, want %+v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRecencyCurveWeight(t *testing.T) {
+	const year = 365 * 24 * time.Hour
+	tests := []struct {
+		name  string
+		curve RecencyCurve
+		age   time.Duration
+		want  float64
+	}{
+		{"off", RecencyCurve{}, 10 * year, 1},
+		{"future", RecencyCurve{Shape: RecencyExp, Span: year}, -year, 1},
+		{"exp half-life", RecencyCurve{Shape: RecencyExp, Span: 2 * year}, 2 * year, 0.5},
+		{"exp two half-lives", RecencyCurve{Shape: RecencyExp, Span: 2 * year}, 4 * year, 0.25},
+		{"exp floor", RecencyCurve{Shape: RecencyExp, Span: year}, 10 * year, MinRecencyWeight},
+		{"linear midway", RecencyCurve{Shape: RecencyLinear, Span: 10 * year}, 5 * year, 0.5},
+		{"linear floor", RecencyCurve{Shape: RecencyLinear, Span: 10 * year}, 12 * year, MinRecencyWeight},
+		{"step before", RecencyCurve{Shape: RecencyStep, Span: 3 * year}, 2 * year, 1},
+		{"step after", RecencyCurve{Shape: RecencyStep, Span: 3 * year}, 3 * year, MinRecencyWeight},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.curve.Weight(tt.age); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("Weight(%v) = %v, want %v", tt.age, got, tt.want)
+			}
+		})
+	}
+}
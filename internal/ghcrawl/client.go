@@ -1,90 +1,151 @@
 package ghcrawl
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
+	"math/rand/v2"
+	"net"
 	"net/http"
 	"strconv"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/google/go-github/v68/github"
 	"golang.org/x/oauth2"
 )
 
-func newGitHubClient(token string) *github.Client {
-	return github.NewClient(newGitHubHTTPClient(token))
+func newGitHubClient(token string, concurrency int) *github.Client {
+	return github.NewClient(newGitHubHTTPClient(token, false, concurrency))
 }
 
-func newGitHubHTTPClient(token string) *http.Client {
-	baseTransport := http.RoundTripper(http.DefaultTransport)
+// newPrivateGitHubClient returns a client for the user's private token,
+// whose cached responses are kept apart from the shared public ones.
+func newPrivateGitHubClient(token string, concurrency int) *github.Client {
+	return github.NewClient(newGitHubHTTPClient(token, true, concurrency))
+}
+
+// attemptTimeout bounds the wait for GitHub to answer one attempt of a
+// request. The client itself has no timeout: it would also cut short the
+// rate limit and backoff waits rateLimitTransport makes between attempts.
+const attemptTimeout = 30 * time.Second
+
+func newGitHubHTTPClient(token string, private bool, concurrency int) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.ResponseHeaderTimeout = attemptTimeout
+	baseTransport := http.RoundTripper(transport)
 	if strings.TrimSpace(token) != "" {
 		ts := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token})
 		baseTransport = &oauth2.Transport{
 			Source: ts,
-			Base:   http.DefaultTransport,
+			Base:   transport,
 		}
 	}
 	return &http.Client{
-		Transport: &rateLimitTransport{base: baseTransport},
-		Timeout:   30 * time.Second,
+		Transport: newCachingTransport(&rateLimitTransport{
+			base:    baseTransport,
+			limiter: newAdaptiveLimiter(concurrency),
+			pacer:   githubPacer,
+		}, token, private),
 	}
 }
 
-// rateLimitTransport wraps an http.RoundTripper and pauses when rate-limited.
+// rateLimitTransport wraps an http.RoundTripper, pauses when rate-limited,
+// and throttles in-flight requests as the remaining budget drops. Secondary
+// (abuse detection) limits are retried with jittered exponential backoff and
+// slow down the per-host pacer shared by all transports. Transient 5xx
+// responses and network errors are retried with the same backoff.
 type rateLimitTransport struct {
-	base http.RoundTripper
+	base    http.RoundTripper
+	limiter *adaptiveLimiter
+	pacer   *hostPacer
 }
 
-const maxRetries = 3
+const (
+	maxRetries = 6
+	// maxRetryWait bounds any single wait derived from response headers.
+	maxRetryWait = 15 * time.Minute
+)
+
+var (
+	// backoffBase and backoffMax bound the jittered exponential backoff used
+	// when GitHub reports a secondary rate limit without Retry-After.
+	backoffBase = time.Second
+	backoffMax  = 2 * time.Minute
+)
 
 func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
-	var resp *http.Response
-	var err error
+	ctx := req.Context()
+	host := req.URL.Host
 
 	for attempt := range maxRetries {
-		resp, err = t.base.RoundTrip(req)
+		attemptReq, err := rewindRequest(req, attempt)
 		if err != nil {
 			return nil, err
 		}
+		if err := t.pacer.wait(ctx, host); err != nil {
+			return nil, err
+		}
+		if err := t.limiter.acquire(ctx); err != nil {
+			return nil, err
+		}
+		resp, err := t.base.RoundTrip(attemptReq)
+		t.limiter.release()
+		if err != nil {
+			if !isTransientNetError(ctx, err) || attempt == maxRetries-1 {
+				return nil, err
+			}
+			wait := backoffDelay(attempt, rand.Int64N)
+			slog.Warn("transient network error, retrying", "error", err, "wait", wait.Round(time.Millisecond), "attempt", attempt+1)
+			if err := sleepContext(ctx, wait); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		t.limiter.observe(resp.Header)
+
+		if isTransientStatus(resp.StatusCode) {
+			if attempt == maxRetries-1 {
+				return resp, nil
+			}
+			wait := backoffDelay(attempt, rand.Int64N)
+			slog.Warn("transient server error, retrying", "status", resp.StatusCode, "wait", wait.Round(time.Millisecond), "attempt", attempt+1)
+			closeBody(resp.Body)
+			if err := sleepContext(ctx, wait); err != nil {
+				return nil, err
+			}
+			continue
+		}
 
 		isRateLimited := resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusTooManyRequests
 
 		// Proactively pause when approaching rate limit, but only if the
 		// current response is not already rate-limited (avoids double-sleep).
 		if !isRateLimited {
-			if remaining := resp.Header.Get("X-RateLimit-Remaining"); remaining != "" {
-				rem, parseErr := strconv.Atoi(remaining)
-				if parseErr == nil && rem <= 10 {
-					resetStr := resp.Header.Get("X-RateLimit-Reset")
-					resetUnix, parseErr := strconv.ParseInt(resetStr, 10, 64)
-					if parseErr == nil {
-						wait := time.Until(time.Unix(resetUnix, 0))
-						if wait > 0 && wait < 15*time.Minute {
-							slog.Warn("approaching github rate limit, pausing",
-								"remaining", rem, "wait", wait.Round(time.Second))
-							if err := sleepContext(req.Context(), wait+time.Second); err != nil {
-								closeBody(resp.Body)
-								return nil, err
-							}
-						}
-					}
+			t.pacer.relax(host)
+			if wait, rem, ok := primaryResetWait(resp.Header, 10); ok {
+				slog.Warn("approaching github rate limit, pausing",
+					"remaining", rem, "wait", wait.Round(time.Second))
+				if err := sleepContext(ctx, wait+time.Second); err != nil {
+					closeBody(resp.Body)
+					return nil, err
 				}
 			}
 			return resp, nil
 		}
 
-		retryAfter := resp.Header.Get("Retry-After")
-		secs, parseErr := strconv.Atoi(retryAfter)
-		if parseErr != nil || secs <= 0 || secs >= 900 {
+		wait, reason, retry := t.retryWait(resp, attempt)
+		if !retry {
 			return resp, nil
 		}
 
-		slog.Warn("rate limited, retrying", "retry_after", secs, "attempt", attempt+1)
+		slog.Warn("rate limited, retrying", "reason", reason, "wait", wait.Round(time.Millisecond), "attempt", attempt+1)
 		closeBody(resp.Body)
-		if err := sleepContext(req.Context(), time.Duration(secs)*time.Second); err != nil {
+		if err := sleepContext(ctx, wait); err != nil {
 			return nil, err
 		}
 	}
@@ -92,6 +153,121 @@ func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error
 	return nil, fmt.Errorf("github rate limit: retries exhausted after %d attempts", maxRetries)
 }
 
+// retryWait decides whether a 403/429 response should be retried and for how
+// long to wait. Plain permission errors are returned to the caller untouched.
+func (t *rateLimitTransport) retryWait(resp *http.Response, attempt int) (time.Duration, string, bool) {
+	if secs, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && secs > 0 {
+		if time.Duration(secs)*time.Second >= maxRetryWait {
+			return 0, "", false
+		}
+		if isSecondaryRateLimit(resp) {
+			t.pacer.penalize(resp.Request.URL.Host)
+		}
+		return time.Duration(secs) * time.Second, "retry-after", true
+	}
+	if isSecondaryRateLimit(resp) {
+		t.pacer.penalize(resp.Request.URL.Host)
+		return backoffDelay(attempt, rand.Int64N), "secondary", true
+	}
+	if wait, _, ok := primaryResetWait(resp.Header, 0); ok {
+		return wait + time.Second, "primary", true
+	}
+	return 0, "", false
+}
+
+// primaryResetWait reports how long to wait for the primary budget to reset
+// when the remaining count is at or below threshold.
+func primaryResetWait(h http.Header, threshold int) (time.Duration, int, bool) {
+	rem, err := strconv.Atoi(h.Get("X-RateLimit-Remaining"))
+	if err != nil || rem > threshold {
+		return 0, 0, false
+	}
+	resetUnix, err := strconv.ParseInt(h.Get("X-RateLimit-Reset"), 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	wait := time.Until(time.Unix(resetUnix, 0))
+	if wait <= 0 || wait >= maxRetryWait {
+		return 0, 0, false
+	}
+	return wait, rem, true
+}
+
+// isSecondaryRateLimit inspects the response body for GitHub's secondary
+// rate limit (abuse detection) message. The body is buffered and restored
+// so callers can still read it when the response is passed through.
+func isSecondaryRateLimit(resp *http.Response) bool {
+	if resp.Body == nil {
+		return false
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	closeBody(resp.Body)
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return false
+	}
+	text := strings.ToLower(string(body))
+	return strings.Contains(text, "secondary rate limit") ||
+		strings.Contains(text, "abuse detection")
+}
+
+// backoffDelay returns an exponential backoff with equal jitter for the given
+// attempt: a random duration in [ceiling/2, ceiling], where ceiling is
+// backoffBase*2^attempt capped at backoffMax.
+func backoffDelay(attempt int, randN func(int64) int64) time.Duration {
+	ceiling := backoffBase << attempt
+	if ceiling <= 0 || ceiling > backoffMax {
+		ceiling = backoffMax
+	}
+	half := ceiling / 2
+	return half + time.Duration(randN(int64(ceiling-half)+1))
+}
+
+// isTransientStatus reports whether a response status is worth retrying:
+// GitHub returns these for brief outages and overloaded backends.
+func isTransientStatus(code int) bool {
+	switch code {
+	case http.StatusInternalServerError, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// isTransientNetError reports whether a transport error is a temporary
+// network failure (timeouts, resets, refused or dropped connections) rather
+// than a cancellation or a permanent error such as a bad URL.
+func isTransientNetError(ctx context.Context, err error) bool {
+	if ctx.Err() != nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	return errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.ECONNABORTED) ||
+		errors.Is(err, syscall.EPIPE)
+}
+
+// rewindRequest returns the request to send for the given attempt. Retries
+// need a fresh body because the previous attempt consumed it.
+func rewindRequest(req *http.Request, attempt int) (*http.Request, error) {
+	if attempt == 0 || req.Body == nil || req.GetBody == nil {
+		return req, nil
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, fmt.Errorf("rewinding request body: %w", err)
+	}
+	clone := req.Clone(req.Context())
+	clone.Body = body
+	return clone, nil
+}
+
 func sleepContext(ctx context.Context, d time.Duration) error {
 	select {
 	case <-ctx.Done():
@@ -0,0 +1,230 @@
+package ghcrawl
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+func newTestResponse(req *http.Request, status int, body string) *http.Response {
+	return &http.Response{
+		StatusCode: status,
+		Header:     http.Header{},
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Request:    req,
+	}
+}
+
+func TestRateLimitTransportRetriesSecondaryLimit(t *testing.T) {
+	oldBase := backoffBase
+	backoffBase = time.Millisecond
+	t.Cleanup(func() { backoffBase = oldBase })
+
+	calls := 0
+	transport := &rateLimitTransport{
+		base: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			calls++
+			if calls < 3 {
+				return newTestResponse(req, http.StatusForbidden,
+					`{"message":"You have exceeded a secondary rate limit. Please wait a few minutes before you try again."}`), nil
+			}
+			return newTestResponse(req, http.StatusOK, `{}`), nil
+		}),
+		limiter: newAdaptiveLimiter(1),
+		pacer:   newHostPacer(),
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, "https://api.github.com/users/octocat", nil)
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("RoundTrip() error: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want 200", resp.StatusCode)
+	}
+	if calls != 3 {
+		t.Errorf("calls = %d, want 3", calls)
+	}
+	if got := transport.pacer.interval("api.github.com"); got == 0 {
+		t.Error("expected secondary limit to slow down the host pacer")
+	}
+}
+
+func TestRateLimitTransportPassesThroughPermissionErrors(t *testing.T) {
+	calls := 0
+	transport := &rateLimitTransport{
+		base: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			calls++
+			return newTestResponse(req, http.StatusForbidden, `{"message":"Resource not accessible by integration"}`), nil
+		}),
+		limiter: newAdaptiveLimiter(1),
+		pacer:   newHostPacer(),
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, "https://api.github.com/repos/a/b", nil)
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("RoundTrip() error: %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("calls = %d, want 1", calls)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	if !strings.Contains(string(body), "Resource not accessible") {
+		t.Errorf("expected original body to be preserved, got %q", body)
+	}
+}
+
+func TestRateLimitTransportRewindsBodyOnRetry(t *testing.T) {
+	oldBase := backoffBase
+	backoffBase = time.Millisecond
+	t.Cleanup(func() { backoffBase = oldBase })
+
+	var bodies []string
+	transport := &rateLimitTransport{
+		base: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			b, _ := io.ReadAll(req.Body)
+			bodies = append(bodies, string(b))
+			if len(bodies) == 1 {
+				return newTestResponse(req, http.StatusForbidden, "secondary rate limit"), nil
+			}
+			return newTestResponse(req, http.StatusOK, `{}`), nil
+		}),
+		limiter: newAdaptiveLimiter(1),
+		pacer:   newHostPacer(),
+	}
+
+	req, _ := http.NewRequest(http.MethodPost, "https://api.github.com/graphql", strings.NewReader(`{"query":"{}"}`))
+	if _, err := transport.RoundTrip(req); err != nil {
+		t.Fatalf("RoundTrip() error: %v", err)
+	}
+	if len(bodies) != 2 || bodies[1] != bodies[0] {
+		t.Errorf("expected identical body on retry, got %q", bodies)
+	}
+}
+
+func TestBackoffDelay(t *testing.T) {
+	oldBase, oldMax := backoffBase, backoffMax
+	backoffBase, backoffMax = time.Second, 8*time.Second
+	t.Cleanup(func() { backoffBase, backoffMax = oldBase, oldMax })
+
+	lowest := func(int64) int64 { return 0 }
+	highest := func(n int64) int64 { return n - 1 }
+
+	tests := []struct {
+		attempt int
+		rand    func(int64) int64
+		want    time.Duration
+	}{
+		{0, lowest, 500 * time.Millisecond},
+		{0, highest, time.Second},
+		{2, lowest, 2 * time.Second},
+		{2, highest, 4 * time.Second},
+		{10, highest, 8 * time.Second},
+	}
+	for _, tt := range tests {
+		if got := backoffDelay(tt.attempt, tt.rand); got != tt.want {
+			t.Errorf("backoffDelay(%d) = %v, want %v", tt.attempt, got, tt.want)
+		}
+	}
+}
+
+func TestRateLimitTransportRetriesTransientFailures(t *testing.T) {
+	oldBase := backoffBase
+	backoffBase = time.Millisecond
+	t.Cleanup(func() { backoffBase = oldBase })
+
+	calls := 0
+	transport := &rateLimitTransport{
+		base: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			calls++
+			switch calls {
+			case 1:
+				return nil, syscall.ECONNRESET
+			case 2:
+				return newTestResponse(req, http.StatusBadGateway, `bad gateway`), nil
+			}
+			return newTestResponse(req, http.StatusOK, `{}`), nil
+		}),
+		limiter: newAdaptiveLimiter(1),
+		pacer:   newHostPacer(),
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, "https://api.github.com/users/octocat", nil)
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("RoundTrip() error: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want 200", resp.StatusCode)
+	}
+	if calls != 3 {
+		t.Errorf("calls = %d, want 3", calls)
+	}
+}
+
+func TestRateLimitTransportReturnsLastServerError(t *testing.T) {
+	oldBase := backoffBase
+	backoffBase = time.Millisecond
+	t.Cleanup(func() { backoffBase = oldBase })
+
+	calls := 0
+	transport := &rateLimitTransport{
+		base: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			calls++
+			return newTestResponse(req, http.StatusServiceUnavailable, `unavailable`), nil
+		}),
+		limiter: newAdaptiveLimiter(1),
+		pacer:   newHostPacer(),
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, "https://api.github.com/users/octocat", nil)
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("RoundTrip() error: %v", err)
+	}
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("status = %d, want 503", resp.StatusCode)
+	}
+	if calls != maxRetries {
+		t.Errorf("calls = %d, want %d", calls, maxRetries)
+	}
+}
+
+func TestRateLimitTransportDoesNotRetryPermanentErrors(t *testing.T) {
+	calls := 0
+	transport := &rateLimitTransport{
+		base: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			calls++
+			return nil, errors.New("unsupported protocol scheme")
+		}),
+		limiter: newAdaptiveLimiter(1),
+		pacer:   newHostPacer(),
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, "https://api.github.com/users/octocat", nil)
+	if _, err := transport.RoundTrip(req); err == nil {
+		t.Fatal("expected error")
+	}
+	if calls != 1 {
+		t.Errorf("calls = %d, want 1", calls)
+	}
+}
+
+func TestGitHubHTTPClientHasNoOverallTimeout(t *testing.T) {
+	// A client timeout would cut short the rate limit waits inside the
+	// transport; each attempt is bounded by the transport instead.
+	for _, token := range []string{"", "tok"} {
+		if c := newGitHubHTTPClient(token, false, 1); c.Timeout != 0 {
+			t.Errorf("client timeout = %v, want none", c.Timeout)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package ghcrawl
 import (
 	"context"
 	"fmt"
+	"io"
 	"log/slog"
 	"net/url"
 	"path"
@@ -22,17 +23,24 @@ const (
 	maxPRsPerRepo     = 30
 	maxReviewsPerRepo = 50
 	maxCodeSamples    = 5
+	maxTestSamples    = 3
 	maxFileSizeBytes  = 32 * 1024
 	maxPatchLen       = 4096
-	crawlConcurrency  = 5
 	maxIssueComments  = 500
 	maxSearchResults  = 200
 	maxStarredRepos   = 500
 	maxGists          = 100
 	maxEvents         = 300
 	maxGistContentLen = 2000
+	maxGistSamples    = 10
+	maxRefNames       = 100
+	maxWorkflowRuns   = 100
+	maxProjectItems   = 50
 )
 
+// defaultCrawlConcurrency is used when the caller does not set one.
+const defaultCrawlConcurrency = 5
+
 // Crawler fetches a GitHub user's repositories, commits, PRs, and comments.
 type Crawler struct {
 	pool          *TokenPool
@@ -41,22 +49,37 @@ type Crawler struct {
 	privateToken  string
 	maxRepos      int
 	exhaustive    bool
+	concurrency   int
+	gitClone      bool
+	ghArchive     string
+	strategy      string
+	selectionOut  io.Writer
+	warnings      warningLog
+	hasToken      bool
+	degraded      bool
+	scopes        map[string]bool
 }
 
 // NewCrawler returns a Crawler authenticated with the given tokens.
 // maxRepos controls how many repos get deep-crawled (commits, PRs, code samples).
 // privateToken is optional; when set it enables fetching private repos via the
-// authenticated user's /user/repos endpoint.
-func NewCrawler(tokens []string, privateToken string, maxRepos int, exhaustive bool) *Crawler {
+// authenticated user's /user/repos endpoint. concurrency bounds how many repos
+// are deep-crawled in parallel; values below 1 use the default.
+func NewCrawler(tokens []string, privateToken string, maxRepos int, exhaustive bool, concurrency int) *Crawler {
+	if concurrency < 1 {
+		concurrency = defaultCrawlConcurrency
+	}
 	c := &Crawler{
-		pool:         NewTokenPool(tokens),
-		gqlPool:      NewGraphQLPool(tokens),
+		pool:         NewTokenPool(tokens, concurrency),
+		gqlPool:      NewGraphQLPool(tokens, concurrency),
 		privateToken: privateToken,
 		maxRepos:     maxRepos,
 		exhaustive:   exhaustive,
+		concurrency:  concurrency,
+		hasToken:     len(tokens) > 0,
 	}
 	if privateToken != "" {
-		c.privateClient = newGitHubClient(privateToken)
+		c.privateClient = newPrivateGitHubClient(privateToken, concurrency)
 	}
 	return c
 }
@@ -64,6 +87,15 @@ func NewCrawler(tokens []string, privateToken string, maxRepos int, exhaustive b
 // Crawl collects activity data for the given GitHub user.
 func (c *Crawler) Crawl(ctx context.Context, username string) (*CrawlResult, error) {
 	result := &CrawlResult{}
+	c.warnings.drain()
+
+	if err := c.checkAuth(ctx); err != nil {
+		return nil, err
+	}
+	if c.degraded {
+		slog.Warn("no GitHub token, running a reduced unauthenticated crawl",
+			"max_repos", min(c.maxRepos, degradedMaxRepos))
+	}
 
 	profile, err := c.fetchProfile(ctx, username)
 	if err != nil {
@@ -83,15 +115,32 @@ func (c *Crawler) Crawl(ctx context.Context, username string) (*CrawlResult, err
 		return nil, fmt.Errorf("listing repos: %w", err)
 	}
 
-	// In exhaustive mode, deep-crawl all repos. Otherwise select a diverse
-	// subset to keep runtime bounded.
-	deepCrawl := repos
-	if !c.exhaustive {
-		// Select a diverse set of repos for deep-crawling, ensuring coverage
-		// across languages, time periods, and activity levels rather than
-		// just the most recently pushed repos.
-		deepCrawl = selectDiverseRepos(repos, c.maxRepos, username)
+	selection := c.selectDeepCrawl(ctx, repos, username)
+	c.available(result, sourceRepoDetails)
+
+	orgs, err := c.fetchOrgs(ctx, username)
+	if err != nil {
+		c.warnFetch("could not fetch orgs", err)
 	}
+	result.Orgs = orgs
+
+	// Keyed by lowercased full name, as mergeMaintained dedupes.
+	maintained := make(map[string]bool)
+	if c.available(result, sourceMaintained) {
+		repos := c.fetchMaintainedRepos(ctx, username, orgs)
+		for _, r := range repos {
+			maintained[strings.ToLower(r.GetFullName())] = true
+		}
+		if len(repos) > 0 {
+			slog.Info("found maintained repos", "count", len(repos))
+		}
+		selection = mergeMaintained(selection, repos)
+	}
+	if c.selectionOut != nil {
+		fmt.Fprintf(c.selectionOut, "Deep-crawling %d of %d repos:\n", len(selection), len(repos))
+		WriteSelection(c.selectionOut, selection)
+	}
+	deepCrawl := selectionRepos(selection)
 
 	deepCrawled := make(map[string]bool, len(deepCrawl))
 	for _, r := range deepCrawl {
@@ -100,12 +149,12 @@ func (c *Crawler) Crawl(ctx context.Context, username string) (*CrawlResult, err
 
 	var mu sync.Mutex
 	g, gCtx := errgroup.WithContext(ctx)
-	g.SetLimit(crawlConcurrency)
+	g.SetLimit(c.concurrency)
 	for _, repo := range deepCrawl {
 		g.Go(func() error {
-			rd, err := c.crawlRepo(gCtx, username, repo)
+			rd, err := c.crawlRepo(gCtx, username, repo, maintained[strings.ToLower(repo.GetFullName())])
 			if err != nil {
-				slog.Warn("skipping repo", "repo", repo.GetFullName(), "error", err)
+				c.warnFetch("could not crawl repo", err, "repo", repo.GetFullName())
 				return nil
 			}
 			mu.Lock()
@@ -152,33 +201,50 @@ func (c *Crawler) Crawl(ctx context.Context, username string) (*CrawlResult, err
 		crawledRepos[r.FullName] = true
 	}
 	since := result.User.CreatedAt
-	extRepos, err := c.fetchExternalReviews(ctx, username, crawledRepos, since)
-	if err != nil {
-		slog.Warn("could not fetch external reviews", "error", err)
-	} else if len(extRepos) > 0 {
-		for _, r := range extRepos {
-			slog.Info("found external review activity",
-				"repo", r.FullName,
-				"line_comments", len(r.ReviewComments),
-				"pr_comments", len(r.PRComments),
-			)
+	if c.available(result, sourceExternalReviews) {
+		extRepos, err := c.fetchExternalReviews(ctx, username, crawledRepos, since)
+		if err != nil {
+			c.warnFetch("could not fetch external reviews", err)
+		} else if len(extRepos) > 0 {
+			for _, r := range extRepos {
+				slog.Info("found external review activity",
+					"repo", r.FullName,
+					"line_comments", len(r.ReviewComments),
+					"pr_comments", len(r.PRComments),
+				)
+			}
+			result.Repos = append(result.Repos, extRepos...)
 		}
-		result.Repos = append(result.Repos, extRepos...)
 	}
 
 	// Fetch independent data sources concurrently. Each source handles
 	// its own errors (logging warnings), so a WaitGroup suffices.
 	var wg sync.WaitGroup
 
+	if c.available(result, sourceIssueComments) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			comments, err := c.fetchIssueComments(ctx, username, since)
+			if err != nil {
+				c.warnFetch("could not fetch issue comments", err)
+			} else {
+				mu.Lock()
+				result.IssueComments = comments
+				mu.Unlock()
+			}
+		}()
+	}
+
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		comments, err := c.fetchIssueComments(ctx, username, since)
+		starred, err := c.fetchStarredRepos(ctx, username)
 		if err != nil {
-			slog.Warn("could not fetch issue comments", "error", err)
+			c.warnFetch("could not fetch starred repos", err)
 		} else {
 			mu.Lock()
-			result.IssueComments = comments
+			result.StarredRepos = starred
 			mu.Unlock()
 		}
 	}()
@@ -186,12 +252,12 @@ func (c *Crawler) Crawl(ctx context.Context, username string) (*CrawlResult, err
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		starred, err := c.fetchStarredRepos(ctx, username)
+		watched, err := c.fetchWatchedRepos(ctx, username)
 		if err != nil {
-			slog.Warn("could not fetch starred repos", "error", err)
+			c.warnFetch("could not fetch watched repos", err)
 		} else {
 			mu.Lock()
-			result.StarredRepos = starred
+			result.WatchedRepos = watched
 			mu.Unlock()
 		}
 	}()
@@ -199,12 +265,12 @@ func (c *Crawler) Crawl(ctx context.Context, username string) (*CrawlResult, err
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		gists, err := c.fetchGists(ctx, username)
+		gists, err := c.fetchStarredGists(ctx, username)
 		if err != nil {
-			slog.Warn("could not fetch gists", "error", err)
+			c.warnFetch("could not fetch starred gists", err)
 		} else {
 			mu.Lock()
-			result.Gists = gists
+			result.StarredGists = gists
 			mu.Unlock()
 		}
 	}()
@@ -212,12 +278,12 @@ func (c *Crawler) Crawl(ctx context.Context, username string) (*CrawlResult, err
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		orgs, err := c.fetchOrgs(ctx, username)
+		gists, err := c.fetchGists(ctx, username)
 		if err != nil {
-			slog.Warn("could not fetch orgs", "error", err)
+			c.warnFetch("could not fetch gists", err)
 		} else {
 			mu.Lock()
-			result.Orgs = orgs
+			result.Gists = gists
 			mu.Unlock()
 		}
 	}()
@@ -227,7 +293,7 @@ func (c *Crawler) Crawl(ctx context.Context, username string) (*CrawlResult, err
 		defer wg.Done()
 		events, err := c.fetchEvents(ctx, username)
 		if err != nil {
-			slog.Warn("could not fetch events", "error", err)
+			c.warnFetch("could not fetch events", err)
 		} else {
 			mu.Lock()
 			result.Events = events
@@ -235,55 +301,119 @@ func (c *Crawler) Crawl(ctx context.Context, username string) (*CrawlResult, err
 		}
 	}()
 
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		issues, err := c.fetchAuthoredIssues(ctx, username, since)
-		if err != nil {
-			slog.Warn("could not fetch authored issues", "error", err)
-		} else {
+	if c.available(result, sourceAuthoredIssues) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			issues, err := c.fetchAuthoredIssues(ctx, username, since)
+			if err != nil {
+				c.warnFetch("could not fetch authored issues", err)
+			} else {
+				mu.Lock()
+				result.AuthoredIssues = issues
+				mu.Unlock()
+			}
+		}()
+	}
+
+	if c.available(result, sourceExternalPRs) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			extPRs, err := c.fetchExternalPRs(ctx, username, since)
+			if err != nil {
+				c.warnFetch("could not fetch external PRs", err)
+			} else {
+				mu.Lock()
+				result.ExternalPRs = extPRs
+				mu.Unlock()
+			}
+		}()
+	}
+
+	if c.available(result, sourceDiscussions) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			discussions := c.fetchDiscussions(ctx, username, result.Repos)
 			mu.Lock()
-			result.AuthoredIssues = issues
+			result.Discussions = discussions
 			mu.Unlock()
-		}
-	}()
+		}()
+	}
 
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		extPRs, err := c.fetchExternalPRs(ctx, username, since)
-		if err != nil {
-			slog.Warn("could not fetch external PRs", "error", err)
-		} else {
+	if c.available(result, sourceProjects) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			projects := c.fetchProjects(ctx, username)
 			mu.Lock()
-			result.ExternalPRs = extPRs
+			result.Projects = projects
 			mu.Unlock()
-		}
-	}()
+		}()
+	}
 
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		discussions := c.fetchDiscussions(ctx, username, result.Repos)
-		mu.Lock()
-		result.Discussions = discussions
-		mu.Unlock()
-	}()
+	if c.available(result, sourceSponsorship) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sponsorship := c.fetchSponsorship(ctx, username)
+			mu.Lock()
+			result.Sponsorship = sponsorship
+			mu.Unlock()
+		}()
+	}
 
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		projects := c.fetchProjects(ctx, username)
-		mu.Lock()
-		result.Projects = projects
-		mu.Unlock()
-	}()
+	if c.available(result, sourceTimeline) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			timeline := c.fetchContributorTimeline(ctx, username, deepCrawl)
+			mu.Lock()
+			result.Timeline = timeline
+			mu.Unlock()
+		}()
+	}
+
+	var archive archiveData
+	if c.ghArchive != "" {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			data, err := c.loadGHArchive(ctx, c.ghArchive, username)
+			if err != nil {
+				c.warnFetch("could not load GH Archive backfill", err, "path", c.ghArchive)
+				return
+			}
+			archive = data
+		}()
+	}
 
 	wg.Wait()
 
+	if c.ghArchive != "" {
+		mergeArchive(result, archive)
+		slog.Info("GH Archive backfill", "events", len(archive.Events), "issue_comments", len(archive.IssueComments),
+			"review_comments", len(archive.ReviewComments), "commit_comments", len(archive.CommitComments))
+	}
+	result.Warnings = c.warnings.drain()
+
 	return result, nil
 }
 
+// selectDeepCrawl returns the repos that get a full crawl and why. In
+// exhaustive mode that is every repo; otherwise the crawler's strategy
+// picks a subset to keep runtime bounded.
+func (c *Crawler) selectDeepCrawl(ctx context.Context, repos []*github.Repository, username string) []RepoSelection {
+	if c.degraded {
+		return c.selectByStrategy(ctx, repos, min(c.maxRepos, degradedMaxRepos), username)
+	}
+	if c.exhaustive {
+		return selectAll(repos, "exhaustive mode")
+	}
+	return c.selectByStrategy(ctx, repos, c.maxRepos, username)
+}
+
 func (c *Crawler) fetchProfile(ctx context.Context, username string) (UserProfile, error) {
 	user, _, err := c.pool.Next().Users.Get(ctx, username)
 	if err != nil {
@@ -342,7 +472,7 @@ func (c *Crawler) fetchRepos(ctx context.Context, username string) ([]*github.Re
 	if c.privateClient != nil {
 		privateRepos, err := c.fetchPrivateRepos(ctx, username)
 		if err != nil {
-			slog.Warn("could not fetch private repos", "error", err)
+			c.warnFetch("could not fetch private repos", err)
 		} else {
 			seen := make(map[string]bool, len(all))
 			for _, r := range all {
@@ -401,7 +531,9 @@ func (c *Crawler) fetchPrivateRepos(ctx context.Context, username string) ([]*gi
 	return all, nil
 }
 
-func (c *Crawler) crawlRepo(ctx context.Context, username string, repo *github.Repository) (RepoData, error) {
+// crawlRepo deep-crawls one repo. Owned and maintained repos also get docs,
+// ref names, CI runs, and wiki pages.
+func (c *Crawler) crawlRepo(ctx context.Context, username string, repo *github.Repository, maintainer bool) (RepoData, error) {
 	owner := repo.GetOwner().GetLogin()
 	name := repo.GetName()
 	slog.Debug("crawling repo", "repo", repo.GetFullName())
@@ -415,6 +547,7 @@ func (c *Crawler) crawlRepo(ctx context.Context, username string, repo *github.R
 		Forks:         repo.GetForksCount(),
 		Topics:        repo.Topics,
 		IsOwner:       strings.EqualFold(owner, username),
+		IsMaintainer:  maintainer,
 		IsFork:        repo.GetFork(),
 		Archived:      repo.GetArchived(),
 		DefaultBranch: repo.GetDefaultBranch(),
@@ -431,18 +564,58 @@ func (c *Crawler) crawlRepo(ctx context.Context, username string, repo *github.R
 		rd.Languages = langs
 	}
 
+	var clone *localClone
+	if c.gitClone {
+		clone, err = c.cloneRepo(ctx, owner, name)
+		if err != nil {
+			c.noteFetchError("could not clone repo, using the API for patches", err, "repo", repo.GetFullName())
+		} else {
+			defer clone.remove()
+		}
+	}
+	rd.Commits = c.fetchCommits(ctx, owner, name, username, clone)
+	if c.degraded {
+		// Unauthenticated crawls keep only commits, code samples, tooling,
+		// and docs to stay within the 60 requests/hour budget.
+		tree, _, err := c.pool.Next().Git.GetTree(ctx, owner, name, "HEAD", true)
+		if err == nil {
+			rd.CodeSamples = c.fetchCodeSamples(ctx, owner, name, tree.Entries)
+			rd.Tooling = c.fetchTooling(ctx, owner, name, tree.Entries)
+			if rd.Maintains() {
+				rd.Docs = c.fetchDocs(ctx, owner, name, tree.Entries)
+			}
+		}
+		return rd, nil
+	}
+
 	repoPRs := c.fetchRepoPRs(ctx, owner, name)
-	rd.Commits = c.fetchCommits(ctx, owner, name, username)
 	rd.PRs = c.fetchPRs(ctx, owner, name, username, repoPRs)
 	rd.Reviews = c.fetchReviews(ctx, owner, name, username, repoPRs)
-	rd.ReviewComments = c.fetchReviewComments(ctx, owner, name, username, repoPRs)
+	rd.ReviewComments, rd.ReviewReplies = c.fetchReviewComments(ctx, owner, name, username, repoPRs)
 	if len(rd.Reviews) == 0 && len(rd.ReviewComments) == 0 {
 		slog.Debug("no submitted reviews or line comments, trying PR conversation comments", "repo", repo.GetFullName())
 		rd.PRComments = c.fetchPRConversationComments(ctx, owner, name, username, repoPRs)
 	}
-	rd.CodeSamples = c.fetchCodeSamples(ctx, owner, name)
+	tree, _, err := c.pool.Next().Git.GetTree(ctx, owner, name, "HEAD", true)
+	if err != nil {
+		c.noteFetchError("could not fetch tree", err, "repo", repo.GetFullName())
+	} else {
+		rd.CodeSamples = c.fetchCodeSamples(ctx, owner, name, tree.Entries)
+		rd.Tooling = c.fetchTooling(ctx, owner, name, tree.Entries)
+		rd.Dependencies = c.fetchDependencies(ctx, owner, name, tree.Entries)
+		if rd.Maintains() {
+			rd.Docs = c.fetchDocs(ctx, owner, name, tree.Entries)
+		}
+	}
 	rd.Releases = c.fetchReleases(ctx, owner, name, username)
-	if rd.IsOwner && repo.GetHasWiki() {
+	if rd.Maintains() {
+		rd.Branches, rd.Tags = c.fetchRefNames(ctx, owner, name)
+		rd.WorkflowRuns = c.fetchWorkflowRuns(ctx, owner, name, rd.DefaultBranch)
+		if repo.GetHasIssues() {
+			rd.Triage = c.fetchTriage(ctx, owner, name, username)
+		}
+	}
+	if rd.Maintains() && repo.GetHasWiki() {
 		rd.WikiPages = fetchWikiPages(ctx, owner, name, c.privateToken)
 	}
 
@@ -465,7 +638,7 @@ func (c *Crawler) fetchRepoPRs(ctx context.Context, owner, repo string) []*githu
 	for {
 		prs, resp, err := c.pool.Next().PullRequests.List(ctx, owner, repo, opts)
 		if err != nil {
-			slog.Debug("could not list PRs", "repo", owner+"/"+repo, "error", err)
+			c.noteFetchError("could not list PRs", err, "repo", owner+"/"+repo)
 			return result
 		}
 		result = append(result, prs...)
@@ -477,10 +650,12 @@ func (c *Crawler) fetchRepoPRs(ctx context.Context, owner, repo string) []*githu
 	return result
 }
 
-func (c *Crawler) fetchCommits(ctx context.Context, owner, repo, author string) []CommitData {
+func (c *Crawler) fetchCommits(ctx context.Context, owner, repo, author string, clone *localClone) []CommitData {
 	// In default mode, fetch recent commits (up to maxCommitsPerRepo) and
 	// sample patch details. In exhaustive mode, paginate all commits and
-	// fetch patch details for every commit.
+	// fetch patch details for every commit. With a local clone, patches are
+	// read from git for every commit, falling back to the API for commits
+	// older than the shallow history.
 	perPage := maxCommitsPerRepo
 	if c.exhaustive {
 		perPage = 100
@@ -494,7 +669,7 @@ func (c *Crawler) fetchCommits(ctx context.Context, owner, repo, author string)
 	for {
 		page, resp, err := c.pool.Next().Repositories.ListCommits(ctx, owner, repo, opts)
 		if err != nil {
-			slog.Debug("could not list commits", "repo", owner+"/"+repo, "error", err)
+			c.noteFetchError("could not list commits", err, "repo", owner+"/"+repo)
 			return nil
 		}
 		commits = append(commits, page...)
@@ -508,6 +683,12 @@ func (c *Crawler) fetchCommits(ctx context.Context, owner, repo, author string)
 	if c.exhaustive {
 		maxPatches = len(commits)
 	}
+	if c.degraded {
+		maxPatches = 3
+	}
+	if clone != nil {
+		maxPatches = len(commits)
+	}
 	patchIndices := spreadIndices(len(commits), maxPatches)
 	patchSet := make(map[int]bool, len(patchIndices))
 	for _, i := range patchIndices {
@@ -516,19 +697,33 @@ func (c *Crawler) fetchCommits(ctx context.Context, owner, repo, author string)
 
 	var result []CommitData
 	for i, cm := range commits {
+		verification := cm.GetCommit().GetVerification()
 		cd := CommitData{
-			SHA:     cm.GetSHA(),
-			Message: cm.GetCommit().GetMessage(),
-			Date:    cm.GetCommit().GetAuthor().GetDate().Time,
+			SHA:       cm.GetSHA(),
+			Message:   cm.GetCommit().GetMessage(),
+			Date:      cm.GetCommit().GetAuthor().GetDate().Time,
+			Signature: signatureMethod(verification.GetSignature()),
+			Verified:  verification.GetVerified(),
 		}
 
 		if patchSet[i] {
-			detail, _, err := c.pool.Next().Repositories.GetCommit(ctx, owner, repo, cm.GetSHA(), nil)
-			if err == nil {
-				cd.Patch = extractPatch(detail.Files)
-				cd.Additions = detail.GetStats().GetAdditions()
-				cd.Deletions = detail.GetStats().GetDeletions()
-				cd.FilesChanged = len(detail.Files)
+			if files, ok := localCommitFiles(ctx, clone, cm.GetSHA()); ok {
+				cd.Patch = extractPatch(files)
+				for _, f := range files {
+					cd.Additions += f.GetAdditions()
+					cd.Deletions += f.GetDeletions()
+				}
+				cd.FilesChanged = len(files)
+			} else if clone == nil || !c.degraded {
+				// Degraded crawls cannot afford an API call for every
+				// commit the shallow clone is missing.
+				detail, _, err := c.pool.Next().Repositories.GetCommit(ctx, owner, repo, cm.GetSHA(), nil)
+				if err == nil {
+					cd.Patch = extractPatch(detail.Files)
+					cd.Additions = detail.GetStats().GetAdditions()
+					cd.Deletions = detail.GetStats().GetDeletions()
+					cd.FilesChanged = len(detail.Files)
+				}
 			}
 		}
 		result = append(result, cd)
@@ -536,6 +731,22 @@ func (c *Crawler) fetchCommits(ctx context.Context, owner, repo, author string)
 	return result
 }
 
+// signatureMethod identifies how a commit was signed from its armored
+// signature, or returns "" for unsigned commits.
+func signatureMethod(signature string) string {
+	switch {
+	case signature == "":
+		return ""
+	case strings.Contains(signature, "BEGIN PGP SIGNATURE"):
+		return "gpg"
+	case strings.Contains(signature, "BEGIN SSH SIGNATURE"):
+		return "ssh"
+	case strings.Contains(signature, "BEGIN SIGNED MESSAGE"):
+		return "x509"
+	}
+	return "other"
+}
+
 // spreadIndices returns up to count evenly spaced indices across [0, total).
 func spreadIndices(total, count int) []int {
 	if total <= 0 {
@@ -582,10 +793,14 @@ func extractPatch(files []*github.CommitFile) string {
 
 func (c *Crawler) fetchPRs(ctx context.Context, owner, repo, username string, prs []*github.PullRequest) []PullRequestData {
 	var result []PullRequestData
+	var reactions map[int]ReactionCounts
 	for _, pr := range prs {
 		if !strings.EqualFold(pr.GetUser().GetLogin(), username) {
 			continue
 		}
+		if reactions == nil {
+			reactions = c.fetchIssueReactions(ctx, owner, repo, username)
+		}
 		detail := pr
 		full, _, err := c.pool.Next().PullRequests.Get(ctx, owner, repo, pr.GetNumber())
 		if err == nil {
@@ -605,6 +820,7 @@ func (c *Crawler) fetchPRs(ctx context.Context, owner, repo, username string, pr
 			ChangedFiles: detail.GetChangedFiles(),
 		}
 		prd.Labels = prLabelNames(detail)
+		prd.Reactions = reactions[pr.GetNumber()]
 		if pr.MergedAt != nil {
 			t := pr.GetMergedAt().Time
 			prd.MergedAt = &t
@@ -618,6 +834,50 @@ func (c *Crawler) fetchPRs(ctx context.Context, owner, repo, username string, pr
 	return result
 }
 
+// fetchIssueReactions returns the reactions on username's issues and pull
+// requests in owner/repo, by number. Pull request payloads omit reactions,
+// but the issue listing carries them for pull requests too, so one listing
+// replaces a lookup per pull request. Outside exhaustive mode it reads the
+// user's 100 most recent.
+func (c *Crawler) fetchIssueReactions(ctx context.Context, owner, repo, username string) map[int]ReactionCounts {
+	opts := &github.IssueListByRepoOptions{
+		Creator:     username,
+		State:       "all",
+		Sort:        "created",
+		Direction:   "desc",
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+	reactions := make(map[int]ReactionCounts)
+	for {
+		issues, resp, err := c.pool.Next().Issues.ListByRepo(ctx, owner, repo, opts)
+		if err != nil {
+			c.noteFetchError("could not list issue reactions", err, "repo", owner+"/"+repo)
+			return reactions
+		}
+		for _, issue := range issues {
+			reactions[issue.GetNumber()] = reactionCounts(issue.Reactions)
+		}
+		if !c.exhaustive || resp.NextPage == 0 {
+			return reactions
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
+func reactionCounts(r *github.Reactions) ReactionCounts {
+	if r == nil {
+		return ReactionCounts{}
+	}
+	return ReactionCounts{
+		Total:    r.GetTotalCount(),
+		PlusOne:  r.GetPlusOne(),
+		MinusOne: r.GetMinusOne(),
+		Heart:    r.GetHeart(),
+		Hooray:   r.GetHooray(),
+		Rocket:   r.GetRocket(),
+	}
+}
+
 func prLabelNames(pr *github.PullRequest) []string {
 	var labels []string
 	for _, lbl := range pr.Labels {
@@ -651,7 +911,7 @@ func (c *Crawler) fetchReviews(ctx context.Context, owner, repo, username string
 		for {
 			reviews, resp, err := c.pool.Next().PullRequests.ListReviews(ctx, owner, repo, pr.GetNumber(), opts)
 			if err != nil {
-				slog.Debug("could not list reviews", "repo", owner+"/"+repo, "number", pr.GetNumber(), "error", err)
+				c.noteFetchError("could not list reviews", err, "repo", owner+"/"+repo, "number", pr.GetNumber())
 				break
 			}
 			for _, review := range reviews {
@@ -691,7 +951,10 @@ func (c *Crawler) fetchReviews(ctx context.Context, owner, repo, username string
 	return result
 }
 
-func (c *Crawler) fetchReviewComments(ctx context.Context, owner, repo, username string, prs []*github.PullRequest) []ReviewComment {
+// fetchReviewComments returns the user's inline comments on other people's
+// pull requests, and the user's replies in inline threads on any pull
+// request, each with the comment it answers.
+func (c *Crawler) fetchReviewComments(ctx context.Context, owner, repo, username string, prs []*github.PullRequest) ([]ReviewComment, []ReviewReply) {
 	opts := &github.PullRequestListCommentsOptions{
 		Sort:        "created",
 		Direction:   "desc",
@@ -703,14 +966,28 @@ func (c *Crawler) fetchReviewComments(ctx context.Context, owner, repo, username
 		prByNumber[pr.GetNumber()] = pr
 	}
 	loadedByNumber := make(map[int]*github.PullRequest)
+	lookup := func(prNumber int) *github.PullRequest {
+		return loadPullRequest(
+			prNumber,
+			prByNumber,
+			loadedByNumber,
+			func(number int) (*github.PullRequest, error) {
+				pr, _, err := c.pool.Next().PullRequests.Get(ctx, owner, repo, number)
+				return pr, err
+			},
+		)
+	}
 	var result []ReviewComment
+	var all []*github.PullRequestComment
 	limit := c.limit(maxReviewsPerRepo)
+pages:
 	for {
 		comments, resp, err := c.pool.Next().PullRequests.ListComments(ctx, owner, repo, 0, opts)
 		if err != nil {
-			slog.Debug("could not list review comments", "repo", owner+"/"+repo, "error", err)
+			c.noteFetchError("could not list review comments", err, "repo", owner+"/"+repo)
 			break
 		}
+		all = append(all, comments...)
 		for _, cm := range comments {
 			if !strings.EqualFold(cm.GetUser().GetLogin(), username) {
 				continue
@@ -718,15 +995,7 @@ func (c *Crawler) fetchReviewComments(ctx context.Context, owner, repo, username
 			prNumber := pullRequestNumberFromURL(cm.GetPullRequestURL())
 			prTitle := ""
 			prAuthor := ""
-			pr := loadPullRequest(
-				prNumber,
-				prByNumber,
-				loadedByNumber,
-				func(number int) (*github.PullRequest, error) {
-					pr, _, err := c.pool.Next().PullRequests.Get(ctx, owner, repo, number)
-					return pr, err
-				},
-			)
+			pr := lookup(prNumber)
 			if pr != nil {
 				prTitle = pr.GetTitle()
 				prAuthor = pr.GetUser().GetLogin()
@@ -735,18 +1004,19 @@ func (c *Crawler) fetchReviewComments(ctx context.Context, owner, repo, username
 				}
 			}
 			result = append(result, ReviewComment{
-				Repo:     owner + "/" + repo,
-				PRNumber: prNumber,
-				PRTitle:  prTitle,
-				PRAuthor: prAuthor,
-				Body:     truncate(cm.GetBody(), 1000),
-				Path:     cm.GetPath(),
-				DiffHunk: truncate(cm.GetDiffHunk(), 2000),
-				URL:      cm.GetHTMLURL(),
-				Date:     cm.GetCreatedAt().Time,
+				Repo:      owner + "/" + repo,
+				PRNumber:  prNumber,
+				PRTitle:   prTitle,
+				PRAuthor:  prAuthor,
+				Body:      truncate(cm.GetBody(), 1000),
+				Path:      cm.GetPath(),
+				DiffHunk:  truncate(cm.GetDiffHunk(), 2000),
+				URL:       cm.GetHTMLURL(),
+				Date:      cm.GetCreatedAt().Time,
+				Reactions: reactionCounts(cm.Reactions),
 			})
 			if c.reachedLimit(len(result), limit) {
-				return result
+				break pages
 			}
 		}
 		if !c.exhaustive || resp.NextPage == 0 {
@@ -754,7 +1024,49 @@ func (c *Crawler) fetchReviewComments(ctx context.Context, owner, repo, username
 		}
 		opts.Page = resp.NextPage
 	}
-	return result
+	replies := reviewReplies(owner+"/"+repo, username, all, lookup)
+	if limit > 0 && len(replies) > limit {
+		replies = replies[:limit]
+	}
+	return result, replies
+}
+
+// reviewReplies pairs each of the user's inline replies in comments with the
+// comment it answers. Replies whose parent is not among comments, or that
+// answer the user's own comment, are skipped. pr looks up a pull request by
+// number and may return nil.
+func reviewReplies(fullName, username string, comments []*github.PullRequestComment, pr func(int) *github.PullRequest) []ReviewReply {
+	byID := make(map[int64]*github.PullRequestComment, len(comments))
+	for _, cm := range comments {
+		byID[cm.GetID()] = cm
+	}
+	var replies []ReviewReply
+	for _, cm := range comments {
+		if cm.GetInReplyTo() == 0 || !strings.EqualFold(cm.GetUser().GetLogin(), username) {
+			continue
+		}
+		parent, ok := byID[cm.GetInReplyTo()]
+		if !ok || strings.EqualFold(parent.GetUser().GetLogin(), username) {
+			continue
+		}
+		prNumber := pullRequestNumberFromURL(cm.GetPullRequestURL())
+		reply := ReviewReply{
+			Repo:         fullName,
+			PRNumber:     prNumber,
+			Path:         cm.GetPath(),
+			ParentAuthor: parent.GetUser().GetLogin(),
+			Parent:       truncate(parent.GetBody(), 1000),
+			Body:         truncate(cm.GetBody(), 1000),
+			URL:          cm.GetHTMLURL(),
+			Date:         cm.GetCreatedAt().Time,
+		}
+		if p := pr(prNumber); p != nil {
+			reply.PRTitle = p.GetTitle()
+			reply.OwnPR = strings.EqualFold(p.GetUser().GetLogin(), username)
+		}
+		replies = append(replies, reply)
+	}
+	return replies
 }
 
 func loadPullRequest(
@@ -810,11 +1122,12 @@ func (c *Crawler) fetchPRConversationComments(ctx context.Context, owner, repo,
 					continue
 				}
 				result = append(result, Comment{
-					Repo:   owner + "/" + repo,
-					Author: cm.GetUser().GetLogin(),
-					Body:   truncate(cm.GetBody(), 1000),
-					URL:    cm.GetHTMLURL(),
-					Date:   cm.GetCreatedAt().Time,
+					Repo:      owner + "/" + repo,
+					Author:    cm.GetUser().GetLogin(),
+					Body:      truncate(cm.GetBody(), 1000),
+					URL:       cm.GetHTMLURL(),
+					Date:      cm.GetCreatedAt().Time,
+					Reactions: reactionCounts(cm.Reactions),
 				})
 				if c.reachedLimit(len(result), limit) {
 					break
@@ -832,23 +1145,22 @@ func (c *Crawler) fetchPRConversationComments(ctx context.Context, owner, repo,
 	return result
 }
 
-func (c *Crawler) fetchCodeSamples(ctx context.Context, owner, repo string) []CodeSample {
-	tree, _, err := c.pool.Next().Git.GetTree(ctx, owner, repo, "HEAD", true)
-	if err != nil {
-		return nil
-	}
-
+func (c *Crawler) fetchCodeSamples(ctx context.Context, owner, repo string, entries []*github.TreeEntry) []CodeSample {
 	var candidates []string
-	var workflows []string
-	for _, entry := range tree.Entries {
+	var tests []string
+	for _, entry := range entries {
 		if entry.GetType() != "blob" {
 			continue
 		}
 		p := entry.GetPath()
 		name := path.Base(p)
-		if isWorkflowFile(p) {
+		// Automation configs are collected separately by fetchTooling.
+		if isToolingFile(p) {
+			continue
+		}
+		if isTestFile(p) {
 			if entry.GetSize() <= maxFileSizeBytes {
-				workflows = append(workflows, p)
+				tests = append(tests, p)
 			}
 			continue
 		}
@@ -861,10 +1173,12 @@ func (c *Crawler) fetchCodeSamples(ctx context.Context, owner, repo string) []Co
 
 	var samples []CodeSample
 	limit := c.limit(maxCodeSamples + 3)
-	for _, p := range workflows {
-		if c.reachedLimit(len(samples), limit) {
-			break
-		}
+
+	// Test files get their own quota so they are not crowded out by
+	// source files that appear earlier in the tree.
+	testLimit := c.limit(maxTestSamples)
+	testCount := 0
+	for _, p := range spreadPaths(tests, testLimit) {
 		fileContent, _, _, err := c.pool.Next().Repositories.GetContents(ctx, owner, repo, p, nil)
 		if err != nil || fileContent == nil {
 			continue
@@ -874,6 +1188,11 @@ func (c *Crawler) fetchCodeSamples(ctx context.Context, owner, repo string) []Co
 			continue
 		}
 		samples = append(samples, CodeSample{Path: p, Content: content})
+		testCount++
+	}
+	// Zero is no limit in exhaustive mode; adding the tests would make it one.
+	if limit > 0 {
+		limit += testCount
 	}
 
 	for _, p := range candidates {
@@ -903,7 +1222,7 @@ func (c *Crawler) fetchReleases(ctx context.Context, owner, repo, username strin
 	for {
 		releases, resp, err := c.pool.Next().Repositories.ListReleases(ctx, owner, repo, opts)
 		if err != nil {
-			slog.Debug("could not list releases", "repo", owner+"/"+repo, "error", err)
+			c.noteFetchError("could not list releases", err, "repo", owner+"/"+repo)
 			return result
 		}
 		for _, rel := range releases {
@@ -926,6 +1245,102 @@ func (c *Crawler) fetchReleases(ctx context.Context, owner, repo, username strin
 	return result
 }
 
+// fetchRefNames lists branch and tag names so naming conventions such as
+// feature/x or vX.Y.Z can be recognized.
+func (c *Crawler) fetchRefNames(ctx context.Context, owner, repo string) (branches, tags []string) {
+	limit := c.limit(maxRefNames)
+
+	branchOpts := &github.BranchListOptions{ListOptions: github.ListOptions{PerPage: 100}}
+	for {
+		page, resp, err := c.pool.Next().Repositories.ListBranches(ctx, owner, repo, branchOpts)
+		if err != nil {
+			c.noteFetchError("could not list branches", err, "repo", owner+"/"+repo)
+			break
+		}
+		for _, b := range page {
+			branches = append(branches, b.GetName())
+		}
+		if c.reachedLimit(len(branches), limit) || resp.NextPage == 0 {
+			break
+		}
+		branchOpts.Page = resp.NextPage
+	}
+
+	tagOpts := &github.ListOptions{PerPage: 100}
+	for {
+		page, resp, err := c.pool.Next().Repositories.ListTags(ctx, owner, repo, tagOpts)
+		if err != nil {
+			c.noteFetchError("could not list tags", err, "repo", owner+"/"+repo)
+			break
+		}
+		for _, t := range page {
+			tags = append(tags, t.GetName())
+		}
+		if c.reachedLimit(len(tags), limit) || resp.NextPage == 0 {
+			break
+		}
+		tagOpts.Page = resp.NextPage
+	}
+
+	if limit > 0 {
+		branches = branches[:min(len(branches), limit)]
+		tags = tags[:min(len(tags), limit)]
+	}
+	return branches, tags
+}
+
+// fetchWorkflowRuns summarizes the most recent completed Actions runs.
+func (c *Crawler) fetchWorkflowRuns(ctx context.Context, owner, repo, defaultBranch string) WorkflowRunStats {
+	opts := &github.ListWorkflowRunsOptions{
+		Status:      "completed",
+		ListOptions: github.ListOptions{PerPage: maxWorkflowRuns},
+	}
+	runs, _, err := c.pool.Next().Actions.ListRepositoryWorkflowRuns(ctx, owner, repo, opts)
+	if err != nil {
+		c.noteFetchError("could not list workflow runs", err, "repo", owner+"/"+repo)
+		return WorkflowRunStats{}
+	}
+	return summarizeWorkflowRuns(runs.WorkflowRuns, defaultBranch)
+}
+
+// summarizeWorkflowRuns tallies run conclusions. Runs are expected newest
+// first, as the API returns them.
+func summarizeWorkflowRuns(runs []*github.WorkflowRun, defaultBranch string) WorkflowRunStats {
+	var stats WorkflowRunStats
+	streakOpen := true
+	for _, run := range runs {
+		conclusion := run.GetConclusion()
+		switch conclusion {
+		case "success":
+			stats.Success++
+		case "failure", "timed_out", "startup_failure":
+			stats.Failure++
+		case "cancelled":
+			stats.Cancelled++
+		case "skipped", "neutral", "":
+			continue
+		}
+		stats.Total++
+
+		if run.GetHeadBranch() != defaultBranch {
+			continue
+		}
+		stats.DefaultBranchTotal++
+		failed := conclusion == "failure" || conclusion == "timed_out" || conclusion == "startup_failure"
+		if failed {
+			stats.DefaultBranchFailures++
+		}
+		if streakOpen {
+			if failed {
+				stats.FailureStreak++
+			} else {
+				streakOpen = false
+			}
+		}
+	}
+	return stats
+}
+
 func (c *Crawler) fetchExternalReviews(ctx context.Context, username string, crawledRepos map[string]bool, since time.Time) ([]RepoData, error) {
 	query := fmt.Sprintf("commenter:%s is:pr -user:%s", username, username)
 
@@ -1050,20 +1465,22 @@ func (c *Crawler) fetchExternalReviews(ctx context.Context, username string, cra
 				if err != nil {
 					break
 				}
+				rd.ReviewReplies = append(rd.ReviewReplies, reviewReplies(fullName, username, rc, func(int) *github.PullRequest { return pr })...)
 				for _, cm := range rc {
 					if !strings.EqualFold(cm.GetUser().GetLogin(), username) {
 						continue
 					}
 					rd.ReviewComments = append(rd.ReviewComments, ReviewComment{
-						Repo:     fullName,
-						PRNumber: ref.number,
-						PRTitle:  prTitle(pr),
-						PRAuthor: prAuthor(pr),
-						Body:     truncate(cm.GetBody(), 1000),
-						Path:     cm.GetPath(),
-						DiffHunk: truncate(cm.GetDiffHunk(), 2000),
-						URL:      cm.GetHTMLURL(),
-						Date:     cm.GetCreatedAt().Time,
+						Repo:      fullName,
+						PRNumber:  ref.number,
+						PRTitle:   prTitle(pr),
+						PRAuthor:  prAuthor(pr),
+						Body:      truncate(cm.GetBody(), 1000),
+						Path:      cm.GetPath(),
+						DiffHunk:  truncate(cm.GetDiffHunk(), 2000),
+						URL:       cm.GetHTMLURL(),
+						Date:      cm.GetCreatedAt().Time,
+						Reactions: reactionCounts(cm.Reactions),
 					})
 					if c.reachedLimit(len(rd.ReviewComments), reviewLimit) {
 						break
@@ -1090,11 +1507,12 @@ func (c *Crawler) fetchExternalReviews(ctx context.Context, username string, cra
 						continue
 					}
 					rd.PRComments = append(rd.PRComments, Comment{
-						Repo:   fullName,
-						Author: cm.GetUser().GetLogin(),
-						Body:   truncate(cm.GetBody(), 1000),
-						URL:    cm.GetHTMLURL(),
-						Date:   cm.GetCreatedAt().Time,
+						Repo:      fullName,
+						Author:    cm.GetUser().GetLogin(),
+						Body:      truncate(cm.GetBody(), 1000),
+						URL:       cm.GetHTMLURL(),
+						Date:      cm.GetCreatedAt().Time,
+						Reactions: reactionCounts(cm.Reactions),
 					})
 					if c.reachedLimit(len(rd.PRComments), reviewLimit) {
 						break
@@ -1107,7 +1525,7 @@ func (c *Crawler) fetchExternalReviews(ctx context.Context, username string, cra
 			}
 		}
 
-		if len(rd.Reviews) > 0 || len(rd.ReviewComments) > 0 || len(rd.PRComments) > 0 {
+		if len(rd.Reviews) > 0 || len(rd.ReviewComments) > 0 || len(rd.PRComments) > 0 || len(rd.ReviewReplies) > 0 {
 			result = append(result, rd)
 		}
 	}
@@ -1167,11 +1585,12 @@ func (c *Crawler) fetchIssueComments(ctx context.Context, username string, since
 			for _, cm := range comments {
 				if strings.EqualFold(cm.GetUser().GetLogin(), username) {
 					allComments = append(allComments, Comment{
-						Repo:   owner + "/" + repo,
-						Author: cm.GetUser().GetLogin(),
-						Body:   truncate(cm.GetBody(), 1000),
-						URL:    cm.GetHTMLURL(),
-						Date:   cm.GetCreatedAt().Time,
+						Repo:      owner + "/" + repo,
+						Author:    cm.GetUser().GetLogin(),
+						Body:      truncate(cm.GetBody(), 1000),
+						URL:       cm.GetHTMLURL(),
+						Date:      cm.GetCreatedAt().Time,
+						Reactions: reactionCounts(cm.Reactions),
 					})
 				}
 				if c.reachedLimit(len(allComments), limit) {
@@ -1247,22 +1666,64 @@ func (c *Crawler) fetchGists(ctx context.Context, username string) ([]GistData,
 				gd.Files = append(gd.Files, GistFile{
 					Name:     string(name),
 					Language: f.GetLanguage(),
+					Size:     f.GetSize(),
 					Content:  truncate(f.GetContent(), maxGistContentLen),
 				})
 			}
 			result = append(result, gd)
 			if c.reachedLimit(len(result), limit) {
-				return result, nil
+				break
 			}
 		}
-		if !c.exhaustive || resp.NextPage == 0 {
+		if c.reachedLimit(len(result), limit) || !c.exhaustive || resp.NextPage == 0 {
 			break
 		}
 		opts.Page = resp.NextPage
 	}
+	if !c.degraded {
+		c.fetchGistContents(ctx, result)
+	}
 	return result, nil
 }
 
+// fetchGistContents fills in file contents for small public gists. The list
+// endpoint omits content, so each gist needs its own request.
+func (c *Crawler) fetchGistContents(ctx context.Context, gists []GistData) {
+	limit := c.limit(maxGistSamples)
+	fetched := 0
+	for i := range gists {
+		if c.reachedLimit(fetched, limit) {
+			return
+		}
+		gd := &gists[i]
+		if !gd.Public || !hasSmallGistFile(gd.Files) {
+			continue
+		}
+		full, _, err := c.pool.Next().Gists.Get(ctx, gd.ID)
+		if err != nil {
+			c.noteFetchError("could not fetch gist", err, "id", gd.ID)
+			continue
+		}
+		fetched++
+		for j := range gd.Files {
+			f, ok := full.Files[github.GistFilename(gd.Files[j].Name)]
+			if !ok || f.GetSize() > maxFileSizeBytes {
+				continue
+			}
+			gd.Files[j].Content = truncate(f.GetContent(), maxGistContentLen)
+		}
+	}
+}
+
+func hasSmallGistFile(files []GistFile) bool {
+	for _, f := range files {
+		if f.Size > 0 && f.Size <= maxFileSizeBytes {
+			return true
+		}
+	}
+	return false
+}
+
 func (c *Crawler) fetchOrgs(ctx context.Context, username string) ([]string, error) {
 	opts := &github.ListOptions{PerPage: 100}
 	var result []string
@@ -1429,12 +1890,13 @@ func (c *Crawler) fetchExternalPRs(ctx context.Context, username string, since t
 			continue
 		}
 		prd := PullRequestData{
-			Repo:   owner + "/" + repo,
-			Number: issue.GetNumber(),
-			Title:  issue.GetTitle(),
-			Body:   truncate(issue.GetBody(), 2000),
-			State:  issue.GetState(),
-			Date:   issue.GetCreatedAt().Time,
+			Repo:      owner + "/" + repo,
+			Number:    issue.GetNumber(),
+			Title:     issue.GetTitle(),
+			Body:      truncate(issue.GetBody(), 2000),
+			State:     issue.GetState(),
+			Date:      issue.GetCreatedAt().Time,
+			Reactions: reactionCounts(issue.Reactions),
 		}
 		for _, lbl := range issue.Labels {
 			prd.Labels = append(prd.Labels, lbl.GetName())
@@ -1467,11 +1929,17 @@ func (c *Crawler) fetchExternalPRs(ctx context.Context, username string, since t
 //   - Owned repos are preferred over forks
 //   - Higher-activity repos (stars, forks) are preferred within each group
 func selectDiverseRepos(repos []*github.Repository, maxRepos int, username string) []*github.Repository {
+	return selectionRepos(selectDiverse(repos, maxRepos, username))
+}
+
+// selectDiverse implements selectDiverseRepos and records why each repo was
+// picked.
+func selectDiverse(repos []*github.Repository, maxRepos int, username string) []RepoSelection {
 	if len(repos) <= maxRepos {
-		return repos
+		return selectAll(repos, "within the --max-repos budget")
 	}
 
-	selected := make(map[int]bool)
+	selected := make(map[int]string)
 
 	// Group owned (non-fork) repos by primary language.
 	langGroups := make(map[string][]int)
@@ -1494,9 +1962,12 @@ func selectDiverseRepos(repos []*github.Repository, maxRepos int, username strin
 	}
 	for round := 0; len(selected) < langBudget; round++ {
 		added := false
-		for _, indices := range langGroups {
+		for lang, indices := range langGroups {
 			if round < len(indices) && len(selected) < langBudget {
-				selected[indices[round]] = true
+				if lang == "_none" {
+					lang = "no language"
+				}
+				selected[indices[round]] = "language coverage: " + lang
 				added = true
 			}
 		}
@@ -1509,7 +1980,7 @@ func selectDiverseRepos(repos []*github.Repository, maxRepos int, username strin
 	// repos by creation date (oldest first) and pick evenly spaced ones.
 	var unselected []int
 	for i, r := range repos {
-		if selected[i] || r.GetFork() {
+		if selected[i] != "" || r.GetFork() {
 			continue
 		}
 		if !strings.EqualFold(r.GetOwner().GetLogin(), username) {
@@ -1528,7 +1999,8 @@ func selectDiverseRepos(repos []*github.Repository, maxRepos int, username strin
 			step = 1
 		}
 		for i := 0; i < len(unselected) && len(selected) < maxRepos; i += step {
-			selected[unselected[i]] = true
+			created := repos[unselected[i]].GetCreatedAt().Format("2006")
+			selected[unselected[i]] = "temporal spread: created " + created
 		}
 	}
 
@@ -1536,7 +2008,7 @@ func selectDiverseRepos(repos []*github.Repository, maxRepos int, username strin
 	if len(selected) < maxRepos {
 		var forks []int
 		for i, r := range repos {
-			if selected[i] {
+			if selected[i] != "" {
 				continue
 			}
 			if r.GetFork() || !strings.EqualFold(r.GetOwner().GetLogin(), username) {
@@ -1550,14 +2022,14 @@ func selectDiverseRepos(repos []*github.Repository, maxRepos int, username strin
 			if len(selected) >= maxRepos {
 				break
 			}
-			selected[i] = true
+			selected[i] = fmt.Sprintf("fork or collaborator repo: %d stars", repos[i].GetStargazersCount())
 		}
 	}
 
-	result := make([]*github.Repository, 0, len(selected))
-	for i := range repos {
-		if selected[i] {
-			result = append(result, repos[i])
+	result := make([]RepoSelection, 0, len(selected))
+	for i, r := range repos {
+		if reason := selected[i]; reason != "" {
+			result = append(result, RepoSelection{FullName: r.GetFullName(), Reason: reason, repo: r})
 		}
 	}
 	return result
@@ -1589,7 +2061,13 @@ func pullRequestNumberFromURL(rawURL string) int {
 	return 0
 }
 
+// limit returns the cap for a source whose default is n. Zero means no
+// limit, which exhaustive crawls use. Degraded crawls shrink every cap, even
+// an exhaustive one, to fit the unauthenticated request budget.
 func (c *Crawler) limit(n int) int {
+	if c.degraded {
+		return max(1, n/degradedLimitDivisor)
+	}
 	if c.exhaustive {
 		return 0
 	}
@@ -1643,6 +2121,45 @@ func isSourceFile(name string) bool {
 	return sourceExts[ext]
 }
 
+// spreadPaths picks up to n paths evenly spaced across paths, so samples come
+// from different parts of the tree. n <= 0 keeps every path.
+func spreadPaths(paths []string, n int) []string {
+	if n <= 0 || len(paths) <= n {
+		return paths
+	}
+	picked := make([]string, 0, n)
+	for _, i := range spreadIndices(len(paths), n) {
+		picked = append(picked, paths[i])
+	}
+	return picked
+}
+
+// isTestFile reports whether p follows a common test file naming convention
+// such as foo_test.go, test_foo.py, foo.spec.ts, or FooTest.java.
+func isTestFile(p string) bool {
+	name := path.Base(p)
+	ext := strings.ToLower(path.Ext(name))
+	if !sourceExts[ext] && ext != ".tsx" && ext != ".jsx" {
+		return false
+	}
+	stem := strings.TrimSuffix(name, path.Ext(name))
+	lower := strings.ToLower(stem)
+	switch {
+	case strings.HasSuffix(lower, "_test"), strings.HasPrefix(lower, "test_"):
+		return true
+	case strings.HasSuffix(lower, ".test"), strings.HasSuffix(lower, ".spec"):
+		return true
+	case strings.HasSuffix(stem, "Test"), strings.HasSuffix(stem, "Tests"):
+		return true
+	}
+	for _, dir := range strings.Split(path.Dir(p), "/") {
+		if dir == "__tests__" {
+			return true
+		}
+	}
+	return false
+}
+
 func isWorkflowFile(p string) bool {
 	return strings.HasPrefix(p, ".github/workflows/") &&
 		(strings.HasSuffix(p, ".yml") || strings.HasSuffix(p, ".yaml"))
@@ -1,7 +1,17 @@
 package ghcrawl
 
 import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"slices"
 	"strings"
+	"sync"
 	"testing"
 
 	"github.com/google/go-github/v68/github"
@@ -384,3 +394,226 @@ func TestPrivateTokenMatchesUsername(t *testing.T) {
 		})
 	}
 }
+
+func TestHasSmallGistFile(t *testing.T) {
+	tests := []struct {
+		name  string
+		files []GistFile
+		want  bool
+	}{
+		{"no files", nil, false},
+		{"unknown size", []GistFile{{Name: "a.sh"}}, false},
+		{"small file", []GistFile{{Name: "a.sh", Size: 120}}, true},
+		{"only large files", []GistFile{{Name: "big.json", Size: maxFileSizeBytes + 1}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := hasSmallGistFile(tt.files); got != tt.want {
+				t.Errorf("hasSmallGistFile() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSignatureMethod(t *testing.T) {
+	tests := []struct {
+		signature string
+		want      string
+	}{
+		{"", ""},
+		{"-----BEGIN PGP SIGNATURE-----\n...", "gpg"},
+		{"-----BEGIN SSH SIGNATURE-----\n...", "ssh"},
+		{"-----BEGIN SIGNED MESSAGE-----\n...", "x509"},
+		{"garbage", "other"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := signatureMethod(tt.signature); got != tt.want {
+				t.Errorf("signatureMethod(%q) = %q, want %q", tt.signature, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSummarizeWorkflowRuns(t *testing.T) {
+	run := func(branch, conclusion string) *github.WorkflowRun {
+		return &github.WorkflowRun{HeadBranch: github.Ptr(branch), Conclusion: github.Ptr(conclusion)}
+	}
+	runs := []*github.WorkflowRun{
+		run("main", "failure"),
+		run("feature/x", "success"),
+		run("main", "timed_out"),
+		run("main", "success"),
+		run("main", "skipped"),
+		run("feature/x", "cancelled"),
+		run("main", "failure"),
+	}
+	got := summarizeWorkflowRuns(runs, "main")
+	want := WorkflowRunStats{
+		Total:                 6,
+		Success:               2,
+		Failure:               3,
+		Cancelled:             1,
+		DefaultBranchTotal:    4,
+		DefaultBranchFailures: 3,
+		FailureStreak:         2,
+	}
+	if got != want {
+		t.Errorf("summarizeWorkflowRuns() = %+v, want %+v", got, want)
+	}
+}
+
+func TestIsTestFile(t *testing.T) {
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"internal/ghcrawl/crawler_test.go", true},
+		{"tests/test_parser.py", true},
+		{"src/app.spec.ts", true},
+		{"src/Button.test.tsx", true},
+		{"src/main/java/FooTest.java", true},
+		{"src/__tests__/util.js", true},
+		{"internal/ghcrawl/crawler.go", false},
+		{"testdata/fixture.json", false},
+		{"docs/test_plan.md", false},
+		{"contest.py", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := isTestFile(tt.path); got != tt.want {
+				t.Errorf("isTestFile(%q) = %v, want %v", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSpreadPaths(t *testing.T) {
+	paths := []string{"a", "b", "c", "d", "e"}
+	if got := spreadPaths(paths, 3); !slices.Equal(got, []string{"a", "c", "e"}) {
+		t.Errorf("spreadPaths(5, 3) = %v", got)
+	}
+	if got := spreadPaths(paths, 0); len(got) != 5 {
+		t.Errorf("spreadPaths with no limit should keep all paths, got %v", got)
+	}
+}
+
+func TestReviewReplies(t *testing.T) {
+	comment := func(id, inReplyTo int64, login, body string, pr int) *github.PullRequestComment {
+		return &github.PullRequestComment{
+			ID:             github.Ptr(id),
+			InReplyTo:      github.Ptr(inReplyTo),
+			User:           &github.User{Login: github.Ptr(login)},
+			Body:           github.Ptr(body),
+			PullRequestURL: github.Ptr(fmt.Sprintf("https://api.github.com/repos/acme/tool/pulls/%d", pr)),
+		}
+	}
+	comments := []*github.PullRequestComment{
+		comment(1, 0, "bob", "Please add a test.", 5),
+		comment(2, 1, "Alice", "Added one.", 5),
+		comment(3, 0, "alice", "Nit: rename this.", 6),
+		comment(4, 3, "alice", "Actually, ignore that.", 6),
+		comment(5, 99, "alice", "Reply to a comment on another page.", 6),
+		comment(6, 3, "carol", "Renamed.", 6),
+	}
+	prs := map[int]*github.PullRequest{
+		5: {Title: github.Ptr("Add cache"), User: &github.User{Login: github.Ptr("alice")}},
+	}
+	got := reviewReplies("acme/tool", "alice", comments, func(n int) *github.PullRequest { return prs[n] })
+	want := []ReviewReply{{
+		Repo:         "acme/tool",
+		PRNumber:     5,
+		PRTitle:      "Add cache",
+		ParentAuthor: "bob",
+		Parent:       "Please add a test.",
+		Body:         "Added one.",
+		OwnPR:        true,
+	}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("reviewReplies() = %+v, want %+v", got, want)
+	}
+}
+
+// fakeGitHub serves canned REST API responses by path and records the
+// paths requested.
+type fakeGitHub struct {
+	mu        sync.Mutex
+	responses map[string]any
+	requested []string
+}
+
+// crawler returns a Crawler whose requests go to a test server backed by f.
+func (f *fakeGitHub) crawler(t *testing.T, exhaustive bool) *Crawler {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		f.requested = append(f.requested, r.URL.Path)
+		body, ok := f.responses[r.URL.Path]
+		f.mu.Unlock()
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(body)
+	}))
+	t.Cleanup(srv.Close)
+	client := github.NewClient(srv.Client())
+	client.BaseURL, _ = url.Parse(srv.URL + "/")
+	return &Crawler{pool: &TokenPool{clients: []*github.Client{client}}, exhaustive: exhaustive, maxRepos: 1, concurrency: 1}
+}
+
+func fileContent(text string) map[string]any {
+	return map[string]any{"type": "file", "encoding": "base64", "content": base64.StdEncoding.EncodeToString([]byte(text))}
+}
+
+func TestFetchPRsReactionsFromIssueListing(t *testing.T) {
+	user := map[string]any{"login": "alice"}
+	f := &fakeGitHub{responses: map[string]any{
+		"/repos/o/r/issues": []map[string]any{
+			{"number": 1, "reactions": map[string]any{"total_count": 3, "+1": 2, "heart": 1}},
+			{"number": 2},
+		},
+	}}
+	var prs []*github.PullRequest
+	for number := 1; number <= 2; number++ {
+		prs = append(prs, &github.PullRequest{Number: github.Ptr(number), User: &github.User{Login: github.Ptr("alice")}})
+		f.responses[fmt.Sprintf("/repos/o/r/pulls/%d", number)] = map[string]any{"number": number, "user": user}
+	}
+	got := f.crawler(t, false).fetchPRs(context.Background(), "o", "r", "alice", prs)
+	if len(got) != 2 || got[0].Reactions != (ReactionCounts{Total: 3, PlusOne: 2, Heart: 1}) || got[1].Reactions.Total != 0 {
+		t.Errorf("fetchPRs() = %+v", got)
+	}
+	listings := 0
+	for _, p := range f.requested {
+		if strings.HasPrefix(p, "/repos/o/r/issues") {
+			listings++
+		}
+	}
+	if listings != 1 {
+		t.Errorf("made %d issue requests for reactions, want one listing: %v", listings, f.requested)
+	}
+}
+
+func TestFetchCodeSamplesExhaustive(t *testing.T) {
+	f := &fakeGitHub{responses: map[string]any{}}
+	var entries []*github.TreeEntry
+	add := func(p string) {
+		entries = append(entries, &github.TreeEntry{Path: github.Ptr(p), Type: github.Ptr("blob"), Size: github.Ptr(100)})
+		f.responses["/repos/o/r/contents/"+p] = fileContent("package x")
+	}
+	add("x_test.go")
+	add("y_test.go")
+	for i := range maxCodeSamples + 10 {
+		add(fmt.Sprintf("file%d.go", i))
+	}
+
+	samples := f.crawler(t, true).fetchCodeSamples(context.Background(), "o", "r", entries)
+	if len(samples) != len(entries) {
+		t.Errorf("exhaustive crawl sampled %d files, want all %d", len(samples), len(entries))
+	}
+	samples = f.crawler(t, false).fetchCodeSamples(context.Background(), "o", "r", entries)
+	if want := maxCodeSamples + 3 + 2; len(samples) != want {
+		t.Errorf("sampled crawl sampled %d files, want %d: the source quota plus the tests", len(samples), want)
+	}
+}
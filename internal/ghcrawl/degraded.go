@@ -0,0 +1,132 @@
+package ghcrawl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/google/go-github/v68/github"
+)
+
+// degradedMaxRepos caps deep crawling when running without a usable token.
+// Unauthenticated clients get 60 REST requests per hour.
+const degradedMaxRepos = 2
+
+// degradedLimitDivisor shrinks every per-source cap in a degraded crawl, so
+// code samples, tooling files, and docs cost a request or two per repo
+// instead of up to eight each.
+const degradedLimitDivisor = 5
+
+// Sources that can be skipped, as reported in CrawlResult.Skipped.
+const (
+	sourceIssueComments   = "issue comments"
+	sourceAuthoredIssues  = "authored issues"
+	sourceExternalPRs     = "external pull requests"
+	sourceExternalReviews = "external reviews"
+	sourceDiscussions     = "discussions"
+	sourceProjects        = "projects"
+	sourceSponsorship     = "sponsorship and funding"
+	sourceTimeline        = "contributor timeline"
+	sourceMaintained      = "maintained repos"
+	sourceRepoDetails     = "pull requests, reviews, releases, dependencies, CI runs, and issue triage"
+)
+
+// degradedSkips lists what an unauthenticated crawl leaves out, and why.
+var degradedSkips = map[string]string{
+	sourceIssueComments:   "search API allows 10 unauthenticated requests per minute",
+	sourceAuthoredIssues:  "search API allows 10 unauthenticated requests per minute",
+	sourceExternalPRs:     "search API allows 10 unauthenticated requests per minute",
+	sourceExternalReviews: "search API allows 10 unauthenticated requests per minute",
+	sourceDiscussions:     "GraphQL API requires a token",
+	sourceProjects:        "GraphQL API requires a token",
+	sourceSponsorship:     "GraphQL API requires a token",
+	sourceTimeline:        "saves the 60 requests/hour unauthenticated budget",
+	sourceMaintained:      "GraphQL API requires a token",
+	sourceRepoDetails:     "saves the 60 requests/hour unauthenticated budget",
+}
+
+// SkippedSource records a data source the crawl did not collect.
+type SkippedSource struct {
+	Source string
+	Reason string
+}
+
+// checkAuth decides whether the crawl runs degraded. A crawler without
+// tokens is always degraded. Classic tokens report their scopes, and only
+// GitHub Projects is skipped when one is missing; other sources a token
+// cannot read fail as fetch warnings. A token GitHub
+// rejects outright is an error, since silently ignoring it would hide a
+// typo.
+func (c *Crawler) checkAuth(ctx context.Context) error {
+	if !c.hasToken {
+		c.degraded = true
+		return nil
+	}
+	_, resp, err := c.pool.Next().Users.Get(ctx, "")
+	if err != nil {
+		var errResp *github.ErrorResponse
+		if errors.As(err, &errResp) && errResp.Response != nil && errResp.Response.StatusCode == http.StatusUnauthorized {
+			return fmt.Errorf("github token rejected: %w", err)
+		}
+		slog.Debug("could not verify token scopes", "error", err)
+		return nil
+	}
+	c.scopes = parseScopes(resp.Header)
+	return nil
+}
+
+// parseScopes returns the OAuth scopes of a classic token, or nil when the
+// header is absent (fine-grained tokens and GitHub Apps do not send it).
+func parseScopes(h http.Header) map[string]bool {
+	values, ok := h[http.CanonicalHeaderKey("X-OAuth-Scopes")]
+	if !ok {
+		return nil
+	}
+	scopes := make(map[string]bool)
+	for _, v := range values {
+		for _, s := range strings.Split(v, ",") {
+			if s = strings.TrimSpace(s); s != "" {
+				scopes[s] = true
+			}
+		}
+	}
+	return scopes
+}
+
+// hasScope reports whether the token grants scope, either directly or via a
+// broader parent scope such as "project" for "read:project". Tokens that do
+// not report scopes are assumed to have them.
+func (c *Crawler) hasScope(scope string) bool {
+	if c.scopes == nil || c.scopes[scope] {
+		return true
+	}
+	if _, parent, ok := strings.Cut(scope, ":"); ok && c.scopes[parent] {
+		return true
+	}
+	return false
+}
+
+// available reports whether source should be crawled, recording it in
+// result.Skipped when it is not.
+func (c *Crawler) available(result *CrawlResult, source string) bool {
+	if c.degraded {
+		if reason, skip := degradedSkips[source]; skip {
+			result.Skipped = append(result.Skipped, SkippedSource{Source: source, Reason: reason})
+			return false
+		}
+	}
+	if source == sourceProjects && !c.hasScope("read:project") {
+		result.Skipped = append(result.Skipped, SkippedSource{Source: source, Reason: "token lacks the read:project scope"})
+		return false
+	}
+	return true
+}
+
+// Degraded reports whether the crawl runs without a usable token. It is
+// only meaningful after Crawl has started.
+func (c *Crawler) Degraded() bool {
+	return c.degraded
+}
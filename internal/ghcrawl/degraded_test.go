@@ -0,0 +1,134 @@
+package ghcrawl
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-github/v68/github"
+)
+
+func TestParseScopes(t *testing.T) {
+	t.Run("fine-grained token sends no header", func(t *testing.T) {
+		if got := parseScopes(http.Header{}); got != nil {
+			t.Errorf("expected nil scopes, got %v", got)
+		}
+	})
+
+	t.Run("classic token", func(t *testing.T) {
+		h := http.Header{}
+		h.Set("X-OAuth-Scopes", "repo, read:org,project")
+		got := parseScopes(h)
+		for _, want := range []string{"repo", "read:org", "project"} {
+			if !got[want] {
+				t.Errorf("expected scope %q in %v", want, got)
+			}
+		}
+	})
+}
+
+func TestHasScope(t *testing.T) {
+	tests := []struct {
+		name   string
+		scopes map[string]bool
+		want   bool
+	}{
+		{"unknown scopes", nil, true},
+		{"exact scope", map[string]bool{"read:project": true}, true},
+		{"parent scope", map[string]bool{"project": true}, true},
+		{"missing scope", map[string]bool{"repo": true}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Crawler{scopes: tt.scopes}
+			if got := c.hasScope("read:project"); got != tt.want {
+				t.Errorf("hasScope(read:project) = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckAuthWithoutTokenIsDegraded(t *testing.T) {
+	c := NewCrawler(nil, "", 10, false, 1)
+	if err := c.checkAuth(context.Background()); err != nil {
+		t.Fatalf("checkAuth() error = %v", err)
+	}
+	if !c.Degraded() {
+		t.Fatal("expected crawler without tokens to be degraded")
+	}
+}
+
+func TestAvailable(t *testing.T) {
+	t.Run("degraded skips search and GraphQL sources", func(t *testing.T) {
+		c := &Crawler{degraded: true}
+		result := &CrawlResult{}
+		if c.available(result, sourceDiscussions) {
+			t.Error("expected discussions to be skipped")
+		}
+		if !c.available(result, "starred repos") {
+			t.Error("expected unlisted sources to stay available")
+		}
+		if len(result.Skipped) != 1 || result.Skipped[0].Source != sourceDiscussions {
+			t.Errorf("unexpected skipped list %+v", result.Skipped)
+		}
+	})
+
+	t.Run("missing scope skips projects", func(t *testing.T) {
+		c := &Crawler{scopes: map[string]bool{"repo": true}}
+		result := &CrawlResult{}
+		if c.available(result, sourceProjects) {
+			t.Error("expected projects to be skipped without read:project")
+		}
+		if len(result.Skipped) != 1 {
+			t.Errorf("expected one skipped source, got %+v", result.Skipped)
+		}
+	})
+
+	t.Run("authenticated crawls everything", func(t *testing.T) {
+		c := &Crawler{}
+		result := &CrawlResult{}
+		for source := range degradedSkips {
+			if !c.available(result, source) {
+				t.Errorf("expected %q to be available", source)
+			}
+		}
+	})
+}
+
+func TestDegradedCrawlRepoStaysWithinBudget(t *testing.T) {
+	f := &fakeGitHub{responses: map[string]any{}}
+	var entries []*github.TreeEntry
+	add := func(p string) {
+		entries = append(entries, &github.TreeEntry{Path: github.Ptr(p), Type: github.Ptr("blob"), Size: github.Ptr(100)})
+		f.responses["/repos/o/r/contents/"+p] = fileContent("x")
+	}
+	for i := range 10 {
+		add(fmt.Sprintf("file%d.go", i))
+		add(fmt.Sprintf("file%d_test.go", i))
+		add(fmt.Sprintf("docs/guide%d.md", i))
+	}
+	for _, p := range []string{"README.md", "CONTRIBUTING.md", "Makefile", ".golangci.yml", ".editorconfig", ".github/workflows/ci.yml"} {
+		add(p)
+	}
+	f.responses["/repos/o/r/git/trees/HEAD"] = map[string]any{"sha": "HEAD", "tree": entries}
+	var commits []map[string]any
+	for i := range maxCommitsPerRepo {
+		sha := fmt.Sprintf("%040d", i)
+		commits = append(commits, map[string]any{"sha": sha, "commit": map[string]any{"message": "change"}})
+		f.responses["/repos/o/r/commits/"+sha] = map[string]any{"sha": sha}
+	}
+	f.responses["/repos/o/r/commits"] = commits
+
+	c := f.crawler(t, true)
+	c.degraded = true
+	repo := &github.Repository{Owner: &github.User{Login: github.Ptr("o")}, Name: github.Ptr("r"), FullName: github.Ptr("o/r")}
+	if _, err := c.crawlRepo(context.Background(), "alice", repo, true); err != nil {
+		t.Fatal(err)
+	}
+	// Languages, commits, three patches, the tree, and one file each of
+	// tests, source, tooling, and docs.
+	if got, want := len(f.requested), 10; got > want {
+		t.Errorf("degraded crawl made %d requests, want at most %d: %v", got, want, f.requested)
+	}
+}
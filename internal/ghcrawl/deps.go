@@ -0,0 +1,294 @@
+package ghcrawl
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/google/go-github/v68/github"
+)
+
+const maxManifestsPerRepo = 5
+
+// manifestEcosystems maps dependency manifest file names to the package
+// ecosystem they describe.
+var manifestEcosystems = map[string]string{
+	"go.mod":           "go",
+	"package.json":     "npm",
+	"cargo.toml":       "cargo",
+	"requirements.txt": "pypi",
+	"pyproject.toml":   "pypi",
+	"gemfile":          "rubygems",
+	"composer.json":    "packagist",
+}
+
+// manifestEcosystem returns the ecosystem for a manifest path, or "" when the
+// file is not a recognized manifest or lives in vendored code.
+func manifestEcosystem(p string) string {
+	for _, dir := range strings.Split(path.Dir(p), "/") {
+		switch dir {
+		case "vendor", "node_modules", "third_party", "testdata":
+			return ""
+		}
+	}
+	return manifestEcosystems[strings.ToLower(path.Base(p))]
+}
+
+// fetchDependencies downloads the dependency manifests found in the repo
+// tree, shallowest first, and extracts the declared dependency names.
+func (c *Crawler) fetchDependencies(ctx context.Context, owner, repo string, entries []*github.TreeEntry) []DependencyData {
+	var manifests []string
+	for _, entry := range entries {
+		if entry.GetType() != "blob" || entry.GetSize() > maxFileSizeBytes {
+			continue
+		}
+		if manifestEcosystem(entry.GetPath()) != "" {
+			manifests = append(manifests, entry.GetPath())
+		}
+	}
+	sort.SliceStable(manifests, func(i, j int) bool {
+		return strings.Count(manifests[i], "/") < strings.Count(manifests[j], "/")
+	})
+
+	var result []DependencyData
+	limit := c.limit(maxManifestsPerRepo)
+	for _, p := range manifests {
+		if c.reachedLimit(len(result), limit) {
+			break
+		}
+		fileContent, _, _, err := c.pool.Next().Repositories.GetContents(ctx, owner, repo, p, nil)
+		if err != nil || fileContent == nil {
+			continue
+		}
+		content, err := fileContent.GetContent()
+		if err != nil {
+			continue
+		}
+		deps := parseManifest(path.Base(p), content)
+		if len(deps) == 0 {
+			continue
+		}
+		result = append(result, DependencyData{
+			Repo:         owner + "/" + repo,
+			Path:         p,
+			Ecosystem:    manifestEcosystem(p),
+			Dependencies: deps,
+		})
+	}
+	return result
+}
+
+// parseManifest extracts dependency names from a manifest. Versions are
+// dropped; only the package identities matter for fingerprinting.
+func parseManifest(name, content string) []string {
+	var deps []string
+	switch strings.ToLower(name) {
+	case "go.mod":
+		deps = parseGoMod(content)
+	case "package.json":
+		deps = parseJSONDeps(content, "dependencies", "devDependencies", "peerDependencies")
+	case "composer.json":
+		deps = parseJSONDeps(content, "require", "require-dev")
+	case "cargo.toml":
+		deps = parseTOMLTables(content, func(table string) bool {
+			return strings.HasSuffix(table, "dependencies")
+		})
+	case "pyproject.toml":
+		deps = parsePyProject(content)
+	case "requirements.txt":
+		deps = parseRequirements(content)
+	case "gemfile":
+		deps = parseGemfile(content)
+	}
+	return dedupe(deps)
+}
+
+func parseGoMod(content string) []string {
+	var deps []string
+	inRequire := false
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.Contains(line, "// indirect") {
+			continue
+		}
+		switch {
+		case line == "require (":
+			inRequire = true
+			continue
+		case inRequire && line == ")":
+			inRequire = false
+			continue
+		case strings.HasPrefix(line, "require "):
+			line = strings.TrimPrefix(line, "require ")
+		case !inRequire:
+			continue
+		}
+		if fields := strings.Fields(line); len(fields) >= 2 && !strings.HasPrefix(fields[0], "//") {
+			deps = append(deps, fields[0])
+		}
+	}
+	return deps
+}
+
+func parseJSONDeps(content string, keys ...string) []string {
+	var manifest map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(content), &manifest); err != nil {
+		return nil
+	}
+	var deps []string
+	for _, key := range keys {
+		var section map[string]any
+		if err := json.Unmarshal(manifest[key], &section); err != nil {
+			continue
+		}
+		for name := range section {
+			if name == "php" || strings.HasPrefix(name, "ext-") {
+				continue
+			}
+			deps = append(deps, name)
+		}
+	}
+	sort.Strings(deps)
+	return deps
+}
+
+// parseTOMLTables returns the keys of every table accepted by match. It
+// handles the flat "name = ..." and "[dependencies.name]" forms used by
+// Cargo and Poetry without pulling in a full TOML parser.
+func parseTOMLTables(content string, match func(table string) bool) []string {
+	var deps []string
+	inTable := false
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") {
+			table := strings.Trim(line, "[] ")
+			inTable = match(table)
+			if !inTable {
+				if parent, name, ok := strings.Cut(table, "."); ok && match(parent) {
+					deps = append(deps, strings.Trim(name, `"`))
+				}
+			}
+			continue
+		}
+		if !inTable {
+			continue
+		}
+		if key, _, ok := strings.Cut(line, "="); ok {
+			deps = append(deps, strings.Trim(strings.TrimSpace(key), `"`))
+		}
+	}
+	return deps
+}
+
+var pyRequirementName = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*`)
+
+func parsePyProject(content string) []string {
+	deps := parseTOMLTables(content, func(table string) bool {
+		return table == "tool.poetry.dependencies" || table == "tool.poetry.dev-dependencies"
+	})
+	// PEP 621 lists dependencies as an array of requirement strings.
+	inList := false
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if !inList {
+			if key, rest, ok := strings.Cut(line, "="); ok && strings.TrimSpace(key) == "dependencies" && strings.HasPrefix(strings.TrimSpace(rest), "[") {
+				inList = true
+				line = strings.TrimPrefix(strings.TrimSpace(rest), "[")
+			} else {
+				continue
+			}
+		}
+		items, closed := tomlArrayItems(line)
+		for _, item := range items {
+			if name := pyRequirementName.FindString(strings.TrimSpace(item)); name != "" {
+				deps = append(deps, name)
+			}
+		}
+		if closed {
+			inList = false
+		}
+	}
+	var result []string
+	for _, d := range deps {
+		if !strings.EqualFold(d, "python") {
+			result = append(result, d)
+		}
+	}
+	return result
+}
+
+// tomlArrayItems returns the quoted strings on one line of a TOML array and
+// whether the line closes the array. Brackets and commas inside quotes, such
+// as in "pkg[extra,other]>=1", are part of the item.
+func tomlArrayItems(line string) (items []string, closed bool) {
+	var quote byte
+	start := 0
+	for i := 0; i < len(line); i++ {
+		ch := line[i]
+		switch {
+		case quote != 0:
+			if ch == quote {
+				items = append(items, line[start:i])
+				quote = 0
+			}
+		case ch == '"' || ch == '\'':
+			quote, start = ch, i+1
+		case ch == '#':
+			return items, false
+		case ch == ']':
+			return items, true
+		}
+	}
+	return items, false
+}
+
+func parseRequirements(content string) []string {
+	var deps []string
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "-") {
+			continue
+		}
+		if name := pyRequirementName.FindString(line); name != "" {
+			deps = append(deps, name)
+		}
+	}
+	return deps
+}
+
+var gemDeclaration = regexp.MustCompile(`^gem\s+['"]([^'"]+)['"]`)
+
+func parseGemfile(content string) []string {
+	var deps []string
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for scanner.Scan() {
+		if m := gemDeclaration.FindStringSubmatch(strings.TrimSpace(scanner.Text())); m != nil {
+			deps = append(deps, m[1])
+		}
+	}
+	return deps
+}
+
+func dedupe(items []string) []string {
+	seen := make(map[string]bool, len(items))
+	var result []string
+	for _, item := range items {
+		if item == "" || seen[item] {
+			continue
+		}
+		seen[item] = true
+		result = append(result, item)
+	}
+	return result
+}
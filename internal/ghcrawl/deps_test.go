@@ -0,0 +1,125 @@
+package ghcrawl
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestManifestEcosystem(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"go.mod", "go"},
+		{"web/package.json", "npm"},
+		{"Cargo.toml", "cargo"},
+		{"requirements.txt", "pypi"},
+		{"Gemfile", "rubygems"},
+		{"node_modules/left-pad/package.json", ""},
+		{"vendor/github.com/x/y/go.mod", ""},
+		{"main.go", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := manifestEcosystem(tt.path); got != tt.want {
+				t.Errorf("manifestEcosystem(%q) = %q, want %q", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseManifest(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		want    []string
+	}{
+		{
+			name: "go.mod skips indirect",
+			file: "go.mod",
+			content: `module example.com/app
+
+go 1.22
+
+require github.com/spf13/cobra v1.8.0
+
+require (
+	golang.org/x/sync v0.7.0
+	github.com/pkg/errors v0.9.1 // indirect
+)
+`,
+			want: []string{"github.com/spf13/cobra", "golang.org/x/sync"},
+		},
+		{
+			name:    "package.json",
+			file:    "package.json",
+			content: `{"name":"app","dependencies":{"react":"^18"},"devDependencies":{"vitest":"^1"}}`,
+			want:    []string{"react", "vitest"},
+		},
+		{
+			name:    "invalid package.json",
+			file:    "package.json",
+			content: `{not json`,
+			want:    nil,
+		},
+		{
+			name: "Cargo.toml",
+			file: "Cargo.toml",
+			content: `[package]
+name = "app"
+
+[dependencies]
+serde = { version = "1", features = ["derive"] }
+tokio = "1"
+
+[dev-dependencies]
+proptest = "1"
+
+[dependencies.clap]
+version = "4"
+`,
+			want: []string{"serde", "tokio", "proptest", "clap"},
+		},
+		{
+			name: "requirements.txt",
+			file: "requirements.txt",
+			content: `# web
+flask>=2.0
+-r dev.txt
+requests[socks]==2.31
+`,
+			want: []string{"flask", "requests"},
+		},
+		{
+			name: "pyproject.toml",
+			file: "pyproject.toml",
+			content: `[project]
+name = "app"
+dependencies = [
+    "httpx>=0.27",
+    "uvicorn[standard,http]>=0.30",  # server
+    "pydantic",
+]
+optional = ["pytest"]
+`,
+			want: []string{"httpx", "uvicorn", "pydantic"},
+		},
+		{
+			name: "Gemfile",
+			file: "Gemfile",
+			content: `source "https://rubygems.org"
+gem "rails", "~> 7.1"
+gem 'pg'
+`,
+			want: []string{"rails", "pg"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseManifest(tt.file, tt.content); !slices.Equal(got, tt.want) {
+				t.Errorf("parseManifest(%q) = %v, want %v", tt.file, got, tt.want)
+			}
+		})
+	}
+}
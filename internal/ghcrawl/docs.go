@@ -0,0 +1,95 @@
+package ghcrawl
+
+import (
+	"context"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/google/go-github/v68/github"
+)
+
+const (
+	maxDocsPerRepo  = 6
+	maxDocSizeBytes = 64 * 1024
+	maxDocLen       = 6000
+)
+
+// docKind classifies a repository documentation file. README and
+// CONTRIBUTING are only recognized at the root, in docs/, or in .github/,
+// where GitHub itself looks for them.
+func docKind(p string) string {
+	lower := strings.ToLower(p)
+	dir := path.Dir(lower)
+	stem := strings.TrimSuffix(path.Base(lower), path.Ext(lower))
+
+	switch {
+	case dir == ".github/issue_template" && stem != "config":
+		return "issue template"
+	case dir == ".github/pull_request_template",
+		stem == "pull_request_template" && isDocDir(dir):
+		return "pull request template"
+	case stem == "issue_template" && isDocDir(dir):
+		return "issue template"
+	case stem == "readme" && isDocDir(dir):
+		return "readme"
+	case stem == "contributing" && isDocDir(dir):
+		return "contributing"
+	}
+	return ""
+}
+
+func isDocDir(dir string) bool {
+	return dir == "." || dir == "docs" || dir == ".github"
+}
+
+var docKindOrder = map[string]int{
+	"readme":                0,
+	"contributing":          1,
+	"pull request template": 2,
+	"issue template":        3,
+}
+
+// fetchDocs downloads the README, CONTRIBUTING guide, and issue/PR templates
+// found in the repo tree. READMEs come first since they say the most about
+// how the developer presents their work.
+func (c *Crawler) fetchDocs(ctx context.Context, owner, repo string, entries []*github.TreeEntry) []RepoDoc {
+	var paths []string
+	for _, entry := range entries {
+		if entry.GetType() != "blob" || entry.GetSize() > maxDocSizeBytes {
+			continue
+		}
+		if docKind(entry.GetPath()) != "" {
+			paths = append(paths, entry.GetPath())
+		}
+	}
+	sort.SliceStable(paths, func(i, j int) bool {
+		ki, kj := docKindOrder[docKind(paths[i])], docKindOrder[docKind(paths[j])]
+		if ki != kj {
+			return ki < kj
+		}
+		return strings.Count(paths[i], "/") < strings.Count(paths[j], "/")
+	})
+
+	var docs []RepoDoc
+	limit := c.limit(maxDocsPerRepo)
+	for _, p := range paths {
+		if c.reachedLimit(len(docs), limit) {
+			break
+		}
+		fileContent, _, _, err := c.pool.Next().Repositories.GetContents(ctx, owner, repo, p, nil)
+		if err != nil || fileContent == nil {
+			continue
+		}
+		content, err := fileContent.GetContent()
+		if err != nil || strings.TrimSpace(content) == "" {
+			continue
+		}
+		docs = append(docs, RepoDoc{
+			Path:    p,
+			Kind:    docKind(p),
+			Content: truncate(content, maxDocLen),
+		})
+	}
+	return docs
+}
@@ -0,0 +1,31 @@
+package ghcrawl
+
+import "testing"
+
+func TestDocKind(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"README.md", "readme"},
+		{"readme.rst", "readme"},
+		{"docs/README.md", "readme"},
+		{"cmd/tool/README.md", ""},
+		{"CONTRIBUTING.md", "contributing"},
+		{".github/CONTRIBUTING.md", "contributing"},
+		{".github/PULL_REQUEST_TEMPLATE.md", "pull request template"},
+		{"pull_request_template.md", "pull request template"},
+		{".github/PULL_REQUEST_TEMPLATE/feature.md", "pull request template"},
+		{".github/ISSUE_TEMPLATE/bug_report.yml", "issue template"},
+		{".github/ISSUE_TEMPLATE/config.yml", ""},
+		{".github/ISSUE_TEMPLATE.md", "issue template"},
+		{"main.go", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := docKind(tt.path); got != tt.want {
+				t.Errorf("docKind(%q) = %q, want %q", tt.path, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package ghcrawl
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/google/go-github/v68/github"
+)
+
+// Error classes used when logging failed fetches.
+const (
+	errClassNotFound  = "not_found"
+	errClassForbidden = "forbidden"
+	errClassEmpty     = "empty_repo"
+	errClassCanceled  = "canceled"
+	errClassTransient = "transient"
+	errClassOther     = "other"
+)
+
+// classifyError sorts a failed API call into expected outcomes (missing or
+// restricted resources, empty repos) and failures worth surfacing. By the
+// time an error reaches the crawler the transport has already retried
+// transient failures, so those mean data was lost.
+func classifyError(err error) string {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return errClassCanceled
+	}
+	var errResp *github.ErrorResponse
+	if errors.As(err, &errResp) && errResp.Response != nil {
+		switch code := errResp.Response.StatusCode; {
+		case code == http.StatusNotFound || code == http.StatusGone:
+			return errClassNotFound
+		case code == http.StatusForbidden || code == http.StatusUnauthorized:
+			return errClassForbidden
+		case code == http.StatusConflict:
+			return errClassEmpty
+		case isTransientStatus(code):
+			return errClassTransient
+		}
+		return errClassOther
+	}
+	var rateErr *github.RateLimitError
+	var abuseErr *github.AbuseRateLimitError
+	if errors.As(err, &rateErr) || errors.As(err, &abuseErr) {
+		return errClassTransient
+	}
+	if isTransientNetError(context.Background(), err) {
+		return errClassTransient
+	}
+	return errClassOther
+}
+
+// logFetchError logs a failed fetch and returns its class. Expected
+// failures stay at debug level; transient and unknown failures are warnings
+// because they silently drop data from the persona.
+func logFetchError(msg string, err error, args ...any) string {
+	class := classifyError(err)
+	args = append(args, "error", err, "class", class)
+	switch class {
+	case errClassTransient, errClassOther:
+		slog.Warn(msg, args...)
+	default:
+		slog.Debug(msg, args...)
+	}
+	return class
+}
+
+// noteFetchError logs a failed fetch and, when it means data is missing
+// from the crawl (access denied, transient, or unknown failures), records it
+// for the end-of-crawl report. Missing resources and empty repos are normal
+// and not recorded.
+func (c *Crawler) noteFetchError(msg string, err error, args ...any) {
+	switch logFetchError(msg, err, args...) {
+	case errClassForbidden, errClassTransient, errClassOther:
+		c.warnings.add(msg, err, args)
+	}
+}
+
+// warnFetch logs and records a failure of a whole data source.
+func (c *Crawler) warnFetch(msg string, err error, args ...any) {
+	slog.Warn(msg, append(args, "error", err)...)
+	c.warnings.add(msg, err, args)
+}
@@ -0,0 +1,39 @@
+package ghcrawl
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"syscall"
+	"testing"
+
+	"github.com/google/go-github/v68/github"
+)
+
+func TestClassifyError(t *testing.T) {
+	status := func(code int) error {
+		return &github.ErrorResponse{Response: &http.Response{StatusCode: code}}
+	}
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"not found", status(http.StatusNotFound), errClassNotFound},
+		{"forbidden", status(http.StatusForbidden), errClassForbidden},
+		{"empty repo", status(http.StatusConflict), errClassEmpty},
+		{"bad gateway", status(http.StatusBadGateway), errClassTransient},
+		{"unprocessable", status(http.StatusUnprocessableEntity), errClassOther},
+		{"connection reset", fmt.Errorf("get: %w", syscall.ECONNRESET), errClassTransient},
+		{"canceled", context.Canceled, errClassCanceled},
+		{"rate limit", &github.RateLimitError{}, errClassTransient},
+		{"unknown", fmt.Errorf("boom"), errClassOther},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := classifyError(tt.err); got != tt.want {
+				t.Errorf("classifyError() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,212 @@
+package ghcrawl
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/go-github/v68/github"
+	"github.com/shurcooL/githubv4"
+)
+
+const (
+	// estimatedRequestLatency is a rough per-request round trip used to turn
+	// call counts into wall-clock time.
+	estimatedRequestLatency = 400 * time.Millisecond
+	// rateLimitWindow is how often the core REST budget resets.
+	rateLimitWindow = time.Hour
+	// coreBudgetPerToken is the hourly REST budget of an authenticated token.
+	coreBudgetPerToken = 5000
+)
+
+// Estimate is a pre-flight approximation of the work a crawl will do.
+type Estimate struct {
+	Repos            int
+	DeepRepos        int
+	PullRequests     int
+	Commits          int
+	IssueSearchHits  int
+	ExternalPRs      int
+	ExternalReviews  int
+	APICalls         int
+	Tokens           int
+	RemainingQuota   int
+	Duration         time.Duration
+	RateLimitedWaits int
+	Selection        []RepoSelection
+}
+
+// Estimate queries cheap counts (repo listing, per-repo totals via GraphQL,
+// and search totals) and approximates the number of REST calls and the time
+// a full Crawl would take with the current settings.
+func (c *Crawler) Estimate(ctx context.Context, username string) (*Estimate, error) {
+	if err := c.checkAuth(ctx); err != nil {
+		return nil, err
+	}
+	repos, err := c.fetchRepos(ctx, username)
+	if err != nil {
+		return nil, fmt.Errorf("listing repos: %w", err)
+	}
+	selection := c.selectDeepCrawl(ctx, repos, username)
+	deepCrawl := selectionRepos(selection)
+
+	est := &Estimate{
+		Repos:     len(repos),
+		DeepRepos: len(deepCrawl),
+		Selection: selection,
+		Tokens:    c.pool.Size(),
+	}
+
+	// Profile, profile README, and the repo listing itself.
+	est.APICalls = 2 + pages(len(repos))
+
+	for _, repo := range deepCrawl {
+		prs, commits := c.fetchRepoCounts(ctx, repo.GetOwner().GetLogin(), repo.GetName())
+		est.PullRequests += prs
+		est.Commits += commits
+		est.APICalls += estimateRepoCalls(prs, commits, c.exhaustive)
+	}
+
+	est.IssueSearchHits = c.searchTotal(ctx, fmt.Sprintf("commenter:%s", username))
+	est.ExternalPRs = c.searchTotal(ctx, fmt.Sprintf("author:%s is:pr -user:%s", username, username))
+	est.ExternalReviews = c.searchTotal(ctx, fmt.Sprintf("commenter:%s is:pr -user:%s", username, username))
+	authoredIssues := c.searchTotal(ctx, fmt.Sprintf("author:%s is:issue", username))
+	est.APICalls += c.estimateSearchCalls(est.IssueSearchHits, authoredIssues, est.ExternalPRs, est.ExternalReviews)
+
+	// Starred repos, watched repos, gists, starred gists (plus the identity
+	// check it needs), orgs, and events.
+	est.APICalls += 7
+
+	est.RemainingQuota = c.remainingQuota(ctx)
+	est.Duration, est.RateLimitedWaits = estimateDuration(est.APICalls, est.RemainingQuota, est.Tokens, c.concurrency)
+	return est, nil
+}
+
+// prCalls is the most REST calls crawlRepo makes for one listed pull
+// request: the review listing and, once the user reviewed it, the detail of
+// someone else's. The user's own take only the detail.
+const prCalls = 2
+
+// estimateRepoCalls approximates the REST calls crawlRepo makes for a repo
+// with the given number of pull requests and commits.
+func estimateRepoCalls(prs, commits int, exhaustive bool) int {
+	// Languages, tree, review comments, releases, branches, tags, workflow
+	// runs, contributor stats, the issue listing for triage, and the user's
+	// issue listing for pull request reactions.
+	calls := 10
+	// One timeline fetch per triaged issue.
+	calls += maxTriageIssues
+	codeSamples := maxCodeSamples + 3 + maxToolingFiles + maxTestSamples + maxManifestsPerRepo + maxDocsPerRepo
+	if exhaustive {
+		calls += 2*pages(prs) + pages(commits)
+		// One detail fetch per commit, plus two calls per pull request.
+		calls += commits + prCalls*prs
+		return calls + codeSamples
+	}
+	listedPRs := min(prs, maxPRsPerRepo)
+	listedCommits := min(commits, maxCommitsPerRepo)
+	calls += 2 // PR and commit listings
+	calls += min(listedCommits, 20)
+	calls += prCalls * listedPRs
+	// Review comments on pull requests past the listing fetch their pull
+	// request.
+	calls += min(prs-listedPRs, maxReviewsPerRepo)
+	return calls + codeSamples
+}
+
+func (c *Crawler) estimateSearchCalls(issueHits, authoredIssues, externalPRs, externalReviews int) int {
+	if !c.exhaustive {
+		issueHits = min(issueHits, maxIssueComments)
+		authoredIssues = min(authoredIssues, maxSearchResults)
+		externalPRs = min(externalPRs, maxSearchResults)
+		externalReviews = min(externalReviews, maxSearchResults)
+	}
+	calls := pages(issueHits) + issueHits
+	calls += pages(authoredIssues)
+	calls += pages(externalPRs) + externalPRs
+	// Each external review reference fetches the PR, reviews, line
+	// comments, and conversation comments.
+	calls += pages(externalReviews) + 4*externalReviews
+	return calls
+}
+
+// estimateDuration converts a call count into wall-clock time. When the calls
+// exceed the remaining quota, each extra budget's worth adds a full reset
+// window of waiting.
+func estimateDuration(calls, remaining, tokens, concurrency int) (time.Duration, int) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	if tokens < 1 {
+		tokens = 1
+	}
+	d := time.Duration((calls+concurrency-1)/concurrency) * estimatedRequestLatency
+	if calls <= remaining {
+		return d, 0
+	}
+	budget := coreBudgetPerToken * tokens
+	waits := (calls - remaining + budget - 1) / budget
+	return d + time.Duration(waits)*rateLimitWindow, waits
+}
+
+func (c *Crawler) fetchRepoCounts(ctx context.Context, owner, repo string) (prs, commits int) {
+	var query struct {
+		Repository struct {
+			PullRequests struct {
+				TotalCount int
+			}
+			DefaultBranchRef struct {
+				Target struct {
+					Commit struct {
+						History struct {
+							TotalCount int
+						}
+					} `graphql:"... on Commit"`
+				}
+			}
+		} `graphql:"repository(owner: $owner, name: $repo)"`
+	}
+	variables := map[string]interface{}{
+		"owner": githubv4.String(owner),
+		"repo":  githubv4.String(repo),
+	}
+	if err := c.gqlPool.Next().Query(ctx, &query, variables); err != nil {
+		slog.Debug("could not fetch repo counts", "repo", owner+"/"+repo, "error", err)
+		return 0, 0
+	}
+	return query.Repository.PullRequests.TotalCount,
+		query.Repository.DefaultBranchRef.Target.Commit.History.TotalCount
+}
+
+func (c *Crawler) searchTotal(ctx context.Context, query string) int {
+	opts := &github.SearchOptions{ListOptions: github.ListOptions{PerPage: 1}}
+	res, _, err := c.pool.Next().Search.Issues(ctx, query, opts)
+	if err != nil {
+		slog.Debug("could not fetch search total", "query", query, "error", err)
+		return 0
+	}
+	return res.GetTotal()
+}
+
+// remainingQuota sums the core REST budget left across all pooled tokens.
+// The rate_limit endpoint itself does not count against the budget.
+func (c *Crawler) remainingQuota(ctx context.Context) int {
+	total := 0
+	for _, client := range c.pool.clients {
+		limits, _, err := client.RateLimit.Get(ctx)
+		if err != nil {
+			slog.Debug("could not fetch rate limit", "error", err)
+			continue
+		}
+		total += limits.GetCore().Remaining
+	}
+	return total
+}
+
+func pages(n int) int {
+	if n <= 0 {
+		return 1
+	}
+	return (n + 99) / 100
+}
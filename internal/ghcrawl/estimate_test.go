@@ -0,0 +1,110 @@
+package ghcrawl
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v68/github"
+)
+
+func TestEstimateRepoCalls(t *testing.T) {
+	t.Run("default mode caps listings", func(t *testing.T) {
+		small := estimateRepoCalls(5, 10, false)
+		large := estimateRepoCalls(5000, 100000, false)
+		capped := estimateRepoCalls(maxPRsPerRepo+maxReviewsPerRepo, maxCommitsPerRepo, false)
+		if large != capped {
+			t.Errorf("default mode should cap at listing limits: got %d, want %d", large, capped)
+		}
+		if small >= large {
+			t.Errorf("expected fewer calls for a small repo: small=%d large=%d", small, large)
+		}
+	})
+
+	t.Run("exhaustive mode scales with history", func(t *testing.T) {
+		def := estimateRepoCalls(500, 2000, false)
+		exh := estimateRepoCalls(500, 2000, true)
+		if exh <= def {
+			t.Errorf("exhaustive estimate %d should exceed default %d", exh, def)
+		}
+	})
+}
+
+func TestEstimateDuration(t *testing.T) {
+	t.Run("within quota", func(t *testing.T) {
+		d, waits := estimateDuration(100, 5000, 1, 5)
+		if waits != 0 {
+			t.Errorf("waits = %d, want 0", waits)
+		}
+		want := 20 * estimatedRequestLatency
+		if d != want {
+			t.Errorf("duration = %v, want %v", d, want)
+		}
+	})
+
+	t.Run("exceeds quota", func(t *testing.T) {
+		d, waits := estimateDuration(12000, 1000, 2, 5)
+		if waits != 2 {
+			t.Errorf("waits = %d, want 2", waits)
+		}
+		if d < 2*time.Hour {
+			t.Errorf("duration = %v, want at least two reset windows", d)
+		}
+	})
+}
+
+func TestPages(t *testing.T) {
+	tests := []struct {
+		n    int
+		want int
+	}{
+		{0, 1},
+		{1, 1},
+		{100, 1},
+		{101, 2},
+		{950, 10},
+	}
+	for _, tt := range tests {
+		if got := pages(tt.n); got != tt.want {
+			t.Errorf("pages(%d) = %d, want %d", tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestEstimateRepoCallsMatchesCrawl(t *testing.T) {
+	f := &fakeGitHub{responses: map[string]any{}}
+	user := func(login string) map[string]any { return map[string]any{"login": login} }
+	// A full listing of pull requests by others and reviewed by the user,
+	// so each takes the most calls.
+	var prs []map[string]any
+	for number := 1; number <= maxPRsPerRepo; number++ {
+		f.responses[fmt.Sprintf("/repos/o/r/pulls/%d/reviews", number)] = []map[string]any{{"id": number, "user": user("alice"), "state": "APPROVED"}}
+		pr := map[string]any{"number": number, "user": user("bob"), "state": "open"}
+		prs = append(prs, pr)
+		f.responses[fmt.Sprintf("/repos/o/r/pulls/%d", number)] = pr
+	}
+	f.responses["/repos/o/r/pulls"] = prs
+	// A line comment on a pull request past the listing.
+	past := maxPRsPerRepo + 1
+	f.responses["/repos/o/r/pulls/comments"] = []map[string]any{{"id": 1, "user": user("alice"), "pull_request_url": fmt.Sprintf("https://api.github.com/repos/o/r/pulls/%d", past)}}
+	f.responses[fmt.Sprintf("/repos/o/r/pulls/%d", past)] = map[string]any{"number": past, "user": user("carol")}
+
+	repo := &github.Repository{Owner: &github.User{Login: github.Ptr("o")}, Name: github.Ptr("r"), FullName: github.Ptr("o/r")}
+	if _, err := f.crawler(t, false).crawlRepo(context.Background(), "alice", repo, false); err != nil {
+		t.Fatal(err)
+	}
+	perPR := 0
+	for _, p := range f.requested {
+		if perPRPath.MatchString(p) {
+			perPR++
+		}
+	}
+	if est := estimateRepoCalls(past, 0, false) - estimateRepoCalls(0, 0, false); perPR != est {
+		t.Errorf("crawl made %d calls for single pull requests, estimate counts %d", perPR, est)
+	}
+}
+
+// perPRPath matches the REST paths of a single pull request.
+var perPRPath = regexp.MustCompile(`^/repos/o/r/(pulls|issues)/\d+(/|$)`)
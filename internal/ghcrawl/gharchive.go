@@ -0,0 +1,323 @@
+package ghcrawl
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/google/go-github/v68/github"
+)
+
+const (
+	// maxArchiveEvents and maxArchiveComments bound what a backfill adds;
+	// exhaustive crawls keep everything.
+	maxArchiveEvents   = 2000
+	maxArchiveComments = 200
+	// archiveLineMax is large enough for the biggest GH Archive payloads
+	// (push events listing many commits); longer lines are skipped.
+	archiveLineMax = 16 << 20
+)
+
+// UseGHArchive backfills events older than the Events API window from GH
+// Archive dumps (https://www.gharchive.org). path is a directory of hourly
+// .json.gz or .json files, or a glob matching them. Exports of the BigQuery
+// githubarchive tables in newline-delimited JSON work too.
+func (c *Crawler) UseGHArchive(path string) {
+	c.ghArchive = path
+}
+
+// archiveData is what a GH Archive backfill found for one user. Each kind
+// of comment is kept apart, as a crawl keeps it.
+type archiveData struct {
+	Events         []EventData
+	IssueComments  []Comment
+	ReviewComments []ReviewComment
+	CommitComments []Comment
+}
+
+// loadGHArchive scans the dumps at path for events performed by username.
+// Comments are kept with their bodies, so ones since deleted on GitHub still
+// reach the persona.
+func (c *Crawler) loadGHArchive(ctx context.Context, path, username string) (archiveData, error) {
+	files, err := archiveFiles(path)
+	if err != nil {
+		return archiveData{}, err
+	}
+	var data archiveData
+	for _, f := range files {
+		if err := ctx.Err(); err != nil {
+			return data, err
+		}
+		if err := scanArchiveFile(f, username, &data); err != nil {
+			c.noteFetchError("could not read GH Archive file", err, "file", f)
+		}
+	}
+	return c.capArchive(data), nil
+}
+
+// archiveFiles expands path into the dump files to scan, in name order, which
+// for GH Archive's YYYY-MM-DD-H naming is chronological.
+func archiveFiles(path string) ([]string, error) {
+	var files []string
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return nil, fmt.Errorf("reading GH Archive dir: %w", err)
+		}
+		for _, e := range entries {
+			if !e.IsDir() {
+				files = append(files, filepath.Join(path, e.Name()))
+			}
+		}
+	} else {
+		files, err = filepath.Glob(path)
+		if err != nil {
+			return nil, fmt.Errorf("bad GH Archive pattern: %w", err)
+		}
+	}
+	files = slices.DeleteFunc(files, func(f string) bool {
+		return !strings.HasSuffix(f, ".json.gz") && !strings.HasSuffix(f, ".json")
+	})
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no GH Archive files found at %s", path)
+	}
+	slices.Sort(files)
+	return files, nil
+}
+
+func scanArchiveFile(path, username string, data *archiveData) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var r io.Reader = f
+	if strings.HasSuffix(path, ".gz") {
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return fmt.Errorf("opening gzip: %w", err)
+		}
+		defer gz.Close()
+		r = gz
+	}
+	return scanArchive(r, username, data)
+}
+
+// scanArchive reads newline-delimited GH Archive events and keeps the ones
+// performed by username. A line longer than archiveLineMax is skipped, so
+// one oversized event does not end the scan of the rest of the file.
+func scanArchive(r io.Reader, username string, data *archiveData) error {
+	br := bufio.NewReaderSize(r, 64*1024)
+	needle := []byte(`"` + strings.ToLower(username) + `"`)
+	var line []byte
+	skipping := false
+	for {
+		chunk, err := br.ReadSlice('\n')
+		if !skipping {
+			line = append(line, chunk...)
+			if len(line) > archiveLineMax {
+				slog.Debug("skipping oversized GH Archive event", "bytes", len(line))
+				skipping = true
+			}
+		}
+		if err == bufio.ErrBufferFull {
+			continue
+		}
+		if !skipping {
+			addArchiveLine(line, username, needle, data)
+		}
+		line, skipping = line[:0], false
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// addArchiveLine adds the event on line to data if username performed it.
+func addArchiveLine(line []byte, username string, needle []byte, data *archiveData) {
+	// Cheap prefilter: hourly dumps hold ~100k events, almost none by this
+	// user.
+	if !containsFold(line, needle) {
+		return
+	}
+	ev, ok := parseArchiveEvent(line)
+	if !ok || !strings.EqualFold(ev.GetActor().GetLogin(), username) {
+		return
+	}
+	data.Events = append(data.Events, EventData{
+		Type:      ev.GetType(),
+		Repo:      ev.GetRepo().GetName(),
+		CreatedAt: ev.GetCreatedAt().Time,
+		Summary:   eventSummary(ev),
+	})
+	addArchiveComment(ev, username, data)
+}
+
+func containsFold(line, needle []byte) bool {
+	return bytes.Contains(bytes.ToLower(line), needle)
+}
+
+// parseArchiveEvent decodes one event. BigQuery exports store the payload
+// as a JSON-encoded string rather than an object, so it is unwrapped.
+func parseArchiveEvent(line []byte) (*github.Event, bool) {
+	var ev github.Event
+	if err := json.Unmarshal(line, &ev); err != nil {
+		return nil, false
+	}
+	if ev.RawPayload != nil && len(*ev.RawPayload) > 0 && (*ev.RawPayload)[0] == '"' {
+		var s string
+		if err := json.Unmarshal(*ev.RawPayload, &s); err == nil {
+			raw := json.RawMessage(s)
+			ev.RawPayload = &raw
+		}
+	}
+	return &ev, true
+}
+
+// addArchiveComment adds the comment of a comment event to data. Line
+// comments on username's own pull requests are skipped, as a crawl skips
+// them.
+func addArchiveComment(ev *github.Event, username string, data *archiveData) {
+	if ev.RawPayload == nil {
+		return
+	}
+	payload, err := ev.ParsePayload()
+	if err != nil {
+		return
+	}
+	repo, date := ev.GetRepo().GetName(), ev.GetCreatedAt().Time
+	comment := func(body, url string) Comment {
+		return Comment{Repo: repo, Author: ev.GetActor().GetLogin(), Body: truncate(body, 2000), URL: url, Date: date}
+	}
+	switch p := payload.(type) {
+	case *github.IssueCommentEvent:
+		if body := p.GetComment().GetBody(); strings.TrimSpace(body) != "" {
+			data.IssueComments = append(data.IssueComments, comment(body, p.GetComment().GetHTMLURL()))
+		}
+	case *github.CommitCommentEvent:
+		if body := p.GetComment().GetBody(); strings.TrimSpace(body) != "" {
+			data.CommitComments = append(data.CommitComments, comment(body, p.GetComment().GetHTMLURL()))
+		}
+	case *github.PullRequestReviewCommentEvent:
+		cm, pr := p.GetComment(), p.GetPullRequest()
+		if strings.TrimSpace(cm.GetBody()) == "" || strings.EqualFold(pr.GetUser().GetLogin(), username) {
+			return
+		}
+		data.ReviewComments = append(data.ReviewComments, ReviewComment{
+			Repo:     repo,
+			PRNumber: pr.GetNumber(),
+			PRTitle:  pr.GetTitle(),
+			PRAuthor: pr.GetUser().GetLogin(),
+			Body:     truncate(cm.GetBody(), 1000),
+			Path:     cm.GetPath(),
+			DiffHunk: truncate(cm.GetDiffHunk(), 2000),
+			URL:      cm.GetHTMLURL(),
+			Date:     date,
+		})
+	}
+}
+
+// capArchive keeps an evenly spread sample of a large backfill so one busy
+// year does not crowd out the rest.
+func (c *Crawler) capArchive(data archiveData) archiveData {
+	if limit := c.limit(maxArchiveEvents); limit > 0 && len(data.Events) > limit {
+		events := make([]EventData, 0, limit)
+		for _, i := range spreadIndices(len(data.Events), limit) {
+			events = append(events, data.Events[i])
+		}
+		data.Events = events
+	}
+	limit := c.limit(maxArchiveComments)
+	data.IssueComments = spread(data.IssueComments, limit)
+	data.ReviewComments = spread(data.ReviewComments, limit)
+	data.CommitComments = spread(data.CommitComments, limit)
+	return data
+}
+
+// spread returns an evenly spread sample of at most limit items, or all of
+// them when limit is zero.
+func spread[T any](items []T, limit int) []T {
+	if limit <= 0 || len(items) <= limit {
+		return items
+	}
+	sample := make([]T, 0, limit)
+	for _, i := range spreadIndices(len(items), limit) {
+		sample = append(sample, items[i])
+	}
+	return sample
+}
+
+// mergeArchive adds backfilled events older than anything the Events API
+// returned, and archived comments not already collected: issue comments to
+// the result's, and line and commit comments to their repo's. A repo the
+// crawl did not collect is added as ArchiveOnly. Events stay newest first.
+func mergeArchive(result *CrawlResult, data archiveData) {
+	var oldest EventData
+	for i, ev := range result.Events {
+		if i == 0 || ev.CreatedAt.Before(oldest.CreatedAt) {
+			oldest = ev
+		}
+	}
+	var older []EventData
+	for _, ev := range data.Events {
+		if len(result.Events) == 0 || ev.CreatedAt.Before(oldest.CreatedAt) {
+			older = append(older, ev)
+		}
+	}
+	slices.SortStableFunc(older, func(a, b EventData) int {
+		return b.CreatedAt.Compare(a.CreatedAt)
+	})
+	result.Events = append(result.Events, older...)
+
+	seen := make(map[string]bool, len(result.IssueComments))
+	for _, cm := range result.IssueComments {
+		seen[cm.URL] = true
+	}
+	for _, cm := range data.IssueComments {
+		if cm.URL != "" && seen[cm.URL] {
+			continue
+		}
+		seen[cm.URL] = true
+		result.IssueComments = append(result.IssueComments, cm)
+	}
+
+	repoIndex := make(map[string]int, len(result.Repos))
+	for i, repo := range result.Repos {
+		repoIndex[repo.FullName] = i
+	}
+	repoOf := func(fullName string) *RepoData {
+		i, ok := repoIndex[fullName]
+		if !ok {
+			_, name, _ := strings.Cut(fullName, "/")
+			result.Repos = append(result.Repos, RepoData{Name: name, FullName: fullName, ArchiveOnly: true})
+			i = len(result.Repos) - 1
+			repoIndex[fullName] = i
+		}
+		return &result.Repos[i]
+	}
+	for _, rc := range data.ReviewComments {
+		repo := repoOf(rc.Repo)
+		if !slices.ContainsFunc(repo.ReviewComments, func(have ReviewComment) bool { return rc.URL != "" && have.URL == rc.URL }) {
+			repo.ReviewComments = append(repo.ReviewComments, rc)
+		}
+	}
+	for _, cm := range data.CommitComments {
+		repo := repoOf(cm.Repo)
+		if !slices.ContainsFunc(repo.CommitComments, func(have Comment) bool { return cm.URL != "" && have.URL == cm.URL }) {
+			repo.CommitComments = append(repo.CommitComments, cm)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package ghcrawl
+
+import (
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const archiveFixture = `{"id":"1","type":"PushEvent","actor":{"login":"someone"},"repo":{"name":"x/y"},"payload":{},"created_at":"2019-03-01T10:00:00Z"}
+{"id":"2","type":"IssueCommentEvent","actor":{"login":"Alice"},"repo":{"name":"acme/api"},"payload":{"action":"created","comment":{"body":"Please add a test.","html_url":"https://github.com/acme/api/issues/1#issuecomment-1"}},"created_at":"2019-03-01T11:00:00Z"}
+{"id":"3","type":"WatchEvent","actor":{"login":"bob"},"repo":{"name":"alice/tool"},"payload":{},"created_at":"2019-03-01T12:00:00Z"}
+{"id":"4","type":"PullRequestReviewCommentEvent","actor":{"login":"alice"},"repo":{"name":"acme/web"},"payload":"{\"action\":\"created\",\"comment\":{\"body\":\"nit: naming\",\"path\":\"main.go\",\"html_url\":\"https://github.com/acme/web/pull/2#r2\"},\"pull_request\":{\"number\":2,\"user\":{\"login\":\"bob\"}}}","created_at":"2019-03-02T09:00:00Z"}
+{"id":"5","type":"PullRequestReviewCommentEvent","actor":{"login":"alice"},"repo":{"name":"alice/tool"},"payload":{"action":"created","comment":{"body":"fixed","html_url":"https://github.com/alice/tool/pull/3#r3"},"pull_request":{"number":3,"user":{"login":"alice"}}},"created_at":"2019-03-02T10:00:00Z"}
+{"id":"6","type":"CommitCommentEvent","actor":{"login":"alice"},"repo":{"name":"acme/api"},"payload":{"comment":{"body":"This breaks the build.","html_url":"https://github.com/acme/api/commit/abc#r6"}},"created_at":"2019-03-03T10:00:00Z"}
+not json
+`
+
+func TestScanArchive(t *testing.T) {
+	var data archiveData
+	if err := scanArchive(strings.NewReader(archiveFixture), "alice", &data); err != nil {
+		t.Fatalf("scanArchive: %v", err)
+	}
+	if len(data.Events) != 4 {
+		t.Fatalf("got %d events, want 4: %+v", len(data.Events), data.Events)
+	}
+	if data.Events[0].Summary != "commented on issue in acme/api" {
+		t.Errorf("summary = %q", data.Events[0].Summary)
+	}
+	if len(data.IssueComments) != 1 || data.IssueComments[0].Body != "Please add a test." {
+		t.Errorf("issue comments = %+v", data.IssueComments)
+	}
+	// BigQuery exports encode the payload as a string. The line comment on
+	// alice's own pull request is not a review.
+	if len(data.ReviewComments) != 1 {
+		t.Fatalf("got %d review comments, want 1: %+v", len(data.ReviewComments), data.ReviewComments)
+	}
+	if rc := data.ReviewComments[0]; rc.Body != "nit: naming" || rc.Repo != "acme/web" || rc.PRNumber != 2 || rc.PRAuthor != "bob" || rc.Path != "main.go" {
+		t.Errorf("string payload review comment = %+v", rc)
+	}
+	if len(data.CommitComments) != 1 || data.CommitComments[0].Body != "This breaks the build." {
+		t.Errorf("commit comments = %+v", data.CommitComments)
+	}
+}
+
+func TestScanArchiveSkipsOversizedLine(t *testing.T) {
+	huge := `{"actor":{"login":"alice"},"payload":"` + strings.Repeat("x", archiveLineMax) + `"}` + "\n"
+	var data archiveData
+	if err := scanArchive(strings.NewReader(huge+archiveFixture), "alice", &data); err != nil {
+		t.Fatalf("scanArchive: %v", err)
+	}
+	if len(data.Events) != 4 {
+		t.Errorf("got %d events after an oversized line, want 4", len(data.Events))
+	}
+}
+
+func TestLoadGHArchiveGzip(t *testing.T) {
+	dir := t.TempDir()
+	f, err := os.Create(filepath.Join(dir, "2019-03-01-10.json.gz"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	gz := gzip.NewWriter(f)
+	if _, err := gz.Write([]byte(archiveFixture)); err != nil {
+		t.Fatal(err)
+	}
+	gz.Close()
+	f.Close()
+	if err := os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("ignored"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	c := &Crawler{}
+	data, err := c.loadGHArchive(t.Context(), dir, "alice")
+	if err != nil {
+		t.Fatalf("loadGHArchive: %v", err)
+	}
+	if len(data.Events) != 4 {
+		t.Errorf("got %d events, want 4", len(data.Events))
+	}
+
+	if _, err := c.loadGHArchive(t.Context(), filepath.Join(dir, "*.csv"), "alice"); err == nil {
+		t.Error("expected error when no dump files match")
+	}
+}
+
+func TestMergeArchive(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, 1, d, 0, 0, 0, 0, time.UTC) }
+	result := &CrawlResult{
+		Events:        []EventData{{Summary: "api new", CreatedAt: day(20)}, {Summary: "api old", CreatedAt: day(10)}},
+		IssueComments: []Comment{{URL: "u1", Body: "kept"}},
+		Repos:         []RepoData{{Name: "api", FullName: "acme/api", ReviewComments: []ReviewComment{{URL: "r1", Body: "kept"}}}},
+	}
+	mergeArchive(result, archiveData{
+		Events: []EventData{
+			{Summary: "overlap", CreatedAt: day(15)},
+			{Summary: "archive old", CreatedAt: day(1)},
+			{Summary: "archive mid", CreatedAt: day(5)},
+		},
+		IssueComments:  []Comment{{URL: "u1", Body: "dup"}, {URL: "u2", Body: "deleted since"}},
+		ReviewComments: []ReviewComment{{Repo: "acme/api", URL: "r1", Body: "dup"}, {Repo: "acme/api", URL: "r2", Body: "older"}},
+		CommitComments: []Comment{{Repo: "acme/web", URL: "c1", Body: "on a commit"}},
+	})
+
+	var got []string
+	for _, ev := range result.Events {
+		got = append(got, ev.Summary)
+	}
+	want := "api new,api old,archive mid,archive old"
+	if strings.Join(got, ",") != want {
+		t.Errorf("events = %v, want %s", got, want)
+	}
+	if len(result.IssueComments) != 2 || result.IssueComments[1].Body != "deleted since" {
+		t.Errorf("comments = %+v", result.IssueComments)
+	}
+	if len(result.Repos) != 2 {
+		t.Fatalf("repos = %+v, want acme/api and the added acme/web", result.Repos)
+	}
+	if rcs := result.Repos[0].ReviewComments; len(rcs) != 2 || rcs[1].Body != "older" {
+		t.Errorf("review comments = %+v", rcs)
+	}
+	if web := result.Repos[1]; web.Name != "web" || web.FullName != "acme/web" || !web.ArchiveOnly || len(web.CommitComments) != 1 || len(web.ReviewComments) != 0 {
+		t.Errorf("added repo = %+v", web)
+	}
+	if result.Repos[0].ArchiveOnly || result.TotalRepos() != 1 {
+		t.Errorf("TotalRepos() = %d, want only the crawled repo counted", result.TotalRepos())
+	}
+}
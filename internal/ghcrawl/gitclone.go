@@ -0,0 +1,183 @@
+package ghcrawl
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v68/github"
+)
+
+const (
+	gitCloneTimeout = 5 * time.Minute
+	// gitCloneDepth bounds history fetched by a shallow clone. Commits older
+	// than this fall back to the API.
+	gitCloneDepth = 500
+)
+
+// localClone is a bare clone of a repo used to read commit patches without
+// one GetCommit API call per commit.
+type localClone struct {
+	dir string
+}
+
+// UseGitClone makes the crawler read commit patches from a shallow local
+// clone of each deep-crawled repo instead of one API call per commit.
+func (c *Crawler) UseGitClone() {
+	c.gitClone = true
+}
+
+// localCommitFiles reads a commit's files from clone. It reports false when
+// there is no clone or the commit is outside its shallow history or on its
+// boundary, where the parent needed for the diff is missing.
+func localCommitFiles(ctx context.Context, clone *localClone, sha string) ([]*github.CommitFile, bool) {
+	if clone == nil {
+		return nil, false
+	}
+	files, err := clone.commitFiles(ctx, sha)
+	if err != nil {
+		slog.Debug("commit not in local clone, falling back to the API", "sha", sha, "error", err)
+		return nil, false
+	}
+	return files, true
+}
+
+// cloneRepo makes a bare, shallow clone of owner/repo. Exhaustive crawls
+// fetch full history. The caller must call remove when done.
+func (c *Crawler) cloneRepo(ctx context.Context, owner, repo string) (*localClone, error) {
+	dir, err := os.MkdirTemp("", "devlica-clone-*")
+	if err != nil {
+		return nil, fmt.Errorf("creating temp dir: %w", err)
+	}
+
+	cloneCtx, cancel := context.WithTimeout(ctx, gitCloneTimeout)
+	defer cancel()
+
+	args := []string{"clone", "--bare", "--quiet", "--single-branch"}
+	if !c.exhaustive {
+		args = append(args, "--depth", strconv.Itoa(gitCloneDepth))
+	}
+	args = append(args, fmt.Sprintf("https://github.com/%s/%s.git", owner, repo), dir)
+	cmd := exec.CommandContext(cloneCtx, "git", args...)
+	cmd.Env = gitHubCloneEnv(c.privateToken)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		lc := &localClone{dir: dir}
+		lc.remove()
+		return nil, fmt.Errorf("git clone: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return &localClone{dir: dir}, nil
+}
+
+func (lc *localClone) remove() {
+	if err := os.RemoveAll(lc.dir); err != nil {
+		slog.Debug("could not remove clone", "dir", lc.dir, "error", err)
+	}
+}
+
+// commitFiles returns the per-file patches and line stats of a commit in the
+// same shape the GetCommit API uses, so extractPatch works on both.
+func (lc *localClone) commitFiles(ctx context.Context, sha string) ([]*github.CommitFile, error) {
+	if lc.shallowBoundary(sha) {
+		return nil, fmt.Errorf("commit %s is at the shallow clone boundary", sha)
+	}
+	numstat, err := lc.git(ctx, "show", "--format=", "--numstat", "--no-renames", sha)
+	if err != nil {
+		return nil, err
+	}
+	patch, err := lc.git(ctx, "show", "--format=", "--patch", "--no-renames", "--no-color", sha)
+	if err != nil {
+		return nil, err
+	}
+	return parseLocalCommit(numstat, patch), nil
+}
+
+// shallowBoundary reports whether sha is a commit whose parents the shallow
+// clone left out. git show would diff it against an empty tree and report
+// every file in the repo as added.
+func (lc *localClone) shallowBoundary(sha string) bool {
+	data, err := os.ReadFile(filepath.Join(lc.dir, "shallow"))
+	if err != nil {
+		return false
+	}
+	return slices.Contains(strings.Fields(string(data)), sha)
+}
+
+func (lc *localClone) git(ctx context.Context, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", append([]string{"--git-dir", lc.dir}, args...)...)
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return string(out), nil
+}
+
+// parseLocalCommit combines `git show --numstat` and `git show --patch`
+// output into CommitFiles. Patches keep only the hunks, matching the API's
+// patch field.
+func parseLocalCommit(numstat, patch string) []*github.CommitFile {
+	var files []*github.CommitFile
+	byName := make(map[string]*github.CommitFile)
+	for _, line := range strings.Split(numstat, "\n") {
+		fields := strings.SplitN(line, "\t", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		// Binary files report "-" for both counts.
+		add, _ := strconv.Atoi(fields[0])
+		del, _ := strconv.Atoi(fields[1])
+		f := &github.CommitFile{
+			Filename:  github.Ptr(fields[2]),
+			Additions: github.Ptr(add),
+			Deletions: github.Ptr(del),
+			Changes:   github.Ptr(add + del),
+		}
+		files = append(files, f)
+		byName[fields[2]] = f
+	}
+
+	var current *github.CommitFile
+	var hunks strings.Builder
+	flush := func() {
+		if current != nil && hunks.Len() > 0 {
+			current.Patch = github.Ptr(strings.TrimSuffix(hunks.String(), "\n"))
+		}
+		hunks.Reset()
+	}
+	inHunks := false
+	for _, line := range strings.Split(patch, "\n") {
+		if strings.HasPrefix(line, "diff --git ") {
+			flush()
+			current = byName[diffTarget(line)]
+			inHunks = false
+			continue
+		}
+		if strings.HasPrefix(line, "@@") {
+			inHunks = true
+		}
+		if inHunks {
+			hunks.WriteString(line)
+			hunks.WriteByte('\n')
+		}
+	}
+	flush()
+	return files
+}
+
+// diffTarget extracts the new path from a "diff --git a/x b/x" header.
+func diffTarget(header string) string {
+	rest := strings.TrimPrefix(header, "diff --git ")
+	if i := strings.LastIndex(rest, " b/"); i >= 0 {
+		return rest[i+len(" b/"):]
+	}
+	return ""
+}
@@ -0,0 +1,102 @@
+package ghcrawl
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseLocalCommit(t *testing.T) {
+	numstat := "3\t1\tmain.go\n-\t-\tlogo.png\n"
+	patch := `diff --git a/main.go b/main.go
+index 1111111..2222222 100644
+--- a/main.go
++++ b/main.go
+@@ -1,3 +1,5 @@
+ package main
++
++import "fmt"
+diff --git a/logo.png b/logo.png
+Binary files a/logo.png and b/logo.png differ
+`
+	files := parseLocalCommit(numstat, patch)
+	if len(files) != 2 {
+		t.Fatalf("got %d files, want 2", len(files))
+	}
+	main := files[0]
+	if main.GetFilename() != "main.go" || main.GetAdditions() != 3 || main.GetDeletions() != 1 {
+		t.Errorf("unexpected main.go stats: %+v", main)
+	}
+	if !strings.HasPrefix(main.GetPatch(), "@@ -1,3 +1,5 @@") || !strings.Contains(main.GetPatch(), `+import "fmt"`) {
+		t.Errorf("unexpected main.go patch %q", main.GetPatch())
+	}
+	if files[1].GetPatch() != "" {
+		t.Errorf("binary file should have no patch, got %q", files[1].GetPatch())
+	}
+}
+
+func TestLocalCloneCommitFiles(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	dir := t.TempDir()
+	run := func(args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=t", "GIT_AUTHOR_EMAIL=t@example.com",
+			"GIT_COMMITTER_NAME=t", "GIT_COMMITTER_EMAIL=t@example.com")
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+	run("init", "--quiet")
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("one\ntwo\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	run("add", ".")
+	run("commit", "--quiet", "-m", "add a")
+	sha := run("rev-parse", "HEAD")
+
+	lc := &localClone{dir: filepath.Join(dir, ".git")}
+	files, ok := localCommitFiles(context.Background(), lc, sha)
+	if !ok {
+		t.Fatal("expected commit to be found in local clone")
+	}
+	if len(files) != 1 || files[0].GetFilename() != "a.txt" || files[0].GetAdditions() != 2 {
+		t.Fatalf("unexpected files %+v", files)
+	}
+	if !strings.Contains(files[0].GetPatch(), "+two") {
+		t.Errorf("expected patch content, got %q", files[0].GetPatch())
+	}
+
+	if _, ok := localCommitFiles(context.Background(), lc, strings.Repeat("0", 40)); ok {
+		t.Error("expected unknown commit to fall back")
+	}
+	if _, ok := localCommitFiles(context.Background(), nil, sha); ok {
+		t.Error("expected nil clone to fall back")
+	}
+
+	// The oldest commit of a shallow clone has no parent there, so its
+	// diff would be the whole tree.
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("one\ntwo\nthree\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	run("commit", "--quiet", "-am", "extend a")
+	head := run("rev-parse", "HEAD")
+	shallowDir := filepath.Join(t.TempDir(), "shallow.git")
+	run("clone", "--bare", "--quiet", "--depth", "1", "file://"+dir, shallowDir)
+	shallow := &localClone{dir: shallowDir}
+	if _, ok := localCommitFiles(context.Background(), shallow, head); ok {
+		t.Error("expected shallow boundary commit to fall back")
+	}
+	if files, ok := localCommitFiles(context.Background(), lc, head); !ok || len(files) != 1 || files[0].GetAdditions() != 1 {
+		t.Errorf("expected full history to diff against the parent, got %+v, %v", files, ok)
+	}
+}
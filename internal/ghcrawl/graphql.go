@@ -2,7 +2,6 @@ package ghcrawl
 
 import (
 	"context"
-	"log/slog"
 	"strings"
 	"sync/atomic"
 	"time"
@@ -17,13 +16,13 @@ type GraphQLPool struct {
 }
 
 // NewGraphQLPool creates a pool of GitHub GraphQL clients, one per token.
-func NewGraphQLPool(tokens []string) *GraphQLPool {
+func NewGraphQLPool(tokens []string, concurrency int) *GraphQLPool {
 	if len(tokens) == 0 {
-		return &GraphQLPool{clients: []*githubv4.Client{githubv4.NewClient(newGitHubHTTPClient(""))}}
+		return &GraphQLPool{clients: []*githubv4.Client{githubv4.NewClient(newGitHubHTTPClient("", false, concurrency))}}
 	}
 	clients := make([]*githubv4.Client, len(tokens))
 	for i, tok := range tokens {
-		clients[i] = githubv4.NewClient(newGitHubHTTPClient(tok))
+		clients[i] = githubv4.NewClient(newGitHubHTTPClient(tok, false, concurrency))
 	}
 	return &GraphQLPool{clients: clients}
 }
@@ -31,7 +30,7 @@ func NewGraphQLPool(tokens []string) *GraphQLPool {
 // Next returns the next client in round-robin order.
 func (p *GraphQLPool) Next() *githubv4.Client {
 	if len(p.clients) == 0 {
-		return githubv4.NewClient(newGitHubHTTPClient(""))
+		return githubv4.NewClient(newGitHubHTTPClient("", false, defaultCrawlConcurrency))
 	}
 	idx := p.counter.Add(1) - 1
 	return p.clients[idx%uint64(len(p.clients))]
@@ -40,7 +39,7 @@ func (p *GraphQLPool) Next() *githubv4.Client {
 func (c *Crawler) fetchDiscussions(ctx context.Context, username string, repos []RepoData) []DiscussionData {
 	var all []DiscussionData
 	for _, repo := range repos {
-		if !repo.IsOwner {
+		if !repo.Maintains() {
 			continue
 		}
 		parts := splitOwnerRepo(repo.FullName)
@@ -98,7 +97,7 @@ func (c *Crawler) fetchRepoDiscussions(ctx context.Context, owner, repo, usernam
 	for {
 		err := c.gqlPool.Next().Query(ctx, &query, variables)
 		if err != nil {
-			slog.Debug("could not fetch discussions", "repo", fullName, "error", err)
+			c.noteFetchError("could not fetch discussions", err, "repo", fullName)
 			return result
 		}
 		for _, d := range query.Repository.Discussions.Nodes {
@@ -163,6 +162,9 @@ func filterDiscussionForUser(username string, discussion DiscussionData) (Discus
 	return discussion, true
 }
 
+// fetchProjects lists the user's Projects v2 boards with their custom
+// fields, views, and most recently updated items, which show how they plan
+// and track work.
 func (c *Crawler) fetchProjects(ctx context.Context, username string) []ProjectData {
 	var query struct {
 		User struct {
@@ -170,12 +172,39 @@ func (c *Crawler) fetchProjects(ctx context.Context, username string) []ProjectD
 				Nodes []struct {
 					Title            string
 					ShortDescription string
+					Readme           string
 					URL              string
 					Public           bool
+					Closed           bool
 					CreatedAt        time.Time
-					Items            struct {
+					UpdatedAt        time.Time
+					Fields           struct {
+						Nodes []struct {
+							Common struct {
+								Name     string
+								DataType string
+							} `graphql:"... on ProjectV2FieldCommon"`
+						}
+					} `graphql:"fields(first: 20)"`
+					Views struct {
+						Nodes []struct {
+							Name   string
+							Layout string
+						}
+					} `graphql:"views(first: 10)"`
+					Items struct {
 						TotalCount int
-					}
+						Nodes      []struct {
+							Type       string
+							IsArchived bool
+							UpdatedAt  time.Time
+							Status     struct {
+								SingleSelect struct {
+									Name string
+								} `graphql:"... on ProjectV2ItemFieldSingleSelectValue"`
+							} `graphql:"fieldValueByName(name: \"Status\")"`
+						}
+					} `graphql:"items(last: $items)"`
 				}
 				PageInfo struct {
 					HasNextPage bool
@@ -188,24 +217,45 @@ func (c *Crawler) fetchProjects(ctx context.Context, username string) []ProjectD
 	variables := map[string]interface{}{
 		"login":  githubv4.String(username),
 		"cursor": (*githubv4.String)(nil),
+		"items":  githubv4.Int(maxProjectItems),
 	}
 
 	var result []ProjectData
 	for {
 		err := c.gqlPool.Next().Query(ctx, &query, variables)
 		if err != nil {
-			slog.Debug("could not fetch projects", "username", username, "error", err)
+			c.noteFetchError("could not fetch projects", err, "username", username)
 			return result
 		}
 		for _, p := range query.User.ProjectsV2.Nodes {
-			result = append(result, ProjectData{
+			pd := ProjectData{
 				Title:     p.Title,
 				Body:      truncate(p.ShortDescription, 2000),
+				Readme:    truncate(p.Readme, 2000),
 				URL:       p.URL,
 				Public:    p.Public,
+				Closed:    p.Closed,
 				CreatedAt: p.CreatedAt,
+				UpdatedAt: p.UpdatedAt,
 				ItemCount: p.Items.TotalCount,
-			})
+			}
+			for _, f := range p.Fields.Nodes {
+				if f.Common.Name != "" {
+					pd.Fields = append(pd.Fields, ProjectField{Name: f.Common.Name, DataType: f.Common.DataType})
+				}
+			}
+			for _, v := range p.Views.Nodes {
+				pd.Views = append(pd.Views, ProjectView{Name: v.Name, Layout: v.Layout})
+			}
+			for _, it := range p.Items.Nodes {
+				pd.Items = append(pd.Items, ProjectItem{
+					Type:      it.Type,
+					Status:    it.Status.SingleSelect.Name,
+					Archived:  it.IsArchived,
+					UpdatedAt: it.UpdatedAt,
+				})
+			}
+			result = append(result, pd)
 		}
 		if !query.User.ProjectsV2.PageInfo.HasNextPage {
 			break
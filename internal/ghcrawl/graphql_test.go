@@ -37,7 +37,7 @@ func TestSplitOwnerRepo(t *testing.T) {
 }
 
 func TestGraphQLPoolEmptyTokensFallsBackToAnonymousClient(t *testing.T) {
-	pool := NewGraphQLPool(nil)
+	pool := NewGraphQLPool(nil, 1)
 	if client := pool.Next(); client == nil {
 		t.Fatal("Next() returned nil GraphQL client")
 	}
@@ -0,0 +1,248 @@
+package ghcrawl
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// responseCache is the process-wide HTTP cache, nil when disabled. Like
+// githubPacer it is shared by every client, so all tokens and pools use it.
+var responseCache atomic.Pointer[diskCache]
+
+// UseHTTPCache enables a disk-backed cache of GitHub API responses in dir.
+// Responses younger than ttl are served without a request. Older REST
+// responses are revalidated with If-None-Match / If-Modified-Since, which
+// GitHub does not count against the rate limit when it answers 304. GraphQL
+// responses have no validators, so they are only reused within ttl.
+func UseHTTPCache(dir string, ttl time.Duration) error {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return fmt.Errorf("creating http cache dir: %w", err)
+	}
+	responseCache.Store(&diskCache{dir: dir, ttl: ttl})
+	return nil
+}
+
+// HTTPCacheStats reports how requests were served by the cache: hits within
+// ttl, revalidated (304) responses, and misses that downloaded a payload.
+func HTTPCacheStats() (hits, revalidated, misses int64) {
+	c := responseCache.Load()
+	if c == nil {
+		return 0, 0, 0
+	}
+	return c.hits.Load(), c.revalidated.Load(), c.misses.Load()
+}
+
+type diskCache struct {
+	dir string
+	ttl time.Duration
+
+	hits        atomic.Int64
+	revalidated atomic.Int64
+	misses      atomic.Int64
+}
+
+// cacheEntry is one stored response. Tokens are never stored; only a hash
+// of a token contributes to the key, and only where entries are per token.
+type cacheEntry struct {
+	StoredAt time.Time
+	Status   int
+	Header   http.Header
+	Body     []byte
+}
+
+// cachingTransport serves GitHub API responses from responseCache when it is
+// enabled and passes requests through otherwise. It sits outside
+// rateLimitTransport so cache hits skip pacing and throttling. Credentials
+// are added further down the chain, so tokenID identifies the token instead.
+type cachingTransport struct {
+	base    http.RoundTripper
+	tokenID string
+	private bool
+}
+
+// newCachingTransport wraps base for the given token. Pool tokens read
+// public data, which looks the same to any token, so they share entries and
+// a rotating pool still hits the cache across runs. A private token sees
+// the user's private repos, so its entries are kept apart.
+func newCachingTransport(base http.RoundTripper, token string, private bool) *cachingTransport {
+	sum := sha256.Sum256([]byte(token))
+	return &cachingTransport{base: base, tokenID: hex.EncodeToString(sum[:8]), private: private}
+}
+
+// scope returns the token part of req's cache key: empty for shared
+// entries, the token hash otherwise. /user describes the token itself, so
+// it is never shared.
+func (t *cachingTransport) scope(req *http.Request) string {
+	if t.private || req.URL.Path == "/user" || strings.HasPrefix(req.URL.Path, "/user/") {
+		return t.tokenID
+	}
+	return ""
+}
+
+func (t *cachingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	cache := responseCache.Load()
+	if cache == nil || !cacheable(req) {
+		return t.base.RoundTrip(req)
+	}
+	key, err := cacheKey(req, t.scope(req))
+	if err != nil {
+		return nil, err
+	}
+
+	entry, ok := cache.load(key)
+	if ok && time.Since(entry.StoredAt) < cache.ttl {
+		cache.hits.Add(1)
+		return entry.response(req), nil
+	}
+
+	outReq := req
+	if ok {
+		outReq = req.Clone(req.Context())
+		if etag := entry.Header.Get("ETag"); etag != "" {
+			outReq.Header.Set("If-None-Match", etag)
+		}
+		if lm := entry.Header.Get("Last-Modified"); lm != "" {
+			outReq.Header.Set("If-Modified-Since", lm)
+		}
+	}
+	resp, err := t.base.RoundTrip(outReq)
+	if err != nil {
+		return nil, err
+	}
+
+	if ok && resp.StatusCode == http.StatusNotModified {
+		resp.Body.Close()
+		cache.revalidated.Add(1)
+		entry.StoredAt = time.Now()
+		cache.store(key, entry)
+		return entry.response(req), nil
+	}
+	cache.misses.Add(1)
+	if resp.StatusCode != http.StatusOK {
+		return resp, nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	// GraphQL reports failures with a 200 status; do not pin them.
+	if req.URL.Path == "/graphql" && bytes.Contains(body, []byte(`"errors"`)) {
+		return resp, nil
+	}
+	cache.store(key, &cacheEntry{
+		StoredAt: time.Now(),
+		Status:   resp.StatusCode,
+		Header:   resp.Header.Clone(),
+		Body:     body,
+	})
+	return resp, nil
+}
+
+// cacheable reports whether a request is a read: any GET, or a GraphQL
+// POST. Rate limit status must always be live.
+func cacheable(req *http.Request) bool {
+	if req.URL.Path == "/rate_limit" {
+		return false
+	}
+	switch req.Method {
+	case http.MethodGet:
+		return true
+	case http.MethodPost:
+		return req.URL.Path == "/graphql"
+	}
+	return false
+}
+
+// cacheKey hashes everything that changes the response: method, URL, media
+// type, token scope, and for GraphQL the query body.
+func cacheKey(req *http.Request, scope string) (string, error) {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s %s\n%s\n%s\n", req.Method, req.URL.String(), req.Header.Get("Accept"), scope)
+	if req.Body != nil && req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return "", fmt.Errorf("reading request body for cache key: %w", err)
+		}
+		defer body.Close()
+		if _, err := io.Copy(h, body); err != nil {
+			return "", fmt.Errorf("reading request body for cache key: %w", err)
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func (c *diskCache) path(key string) string {
+	return filepath.Join(c.dir, key[:2], key+".json")
+}
+
+func (c *diskCache) load(key string) (*cacheEntry, bool) {
+	data, err := os.ReadFile(c.path(key))
+	if err != nil {
+		return nil, false
+	}
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		slog.Debug("ignoring corrupt http cache entry", "key", key, "error", err)
+		return nil, false
+	}
+	return &entry, true
+}
+
+// store writes the entry atomically so concurrent crawls never read a
+// partial file. Failures only cost a future cache miss.
+func (c *diskCache) store(key string, entry *cacheEntry) {
+	p := c.path(key)
+	if err := os.MkdirAll(filepath.Dir(p), 0o700); err != nil {
+		slog.Debug("could not write http cache entry", "error", err)
+		return
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		slog.Debug("could not write http cache entry", "error", err)
+		return
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(p), ".tmp-*")
+	if err != nil {
+		slog.Debug("could not write http cache entry", "error", err)
+		return
+	}
+	_, werr := tmp.Write(data)
+	if cerr := tmp.Close(); werr == nil {
+		werr = cerr
+	}
+	if werr == nil {
+		werr = os.Rename(tmp.Name(), p)
+	}
+	if werr != nil {
+		os.Remove(tmp.Name())
+		slog.Debug("could not write http cache entry", "error", werr)
+	}
+}
+
+func (e *cacheEntry) response(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", e.Status, http.StatusText(e.Status)),
+		StatusCode:    e.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        e.Header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(e.Body)),
+		ContentLength: int64(len(e.Body)),
+		Request:       req,
+	}
+}
@@ -0,0 +1,135 @@
+package ghcrawl
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func enableTestCache(t *testing.T, ttl time.Duration) {
+	t.Helper()
+	if err := UseHTTPCache(t.TempDir(), ttl); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { responseCache.Store(nil) })
+}
+
+func TestCachingTransportRevalidatesWithETag(t *testing.T) {
+	var full, notModified atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			notModified.Add(1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		full.Add(1)
+		w.Header().Set("ETag", `"v1"`)
+		io.WriteString(w, "payload")
+	}))
+	defer srv.Close()
+	enableTestCache(t, 0)
+
+	client := &http.Client{Transport: newCachingTransport(http.DefaultTransport, "tok", false)}
+	for range 3 {
+		resp, err := client.Get(srv.URL + "/repos/a/b")
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK || string(body) != "payload" {
+			t.Fatalf("got %d %q", resp.StatusCode, body)
+		}
+	}
+	if full.Load() != 1 || notModified.Load() != 2 {
+		t.Errorf("full downloads = %d, revalidations = %d; want 1 and 2", full.Load(), notModified.Load())
+	}
+	if hits, revalidated, misses := HTTPCacheStats(); hits != 0 || revalidated != 2 || misses != 1 {
+		t.Errorf("stats = %d/%d/%d", hits, revalidated, misses)
+	}
+}
+
+func TestCachingTransportServesFreshEntries(t *testing.T) {
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := requests.Add(1)
+		if r.URL.Path == "/missing" {
+			http.NotFound(w, r)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		if strings.Contains(string(body), "broken") {
+			io.WriteString(w, `{"errors":[{"message":"boom"}]}`)
+			return
+		}
+		io.WriteString(w, r.URL.Path+string(body)+string(rune('0'+n)))
+	}))
+	defer srv.Close()
+	enableTestCache(t, time.Hour)
+
+	get := func(token, method, path, body string) string {
+		t.Helper()
+		client := &http.Client{Transport: newCachingTransport(http.DefaultTransport, token, token == "private")}
+		req, _ := http.NewRequest(method, srv.URL+path, strings.NewReader(body))
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		b, _ := io.ReadAll(resp.Body)
+		return string(b)
+	}
+
+	first := get("a", http.MethodGet, "/users/x", "")
+	if again := get("a", http.MethodGet, "/users/x", ""); again != first {
+		t.Errorf("fresh entry not reused: %q then %q", first, again)
+	}
+	if other := get("b", http.MethodGet, "/users/x", ""); other != first {
+		t.Error("pool tokens should share public entries")
+	}
+	if private := get("private", http.MethodGet, "/users/x", ""); private == first {
+		t.Error("a private token must not share entries")
+	}
+	if self := get("a", http.MethodGet, "/user", ""); get("b", http.MethodGet, "/user", "") == self {
+		t.Error("different tokens must not share /user")
+	}
+	q1 := get("a", http.MethodPost, "/graphql", "q1")
+	if get("a", http.MethodPost, "/graphql", "q1") != q1 || get("a", http.MethodPost, "/graphql", "q2") == q1 {
+		t.Error("GraphQL entries should be keyed by query body")
+	}
+
+	before := requests.Load()
+	get("a", http.MethodGet, "/missing", "")
+	get("a", http.MethodGet, "/missing", "")
+	get("a", http.MethodPost, "/graphql", "broken")
+	get("a", http.MethodPost, "/graphql", "broken")
+	get("a", http.MethodGet, "/rate_limit", "")
+	get("a", http.MethodGet, "/rate_limit", "")
+	if n := requests.Load() - before; n != 6 {
+		t.Errorf("errors and rate limit checks should not be cached, got %d requests, want 6", n)
+	}
+}
+
+func TestCachingTransportDisabledPassesThrough(t *testing.T) {
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+	}))
+	defer srv.Close()
+
+	client := &http.Client{Transport: newCachingTransport(http.DefaultTransport, "tok", false)}
+	for range 2 {
+		resp, err := client.Get(srv.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+	if requests.Load() != 2 {
+		t.Errorf("got %d requests, want 2", requests.Load())
+	}
+}
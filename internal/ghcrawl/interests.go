@@ -0,0 +1,121 @@
+package ghcrawl
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+
+	"github.com/google/go-github/v68/github"
+)
+
+const (
+	maxWatchedRepos = 200
+	maxStarredGists = 50
+)
+
+// fetchWatchedRepos lists repos the user subscribes to for notifications.
+// GitHub subscribes owners to their own repos automatically, so those are
+// left out.
+func (c *Crawler) fetchWatchedRepos(ctx context.Context, username string) ([]StarredRepo, error) {
+	opts := &github.ListOptions{PerPage: 100}
+
+	var result []StarredRepo
+	limit := c.limit(maxWatchedRepos)
+	for {
+		repos, resp, err := c.pool.Next().Activity.ListWatched(ctx, username, opts)
+		if err != nil {
+			return result, err
+		}
+		for _, repo := range repos {
+			if strings.EqualFold(repo.GetOwner().GetLogin(), username) {
+				continue
+			}
+			result = append(result, StarredRepo{
+				Name:        repo.GetName(),
+				FullName:    repo.GetFullName(),
+				Description: truncate(repo.GetDescription(), 500),
+				Language:    repo.GetLanguage(),
+				Topics:      repo.Topics,
+				Stars:       repo.GetStargazersCount(),
+			})
+			if c.reachedLimit(len(result), limit) {
+				return result, nil
+			}
+		}
+		if !c.exhaustive || resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	return result, nil
+}
+
+// fetchStarredGists lists gists the user starred. GitHub only exposes these
+// to the user themselves, so this needs a token (the private token or one
+// from the pool) that authenticates as username; otherwise it returns nil.
+func (c *Crawler) fetchStarredGists(ctx context.Context, username string) ([]GistData, error) {
+	client := c.selfClient(ctx, username)
+	if client == nil {
+		slog.Debug("skipping starred gists: no token authenticates as the requested user", "username", username)
+		return nil, nil
+	}
+
+	opts := &github.GistListOptions{ListOptions: github.ListOptions{PerPage: 100}}
+	var result []GistData
+	limit := c.limit(maxStarredGists)
+	for {
+		gists, resp, err := client.Gists.ListStarred(ctx, opts)
+		if err != nil {
+			return result, err
+		}
+		for _, g := range gists {
+			gd := GistData{
+				ID:          g.GetID(),
+				Owner:       g.GetOwner().GetLogin(),
+				Description: truncate(g.GetDescription(), 500),
+				Public:      g.GetPublic(),
+				CreatedAt:   g.GetCreatedAt().Time,
+				UpdatedAt:   g.GetUpdatedAt().Time,
+			}
+			for name, f := range g.Files {
+				gd.Files = append(gd.Files, GistFile{
+					Name:     string(name),
+					Language: f.GetLanguage(),
+					Size:     f.GetSize(),
+				})
+			}
+			result = append(result, gd)
+			if c.reachedLimit(len(result), limit) {
+				return result, nil
+			}
+		}
+		if !c.exhaustive || resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	return result, nil
+}
+
+// selfClient returns a client authenticated as username, preferring the
+// private token, or nil when no available token belongs to that user.
+func (c *Crawler) selfClient(ctx context.Context, username string) *github.Client {
+	var candidates []*github.Client
+	if c.privateClient != nil {
+		candidates = append(candidates, c.privateClient)
+	}
+	if c.hasToken {
+		candidates = append(candidates, c.pool.Next())
+	}
+	for _, client := range candidates {
+		authUser, _, err := client.Users.Get(ctx, "")
+		if err != nil {
+			c.noteFetchError("could not resolve token identity", err)
+			continue
+		}
+		if privateTokenMatchesUsername(authUser.GetLogin(), username) {
+			return client
+		}
+	}
+	return nil
+}
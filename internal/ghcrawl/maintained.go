@@ -0,0 +1,153 @@
+package ghcrawl
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v68/github"
+	"github.com/shurcooL/githubv4"
+)
+
+const (
+	// maxMaintainedRepos caps how many maintained repos are deep-crawled in
+	// addition to the owned selection.
+	maxMaintainedRepos = 5
+	// maintainedMinCommits is the number of commits in the last year that
+	// marks an org member as a maintainer rather than a drive-by contributor.
+	maintainedMinCommits = 20
+	maintainedWindow     = 365 * 24 * time.Hour
+	// maintainedMinMerges is how many of a repo's last 100 merged pull
+	// requests the user must have merged to count as a maintainer.
+	maintainedMinMerges = 5
+)
+
+// fetchMaintainedRepos finds repos the user does not own but maintains: repos
+// of one of orgs, the organizations they publicly belong to, where they
+// committed at least maintainedMinCommits times in the last year or merged
+// at least maintainedMinMerges recent pull requests. Candidates come from
+// GitHub's "repositories contributed to" list, so only active repos are
+// checked.
+func (c *Crawler) fetchMaintainedRepos(ctx context.Context, username string, orgs []string) []*github.Repository {
+	if len(orgs) == 0 {
+		return nil
+	}
+	memberOf := make(map[string]bool, len(orgs))
+	for _, org := range orgs {
+		memberOf[strings.ToLower(org)] = true
+	}
+
+	var query struct {
+		User struct {
+			RepositoriesContributedTo struct {
+				Nodes []struct {
+					Name       string
+					IsFork     bool
+					IsArchived bool
+					Owner      struct {
+						Login string
+					}
+				}
+			} `graphql:"repositoriesContributedTo(first: 100, includeUserRepositories: false, contributionTypes: [COMMIT, PULL_REQUEST, PULL_REQUEST_REVIEW])"`
+		} `graphql:"user(login: $login)"`
+	}
+	variables := map[string]interface{}{
+		"login": githubv4.String(username),
+	}
+	if err := c.gqlPool.Next().Query(ctx, &query, variables); err != nil {
+		c.noteFetchError("could not fetch contributed repos", err, "username", username)
+		return nil
+	}
+
+	limit := c.limit(maxMaintainedRepos)
+	since := time.Now().Add(-maintainedWindow)
+	var maintained []*github.Repository
+	for _, node := range query.User.RepositoriesContributedTo.Nodes {
+		if c.reachedLimit(len(maintained), limit) {
+			break
+		}
+		owner := node.Owner.Login
+		if node.IsFork || node.IsArchived || !memberOf[strings.ToLower(owner)] {
+			continue
+		}
+		if c.recentCommitCount(ctx, owner, node.Name, username, since) < maintainedMinCommits &&
+			c.recentMergeCount(ctx, owner, node.Name, username) < maintainedMinMerges {
+			continue
+		}
+		repo, _, err := c.pool.Next().Repositories.Get(ctx, owner, node.Name)
+		if err != nil {
+			c.noteFetchError("could not fetch maintained repo", err, "repo", owner+"/"+node.Name)
+			continue
+		}
+		maintained = append(maintained, repo)
+	}
+	return maintained
+}
+
+// recentCommitCount counts the user's commits since the given time, capped
+// at one page since only the threshold matters.
+func (c *Crawler) recentCommitCount(ctx context.Context, owner, repo, author string, since time.Time) int {
+	opts := &github.CommitsListOptions{
+		Author:      author,
+		Since:       since,
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+	commits, _, err := c.pool.Next().Repositories.ListCommits(ctx, owner, repo, opts)
+	if err != nil {
+		c.noteFetchError("could not count recent commits", err, "repo", owner+"/"+repo)
+		return 0
+	}
+	return len(commits)
+}
+
+// recentMergeCount counts how many of the repo's last 100 merged pull
+// requests the user merged. Merging takes write access, which GitHub only
+// reveals to collaborators with push access themselves.
+func (c *Crawler) recentMergeCount(ctx context.Context, owner, repo, username string) int {
+	var query struct {
+		Repository struct {
+			PullRequests struct {
+				Nodes []struct {
+					MergedBy struct {
+						Login string
+					}
+				}
+			} `graphql:"pullRequests(states: MERGED, last: 100)"`
+		} `graphql:"repository(owner: $owner, name: $name)"`
+	}
+	variables := map[string]interface{}{
+		"owner": githubv4.String(owner),
+		"name":  githubv4.String(repo),
+	}
+	if err := c.gqlPool.Next().Query(ctx, &query, variables); err != nil {
+		c.noteFetchError("could not count recent merges", err, "repo", owner+"/"+repo)
+		return 0
+	}
+	n := 0
+	for _, pr := range query.Repository.PullRequests.Nodes {
+		if strings.EqualFold(pr.MergedBy.Login, username) {
+			n++
+		}
+	}
+	return n
+}
+
+// mergeMaintained appends maintained repos not already selected for deep
+// crawling.
+func mergeMaintained(selection []RepoSelection, maintained []*github.Repository) []RepoSelection {
+	seen := make(map[string]bool, len(selection))
+	for _, s := range selection {
+		seen[strings.ToLower(s.FullName)] = true
+	}
+	for _, r := range maintained {
+		if !seen[strings.ToLower(r.GetFullName())] {
+			selection = append(selection, RepoSelection{
+				FullName: r.GetFullName(),
+				Reason:   "maintained: org member with frequent recent commits or merges",
+				repo:     r,
+			})
+			seen[strings.ToLower(r.GetFullName())] = true
+		}
+	}
+	return selection
+}
@@ -0,0 +1,69 @@
+package ghcrawl
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-github/v68/github"
+	"github.com/shurcooL/githubv4"
+)
+
+func TestRecentMergeCount(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, `{"data":{"repository":{"pullRequests":{"nodes":[
+			{"mergedBy":{"login":"Alice"}},{"mergedBy":{"login":"bob"}},{"mergedBy":null},{"mergedBy":{"login":"alice"}}]}}}}`)
+	}))
+	defer srv.Close()
+	c := &Crawler{gqlPool: &GraphQLPool{clients: []*githubv4.Client{githubv4.NewEnterpriseClient(srv.URL, srv.Client())}}}
+	if got := c.recentMergeCount(context.Background(), "acme", "api", "alice"); got != 2 {
+		t.Errorf("recentMergeCount() = %d, want 2", got)
+	}
+}
+
+func TestMergeMaintained(t *testing.T) {
+	repo := func(name string) *github.Repository {
+		return &github.Repository{FullName: github.Ptr(name)}
+	}
+	deep := selectAll([]*github.Repository{repo("alice/tool"), repo("acme/api")}, "test")
+	maintained := []*github.Repository{repo("ACME/api"), repo("acme/web"), repo("acme/web")}
+
+	got := mergeMaintained(deep, maintained)
+	var names []string
+	for _, s := range got {
+		names = append(names, s.FullName)
+	}
+	if got[2].Reason == "test" || got[2].repo == nil {
+		t.Errorf("maintained repo should carry its own reason and repo: %+v", got[2])
+	}
+	want := []string{"alice/tool", "acme/api", "acme/web"}
+	if len(names) != len(want) {
+		t.Fatalf("got %v, want %v", names, want)
+	}
+	for i := range want {
+		if names[i] != want[i] {
+			t.Errorf("names[%d] = %q, want %q", i, names[i], want[i])
+		}
+	}
+}
+
+func TestRepoData_Maintains(t *testing.T) {
+	tests := []struct {
+		name string
+		rd   RepoData
+		want bool
+	}{
+		{"owner", RepoData{IsOwner: true}, true},
+		{"maintainer", RepoData{IsMaintainer: true}, true},
+		{"contributor", RepoData{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.rd.Maintains(); got != tt.want {
+				t.Errorf("Maintains() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
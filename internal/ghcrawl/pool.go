@@ -14,13 +14,14 @@ type TokenPool struct {
 }
 
 // NewTokenPool creates a pool of GitHub REST clients, one per token.
-func NewTokenPool(tokens []string) *TokenPool {
+// concurrency caps in-flight requests per token before adaptive throttling.
+func NewTokenPool(tokens []string, concurrency int) *TokenPool {
 	if len(tokens) == 0 {
-		return &TokenPool{clients: []*github.Client{newGitHubClient("")}}
+		return &TokenPool{clients: []*github.Client{newGitHubClient("", concurrency)}}
 	}
 	clients := make([]*github.Client, len(tokens))
 	for i, tok := range tokens {
-		clients[i] = newGitHubClient(tok)
+		clients[i] = newGitHubClient(tok, concurrency)
 	}
 	return &TokenPool{clients: clients}
 }
@@ -28,7 +29,7 @@ func NewTokenPool(tokens []string) *TokenPool {
 // Next returns the next client in round-robin order.
 func (p *TokenPool) Next() *github.Client {
 	if len(p.clients) == 0 {
-		return newGitHubClient("")
+		return newGitHubClient("", defaultCrawlConcurrency)
 	}
 	idx := p.counter.Add(1) - 1
 	return p.clients[idx%uint64(len(p.clients))]
@@ -56,7 +56,7 @@ func TestTokenPoolConcurrentAccess(t *testing.T) {
 }
 
 func TestTokenPoolEmptyTokensFallsBackToAnonymousClient(t *testing.T) {
-	pool := NewTokenPool(nil)
+	pool := NewTokenPool(nil, 1)
 	if got := pool.Size(); got != 1 {
 		t.Fatalf("Size() = %d, want 1", got)
 	}
@@ -0,0 +1,54 @@
+package ghcrawl
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// WriteCrawl saves a crawl result as JSON so later runs can analyze it
+// again without crawling. The format follows the Go types and is only
+// meant to be read back by ReadCrawl from the same version of devlica.
+func WriteCrawl(path string, r *CrawlResult) error {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return fmt.Errorf("encoding crawl: %w", err)
+	}
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return fmt.Errorf("creating crawl directory: %w", err)
+		}
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("writing crawl: %w", err)
+	}
+	return nil
+}
+
+// ReadCrawl loads a crawl result saved by WriteCrawl.
+func ReadCrawl(path string) (*CrawlResult, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading crawl: %w", err)
+	}
+	var r CrawlResult
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("decoding crawl %s: %w", path, err)
+	}
+	return &r, nil
+}
+
+// Hash returns a digest of the crawl's content, "sha256:<hex>", so a
+// persona can name the exact crawl it was built from. A crawl read back
+// with ReadCrawl hashes the same as the one WriteCrawl saved.
+func Hash(r *CrawlResult) (string, error) {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return "", fmt.Errorf("encoding crawl: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,61 @@
+package ghcrawl
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriteReadCrawl(t *testing.T) {
+	merged := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	want := &CrawlResult{
+		User: UserProfile{Login: "alice", CreatedAt: time.Date(2015, 1, 1, 0, 0, 0, 0, time.UTC)},
+		Repos: []RepoData{{
+			FullName:  "alice/tool",
+			Languages: map[string]int{"Go": 1200},
+			Commits:   []CommitData{{SHA: "abc", Message: "Fix parser", Date: merged}},
+			PRs:       []PullRequestData{{Number: 1, MergedAt: &merged}},
+			Triage:    []TriageData{{Number: 2, FirstResponse: 90 * time.Minute, Responded: true}},
+		}},
+		Orgs: []string{"acme"},
+	}
+	path := filepath.Join(t.TempDir(), "crawl", "alice.json")
+	if err := WriteCrawl(path, want); err != nil {
+		t.Fatal(err)
+	}
+	got, err := ReadCrawl(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("round trip = %+v, want %+v", got, want)
+	}
+}
+
+func TestHash(t *testing.T) {
+	data := &CrawlResult{User: UserProfile{Login: "alice"}, Orgs: []string{"acme"}}
+	path := filepath.Join(t.TempDir(), "alice.json")
+	if err := WriteCrawl(path, data); err != nil {
+		t.Fatal(err)
+	}
+	read, err := ReadCrawl(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := Hash(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := Hash(read); got != want {
+		t.Errorf("Hash after round trip = %q, want %q", got, want)
+	}
+	if !strings.HasPrefix(want, "sha256:") {
+		t.Errorf("Hash = %q, want sha256: prefix", want)
+	}
+	data.Orgs = append(data.Orgs, "other")
+	if got, _ := Hash(data); got == want {
+		t.Error("Hash did not change when the crawl changed")
+	}
+}
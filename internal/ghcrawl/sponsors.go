@@ -0,0 +1,92 @@
+package ghcrawl
+
+import (
+	"context"
+
+	"github.com/shurcooL/githubv4"
+)
+
+// maxSponsoring caps how many sponsored accounts are listed by name.
+const maxSponsoring = 50
+
+// fetchSponsorship collects the user's GitHub Sponsors activity: whether
+// they can be sponsored, how many sponsors they have, whom they sponsor
+// publicly, and which of their own non-fork repos publish funding links.
+// Funding links come from the repo's FUNDING.yml or, failing that, the one
+// in the owner's .github repo.
+func (c *Crawler) fetchSponsorship(ctx context.Context, username string) *SponsorshipData {
+	var query struct {
+		User struct {
+			HasSponsorsListing bool
+			Sponsors           struct {
+				TotalCount int
+			} `graphql:"sponsors(first: 1)"`
+			Sponsoring struct {
+				TotalCount int
+				Nodes      []struct {
+					User struct {
+						Login string
+					} `graphql:"... on User"`
+					Organization struct {
+						Login string
+					} `graphql:"... on Organization"`
+				}
+			} `graphql:"sponsoring(first: $sponsoring)"`
+			Repositories struct {
+				Nodes []struct {
+					NameWithOwner string
+					FundingLinks  []struct {
+						Platform string
+					}
+				}
+				PageInfo struct {
+					HasNextPage bool
+					EndCursor   string
+				}
+			} `graphql:"repositories(first: 100, after: $cursor, ownerAffiliations: OWNER, isFork: false)"`
+		} `graphql:"user(login: $login)"`
+	}
+	variables := map[string]interface{}{
+		"login":      githubv4.String(username),
+		"cursor":     (*githubv4.String)(nil),
+		"sponsoring": githubv4.Int(maxSponsoring),
+	}
+
+	var result *SponsorshipData
+	for {
+		if err := c.gqlPool.Next().Query(ctx, &query, variables); err != nil {
+			c.noteFetchError("could not fetch sponsorship", err, "username", username)
+			return result
+		}
+		u := query.User
+		if result == nil {
+			result = &SponsorshipData{
+				Sponsorable:     u.HasSponsorsListing,
+				SponsorCount:    u.Sponsors.TotalCount,
+				SponsoringCount: u.Sponsoring.TotalCount,
+			}
+			for _, n := range u.Sponsoring.Nodes {
+				if n.User.Login != "" {
+					result.Sponsoring = append(result.Sponsoring, n.User.Login)
+				} else if n.Organization.Login != "" {
+					result.Sponsoring = append(result.Sponsoring, n.Organization.Login)
+				}
+			}
+		}
+		for _, r := range u.Repositories.Nodes {
+			result.OwnedRepos++
+			if len(r.FundingLinks) == 0 {
+				continue
+			}
+			fd := FundingData{Repo: r.NameWithOwner}
+			for _, l := range r.FundingLinks {
+				fd.Platforms = append(fd.Platforms, l.Platform)
+			}
+			result.FundedRepos = append(result.FundedRepos, fd)
+		}
+		if !u.Repositories.PageInfo.HasNextPage {
+			return result
+		}
+		variables["cursor"] = githubv4.String(u.Repositories.PageInfo.EndCursor)
+	}
+}
@@ -0,0 +1,191 @@
+package ghcrawl
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/google/go-github/v68/github"
+	"github.com/shurcooL/githubv4"
+)
+
+// Strategies for choosing which repos get deep-crawled.
+const (
+	StrategyDiverse     = "diverse"
+	StrategyRecent      = "recent"
+	StrategyPopular     = "popular"
+	StrategyPinnedFirst = "pinned-first"
+)
+
+// RepoStrategies lists the valid --repo-strategy values.
+var RepoStrategies = []string{StrategyDiverse, StrategyRecent, StrategyPopular, StrategyPinnedFirst}
+
+// RepoSelection is a repo chosen for deep crawling and the reason it was
+// chosen.
+type RepoSelection struct {
+	FullName string
+	Reason   string
+	repo     *github.Repository
+}
+
+// SetRepoStrategy sets how repos are chosen for deep crawling. The default
+// is StrategyDiverse.
+func (c *Crawler) SetRepoStrategy(strategy string) {
+	c.strategy = strategy
+}
+
+// ShowSelection makes Crawl write the deep-crawl selection, with reasons,
+// to w before crawling the selected repos.
+func (c *Crawler) ShowSelection(w io.Writer) {
+	c.selectionOut = w
+}
+
+// WriteSelection prints a selection as an aligned list.
+func WriteSelection(w io.Writer, sel []RepoSelection) {
+	width := 0
+	for _, s := range sel {
+		width = max(width, len(s.FullName))
+	}
+	for _, s := range sel {
+		fmt.Fprintf(w, "  %-*s  %s\n", width, s.FullName, s.Reason)
+	}
+}
+
+// selectByStrategy picks up to maxRepos repos with the crawler's strategy.
+func (c *Crawler) selectByStrategy(ctx context.Context, repos []*github.Repository, maxRepos int, username string) []RepoSelection {
+	switch c.strategy {
+	case StrategyRecent:
+		return selectRecent(repos, maxRepos)
+	case StrategyPopular:
+		return selectPopular(repos, maxRepos, username)
+	case StrategyPinnedFirst:
+		if c.degraded {
+			slog.Warn("pinned repos need a GitHub token, falling back to the diverse strategy")
+			return selectDiverse(repos, maxRepos, username)
+		}
+		return selectPinnedFirst(repos, c.fetchPinnedRepos(ctx, username), maxRepos, username)
+	default:
+		return selectDiverse(repos, maxRepos, username)
+	}
+}
+
+// selectRecent picks the most recently pushed repos.
+func selectRecent(repos []*github.Repository, maxRepos int) []RepoSelection {
+	sorted := slices.Clone(repos)
+	slices.SortStableFunc(sorted, func(a, b *github.Repository) int {
+		return b.GetPushedAt().Compare(a.GetPushedAt().Time)
+	})
+	var result []RepoSelection
+	for _, r := range sorted[:min(maxRepos, len(sorted))] {
+		reason := "recently pushed"
+		if !r.GetPushedAt().IsZero() {
+			reason = "pushed " + r.GetPushedAt().Format("2006-01-02")
+		}
+		result = append(result, RepoSelection{FullName: r.GetFullName(), Reason: reason, repo: r})
+	}
+	return result
+}
+
+// selectPopular picks the owned non-fork repos with the most stars and
+// forks, then fills any remaining budget with other repos the same way.
+func selectPopular(repos []*github.Repository, maxRepos int, username string) []RepoSelection {
+	sorted := slices.Clone(repos)
+	owned := func(r *github.Repository) bool {
+		return !r.GetFork() && strings.EqualFold(r.GetOwner().GetLogin(), username)
+	}
+	slices.SortStableFunc(sorted, func(a, b *github.Repository) int {
+		if oa, ob := owned(a), owned(b); oa != ob {
+			if oa {
+				return -1
+			}
+			return 1
+		}
+		return (b.GetStargazersCount() + b.GetForksCount()) - (a.GetStargazersCount() + a.GetForksCount())
+	})
+	var result []RepoSelection
+	for _, r := range sorted[:min(maxRepos, len(sorted))] {
+		result = append(result, RepoSelection{
+			FullName: r.GetFullName(),
+			Reason:   fmt.Sprintf("popular: %d stars, %d forks", r.GetStargazersCount(), r.GetForksCount()),
+			repo:     r,
+		})
+	}
+	return result
+}
+
+// selectPinnedFirst picks the repos pinned on the user's profile, in pin
+// order, and fills the remaining budget with the diverse strategy.
+func selectPinnedFirst(repos []*github.Repository, pinned []string, maxRepos int, username string) []RepoSelection {
+	byName := make(map[string]*github.Repository, len(repos))
+	for _, r := range repos {
+		byName[strings.ToLower(r.GetFullName())] = r
+	}
+	var result []RepoSelection
+	taken := make(map[string]bool)
+	for _, name := range pinned {
+		r := byName[strings.ToLower(name)]
+		if r == nil || taken[r.GetFullName()] || len(result) >= maxRepos {
+			continue
+		}
+		taken[r.GetFullName()] = true
+		result = append(result, RepoSelection{FullName: r.GetFullName(), Reason: "pinned on profile", repo: r})
+	}
+	// selectDiverse picks at least one repo, even with no budget left.
+	if len(result) >= maxRepos {
+		return result
+	}
+	rest := slices.DeleteFunc(slices.Clone(repos), func(r *github.Repository) bool {
+		return taken[r.GetFullName()]
+	})
+	return append(result, selectDiverse(rest, maxRepos-len(result), username)...)
+}
+
+// fetchPinnedRepos returns the full names of the repos pinned on the user's
+// profile.
+func (c *Crawler) fetchPinnedRepos(ctx context.Context, username string) []string {
+	var query struct {
+		User struct {
+			PinnedItems struct {
+				Nodes []struct {
+					Repository struct {
+						NameWithOwner string
+					} `graphql:"... on Repository"`
+				}
+			} `graphql:"pinnedItems(first: 6, types: [REPOSITORY])"`
+		} `graphql:"user(login: $login)"`
+	}
+	variables := map[string]interface{}{
+		"login": githubv4.String(username),
+	}
+	if err := c.gqlPool.Next().Query(ctx, &query, variables); err != nil {
+		c.noteFetchError("could not fetch pinned repos", err, "username", username)
+		return nil
+	}
+	var pinned []string
+	for _, n := range query.User.PinnedItems.Nodes {
+		if n.Repository.NameWithOwner != "" {
+			pinned = append(pinned, n.Repository.NameWithOwner)
+		}
+	}
+	return pinned
+}
+
+// selectAll selects every repo with the same reason.
+func selectAll(repos []*github.Repository, reason string) []RepoSelection {
+	result := make([]RepoSelection, 0, len(repos))
+	for _, r := range repos {
+		result = append(result, RepoSelection{FullName: r.GetFullName(), Reason: reason, repo: r})
+	}
+	return result
+}
+
+func selectionRepos(sel []RepoSelection) []*github.Repository {
+	repos := make([]*github.Repository, 0, len(sel))
+	for _, s := range sel {
+		repos = append(repos, s.repo)
+	}
+	return repos
+}
@@ -0,0 +1,113 @@
+package ghcrawl
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v68/github"
+)
+
+func strategyRepo(name, owner string, stars int, pushed time.Time, fork bool) *github.Repository {
+	return &github.Repository{
+		Name:            github.Ptr(name),
+		FullName:        github.Ptr(owner + "/" + name),
+		Owner:           &github.User{Login: github.Ptr(owner)},
+		StargazersCount: github.Ptr(stars),
+		PushedAt:        &github.Timestamp{Time: pushed},
+		Fork:            github.Ptr(fork),
+	}
+}
+
+func selectionNames(sel []RepoSelection) string {
+	var names []string
+	for _, s := range sel {
+		names = append(names, s.FullName)
+	}
+	return strings.Join(names, ",")
+}
+
+func TestSelectRecent(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, 1, d, 0, 0, 0, 0, time.UTC) }
+	repos := []*github.Repository{
+		strategyRepo("old", "user", 0, day(1), false),
+		strategyRepo("new", "user", 0, day(20), false),
+		strategyRepo("mid", "user", 0, day(10), false),
+	}
+	got := selectRecent(repos, 2)
+	if names := selectionNames(got); names != "user/new,user/mid" {
+		t.Errorf("got %s", names)
+	}
+	if got[0].Reason != "pushed 2024-01-20" {
+		t.Errorf("reason = %q", got[0].Reason)
+	}
+}
+
+func TestSelectPopular(t *testing.T) {
+	repos := []*github.Repository{
+		strategyRepo("small", "user", 3, time.Time{}, false),
+		strategyRepo("famous-fork", "user", 900, time.Time{}, true),
+		strategyRepo("big", "user", 50, time.Time{}, false),
+		strategyRepo("org", "acme", 500, time.Time{}, false),
+	}
+	got := selectPopular(repos, 3, "user")
+	if names := selectionNames(got); names != "user/big,user/small,user/famous-fork" {
+		t.Errorf("owned repos should come first by popularity, got %s", names)
+	}
+	if got[0].Reason != "popular: 50 stars, 0 forks" {
+		t.Errorf("reason = %q", got[0].Reason)
+	}
+}
+
+func TestSelectPinnedFirst(t *testing.T) {
+	repos := []*github.Repository{
+		strategyRepo("a", "user", 0, time.Time{}, false),
+		strategyRepo("b", "user", 0, time.Time{}, false),
+		strategyRepo("c", "user", 0, time.Time{}, false),
+	}
+	got := selectPinnedFirst(repos, []string{"user/c", "other/gone"}, 2, "user")
+	if len(got) != 2 {
+		t.Fatalf("got %d repos, want 2", len(got))
+	}
+	if got[0].FullName != "user/c" || got[0].Reason != "pinned on profile" {
+		t.Errorf("pinned repo should come first, got %+v", got[0])
+	}
+	if got[1].FullName == "user/c" || got[1].repo == nil {
+		t.Errorf("remaining budget should be filled from unpinned repos, got %+v", got[1])
+	}
+
+	got = selectPinnedFirst(repos, []string{"user/c", "user/a"}, 2, "user")
+	if names := selectionNames(got); names != "user/c,user/a" {
+		t.Errorf("pins filling the budget should be all that is picked, got %s", names)
+	}
+}
+
+func TestSelectDiverseReasons(t *testing.T) {
+	repos := []*github.Repository{
+		strategyRepo("go1", "user", 0, time.Time{}, false),
+		strategyRepo("go2", "user", 0, time.Time{}, false),
+		strategyRepo("fork", "user", 7, time.Time{}, true),
+	}
+	repos[0].Language = github.Ptr("Go")
+	for _, s := range selectDiverse(repos, 2, "user") {
+		if s.Reason == "" || s.repo == nil {
+			t.Errorf("selection without reason or repo: %+v", s)
+		}
+	}
+	if got := selectDiverse(repos[:1], 5, "user"); got[0].Reason != "within the --max-repos budget" {
+		t.Errorf("reason = %q", got[0].Reason)
+	}
+}
+
+func TestWriteSelection(t *testing.T) {
+	var buf bytes.Buffer
+	WriteSelection(&buf, []RepoSelection{
+		{FullName: "user/a", Reason: "pinned on profile"},
+		{FullName: "user/long-name", Reason: "popular: 5 stars, 1 forks"},
+	})
+	want := "  user/a          pinned on profile\n  user/long-name  popular: 5 stars, 1 forks\n"
+	if buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+}
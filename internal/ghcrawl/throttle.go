@@ -0,0 +1,195 @@
+package ghcrawl
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// adaptiveLimiter bounds the number of in-flight requests made with a single
+// token. The bound starts at the configured crawl concurrency and shrinks as
+// the token's remaining rate-limit budget drops, so large budgets crawl fast
+// and small ones back off well before the hard pause kicks in.
+type adaptiveLimiter struct {
+	mu       sync.Mutex
+	max      int
+	allowed  int
+	inFlight int
+	wake     chan struct{}
+}
+
+func newAdaptiveLimiter(max int) *adaptiveLimiter {
+	if max < 1 {
+		max = 1
+	}
+	return &adaptiveLimiter{
+		max:     max,
+		allowed: max,
+		wake:    make(chan struct{}),
+	}
+}
+
+// acquire blocks until a request slot is free or ctx is done.
+func (l *adaptiveLimiter) acquire(ctx context.Context) error {
+	for {
+		l.mu.Lock()
+		if l.inFlight < l.allowed {
+			l.inFlight++
+			l.mu.Unlock()
+			return nil
+		}
+		wake := l.wake
+		l.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-wake:
+		}
+	}
+}
+
+func (l *adaptiveLimiter) release() {
+	l.mu.Lock()
+	l.inFlight--
+	l.broadcastLocked()
+	l.mu.Unlock()
+}
+
+// observe adjusts the allowed parallelism from the rate-limit headers of a
+// response. Search endpoints have their own small budget and are ignored so
+// they do not throttle the core API.
+func (l *adaptiveLimiter) observe(h http.Header) {
+	switch h.Get("X-RateLimit-Resource") {
+	case "search", "code_search":
+		return
+	}
+	remaining, err := strconv.Atoi(h.Get("X-RateLimit-Remaining"))
+	if err != nil {
+		return
+	}
+	limit, err := strconv.Atoi(h.Get("X-RateLimit-Limit"))
+	if err != nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	next := allowedConcurrency(l.max, remaining, limit)
+	if next == l.allowed {
+		return
+	}
+	l.allowed = next
+	l.broadcastLocked()
+}
+
+func (l *adaptiveLimiter) broadcastLocked() {
+	close(l.wake)
+	l.wake = make(chan struct{})
+}
+
+// allowedConcurrency returns how many parallel requests to permit given the
+// remaining budget. Full parallelism is kept while at least half the budget
+// remains; below that it scales down linearly to a single request.
+func allowedConcurrency(max, remaining, limit int) int {
+	if max < 1 {
+		return 1
+	}
+	if limit <= 0 || remaining*2 >= limit {
+		return max
+	}
+	if remaining <= 0 {
+		return 1
+	}
+	n := (max*remaining*2 + limit - 1) / limit
+	if n < 1 {
+		return 1
+	}
+	if n > max {
+		return max
+	}
+	return n
+}
+
+const (
+	minPenaltyInterval = 500 * time.Millisecond
+	maxPenaltyInterval = 5 * time.Second
+)
+
+// githubPacer is shared by every transport because secondary rate limits are
+// enforced per account and host, not per client.
+var githubPacer = newHostPacer()
+
+// hostPacer spaces out requests to the same host. The gap starts at zero and
+// grows each time the host reports a secondary rate limit, then decays back
+// as requests succeed.
+type hostPacer struct {
+	mu    sync.Mutex
+	hosts map[string]*hostPace
+}
+
+type hostPace struct {
+	interval time.Duration
+	next     time.Time
+}
+
+func newHostPacer() *hostPacer {
+	return &hostPacer{hosts: make(map[string]*hostPace)}
+}
+
+// wait reserves the next send slot for host and sleeps until it arrives.
+func (p *hostPacer) wait(ctx context.Context, host string) error {
+	p.mu.Lock()
+	hp := p.hosts[host]
+	if hp == nil || hp.interval == 0 {
+		p.mu.Unlock()
+		return nil
+	}
+	now := time.Now()
+	start := hp.next
+	if start.Before(now) {
+		start = now
+	}
+	hp.next = start.Add(hp.interval)
+	p.mu.Unlock()
+
+	return sleepContext(ctx, time.Until(start))
+}
+
+// penalize doubles the gap between requests to host.
+func (p *hostPacer) penalize(host string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	hp := p.hosts[host]
+	if hp == nil {
+		hp = &hostPace{}
+		p.hosts[host] = hp
+	}
+	hp.interval = min(max(hp.interval*2, minPenaltyInterval), maxPenaltyInterval)
+}
+
+// relax shrinks the gap after a successful request, dropping it entirely
+// once it is small enough not to matter.
+func (p *hostPacer) relax(host string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	hp := p.hosts[host]
+	if hp == nil || hp.interval == 0 {
+		return
+	}
+	hp.interval -= hp.interval / 8
+	if hp.interval < 50*time.Millisecond {
+		hp.interval = 0
+	}
+}
+
+func (p *hostPacer) interval(host string) time.Duration {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if hp := p.hosts[host]; hp != nil {
+		return hp.interval
+	}
+	return 0
+}
@@ -0,0 +1,95 @@
+package ghcrawl
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestAllowedConcurrency(t *testing.T) {
+	tests := []struct {
+		name      string
+		max       int
+		remaining int
+		limit     int
+		want      int
+	}{
+		{"unknown limit", 5, 0, 0, 5},
+		{"full budget", 5, 5000, 5000, 5},
+		{"half budget", 5, 2500, 5000, 5},
+		{"quarter budget", 8, 1250, 5000, 4},
+		{"nearly exhausted", 5, 10, 5000, 1},
+		{"exhausted", 5, 0, 5000, 1},
+		{"zero max", 0, 5000, 5000, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := allowedConcurrency(tt.max, tt.remaining, tt.limit); got != tt.want {
+				t.Errorf("allowedConcurrency(%d, %d, %d) = %d, want %d",
+					tt.max, tt.remaining, tt.limit, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAdaptiveLimiterShrinksWithBudget(t *testing.T) {
+	l := newAdaptiveLimiter(4)
+	h := http.Header{}
+	h.Set("X-RateLimit-Remaining", "100")
+	h.Set("X-RateLimit-Limit", "5000")
+	l.observe(h)
+
+	if err := l.acquire(context.Background()); err != nil {
+		t.Fatalf("first acquire: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := l.acquire(ctx); err == nil {
+		t.Fatal("expected second acquire to block when budget is low")
+	}
+
+	l.release()
+	if err := l.acquire(context.Background()); err != nil {
+		t.Fatalf("acquire after release: %v", err)
+	}
+}
+
+func TestAdaptiveLimiterIgnoresSearchBudget(t *testing.T) {
+	l := newAdaptiveLimiter(3)
+	h := http.Header{}
+	h.Set("X-RateLimit-Resource", "search")
+	h.Set("X-RateLimit-Remaining", "1")
+	h.Set("X-RateLimit-Limit", "30")
+	l.observe(h)
+
+	if l.allowed != 3 {
+		t.Errorf("allowed = %d, want 3 (search budget should not throttle core)", l.allowed)
+	}
+}
+
+func TestHostPacerPenalizeAndRelax(t *testing.T) {
+	p := newHostPacer()
+	const host = "api.github.com"
+
+	p.penalize(host)
+	if got := p.interval(host); got != minPenaltyInterval {
+		t.Fatalf("interval after first penalty = %v, want %v", got, minPenaltyInterval)
+	}
+	for range 10 {
+		p.penalize(host)
+	}
+	if got := p.interval(host); got != maxPenaltyInterval {
+		t.Fatalf("interval after repeated penalties = %v, want %v", got, maxPenaltyInterval)
+	}
+	for range 100 {
+		p.relax(host)
+	}
+	if got := p.interval(host); got != 0 {
+		t.Errorf("interval after relaxing = %v, want 0", got)
+	}
+	if got := p.interval("other.example.com"); got != 0 {
+		t.Errorf("unrelated host interval = %v, want 0", got)
+	}
+}
@@ -0,0 +1,89 @@
+package ghcrawl
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v68/github"
+)
+
+const (
+	// statsAttempts bounds how often a repo's contributor stats are requested
+	// while GitHub is still computing them (HTTP 202).
+	statsAttempts = 3
+	statsRetryGap = 2 * time.Second
+)
+
+// fetchContributorTimeline merges the user's weekly additions, deletions,
+// and commits across repos using the contributor statistics API, which
+// covers the full history of each repo rather than recent events.
+func (c *Crawler) fetchContributorTimeline(ctx context.Context, username string, repos []*github.Repository) []WeeklyActivity {
+	perRepo := make([][]*github.ContributorStats, 0, len(repos))
+	for _, repo := range repos {
+		perRepo = append(perRepo, c.fetchContributorStats(ctx, repo.GetOwner().GetLogin(), repo.GetName()))
+	}
+	return mergeContributorWeeks(username, perRepo)
+}
+
+// mergeContributorWeeks sums the user's non-empty weeks across repos and
+// returns them oldest first.
+func mergeContributorWeeks(username string, perRepo [][]*github.ContributorStats) []WeeklyActivity {
+	weeks := make(map[time.Time]*WeeklyActivity)
+	for _, stats := range perRepo {
+		for _, cs := range stats {
+			if !strings.EqualFold(cs.GetAuthor().GetLogin(), username) {
+				continue
+			}
+			for _, w := range cs.Weeks {
+				if w.GetCommits() == 0 && w.GetAdditions() == 0 && w.GetDeletions() == 0 {
+					continue
+				}
+				start := w.GetWeek().UTC()
+				wa := weeks[start]
+				if wa == nil {
+					wa = &WeeklyActivity{Week: start}
+					weeks[start] = wa
+				}
+				wa.Additions += w.GetAdditions()
+				wa.Deletions += w.GetDeletions()
+				wa.Commits += w.GetCommits()
+				wa.Repos++
+			}
+		}
+	}
+
+	timeline := make([]WeeklyActivity, 0, len(weeks))
+	for _, wa := range weeks {
+		timeline = append(timeline, *wa)
+	}
+	sort.Slice(timeline, func(i, j int) bool {
+		return timeline[i].Week.Before(timeline[j].Week)
+	})
+	return timeline
+}
+
+func (c *Crawler) fetchContributorStats(ctx context.Context, owner, repo string) []*github.ContributorStats {
+	for attempt := range statsAttempts {
+		stats, _, err := c.pool.Next().Repositories.ListContributorsStats(ctx, owner, repo)
+		if err == nil {
+			return stats
+		}
+		var accepted *github.AcceptedError
+		if !errors.As(err, &accepted) {
+			c.noteFetchError("could not fetch contributor stats", err, "repo", owner+"/"+repo)
+			return nil
+		}
+		if attempt == statsAttempts-1 {
+			break
+		}
+		if err := sleepContext(ctx, statsRetryGap); err != nil {
+			return nil
+		}
+	}
+	slog.Debug("contributor stats still being computed, skipping", "repo", owner+"/"+repo)
+	return nil
+}
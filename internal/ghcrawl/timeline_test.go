@@ -0,0 +1,47 @@
+package ghcrawl
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v68/github"
+)
+
+func TestMergeContributorWeeks(t *testing.T) {
+	week1 := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	week2 := week1.AddDate(0, 0, 7)
+	stat := func(login string, weeks ...*github.WeeklyStats) *github.ContributorStats {
+		return &github.ContributorStats{Author: &github.Contributor{Login: github.Ptr(login)}, Weeks: weeks}
+	}
+	week := func(start time.Time, add, del, commits int) *github.WeeklyStats {
+		return &github.WeeklyStats{
+			Week:      &github.Timestamp{Time: start},
+			Additions: github.Ptr(add),
+			Deletions: github.Ptr(del),
+			Commits:   github.Ptr(commits),
+		}
+	}
+
+	perRepo := [][]*github.ContributorStats{
+		{
+			stat("Octocat", week(week2, 10, 2, 1), week(week1, 0, 0, 0)),
+			stat("someone-else", week(week1, 100, 100, 10)),
+		},
+		{
+			stat("octocat", week(week2, 5, 1, 2), week(week1, 3, 0, 1)),
+		},
+	}
+	got := mergeContributorWeeks("octocat", perRepo)
+	want := []WeeklyActivity{
+		{Week: week1, Additions: 3, Deletions: 0, Commits: 1, Repos: 1},
+		{Week: week2, Additions: 15, Deletions: 3, Commits: 3, Repos: 2},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d weeks, want %d: %+v", len(got), len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("week %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
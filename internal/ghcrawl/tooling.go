@@ -0,0 +1,97 @@
+package ghcrawl
+
+import (
+	"context"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/google/go-github/v68/github"
+)
+
+const maxToolingFiles = 8
+
+// toolingFiles are build, lint, hook, and release configs recognized by
+// lowercased base name.
+var toolingFiles = map[string]bool{
+	"makefile": true, "gnumakefile": true, "justfile": true, "taskfile.yml": true, "taskfile.yaml": true,
+	".pre-commit-config.yaml": true, "lefthook.yml": true, ".lefthook.yml": true,
+	".golangci.yml": true, ".golangci.yaml": true, ".golangci.toml": true,
+	".goreleaser.yml": true, ".goreleaser.yaml": true,
+	".eslintrc": true, ".eslintrc.json": true, ".eslintrc.js": true, ".eslintrc.cjs": true, ".eslintrc.yml": true,
+	"eslint.config.js": true, "eslint.config.mjs": true, ".prettierrc": true, ".prettierrc.json": true,
+	"ruff.toml": true, ".ruff.toml": true, "tox.ini": true, "noxfile.py": true, ".flake8": true,
+	"rustfmt.toml": true, ".rustfmt.toml": true, "clippy.toml": true, "deny.toml": true,
+	".rubocop.yml": true, ".clang-format": true, ".clang-tidy": true, ".editorconfig": true,
+	"renovate.json": true, ".releaserc": true, ".releaserc.json": true, "release-please-config.json": true,
+}
+
+// isToolingFile reports whether p configures the repo's automation: CI
+// workflows, task runners, linters and formatters, git hooks, dependency
+// bots, or release tooling.
+func isToolingFile(p string) bool {
+	if isWorkflowFile(p) {
+		return true
+	}
+	switch {
+	case p == ".github/dependabot.yml", p == ".github/dependabot.yaml", p == ".github/renovate.json":
+		return true
+	case path.Dir(p) == ".husky", path.Dir(p) == ".githooks":
+		// Hook scripts live directly in these dirs; husky's own runtime
+		// sits in .husky/_.
+		return true
+	}
+	for _, dir := range strings.Split(path.Dir(p), "/") {
+		switch dir {
+		case "vendor", "node_modules", "third_party", "testdata":
+			return false
+		}
+	}
+	return toolingFiles[strings.ToLower(path.Base(p))]
+}
+
+// fetchTooling downloads the repo's automation configs. Workflows come
+// first since they show the most, then the remaining configs shallowest
+// first, so root-level Makefiles and linter configs win over nested ones.
+func (c *Crawler) fetchTooling(ctx context.Context, owner, repo string, entries []*github.TreeEntry) []CodeSample {
+	var paths []string
+	for _, entry := range entries {
+		if entry.GetType() != "blob" || entry.GetSize() > maxFileSizeBytes {
+			continue
+		}
+		if isToolingFile(entry.GetPath()) {
+			paths = append(paths, entry.GetPath())
+		}
+	}
+	sortToolingPaths(paths)
+
+	var result []CodeSample
+	limit := c.limit(maxToolingFiles)
+	for _, p := range paths {
+		if c.reachedLimit(len(result), limit) {
+			break
+		}
+		fileContent, _, _, err := c.pool.Next().Repositories.GetContents(ctx, owner, repo, p, nil)
+		if err != nil || fileContent == nil {
+			continue
+		}
+		content, err := fileContent.GetContent()
+		if err != nil {
+			continue
+		}
+		result = append(result, CodeSample{Path: p, Content: content})
+	}
+	return result
+}
+
+// sortToolingPaths orders workflows before other configs and shallower
+// paths before deeper ones, keeping tree order otherwise.
+func sortToolingPaths(paths []string) {
+	sort.SliceStable(paths, func(i, j int) bool {
+		wi, wj := isWorkflowFile(paths[i]), isWorkflowFile(paths[j])
+		if wi != wj {
+			return wi
+		}
+		return strings.Count(paths[i], "/") < strings.Count(paths[j], "/")
+	})
+}
@@ -0,0 +1,43 @@
+package ghcrawl
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestIsToolingFile(t *testing.T) {
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{".github/workflows/ci.yml", true},
+		{".github/dependabot.yml", true},
+		{"Makefile", true},
+		{"tools/Justfile", true},
+		{".pre-commit-config.yaml", true},
+		{".golangci.yml", true},
+		{".goreleaser.yaml", true},
+		{".husky/pre-commit", true},
+		{".husky/_/husky.sh", false},
+		{"vendor/foo/Makefile", false},
+		{"main.go", false},
+		{"Dockerfile", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := isToolingFile(tt.path); got != tt.want {
+				t.Errorf("isToolingFile(%q) = %v, want %v", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSortToolingPaths(t *testing.T) {
+	paths := []string{"cmd/Makefile", ".golangci.yml", ".github/workflows/release.yml", "Makefile", ".github/workflows/ci.yml"}
+	sortToolingPaths(paths)
+	want := []string{".github/workflows/release.yml", ".github/workflows/ci.yml", ".golangci.yml", "Makefile", "cmd/Makefile"}
+	if !slices.Equal(paths, want) {
+		t.Errorf("sortToolingPaths() = %v, want %v", paths, want)
+	}
+}
@@ -0,0 +1,129 @@
+package ghcrawl
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v68/github"
+)
+
+const (
+	// maxTriageIssues bounds how many issues per repo get a timeline fetch.
+	maxTriageIssues = 20
+	// closeCommentWindow is how close to a close event the user's comment
+	// must be for the close to count as explained rather than silent.
+	closeCommentWindow = 10 * time.Minute
+)
+
+// fetchTriage collects how the user handled recent issues that other people
+// opened on owner/repo: whether and how fast they replied, which labels they
+// applied, and whether they closed issues with or without a comment. Each
+// issue costs a timeline request, so degraded crawls skip triage with the
+// rest of sourceRepoDetails.
+func (c *Crawler) fetchTriage(ctx context.Context, owner, repo, username string) []TriageData {
+	if c.degraded {
+		return nil
+	}
+	limit := c.limit(maxTriageIssues)
+	opts := &github.IssueListByRepoOptions{
+		State:       "all",
+		Sort:        "created",
+		Direction:   "desc",
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+
+	var result []TriageData
+	for {
+		issues, resp, err := c.pool.Next().Issues.ListByRepo(ctx, owner, repo, opts)
+		if err != nil {
+			c.noteFetchError("could not list issues", err, "repo", owner+"/"+repo)
+			return result
+		}
+		for _, issue := range issues {
+			if issue.IsPullRequest() || strings.EqualFold(issue.GetUser().GetLogin(), username) {
+				continue
+			}
+			events, err := c.fetchIssueTimeline(ctx, owner, repo, issue.GetNumber())
+			if err != nil {
+				c.noteFetchError("could not fetch issue timeline", err, "repo", owner+"/"+repo, "number", issue.GetNumber())
+				continue
+			}
+			result = append(result, summarizeTriage(owner+"/"+repo, issue, events, username))
+			if c.reachedLimit(len(result), limit) {
+				return result
+			}
+		}
+		if !c.exhaustive || resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	return result
+}
+
+func (c *Crawler) fetchIssueTimeline(ctx context.Context, owner, repo string, number int) ([]*github.Timeline, error) {
+	opts := &github.ListOptions{PerPage: 100}
+	var all []*github.Timeline
+	for {
+		events, resp, err := c.pool.Next().Issues.ListIssueTimeline(ctx, owner, repo, number, opts)
+		if err != nil {
+			return all, err
+		}
+		all = append(all, events...)
+		if resp.NextPage == 0 {
+			return all, nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
+// summarizeTriage reduces an issue's timeline to the user's part in it.
+// Comment events carry the commenter in User; other events in Actor.
+func summarizeTriage(fullName string, issue *github.Issue, events []*github.Timeline, username string) TriageData {
+	td := TriageData{
+		Repo:     fullName,
+		Number:   issue.GetNumber(),
+		Title:    issue.GetTitle(),
+		Author:   issue.GetUser().GetLogin(),
+		Body:     truncate(issue.GetBody(), 1000),
+		OpenedAt: issue.GetCreatedAt().Time,
+		Open:     issue.GetState() == "open",
+	}
+
+	var comments []time.Time
+	var closedAt time.Time
+	for _, ev := range events {
+		at := ev.GetCreatedAt().Time
+		switch ev.GetEvent() {
+		case "commented":
+			if !strings.EqualFold(ev.GetUser().GetLogin(), username) && !strings.EqualFold(ev.GetActor().GetLogin(), username) {
+				continue
+			}
+			comments = append(comments, at)
+			if !td.Responded {
+				td.Responded = true
+				td.FirstResponse = at.Sub(td.OpenedAt)
+				td.FirstReply = truncate(ev.GetBody(), 500)
+			}
+		case "labeled":
+			if strings.EqualFold(ev.GetActor().GetLogin(), username) {
+				td.Labels = append(td.Labels, ev.GetLabel().GetName())
+			}
+		case "closed":
+			if strings.EqualFold(ev.GetActor().GetLogin(), username) {
+				td.ClosedByUser = true
+				closedAt = at
+			}
+		}
+	}
+	if td.ClosedByUser {
+		for _, at := range comments {
+			if d := closedAt.Sub(at); d >= -closeCommentWindow && d <= closeCommentWindow {
+				td.ClosedWithComment = true
+				break
+			}
+		}
+	}
+	return td
+}
@@ -0,0 +1,98 @@
+package ghcrawl
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v68/github"
+)
+
+func TestSummarizeTriage(t *testing.T) {
+	opened := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	ts := func(d time.Duration) *github.Timestamp {
+		return &github.Timestamp{Time: opened.Add(d)}
+	}
+	user := func(login string) *github.User {
+		return &github.User{Login: github.Ptr(login)}
+	}
+	issue := &github.Issue{
+		Number:    github.Ptr(7),
+		Title:     github.Ptr("crash on start"),
+		Body:      github.Ptr("panics with a nil map"),
+		State:     github.Ptr("closed"),
+		User:      user("bob"),
+		CreatedAt: ts(0),
+	}
+
+	tests := []struct {
+		name   string
+		events []*github.Timeline
+		check  func(t *testing.T, td TriageData)
+	}{
+		{
+			name: "reply, label, close with comment",
+			events: []*github.Timeline{
+				{Event: github.Ptr("commented"), User: user("bob"), CreatedAt: ts(time.Minute), Body: github.Ptr("more info")},
+				{Event: github.Ptr("commented"), User: user("Alice"), CreatedAt: ts(2 * time.Hour), Body: github.Ptr("fixed in main")},
+				{Event: github.Ptr("labeled"), Actor: user("alice"), CreatedAt: ts(2 * time.Hour), Label: &github.Label{Name: github.Ptr("bug")}},
+				{Event: github.Ptr("labeled"), Actor: user("bot"), CreatedAt: ts(2 * time.Hour), Label: &github.Label{Name: github.Ptr("triage")}},
+				{Event: github.Ptr("closed"), Actor: user("alice"), CreatedAt: ts(2*time.Hour + time.Minute)},
+			},
+			check: func(t *testing.T, td TriageData) {
+				if td.Body != "panics with a nil map" || !td.Responded || td.FirstResponse != 2*time.Hour || td.FirstReply != "fixed in main" {
+					t.Errorf("unexpected response: %+v", td)
+				}
+				if len(td.Labels) != 1 || td.Labels[0] != "bug" {
+					t.Errorf("Labels = %v, want [bug]", td.Labels)
+				}
+				if !td.ClosedByUser || !td.ClosedWithComment {
+					t.Errorf("expected close with comment: %+v", td)
+				}
+			},
+		},
+		{
+			name: "silent close",
+			events: []*github.Timeline{
+				{Event: github.Ptr("commented"), User: user("alice"), CreatedAt: ts(time.Hour), Body: github.Ptr("looking")},
+				{Event: github.Ptr("closed"), Actor: user("alice"), CreatedAt: ts(48 * time.Hour)},
+			},
+			check: func(t *testing.T, td TriageData) {
+				if !td.ClosedByUser || td.ClosedWithComment {
+					t.Errorf("expected silent close: %+v", td)
+				}
+			},
+		},
+		{
+			name: "closed by someone else",
+			events: []*github.Timeline{
+				{Event: github.Ptr("closed"), Actor: user("bob"), CreatedAt: ts(time.Hour)},
+			},
+			check: func(t *testing.T, td TriageData) {
+				if td.Responded || td.ClosedByUser {
+					t.Errorf("user took no part: %+v", td)
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			td := summarizeTriage("alice/tool", issue, tt.events, "alice")
+			if td.Repo != "alice/tool" || td.Number != 7 || td.Author != "bob" || td.Open {
+				t.Errorf("unexpected issue fields: %+v", td)
+			}
+			tt.check(t, td)
+		})
+	}
+}
+
+func TestFetchTriageSkippedWhenDegraded(t *testing.T) {
+	f := &fakeGitHub{responses: map[string]any{
+		"/repos/o/r/issues": []map[string]any{{"number": 1, "user": map[string]any{"login": "bob"}}},
+	}}
+	c := f.crawler(t, false)
+	c.degraded = true
+	if got := c.fetchTriage(context.Background(), "o", "r", "alice"); got != nil || len(f.requested) != 0 {
+		t.Errorf("degraded fetchTriage() = %+v after %v, want no requests", got, f.requested)
+	}
+}
@@ -8,13 +8,31 @@ type CrawlResult struct {
 	Repos          []RepoData
 	IssueComments  []Comment
 	StarredRepos   []StarredRepo
+	WatchedRepos   []StarredRepo
+	StarredGists   []GistData
 	Gists          []GistData
 	Orgs           []string
+	Sponsorship    *SponsorshipData
 	AuthoredIssues []IssueData
 	ExternalPRs    []PullRequestData
 	Events         []EventData
 	Discussions    []DiscussionData
 	Projects       []ProjectData
+	Timeline       []WeeklyActivity
+	Skipped        []SkippedSource
+	Warnings       []CrawlWarning
+}
+
+// TotalRepos returns the number of repos crawled, leaving out ones added
+// only to hold GH Archive comments.
+func (r *CrawlResult) TotalRepos() int {
+	n := 0
+	for _, repo := range r.Repos {
+		if !repo.ArchiveOnly {
+			n++
+		}
+	}
+	return n
 }
 
 // TotalCommits returns the sum of commits across all repos.
@@ -42,6 +60,25 @@ func (r *CrawlResult) TotalReviews() int {
 	return n
 }
 
+// SigningStats counts commits that are signed and commits GitHub verified,
+// grouped by signing method.
+func (r *CrawlResult) SigningStats() (signed, verified int, byMethod map[string]int) {
+	byMethod = make(map[string]int)
+	for _, repo := range r.Repos {
+		for _, cm := range repo.Commits {
+			if cm.Signature == "" {
+				continue
+			}
+			signed++
+			byMethod[cm.Signature]++
+			if cm.Verified {
+				verified++
+			}
+		}
+	}
+	return signed, verified, byMethod
+}
+
 func (r *CrawlResult) TotalIssues() int  { return len(r.AuthoredIssues) }
 func (r *CrawlResult) TotalStarred() int { return len(r.StarredRepos) }
 func (r *CrawlResult) TotalGists() int   { return len(r.Gists) }
@@ -52,6 +89,35 @@ func (r *CrawlResult) TotalReleases() int {
 	}
 	return n
 }
+
+// TotalDependencies returns the number of dependency manifests across all repos.
+func (r *CrawlResult) TotalDependencies() int {
+	n := 0
+	for _, repo := range r.Repos {
+		n += len(repo.Dependencies)
+	}
+	return n
+}
+
+// TotalTriage returns the number of other people's issues whose triage by
+// the user was recorded across all repos.
+func (r *CrawlResult) TotalTriage() int {
+	n := 0
+	for _, repo := range r.Repos {
+		n += len(repo.Triage)
+	}
+	return n
+}
+
+// TotalDocs returns the number of repo docs, such as READMEs and templates,
+// across all repos.
+func (r *CrawlResult) TotalDocs() int {
+	n := 0
+	for _, repo := range r.Repos {
+		n += len(repo.Docs)
+	}
+	return n
+}
 func (r *CrawlResult) TotalExternalPRs() int { return len(r.ExternalPRs) }
 func (r *CrawlResult) TotalDiscussions() int { return len(r.Discussions) }
 func (r *CrawlResult) TotalProjects() int    { return len(r.Projects) }
@@ -85,6 +151,7 @@ type RepoData struct {
 	Forks          int
 	Topics         []string
 	IsOwner        bool
+	IsMaintainer   bool
 	IsFork         bool
 	Archived       bool
 	License        string
@@ -97,12 +164,32 @@ type RepoData struct {
 	Reviews        []ReviewData
 	ReviewComments []ReviewComment
 	PRComments     []Comment
+	// CommitComments are the user's comments on commits, which only a GH
+	// Archive backfill collects.
+	CommitComments []Comment
 	CodeSamples    []CodeSample
+	Tooling        []CodeSample
+	Dependencies   []DependencyData
+	Docs           []RepoDoc
+	Branches       []string
+	Tags           []string
+	WorkflowRuns   WorkflowRunStats
 	Releases       []ReleaseData
 	WikiPages      []WikiPage
+	Triage         []TriageData
+	ReviewReplies  []ReviewReply
+	// ArchiveOnly marks a repo that was never crawled and only holds
+	// comments from a GH Archive backfill, so it is left out of repo counts.
+	ArchiveOnly bool
 }
 
+// Maintains reports whether the user owns or maintains the repo, which is
+// when its docs, ref names, and CI runs reflect the user's own conventions.
+func (r RepoData) Maintains() bool { return r.IsOwner || r.IsMaintainer }
+
 // CommitData holds a commit's metadata, optional diff patch, and change stats.
+// Signature is the signing method ("gpg", "ssh", "x509") or empty when the
+// commit is unsigned; Verified reports whether GitHub verified it.
 type CommitData struct {
 	SHA          string
 	Message      string
@@ -111,6 +198,8 @@ type CommitData struct {
 	Additions    int
 	Deletions    int
 	FilesChanged int
+	Signature    string
+	Verified     bool
 }
 
 // PullRequestData holds metadata for a pull request.
@@ -130,6 +219,25 @@ type PullRequestData struct {
 	Deletions      int
 	ChangedFiles   int
 	ReviewDecision string
+	Reactions      ReactionCounts
+}
+
+// SponsorshipData describes the user's GitHub Sponsors activity and which
+// of their non-fork repos publish funding links (FUNDING.yml).
+type SponsorshipData struct {
+	Sponsorable     bool
+	SponsorCount    int
+	SponsoringCount int
+	Sponsoring      []string
+	OwnedRepos      int
+	FundedRepos     []FundingData
+}
+
+// FundingData lists the funding platforms a repo links to, such as
+// GITHUB, PATREON, or OPEN_COLLECTIVE.
+type FundingData struct {
+	Repo      string
+	Platforms []string
 }
 
 // ReviewData holds metadata for a submitted PR review.
@@ -152,24 +260,58 @@ type ReviewData struct {
 
 // ReviewComment holds a single PR review comment.
 type ReviewComment struct {
-	Repo     string
-	PRNumber int
-	PRTitle  string
-	PRAuthor string
-	Body     string
-	Path     string
-	DiffHunk string
-	URL      string
-	Date     time.Time
+	Repo      string
+	PRNumber  int
+	PRTitle   string
+	PRAuthor  string
+	Body      string
+	Path      string
+	DiffHunk  string
+	URL       string
+	Date      time.Time
+	Reactions ReactionCounts
+}
+
+// ReviewReply holds the user's reply in an inline review thread together
+// with the comment it answers. OwnPR reports whether the thread is on the
+// user's own pull request, where the parent is usually a reviewer pushing
+// back on their change.
+type ReviewReply struct {
+	Repo         string
+	PRNumber     int
+	PRTitle      string
+	Path         string
+	ParentAuthor string
+	Parent       string
+	Body         string
+	URL          string
+	Date         time.Time
+	OwnPR        bool
 }
 
 // Comment holds an issue or PR conversation comment.
 type Comment struct {
-	Repo   string
-	Author string
-	Body   string
-	URL    string
-	Date   time.Time
+	Repo      string
+	Author    string
+	Body      string
+	URL       string
+	Date      time.Time
+	Reactions ReactionCounts
+}
+
+// ReactionCounts holds the reactions a comment or pull request received.
+type ReactionCounts struct {
+	Total    int
+	PlusOne  int
+	MinusOne int
+	Heart    int
+	Hooray   int
+	Rocket   int
+}
+
+// Positive returns the number of approving reactions (👍, ❤️, 🎉, 🚀).
+func (r ReactionCounts) Positive() int {
+	return r.PlusOne + r.Heart + r.Hooray + r.Rocket
 }
 
 // CodeSample holds a source file's path and content.
@@ -178,7 +320,57 @@ type CodeSample struct {
 	Content string
 }
 
-// StarredRepo holds metadata for a repository the user has starred.
+// RepoDoc holds a documentation file of an owned repository: its README,
+// CONTRIBUTING guide, or an issue/PR template.
+type RepoDoc struct {
+	Path    string
+	Kind    string
+	Content string
+}
+
+// WorkflowRunStats summarizes the outcomes of recent completed GitHub Actions
+// runs. The DefaultBranch fields only count runs on the default branch.
+type WorkflowRunStats struct {
+	Total                 int
+	Success               int
+	Failure               int
+	Cancelled             int
+	DefaultBranchTotal    int
+	DefaultBranchFailures int
+	// FailureStreak is the number of consecutive failed runs on the default
+	// branch counting back from the most recent one.
+	FailureStreak int
+}
+
+// SuccessRate returns the share of runs that succeeded, or 0 with no runs.
+func (s WorkflowRunStats) SuccessRate() float64 {
+	if s.Total == 0 {
+		return 0
+	}
+	return float64(s.Success) / float64(s.Total)
+}
+
+// WeeklyActivity holds the user's code changes in one week, summed across
+// the repos where they were active that week.
+type WeeklyActivity struct {
+	Week      time.Time
+	Additions int
+	Deletions int
+	Commits   int
+	Repos     int
+}
+
+// DependencyData holds the dependencies declared in one manifest file
+// (go.mod, package.json, Cargo.toml, ...).
+type DependencyData struct {
+	Repo         string
+	Path         string
+	Ecosystem    string
+	Dependencies []string
+}
+
+// StarredRepo holds metadata for a repository the user has starred. Watched
+// repos use the same shape.
 type StarredRepo struct {
 	Name        string
 	FullName    string
@@ -191,6 +383,7 @@ type StarredRepo struct {
 // GistData holds metadata for a user's gist.
 type GistData struct {
 	ID          string
+	Owner       string
 	Description string
 	Files       []GistFile
 	Public      bool
@@ -198,10 +391,12 @@ type GistData struct {
 	UpdatedAt   time.Time
 }
 
-// GistFile holds the name and language of a single file within a gist.
+// GistFile holds the name, language, size, and (for small public gists)
+// content of a single file within a gist.
 type GistFile struct {
 	Name     string
 	Language string
+	Size     int
 	Content  string
 }
 
@@ -246,14 +441,67 @@ type DiscussionData struct {
 	Comments  []Comment
 }
 
-// ProjectData holds metadata for a GitHub Projects v2 project.
+// TriageData records how the user handled an issue someone else opened on a
+// repo they own or maintain. Body is the issue's opening text. FirstResponse
+// is the time from opening to the user's first comment and is only
+// meaningful when Responded is set. Labels
+// lists the labels the user applied. ClosedWithComment means the user
+// commented around the time they closed the issue.
+type TriageData struct {
+	Repo              string
+	Number            int
+	Title             string
+	Author            string
+	Body              string
+	OpenedAt          time.Time
+	Open              bool
+	Responded         bool
+	FirstResponse     time.Duration
+	FirstReply        string
+	Labels            []string
+	ClosedByUser      bool
+	ClosedWithComment bool
+}
+
+// ProjectData holds metadata for a GitHub Projects v2 project. Items holds
+// only the most recently added items; ItemCount is the total.
 type ProjectData struct {
 	Title     string
 	Body      string
+	Readme    string
 	URL       string
 	Public    bool
+	Closed    bool
 	CreatedAt time.Time
+	UpdatedAt time.Time
 	ItemCount int
+	Fields    []ProjectField
+	Views     []ProjectView
+	Items     []ProjectItem
+}
+
+// ProjectField is a field of a project, such as "Status" (SINGLE_SELECT) or
+// "Estimate" (NUMBER).
+type ProjectField struct {
+	Name     string
+	DataType string
+}
+
+// ProjectView is a saved view of a project. Layout is BOARD_LAYOUT,
+// TABLE_LAYOUT, or ROADMAP_LAYOUT.
+type ProjectView struct {
+	Name   string
+	Layout string
+}
+
+// ProjectItem is a card on a project. Type is ISSUE, PULL_REQUEST,
+// DRAFT_ISSUE, or REDACTED; Status is the value of the "Status" field, if
+// set.
+type ProjectItem struct {
+	Type      string
+	Status    string
+	Archived  bool
+	UpdatedAt time.Time
 }
 
 // WikiPage holds the title and content of a repository wiki page.
@@ -135,3 +135,44 @@ func TestCrawlResult_Zeros(t *testing.T) {
 		t.Errorf("TotalProjects() = %d, want 0", got)
 	}
 }
+
+func TestReactionCounts_Positive(t *testing.T) {
+	r := ReactionCounts{Total: 7, PlusOne: 2, MinusOne: 3, Heart: 1, Rocket: 1}
+	if got := r.Positive(); got != 4 {
+		t.Errorf("Positive() = %d, want 4", got)
+	}
+}
+
+func TestCrawlResult_TotalDependencies(t *testing.T) {
+	r := &CrawlResult{
+		Repos: []RepoData{
+			{Dependencies: make([]DependencyData, 2)},
+			{},
+		},
+	}
+	if got := r.TotalDependencies(); got != 2 {
+		t.Errorf("TotalDependencies() = %d, want 2", got)
+	}
+}
+
+func TestCrawlResult_SigningStats(t *testing.T) {
+	r := &CrawlResult{
+		Repos: []RepoData{
+			{Commits: []CommitData{
+				{Signature: "gpg", Verified: true},
+				{Signature: "ssh", Verified: true},
+				{},
+			}},
+			{Commits: []CommitData{
+				{Signature: "gpg"},
+			}},
+		},
+	}
+	signed, verified, byMethod := r.SigningStats()
+	if signed != 3 || verified != 2 {
+		t.Errorf("SigningStats() = signed %d, verified %d, want 3, 2", signed, verified)
+	}
+	if byMethod["gpg"] != 2 || byMethod["ssh"] != 1 {
+		t.Errorf("SigningStats() byMethod = %v, want gpg:2 ssh:1", byMethod)
+	}
+}
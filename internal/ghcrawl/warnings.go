@@ -0,0 +1,93 @@
+package ghcrawl
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// CrawlWarning records data the crawl failed to collect. Source names what
+// was being fetched, such as "list PRs"; Repo is empty for user-level
+// sources like starred repos or events.
+type CrawlWarning struct {
+	Source string
+	Repo   string
+	Class  string
+	Error  string
+}
+
+// warningLog collects warnings from concurrent fetchers.
+type warningLog struct {
+	mu    sync.Mutex
+	items []CrawlWarning
+}
+
+func (l *warningLog) add(msg string, err error, args []any) {
+	w := CrawlWarning{
+		Source: strings.TrimPrefix(msg, "could not "),
+		Class:  classifyError(err),
+		Error:  err.Error(),
+	}
+	for i := 0; i+1 < len(args); i += 2 {
+		if key, ok := args[i].(string); ok && key == "repo" {
+			w.Repo = fmt.Sprint(args[i+1])
+		}
+	}
+	l.mu.Lock()
+	l.items = append(l.items, w)
+	l.mu.Unlock()
+}
+
+// drain returns the collected warnings and resets the log.
+func (l *warningLog) drain() []CrawlWarning {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	items := l.items
+	l.items = nil
+	return items
+}
+
+// WriteWarnings prints warnings grouped by source and error class, with the
+// affected repos, so users can judge how complete the crawl was.
+func WriteWarnings(w io.Writer, warnings []CrawlWarning) {
+	type group struct {
+		source, class string
+		count         int
+		repos         []string
+	}
+	var groups []*group
+	index := make(map[string]*group)
+	for _, cw := range warnings {
+		key := cw.Source + "\x00" + cw.Class
+		g := index[key]
+		if g == nil {
+			g = &group{source: cw.Source, class: cw.Class}
+			index[key] = g
+			groups = append(groups, g)
+		}
+		g.count++
+		if cw.Repo != "" && !slices.Contains(g.repos, cw.Repo) {
+			g.repos = append(g.repos, cw.Repo)
+		}
+	}
+	slices.SortStableFunc(groups, func(a, b *group) int { return b.count - a.count })
+
+	width := len("SOURCE")
+	for _, g := range groups {
+		width = max(width, len(g.source))
+	}
+	fmt.Fprintf(w, "  %-*s  %-9s  %5s  %s\n", width, "SOURCE", "CLASS", "COUNT", "REPOS")
+	for _, g := range groups {
+		repos := "-"
+		if len(g.repos) > 0 {
+			shown := g.repos[:min(3, len(g.repos))]
+			repos = strings.Join(shown, ", ")
+			if more := len(g.repos) - len(shown); more > 0 {
+				repos += fmt.Sprintf(" (+%d more)", more)
+			}
+		}
+		fmt.Fprintf(w, "  %-*s  %-9s  %5d  %s\n", width, g.source, g.class, g.count, repos)
+	}
+}
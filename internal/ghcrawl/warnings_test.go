@@ -0,0 +1,67 @@
+package ghcrawl
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/google/go-github/v68/github"
+)
+
+func TestNoteFetchErrorRecordsDataLoss(t *testing.T) {
+	status := func(code int) error {
+		return &github.ErrorResponse{Response: &http.Response{StatusCode: code}}
+	}
+	c := &Crawler{}
+	c.noteFetchError("could not list PRs", status(http.StatusBadGateway), "repo", "user/a")
+	c.noteFetchError("could not list tags", status(http.StatusNotFound), "repo", "user/b")
+	c.noteFetchError("could not list releases", status(http.StatusConflict), "repo", "user/c")
+	c.noteFetchError("could not list workflow runs", status(http.StatusForbidden), "repo", "user/d")
+	c.warnFetch("could not fetch events", fmt.Errorf("boom"))
+
+	got := c.warnings.drain()
+	if len(got) != 3 {
+		t.Fatalf("got %d warnings, want 3: %+v", len(got), got)
+	}
+	if got[0].Source != "list PRs" || got[0].Repo != "user/a" || got[0].Class != errClassTransient {
+		t.Errorf("warning[0] = %+v", got[0])
+	}
+	if got[1].Class != errClassForbidden {
+		t.Errorf("warning[1] = %+v", got[1])
+	}
+	if got[2].Source != "fetch events" || got[2].Repo != "" {
+		t.Errorf("warning[2] = %+v", got[2])
+	}
+	if len(c.warnings.drain()) != 0 {
+		t.Error("drain should reset the log")
+	}
+}
+
+func TestWriteWarnings(t *testing.T) {
+	var buf bytes.Buffer
+	WriteWarnings(&buf, []CrawlWarning{
+		{Source: "fetch events", Class: errClassOther},
+		{Source: "list PRs", Repo: "u/a", Class: errClassTransient},
+		{Source: "list PRs", Repo: "u/b", Class: errClassTransient},
+		{Source: "list PRs", Repo: "u/b", Class: errClassTransient},
+		{Source: "list PRs", Repo: "u/c", Class: errClassTransient},
+		{Source: "list PRs", Repo: "u/d", Class: errClassTransient},
+	})
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("got %d lines, want header and 2 groups:\n%s", len(lines), buf.String())
+	}
+	if !strings.HasPrefix(strings.TrimSpace(lines[0]), "SOURCE") {
+		t.Errorf("missing header: %q", lines[0])
+	}
+	for _, want := range []string{"list PRs", "transient", "5", "u/a, u/b, u/c (+1 more)"} {
+		if !strings.Contains(lines[1], want) {
+			t.Errorf("expected %q in %q", want, lines[1])
+		}
+	}
+	if !strings.Contains(lines[2], "fetch events") || !strings.HasSuffix(lines[2], "-") {
+		t.Errorf("user-level warning line = %q", lines[2])
+	}
+}
@@ -0,0 +1,86 @@
+package ghcrawl
+
+import (
+	"math/rand/v2"
+	"time"
+)
+
+// Window returns a copy of r with only the activity dated within
+// [from, to). Data that is a snapshot of today, such as code samples,
+// docs, dependencies, stars, and organizations, has no date to filter by
+// and is dropped, so no window is credited with the present, as are repos
+// created after the window. The profile is kept since it identifies the
+// user.
+func (r *CrawlResult) Window(from, to time.Time) *CrawlResult {
+	in := func(t time.Time) bool { return !t.Before(from) && t.Before(to) }
+	w := &CrawlResult{
+		User:           r.User,
+		IssueComments:  filterDated(r.IssueComments, in, func(c Comment) time.Time { return c.Date }),
+		Gists:          filterDated(r.Gists, in, func(g GistData) time.Time { return g.CreatedAt }),
+		AuthoredIssues: filterDated(r.AuthoredIssues, in, func(i IssueData) time.Time { return i.CreatedAt }),
+		ExternalPRs:    filterDated(r.ExternalPRs, in, func(p PullRequestData) time.Time { return p.Date }),
+		Events:         filterDated(r.Events, in, func(e EventData) time.Time { return e.CreatedAt }),
+		Discussions:    filterDated(r.Discussions, in, func(d DiscussionData) time.Time { return d.CreatedAt }),
+		Projects:       filterDated(r.Projects, in, func(p ProjectData) time.Time { return p.CreatedAt }),
+		Timeline:       filterDated(r.Timeline, in, func(a WeeklyActivity) time.Time { return a.Week }),
+	}
+	for _, repo := range r.Repos {
+		if !repo.CreatedAt.IsZero() && !repo.CreatedAt.Before(to) {
+			continue
+		}
+		rw := repo
+		rw.Commits = filterDated(repo.Commits, in, func(c CommitData) time.Time { return c.Date })
+		rw.PRs = filterDated(repo.PRs, in, func(p PullRequestData) time.Time { return p.Date })
+		rw.Reviews = filterDated(repo.Reviews, in, func(rv ReviewData) time.Time { return rv.SubmittedAt })
+		rw.ReviewComments = filterDated(repo.ReviewComments, in, func(c ReviewComment) time.Time { return c.Date })
+		rw.PRComments = filterDated(repo.PRComments, in, func(c Comment) time.Time { return c.Date })
+		rw.CommitComments = filterDated(repo.CommitComments, in, func(c Comment) time.Time { return c.Date })
+		rw.ReviewReplies = filterDated(repo.ReviewReplies, in, func(rr ReviewReply) time.Time { return rr.Date })
+		rw.Releases = filterDated(repo.Releases, in, func(rl ReleaseData) time.Time { return rl.CreatedAt })
+		rw.Triage = filterDated(repo.Triage, in, func(t TriageData) time.Time { return t.OpenedAt })
+		rw.CodeSamples, rw.Tooling, rw.Dependencies, rw.Docs, rw.WikiPages = nil, nil, nil, nil, nil
+		rw.Branches, rw.Tags = nil, nil
+		rw.WorkflowRuns = WorkflowRunStats{}
+		w.Repos = append(w.Repos, rw)
+	}
+	return w
+}
+
+func filterDated[T any](items []T, keep func(time.Time) bool, date func(T) time.Time) []T {
+	var out []T
+	for _, item := range items {
+		if keep(date(item)) {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+// SampleByAge returns a copy of r in which each dated commit, pull request,
+// review, and comment is kept with probability weight(now - date), so a
+// weight that falls with age samples recent activity more heavily. Undated
+// items and snapshots of the present are always kept. The sample is
+// seeded, so the same crawl and weights always keep the same items.
+func (r *CrawlResult) SampleByAge(now time.Time, weight func(age time.Duration) float64) *CrawlResult {
+	rng := rand.New(rand.NewPCG(1, 2))
+	keep := func(t time.Time) bool {
+		return t.IsZero() || rng.Float64() < weight(now.Sub(t))
+	}
+	s := *r
+	s.IssueComments = filterDated(r.IssueComments, keep, func(c Comment) time.Time { return c.Date })
+	s.AuthoredIssues = filterDated(r.AuthoredIssues, keep, func(i IssueData) time.Time { return i.CreatedAt })
+	s.ExternalPRs = filterDated(r.ExternalPRs, keep, func(p PullRequestData) time.Time { return p.Date })
+	s.Discussions = filterDated(r.Discussions, keep, func(d DiscussionData) time.Time { return d.CreatedAt })
+	s.Repos = make([]RepoData, len(r.Repos))
+	for i, repo := range r.Repos {
+		repo.Commits = filterDated(repo.Commits, keep, func(c CommitData) time.Time { return c.Date })
+		repo.PRs = filterDated(repo.PRs, keep, func(p PullRequestData) time.Time { return p.Date })
+		repo.Reviews = filterDated(repo.Reviews, keep, func(rv ReviewData) time.Time { return rv.SubmittedAt })
+		repo.ReviewComments = filterDated(repo.ReviewComments, keep, func(c ReviewComment) time.Time { return c.Date })
+		repo.PRComments = filterDated(repo.PRComments, keep, func(c Comment) time.Time { return c.Date })
+		repo.CommitComments = filterDated(repo.CommitComments, keep, func(c Comment) time.Time { return c.Date })
+		repo.ReviewReplies = filterDated(repo.ReviewReplies, keep, func(rr ReviewReply) time.Time { return rr.Date })
+		s.Repos[i] = repo
+	}
+	return &s
+}
@@ -0,0 +1,101 @@
+package ghcrawl
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWindow(t *testing.T) {
+	day := func(y int) time.Time { return time.Date(y, 6, 1, 0, 0, 0, 0, time.UTC) }
+	r := &CrawlResult{
+		User: UserProfile{Login: "alice"},
+		Repos: []RepoData{
+			{
+				FullName:       "alice/old",
+				CreatedAt:      day(2018),
+				Commits:        []CommitData{{SHA: "a", Date: day(2019)}, {SHA: "b", Date: day(2023)}},
+				ReviewComments: []ReviewComment{{Body: "nit", Date: day(2020)}},
+				CodeSamples:    []CodeSample{{Path: "main.go"}},
+				WorkflowRuns:   WorkflowRunStats{Total: 3},
+			},
+			{FullName: "alice/new", CreatedAt: day(2023)},
+		},
+		IssueComments: []Comment{{Body: "old", Date: day(2019)}, {Body: "new", Date: day(2024)}},
+		StarredRepos:  []StarredRepo{{FullName: "x/y"}},
+		Orgs:          []string{"acme"},
+	}
+
+	w := r.Window(time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	if w.User.Login != "alice" {
+		t.Error("profile should be kept")
+	}
+	if len(w.Repos) != 1 || w.Repos[0].FullName != "alice/old" {
+		t.Fatalf("repos = %+v, want only the repo that existed in the window", w.Repos)
+	}
+	repo := w.Repos[0]
+	if len(repo.Commits) != 1 || repo.Commits[0].SHA != "a" || len(repo.ReviewComments) != 1 {
+		t.Errorf("repo activity = %+v", repo)
+	}
+	if repo.CodeSamples != nil || repo.WorkflowRuns.Total != 0 {
+		t.Error("snapshot data should be dropped")
+	}
+	if len(w.IssueComments) != 1 || w.IssueComments[0].Body != "old" {
+		t.Errorf("issue comments = %+v", w.IssueComments)
+	}
+	if w.StarredRepos != nil || w.Orgs != nil {
+		t.Error("undated profile data should be dropped")
+	}
+	if len(r.Repos[0].Commits) != 2 {
+		t.Error("Window modified the original")
+	}
+}
+
+func TestSampleByAge(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	var commits []CommitData
+	for i := range 200 {
+		// Half the commits are a month old, half ten years old.
+		date := now.AddDate(0, -1, 0)
+		if i%2 == 1 {
+			date = now.AddDate(-10, 0, 0)
+		}
+		commits = append(commits, CommitData{SHA: string(rune('a' + i%26)), Date: date})
+	}
+	r := &CrawlResult{
+		Repos:         []RepoData{{FullName: "alice/tool", Commits: commits, CodeSamples: []CodeSample{{Path: "main.go"}}}},
+		IssueComments: []Comment{{Body: "undated"}},
+	}
+	weight := func(age time.Duration) float64 {
+		if age > 365*24*time.Hour {
+			return 0.1
+		}
+		return 1
+	}
+
+	s := r.SampleByAge(now, weight)
+
+	var recent, old int
+	for _, c := range s.Repos[0].Commits {
+		if c.Date.Year() == 2024 {
+			recent++
+		} else {
+			old++
+		}
+	}
+	if recent != 100 {
+		t.Errorf("kept %d of 100 recent commits, want all", recent)
+	}
+	if old == 0 || old > 30 {
+		t.Errorf("kept %d of 100 decade-old commits, want about 10", old)
+	}
+	if len(s.Repos[0].CodeSamples) != 1 || len(s.IssueComments) != 1 {
+		t.Error("undated data was dropped")
+	}
+	if len(r.Repos[0].Commits) != 200 {
+		t.Error("SampleByAge modified the original crawl")
+	}
+	if again := r.SampleByAge(now, weight); len(again.Repos[0].Commits) != len(s.Repos[0].Commits) {
+		t.Error("sampling the same crawl twice kept different commits")
+	}
+}
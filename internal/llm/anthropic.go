@@ -2,6 +2,7 @@ package llm
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 
 	"github.com/anthropics/anthropic-sdk-go"
@@ -16,7 +17,8 @@ type anthropicProvider struct {
 
 func newAnthropic(apiKey, model string, useVertexAI bool, vertexRegion, vertexProjectID string) (*anthropicProvider, error) {
 	clientOpts := []option.RequestOption{
-		option.WithMaxRetries(5),
+		// Retries are left to retryProvider, so -llm-retries 0 means none.
+		option.WithMaxRetries(0),
 	}
 	if useVertexAI {
 		vopt, err := newVertexAuthOption(context.Background(), vertexRegion, vertexProjectID)
@@ -47,7 +49,7 @@ func (p *anthropicProvider) Complete(ctx context.Context, system, prompt string,
 	if opts != nil && opts.MaxTokens > 0 {
 		maxTokens = int64(opts.MaxTokens)
 	}
-	msg, err := p.client.Messages.New(ctx, anthropic.MessageNewParams{
+	params := anthropic.MessageNewParams{
 		Model:     anthropic.Model(p.model),
 		MaxTokens: maxTokens,
 		System: []anthropic.TextBlockParam{
@@ -56,16 +58,60 @@ func (p *anthropicProvider) Complete(ctx context.Context, system, prompt string,
 		Messages: []anthropic.MessageParam{
 			anthropic.NewUserMessage(anthropic.NewTextBlock(prompt)),
 		},
-	})
+	}
+	structured := opts != nil && len(opts.JSONSchema) > 0
+	if structured {
+		// Anthropic has no JSON mode; forcing a tool whose input schema is
+		// the requested one yields the object as the tool input.
+		schema, err := toolInputSchema(opts.JSONSchema)
+		if err != nil {
+			return "", err
+		}
+		params.Tools = []anthropic.ToolUnionParam{{OfTool: &anthropic.ToolParam{
+			Name:        jsonSchemaName,
+			Description: anthropic.String("Respond with the requested JSON object."),
+			InputSchema: schema,
+		}}}
+		params.ToolChoice = anthropic.ToolChoiceParamOfTool(jsonSchemaName)
+	}
+	msg, err := p.client.Messages.New(ctx, params)
 	if err != nil {
 		return "", fmt.Errorf("anthropic completion: %w", err)
 	}
 	// Return the first text block only; multi-block responses are not expected
-	// from single-turn completions.
+	// from single-turn completions. A structured reply is the forced tool's
+	// input instead.
 	for _, block := range msg.Content {
-		if block.Type == "text" {
+		switch {
+		case structured && block.Type == "tool_use":
+			return string(block.Input), nil
+		case block.Type == "text":
 			return block.Text, nil
 		}
 	}
 	return "", fmt.Errorf("anthropic returned no text content")
 }
+
+// toolInputSchema converts an object JSON Schema to a tool input schema.
+func toolInputSchema(raw json.RawMessage) (anthropic.ToolInputSchemaParam, error) {
+	var schema map[string]any
+	if err := json.Unmarshal(raw, &schema); err != nil {
+		return anthropic.ToolInputSchemaParam{}, fmt.Errorf("decoding JSON schema: %w", err)
+	}
+	var param anthropic.ToolInputSchemaParam
+	param.Properties = schema["properties"]
+	if required, ok := schema["required"].([]any); ok {
+		for _, r := range required {
+			if s, ok := r.(string); ok {
+				param.Required = append(param.Required, s)
+			}
+		}
+	}
+	delete(schema, "type")
+	delete(schema, "properties")
+	delete(schema, "required")
+	if len(schema) > 0 {
+		param.ExtraFields = schema
+	}
+	return param, nil
+}
@@ -0,0 +1,55 @@
+package llm
+
+import "strings"
+
+// contextWindows lists known context windows in tokens by model name
+// prefix. More specific prefixes come first.
+var contextWindows = []struct {
+	prefix string
+	tokens int
+}{
+	{"gpt-4.1", 1_047_576},
+	{"gpt-5", 400_000},
+	{"gpt-4o", 128_000},
+	{"gpt-4-turbo", 128_000},
+	{"gpt-4", 8_192},
+	{"gpt-3.5-turbo", 16_385},
+	{"o1", 200_000},
+	{"o3", 200_000},
+	{"o4", 200_000},
+	{"claude", 200_000},
+	{"deepseek-chat", 128_000},
+	{"deepseek-reasoner", 128_000},
+	{"llama3.1", 128_000},
+	{"llama3.2", 128_000},
+	{"llama3.3", 128_000},
+	{"llama3", 8_192},
+	{"qwen2.5", 32_768},
+	{"mistral", 32_768},
+}
+
+// ContextWindow returns the context window of model in tokens. Unknown
+// models get a conservative default for their provider. For Ollama this is
+// what the model supports; the server may be configured with less.
+// OpenRouter models are named vendor/model, and looked up by the model.
+func ContextWindow(provider ProviderName, model string) int {
+	m := strings.ToLower(model)
+	if provider == ProviderOpenRouter {
+		if _, name, ok := strings.Cut(m, "/"); ok {
+			m = name
+		}
+	}
+	for _, w := range contextWindows {
+		if strings.HasPrefix(m, w.prefix) {
+			return w.tokens
+		}
+	}
+	switch provider {
+	case ProviderAnthropic:
+		return 200_000
+	case ProviderOpenAI, ProviderOpenRouter, ProviderDeepSeek:
+		return 128_000
+	default:
+		return 8_192
+	}
+}
@@ -0,0 +1,32 @@
+package llm
+
+import "testing"
+
+func TestContextWindow(t *testing.T) {
+	tests := []struct {
+		provider ProviderName
+		model    string
+		want     int
+	}{
+		{ProviderOpenAI, "gpt-4o", 128_000},
+		{ProviderOpenAI, "gpt-4o-mini", 128_000},
+		{ProviderOpenAI, "gpt-4.1-mini", 1_047_576},
+		{ProviderOpenAI, "gpt-4", 8_192},
+		{ProviderOpenAI, "some-future-model", 128_000},
+		{ProviderAnthropic, "claude-opus-4-6", 200_000},
+		{ProviderAnthropic, "", 200_000},
+		{ProviderOllama, "llama3", 8_192},
+		{ProviderOllama, "llama3.1:70b", 128_000},
+		{ProviderOllama, "phi3", 8_192},
+		{ProviderOpenRouter, "anthropic/claude-sonnet-4.5", 200_000},
+		{ProviderOpenRouter, "openai/gpt-4.1", 1_047_576},
+		{ProviderOpenRouter, "mistralai/some-new-model", 128_000},
+		{ProviderOpenRouter, "deepseek/deepseek-chat", 128_000},
+		{ProviderDeepSeek, "deepseek-reasoner", 128_000},
+	}
+	for _, tt := range tests {
+		if got := ContextWindow(tt.provider, tt.model); got != tt.want {
+			t.Errorf("ContextWindow(%s, %q) = %d, want %d", tt.provider, tt.model, got, tt.want)
+		}
+	}
+}
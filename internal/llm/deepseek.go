@@ -0,0 +1,62 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+
+	openai "github.com/sashabaranov/go-openai"
+)
+
+// deepSeekURL is DeepSeek's OpenAI-compatible API.
+const deepSeekURL = "https://api.deepseek.com/v1"
+
+type deepSeekProvider struct {
+	client *openai.Client
+	model  string
+}
+
+func newDeepSeek(apiKey, model string) *deepSeekProvider {
+	cfg := openai.DefaultConfig(apiKey)
+	cfg.BaseURL = deepSeekURL
+	return &deepSeekProvider{
+		client: openai.NewClientWithConfig(cfg),
+		model:  model,
+	}
+}
+
+// deepSeekSchemaPrompt is appended to the system prompt of a structured
+// request. DeepSeek's JSON mode takes no schema, and wants the prompt to
+// ask for JSON.
+const deepSeekSchemaPrompt = "\n\nReply with a single JSON object matching this JSON Schema:\n%s"
+
+func (p *deepSeekProvider) Complete(ctx context.Context, system, prompt string, opts *CompleteOptions) (string, error) {
+	temp := float32(0.3)
+	if opts != nil && opts.Temperature != nil {
+		temp = *opts.Temperature
+	}
+	req := openai.ChatCompletionRequest{
+		Model:       p.model,
+		Temperature: temp,
+	}
+	if opts != nil {
+		req.MaxTokens = opts.MaxTokens
+		if len(opts.JSONSchema) > 0 {
+			system += fmt.Sprintf(deepSeekSchemaPrompt, opts.JSONSchema)
+			req.ResponseFormat = &openai.ChatCompletionResponseFormat{Type: openai.ChatCompletionResponseFormatTypeJSONObject}
+		}
+	}
+	req.Messages = []openai.ChatCompletionMessage{
+		{Role: openai.ChatMessageRoleSystem, Content: system},
+		{Role: openai.ChatMessageRoleUser, Content: prompt},
+	}
+	resp, err := p.client.CreateChatCompletion(ctx, req)
+	if err != nil {
+		return "", fmt.Errorf("deepseek completion: %w", err)
+	}
+	if len(resp.Choices) == 0 {
+		return "", fmt.Errorf("deepseek returned no choices")
+	}
+	// deepseek-reasoner returns its chain of thought separately, so the
+	// content is only the answer.
+	return resp.Choices[0].Message.Content, nil
+}
@@ -0,0 +1,17 @@
+package llm
+
+import (
+	"context"
+	"errors"
+)
+
+// Embedder is implemented by providers that can turn text into embedding
+// vectors. Anthropic has no embeddings API, so only OpenAI and Ollama do.
+type Embedder interface {
+	// Embed returns one vector per input text, in input order.
+	Embed(ctx context.Context, texts []string) ([][]float32, error)
+}
+
+// ErrNoEmbedModel is returned by Embed when the provider was created
+// without an embedding model.
+var ErrNoEmbedModel = errors.New("no embedding model configured")
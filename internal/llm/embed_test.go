@@ -0,0 +1,48 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestOllamaEmbed(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/embed" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		var req ollamaEmbedRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatal(err)
+		}
+		if req.Model != "nomic-embed-text" || len(req.Input) != 2 {
+			t.Errorf("unexpected request %+v", req)
+		}
+		_ = json.NewEncoder(w).Encode(ollamaEmbedResponse{Embeddings: [][]float32{{1, 0}, {0, 1}}})
+	}))
+	defer srv.Close()
+
+	p := newOllama(srv.URL, "llama3", "nomic-embed-text")
+	got, err := p.Embed(context.Background(), []string{"a", "b"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[1][1] != 1 {
+		t.Errorf("Embed() = %v", got)
+	}
+}
+
+func TestEmbedWithoutModel(t *testing.T) {
+	providers := map[string]Embedder{
+		"openai": newOpenAI("key", "gpt-4o", ""),
+		"ollama": newOllama("http://localhost:11434", "llama3", ""),
+	}
+	for name, p := range providers {
+		if _, err := p.Embed(context.Background(), []string{"a"}); !errors.Is(err, ErrNoEmbedModel) {
+			t.Errorf("%s: Embed() error = %v, want ErrNoEmbedModel", name, err)
+		}
+	}
+}
@@ -5,21 +5,22 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
 	"time"
 )
 
 type ollamaProvider struct {
-	host   string
-	model  string
-	client *http.Client
+	host       string
+	model      string
+	embedModel string
+	client     *http.Client
 }
 
-func newOllama(host, model string) *ollamaProvider {
+func newOllama(host, model, embedModel string) *ollamaProvider {
 	return &ollamaProvider{
-		host:  host,
-		model: model,
+		host:       host,
+		model:      model,
+		embedModel: embedModel,
 		client: &http.Client{
 			Timeout: 5 * time.Minute,
 		},
@@ -31,6 +32,7 @@ type ollamaRequest struct {
 	System  string         `json:"system,omitempty"`
 	Prompt  string         `json:"prompt"`
 	Stream  bool           `json:"stream"`
+	Format  string         `json:"format,omitempty"`
 	Options *ollamaOptions `json:"options,omitempty"`
 }
 
@@ -51,6 +53,9 @@ func (p *ollamaProvider) Complete(ctx context.Context, system, prompt string, op
 		Prompt: prompt,
 		Stream: false,
 	}
+	if opts != nil && len(opts.JSONSchema) > 0 {
+		req.Format = "json"
+	}
 	if opts != nil {
 		var o ollamaOptions
 		if opts.Temperature != nil {
@@ -83,8 +88,7 @@ func (p *ollamaProvider) Complete(ctx context.Context, system, prompt string, op
 	}()
 
 	if resp.StatusCode != http.StatusOK {
-		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
-		return "", fmt.Errorf("ollama returned status %d: %s", resp.StatusCode, string(respBody))
+		return "", newStatusError("ollama", resp)
 	}
 
 	var result ollamaResponse
@@ -93,3 +97,49 @@ func (p *ollamaProvider) Complete(ctx context.Context, system, prompt string, op
 	}
 	return result.Response, nil
 }
+
+type ollamaEmbedRequest struct {
+	Model string   `json:"model"`
+	Input []string `json:"input"`
+}
+
+type ollamaEmbedResponse struct {
+	Embeddings [][]float32 `json:"embeddings"`
+}
+
+func (p *ollamaProvider) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	if p.embedModel == "" {
+		return nil, ErrNoEmbedModel
+	}
+	body, err := json.Marshal(ollamaEmbedRequest{Model: p.embedModel, Input: texts})
+	if err != nil {
+		return nil, fmt.Errorf("marshaling ollama embed request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, p.host+"/api/embed", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("creating ollama embed request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("ollama embed request: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newStatusError("ollama", resp)
+	}
+
+	var result ollamaEmbedResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("decoding ollama embed response: %w", err)
+	}
+	if len(result.Embeddings) != len(texts) {
+		return nil, fmt.Errorf("ollama returned %d embeddings for %d inputs", len(result.Embeddings), len(texts))
+	}
+	return result.Embeddings, nil
+}
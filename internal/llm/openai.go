@@ -8,14 +8,16 @@ import (
 )
 
 type openaiProvider struct {
-	client *openai.Client
-	model  string
+	client     *openai.Client
+	model      string
+	embedModel string
 }
 
-func newOpenAI(apiKey, model string) *openaiProvider {
+func newOpenAI(apiKey, model, embedModel string) *openaiProvider {
 	return &openaiProvider{
-		client: openai.NewClient(apiKey),
-		model:  model,
+		client:     openai.NewClient(apiKey),
+		model:      model,
+		embedModel: embedModel,
 	}
 }
 
@@ -24,14 +26,26 @@ func (p *openaiProvider) Complete(ctx context.Context, system, prompt string, op
 	if opts != nil && opts.Temperature != nil {
 		temp = *opts.Temperature
 	}
-	resp, err := p.client.CreateChatCompletion(ctx, openai.ChatCompletionRequest{
+	req := openai.ChatCompletionRequest{
 		Model: p.model,
 		Messages: []openai.ChatCompletionMessage{
 			{Role: openai.ChatMessageRoleSystem, Content: system},
 			{Role: openai.ChatMessageRoleUser, Content: prompt},
 		},
 		Temperature: temp,
-	})
+	}
+	if opts != nil && len(opts.JSONSchema) > 0 {
+		// Strict mode rejects objects keyed by field name, such as the
+		// synthesis evidence map, so the schema is a guide, not a contract.
+		req.ResponseFormat = &openai.ChatCompletionResponseFormat{
+			Type: openai.ChatCompletionResponseFormatTypeJSONSchema,
+			JSONSchema: &openai.ChatCompletionResponseFormatJSONSchema{
+				Name:   jsonSchemaName,
+				Schema: opts.JSONSchema,
+			},
+		}
+	}
+	resp, err := p.client.CreateChatCompletion(ctx, req)
 	if err != nil {
 		return "", fmt.Errorf("openai completion: %w", err)
 	}
@@ -40,3 +54,27 @@ func (p *openaiProvider) Complete(ctx context.Context, system, prompt string, op
 	}
 	return resp.Choices[0].Message.Content, nil
 }
+
+func (p *openaiProvider) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	if p.embedModel == "" {
+		return nil, ErrNoEmbedModel
+	}
+	resp, err := p.client.CreateEmbeddings(ctx, openai.EmbeddingRequestStrings{
+		Input: texts,
+		Model: openai.EmbeddingModel(p.embedModel),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("openai embeddings: %w", err)
+	}
+	if len(resp.Data) != len(texts) {
+		return nil, fmt.Errorf("openai returned %d embeddings for %d inputs", len(resp.Data), len(texts))
+	}
+	vectors := make([][]float32, len(texts))
+	for _, d := range resp.Data {
+		if d.Index < 0 || d.Index >= len(texts) {
+			return nil, fmt.Errorf("openai returned embedding index %d out of range", d.Index)
+		}
+		vectors[d.Index] = d.Embedding
+	}
+	return vectors, nil
+}
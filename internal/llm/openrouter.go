@@ -0,0 +1,161 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"time"
+)
+
+// openRouterURL is OpenRouter's OpenAI-compatible API.
+const openRouterURL = "https://openrouter.ai/api/v1"
+
+// OpenRouterRouting is how OpenRouter picks the model and the upstream
+// provider that serve a request.
+type OpenRouterRouting struct {
+	// Order lists upstream providers, such as "anthropic" or "together",
+	// to try first, in order.
+	Order []string
+	// OnlyOrder keeps requests on the Order providers instead of falling
+	// back to any other provider of the model.
+	OnlyOrder bool
+	// FallbackModels are tried in order when the model is down, rate
+	// limited, or refuses the request.
+	FallbackModels []string
+}
+
+type openRouterProvider struct {
+	baseURL string
+	apiKey  string
+	model   string
+	routing OpenRouterRouting
+	client  *http.Client
+}
+
+func newOpenRouter(apiKey, model string, routing OpenRouterRouting) *openRouterProvider {
+	return &openRouterProvider{
+		baseURL: openRouterURL,
+		apiKey:  apiKey,
+		model:   model,
+		routing: routing,
+		client: &http.Client{
+			Timeout: 5 * time.Minute,
+		},
+	}
+}
+
+type openRouterRequest struct {
+	Model          string                     `json:"model"`
+	Models         []string                   `json:"models,omitempty"`
+	Messages       []openRouterMessage        `json:"messages"`
+	Temperature    float32                    `json:"temperature"`
+	MaxTokens      int                        `json:"max_tokens,omitempty"`
+	ResponseFormat *openRouterResponseFormat  `json:"response_format,omitempty"`
+	Provider       *openRouterProviderOptions `json:"provider,omitempty"`
+}
+
+type openRouterMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type openRouterResponseFormat struct {
+	Type       string `json:"type"`
+	JSONSchema struct {
+		Name   string          `json:"name"`
+		Schema json.RawMessage `json:"schema"`
+	} `json:"json_schema"`
+}
+
+// openRouterProviderOptions are OpenRouter's provider preferences.
+type openRouterProviderOptions struct {
+	Order          []string `json:"order,omitempty"`
+	AllowFallbacks *bool    `json:"allow_fallbacks,omitempty"`
+}
+
+type openRouterResponse struct {
+	Model   string `json:"model"`
+	Choices []struct {
+		Message openRouterMessage `json:"message"`
+	} `json:"choices"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+func (p *openRouterProvider) Complete(ctx context.Context, system, prompt string, opts *CompleteOptions) (string, error) {
+	req := openRouterRequest{
+		Model: p.model,
+		// OpenRouter tries model first and then each of models.
+		Models: p.routing.FallbackModels,
+		Messages: []openRouterMessage{
+			{Role: "system", Content: system},
+			{Role: "user", Content: prompt},
+		},
+		Temperature: 0.3,
+	}
+	if opts != nil {
+		if opts.Temperature != nil {
+			req.Temperature = *opts.Temperature
+		}
+		req.MaxTokens = opts.MaxTokens
+		if len(opts.JSONSchema) > 0 {
+			// As with OpenAI, the schema is not strict; models without
+			// structured outputs still see it as a hint.
+			req.ResponseFormat = &openRouterResponseFormat{Type: "json_schema"}
+			req.ResponseFormat.JSONSchema.Name = jsonSchemaName
+			req.ResponseFormat.JSONSchema.Schema = opts.JSONSchema
+		}
+	}
+	if len(p.routing.Order) > 0 || p.routing.OnlyOrder {
+		req.Provider = &openRouterProviderOptions{Order: p.routing.Order}
+		if p.routing.OnlyOrder {
+			req.Provider.AllowFallbacks = new(bool)
+		}
+	}
+	body, err := json.Marshal(req)
+	if err != nil {
+		return "", fmt.Errorf("marshaling openrouter request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, p.baseURL+"/chat/completions", bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("creating openrouter request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Authorization", "Bearer "+p.apiKey)
+	// OpenRouter attributes requests to the app named by these headers.
+	httpReq.Header.Set("HTTP-Referer", "https://github.com/drpaneas/devlica")
+	httpReq.Header.Set("X-Title", "devlica")
+
+	resp, err := p.client.Do(httpReq)
+	if err != nil {
+		return "", fmt.Errorf("openrouter request: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", newStatusError("openrouter", resp)
+	}
+
+	var result openRouterResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("decoding openrouter response: %w", err)
+	}
+	if result.Error != nil {
+		return "", fmt.Errorf("openrouter error: %s", result.Error.Message)
+	}
+	if len(result.Choices) == 0 {
+		return "", fmt.Errorf("openrouter returned no choices")
+	}
+	if result.Model != p.model && slices.Contains(p.routing.FallbackModels, result.Model) {
+		slog.Info("openrouter fell back to another model", "model", p.model, "fallback", result.Model)
+	}
+	return result.Choices[0].Message.Content, nil
+}
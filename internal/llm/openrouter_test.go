@@ -0,0 +1,111 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestOpenRouterRouting(t *testing.T) {
+	tests := []struct {
+		name    string
+		routing OpenRouterRouting
+		// wantModels and wantProvider are the request's "models" and
+		// "provider" fields, nil when it should leave them out.
+		wantModels   []any
+		wantProvider map[string]any
+	}{
+		{name: "no routing"},
+		{
+			name:       "fallback models",
+			routing:    OpenRouterRouting{FallbackModels: []string{"openai/gpt-4o", "meta-llama/llama-3.3-70b-instruct"}},
+			wantModels: []any{"openai/gpt-4o", "meta-llama/llama-3.3-70b-instruct"},
+		},
+		{
+			name:         "provider order",
+			routing:      OpenRouterRouting{Order: []string{"anthropic", "amazon-bedrock"}},
+			wantProvider: map[string]any{"order": []any{"anthropic", "amazon-bedrock"}},
+		},
+		{
+			name:         "only the ordered providers",
+			routing:      OpenRouterRouting{Order: []string{"anthropic"}, OnlyOrder: true},
+			wantProvider: map[string]any{"order": []any{"anthropic"}, "allow_fallbacks": false},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/chat/completions" || r.Header.Get("Authorization") != "Bearer key" {
+					t.Errorf("request to %s with %q, want /chat/completions with the key", r.URL.Path, r.Header.Get("Authorization"))
+				}
+				var body map[string]any
+				if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+					t.Fatal(err)
+				}
+				if body["model"] != "anthropic/claude-sonnet-4.5" {
+					t.Errorf("model = %v, want the configured model", body["model"])
+				}
+				models, _ := body["models"].([]any)
+				if !slices.Equal(models, tt.wantModels) {
+					t.Errorf("models = %v, want %v", body["models"], tt.wantModels)
+				}
+				if got, want := mustJSON(t, body["provider"]), mustJSON(t, tt.wantProvider); string(got) != string(want) {
+					t.Errorf("provider = %s, want %s", got, want)
+				}
+				_ = json.NewEncoder(w).Encode(map[string]any{
+					"model":   "anthropic/claude-sonnet-4.5",
+					"choices": []any{map[string]any{"message": map[string]any{"role": "assistant", "content": "hello"}}},
+				})
+			}))
+			defer srv.Close()
+
+			p := newOpenRouter("key", "anthropic/claude-sonnet-4.5", tt.routing)
+			p.baseURL = srv.URL
+			got, err := p.Complete(context.Background(), "system", "prompt", nil)
+			if err != nil || got != "hello" {
+				t.Errorf("Complete() = %q, %v, want the reply", got, err)
+			}
+		})
+	}
+}
+
+func TestOpenRouterErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		reply   string
+		wantErr string
+	}{
+		{"status", http.StatusPaymentRequired, `{"error":{"message":"Insufficient credits"}}`, "status 402: {\"error\":{\"message\":\"Insufficient credits\"}}"},
+		{"error body", http.StatusOK, `{"error":{"message":"No endpoints found"}}`, "openrouter error: No endpoints found"},
+		{"no choices", http.StatusOK, `{"choices":[]}`, "no choices"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.reply))
+			}))
+			defer srv.Close()
+
+			p := newOpenRouter("key", "openai/gpt-4o", OpenRouterRouting{})
+			p.baseURL = srv.URL
+			if _, err := p.Complete(context.Background(), "system", "prompt", nil); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Complete() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func mustJSON(t *testing.T, v any) []byte {
+	t.Helper()
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
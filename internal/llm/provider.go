@@ -2,6 +2,7 @@ package llm
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 )
 
@@ -9,9 +10,11 @@ import (
 type ProviderName string
 
 const (
-	ProviderOpenAI    ProviderName = "openai"
-	ProviderAnthropic ProviderName = "anthropic"
-	ProviderOllama    ProviderName = "ollama"
+	ProviderOpenAI     ProviderName = "openai"
+	ProviderAnthropic  ProviderName = "anthropic"
+	ProviderOllama     ProviderName = "ollama"
+	ProviderOpenRouter ProviderName = "openrouter"
+	ProviderDeepSeek   ProviderName = "deepseek"
 )
 
 // CompleteOptions controls per-request LLM parameters.
@@ -19,17 +22,30 @@ const (
 type CompleteOptions struct {
 	Temperature *float32
 	MaxTokens   int
+	// JSONSchema, when set, asks for a reply that is a single JSON object
+	// matching this JSON Schema, through the provider's structured-output
+	// mode. The object is still returned as text.
+	JSONSchema json.RawMessage
 }
 
+// jsonSchemaName names the schema or tool a structured reply is requested
+// through.
+const jsonSchemaName = "respond"
+
 // ProviderConfig holds the configuration needed to construct a Provider.
 type ProviderConfig struct {
 	Name            ProviderName
 	APIKey          string
 	Model           string
+	EmbedModel      string
 	OllamaHost      string
 	UseVertexAI     bool
 	VertexRegion    string
 	VertexProjectID string
+	// OpenRouter routes requests when Name is ProviderOpenRouter.
+	OpenRouter OpenRouterRouting
+	// Retry retries failed calls; the zero value never does.
+	Retry RetryPolicy
 }
 
 // Provider abstracts an LLM completion backend.
@@ -39,13 +55,25 @@ type Provider interface {
 
 // NewProvider creates a Provider for the given configuration.
 func NewProvider(cfg ProviderConfig) (Provider, error) {
+	p, err := newProvider(cfg)
+	if err != nil || cfg.Retry.MaxRetries <= 0 {
+		return p, err
+	}
+	return WithRetry(p, cfg.Retry), nil
+}
+
+func newProvider(cfg ProviderConfig) (Provider, error) {
 	switch cfg.Name {
 	case ProviderOpenAI:
-		return newOpenAI(cfg.APIKey, cfg.Model), nil
+		return newOpenAI(cfg.APIKey, cfg.Model, cfg.EmbedModel), nil
 	case ProviderAnthropic:
 		return newAnthropic(cfg.APIKey, cfg.Model, cfg.UseVertexAI, cfg.VertexRegion, cfg.VertexProjectID)
 	case ProviderOllama:
-		return newOllama(cfg.OllamaHost, cfg.Model), nil
+		return newOllama(cfg.OllamaHost, cfg.Model, cfg.EmbedModel), nil
+	case ProviderOpenRouter:
+		return newOpenRouter(cfg.APIKey, cfg.Model, cfg.OpenRouter), nil
+	case ProviderDeepSeek:
+		return newDeepSeek(cfg.APIKey, cfg.Model), nil
 	default:
 		return nil, fmt.Errorf("unknown LLM provider: %s", cfg.Name)
 	}
@@ -16,6 +16,8 @@ func TestNewProvider_ValidNames(t *testing.T) {
 		{ProviderOpenAI},
 		{ProviderAnthropic},
 		{ProviderOllama},
+		{ProviderOpenRouter},
+		{ProviderDeepSeek},
 	}
 	for _, tt := range tests {
 		t.Run(string(tt.name), func(t *testing.T) {
@@ -0,0 +1,196 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/anthropics/anthropic-sdk-go"
+	openai "github.com/sashabaranov/go-openai"
+)
+
+// Default waits of a RetryPolicy that leaves them unset.
+const (
+	DefaultRetryBaseDelay = 2 * time.Second
+	DefaultRetryMaxDelay  = time.Minute
+)
+
+// RetryPolicy is how a provider retries calls that failed for a reason
+// that may pass: a rate limit, an overloaded or failing server, or a
+// network error. Anything else, such as a bad key, fails at once.
+type RetryPolicy struct {
+	// MaxRetries is how many times a failed call is retried; zero never
+	// retries.
+	MaxRetries int
+	// BaseDelay is the wait before the first retry, doubled before each
+	// next one up to MaxDelay. Each wait is jittered to between half and
+	// all of it, and a server's Retry-After is waited out in full, up to
+	// MaxDelay.
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+}
+
+// StatusError is an error status returned by a provider's HTTP API.
+type StatusError struct {
+	Provider   string
+	StatusCode int
+	Body       string
+	// RetryAfter is how long the server asked to wait, or zero.
+	RetryAfter time.Duration
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%s returned status %d: %s", e.Provider, e.StatusCode, e.Body)
+}
+
+// newStatusError reads the error status and start of the body of resp.
+func newStatusError(provider string, resp *http.Response) *StatusError {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	return &StatusError{
+		Provider:   provider,
+		StatusCode: resp.StatusCode,
+		Body:       string(body),
+		RetryAfter: retryAfter(resp.Header),
+	}
+}
+
+// retryAfter parses a Retry-After header given in seconds, or returns zero.
+func retryAfter(h http.Header) time.Duration {
+	if secs, err := strconv.Atoi(h.Get("Retry-After")); err == nil && secs > 0 {
+		return time.Duration(secs) * time.Second
+	}
+	return 0
+}
+
+// retryableStatus reports whether an HTTP status may pass on its own:
+// timeouts, rate limits, and server errors, including Anthropic's 529
+// overloaded.
+func retryableStatus(code int) bool {
+	return code == http.StatusRequestTimeout || code == http.StatusTooManyRequests || code >= 500
+}
+
+// retryable reports whether err may pass on a retry and how long the
+// server asked to wait first, if it did.
+func retryable(err error) (bool, time.Duration) {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false, 0
+	}
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return retryableStatus(statusErr.StatusCode), statusErr.RetryAfter
+	}
+	var apiErr *openai.APIError
+	if errors.As(err, &apiErr) {
+		return retryableStatus(apiErr.HTTPStatusCode), 0
+	}
+	var reqErr *openai.RequestError
+	if errors.As(err, &reqErr) {
+		return retryableStatus(reqErr.HTTPStatusCode), 0
+	}
+	var anthropicErr *anthropic.Error
+	if errors.As(err, &anthropicErr) {
+		var wait time.Duration
+		if anthropicErr.Response != nil {
+			wait = retryAfter(anthropicErr.Response.Header)
+		}
+		return retryableStatus(anthropicErr.StatusCode), wait
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr), 0
+}
+
+// WithRetry returns p retrying its failed calls as policy says. The result
+// embeds too when p does.
+func WithRetry(p Provider, policy RetryPolicy) Provider {
+	if policy.BaseDelay <= 0 {
+		policy.BaseDelay = DefaultRetryBaseDelay
+	}
+	if policy.MaxDelay <= 0 {
+		policy.MaxDelay = DefaultRetryMaxDelay
+	}
+	r := &retryProvider{provider: p, policy: policy, sleep: sleep}
+	if e, ok := p.(Embedder); ok {
+		return &retryEmbedder{retryProvider: r, embedder: e}
+	}
+	return r
+}
+
+type retryProvider struct {
+	provider Provider
+	policy   RetryPolicy
+	// sleep waits d or until ctx is done.
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+func (r *retryProvider) Complete(ctx context.Context, system, prompt string, opts *CompleteOptions) (string, error) {
+	var reply string
+	err := r.retry(ctx, "completion", func() error {
+		var err error
+		reply, err = r.provider.Complete(ctx, system, prompt, opts)
+		return err
+	})
+	return reply, err
+}
+
+type retryEmbedder struct {
+	*retryProvider
+	embedder Embedder
+}
+
+func (r *retryEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	var vectors [][]float32
+	err := r.retry(ctx, "embedding", func() error {
+		var err error
+		vectors, err = r.embedder.Embed(ctx, texts)
+		return err
+	})
+	return vectors, err
+}
+
+// retry calls call until it succeeds, fails for good, or runs out of
+// retries, and returns its last error.
+func (r *retryProvider) retry(ctx context.Context, what string, call func() error) error {
+	for attempt := 0; ; attempt++ {
+		err := call()
+		if err == nil || attempt == r.policy.MaxRetries {
+			return err
+		}
+		ok, wait := retryable(err)
+		if !ok {
+			return err
+		}
+		wait = min(max(wait, r.backoff(attempt)), r.policy.MaxDelay)
+		slog.Warn("LLM call failed, retrying", "call", what, "retry", attempt+1, "max_retries", r.policy.MaxRetries, "wait", wait.Round(time.Millisecond), "error", err)
+		if err := r.sleep(ctx, wait); err != nil {
+			return err
+		}
+	}
+}
+
+// backoff is the jittered wait before retry attempt+1.
+func (r *retryProvider) backoff(attempt int) time.Duration {
+	d := r.policy.BaseDelay
+	for i := 0; i < attempt && d < r.policy.MaxDelay; i++ {
+		d *= 2
+	}
+	d = min(d, r.policy.MaxDelay)
+	return d/2 + rand.N(d/2+1)
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
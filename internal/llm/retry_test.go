@@ -0,0 +1,162 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/anthropics/anthropic-sdk-go"
+	openai "github.com/sashabaranov/go-openai"
+)
+
+// flakyProvider fails with each of errs in turn, then replies "ok".
+type flakyProvider struct {
+	errs  []error
+	calls int
+}
+
+func (p *flakyProvider) Complete(context.Context, string, string, *CompleteOptions) (string, error) {
+	p.calls++
+	if p.calls <= len(p.errs) {
+		return "", p.errs[p.calls-1]
+	}
+	return "ok", nil
+}
+
+// withFakeSleep returns p retrying under policy, recording its waits
+// instead of sleeping.
+func withFakeSleep(p Provider, policy RetryPolicy, waits *[]time.Duration) Provider {
+	r := WithRetry(p, policy)
+	rp, ok := r.(*retryProvider)
+	if !ok {
+		rp = r.(*retryEmbedder).retryProvider
+	}
+	rp.sleep = func(_ context.Context, d time.Duration) error {
+		*waits = append(*waits, d)
+		return nil
+	}
+	return r
+}
+
+// anthropicError is the SDK's error for a reply with status code.
+func anthropicError(code int) *anthropic.Error {
+	resp := &http.Response{StatusCode: code, Header: http.Header{}}
+	return &anthropic.Error{StatusCode: code, Request: httptest.NewRequest(http.MethodPost, "/v1/messages", nil), Response: resp}
+}
+
+func TestRetry(t *testing.T) {
+	rateLimited := &StatusError{Provider: "ollama", StatusCode: http.StatusTooManyRequests}
+	tests := []struct {
+		name      string
+		errs      []error
+		wantCalls int
+		wantErr   bool
+	}{
+		{"success", nil, 1, false},
+		{"rate limit", []error{rateLimited}, 2, false},
+		{"ollama overloaded", []error{&StatusError{Provider: "ollama", StatusCode: http.StatusServiceUnavailable}}, 2, false},
+		{"openai rate limit", []error{fmt.Errorf("openai completion: %w", &openai.APIError{HTTPStatusCode: http.StatusTooManyRequests})}, 2, false},
+		{"openai server error", []error{&openai.RequestError{HTTPStatusCode: http.StatusBadGateway}}, 2, false},
+		{"anthropic overloaded", []error{fmt.Errorf("anthropic completion: %w", anthropicError(529))}, 2, false},
+		{"network error", []error{&net.OpError{Op: "dial", Err: errors.New("connection reset")}}, 2, false},
+		{"bad request", []error{&StatusError{Provider: "openrouter", StatusCode: http.StatusBadRequest}}, 1, true},
+		{"unauthorized", []error{&openai.APIError{HTTPStatusCode: http.StatusUnauthorized}}, 1, true},
+		{"canceled", []error{fmt.Errorf("ollama request: %w", context.Canceled)}, 1, true},
+		{"other error", []error{errors.New("openai returned no choices")}, 1, true},
+		{"out of retries", []error{rateLimited, rateLimited, rateLimited, rateLimited}, 4, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &flakyProvider{errs: tt.errs}
+			var waits []time.Duration
+			got, err := withFakeSleep(p, RetryPolicy{MaxRetries: 3}, &waits).Complete(context.Background(), "system", "prompt", nil)
+			if (err != nil) != tt.wantErr || (err == nil && got != "ok") {
+				t.Errorf("Complete() = %q, %v, want error %v", got, err, tt.wantErr)
+			}
+			if p.calls != tt.wantCalls || len(waits) != tt.wantCalls-1 {
+				t.Errorf("%d calls after %d waits, want %d calls", p.calls, len(waits), tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestRetryWaits(t *testing.T) {
+	policy := RetryPolicy{MaxRetries: 4, BaseDelay: time.Second, MaxDelay: 5 * time.Second}
+	errs := []error{
+		&StatusError{StatusCode: http.StatusTooManyRequests},
+		&StatusError{StatusCode: http.StatusTooManyRequests},
+		&StatusError{StatusCode: http.StatusTooManyRequests},
+		&StatusError{StatusCode: http.StatusTooManyRequests, RetryAfter: 4 * time.Second},
+	}
+	var waits []time.Duration
+	if _, err := withFakeSleep(&flakyProvider{errs: errs}, policy, &waits).Complete(context.Background(), "", "", nil); err != nil {
+		t.Fatal(err)
+	}
+	// Each backoff doubles up to the cap and is jittered to at least half
+	// of it; Retry-After is waited out in full.
+	bounds := [][2]time.Duration{{500 * time.Millisecond, time.Second}, {time.Second, 2 * time.Second}, {2 * time.Second, 4 * time.Second}, {4 * time.Second, 5 * time.Second}}
+	for i, w := range waits {
+		if w < bounds[i][0] || w > bounds[i][1] {
+			t.Errorf("wait %d = %v, want between %v and %v", i+1, w, bounds[i][0], bounds[i][1])
+		}
+	}
+}
+
+func TestRetryStopsWhenCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	p := &flakyProvider{errs: []error{&StatusError{StatusCode: http.StatusTooManyRequests}}}
+	if _, err := WithRetry(p, RetryPolicy{MaxRetries: 3}).Complete(ctx, "", "", nil); !errors.Is(err, context.Canceled) || p.calls != 1 {
+		t.Errorf("Complete() error = %v after %d calls, want the cancellation after one", err, p.calls)
+	}
+}
+
+func TestWithRetryKeepsEmbedder(t *testing.T) {
+	if _, ok := WithRetry(&flakyProvider{}, RetryPolicy{MaxRetries: 1}).(Embedder); ok {
+		t.Error("a provider that cannot embed should not gain Embed")
+	}
+
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls++; calls == 1 {
+			w.Header().Set("Retry-After", "1")
+			http.Error(w, "server busy, please try again.", http.StatusServiceUnavailable)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(ollamaEmbedResponse{Embeddings: [][]float32{{1, 0}}})
+	}))
+	defer srv.Close()
+
+	var waits []time.Duration
+	embedder, ok := withFakeSleep(newOllama(srv.URL, "llama3", "nomic-embed-text"), RetryPolicy{MaxRetries: 1, BaseDelay: time.Millisecond}, &waits).(Embedder)
+	if !ok {
+		t.Fatal("retrying an embedding provider should keep Embed")
+	}
+	if _, err := embedder.Embed(context.Background(), []string{"text"}); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 2 || len(waits) != 1 || waits[0] != time.Second {
+		t.Errorf("%d calls after waits %v, want a retry after the server's Retry-After", calls, waits)
+	}
+}
+
+func TestNewProviderRetry(t *testing.T) {
+	cfg := ProviderConfig{Name: ProviderOllama, Model: "llama3", OllamaHost: "http://localhost:11434"}
+	if p, _ := NewProvider(cfg); p == nil {
+		t.Fatal("NewProvider() returned nil")
+	} else if _, ok := p.(*ollamaProvider); !ok {
+		t.Errorf("NewProvider() = %T, want the bare provider without retries", p)
+	}
+	cfg.Retry = RetryPolicy{MaxRetries: 2}
+	if p, _ := NewProvider(cfg); p == nil {
+		t.Fatal("NewProvider() returned nil")
+	} else if _, ok := p.(*retryEmbedder); !ok {
+		t.Errorf("NewProvider() = %T, want it wrapped in retries", p)
+	}
+}
@@ -0,0 +1,135 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/anthropics/anthropic-sdk-go"
+	"github.com/anthropics/anthropic-sdk-go/option"
+	openai "github.com/sashabaranov/go-openai"
+)
+
+var testSchema = json.RawMessage(`{"type":"object","properties":{"score":{"type":"number"}},"required":["score"],"additionalProperties":false}`)
+
+func TestStructuredOutput(t *testing.T) {
+	tests := []struct {
+		name string
+		// serve checks the request body and writes the provider's reply.
+		serve func(t *testing.T, body map[string]any, w http.ResponseWriter)
+		// provider returns a provider that talks to url.
+		provider func(url string) Provider
+	}{
+		{
+			name: "openai response_format",
+			serve: func(t *testing.T, body map[string]any, w http.ResponseWriter) {
+				format, _ := body["response_format"].(map[string]any)
+				schema, _ := format["json_schema"].(map[string]any)
+				if format["type"] != "json_schema" || schema["name"] != jsonSchemaName || schema["schema"] == nil {
+					t.Errorf("response_format = %v, want the JSON schema", body["response_format"])
+				}
+				_ = json.NewEncoder(w).Encode(map[string]any{
+					"choices": []any{map[string]any{"message": map[string]any{"role": "assistant", "content": `{"score": 7}`}}},
+				})
+			},
+			provider: func(url string) Provider {
+				cfg := openai.DefaultConfig("key")
+				cfg.BaseURL = url
+				return &openaiProvider{client: openai.NewClientWithConfig(cfg), model: "gpt-4o"}
+			},
+		},
+		{
+			name: "anthropic forced tool",
+			serve: func(t *testing.T, body map[string]any, w http.ResponseWriter) {
+				tools, _ := body["tools"].([]any)
+				choice, _ := body["tool_choice"].(map[string]any)
+				if len(tools) != 1 || choice["type"] != "tool" || choice["name"] != jsonSchemaName {
+					t.Errorf("tools = %v, tool_choice = %v, want one forced tool", body["tools"], body["tool_choice"])
+				} else if schema := tools[0].(map[string]any)["input_schema"].(map[string]any); schema["additionalProperties"] != false || schema["properties"] == nil {
+					t.Errorf("input_schema = %v, want the JSON schema", schema)
+				}
+				_ = json.NewEncoder(w).Encode(map[string]any{
+					"id": "msg", "type": "message", "role": "assistant", "model": "claude", "stop_reason": "tool_use",
+					"content": []any{map[string]any{"type": "tool_use", "id": "t", "name": jsonSchemaName, "input": map[string]any{"score": 7}}},
+				})
+			},
+			provider: func(url string) Provider {
+				return &anthropicProvider{client: anthropic.NewClient(option.WithBaseURL(url), option.WithAPIKey("key"), option.WithMaxRetries(0)), model: "claude"}
+			},
+		},
+		{
+			name: "ollama format",
+			serve: func(t *testing.T, body map[string]any, w http.ResponseWriter) {
+				if body["format"] != "json" {
+					t.Errorf("format = %v, want json", body["format"])
+				}
+				_ = json.NewEncoder(w).Encode(ollamaResponse{Response: `{"score": 7}`, Done: true})
+			},
+			provider: func(url string) Provider { return newOllama(url, "llama3", "") },
+		},
+		{
+			name: "openrouter response_format",
+			serve: func(t *testing.T, body map[string]any, w http.ResponseWriter) {
+				format, _ := body["response_format"].(map[string]any)
+				schema, _ := format["json_schema"].(map[string]any)
+				if format["type"] != "json_schema" || schema["name"] != jsonSchemaName || schema["schema"] == nil {
+					t.Errorf("response_format = %v, want the JSON schema", body["response_format"])
+				}
+				_ = json.NewEncoder(w).Encode(map[string]any{
+					"choices": []any{map[string]any{"message": map[string]any{"role": "assistant", "content": `{"score": 7}`}}},
+				})
+			},
+			provider: func(url string) Provider {
+				p := newOpenRouter("key", "openai/gpt-4o", OpenRouterRouting{})
+				p.baseURL = url
+				return p
+			},
+		},
+		{
+			name: "deepseek json_object with the schema in the prompt",
+			serve: func(t *testing.T, body map[string]any, w http.ResponseWriter) {
+				format, _ := body["response_format"].(map[string]any)
+				if format["type"] != "json_object" || format["json_schema"] != nil {
+					t.Errorf("response_format = %v, want json_object", body["response_format"])
+				}
+				messages, _ := body["messages"].([]any)
+				if system, _ := messages[0].(map[string]any)["content"].(string); !strings.HasSuffix(system, string(testSchema)) {
+					t.Errorf("system prompt = %q, want it to end with the schema", system)
+				}
+				_ = json.NewEncoder(w).Encode(map[string]any{
+					"choices": []any{map[string]any{"message": map[string]any{"role": "assistant", "content": `{"score": 7}`, "reasoning_content": "Seven."}}},
+				})
+			},
+			provider: func(url string) Provider {
+				cfg := openai.DefaultConfig("key")
+				cfg.BaseURL = url
+				return &deepSeekProvider{client: openai.NewClientWithConfig(cfg), model: "deepseek-reasoner"}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var body map[string]any
+				if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+					t.Fatal(err)
+				}
+				w.Header().Set("Content-Type", "application/json")
+				tt.serve(t, body, w)
+			}))
+			defer srv.Close()
+
+			got, err := tt.provider(srv.URL).Complete(context.Background(), "system", "prompt", &CompleteOptions{JSONSchema: testSchema})
+			if err != nil {
+				t.Fatal(err)
+			}
+			var reply struct{ Score float64 }
+			if err := json.Unmarshal([]byte(got), &reply); err != nil || reply.Score != 7 {
+				t.Errorf("Complete() = %q, want the JSON object", got)
+			}
+		})
+	}
+}
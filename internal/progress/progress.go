@@ -0,0 +1,80 @@
+// Package progress reports the steps of an analysis or benchmark run as
+// they start and finish, with the LLM tokens each step used, so a CLI or a
+// server can show more than log lines.
+package progress
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+// bytesPerToken matches the analyzer's conservative estimate for mixed
+// prose and code; providers do not report usage back.
+const bytesPerToken = 3
+
+// Phase is the point in a step an Event reports.
+type Phase int
+
+const (
+	Started Phase = iota
+	Finished
+	Failed
+)
+
+func (p Phase) String() string {
+	switch p {
+	case Started:
+		return "started"
+	case Finished:
+		return "finished"
+	case Failed:
+		return "failed"
+	default:
+		return "unknown"
+	}
+}
+
+// Event reports one step of a run. Tokens and Elapsed are only set when the
+// step has finished or failed; Err only when it failed.
+type Event struct {
+	Step    string
+	Phase   Phase
+	Tokens  int
+	Elapsed time.Duration
+	Err     error
+}
+
+// Func receives events. Steps run concurrently, so it may be called from
+// several goroutines at once and must not block for long.
+type Func func(Event)
+
+type counterKey struct{}
+
+// Step runs f as the named step, reporting it to fn before and after. LLM
+// calls made with the context f receives are counted toward the step's
+// tokens by Record. A nil fn only runs f.
+func (fn Func) Step(ctx context.Context, name string, f func(context.Context) error) error {
+	if fn == nil {
+		return f(ctx)
+	}
+	var tokens atomic.Int64
+	start := time.Now()
+	fn(Event{Step: name, Phase: Started})
+	err := f(context.WithValue(ctx, counterKey{}, &tokens))
+	ev := Event{Step: name, Phase: Finished, Tokens: int(tokens.Load()), Elapsed: time.Since(start)}
+	if err != nil {
+		ev.Phase = Failed
+		ev.Err = err
+	}
+	fn(ev)
+	return err
+}
+
+// Record adds the estimated tokens of one LLM call to the step ctx belongs
+// to, if any.
+func Record(ctx context.Context, sent, received string) {
+	if tokens, ok := ctx.Value(counterKey{}).(*atomic.Int64); ok {
+		tokens.Add(int64((len(sent) + len(received)) / bytesPerToken))
+	}
+}
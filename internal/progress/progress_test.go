@@ -0,0 +1,54 @@
+package progress
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestStep(t *testing.T) {
+	var events []Event
+	fn := Func(func(e Event) { events = append(events, e) })
+
+	err := fn.Step(context.Background(), "review-style", func(ctx context.Context) error {
+		Record(ctx, strings.Repeat("x", 300), strings.Repeat("y", 30))
+		Record(ctx, strings.Repeat("x", 30), "")
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 2 || events[0].Phase != Started || events[1].Phase != Finished {
+		t.Fatalf("events = %+v, want started then finished", events)
+	}
+	if events[1].Step != "review-style" || events[1].Tokens != 120 {
+		t.Errorf("finished event = %+v, want 120 tokens for review-style", events[1])
+	}
+}
+
+func TestStepFailed(t *testing.T) {
+	var last Event
+	fn := Func(func(e Event) { last = e })
+	boom := errors.New("boom")
+
+	if err := fn.Step(context.Background(), "synthesis", func(context.Context) error { return boom }); err != boom {
+		t.Fatalf("err = %v, want the step's error", err)
+	}
+	if last.Phase != Failed || last.Err != boom {
+		t.Errorf("last event = %+v, want a failure carrying the error", last)
+	}
+}
+
+func TestNilFuncRunsStep(t *testing.T) {
+	var fn Func
+	ran := false
+	_ = fn.Step(context.Background(), "x", func(ctx context.Context) error {
+		Record(ctx, "sent", "received")
+		ran = true
+		return nil
+	})
+	if !ran {
+		t.Fatal("step did not run")
+	}
+}
@@ -0,0 +1,119 @@
+// Package prompts lets users override the compiled-in LLM prompts with
+// their own Go templates, so prompt experiments need no fork.
+package prompts
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/template"
+)
+
+// Ext is the file extension of prompt override templates.
+const Ext = ".tmpl"
+
+// Var is one named value substituted into a prompt. Templates refer to it as
+// {{.Name}}; compiled-in prompts receive the values in order as fmt
+// arguments.
+type Var struct {
+	Name  string
+	Value any
+}
+
+// Arg names a prompt value.
+func Arg(name string, value any) Var {
+	return Var{Name: name, Value: value}
+}
+
+// Set renders prompts, preferring user templates over compiled-in defaults.
+// A nil *Set always renders the defaults.
+type Set struct {
+	templates map[string]*template.Template
+}
+
+// Load parses every <name>.tmpl file in dir. Each name must be one of known,
+// so a misspelled file name fails loudly instead of being ignored.
+func Load(dir string, known []string) (*Set, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*"+Ext))
+	if err != nil {
+		return nil, fmt.Errorf("listing prompt templates: %w", err)
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no %s files in prompts dir %s", Ext, dir)
+	}
+	s := &Set{templates: make(map[string]*template.Template, len(paths))}
+	for _, p := range paths {
+		name := strings.TrimSuffix(filepath.Base(p), Ext)
+		if !slices.Contains(known, name) {
+			return nil, fmt.Errorf("unknown prompt template %s: must be one of %s", filepath.Base(p), strings.Join(known, ", "))
+		}
+		text, err := os.ReadFile(p)
+		if err != nil {
+			return nil, fmt.Errorf("reading prompt template: %w", err)
+		}
+		tmpl, err := template.New(name).Option("missingkey=error").Parse(string(text))
+		if err != nil {
+			return nil, fmt.Errorf("parsing prompt template %s: %w", filepath.Base(p), err)
+		}
+		s.templates[name] = tmpl
+	}
+	return s, nil
+}
+
+// Names returns the names of the overridden prompts, sorted.
+func (s *Set) Names() []string {
+	if s == nil {
+		return nil
+	}
+	names := make([]string, 0, len(s.templates))
+	for name := range s.templates {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// Hash returns a SHA-256 of the override templates, so results can be
+// tied to the prompts that produced them, or "" for a nil Set, which uses
+// only the compiled-in prompts.
+func (s *Set) Hash() string {
+	if s == nil {
+		return ""
+	}
+	h := sha256.New()
+	for _, name := range s.Names() {
+		fmt.Fprintf(h, "%s\x00%s\x00", name, s.templates[name].Root.String())
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Render returns the prompt called name. With an override it executes the
+// template with vars as fields; otherwise it formats def with the var
+// values in order.
+func (s *Set) Render(name, def string, vars ...Var) (string, error) {
+	if s != nil {
+		if tmpl, ok := s.templates[name]; ok {
+			data := make(map[string]any, len(vars))
+			for _, v := range vars {
+				data[v.Name] = v.Value
+			}
+			var b strings.Builder
+			if err := tmpl.Execute(&b, data); err != nil {
+				return "", fmt.Errorf("rendering prompt template %s: %w", name, err)
+			}
+			return b.String(), nil
+		}
+	}
+	if len(vars) == 0 {
+		return def, nil
+	}
+	args := make([]any, len(vars))
+	for i, v := range vars {
+		args[i] = v.Value
+	}
+	return fmt.Sprintf(def, args...), nil
+}
@@ -0,0 +1,111 @@
+package prompts
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRenderDefault(t *testing.T) {
+	var s *Set
+	got, err := s.Render("greet", "Hello %s, chunk %d", Var{"Username", "alice"}, Var{"Chunk", 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "Hello alice, chunk 2" {
+		t.Errorf("Render() = %q", got)
+	}
+	if got, _ := s.Render("system", "100% literal"); got != "100% literal" {
+		t.Errorf("prompts without vars must be returned verbatim, got %q", got)
+	}
+}
+
+func TestLoadAndRenderOverride(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "greet.tmpl"), []byte("Hi {{.Username}} ({{.Chunk}})"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	s, err := Load(dir, []string{"greet", "system"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := s.Render("greet", "Hello %s", Var{"Username", "alice"}, Var{"Chunk", 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "Hi alice (2)" {
+		t.Errorf("Render() = %q", got)
+	}
+	if got, _ := s.Render("system", "default"); got != "default" {
+		t.Errorf("prompts without an override keep the default, got %q", got)
+	}
+	if names := s.Names(); len(names) != 1 || names[0] != "greet" {
+		t.Errorf("Names() = %v", names)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  string
+	}{
+		{"empty dir", nil, "no .tmpl files"},
+		{"unknown name", map[string]string{"gret.tmpl": "x"}, "unknown prompt template gret.tmpl"},
+		{"bad syntax", map[string]string{"greet.tmpl": "{{.Username"}, "parsing prompt template"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for name, content := range tt.files {
+				if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			_, err := Load(dir, []string{"greet"})
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Load() error = %v, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderMissingVar(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "greet.tmpl"), []byte("{{.Nope}}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	s, err := Load(dir, []string{"greet"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Render("greet", "%s", Var{"Username", "alice"}); err == nil {
+		t.Error("expected an error for an unknown variable")
+	}
+}
+
+func TestHash(t *testing.T) {
+	var none *Set
+	if none.Hash() != "" {
+		t.Error("a nil Set should hash to \"\"")
+	}
+	load := func(text string) *Set {
+		dir := t.TempDir()
+		if err := os.WriteFile(filepath.Join(dir, "greet.tmpl"), []byte(text), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		s, err := Load(dir, []string{"greet"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return s
+	}
+	a, b, c := load("Hi {{.Username}}"), load("Hi {{.Username}}"), load("Hello {{.Username}}")
+	if a.Hash() == "" || a.Hash() != b.Hash() {
+		t.Error("identical overrides should hash the same")
+	}
+	if a.Hash() == c.Hash() {
+		t.Error("different overrides should hash differently")
+	}
+}
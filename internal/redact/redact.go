@@ -0,0 +1,142 @@
+// Package redact removes personally identifying information from crawled
+// data and generated personas, so a persona can be shared without exposing
+// who it was built from.
+package redact
+
+import (
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/drpaneas/devlica/internal/ghcrawl"
+)
+
+var emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+
+// term is a known identifying value and the placeholder that replaces it.
+type term struct {
+	value       string
+	pattern     *regexp.Regexp
+	placeholder string
+}
+
+// Redactor replaces email addresses and the identifying values of one
+// GitHub profile. The login is kept because output is named after it.
+type Redactor struct {
+	terms []term
+}
+
+// ForProfile returns a Redactor for the profile's name, company, location,
+// email, blog, and Twitter handle. Values shorter than three characters are
+// ignored, since replacing them would mangle unrelated words. Like the
+// login, values that are GitHub names are kept, since replacing them would
+// break repo paths and URLs: a name that is just the login, a company given
+// as an organization (@acme), and a blog on github.com.
+func ForProfile(p ghcrawl.UserProfile) *Redactor {
+	r := &Redactor{}
+	if !strings.EqualFold(strings.TrimSpace(p.Name), p.Login) {
+		r.add(p.Name, "[name]")
+	}
+	if company := strings.TrimSpace(p.Company); !strings.HasPrefix(company, "@") {
+		r.add(company, "[company]")
+	}
+	r.add(p.Location, "[location]")
+	r.add(p.Email, "[email]")
+	if blog := strings.TrimPrefix(strings.TrimPrefix(strings.TrimSpace(p.Blog), "https://"), "http://"); !isGitHubURL(blog) {
+		r.add(p.Blog, "[website]")
+		r.add(blog, "[website]")
+	}
+	r.add(p.TwitterUsername, "[twitter]")
+	// Longer values first, so a name is not partly replaced by a shorter
+	// value it contains.
+	slices.SortStableFunc(r.terms, func(a, b term) int {
+		return len(b.value) - len(a.value)
+	})
+	return r
+}
+
+// isGitHubURL reports whether u, without its scheme, is a page on
+// github.com, such as the user's own profile.
+func isGitHubURL(u string) bool {
+	host, _, _ := strings.Cut(strings.ToLower(u), "/")
+	return host == "github.com" || host == "www.github.com"
+}
+
+func (r *Redactor) add(value, placeholder string) {
+	value = strings.TrimSpace(value)
+	if len(value) < 3 {
+		return
+	}
+	for _, t := range r.terms {
+		if strings.EqualFold(t.value, value) {
+			return
+		}
+	}
+	// Word boundaries only apply where the value starts or ends with a
+	// word character.
+	expr := regexp.QuoteMeta(value)
+	if isWordByte(value[0]) {
+		expr = `\b` + expr
+	}
+	if isWordByte(value[len(value)-1]) {
+		expr += `\b`
+	}
+	r.terms = append(r.terms, term{value: value, pattern: regexp.MustCompile(`(?i)` + expr), placeholder: placeholder})
+}
+
+func isWordByte(b byte) bool {
+	return b == '_' || '0' <= b && b <= '9' || 'a' <= b && b <= 'z' || 'A' <= b && b <= 'Z'
+}
+
+// String returns s with email addresses and the profile's identifying
+// values replaced by placeholders.
+func (r *Redactor) String(s string) string {
+	if s == "" {
+		return s
+	}
+	for _, t := range r.terms {
+		s = t.pattern.ReplaceAllLiteralString(s, t.placeholder)
+	}
+	return emailPattern.ReplaceAllLiteralString(s, "[email]")
+}
+
+// Scrub redacts, in place, every exported string reachable from v, which
+// must be a pointer. It walks structs, pointers, slices, and maps, so new
+// fields of crawled data are covered without listing them here.
+func (r *Redactor) Scrub(v any) {
+	r.scrub(reflect.ValueOf(v))
+}
+
+func (r *Redactor) scrub(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if !v.IsNil() {
+			r.scrub(v.Elem())
+		}
+	case reflect.Struct:
+		for i := range v.NumField() {
+			if v.Type().Field(i).IsExported() {
+				r.scrub(v.Field(i))
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := range v.Len() {
+			r.scrub(v.Index(i))
+		}
+	case reflect.Map:
+		// Map values are not addressable, so each is copied, scrubbed, and
+		// stored back. Keys are left alone.
+		iter := v.MapRange()
+		for iter.Next() {
+			val := reflect.New(iter.Value().Type()).Elem()
+			val.Set(iter.Value())
+			r.scrub(val)
+			v.SetMapIndex(iter.Key(), val)
+		}
+	case reflect.String:
+		if v.CanSet() {
+			v.SetString(r.String(v.String()))
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package redact
+
+import (
+	"testing"
+
+	"github.com/drpaneas/devlica/internal/ghcrawl"
+)
+
+func TestString(t *testing.T) {
+	r := ForProfile(ghcrawl.UserProfile{
+		Login:    "jdoe",
+		Name:     "Jane Doe",
+		Company:  "Acme",
+		Location: "Berlin",
+		Blog:     "https://jane.dev",
+		Email:    "jane@example.com",
+	})
+	tests := []struct {
+		in, want string
+	}{
+		{"Signed-off-by: Jane Doe <jane@example.com>", "Signed-off-by: [name] <[email]>"},
+		{"ping bob@corp.io about it", "ping [email] about it"},
+		{"Working at ACME from berlin", "Working at [company] from [location]"},
+		{"see https://jane.dev/post", "see [website]/post"},
+		{"jdoe wrote this", "jdoe wrote this"},
+		{"acmeish and Berliner stay", "acmeish and Berliner stay"},
+	}
+	for _, tt := range tests {
+		if got := r.String(tt.in); got != tt.want {
+			t.Errorf("String(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestShortValuesIgnored(t *testing.T) {
+	r := ForProfile(ghcrawl.UserProfile{Name: "Al", Location: " "})
+	if got := r.String("Also all fine"); got != "Also all fine" {
+		t.Errorf("got %q, want short profile values left alone", got)
+	}
+}
+
+func TestNameSameAsLoginKept(t *testing.T) {
+	r := ForProfile(ghcrawl.UserProfile{Login: "jdoe", Name: "JDoe", Location: "Berlin"})
+	in := "https://github.com/jdoe/tool/pull/3 by jdoe in Berlin"
+	if got, want := r.String(in), "https://github.com/jdoe/tool/pull/3 by jdoe in [location]"; got != want {
+		t.Errorf("String(%q) = %q, want %q", in, got, want)
+	}
+}
+
+func TestGitHubNamesKept(t *testing.T) {
+	tests := []struct {
+		name    string
+		profile ghcrawl.UserProfile
+		in      string
+	}{
+		{"organization company", ghcrawl.UserProfile{Login: "jdoe", Company: "@acme"}, "https://github.com/acme/tool/pull/3 in acme/tool"},
+		{"profile blog", ghcrawl.UserProfile{Login: "jdoe", Blog: "https://github.com/jdoe"}, "https://github.com/jdoe/tool in jdoe/tool"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ForProfile(tt.profile).String(tt.in); got != tt.in {
+				t.Errorf("String(%q) = %q, want it unchanged", tt.in, got)
+			}
+		})
+	}
+}
+
+func TestScrub(t *testing.T) {
+	result := &ghcrawl.CrawlResult{
+		User: ghcrawl.UserProfile{Login: "jdoe", Name: "Jane Doe", Bio: "Jane Doe, jane@example.com"},
+		Repos: []ghcrawl.RepoData{{
+			FullName:  "jdoe/tool",
+			Languages: map[string]int{"Go": 10},
+			Commits:   []ghcrawl.CommitData{{Message: "Thanks Jane Doe"}},
+		}},
+		Sponsorship: &ghcrawl.SponsorshipData{},
+	}
+	ForProfile(result.User).Scrub(result)
+
+	if result.User.Name != "[name]" || result.User.Bio != "[name], [email]" {
+		t.Errorf("profile = %+v, want name and bio redacted", result.User)
+	}
+	if got := result.Repos[0].Commits[0].Message; got != "Thanks [name]" {
+		t.Errorf("commit message = %q", got)
+	}
+	if result.User.Login != "jdoe" || result.Repos[0].FullName != "jdoe/tool" {
+		t.Error("login should be kept")
+	}
+	if result.Repos[0].Languages["Go"] != 10 {
+		t.Error("non-string map values should be untouched")
+	}
+}
+
+func TestScrubStringMap(t *testing.T) {
+	m := map[string]string{"a": "mail jane@example.com"}
+	ForProfile(ghcrawl.UserProfile{}).Scrub(&m)
+	if m["a"] != "mail [email]" {
+		t.Errorf("got %q", m["a"])
+	}
+}
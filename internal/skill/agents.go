@@ -0,0 +1,76 @@
+package skill
+
+import (
+	"fmt"
+	"log/slog"
+	"path/filepath"
+
+	"github.com/drpaneas/devlica/internal/analyzer"
+)
+
+type agentsData struct {
+	Username         string
+	Philosophy       string
+	CodeStyle        string
+	LanguageStyles   []analyzer.LanguageStyle
+	Testing          string
+	AntiPatterns     string
+	CommitMessages   string
+	ReviewPriorities string
+	ReviewDecision   string
+	ReviewNits       string
+	ReviewVoice      string
+	Collaboration    string
+	Communication    string
+	Maintainer       string
+	// Sparse is the caveat for a sparse profile, or "".
+	Sparse string
+}
+
+// generateAgents writes the coding style, review voice, and collaboration
+// norms as one repo-level instruction file, such as AGENTS.md, for agents
+// that read that rather than skill directories. Sections without data are
+// left out.
+func (g *Generator) generateAgents(username string, persona *analyzer.Persona, name string) ([]string, error) {
+	content, err := render(name, agentsTemplate, newAgentsData(username, persona))
+	if err != nil {
+		return nil, fmt.Errorf("generating %s: %w", name, err)
+	}
+	path, err := g.write(filepath.Join(g.outputDir, username, name), content)
+	if err != nil {
+		return nil, fmt.Errorf("generating %s: %w", name, err)
+	}
+	slog.Info("wrote agent instructions", "path", path)
+	return []string{path}, nil
+}
+
+// newAgentsData collects the sections of the single-file formats, falling
+// back to the raw analyses where the synthesis has no code style or review
+// priorities.
+func newAgentsData(username string, persona *analyzer.Persona) agentsData {
+	s := persona.Synthesis
+	data := agentsData{
+		Username:         username,
+		Philosophy:       s.CodingPhilosophy,
+		CodeStyle:        s.CodeStyleRules,
+		LanguageStyles:   persona.LanguageStyles,
+		Testing:          s.TestingPhilosophy,
+		AntiPatterns:     s.AntiPatterns,
+		CommitMessages:   s.CommitMessageStyle,
+		ReviewPriorities: s.ReviewPriorities,
+		ReviewDecision:   s.ReviewDecisionStyle,
+		ReviewNits:       s.ReviewNonBlockingNits,
+		ReviewVoice:      s.ReviewVoice,
+		Collaboration:    s.CollaborationStyle,
+		Communication:    s.CommunicationPatterns,
+		Maintainer:       s.MaintainerBehavior,
+		Sparse:           sparseCaveat(persona.Sparse),
+	}
+	if data.CodeStyle == "" {
+		data.CodeStyle = persona.CodeStyle
+	}
+	if data.ReviewPriorities == "" {
+		data.ReviewPriorities = persona.ReviewStyle
+	}
+	return data
+}
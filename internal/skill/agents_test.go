@@ -0,0 +1,62 @@
+package skill
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/drpaneas/devlica/internal/analyzer"
+)
+
+func TestGenerate_Agents(t *testing.T) {
+	dir := t.TempDir()
+	gen := NewGenerator(dir)
+	gen.SetFormats([]Format{FormatAgents, FormatClaude})
+	persona := &analyzer.Persona{
+		ReviewStyle: "Focuses on error handling.",
+		Synthesis: &analyzer.SynthesisResult{
+			CodeStyleRules:     "- Wrap errors with context",
+			ReviewVoice:        "Short and direct.",
+			CollaborationStyle: "Files detailed upstream bug reports.",
+		},
+		LanguageStyles: []analyzer.LanguageStyle{{Language: "Go", Rules: "- Table-driven tests"}},
+	}
+
+	paths, err := gen.Generate("testdev", persona)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{filepath.Join(dir, "testdev", "AGENTS.md"), filepath.Join(dir, "testdev", "CLAUDE.md")}
+	if strings.Join(paths, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("paths = %v, want %v", paths, want)
+	}
+	agents, err := os.ReadFile(want[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	claude, err := os.ReadFile(want[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(agents) != string(claude) {
+		t.Error("AGENTS.md and CLAUDE.md should have the same content")
+	}
+	for _, s := range []string{
+		"# Working like testdev",
+		"### Style Rules\n\n- Wrap errors with context",
+		"### Go\n\n- Table-driven tests",
+		"### Priorities\n\nFocuses on error handling.",
+		"### Feedback Style\n\nShort and direct.",
+		"### Working With Others\n\nFiles detailed upstream bug reports.",
+	} {
+		if !strings.Contains(string(agents), s) {
+			t.Errorf("AGENTS.md is missing %q:\n%s", s, agents)
+		}
+	}
+	for _, s := range []string{"### Testing", "## Commit Messages", "### Issues and Maintenance"} {
+		if strings.Contains(string(agents), s) {
+			t.Errorf("AGENTS.md has empty section %q:\n%s", s, agents)
+		}
+	}
+}
@@ -0,0 +1,28 @@
+package skill
+
+import (
+	"fmt"
+	"log/slog"
+	"path/filepath"
+
+	"github.com/drpaneas/devlica/internal/analyzer"
+)
+
+// AiderConventionsFile is the conventions file Aider loads with --read.
+const AiderConventionsFile = "CONVENTIONS.md"
+
+// generateAider writes the coding conventions and commit message style as
+// an Aider CONVENTIONS.md. Aider writes code and commits rather than
+// reviewing, so the review and collaboration sections are left out.
+func (g *Generator) generateAider(username string, persona *analyzer.Persona) ([]string, error) {
+	content, err := render("aider", aiderTemplate, newAgentsData(username, persona))
+	if err != nil {
+		return nil, fmt.Errorf("generating Aider conventions: %w", err)
+	}
+	path, err := g.write(filepath.Join(g.outputDir, username, AiderConventionsFile), content)
+	if err != nil {
+		return nil, fmt.Errorf("generating Aider conventions: %w", err)
+	}
+	slog.Info("wrote Aider conventions", "path", path)
+	return []string{path}, nil
+}
@@ -0,0 +1,45 @@
+package skill
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/drpaneas/devlica/internal/analyzer"
+)
+
+func TestGenerate_Aider(t *testing.T) {
+	dir := t.TempDir()
+	gen := NewGenerator(dir)
+	gen.SetFormats([]Format{FormatAider})
+	persona := &analyzer.Persona{
+		Synthesis: &analyzer.SynthesisResult{
+			CodeStyleRules:     "- Wrap errors with context",
+			CommitMessageStyle: "Imperative subject lines.",
+			ReviewVoice:        "Short and direct.",
+		},
+		LanguageStyles: []analyzer.LanguageStyle{{Language: "Go", Rules: "- Table-driven tests"}},
+	}
+
+	paths, err := gen.Generate("testdev", persona)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := filepath.Join(dir, "testdev", AiderConventionsFile)
+	if len(paths) != 1 || paths[0] != want {
+		t.Fatalf("paths = %v, want [%s]", paths, want)
+	}
+	content, err := os.ReadFile(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{"# Conventions of testdev", "## Style Rules\n\n- Wrap errors with context", "## Go\n\n- Table-driven tests", "## Commit Messages\n\nImperative subject lines."} {
+		if !strings.Contains(string(content), s) {
+			t.Errorf("CONVENTIONS.md is missing %q:\n%s", s, content)
+		}
+	}
+	if strings.Contains(string(content), "Short and direct.") {
+		t.Errorf("CONVENTIONS.md should leave out the review sections:\n%s", content)
+	}
+}
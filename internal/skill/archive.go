@@ -0,0 +1,157 @@
+package skill
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/drpaneas/devlica/internal/analyzer"
+)
+
+// ArchiveFormat names the kind of archive WriteArchive packs.
+type ArchiveFormat string
+
+// The supported archive formats.
+const (
+	ArchiveZip   ArchiveFormat = "zip"
+	ArchiveTarGz ArchiveFormat = "tar.gz"
+)
+
+// ManifestName is the manifest's name at the root of an archive.
+const ManifestName = "manifest.json"
+
+// ParseArchiveFormat parses "zip" or "tar.gz", also accepted as "tgz".
+func ParseArchiveFormat(s string) (ArchiveFormat, error) {
+	switch f := ArchiveFormat(strings.ToLower(strings.TrimSpace(s))); f {
+	case ArchiveZip, ArchiveTarGz:
+		return f, nil
+	case "tgz":
+		return ArchiveTarGz, nil
+	}
+	return "", fmt.Errorf("unknown archive format %q: must be zip or tar.gz", s)
+}
+
+// Manifest describes an archive's or version directory's contents: whose
+// persona the files carry, how it was produced, and a checksum per file so
+// a teammate can tell a bundle was not altered.
+type Manifest struct {
+	Username  string                   `json:"username"`
+	CreatedAt time.Time                `json:"created_at"`
+	Persona   analyzer.PersonaMetadata `json:"persona"`
+	Files     []ManifestFile           `json:"files"`
+}
+
+// ManifestFile is one file, by its slash-separated path in the archive or
+// version directory.
+type ManifestFile struct {
+	Path   string `json:"path"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// WriteArchive packs the generated files at paths, which must be under
+// outputDir, into <outputDir>/<username>-skills.zip or .tar.gz, keeping
+// their layout relative to outputDir, with a ManifestName at the root. It
+// returns the archive's path.
+func WriteArchive(outputDir, username string, meta analyzer.PersonaMetadata, paths []string, format ArchiveFormat) (string, error) {
+	manifest, contents, err := newManifest(outputDir, username, meta, paths)
+	if err != nil {
+		return "", fmt.Errorf("archiving: %w", err)
+	}
+	manifestJSON, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("encoding archive manifest: %w", err)
+	}
+
+	archivePath := filepath.Join(outputDir, username+"-skills."+string(format))
+	f, err := os.Create(archivePath)
+	if err != nil {
+		return "", fmt.Errorf("creating archive: %w", err)
+	}
+	var add func(name string, content []byte) error
+	var finish func() error
+	switch format {
+	case ArchiveZip:
+		zw := zip.NewWriter(f)
+		add = func(name string, content []byte) error {
+			w, err := zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: manifest.CreatedAt})
+			if err != nil {
+				return err
+			}
+			_, err = w.Write(content)
+			return err
+		}
+		finish = zw.Close
+	case ArchiveTarGz:
+		gw := gzip.NewWriter(f)
+		tw := tar.NewWriter(gw)
+		add = func(name string, content []byte) error {
+			hdr := &tar.Header{Name: name, Mode: 0o644, Size: int64(len(content)), ModTime: manifest.CreatedAt, Typeflag: tar.TypeReg}
+			if err := tw.WriteHeader(hdr); err != nil {
+				return err
+			}
+			_, err := tw.Write(content)
+			return err
+		}
+		finish = func() error {
+			if err := tw.Close(); err != nil {
+				return err
+			}
+			return gw.Close()
+		}
+	default:
+		f.Close()
+		os.Remove(archivePath)
+		return "", fmt.Errorf("unknown archive format %q", format)
+	}
+
+	err = add(ManifestName, append(manifestJSON, '\n'))
+	for i := 0; err == nil && i < len(paths); i++ {
+		err = add(manifest.Files[i].Path, contents[i])
+	}
+	if err == nil {
+		err = finish()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(archivePath)
+		return "", fmt.Errorf("writing archive %s: %w", archivePath, err)
+	}
+	return archivePath, nil
+}
+
+// newManifest reads the files at paths, which must be under dir, and lists
+// them in a Manifest by their paths relative to dir. It also returns each
+// file's content, in the order of paths.
+func newManifest(dir, username string, meta analyzer.PersonaMetadata, paths []string) (Manifest, [][]byte, error) {
+	manifest := Manifest{Username: username, CreatedAt: time.Now().UTC(), Persona: meta}
+	contents := make([][]byte, len(paths))
+	for i, path := range paths {
+		rel, err := filepath.Rel(dir, path)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return Manifest{}, nil, fmt.Errorf("%s is not under %s", path, dir)
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return Manifest{}, nil, err
+		}
+		sum := sha256.Sum256(content)
+		contents[i] = content
+		manifest.Files = append(manifest.Files, ManifestFile{
+			Path:   filepath.ToSlash(rel),
+			Size:   int64(len(content)),
+			SHA256: hex.EncodeToString(sum[:]),
+		})
+	}
+	return manifest, contents, nil
+}
@@ -0,0 +1,146 @@
+package skill
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/drpaneas/devlica/internal/analyzer"
+)
+
+func TestParseArchiveFormat(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    ArchiveFormat
+		wantErr bool
+	}{
+		{"zip", ArchiveZip, false},
+		{"TAR.GZ", ArchiveTarGz, false},
+		{"tgz", ArchiveTarGz, false},
+		{"rar", "", true},
+	}
+	for _, tt := range tests {
+		got, err := ParseArchiveFormat(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseArchiveFormat(%q) = %q, %v; want %q, error %v", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+// readArchive returns each file in the archive at path by name.
+func readArchive(t *testing.T, path string, format ArchiveFormat) map[string][]byte {
+	t.Helper()
+	files := make(map[string][]byte)
+	switch format {
+	case ArchiveZip:
+		zr, err := zip.OpenReader(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer zr.Close()
+		for _, f := range zr.File {
+			rc, err := f.Open()
+			if err != nil {
+				t.Fatal(err)
+			}
+			files[f.Name], err = io.ReadAll(rc)
+			rc.Close()
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+	case ArchiveTarGz:
+		f, err := os.Open(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		gr, err := gzip.NewReader(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tr := tar.NewReader(gr)
+		for {
+			hdr, err := tr.Next()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if files[hdr.Name], err = io.ReadAll(tr); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	return files
+}
+
+func TestWriteArchive(t *testing.T) {
+	for _, format := range []ArchiveFormat{ArchiveZip, ArchiveTarGz} {
+		t.Run(string(format), func(t *testing.T) {
+			dir := t.TempDir()
+			gen := NewGenerator(dir)
+			gen.SetFormats([]Format{FormatCursor, FormatAgents})
+			persona := &analyzer.Persona{
+				Synthesis: &analyzer.SynthesisResult{CodeStyleRules: "- Wrap errors with context"},
+			}
+			paths, err := gen.Generate("testdev", persona)
+			if err != nil {
+				t.Fatal(err)
+			}
+			meta := analyzer.PersonaMetadata{Username: "testdev", Model: "test-model"}
+			path, err := WriteArchive(dir, "testdev", meta, paths, format)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := filepath.Join(dir, "testdev-skills."+string(format)); path != want {
+				t.Errorf("archive path = %s, want %s", path, want)
+			}
+
+			files := readArchive(t, path, format)
+			var manifest Manifest
+			if err := json.Unmarshal(files[ManifestName], &manifest); err != nil {
+				t.Fatalf("reading manifest: %v", err)
+			}
+			if manifest.Username != "testdev" || manifest.Persona.Model != "test-model" {
+				t.Errorf("manifest = %+v", manifest)
+			}
+			if len(manifest.Files) != len(paths) || len(files) != len(paths)+1 {
+				t.Fatalf("archived %d files with %d in the manifest, want %d", len(files)-1, len(manifest.Files), len(paths))
+			}
+			for _, mf := range manifest.Files {
+				content, ok := files[mf.Path]
+				if !ok {
+					t.Errorf("manifest lists %s, which is not in the archive", mf.Path)
+					continue
+				}
+				sum := sha256.Sum256(content)
+				if mf.SHA256 != hex.EncodeToString(sum[:]) || mf.Size != int64(len(content)) {
+					t.Errorf("%s: manifest size or checksum does not match its content", mf.Path)
+				}
+			}
+			if _, ok := files["testdev/AGENTS.md"]; !ok {
+				t.Error("archive is missing testdev/AGENTS.md")
+			}
+		})
+	}
+}
+
+func TestWriteArchive_OutsideOutput(t *testing.T) {
+	dir := t.TempDir()
+	outside := filepath.Join(t.TempDir(), "SKILL.md")
+	if err := os.WriteFile(outside, []byte("x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := WriteArchive(dir, "testdev", analyzer.PersonaMetadata{}, []string{outside}, ArchiveZip); err == nil {
+		t.Error("WriteArchive() should refuse a file outside the output directory")
+	}
+}
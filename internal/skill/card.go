@@ -0,0 +1,170 @@
+package skill
+
+import (
+	"bytes"
+	"fmt"
+	htmltemplate "html/template"
+	"log/slog"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/drpaneas/devlica/internal/analyzer"
+	"github.com/drpaneas/devlica/internal/textutil"
+)
+
+// Files written by FormatCard and FormatCardHTML.
+const (
+	CardFile     = "PERSONA.md"
+	CardHTMLFile = "PERSONA.html"
+)
+
+const (
+	// maxCardTraits bounds the traits on a card, which should fit on a page.
+	maxCardTraits = 5
+	// maxHeadlineLen bounds the headline, in bytes.
+	maxHeadlineLen = 200
+	// cardPhrases is how many signature phrases a card quotes.
+	cardPhrases = 3
+)
+
+type cardData struct {
+	Username string
+	Headline string
+	Sparse   string
+	Traits   []string
+	Stats    []cardStat
+	// Score is the benchmark score, as "82.5/100", or "".
+	Score  string
+	Footer string
+}
+
+// cardStat is one key number on a card.
+type cardStat struct {
+	Label string
+	Value string
+}
+
+// generateCard writes PERSONA.md, or with html PERSONA.html, a one-page
+// summary of the persona for people rather than agents: a headline, the
+// top distinctive traits, key numbers from the crawl, and the benchmark
+// score when the persona was benchmarked.
+func (g *Generator) generateCard(username string, persona *analyzer.Persona, html bool) ([]string, error) {
+	data := newCardData(username, persona)
+	if data.Headline == "" && len(data.Traits) == 0 && len(data.Stats) == 0 {
+		slog.Warn("persona has nothing to summarize, so no persona card was written")
+		return nil, nil
+	}
+	name := CardFile
+	content, err := render("card", cardTemplate, data)
+	if html {
+		name = CardHTMLFile
+		content, err = renderHTML("card-html", cardHTMLTemplate, data)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("generating persona card: %w", err)
+	}
+	path, err := g.write(filepath.Join(g.outputDir, username, name), content)
+	if err != nil {
+		return nil, fmt.Errorf("generating persona card: %w", err)
+	}
+	slog.Info("wrote persona card", "path", path)
+	return []string{path}, nil
+}
+
+func newCardData(username string, persona *analyzer.Persona) cardData {
+	traits := checklistItems(persona.Synthesis.DistinctiveTraits)
+	data := cardData{
+		Username: username,
+		Headline: headline(persona.Synthesis.CodingPhilosophy, persona.DeveloperIdentity),
+		Sparse:   sparseCaveat(persona.Sparse),
+		Traits:   traits[:min(len(traits), maxCardTraits)],
+		Stats:    cardStats(persona),
+		Footer:   cardFooter(persona.Metadata),
+	}
+	if score := persona.Metadata.BenchmarkScore; score != nil {
+		data.Score = fmt.Sprintf("%.1f/100", *score)
+	}
+	return data
+}
+
+// headline is the first sentence of the first of texts with any, without
+// Markdown emphasis.
+func headline(texts ...string) string {
+	for _, text := range texts {
+		if strings.HasPrefix(text, "Insufficient data") {
+			continue
+		}
+		items := checklistItems(text)
+		if len(items) == 0 {
+			continue
+		}
+		s := strings.NewReplacer("**", "", "__", "", "`", "").Replace(items[0])
+		if i := strings.Index(s, ". "); i >= 0 {
+			s = s[:i+1]
+		}
+		return textutil.Truncate(s, maxHeadlineLen, "...")
+	}
+	return ""
+}
+
+// cardStats returns the key numbers, skipping any the crawl had no data
+// for.
+func cardStats(persona *analyzer.Persona) []cardStat {
+	var out []cardStat
+	m := persona.Metrics
+	if m.ReviewComments > 0 {
+		out = append(out,
+			cardStat{"Review comments", fmt.Sprintf("%d, median %d characters, %s asking a question", m.ReviewComments, m.ReviewLenMedian, pct(m.QuestionRate))},
+			cardStat{"Review tone", fmt.Sprintf("%s warm, %s hedged, %s harsh", pct(m.WarmRate), pct(m.HedgeRate), pct(m.HarshRate))},
+		)
+	}
+	if m.PRs > 0 {
+		out = append(out, cardStat{"Pull requests", fmt.Sprintf("%d, median %d lines across %d files", m.PRs, m.PRSizeMedian, m.PRFilesMedian)})
+	}
+	if cs := persona.CommitStats; cs.Total-cs.Merges > 0 {
+		out = append(out, cardStat{"Commits", fmt.Sprintf("%d, subjects median %d characters, %s conventional", cs.Total-cs.Merges, cs.SubjectLenMedian, pct(cs.ConventionalRate))})
+	}
+	if m.ChangedFiles > 0 {
+		out = append(out, cardStat{"Tests", pct(m.TestFileRate) + " of changed files"})
+	}
+	if phrases := persona.Phrases.Signature; len(phrases) > 0 {
+		quoted := make([]string, 0, cardPhrases)
+		for _, p := range phrases[:min(len(phrases), cardPhrases)] {
+			quoted = append(quoted, strconv.Quote(p.Text))
+		}
+		out = append(out, cardStat{"Signature phrases", strings.Join(quoted, ", ")})
+	}
+	return out
+}
+
+// cardFooter says what produced the persona and from how much activity, as
+// far as meta records it.
+func cardFooter(meta analyzer.PersonaMetadata) string {
+	s := "Generated by devlica"
+	if meta.DevlicaVersion != "" {
+		s += " " + meta.DevlicaVersion
+	}
+	if !meta.GeneratedAt.IsZero() {
+		s += " on " + meta.GeneratedAt.UTC().Format(time.DateOnly)
+	}
+	if c := meta.DataCounts; c != (analyzer.DataCounts{}) {
+		s += fmt.Sprintf(" from %d repositories, %d commits, and %d reviews", c.Repos, c.Commits, c.Reviews)
+	}
+	return s + "."
+}
+
+// renderHTML is render with html/template, which escapes the persona's
+// text for the page.
+func renderHTML(name, tmplStr string, data any) ([]byte, error) {
+	tmpl, err := htmltemplate.New(name).Parse(tmplStr)
+	if err != nil {
+		return nil, fmt.Errorf("parsing template %s: %w", name, err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("executing template %s: %w", name, err)
+	}
+	return buf.Bytes(), nil
+}
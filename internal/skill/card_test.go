@@ -0,0 +1,119 @@
+package skill
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/drpaneas/devlica/internal/analyzer"
+	"github.com/drpaneas/devlica/internal/stats"
+)
+
+func cardPersona() *analyzer.Persona {
+	score := 82.46
+	return &analyzer.Persona{
+		Synthesis: &analyzer.SynthesisResult{
+			CodingPhilosophy:  "**Small, boring code** wins. Everything else follows.",
+			DistinctiveTraits: "- Names <things> plainly\n- Deletes dead code\n- Two\n- Three\n- Four\n- Five",
+		},
+		Metrics:     stats.Metrics{ReviewComments: 40, ReviewLenMedian: 90, QuestionRate: 0.25, PRs: 12, PRSizeMedian: 80, PRFilesMedian: 3},
+		CommitStats: stats.CommitMessageStats{Total: 21, Merges: 1, SubjectLenMedian: 48, ConventionalRate: 0.9},
+		Phrases:     stats.PhraseStats{Comments: 40, Signature: []stats.Phrase{{Text: "nit:", Comments: 9}}},
+		Metadata: analyzer.PersonaMetadata{
+			DevlicaVersion: "v1.2.0",
+			GeneratedAt:    time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+			DataCounts:     analyzer.DataCounts{Repos: 4, Commits: 21, Reviews: 40},
+			BenchmarkScore: &score,
+		},
+	}
+}
+
+func TestGenerate_Card(t *testing.T) {
+	dir := t.TempDir()
+	gen := NewGenerator(dir)
+	gen.SetFormats([]Format{FormatCard, FormatCardHTML})
+	paths, err := gen.Generate("testdev", cardPersona())
+	if err != nil {
+		t.Fatal(err)
+	}
+	md, html := filepath.Join(dir, "testdev", CardFile), filepath.Join(dir, "testdev", CardHTMLFile)
+	if len(paths) != 2 || paths[0] != md || paths[1] != html {
+		t.Fatalf("paths = %v, want [%s %s]", paths, md, html)
+	}
+
+	content, err := os.ReadFile(md)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := string(content)
+	for _, s := range []string{
+		"# testdev\n\n> Small, boring code wins.\n",
+		"- Four\n\n",
+		"- **Review comments:** 40, median 90 characters, 25% asking a question\n",
+		"- **Commits:** 20, subjects median 48 characters, 90% conventional\n",
+		"- **Signature phrases:** \"nit:\"\n",
+		"Scored **82.5/100**",
+		"Generated by devlica v1.2.0 on 2026-03-01 from 4 repositories, 21 commits, and 40 reviews.",
+	} {
+		if !strings.Contains(got, s) {
+			t.Errorf("PERSONA.md is missing %q:\n%s", s, got)
+		}
+	}
+	if strings.Contains(got, "Five") {
+		t.Errorf("card should keep only the top %d traits:\n%s", maxCardTraits, got)
+	}
+	if strings.Contains(got, "Tests:") {
+		t.Errorf("card should skip numbers the crawl had no data for:\n%s", got)
+	}
+
+	content, err = os.ReadFile(html)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got = string(content)
+	if !strings.Contains(got, "<li>Names &lt;things&gt; plainly</li>") {
+		t.Errorf("PERSONA.html should escape the persona's text:\n%s", got)
+	}
+	if !strings.Contains(got, `<span class="score">82.5/100</span>`) {
+		t.Errorf("PERSONA.html is missing the benchmark score:\n%s", got)
+	}
+}
+
+func TestGenerate_CardWithoutBenchmark(t *testing.T) {
+	dir := t.TempDir()
+	persona := cardPersona()
+	persona.Metadata = analyzer.PersonaMetadata{}
+	gen := NewGenerator(dir)
+	gen.SetFormats([]Format{FormatCard})
+	if _, err := gen.Generate("testdev", persona); err != nil {
+		t.Fatal(err)
+	}
+	content, err := os.ReadFile(filepath.Join(dir, "testdev", CardFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(content), "## Benchmark") {
+		t.Errorf("an unbenchmarked persona should get no benchmark section:\n%s", content)
+	}
+	if !strings.Contains(string(content), "\nGenerated by devlica.\n") {
+		t.Errorf("footer should say only what was recorded:\n%s", content)
+	}
+}
+
+func TestHeadline(t *testing.T) {
+	tests := []struct {
+		texts []string
+		want  string
+	}{
+		{[]string{"Insufficient data to say.", "Ships `small` changes. Often."}, "Ships small changes."},
+		{[]string{"", "## Identity\n\n- A __maintainer__ first"}, "A maintainer first"},
+		{[]string{"", ""}, ""},
+	}
+	for _, tt := range tests {
+		if got := headline(tt.texts...); got != tt.want {
+			t.Errorf("headline(%q) = %q, want %q", tt.texts, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package skill
+
+import (
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/drpaneas/devlica/internal/analyzer"
+)
+
+// ChecklistFile is the review checklist written by FormatChecklist.
+const ChecklistFile = "CHECKLIST.md"
+
+// listItemPattern matches a top-level Markdown list item, bulleted or
+// numbered, and captures its text.
+var listItemPattern = regexp.MustCompile(`^(?:[-*+]|\d+[.)])\s+(.+)$`)
+
+// checkboxPattern matches a task-list box already on an item.
+var checkboxPattern = regexp.MustCompile(`^\[[ xX]\]\s+`)
+
+type checklistData struct {
+	Username string
+	Items    []string
+	Nits     []string
+}
+
+// generateChecklist writes the review priorities as an ordered task list,
+// most important first, that can be pasted into a pull request template or
+// handed to a review bot as its rubric. Nothing is written when the persona
+// has no review data.
+func (g *Generator) generateChecklist(username string, persona *analyzer.Persona) ([]string, error) {
+	priorities := persona.Synthesis.ReviewPriorities
+	if priorities == "" {
+		priorities = persona.ReviewStyle
+	}
+	data := checklistData{
+		Username: username,
+		Items:    checklistItems(priorities),
+		Nits:     checklistItems(persona.Synthesis.ReviewNonBlockingNits),
+	}
+	if len(data.Items) == 0 {
+		slog.Warn("persona has no review priorities, so no review checklist was written")
+		return nil, nil
+	}
+	content, err := render("checklist", checklistTemplate, data)
+	if err != nil {
+		return nil, fmt.Errorf("generating review checklist: %w", err)
+	}
+	path, err := g.write(filepath.Join(g.outputDir, username, ChecklistFile), content)
+	if err != nil {
+		return nil, fmt.Errorf("generating review checklist: %w", err)
+	}
+	slog.Info("wrote review checklist", "path", path, "items", len(data.Items))
+	return []string{path}, nil
+}
+
+// checklistItems distills Markdown into checklist items, in order: each
+// top-level list item, with its wrapped lines joined, or each line of prose
+// when there is no list. Headings, nested items, and boxes already on an
+// item are dropped.
+func checklistItems(markdown string) []string {
+	var items []string
+	var prose []string
+	inItem := false
+	for _, line := range strings.Split(markdown, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "" || strings.HasPrefix(trimmed, "#"):
+			inItem = false
+		case line[0] != ' ' && line[0] != '\t' && listItemPattern.MatchString(trimmed):
+			text := listItemPattern.FindStringSubmatch(trimmed)[1]
+			items = append(items, checkboxPattern.ReplaceAllString(text, ""))
+			inItem = true
+		case inItem && !listItemPattern.MatchString(trimmed):
+			items[len(items)-1] += " " + trimmed
+		case !inItem && !listItemPattern.MatchString(trimmed):
+			prose = append(prose, trimmed)
+		}
+	}
+	if len(items) == 0 {
+		return prose
+	}
+	return items
+}
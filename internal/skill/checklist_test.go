@@ -0,0 +1,79 @@
+package skill
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/drpaneas/devlica/internal/analyzer"
+)
+
+func TestChecklistItems(t *testing.T) {
+	tests := []struct {
+		name     string
+		markdown string
+		want     []string
+	}{
+		{
+			name:     "bullets with wrapped and nested lines",
+			markdown: "### Priorities\n\n- **Correctness** of error paths,\n  including cleanup\n  - nested detail\n* [x] Tests for every fix\n",
+			want:     []string{"**Correctness** of error paths, including cleanup", "Tests for every fix"},
+		},
+		{
+			name:     "numbered",
+			markdown: "1. API compatibility\n2) Naming\n",
+			want:     []string{"API compatibility", "Naming"},
+		},
+		{
+			name:     "prose without a list",
+			markdown: "Focuses on error handling.\nAsks for benchmarks.\n",
+			want:     []string{"Focuses on error handling.", "Asks for benchmarks."},
+		},
+		{
+			name: "empty",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := checklistItems(tt.markdown); !slices.Equal(got, tt.want) {
+				t.Errorf("checklistItems() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerate_Checklist(t *testing.T) {
+	dir := t.TempDir()
+	gen := NewGenerator(dir)
+	gen.SetFormats([]Format{FormatChecklist})
+	persona := &analyzer.Persona{
+		Synthesis: &analyzer.SynthesisResult{
+			ReviewPriorities:      "1. Correctness\n2. Tests",
+			ReviewNonBlockingNits: "- Typos in comments",
+		},
+	}
+	paths, err := gen.Generate("testdev", persona)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := filepath.Join(dir, "testdev", ChecklistFile)
+	if len(paths) != 1 || paths[0] != want {
+		t.Fatalf("paths = %v, want [%s]", paths, want)
+	}
+	content, err := os.ReadFile(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{"## Before approving\n\n- [ ] Correctness\n- [ ] Tests\n", "- [ ] Typos in comments"} {
+		if !strings.Contains(string(content), s) {
+			t.Errorf("CHECKLIST.md is missing %q:\n%s", s, content)
+		}
+	}
+
+	paths, err = gen.Generate("testdev", &analyzer.Persona{Synthesis: &analyzer.SynthesisResult{}})
+	if err != nil || len(paths) != 0 {
+		t.Errorf("Generate() = %v, %v; want no checklist without review data", paths, err)
+	}
+}
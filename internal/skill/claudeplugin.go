@@ -0,0 +1,106 @@
+package skill
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+
+	"github.com/drpaneas/devlica/internal/analyzer"
+)
+
+// Layout of the plugin FormatClaudePlugin writes under output/<username>.
+const (
+	ClaudePluginDir = "claude-plugin"
+	// ClaudePluginMetaDir holds the plugin and marketplace manifests.
+	ClaudePluginMetaDir     = ".claude-plugin"
+	ClaudePluginFile        = "plugin.json"
+	ClaudeMarketplaceFile   = "marketplace.json"
+	claudePluginSkillsDir   = "skills"
+	claudePluginNameSuffix  = "-persona"
+	claudeMarketplaceSuffix = "-devlica"
+	// claudePluginVersionLayout is a semantic version that grows with the
+	// time it formats.
+	claudePluginVersionLayout = "2006.1.2150405"
+)
+
+// claudePlugin is the plugin manifest, .claude-plugin/plugin.json.
+type claudePlugin struct {
+	Name        string             `json:"name"`
+	Version     string             `json:"version,omitempty"`
+	Description string             `json:"description"`
+	Author      claudePluginAuthor `json:"author"`
+	Keywords    []string           `json:"keywords"`
+}
+
+type claudePluginAuthor struct {
+	Name string `json:"name"`
+}
+
+// claudeMarketplace is a marketplace listing the one plugin, from the
+// same directory, so the plugin directory can be pushed as a repository
+// and added with /plugin marketplace add.
+type claudeMarketplace struct {
+	Name    string                   `json:"name"`
+	Owner   claudePluginAuthor       `json:"owner"`
+	Plugins []claudeMarketplaceEntry `json:"plugins"`
+}
+
+type claudeMarketplaceEntry struct {
+	Name        string `json:"name"`
+	Source      string `json:"source"`
+	Description string `json:"description"`
+	Version     string `json:"version,omitempty"`
+}
+
+// generateClaudePlugin writes the Cursor skills into a Claude Code plugin
+// under output/<username>/claude-plugin: the selected skills in skills/,
+// with their examples and language-scoped skills, and a plugin manifest
+// and a marketplace manifest in .claude-plugin/.
+func (g *Generator) generateClaudePlugin(username string, persona *analyzer.Persona) ([]string, error) {
+	root := filepath.Join(g.outputDir, username, ClaudePluginDir)
+	g.skillsDir = filepath.Join(root, claudePluginSkillsDir)
+	paths, err := g.generateCursor(username, persona)
+	g.skillsDir = ""
+	if err != nil {
+		return nil, err
+	}
+
+	name := username + claudePluginNameSuffix
+	description := fmt.Sprintf("Skills that write, review, and explain code the way %s does, generated by devlica from their GitHub activity.", username)
+	var version string
+	if t := persona.Metadata.GeneratedAt; !t.IsZero() {
+		// Each generation is a release of the plugin, versioned as
+		// year.month.dayHHMMSS so a newer persona updates installs of an
+		// older one.
+		version = t.UTC().Format(claudePluginVersionLayout)
+	}
+	plugin := claudePlugin{
+		Name:        name,
+		Version:     version,
+		Description: description,
+		Author:      claudePluginAuthor{Name: username},
+		Keywords:    []string{"devlica", "persona", "code-review", "coding-style"},
+	}
+	marketplace := claudeMarketplace{
+		Name:    username + claudeMarketplaceSuffix,
+		Owner:   claudePluginAuthor{Name: username},
+		Plugins: []claudeMarketplaceEntry{{Name: name, Source: "./", Description: description, Version: version}},
+	}
+	for _, f := range []struct {
+		file string
+		v    any
+	}{{ClaudePluginFile, plugin}, {ClaudeMarketplaceFile, marketplace}} {
+		data, err := json.MarshalIndent(f.v, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("encoding Claude plugin %s: %w", f.file, err)
+		}
+		path, err := g.write(filepath.Join(root, ClaudePluginMetaDir, f.file), append(data, '\n'))
+		if err != nil {
+			return nil, fmt.Errorf("generating Claude plugin: %w", err)
+		}
+		paths = append(paths, path)
+	}
+	slog.Info("wrote Claude plugin", "dir", root, "plugin", name)
+	return paths, nil
+}
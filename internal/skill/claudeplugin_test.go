@@ -0,0 +1,59 @@
+package skill
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/drpaneas/devlica/internal/analyzer"
+)
+
+func TestGenerate_ClaudePlugin(t *testing.T) {
+	dir := t.TempDir()
+	persona := &analyzer.Persona{
+		Synthesis: &analyzer.SynthesisResult{CodeStyleRules: "Wrap errors."},
+		Metadata:  analyzer.PersonaMetadata{GeneratedAt: time.Date(2026, 3, 1, 9, 5, 7, 0, time.UTC)},
+	}
+	gen := NewGenerator(dir)
+	gen.SetFormats([]Format{FormatClaudePlugin})
+	gen.SetUpdate(true)
+	// Update mode rewrites the manifests whole: JSON has no comments to
+	// keep a managed block between.
+	for range 2 {
+		if _, err := gen.Generate("testdev", persona); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	root := filepath.Join(dir, "testdev", ClaudePluginDir)
+	if n, err := LintDir(filepath.Join(root, "skills")); err != nil || n != len(DefaultSkills) {
+		t.Errorf("LintDir(skills) = %d, %v; want the %d default skills, all valid", n, err, len(DefaultSkills))
+	}
+	if _, err := os.Stat(filepath.Join(dir, "testdev-coding-style")); !os.IsNotExist(err) {
+		t.Error("plugin skills should not also be written to the output directory")
+	}
+
+	var plugin claudePlugin
+	readJSON(t, filepath.Join(root, ClaudePluginMetaDir, ClaudePluginFile), &plugin)
+	if plugin.Name != "testdev-persona" || plugin.Version != "2026.3.1090507" || plugin.Author.Name != "testdev" {
+		t.Errorf("plugin.json = %+v", plugin)
+	}
+	var marketplace claudeMarketplace
+	readJSON(t, filepath.Join(root, ClaudePluginMetaDir, ClaudeMarketplaceFile), &marketplace)
+	if len(marketplace.Plugins) != 1 || marketplace.Plugins[0].Name != plugin.Name || marketplace.Plugins[0].Source != "./" {
+		t.Errorf("marketplace.json = %+v, want it to list the plugin from its own directory", marketplace)
+	}
+}
+
+func readJSON(t *testing.T, path string, v any) {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		t.Fatalf("%s is not valid JSON: %v\n%s", path, err, data)
+	}
+}
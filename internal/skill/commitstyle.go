@@ -0,0 +1,160 @@
+package skill
+
+import (
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"strings"
+
+	"github.com/drpaneas/devlica/internal/analyzer"
+	"github.com/drpaneas/devlica/internal/stats"
+)
+
+// Files written by FormatCommitStyle.
+const (
+	CommitStyleFile = "COMMIT_STYLE.md"
+	// GitMessageFile is a commit message template for git's
+	// commit.template setting.
+	GitMessageFile = ".gitmessage"
+)
+
+// majority is the share of commits a habit needs for the template to state
+// it as a rule.
+const majority = 0.5
+
+type commitStyleData struct {
+	Username string
+	// Commits is how many non-merge commits Stats were computed from.
+	Commits int
+	Stats   []string
+	Guide   string
+}
+
+// generateCommitStyle writes COMMIT_STYLE.md, a guide to the developer's
+// commit messages for people, and a .gitmessage template that puts the
+// same conventions in the editor for every commit. The numbers come from
+// the persona's commit statistics, which personas saved by an older
+// devlica do not have; the guide is the synthesized commit message style.
+func (g *Generator) generateCommitStyle(username string, persona *analyzer.Persona) ([]string, error) {
+	cs := persona.CommitStats
+	data := commitStyleData{
+		Username: username,
+		Commits:  cs.Total - cs.Merges,
+		Guide:    persona.Synthesis.CommitMessageStyle,
+	}
+	if data.Commits > 0 {
+		data.Stats = commitStatLines(cs)
+	}
+	if data.Guide == "" && !strings.HasPrefix(persona.CommitMessages, "Insufficient data") {
+		data.Guide = persona.CommitMessages
+	}
+	if data.Guide == "" && data.Commits <= 0 {
+		slog.Warn("persona has no commit message data, so no commit style guide was written")
+		return nil, nil
+	}
+
+	dir := filepath.Join(g.outputDir, username)
+	content, err := render("commit-style", commitStyleTemplate, data)
+	if err != nil {
+		return nil, fmt.Errorf("generating commit style guide: %w", err)
+	}
+	guide, err := g.write(filepath.Join(dir, CommitStyleFile), content)
+	if err != nil {
+		return nil, fmt.Errorf("generating commit style guide: %w", err)
+	}
+	slog.Info("wrote commit style guide", "path", guide)
+	template, err := g.write(filepath.Join(dir, GitMessageFile), []byte(gitMessage(username, cs)))
+	if err != nil {
+		return nil, fmt.Errorf("generating commit message template: %w", err)
+	}
+	slog.Info("wrote commit message template", "path", template)
+	return []string{guide, template}, nil
+}
+
+// commitStatLines describes the shape of the commit messages, one line per
+// habit.
+func commitStatLines(cs stats.CommitMessageStats) []string {
+	lines := []string{
+		fmt.Sprintf("Subject length: median %d characters, 90%% within %d", cs.SubjectLenMedian, cs.SubjectLenP90),
+		fmt.Sprintf("Imperative mood: %s of subjects", pct(cs.ImperativeRate)),
+		fmt.Sprintf("Capitalized subject: %s; trailing period: %s", pct(cs.CapitalizedRate), pct(cs.TrailingPeriodRate)),
+	}
+	conventional := fmt.Sprintf("Conventional commits: %s, with a scope: %s", pct(cs.ConventionalRate), pct(cs.ScopeRate))
+	if types := cs.Types(); len(types) > 0 {
+		conventional += " (types: " + strings.Join(types, ", ") + ")"
+	}
+	return append(lines,
+		conventional,
+		fmt.Sprintf("Has a body: %s", pct(cs.BodyRate)),
+		fmt.Sprintf("References an issue or PR: %s", pct(cs.IssueRefRate)),
+		fmt.Sprintf("Emoji or gitmoji: %s", pct(cs.EmojiRate)),
+	)
+}
+
+func pct(rate float64) string {
+	return fmt.Sprintf("%.0f%%", rate*100)
+}
+
+// gitMessage returns the commit message template: every line is a "#"
+// comment, which git strips, spelling out the conventions most of the
+// developer's commits follow.
+func gitMessage(username string, cs stats.CommitMessageStats) string {
+	var lines []string
+	add := func(format string, args ...any) { lines = append(lines, fmt.Sprintf(format, args...)) }
+	add("Write this commit message the way %s would; see %s.", username, CommitStyleFile)
+	if cs.Total-cs.Merges <= 0 {
+		return commentLines(lines)
+	}
+
+	subject := "<what the change does>"
+	switch {
+	case cs.ConventionalRate >= majority && cs.ScopeRate >= cs.ConventionalRate/2:
+		subject = "<type>(<scope>): " + subject
+	case cs.ConventionalRate >= majority:
+		subject = "<type>: " + subject
+	}
+	if cs.EmojiRate >= majority {
+		subject = "<gitmoji> " + subject
+	}
+	add("")
+	add("Subject: %s", subject)
+	if types := cs.Types(); cs.ConventionalRate >= majority && len(types) > 0 {
+		add("  Types they use, most used first: %s", strings.Join(types, ", "))
+	}
+	add("  At most %d characters; %d is typical", cs.SubjectLenP90, cs.SubjectLenMedian)
+	if cs.ImperativeRate >= majority {
+		add("  Imperative mood: \"Add\", not \"Added\" or \"Adds\"")
+	}
+	if cs.CapitalizedRate >= majority {
+		add("  Start with a capital letter")
+	} else {
+		add("  Start in lowercase")
+	}
+	if cs.TrailingPeriodRate >= majority {
+		add("  End with a period")
+	} else {
+		add("  No trailing period")
+	}
+
+	add("")
+	if cs.BodyRate >= majority {
+		add("Body: after a blank line, explain what changed and why (%s of their commits have one)", pct(cs.BodyRate))
+	} else {
+		add("Body: optional; only %s of their commits have one, for changes the subject cannot explain", pct(cs.BodyRate))
+	}
+	if cs.IssueRefRate >= 0.2 {
+		add("")
+		add("References: link the issue or PR, such as \"Fixes #123\" (%s of their commits do)", pct(cs.IssueRefRate))
+	}
+	return commentLines(lines)
+}
+
+// commentLines prefixes each line with "# ", or "#" when it is empty.
+func commentLines(lines []string) string {
+	var b strings.Builder
+	for _, line := range lines {
+		b.WriteString(strings.TrimRight("# "+line, " "))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
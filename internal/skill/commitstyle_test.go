@@ -0,0 +1,89 @@
+package skill
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/drpaneas/devlica/internal/analyzer"
+	"github.com/drpaneas/devlica/internal/stats"
+)
+
+func TestGitMessage(t *testing.T) {
+	cs := stats.CommitMessageStats{
+		Total:             12,
+		Merges:            2,
+		SubjectLenMedian:  42,
+		SubjectLenP90:     60,
+		ConventionalRate:  0.9,
+		ConventionalTypes: map[string]int{"fix": 3, "feat": 5},
+		ScopeRate:         0.6,
+		ImperativeRate:    0.8,
+		BodyRate:          0.3,
+		IssueRefRate:      0.4,
+	}
+	got := gitMessage("octocat", cs)
+	for _, s := range []string{
+		"# Subject: <type>(<scope>): <what the change does>\n",
+		"#   Types they use, most used first: feat, fix\n",
+		"#   At most 60 characters; 42 is typical\n",
+		"#   Imperative mood",
+		"#   Start in lowercase\n",
+		"#   No trailing period\n",
+		"# Body: optional; only 30% of their commits have one",
+		"# References: link the issue or PR",
+	} {
+		if !strings.Contains(got, s) {
+			t.Errorf("gitMessage() is missing %q:\n%s", s, got)
+		}
+	}
+	for _, line := range strings.Split(strings.TrimSuffix(got, "\n"), "\n") {
+		if !strings.HasPrefix(line, "#") {
+			t.Errorf("line %q is not a comment, so git would keep it in the message", line)
+		}
+	}
+
+	if got := gitMessage("octocat", stats.CommitMessageStats{}); strings.Count(got, "\n") != 1 {
+		t.Errorf("without statistics the template should only point at the guide:\n%s", got)
+	}
+}
+
+func TestGenerate_CommitStyle(t *testing.T) {
+	dir := t.TempDir()
+	gen := NewGenerator(dir)
+	gen.SetFormats([]Format{FormatCommitStyle})
+	persona := &analyzer.Persona{
+		Synthesis:   &analyzer.SynthesisResult{CommitMessageStyle: "Imperative subjects, wrapped bodies."},
+		CommitStats: stats.CommitMessageStats{Total: 5, SubjectLenMedian: 40, SubjectLenP90: 50, BodyRate: 1},
+	}
+	paths, err := gen.Generate("testdev", persona)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{filepath.Join(dir, "testdev", CommitStyleFile), filepath.Join(dir, "testdev", GitMessageFile)}
+	if strings.Join(paths, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("paths = %v, want %v", paths, want)
+	}
+	guide, err := os.ReadFile(want[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{"Measured over 5 commits", "- Has a body: 100%", "## Guide\n\nImperative subjects, wrapped bodies."} {
+		if !strings.Contains(string(guide), s) {
+			t.Errorf("COMMIT_STYLE.md is missing %q:\n%s", s, guide)
+		}
+	}
+	template, err := os.ReadFile(want[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(template), hashMarkers.begin+"\n") || strings.Contains(string(template), "<!--") {
+		t.Errorf(".gitmessage should use # markers, which git strips:\n%s", template)
+	}
+
+	paths, err = gen.Generate("testdev", &analyzer.Persona{Synthesis: &analyzer.SynthesisResult{}, CommitMessages: "Insufficient data for commit message analysis."})
+	if err != nil || len(paths) != 0 {
+		t.Errorf("Generate() = %v, %v; want nothing without commit data", paths, err)
+	}
+}
@@ -0,0 +1,72 @@
+package skill
+
+import (
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"strings"
+
+	"github.com/drpaneas/devlica/internal/analyzer"
+)
+
+type continueLanguageData struct {
+	Username string
+	Language string
+	Globs    []string
+	Rules    string
+}
+
+// generateContinue writes Continue rules under .continue/rules: a coding
+// style rule that always applies, a code review rule Continue pulls in when
+// the request matches its description, and one rule per language scoped to
+// that language's files by glob.
+func (g *Generator) generateContinue(username string, persona *analyzer.Persona) ([]string, error) {
+	dir := filepath.Join(g.outputDir, username, ".continue", "rules")
+	data := newAgentsData(username, persona)
+
+	rules := []struct {
+		name, tmpl string
+		skip       bool
+	}{
+		{name: SkillCodingStyle, tmpl: continueCodingStyleTemplate},
+		{name: SkillCodeReviewer, tmpl: continueReviewTemplate, skip: data.ReviewPriorities == "" && data.ReviewDecision == "" && data.ReviewNits == "" && data.ReviewVoice == ""},
+	}
+	var paths []string
+	for _, r := range rules {
+		if r.skip {
+			continue
+		}
+		content, err := render("continue-"+r.name, r.tmpl, data)
+		if err != nil {
+			return nil, fmt.Errorf("generating Continue %s rule: %w", r.name, err)
+		}
+		path, err := g.write(filepath.Join(dir, username+"-"+r.name+".md"), content)
+		if err != nil {
+			return nil, fmt.Errorf("generating Continue %s rule: %w", r.name, err)
+		}
+		slog.Info("wrote Continue rule", "path", path)
+		paths = append(paths, path)
+	}
+
+	for _, ls := range persona.LanguageStyles {
+		exts := analyzer.LanguageExtensions(ls.Language)
+		if len(exts) == 0 {
+			continue
+		}
+		langData := continueLanguageData{Username: username, Language: ls.Language, Rules: strings.TrimSpace(ls.Rules)}
+		for _, ext := range exts {
+			langData.Globs = append(langData.Globs, "**/*"+ext)
+		}
+		content, err := render("continue-language", continueLanguageTemplate, langData)
+		if err != nil {
+			return nil, fmt.Errorf("generating Continue %s rule: %w", ls.Language, err)
+		}
+		path, err := g.write(filepath.Join(dir, username+"-"+languageSlug(ls.Language)+".md"), content)
+		if err != nil {
+			return nil, fmt.Errorf("generating Continue %s rule: %w", ls.Language, err)
+		}
+		slog.Info("wrote Continue rule", "path", path, "globs", strings.Join(langData.Globs, ","))
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
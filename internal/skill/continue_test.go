@@ -0,0 +1,82 @@
+package skill
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/drpaneas/devlica/internal/analyzer"
+)
+
+func TestGenerate_Continue(t *testing.T) {
+	dir := t.TempDir()
+	gen := NewGenerator(dir)
+	gen.SetFormats([]Format{FormatContinue})
+	persona := &analyzer.Persona{
+		Synthesis: &analyzer.SynthesisResult{
+			CodeStyleRules:     "- Wrap errors with context",
+			CommitMessageStyle: "Imperative subject lines.",
+		},
+		LanguageStyles: []analyzer.LanguageStyle{
+			{Language: "TypeScript", Rules: "- Prefer type over interface"},
+			{Language: "Brainfuck", Rules: "- unknown extension"},
+		},
+	}
+
+	paths, err := gen.Generate("testdev", persona)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rules := filepath.Join(dir, "testdev", ".continue", "rules")
+	want := []string{
+		filepath.Join(rules, "testdev-coding-style.md"),
+		filepath.Join(rules, "testdev-typescript.md"),
+	}
+	if strings.Join(paths, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("paths = %v, want %v (no review rule without review data)", paths, want)
+	}
+
+	style, err := os.ReadFile(want[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{"name: testdev coding style\n", "alwaysApply: true\n---\n", "## Style Rules\n\n- Wrap errors with context", "## Commit Messages\n\nImperative subject lines."} {
+		if !strings.Contains(string(style), s) {
+			t.Errorf("coding style rule is missing %q:\n%s", s, style)
+		}
+	}
+	if strings.Contains(string(style), "## Testing") {
+		t.Errorf("empty sections should be left out:\n%s", style)
+	}
+
+	lang, err := os.ReadFile(want[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(lang), "globs:\n  - \"**/*.ts\"\n  - \"**/*.tsx\"\n---\n") || !strings.Contains(string(lang), "- Prefer type over interface") {
+		t.Errorf("typescript rule =\n%s", lang)
+	}
+}
+
+func TestGenerate_ContinueReview(t *testing.T) {
+	dir := t.TempDir()
+	gen := NewGenerator(dir)
+	gen.SetFormats([]Format{FormatContinue})
+	persona := &analyzer.Persona{
+		ReviewStyle: "Focuses on error handling.",
+		Synthesis:   &analyzer.SynthesisResult{ReviewVoice: "Short and direct."},
+	}
+	if _, err := gen.Generate("testdev", persona); err != nil {
+		t.Fatal(err)
+	}
+	content, err := os.ReadFile(filepath.Join(dir, "testdev", ".continue", "rules", "testdev-code-reviewer.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{"alwaysApply: false\n", "## Priorities\n\nFocuses on error handling.", "## Feedback Style\n\nShort and direct."} {
+		if !strings.Contains(string(content), s) {
+			t.Errorf("code review rule is missing %q:\n%s", s, content)
+		}
+	}
+}
@@ -0,0 +1,183 @@
+package skill
+
+import (
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"strings"
+
+	"github.com/drpaneas/devlica/internal/analyzer"
+)
+
+// copilotMaxChars bounds each Copilot instruction file. Copilot code review
+// reads only the first 4,000 characters of one, and in chat a longer file
+// takes context from the code without adding much.
+const copilotMaxChars = 4000
+
+// copilotSection is one section of the repository-wide instructions.
+type copilotSection struct {
+	Title string
+	Body  string
+}
+
+type copilotData struct {
+	Username string
+	// Sparse is the caveat for a sparse profile, or "".
+	Sparse string
+	// Sections are in priority order: the last is trimmed first.
+	Sections []copilotSection
+}
+
+type copilotPathData struct {
+	Username string
+	Language string
+	ApplyTo  string
+	Rules    string
+}
+
+// generateCopilot writes .github/copilot-instructions.md, and one
+// path-scoped .github/instructions file per language style, under the
+// user's output directory, ready to copy into a repository.
+func (g *Generator) generateCopilot(username string, persona *analyzer.Persona) ([]string, error) {
+	dir := filepath.Join(g.outputDir, username, ".github")
+	data := copilotData{Username: username, Sparse: sparseCaveat(persona.Sparse), Sections: copilotSections(persona, false)}
+	content, err := renderCopilot(data, "copilot", copilotMaxChars)
+	if err != nil {
+		return nil, fmt.Errorf("generating Copilot instructions: %w", err)
+	}
+	path, err := g.write(filepath.Join(dir, "copilot-instructions.md"), content)
+	if err != nil {
+		return nil, fmt.Errorf("generating Copilot instructions: %w", err)
+	}
+	slog.Info("wrote Copilot instructions", "path", path)
+	paths := []string{path}
+
+	for _, ls := range persona.LanguageStyles {
+		exts := analyzer.LanguageExtensions(ls.Language)
+		if len(exts) == 0 {
+			continue
+		}
+		globs := make([]string, len(exts))
+		for i, ext := range exts {
+			globs[i] = "**/*" + ext
+		}
+		pathData := copilotPathData{Username: username, Language: ls.Language, ApplyTo: strings.Join(globs, ",")}
+		header, err := render("copilot-path", copilotPathTemplate, pathData)
+		if err != nil {
+			return nil, fmt.Errorf("generating Copilot %s instructions: %w", ls.Language, err)
+		}
+		pathData.Rules = cutLines(strings.TrimSpace(ls.Rules), copilotMaxChars-markerOverhead-len(header))
+		content, err := render("copilot-path", copilotPathTemplate, pathData)
+		if err != nil {
+			return nil, fmt.Errorf("generating Copilot %s instructions: %w", ls.Language, err)
+		}
+		path, err := g.write(filepath.Join(dir, "instructions", languageSlug(ls.Language)+".instructions.md"), content)
+		if err != nil {
+			return nil, fmt.Errorf("generating Copilot %s instructions: %w", ls.Language, err)
+		}
+		slog.Info("wrote Copilot instructions", "path", path, "applies_to", pathData.ApplyTo)
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
+// copilotSections returns the non-empty sections of a single-file
+// instruction set in priority order. With languages, each language style
+// follows the never-do rules as a section of its own, for tools that have no
+// path-scoped files.
+func copilotSections(persona *analyzer.Persona, languages bool) []copilotSection {
+	s := persona.Synthesis
+	codeStyle := s.CodeStyleRules
+	if codeStyle == "" {
+		codeStyle = persona.CodeStyle
+	}
+	all := []copilotSection{
+		{"Code Style", codeStyle},
+		{"Never Do", s.AntiPatterns},
+	}
+	if languages {
+		for _, ls := range persona.LanguageStyles {
+			all = append(all, copilotSection{ls.Language, ls.Rules})
+		}
+	}
+	all = append(all,
+		copilotSection{"Coding Philosophy", s.CodingPhilosophy},
+		copilotSection{"Testing", s.TestingPhilosophy},
+		copilotSection{"Commit Messages", s.CommitMessageStyle},
+		copilotSection{"Reviewing Pull Requests", strings.TrimSpace(s.ReviewPriorities + "\n\n" + s.ReviewVoice)},
+		copilotSection{"Project Patterns", s.ProjectPatterns},
+	)
+	var sections []copilotSection
+	for _, sec := range all {
+		if sec.Body = strings.TrimSpace(sec.Body); sec.Body != "" {
+			sections = append(sections, sec)
+		}
+	}
+	return sections
+}
+
+// renderCopilot renders a single-file instruction set, named name in the
+// log, within maxChars, trimming the lowest-priority sections line by line
+// and dropping those left empty.
+func renderCopilot(data copilotData, name string, maxChars int) ([]byte, error) {
+	full := len(data.Sections)
+	trimmed := false
+	for {
+		content, err := render(name, copilotTemplate, data)
+		if err != nil {
+			return nil, err
+		}
+		over := len(content) + markerOverhead - maxChars
+		if over <= 0 || len(data.Sections) == 0 {
+			if trimmed {
+				slog.Info("trimmed instructions to fit", "file", name, "max_chars", maxChars, "sections_kept", len(data.Sections), "of", full)
+			}
+			return content, nil
+		}
+		trimmed = true
+		last := &data.Sections[len(data.Sections)-1]
+		if last.Body = cutLines(last.Body, len(last.Body)-over); last.Body == "" {
+			data.Sections = data.Sections[:len(data.Sections)-1]
+		}
+	}
+}
+
+// cutLines returns the longest run of s's leading lines that fits in max
+// bytes. When not even the first line fits, as with a single paragraph, it
+// cuts that line at a word and marks the cut with "...".
+func cutLines(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	if max <= 0 {
+		return ""
+	}
+	if cut := strings.LastIndexByte(s[:max+1], '\n'); cut > 0 {
+		return strings.TrimRight(s[:cut], "\n")
+	}
+	if max <= len("...") {
+		return ""
+	}
+	cut := strings.LastIndexByte(s[:max-len("...")+1], ' ')
+	if cut <= 0 {
+		return ""
+	}
+	return s[:cut] + "..."
+}
+
+// languageSlug names a language's instruction file and skill, such as
+// "cpp" for C++. It keeps only lowercase letters, digits, and single
+// hyphens, which skill names allow.
+func languageSlug(language string) string {
+	r := strings.NewReplacer("+", "p", "#", "sharp", " ", "-")
+	var b strings.Builder
+	for _, c := range r.Replace(strings.ToLower(language)) {
+		switch {
+		case c >= 'a' && c <= 'z', c >= '0' && c <= '9':
+			b.WriteRune(c)
+		case c == '-' && b.Len() > 0 && !strings.HasSuffix(b.String(), "-"):
+			b.WriteRune(c)
+		}
+	}
+	return strings.TrimSuffix(b.String(), "-")
+}
@@ -0,0 +1,120 @@
+package skill
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/drpaneas/devlica/internal/analyzer"
+)
+
+func TestGenerate_Copilot(t *testing.T) {
+	dir := t.TempDir()
+	gen := NewGenerator(dir)
+	gen.SetFormats([]Format{FormatCopilot})
+	persona := &analyzer.Persona{
+		Synthesis: &analyzer.SynthesisResult{
+			CodeStyleRules:   "- Wrap errors with context",
+			AntiPatterns:     "- Never panic in library code",
+			ReviewPriorities: "Correctness first.",
+		},
+		LanguageStyles: []analyzer.LanguageStyle{
+			{Language: "TypeScript", Rules: "- Prefer type over interface"},
+			{Language: "Brainfuck", Rules: "- unknown extension"},
+		},
+	}
+
+	paths, err := gen.Generate("testdev", persona)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		filepath.Join(dir, "testdev", ".github", "copilot-instructions.md"),
+		filepath.Join(dir, "testdev", ".github", "instructions", "typescript.instructions.md"),
+	}
+	if strings.Join(paths, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("paths = %v, want %v", paths, want)
+	}
+
+	content, err := os.ReadFile(want[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{"# Coding like testdev", "## Code Style\n\n- Wrap errors with context", "## Never Do", "## Reviewing Pull Requests\n\nCorrectness first."} {
+		if !strings.Contains(string(content), s) {
+			t.Errorf("copilot-instructions.md is missing %q:\n%s", s, content)
+		}
+	}
+	if strings.Contains(string(content), "## Testing") {
+		t.Errorf("empty sections should be left out:\n%s", content)
+	}
+
+	scoped, err := os.ReadFile(want[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(scoped), "---\napplyTo: \"**/*.ts,**/*.tsx\"\n---\n") || !strings.Contains(string(scoped), "- Prefer type over interface") {
+		t.Errorf("typescript.instructions.md =\n%s", scoped)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "testdev-coding-style")); !os.IsNotExist(err) {
+		t.Errorf("Cursor skills written without the cursor format: %v", err)
+	}
+}
+
+func TestRenderCopilotTrims(t *testing.T) {
+	line := func(c string) string { return "- " + strings.Repeat(c, 97) + "\n" }
+	data := copilotData{Username: "testdev", Sections: []copilotSection{
+		{"Code Style", strings.Repeat(line("a"), 20)},
+		{"Never Do", strings.Repeat(line("b"), 20)},
+		{"Project Patterns", strings.Repeat(line("c"), 10)},
+	}}
+
+	content, err := renderCopilot(data, "copilot", copilotMaxChars)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(content) > copilotMaxChars {
+		t.Errorf("rendered %d characters, want at most %d", len(content), copilotMaxChars)
+	}
+	if n := strings.Count(string(content), line("a")); n != 20 {
+		t.Errorf("kept %d of 20 code style lines, want the top section whole", n)
+	}
+	if n := strings.Count(string(content), line("b")); n == 0 || n == 20 {
+		t.Errorf("kept %d of 20 never-do lines, want the section trimmed", n)
+	}
+	if strings.Contains(string(content), "Project Patterns") {
+		t.Error("the lowest-priority section should be dropped")
+	}
+}
+
+func TestCutLines(t *testing.T) {
+	tests := []struct {
+		s    string
+		max  int
+		want string
+	}{
+		{"short", 10, "short"},
+		{"one\ntwo\nthree", 9, "one\ntwo"},
+		{"a single long paragraph", 12, "a single..."},
+		{"unbreakable", 5, ""},
+	}
+	for _, tt := range tests {
+		if got := cutLines(tt.s, tt.max); got != tt.want {
+			t.Errorf("cutLines(%q, %d) = %q, want %q", tt.s, tt.max, got, tt.want)
+		}
+	}
+}
+
+func TestParseFormats(t *testing.T) {
+	formats, err := ParseFormats("Copilot, cursor,copilot")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(formats) != 2 || formats[0] != FormatCopilot || formats[1] != FormatCursor {
+		t.Errorf("ParseFormats() = %v, want [copilot cursor]", formats)
+	}
+	if _, err := ParseFormats("vim"); err == nil {
+		t.Error("ParseFormats(vim) should fail")
+	}
+}
@@ -0,0 +1,98 @@
+package skill
+
+import (
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"strings"
+
+	"github.com/drpaneas/devlica/internal/analyzer"
+)
+
+// ExamplesFile is written next to a skill's SKILL.md when the persona
+// carries quoted evidence for it.
+const ExamplesFile = "EXAMPLES.md"
+
+// skillFields maps each Cursor skill to the synthesis fields it is built
+// from, which pick the examples quoted for it and its token budget.
+var skillFields = map[string][]string{
+	SkillCodingStyle:         codingStyleFields,
+	SkillCodeReviewer:        reviewerFields,
+	SkillDeveloperProfile:    developerProfileFields,
+	SkillCommitMessageWriter: commitMessageWriterFields,
+	SkillPRAuthor:            prAuthorFields,
+}
+
+type examplesData struct {
+	Username string
+	Skill    string
+	Sections []exampleSection
+}
+
+// exampleSection holds the quoted evidence behind one section of a skill.
+type exampleSection struct {
+	Title    string
+	Examples []exampleEntry
+}
+
+type exampleEntry struct {
+	URL     string
+	Kind    string
+	Context string
+	// Quote is the text as a Markdown blockquote.
+	Quote string
+}
+
+// exampleSections returns the quoted evidence for the given synthesis
+// fields, in order, skipping fields without any.
+func exampleSections(s *analyzer.SynthesisResult, fields []string) []exampleSection {
+	var sections []exampleSection
+	for _, f := range fields {
+		var entries []exampleEntry
+		for _, url := range s.Evidence[f] {
+			if ex, ok := s.Examples[url]; ok {
+				entries = append(entries, exampleEntry{URL: url, Kind: ex.Kind, Context: ex.Context, Quote: blockquote(ex.Text)})
+			}
+		}
+		if len(entries) > 0 {
+			sections = append(sections, exampleSection{Title: evidenceTitles[f], Examples: entries})
+		}
+	}
+	return sections
+}
+
+// hasExamples reports whether any of the fields has quoted evidence, which
+// is when the skill gets an ExamplesFile.
+func hasExamples(s *analyzer.SynthesisResult, fields []string) bool {
+	return len(exampleSections(s, fields)) > 0
+}
+
+// writeExamples writes the ExamplesFile quoting sections next to the named
+// skill, or nothing when there are none, which is always the case for
+// personas saved before examples were recorded. It returns the path
+// written, or "".
+func (g *Generator) writeExamples(username, name string, sections []exampleSection) (string, error) {
+	if len(sections) == 0 {
+		return "", nil
+	}
+	content, err := render("examples", examplesTemplate, examplesData{Username: username, Skill: name, Sections: sections})
+	if err != nil {
+		return "", fmt.Errorf("generating %s examples: %w", name, err)
+	}
+	path, err := g.write(filepath.Join(g.skillDir(name), ExamplesFile), content)
+	if err != nil {
+		return "", fmt.Errorf("generating %s examples: %w", name, err)
+	}
+	slog.Info("wrote skill examples", "path", path)
+	return path, nil
+}
+
+// blockquote renders s as a Markdown blockquote, so headings and fences in
+// the quoted text cannot break the file around it.
+func blockquote(s string) string {
+	lines := strings.Split(strings.TrimSpace(s), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight("> "+line, " ")
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,60 @@
+package skill
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/drpaneas/devlica/internal/analyzer"
+)
+
+func TestGenerate_Examples(t *testing.T) {
+	dir := t.TempDir()
+	commit := "https://github.com/a/b/commit/1"
+	review := "https://github.com/a/b/pull/3#discussion_r4"
+	persona := &analyzer.Persona{
+		Synthesis: &analyzer.SynthesisResult{
+			Evidence: map[string][]string{
+				"code_style_rules": {commit, "https://github.com/a/b/commit/2"},
+				"review_voice":     {review},
+			},
+			Examples: map[string]analyzer.Example{
+				commit: {Kind: "commit message", Text: "fix: close body\n\n## Why\nIt leaked.", Context: "a/b"},
+				review: {Kind: "review comment", Text: "Wrap this error.", Context: "a/b#3 (Add retries), client.go"},
+			},
+		},
+	}
+	paths, err := NewGenerator(dir).Generate("testdev", persona)
+	if err != nil {
+		t.Fatalf("Generate() error: %v", err)
+	}
+	csExamples := filepath.Join(dir, "testdev-coding-style", ExamplesFile)
+	rvExamples := filepath.Join(dir, "testdev-code-reviewer", ExamplesFile)
+	got := strings.Join(paths, "\n")
+	if !strings.Contains(got, csExamples) || !strings.Contains(got, rvExamples) {
+		t.Errorf("paths = %v, want both examples files", paths)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "testdev-developer-profile", ExamplesFile)); !os.IsNotExist(err) {
+		t.Error("developer profile has no quoted evidence, so it should get no examples file")
+	}
+
+	content, err := os.ReadFile(csExamples)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "## Code Style Rules\n\n**commit message** in a/b ([source](" + commit + "))\n\n> fix: close body\n>\n> ## Why\n> It leaked.\n"
+	if !strings.Contains(string(content), want) {
+		t.Errorf("coding style examples should quote the commit:\n%s", content)
+	}
+	if strings.Contains(string(content), "commit/2") {
+		t.Error("evidence without quoted text should be left out of the examples")
+	}
+	skill, err := os.ReadFile(filepath.Join(dir, "testdev-coding-style", "SKILL.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(skill), "EXAMPLES.md next to this file quotes it.") {
+		t.Errorf("SKILL.md should point at its examples:\n%s", skill)
+	}
+}
@@ -0,0 +1,75 @@
+package skill
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Format names a coding assistant whose instruction files are generated,
+// or a document for the developer's team.
+type Format string
+
+// The supported output formats.
+const (
+	// FormatCursor writes Cursor skills, one SKILL.md per skill.
+	FormatCursor Format = "cursor"
+	// FormatCopilot writes GitHub Copilot custom instructions.
+	FormatCopilot Format = "copilot"
+	// FormatAgents writes one consolidated AGENTS.md.
+	FormatAgents Format = "agents"
+	// FormatClaude writes the same consolidated file as CLAUDE.md.
+	FormatClaude Format = "claude"
+	// FormatContinue writes Continue rules under .continue/rules.
+	FormatContinue Format = "continue"
+	// FormatAider writes a CONVENTIONS.md for Aider to read.
+	FormatAider Format = "aider"
+	// FormatWindsurf writes Windsurf workspace rules as .windsurfrules.
+	FormatWindsurf Format = "windsurf"
+	// FormatChecklist writes a CHECKLIST.md of the review priorities.
+	FormatChecklist Format = "checklist"
+	// FormatCommitStyle writes a COMMIT_STYLE.md guide and a .gitmessage
+	// commit template.
+	FormatCommitStyle Format = "commit-style"
+	// FormatPRTemplate writes a .github/PULL_REQUEST_TEMPLATE.md.
+	FormatPRTemplate Format = "pr-template"
+	// FormatCard writes a one-page PERSONA.md summary for people.
+	FormatCard Format = "card"
+	// FormatCardHTML writes the same summary as a PERSONA.html page.
+	FormatCardHTML Format = "card-html"
+	// FormatClaudePlugin packages the skills as a Claude Code plugin with
+	// a marketplace to install it from.
+	FormatClaudePlugin Format = "claude-plugin"
+)
+
+// Formats lists every supported format.
+var Formats = []Format{FormatCursor, FormatCopilot, FormatAgents, FormatClaude, FormatContinue, FormatAider, FormatWindsurf, FormatChecklist, FormatCommitStyle, FormatPRTemplate, FormatCard, FormatCardHTML, FormatClaudePlugin}
+
+// ParseFormats parses a comma-separated list of formats, such as
+// "cursor,copilot".
+func ParseFormats(s string) ([]Format, error) {
+	var formats []Format
+	for _, f := range strings.Split(s, ",") {
+		format := Format(strings.ToLower(strings.TrimSpace(f)))
+		if !slices.Contains(Formats, format) {
+			return nil, fmt.Errorf("unknown format %q: must be one of %s", f, formatList())
+		}
+		if !slices.Contains(formats, format) {
+			formats = append(formats, format)
+		}
+	}
+	return formats, nil
+}
+
+func formatList() string {
+	names := make([]string, len(Formats))
+	for i, f := range Formats {
+		names[i] = string(f)
+	}
+	return strings.Join(names, ", ")
+}
+
+// FormatUsage describes the -format flag, listing the supported formats.
+func FormatUsage() string {
+	return "Comma-separated output formats: " + formatList() + " (default cursor)"
+}
@@ -0,0 +1,85 @@
+package skill
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// FrontmatterField is an extra key written into the frontmatter of every
+// skill, such as license or allowed-tools.
+type FrontmatterField struct {
+	Key   string
+	Value string
+}
+
+// frontmatterKeyPattern is the form of an extra frontmatter key.
+var frontmatterKeyPattern = regexp.MustCompile(`^[a-z][a-z0-9_-]*$`)
+
+// generatedFrontmatterKeys are written by devlica and cannot be set.
+var generatedFrontmatterKeys = []string{"name", "description", "metadata", "confidence"}
+
+// frontmatterListKeys take a comma-separated value and are written as a
+// YAML list.
+var frontmatterListKeys = []string{"tags"}
+
+// ParseFrontmatterField parses "key=value", such as "license=MIT" or
+// "tags=go,review".
+func ParseFrontmatterField(s string) (FrontmatterField, error) {
+	key, value, ok := strings.Cut(s, "=")
+	key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+	switch {
+	case !ok || value == "":
+		return FrontmatterField{}, fmt.Errorf("frontmatter field %q must be key=value", s)
+	case !frontmatterKeyPattern.MatchString(key):
+		return FrontmatterField{}, fmt.Errorf("frontmatter key %q must be lowercase letters, digits, hyphens, and underscores", key)
+	case slices.Contains(generatedFrontmatterKeys, key):
+		return FrontmatterField{}, fmt.Errorf("frontmatter key %q is written by devlica and cannot be set", key)
+	}
+	return FrontmatterField{Key: key, Value: value}, nil
+}
+
+// SetFrontmatter adds fields to the frontmatter of every skill, after the
+// keys devlica writes. A key given twice keeps its last value.
+func (g *Generator) SetFrontmatter(fields []FrontmatterField) {
+	g.frontmatter = nil
+	for _, f := range fields {
+		g.frontmatter = slices.DeleteFunc(g.frontmatter, func(e FrontmatterField) bool { return e.Key == f.Key })
+		g.frontmatter = append(g.frontmatter, f)
+	}
+}
+
+// withFrontmatter appends fields to the frontmatter of content, a rendered
+// SKILL.md.
+func withFrontmatter(content []byte, fields []FrontmatterField) []byte {
+	fm, body := splitFrontmatter(string(content))
+	if len(fields) == 0 || fm == "" {
+		return content
+	}
+	var b strings.Builder
+	b.WriteString(strings.TrimSuffix(fm, "---\n"))
+	for _, f := range fields {
+		fmt.Fprintf(&b, "%s: %s\n", f.Key, frontmatterValue(f))
+	}
+	b.WriteString("---\n")
+	b.WriteString(body)
+	return []byte(b.String())
+}
+
+// frontmatterValue renders the value of f as YAML: a flow list of quoted
+// strings for the frontmatterListKeys, and a quoted string otherwise, as
+// provenanceEntry values are.
+func frontmatterValue(f FrontmatterField) string {
+	if !slices.Contains(frontmatterListKeys, f.Key) {
+		return strconv.Quote(f.Value)
+	}
+	var items []string
+	for _, item := range strings.Split(f.Value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, strconv.Quote(item))
+		}
+	}
+	return "[" + strings.Join(items, ", ") + "]"
+}
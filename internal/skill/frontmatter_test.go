@@ -0,0 +1,68 @@
+package skill
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/drpaneas/devlica/internal/analyzer"
+)
+
+func TestParseFrontmatterField(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    FrontmatterField
+		wantErr bool
+	}{
+		{"license=MIT", FrontmatterField{"license", "MIT"}, false},
+		{" allowed-tools = Read, Grep ", FrontmatterField{"allowed-tools", "Read, Grep"}, false},
+		{"version=", FrontmatterField{}, true},
+		{"license", FrontmatterField{}, true},
+		{"License=MIT", FrontmatterField{}, true},
+		{"name=other", FrontmatterField{}, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseFrontmatterField(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseFrontmatterField(%q) = %+v, %v; want %+v, error %v", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestGenerate_Frontmatter(t *testing.T) {
+	dir := t.TempDir()
+	gen := NewGenerator(dir)
+	gen.SetSkills([]string{SkillCodeReviewer})
+	gen.SetSkillData([]DataFormat{DataJSON})
+	gen.SetFrontmatter([]FrontmatterField{
+		{"license", "Apache-2.0"},
+		{"tags", "go, review"},
+		{"license", "MIT"},
+	})
+	if _, err := gen.Generate("testdev", &analyzer.Persona{Synthesis: &analyzer.SynthesisResult{}}); err != nil {
+		t.Fatal(err)
+	}
+	skillDir := filepath.Join(dir, "testdev-code-reviewer")
+	content, err := os.ReadFile(filepath.Join(skillDir, SkillFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	fm, _ := splitFrontmatter(string(content))
+	if !strings.HasSuffix(fm, "\ntags: [\"go\", \"review\"]\nlicense: \"MIT\"\n---\n") {
+		t.Errorf("frontmatter should end with the extra keys, the last value of a repeated one winning:\n%s", fm)
+	}
+	if strings.Contains(fm, "Apache") {
+		t.Errorf("a repeated key should be written once:\n%s", fm)
+	}
+
+	var doc SkillDocument
+	readJSON(t, filepath.Join(skillDir, SkillJSONFile), &doc)
+	tags, _ := doc.Frontmatter["tags"].([]any)
+	if doc.Frontmatter["license"] != "MIT" || len(tags) != 2 || tags[1] != "review" {
+		t.Errorf("skill.json frontmatter = %v", doc.Frontmatter)
+	}
+	if len(doc.Frontmatter) != 2 {
+		t.Errorf("skill.json frontmatter = %v, want only the extra keys", doc.Frontmatter)
+	}
+}
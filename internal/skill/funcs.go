@@ -0,0 +1,129 @@
+package skill
+
+import (
+	"regexp"
+	"strings"
+	"text/template"
+)
+
+// templateFuncs are the helpers every template can call to fit synthesized
+// Markdown, whose shape is up to the model, into the file around it.
+var templateFuncs = template.FuncMap{
+	"list":     normalizeList,
+	"demote":   demoteHeadings,
+	"truncate": truncateMarkdown,
+	"quote":    blockquote,
+	// skill names one of the user's skills, as in {{skill .Username "coding-style"}}.
+	"skill": skillName,
+}
+
+// headingPattern matches an ATX heading and captures its hashes.
+var headingPattern = regexp.MustCompile(`^(#{1,6})(\s|$)`)
+
+// listMarkerPattern matches a list item's marker, after any indentation.
+var listMarkerPattern = regexp.MustCompile(`^(\s*)(?:[*+]|(\d+)\))(\s+)`)
+
+// isFence reports whether line opens or closes a fenced code block.
+func isFence(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	return strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~")
+}
+
+// mapLines calls fn on each line of s outside fenced code blocks and
+// returns s with the lines fn returns.
+func mapLines(s string, fn func(line string) string) string {
+	lines := strings.Split(s, "\n")
+	fenced := false
+	for i, line := range lines {
+		if isFence(line) {
+			fenced = !fenced
+			continue
+		}
+		if !fenced {
+			lines[i] = fn(line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// normalizeList writes every list item in s with "-" bullets or "1."
+// numbers, and puts a blank line between a paragraph and a list right
+// after it, which some renderers otherwise run together.
+func normalizeList(s string) string {
+	s = mapLines(s, func(line string) string {
+		return listMarkerPattern.ReplaceAllStringFunc(line, func(m string) string {
+			sub := listMarkerPattern.FindStringSubmatch(m)
+			if sub[2] != "" {
+				return sub[1] + sub[2] + "." + sub[3]
+			}
+			return sub[1] + "-" + sub[3]
+		})
+	})
+	// prose and inList say what the lines since the last blank one are; a
+	// line of text after an item continues the item.
+	var out []string
+	prose, inList, fenced := false, false, false
+	for _, line := range strings.Split(s, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case isFence(line):
+			fenced = !fenced
+			prose, inList = false, false
+		case fenced:
+		case trimmed == "" || headingPattern.MatchString(trimmed):
+			prose, inList = false, false
+		case listItemPattern.MatchString(trimmed):
+			if prose {
+				out = append(out, "")
+			}
+			prose, inList = false, true
+		case !inList:
+			prose = true
+		}
+		out = append(out, line)
+	}
+	return strings.Join(out, "\n")
+}
+
+// demoteHeadings pushes the headings in s down so none is above level,
+// keeping their levels relative to each other, as for a section that sits
+// under a level-1 heading. Headings cannot go below level 6.
+func demoteHeadings(level int, s string) string {
+	top := 7
+	mapLines(s, func(line string) string {
+		if m := headingPattern.FindStringSubmatch(line); m != nil {
+			top = min(top, len(m[1]))
+		}
+		return line
+	})
+	shift := level - top
+	if top == 7 || shift <= 0 {
+		return s
+	}
+	return mapLines(s, func(line string) string {
+		m := headingPattern.FindStringSubmatch(line)
+		if m == nil {
+			return line
+		}
+		return strings.Repeat("#", min(len(m[1])+shift, 6)) + line[len(m[1]):]
+	})
+}
+
+// truncateMarkdown cuts s to at most max bytes at a line, or at a word
+// marked with "...", and closes a code block the cut left open.
+func truncateMarkdown(max int, s string) string {
+	cut := cutLines(s, max)
+	if cut == s {
+		return s
+	}
+	fences := 0
+	for _, line := range strings.Split(cut, "\n") {
+		if isFence(line) {
+			fences++
+		}
+	}
+	if fences%2 == 1 {
+		cut += "\n```"
+	}
+	return cut
+}
@@ -0,0 +1,78 @@
+package skill
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/drpaneas/devlica/internal/analyzer"
+)
+
+func TestNormalizeList(t *testing.T) {
+	tests := []struct {
+		name, in, want string
+	}{
+		{"bullets", "* one\n+ two\n  * nested", "- one\n- two\n  - nested"},
+		{"numbers", "1) one\n2) two", "1. one\n2. two"},
+		{"after paragraph", "Rules:\n- one\n- two", "Rules:\n\n- one\n- two"},
+		{"continued item", "- one\nwrapped\n- two", "- one\nwrapped\n- two"},
+		{"code block", "```\n* not a list\n```", "```\n* not a list\n```"},
+		{"emphasis", "*stress* this", "*stress* this"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := normalizeList(tt.in); got != tt.want {
+				t.Errorf("normalizeList(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDemoteHeadings(t *testing.T) {
+	tests := []struct {
+		name  string
+		level int
+		in    string
+		want  string
+	}{
+		{"shifted", 3, "# Top\n## Sub\ntext", "### Top\n#### Sub\ntext"},
+		{"already deep", 3, "### Deep\n#### Deeper", "### Deep\n#### Deeper"},
+		{"capped", 5, "# a\n#### b", "##### a\n###### b"},
+		{"no headings", 3, "#hashtag and text", "#hashtag and text"},
+		{"code block", 3, "## Go\n```sh\n# comment\n```", "### Go\n```sh\n# comment\n```"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := demoteHeadings(tt.level, tt.in); got != tt.want {
+				t.Errorf("demoteHeadings(%d, %q) = %q, want %q", tt.level, tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTruncateMarkdown(t *testing.T) {
+	if got := truncateMarkdown(100, "short"); got != "short" {
+		t.Errorf("truncateMarkdown() = %q, want text that fits left alone", got)
+	}
+	if got, want := truncateMarkdown(18, "Intro\n```go\nx := 1\ny := 2\n```"), "Intro\n```go\nx := 1\n```"; got != want {
+		t.Errorf("truncateMarkdown() = %q, want %q with the code block closed", got, want)
+	}
+}
+
+func TestGenerate_DemotesSectionHeadings(t *testing.T) {
+	dir := t.TempDir()
+	persona := &analyzer.Persona{Synthesis: &analyzer.SynthesisResult{CodeStyleRules: "## Naming\n\nShort names."}}
+	gen := NewGenerator(dir)
+	gen.SetSkills([]string{SkillCodingStyle})
+	if _, err := gen.Generate("testdev", persona); err != nil {
+		t.Fatal(err)
+	}
+	content, err := os.ReadFile(filepath.Join(dir, "testdev-coding-style", SkillFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(content), "## Code Style Rules\n\n### Naming\n") {
+		t.Errorf("a synthesized heading should sit under its section:\n%s", content)
+	}
+}
@@ -2,33 +2,77 @@ package skill
 
 import (
 	"bytes"
+	"cmp"
 	"fmt"
+	"io"
 	"log/slog"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 	"text/template"
+	"time"
 
 	"github.com/drpaneas/devlica/internal/analyzer"
+	"github.com/drpaneas/devlica/internal/stats"
 )
 
 // Generator writes skill files from analyzed persona data.
 type Generator struct {
 	outputDir string
+	// skillsDir, when set, holds the skill directories instead of
+	// outputDir.
+	skillsDir string
+	// skillData lists the machine-readable files written next to each
+	// SKILL.md.
+	skillData []DataFormat
+	// frontmatter is added to the frontmatter of every skill.
+	frontmatter []FrontmatterField
+	formats     []Format
+	skills      []string
+	update      bool
+	// stream, when set, receives the files instead of outputDir.
+	stream       io.Writer
+	streamFormat StreamFormat
+	streamed     []streamFile
 }
 
-// NewGenerator returns a Generator that writes to outputDir.
+// NewGenerator returns a Generator that writes the DefaultSkills for
+// Cursor to outputDir.
 func NewGenerator(outputDir string) *Generator {
-	return &Generator{outputDir: outputDir}
+	return &Generator{outputDir: outputDir, formats: []Format{FormatCursor}, skills: DefaultSkills}
+}
+
+// SetSkills makes the Cursor format write skills instead of the
+// DefaultSkills. An empty list keeps the default.
+func (g *Generator) SetSkills(skills []string) {
+	if len(skills) > 0 {
+		g.skills = skills
+	}
+}
+
+// SetFormats makes Generate write each of formats instead of only Cursor
+// skills. An empty list keeps the default.
+func (g *Generator) SetFormats(formats []Format) {
+	if len(formats) > 0 {
+		g.formats = formats
+	}
 }
 
 type codingStyleData struct {
 	Username        string
 	Philosophy      string
 	CodeStyle       string
+	LanguageStyles  []analyzer.LanguageStyle
+	CommitMessages  string
 	Testing         string
 	ProjectPatterns string
+	Automation      string
 	CodeExamples    string
+	StyleEvolution  string
 	Traits          string
+	AntiPatterns    string
+	skillMeta
 }
 
 type reviewerData struct {
@@ -38,7 +82,30 @@ type reviewerData struct {
 	ReviewNits         string
 	ReviewContext      string
 	ReviewVoice        string
+	Phrases            []phraseEntry
+	PhraseComments     int
+	AvoidPhrases       string
+	AntiPatterns       string
 	CollaborationStyle string
+	skillMeta
+}
+
+// phraseEntry is one signature phrase as the reviewer skill lists it.
+type phraseEntry struct {
+	Text  string
+	Kind  string
+	Share string
+}
+
+// phraseEntries turns phrase counts into skill entries with their share of
+// comments.
+func phraseEntries(ps stats.PhraseStats) []phraseEntry {
+	entries := make([]phraseEntry, 0, len(ps.Signature))
+	for _, p := range ps.Signature {
+		share := fmt.Sprintf("%.0f%%", 100*float64(p.Comments)/float64(ps.Comments))
+		entries = append(entries, phraseEntry{Text: strconv.Quote(p.Text), Kind: p.Kind, Share: share})
+	}
+	return entries
 }
 
 type developerProfileData struct {
@@ -46,21 +113,272 @@ type developerProfileData struct {
 	DeveloperInterests string
 	ActivityPatterns   string
 	CollaborationStyle string
+	MaintainerBehavior string
 	Traits             string
+	skillMeta
 }
 
-// Generate produces skill files from the analyzed persona and returns their paths.
+// Synthesis fields each skill is built from, in section order.
+var (
+	codingStyleFields = []string{
+		"coding_philosophy", "code_style_rules", "commit_message_style", "testing_philosophy",
+		"project_patterns", "automation", "code_examples", "style_evolution", "distinctive_traits",
+		"anti_patterns",
+	}
+	reviewerFields = []string{
+		"review_priorities", "review_decision_style", "review_non_blocking_nits",
+		"review_context_sensitivity", "review_voice", "anti_patterns", "collaboration_style",
+	}
+	developerProfileFields = []string{
+		"developer_interests", "activity_patterns", "collaboration_style",
+		"maintainer_behavior", "distinctive_traits",
+	}
+)
+
+// skillMeta is what every skill's frontmatter and trailing sections take
+// from the persona beyond its own text.
+type skillMeta struct {
+	Confidence []confidenceEntry
+	Evidence   []evidenceSection
+	// Examples reports whether an ExamplesFile quotes the evidence.
+	Examples   bool
+	Provenance []provenanceEntry
+	// Sparse is the caveat for a sparse profile, or "".
+	Sparse string
+}
+
+// newSkillMeta returns the metadata of a skill built from the given
+// synthesis fields.
+func newSkillMeta(persona *analyzer.Persona, fields []string) skillMeta {
+	s := persona.Synthesis
+	return skillMeta{
+		Confidence: confidenceEntries(s.Confidence, fields),
+		Evidence:   evidenceSections(s.Evidence, fields),
+		Examples:   hasExamples(s, fields),
+		Provenance: provenanceEntries(persona.Metadata),
+		Sparse:     sparseCaveat(persona.Sparse),
+	}
+}
+
+// confidenceEntry is one field's confidence in a skill's frontmatter.
+// Rationale is already quoted for YAML; Go's escapes are a subset of YAML's,
+// so strconv.Quote output is a valid double-quoted scalar.
+type confidenceEntry struct {
+	Field     string
+	Level     string
+	Rationale string
+}
+
+// confidenceEntries returns the confidence for the given synthesis fields,
+// in order, skipping fields without a rating.
+func confidenceEntries(confidence map[string]analyzer.Confidence, fields []string) []confidenceEntry {
+	var entries []confidenceEntry
+	for _, f := range fields {
+		if c, ok := confidence[f]; ok {
+			entries = append(entries, confidenceEntry{Field: f, Level: c.Level, Rationale: strconv.Quote(c.Rationale)})
+		}
+	}
+	return entries
+}
+
+// evidenceSection lists the links backing one section of a skill.
+type evidenceSection struct {
+	Title string
+	URLs  []string
+}
+
+// evidenceTitles names each synthesis field as its skill section does.
+var evidenceTitles = map[string]string{
+	"coding_philosophy":          "Coding Philosophy",
+	"code_style_rules":           "Code Style Rules",
+	"commit_message_style":       "Commit Messages",
+	"testing_philosophy":         "Testing Approach",
+	"project_patterns":           "Project Patterns",
+	"automation":                 "Automation",
+	"code_examples":              "Code Examples",
+	"style_evolution":            "Style Evolution",
+	"distinctive_traits":         "Distinctive Traits",
+	"anti_patterns":              "Never Do",
+	"review_priorities":          "Review Priorities",
+	"review_decision_style":      "Approval Thresholds",
+	"review_non_blocking_nits":   "Non-Blocking Nits",
+	"review_context_sensitivity": "Context Sensitivity",
+	"review_voice":               "Feedback Style",
+	"collaboration_style":        "Collaboration Style",
+	"communication_patterns":     "Communication Patterns",
+	"developer_interests":        "Interests and Focus Areas",
+	"activity_patterns":          "Activity Patterns",
+	"maintainer_behavior":        "Maintainer Behavior",
+}
+
+// evidenceSections returns the evidence for the given synthesis fields, in
+// order, skipping fields without links.
+func evidenceSections(evidence map[string][]string, fields []string) []evidenceSection {
+	var sections []evidenceSection
+	for _, f := range fields {
+		if urls := evidence[f]; len(urls) > 0 {
+			sections = append(sections, evidenceSection{Title: evidenceTitles[f], URLs: urls})
+		}
+	}
+	return sections
+}
+
+// provenanceEntry is one line of a skill's frontmatter metadata. Value is
+// already quoted for YAML, like confidenceEntry.Rationale.
+type provenanceEntry struct {
+	Key   string
+	Value string
+}
+
+// provenanceEntries records how the persona was produced, so stale or
+// mixed-model skills can be spotted. It is empty when nothing was recorded.
+func provenanceEntries(meta analyzer.PersonaMetadata) []provenanceEntry {
+	var entries []provenanceEntry
+	add := func(key, value string) {
+		if value != "" {
+			entries = append(entries, provenanceEntry{Key: key, Value: strconv.Quote(value)})
+		}
+	}
+	stamp := func(t time.Time) string {
+		if t.IsZero() {
+			return ""
+		}
+		return t.UTC().Format(time.RFC3339)
+	}
+	add("generated_by", meta.DevlicaVersion)
+	add("provider", meta.Provider)
+	add("model", meta.Model)
+	add("ensemble_provider", meta.EnsembleProvider)
+	add("ensemble_model", meta.EnsembleModel)
+	add("crawled_at", stamp(meta.CrawledAt))
+	add("generated_at", stamp(meta.GeneratedAt))
+	add("crawl_hash", meta.CrawlHash)
+	if c := meta.DataCounts; c != (analyzer.DataCounts{}) {
+		add("data_counts", fmt.Sprintf("repos=%d commits=%d reviews=%d issue_comments=%d", c.Repos, c.Commits, c.Reviews, c.IssueComments))
+	}
+	return entries
+}
+
+// sparseCaveat renders why a sparse profile's skills are only a sketch, or
+// "" for a normal profile.
+func sparseCaveat(reasons []string) string {
+	if len(reasons) == 0 {
+		return ""
+	}
+	return "Built from little public activity: " + strings.Join(reasons, ", ") + "."
+}
+
+// Generate produces the instruction files of every format from the
+// analyzed persona and returns their paths.
 func (g *Generator) Generate(username string, persona *analyzer.Persona) ([]string, error) {
+	g.streamed = nil
 	var paths []string
-	s := persona.Synthesis
+	for _, f := range g.formats {
+		var written []string
+		var err error
+		switch f {
+		case FormatCursor:
+			written, err = g.generateCursor(username, persona)
+		case FormatCopilot:
+			written, err = g.generateCopilot(username, persona)
+		case FormatAgents:
+			written, err = g.generateAgents(username, persona, "AGENTS.md")
+		case FormatClaude:
+			written, err = g.generateAgents(username, persona, "CLAUDE.md")
+		case FormatContinue:
+			written, err = g.generateContinue(username, persona)
+		case FormatAider:
+			written, err = g.generateAider(username, persona)
+		case FormatWindsurf:
+			written, err = g.generateWindsurf(username, persona)
+		case FormatChecklist:
+			written, err = g.generateChecklist(username, persona)
+		case FormatCommitStyle:
+			written, err = g.generateCommitStyle(username, persona)
+		case FormatPRTemplate:
+			written, err = g.generatePRTemplate(username, persona)
+		case FormatCard:
+			written, err = g.generateCard(username, persona, false)
+		case FormatCardHTML:
+			written, err = g.generateCard(username, persona, true)
+		case FormatClaudePlugin:
+			written, err = g.generateClaudePlugin(username, persona)
+		default:
+			err = fmt.Errorf("unknown format %q", f)
+		}
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, written...)
+	}
+	if g.stream != nil {
+		if err := g.flushStream(); err != nil {
+			return nil, err
+		}
+	}
+	return paths, nil
+}
 
+// generateCursor writes a SKILL.md for each selected skill, with an
+// ExamplesFile next to it when there is quoted evidence, and the
+// language-scoped skills after the coding style skill.
+func (g *Generator) generateCursor(username string, persona *analyzer.Persona) ([]string, error) {
+	var paths []string
+	for _, name := range g.skills {
+		var written []string
+		var err error
+		switch name {
+		case SkillCodingStyle:
+			written, err = g.codingStyleSkill(username, persona)
+		case SkillCodeReviewer:
+			written, err = g.codeReviewerSkill(username, persona)
+		case SkillDeveloperProfile:
+			written, err = g.developerProfileSkill(username, persona)
+		case SkillCommitMessageWriter:
+			written, err = g.commitMessageWriterSkill(username, persona)
+		case SkillPRAuthor:
+			written, err = g.prAuthorSkill(username, persona)
+		default:
+			err = fmt.Errorf("unknown skill %q", name)
+		}
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, written...)
+		sections := exampleSections(persona.Synthesis, skillFields[name])
+		examples, err := g.writeExamples(username, skillName(username, name), sections)
+		if err != nil {
+			return nil, err
+		}
+		if examples != "" {
+			paths = append(paths, examples)
+		}
+		if name == SkillCodingStyle {
+			languages, err := g.languageSkills(username, persona)
+			if err != nil {
+				return nil, err
+			}
+			paths = append(paths, languages...)
+		}
+	}
+	return paths, nil
+}
+
+func (g *Generator) codingStyleSkill(username string, persona *analyzer.Persona) ([]string, error) {
+	s := persona.Synthesis
 	csData := codingStyleData{
 		Username:        username,
 		Philosophy:      s.CodingPhilosophy,
 		CodeStyle:       s.CodeStyleRules,
+		LanguageStyles:  persona.LanguageStyles,
+		CommitMessages:  s.CommitMessageStyle,
 		Testing:         s.TestingPhilosophy,
 		ProjectPatterns: s.ProjectPatterns,
+		Automation:      s.Automation,
+		StyleEvolution:  s.StyleEvolution,
 		Traits:          s.DistinctiveTraits,
+		AntiPatterns:    s.AntiPatterns,
+		skillMeta:       newSkillMeta(persona, codingStyleFields),
 	}
 	if csData.CodeStyle == "" {
 		csData.CodeStyle = persona.CodeStyle
@@ -68,26 +386,41 @@ func (g *Generator) Generate(username string, persona *analyzer.Persona) ([]stri
 	if csData.Philosophy == "" {
 		csData.Philosophy = "See code style rules below."
 	}
+	if csData.CommitMessages == "" {
+		csData.CommitMessages = "No specific commit message data was identified."
+	}
 	if csData.Testing == "" {
 		csData.Testing = "No specific testing data was identified."
 	}
 	if csData.ProjectPatterns == "" {
 		csData.ProjectPatterns = "No specific project pattern data was identified."
 	}
+	if csData.Automation == "" {
+		csData.Automation = "No specific automation data was identified."
+	}
 	csData.CodeExamples = s.CodeExamples
 	if csData.CodeExamples == "" {
 		csData.CodeExamples = "No representative code examples were identified."
 	}
+	if csData.StyleEvolution == "" {
+		csData.StyleEvolution = "No style-evolution data was identified."
+	}
 	if csData.Traits == "" {
 		csData.Traits = "See code style rules above."
 	}
+	if csData.AntiPatterns == "" {
+		csData.AntiPatterns = "No specific anti-pattern data was identified."
+	}
 
-	csPath, err := g.writeSkill(username+"-coding-style", codingStyleTemplate, csData)
+	paths, err := g.writeSkill(skillName(username, SkillCodingStyle), codingStyleTemplate, csData)
 	if err != nil {
 		return nil, fmt.Errorf("generating coding style skill: %w", err)
 	}
-	paths = append(paths, csPath)
+	return paths, nil
+}
 
+func (g *Generator) codeReviewerSkill(username string, persona *analyzer.Persona) ([]string, error) {
+	s := persona.Synthesis
 	rvData := reviewerData{
 		Username:           username,
 		ReviewPriorities:   s.ReviewPriorities,
@@ -95,7 +428,11 @@ func (g *Generator) Generate(username string, persona *analyzer.Persona) ([]stri
 		ReviewNits:         s.ReviewNonBlockingNits,
 		ReviewContext:      s.ReviewContext,
 		ReviewVoice:        s.ReviewVoice,
+		Phrases:            phraseEntries(persona.Phrases),
+		PhraseComments:     persona.Phrases.Comments,
+		AntiPatterns:       s.AntiPatterns,
 		CollaborationStyle: s.CollaborationStyle,
+		skillMeta:          newSkillMeta(persona, reviewerFields),
 	}
 	if rvData.ReviewPriorities == "" {
 		rvData.ReviewPriorities = persona.ReviewStyle
@@ -112,22 +449,36 @@ func (g *Generator) Generate(username string, persona *analyzer.Persona) ([]stri
 	if rvData.ReviewVoice == "" {
 		rvData.ReviewVoice = "No specific review voice data was identified."
 	}
+	for _, p := range persona.Phrases.Avoid {
+		if rvData.AvoidPhrases != "" {
+			rvData.AvoidPhrases += ", "
+		}
+		rvData.AvoidPhrases += strconv.Quote(p)
+	}
+	if rvData.AntiPatterns == "" {
+		rvData.AntiPatterns = "No specific anti-pattern data was identified."
+	}
 	if rvData.CollaborationStyle == "" {
 		rvData.CollaborationStyle = "No specific collaboration data was identified."
 	}
 
-	rvPath, err := g.writeSkill(username+"-code-reviewer", codeReviewerTemplate, rvData)
+	paths, err := g.writeSkill(skillName(username, SkillCodeReviewer), codeReviewerTemplate, rvData)
 	if err != nil {
 		return nil, fmt.Errorf("generating code reviewer skill: %w", err)
 	}
-	paths = append(paths, rvPath)
+	return paths, nil
+}
 
+func (g *Generator) developerProfileSkill(username string, persona *analyzer.Persona) ([]string, error) {
+	s := persona.Synthesis
 	dpData := developerProfileData{
 		Username:           username,
 		DeveloperInterests: s.DeveloperInterests,
 		ActivityPatterns:   s.ActivityPatterns,
 		CollaborationStyle: s.CollaborationStyle,
+		MaintainerBehavior: s.MaintainerBehavior,
 		Traits:             s.DistinctiveTraits,
+		skillMeta:          newSkillMeta(persona, developerProfileFields),
 	}
 	if dpData.DeveloperInterests == "" {
 		dpData.DeveloperInterests = persona.DeveloperIdentity
@@ -138,40 +489,90 @@ func (g *Generator) Generate(username string, persona *analyzer.Persona) ([]stri
 	if dpData.CollaborationStyle == "" {
 		dpData.CollaborationStyle = "No specific collaboration data was identified."
 	}
+	if dpData.MaintainerBehavior == "" {
+		dpData.MaintainerBehavior = "No specific issue-triage data was identified."
+	}
 	if dpData.Traits == "" {
 		dpData.Traits = "See developer interests above."
 	}
 
-	dpPath, err := g.writeSkill(username+"-developer-profile", developerProfileTemplate, dpData)
+	paths, err := g.writeSkill(skillName(username, SkillDeveloperProfile), developerProfileTemplate, dpData)
 	if err != nil {
 		return nil, fmt.Errorf("generating developer profile skill: %w", err)
 	}
-	paths = append(paths, dpPath)
-
 	return paths, nil
 }
 
-func (g *Generator) writeSkill(name, tmplStr string, data any) (string, error) {
-	tmpl, err := template.New(name).Parse(tmplStr)
+// writeSkill renders the SKILL.md of the named skill and writes it, with
+// the data files SetSkillData asks for next to it, failing with a
+// *LintError when it breaks the skill spec. A reserved word in the name is
+// only logged. The SKILL.md path comes first.
+func (g *Generator) writeSkill(name, tmplStr string, data any) ([]string, error) {
+	content, err := render(name, tmplStr, data)
+	if err != nil {
+		return nil, err
+	}
+	content = withFrontmatter(content, g.frontmatter)
+	path := filepath.Join(g.skillDir(name), SkillFile)
+	var violations []Violation
+	for _, v := range LintSkill(path, content) {
+		if v.reserved {
+			slog.Warn("skill name breaks the skill spec; some agents may not load it", "path", path, "problem", v.Message)
+			continue
+		}
+		violations = append(violations, v)
+	}
+	if len(violations) > 0 {
+		return nil, &LintError{Violations: violations}
+	}
+	path, err = g.write(path, content)
+	if err != nil {
+		return nil, err
+	}
+	slog.Info("wrote skill", "path", path)
+	dataPaths, err := g.writeSkillData(name, content)
+	if err != nil {
+		return nil, err
+	}
+	return append([]string{path}, dataPaths...), nil
+}
+
+// skillDir is the directory of the named skill.
+func (g *Generator) skillDir(name string) string {
+	return filepath.Join(cmp.Or(g.skillsDir, g.outputDir), name)
+}
+
+// partials holds the blocks of partialsTemplate, parsed once and cloned
+// under each template that includes them.
+var partials = template.Must(template.New("partials").Funcs(templateFuncs).Parse(partialsTemplate))
+
+// render executes tmplStr, which may include the blocks of
+// partialsTemplate, with data.
+func render(name, tmplStr string, data any) ([]byte, error) {
+	tmpl, err := partials.Clone()
 	if err != nil {
-		return "", fmt.Errorf("parsing template %s: %w", name, err)
+		return nil, fmt.Errorf("cloning templates for %s: %w", name, err)
+	}
+	tmpl, err = tmpl.New(name).Parse(tmplStr)
+	if err != nil {
+		return nil, fmt.Errorf("parsing template %s: %w", name, err)
 	}
 
 	var buf bytes.Buffer
 	if err := tmpl.Execute(&buf, data); err != nil {
-		return "", fmt.Errorf("executing template %s: %w", name, err)
+		return nil, fmt.Errorf("executing template %s: %w", name, err)
 	}
+	return buf.Bytes(), nil
+}
 
-	dir := filepath.Join(g.outputDir, name)
+// writeFile writes content to path, creating its directory.
+func writeFile(path string, content []byte) error {
+	dir := filepath.Dir(path)
 	if err := os.MkdirAll(dir, 0o755); err != nil {
-		return "", fmt.Errorf("creating directory %s: %w", dir, err)
+		return fmt.Errorf("creating directory %s: %w", dir, err)
 	}
-
-	path := filepath.Join(dir, "SKILL.md")
-	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
-		return "", fmt.Errorf("writing file %s: %w", path, err)
+	if err := os.WriteFile(path, content, 0o644); err != nil {
+		return fmt.Errorf("writing file %s: %w", path, err)
 	}
-
-	slog.Info("wrote skill", "path", path)
-	return path, nil
+	return nil
 }
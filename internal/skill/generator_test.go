@@ -5,8 +5,10 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/drpaneas/devlica/internal/analyzer"
+	"github.com/drpaneas/devlica/internal/stats"
 )
 
 func TestGenerate(t *testing.T) {
@@ -19,6 +21,11 @@ func TestGenerate(t *testing.T) {
 		ReviewStyle:       "Focuses on performance.",
 		Communication:     "Very direct.",
 		DeveloperIdentity: "Go enthusiast, Kubernetes contributor.",
+		Phrases: stats.PhraseStats{
+			Comments:  40,
+			Signature: []stats.Phrase{{Text: "lgtm with nits", Kind: stats.PhraseWords, Comments: 10}},
+			Avoid:     []string{"great job"},
+		},
 		Synthesis: &analyzer.SynthesisResult{
 			CodingPhilosophy:      "Values performance over readability.",
 			CodeStyleRules:        "- Use snake_case for variables\n- Keep functions under 20 lines",
@@ -30,9 +37,11 @@ func TestGenerate(t *testing.T) {
 			CommunicationPatterns: "Short sentences. No fluff.",
 			TestingPhilosophy:     "Benchmark everything.",
 			DistinctiveTraits:     "Performance-obsessed.",
+			AntiPatterns:          "- Never allocate in a hot loop.",
 			DeveloperInterests:    "Go, Kubernetes, performance tooling.",
 			ActivityPatterns:      "Steady upstream fixes and benchmark-driven maintenance.",
 			ProjectPatterns:       "CLI tools with MIT license, CI via GitHub Actions.",
+			Automation:            "Runs golangci-lint from a make lint target before every push.",
 			CollaborationStyle:    "Active upstream contributor, detailed bug reports.",
 		},
 	}
@@ -62,8 +71,14 @@ func TestGenerate(t *testing.T) {
 	if !strings.Contains(cs, "snake_case") {
 		t.Error("coding style skill should contain 'snake_case'")
 	}
-	if !strings.Contains(cs, "Automation And Project Patterns") {
-		t.Error("coding style skill should contain 'Automation And Project Patterns' section")
+	if !strings.Contains(cs, "## Project Patterns") {
+		t.Error("coding style skill should contain 'Project Patterns' section")
+	}
+	if !strings.Contains(cs, "## Automation\n\nRuns golangci-lint") {
+		t.Error("coding style skill should contain the 'Automation' section")
+	}
+	if !strings.Contains(cs, "## Never Do\n\n- Never allocate in a hot loop.") {
+		t.Error("coding style skill should contain the 'Never Do' section")
 	}
 
 	rvPath := filepath.Join(dir, "testdev-code-reviewer", "SKILL.md")
@@ -88,6 +103,15 @@ func TestGenerate(t *testing.T) {
 	if !strings.Contains(rv, "Collaboration Style") {
 		t.Error("code reviewer skill should contain 'Collaboration Style' section")
 	}
+	if !strings.Contains(rv, "## Signature Phrases") || !strings.Contains(rv, `- "lgtm with nits" (phrase, 25% of comments)`) {
+		t.Errorf("code reviewer skill should list signature phrases with their share:\n%s", rv)
+	}
+	if !strings.Contains(rv, `never or almost never writes them: "great job"`) {
+		t.Error("code reviewer skill should list phrases to avoid")
+	}
+	if !strings.Contains(rv, "## Never Do") || !strings.Contains(rv, "- Never allocate in a hot loop.") {
+		t.Error("code reviewer skill should contain the 'Never Do' section")
+	}
 
 	dpPath := filepath.Join(dir, "testdev-developer-profile", "SKILL.md")
 	dpContent, err := os.ReadFile(dpPath)
@@ -160,3 +184,198 @@ func TestGenerate_EmptyFields(t *testing.T) {
 		t.Error("expected fallback developer identity when synthesis field is empty")
 	}
 }
+
+func TestGenerate_LanguageStyles(t *testing.T) {
+	dir := t.TempDir()
+	persona := &analyzer.Persona{
+		Username:  "testdev",
+		Synthesis: &analyzer.SynthesisResult{CodeStyleRules: "- Keep it short"},
+		LanguageStyles: []analyzer.LanguageStyle{
+			{Language: "Go", Rules: "- Wrap errors with %w"},
+			{Language: "Python", Rules: "- Use dataclasses"},
+		},
+	}
+	if _, err := NewGenerator(dir).Generate("testdev", persona); err != nil {
+		t.Fatalf("Generate() error: %v", err)
+	}
+	content, err := os.ReadFile(filepath.Join(dir, "testdev-coding-style", "SKILL.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cs := string(content)
+	for _, want := range []string{"## Language-Specific Style", "### Go\n\n- Wrap errors with %w", "### Python\n\n- Use dataclasses\n\n## Commit Messages"} {
+		if !strings.Contains(cs, want) {
+			t.Errorf("expected %q in coding style skill:\n%s", want, cs)
+		}
+	}
+
+	persona.LanguageStyles = nil
+	if _, err := NewGenerator(dir).Generate("testdev", persona); err != nil {
+		t.Fatalf("Generate() error: %v", err)
+	}
+	content, _ = os.ReadFile(filepath.Join(dir, "testdev-coding-style", "SKILL.md"))
+	if strings.Contains(string(content), "Language-Specific Style") {
+		t.Error("single-language personas should not get a language section")
+	}
+}
+
+func TestGenerate_EvidenceAppendix(t *testing.T) {
+	dir := t.TempDir()
+	persona := &analyzer.Persona{
+		Username: "testdev",
+		Synthesis: &analyzer.SynthesisResult{
+			Evidence: map[string][]string{
+				"code_style_rules": {"https://github.com/a/b/commit/1", "https://github.com/a/b/commit/2"},
+				"review_voice":     {"https://github.com/a/b/pull/3#discussion_r4"},
+			},
+		},
+	}
+	if _, err := NewGenerator(dir).Generate("testdev", persona); err != nil {
+		t.Fatalf("Generate() error: %v", err)
+	}
+	read := func(name string) string {
+		content, err := os.ReadFile(filepath.Join(dir, name, "SKILL.md"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(content)
+	}
+
+	cs := read("testdev-coding-style")
+	want := "## Appendix: Evidence\n\nActivity each section above is based on.\n\n### Code Style Rules\n\n- https://github.com/a/b/commit/1\n- https://github.com/a/b/commit/2\n" + endMarker + "\n"
+	if !strings.HasSuffix(cs, want) {
+		t.Errorf("coding style skill should end with the evidence appendix:\n%s", cs)
+	}
+	if strings.Contains(cs, "Feedback Style") {
+		t.Error("review evidence leaked into the coding style skill")
+	}
+	if rv := read("testdev-code-reviewer"); !strings.Contains(rv, "### Feedback Style\n\n- https://github.com/a/b/pull/3#discussion_r4\n") {
+		t.Errorf("code reviewer skill is missing its evidence:\n%s", rv)
+	}
+	if dp := read("testdev-developer-profile"); strings.Contains(dp, "Appendix") {
+		t.Errorf("skills without evidence should have no appendix:\n%s", dp)
+	}
+}
+
+func TestGenerate_ConfidenceFrontmatter(t *testing.T) {
+	dir := t.TempDir()
+	persona := &analyzer.Persona{
+		Username: "testdev",
+		Synthesis: &analyzer.SynthesisResult{
+			Confidence: map[string]analyzer.Confidence{
+				"coding_philosophy": {Level: "high", Rationale: "Dozens of \"simplify\" commits."},
+				"code_style_rules":  {Level: "low", Rationale: "Few samples."},
+				"review_voice":      {Level: "medium"},
+			},
+		},
+	}
+	if _, err := NewGenerator(dir).Generate("testdev", persona); err != nil {
+		t.Fatalf("Generate() error: %v", err)
+	}
+	content, err := os.ReadFile(filepath.Join(dir, "testdev-coding-style", "SKILL.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `confidence:
+  coding_philosophy:
+    level: high
+    rationale: "Dozens of \"simplify\" commits."
+  code_style_rules:
+    level: low
+    rationale: "Few samples."
+---
+`
+	if !strings.Contains(string(content), want) {
+		t.Errorf("expected confidence in frontmatter:\n%s", content)
+	}
+
+	content, err = os.ReadFile(filepath.Join(dir, "testdev-developer-profile", "SKILL.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(content), "confidence:") {
+		t.Errorf("skills without rated fields should have no confidence key:\n%s", content)
+	}
+	if !strings.Contains(string(content), "professionally.\n---\n") {
+		t.Errorf("frontmatter should close right after the description:\n%s", content)
+	}
+}
+
+func TestGenerate_SparseCaveat(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		sparse []string
+		want   bool
+	}{
+		{"sparse profile", []string{"only 3 commits (20 or more needed)"}, true},
+		{"normal profile", nil, false},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			persona := &analyzer.Persona{
+				Username:  "testdev",
+				Synthesis: &analyzer.SynthesisResult{CodingPhilosophy: "Values clarity."},
+				Sparse:    tt.sparse,
+			}
+			paths, err := NewGenerator(dir).Generate("testdev", persona)
+			if err != nil {
+				t.Fatalf("Generate() error: %v", err)
+			}
+			for _, p := range paths {
+				content, err := os.ReadFile(p)
+				if err != nil {
+					t.Fatal(err)
+				}
+				got := strings.Contains(string(content), "> **Sparse profile:** Built from little public activity: only 3 commits")
+				if got != tt.want {
+					t.Errorf("%s has sparse caveat = %v, want %v", filepath.Base(filepath.Dir(p)), got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerate_ProvenanceFrontmatter(t *testing.T) {
+	dir := t.TempDir()
+	persona := &analyzer.Persona{
+		Username:  "testdev",
+		Synthesis: &analyzer.SynthesisResult{CodingPhilosophy: "Values clarity."},
+		Metadata: analyzer.PersonaMetadata{
+			Provider:       "openai",
+			Model:          "gpt-4o",
+			DevlicaVersion: "v1.2.3",
+			CrawledAt:      time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC),
+			CrawlHash:      "sha256:abc",
+			DataCounts:     analyzer.DataCounts{Repos: 2, Commits: 40},
+		},
+	}
+	paths, err := NewGenerator(dir).Generate("testdev", persona)
+	if err != nil {
+		t.Fatalf("Generate() error: %v", err)
+	}
+	for _, p := range paths {
+		content, err := os.ReadFile(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		front, _, ok := strings.Cut(strings.TrimPrefix(string(content), "---\n"), "\n---\n")
+		if !ok {
+			t.Fatalf("%s has no frontmatter", p)
+		}
+		for _, want := range []string{
+			"metadata:\n",
+			`  generated_by: "v1.2.3"`,
+			`  model: "gpt-4o"`,
+			`  crawled_at: "2025-03-01T12:00:00Z"`,
+			`  crawl_hash: "sha256:abc"`,
+			`  data_counts: "repos=2 commits=40 reviews=0 issue_comments=0"`,
+		} {
+			if !strings.Contains(front, want) {
+				t.Errorf("%s frontmatter missing %q:\n%s", filepath.Base(filepath.Dir(p)), want, front)
+			}
+		}
+		if strings.Contains(front, "ensemble_model") {
+			t.Errorf("%s frontmatter lists an unset ensemble model", filepath.Base(filepath.Dir(p)))
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package skill
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/drpaneas/devlica/internal/analyzer"
+)
+
+type languageStyleData struct {
+	Username string
+	Name     string
+	Language string
+	Rules    string
+	// Confidence and Evidence stay empty: they are per synthesis field,
+	// and a language's rules are not one. Examples reports whether an
+	// ExamplesFile quotes the language's code reviews.
+	skillMeta
+}
+
+// languageSkillName names the language-scoped skill, such as
+// "octocat-go-style".
+func languageSkillName(username, language string) string {
+	return skillName(username, languageSlug(language)+"-style")
+}
+
+// languageSkills writes a <username>-<language>-style skill for each
+// language style in the persona, holding only that language's rules, with
+// an ExamplesFile quoting the review comments on that language's files.
+// They refine the coding style skill, so they are written along with it.
+func (g *Generator) languageSkills(username string, persona *analyzer.Persona) ([]string, error) {
+	var paths []string
+	for _, ls := range persona.LanguageStyles {
+		rules := strings.TrimSpace(ls.Rules)
+		if rules == "" || languageSlug(ls.Language) == "" {
+			continue
+		}
+		name := languageSkillName(username, ls.Language)
+		sections := languageExamples(persona.Synthesis, ls.Language)
+		data := languageStyleData{
+			Username: username,
+			Name:     name,
+			Language: ls.Language,
+			Rules:    rules,
+			skillMeta: skillMeta{
+				Examples:   len(sections) > 0,
+				Provenance: provenanceEntries(persona.Metadata),
+				Sparse:     sparseCaveat(persona.Sparse),
+			},
+		}
+		written, err := g.writeSkill(name, languageStyleTemplate, data)
+		if err != nil {
+			return nil, fmt.Errorf("generating %s style skill: %w", ls.Language, err)
+		}
+		paths = append(paths, written...)
+		examples, err := g.writeExamples(username, name, sections)
+		if err != nil {
+			return nil, err
+		}
+		if examples != "" {
+			paths = append(paths, examples)
+		}
+	}
+	return paths, nil
+}
+
+// languageExamples returns the quoted evidence about files in language,
+// which only review comments carry a file path for, sorted by link.
+func languageExamples(s *analyzer.SynthesisResult, language string) []exampleSection {
+	exts := analyzer.LanguageExtensions(language)
+	var entries []exampleEntry
+	for url, ex := range s.Examples {
+		i := strings.LastIndex(ex.Context, ", ")
+		if i < 0 || !slices.Contains(exts, strings.ToLower(path.Ext(ex.Context[i+2:]))) {
+			continue
+		}
+		entries = append(entries, exampleEntry{URL: url, Kind: ex.Kind, Context: ex.Context, Quote: blockquote(ex.Text)})
+	}
+	if len(entries) == 0 {
+		return nil
+	}
+	slices.SortFunc(entries, func(a, b exampleEntry) int { return strings.Compare(a.URL, b.URL) })
+	return []exampleSection{{Title: language + " Reviews", Examples: entries}}
+}
@@ -0,0 +1,93 @@
+package skill
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/drpaneas/devlica/internal/analyzer"
+)
+
+func TestGenerate_LanguageSkills(t *testing.T) {
+	dir := t.TempDir()
+	goReview := "https://github.com/a/b/pull/3#discussion_r4"
+	pyReview := "https://github.com/a/b/pull/3#discussion_r5"
+	persona := &analyzer.Persona{
+		Synthesis: &analyzer.SynthesisResult{
+			CodeStyleRules: "- Keep it short",
+			Examples: map[string]analyzer.Example{
+				goReview: {Kind: "review comment", Text: "Wrap this error.", Context: "a/b#3 (Add retries), client/retry.go"},
+				pyReview: {Kind: "review comment", Text: "Use a dataclass.", Context: "a/b#3 (Add retries), tools/gen.py"},
+			},
+		},
+		LanguageStyles: []analyzer.LanguageStyle{
+			{Language: "Go", Rules: "- Wrap errors with %w"},
+			{Language: "C++", Rules: "- Prefer RAII"},
+			{Language: "Python", Rules: "  "},
+		},
+	}
+	gen := NewGenerator(dir)
+	gen.SetSkills([]string{SkillCodingStyle})
+	paths, err := gen.Generate("testdev", persona)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		filepath.Join(dir, "testdev-coding-style", SkillFile),
+		filepath.Join(dir, "testdev-go-style", SkillFile),
+		filepath.Join(dir, "testdev-go-style", ExamplesFile),
+		filepath.Join(dir, "testdev-cpp-style", SkillFile),
+	}
+	if strings.Join(paths, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("paths = %v, want %v", paths, want)
+	}
+
+	content, err := os.ReadFile(want[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := string(content)
+	for _, s := range []string{"name: testdev-go-style\n", "## Go Rules\n\n- Wrap errors with %w", "refines testdev-coding-style", "EXAMPLES.md next to this file"} {
+		if !strings.Contains(got, s) {
+			t.Errorf("Go skill is missing %q:\n%s", s, got)
+		}
+	}
+	if strings.Contains(got, "Prefer RAII") || strings.Contains(got, "Keep it short") {
+		t.Errorf("Go skill should hold only the Go rules:\n%s", got)
+	}
+
+	examples, err := os.ReadFile(want[2])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(examples), "> Wrap this error.") || strings.Contains(string(examples), "dataclass") {
+		t.Errorf("Go examples should quote only reviews of Go files:\n%s", examples)
+	}
+
+	gen.SetSkills([]string{SkillCodeReviewer})
+	paths, err = gen.Generate("testdev", persona)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) != 1 {
+		t.Errorf("paths = %v, want language skills only with the coding style skill", paths)
+	}
+}
+
+func TestLanguageSlug(t *testing.T) {
+	for in, want := range map[string]string{
+		"Go":               "go",
+		"C++":              "cpp",
+		"C#":               "csharp",
+		"Objective-C":      "objective-c",
+		"Jupyter Notebook": "jupyter-notebook",
+		"Ren'Py":           "renpy",
+		"F*":               "f",
+		"- -":              "",
+	} {
+		if got := languageSlug(in); got != want {
+			t.Errorf("languageSlug(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package skill
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// SkillFile is the file each skill directory is named for.
+const SkillFile = "SKILL.md"
+
+// Limits a SKILL.md must keep to under the Agent Skills spec, which
+// Claude and Cursor load skills by.
+const (
+	maxSkillNameLen        = 64
+	maxSkillDescriptionLen = 1024
+	// maxSkillLines bounds the body: an agent loads all of it once the
+	// skill triggers, and the spec asks for anything longer to be split
+	// into referenced files.
+	maxSkillLines = 500
+)
+
+// skillNamePattern is the spec's name format: lowercase letters, digits,
+// and single hyphens between them.
+var skillNamePattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// xmlTagPattern matches an XML tag, which a name or description may not
+// contain because agents splice them into their system prompt.
+var xmlTagPattern = regexp.MustCompile(`</?[A-Za-z][^<>]*>`)
+
+// reservedSkillWords may not appear in a skill name.
+var reservedSkillWords = []string{"anthropic", "claude"}
+
+// Violation is one way a SKILL.md breaks the skill spec.
+type Violation struct {
+	Path    string
+	Message string
+	// reserved marks a reserved word in the name, which generation only
+	// warns about: it comes from the username, which the user cannot
+	// change.
+	reserved bool
+}
+
+func (v Violation) String() string {
+	return v.Path + ": " + v.Message
+}
+
+// LintError reports the violations that failed a lint.
+type LintError struct {
+	Violations []Violation
+}
+
+func (e *LintError) Error() string {
+	lines := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		lines[i] = v.String()
+	}
+	return fmt.Sprintf("%d skill spec violations:\n%s", len(e.Violations), strings.Join(lines, "\n"))
+}
+
+// skillName names a user's skill, such as "octocat-coding-style". Logins
+// are case-insensitive, so the username is lowercased as the spec wants.
+func skillName(username, skill string) string {
+	return strings.ToLower(username) + "-" + skill
+}
+
+// LintSkill checks the SKILL.md at path, whose content is given, against
+// the skill spec: the frontmatter must have a name in the spec's format,
+// free of reserved words, that matches the skill's directory and a
+// description within its length, neither with XML tags, and the body must
+// stay within maxSkillLines.
+func LintSkill(path string, content []byte) []Violation {
+	var violations []Violation
+	add := func(format string, args ...any) {
+		violations = append(violations, Violation{Path: path, Message: fmt.Sprintf(format, args...)})
+	}
+	fm, body := splitFrontmatter(string(content))
+	if fm == "" {
+		add("missing YAML frontmatter")
+		return violations
+	}
+	fields := frontmatterFields(fm)
+
+	name, ok := fields["name"]
+	switch {
+	case !ok || name == "":
+		add("frontmatter has no name")
+	case len(name) > maxSkillNameLen:
+		add("name is %d characters, over the limit of %d", len(name), maxSkillNameLen)
+	case !skillNamePattern.MatchString(name):
+		add("name %q must be lowercase letters, digits, and single hyphens, without a leading or trailing hyphen", name)
+	}
+	for _, w := range reservedSkillWords {
+		if strings.Contains(name, w) {
+			violations = append(violations, Violation{Path: path, Message: fmt.Sprintf("name %q contains the reserved word %q", name, w), reserved: true})
+		}
+	}
+	if xmlTagPattern.MatchString(name) {
+		add("name contains an XML tag")
+	}
+	if dir := filepath.Base(filepath.Dir(path)); name != "" && dir != "." && dir != name {
+		add("name %q does not match its directory %q", name, dir)
+	}
+
+	description, ok := fields["description"]
+	switch {
+	case !ok || description == "":
+		add("frontmatter has no description")
+	case len(description) > maxSkillDescriptionLen:
+		add("description is %d characters, over the limit of %d", len(description), maxSkillDescriptionLen)
+	}
+	if xmlTagPattern.MatchString(description) {
+		add("description contains an XML tag")
+	}
+
+	if n := strings.Count(strings.TrimRight(body, "\n"), "\n") + 1; n > maxSkillLines {
+		add("body is %d lines, over the limit of %d; try -max-skill-tokens", n, maxSkillLines)
+	}
+	return violations
+}
+
+// frontmatterFields returns the top-level scalar fields of YAML
+// frontmatter, unquoted. Nested blocks such as metadata are skipped; a
+// skill's name and description are always plain top-level scalars.
+func frontmatterFields(fm string) map[string]string {
+	fields := make(map[string]string)
+	for _, line := range strings.Split(fm, "\n") {
+		if line == "---" || line == "" || line[0] == ' ' || line[0] == '\t' || line[0] == '#' {
+			continue
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+			value = value[1 : len(value)-1]
+		}
+		fields[strings.TrimSpace(key)] = value
+	}
+	return fields
+}
+
+// LintDir lints every SKILL.md under dir. It fails with a *LintError
+// listing the violations, if any.
+func LintDir(dir string) (int, error) {
+	var violations []Violation
+	n := 0
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || d.Name() != SkillFile {
+			return nil
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		n++
+		violations = append(violations, LintSkill(path, content)...)
+		return nil
+	})
+	if err != nil {
+		return n, fmt.Errorf("linting %s: %w", dir, err)
+	}
+	if len(violations) > 0 {
+		return n, &LintError{Violations: violations}
+	}
+	return n, nil
+}
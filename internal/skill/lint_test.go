@@ -0,0 +1,131 @@
+package skill
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/drpaneas/devlica/internal/analyzer"
+)
+
+func TestLintSkill(t *testing.T) {
+	path := filepath.Join("out", "octocat-coding-style", SkillFile)
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{
+			name:    "valid",
+			content: "---\nname: octocat-coding-style\ndescription: \"Write code like octocat.\"\nmetadata:\n  model: m1\n---\n\n# Body\n",
+		},
+		{
+			name:    "no frontmatter",
+			content: "# Body\n",
+			want:    []string{"missing YAML frontmatter"},
+		},
+		{
+			name:    "missing fields",
+			content: "---\nmetadata:\n  name: nested\n---\n",
+			want:    []string{"no name", "no description"},
+		},
+		{
+			name:    "bad name",
+			content: "---\nname: Octocat--Style\ndescription: ok\n---\n",
+			want:    []string{"must be lowercase", "does not match its directory"},
+		},
+		{
+			name:    "reserved word",
+			content: "---\nname: claude-helper\ndescription: ok\n---\n",
+			want:    []string{"reserved word \"claude\"", "does not match"},
+		},
+		{
+			name:    "long description with a tag",
+			content: "---\nname: octocat-coding-style\ndescription: <b>" + strings.Repeat("x", maxSkillDescriptionLen) + "\n---\n",
+			want:    []string{"description is 1027 characters", "description contains an XML tag"},
+		},
+		{
+			name:    "long body",
+			content: "---\nname: octocat-coding-style\ndescription: ok\n---\n" + strings.Repeat("line\n", maxSkillLines+1),
+			want:    []string{"body is 501 lines"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := LintSkill(path, []byte(tt.content))
+			if len(got) != len(tt.want) {
+				t.Fatalf("LintSkill() = %v, want %d violations", got, len(tt.want))
+			}
+			for i, v := range got {
+				if !strings.Contains(v.Message, tt.want[i]) {
+					t.Errorf("violation %d = %q, want it to mention %q", i, v.Message, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestLintDir(t *testing.T) {
+	dir := t.TempDir()
+	persona := &analyzer.Persona{Synthesis: &analyzer.SynthesisResult{CodeStyleRules: "- Wrap errors"}}
+	if _, err := NewGenerator(dir).Generate("octocat", persona); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := LintDir(dir); err != nil || n != len(DefaultSkills) {
+		t.Fatalf("LintDir() = %d, %v; want %d generated skills to pass", n, err, len(DefaultSkills))
+	}
+
+	edited := filepath.Join(dir, "octocat-coding-style", SkillFile)
+	if err := os.WriteFile(edited, []byte("---\nname: octocat-coding-style\n---\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	_, err := LintDir(dir)
+	var lintErr *LintError
+	if !errors.As(err, &lintErr) || len(lintErr.Violations) != 1 || lintErr.Violations[0].Path != edited {
+		t.Errorf("LintDir() = %v, want one violation in %s", err, edited)
+	}
+}
+
+func TestGenerate_LintFailure(t *testing.T) {
+	dir := t.TempDir()
+	long := strings.Repeat("- Wrap errors\n", maxSkillLines)
+	persona := &analyzer.Persona{Synthesis: &analyzer.SynthesisResult{CodeStyleRules: long}}
+	_, err := NewGenerator(dir).Generate("octocat", persona)
+	var lintErr *LintError
+	if !errors.As(err, &lintErr) {
+		t.Fatalf("Generate() = %v, want a lint error for an overlong skill", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "octocat-coding-style", SkillFile)); !os.IsNotExist(err) {
+		t.Error("a skill that fails the lint should not be written")
+	}
+}
+
+func TestGenerate_SkillNames(t *testing.T) {
+	persona := &analyzer.Persona{Synthesis: &analyzer.SynthesisResult{CodeStyleRules: "- Wrap errors"}}
+	tests := []struct {
+		username string
+		want     string
+	}{
+		{"DrPaneas", "drpaneas-coding-style"},
+		// A login with a reserved word still generates; only devlica lint
+		// fails on it.
+		{"claudette", "claudette-coding-style"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.username, func(t *testing.T) {
+			dir := t.TempDir()
+			if _, err := NewGenerator(dir).Generate(tt.username, persona); err != nil {
+				t.Fatalf("Generate() error: %v", err)
+			}
+			content, err := os.ReadFile(filepath.Join(dir, tt.want, SkillFile))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(string(content), "name: "+tt.want+"\n") {
+				t.Errorf("SKILL.md should be named %s:\n%s", tt.want, content)
+			}
+		})
+	}
+}
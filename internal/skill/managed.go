@@ -0,0 +1,125 @@
+package skill
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Markers around the part of each generated file devlica owns. With
+// SetUpdate, a re-run replaces only what is between them, and the
+// frontmatter, so text the user added above or below survives.
+const (
+	beginMarker = "<!-- devlica:begin (regenerated by devlica; edit outside this block) -->"
+	endMarker   = "<!-- devlica:end -->"
+)
+
+// markerOverhead is how many bytes the markers add to a file.
+const markerOverhead = len(beginMarker) + len(endMarker) + 2
+
+// markers are the lines around a file's managed block, written as
+// comments in the file's own syntax.
+type markers struct{ begin, end string }
+
+var (
+	htmlMarkers = markers{beginMarker, endMarker}
+	// hashMarkers are for files where "#" starts a comment: YAML, and a
+	// commit message template, which git strips them from.
+	hashMarkers = markers{"# devlica:begin (regenerated by devlica; edit outside this block)", "# devlica:end"}
+)
+
+// markersFor returns the markers for the file at path. JSON has no
+// comments, so a JSON file gets none and is always rewritten whole.
+func markersFor(path string) markers {
+	switch {
+	case filepath.Base(path) == GitMessageFile, filepath.Ext(path) == ".yaml":
+		return hashMarkers
+	case filepath.Ext(path) == ".json":
+		return markers{}
+	}
+	return htmlMarkers
+}
+
+// SetUpdate makes Generate refresh only the devlica-managed block of files
+// that already exist instead of overwriting them.
+func (g *Generator) SetUpdate(update bool) {
+	g.update = update
+}
+
+// splitFrontmatter splits content into its leading YAML frontmatter,
+// closing "---" line included, and the rest.
+func splitFrontmatter(content string) (string, string) {
+	if !strings.HasPrefix(content, "---\n") {
+		return "", content
+	}
+	end := strings.Index(content[3:], "\n---\n")
+	if end < 0 {
+		return "", content
+	}
+	end += 3 + len("\n---\n")
+	return content[:end], content[end:]
+}
+
+// managed wraps the body of generated content in the markers, leaving
+// any frontmatter above them.
+func managed(content []byte, m markers) string {
+	if m == (markers{}) {
+		return string(content)
+	}
+	fm, body := splitFrontmatter(string(content))
+	return fm + m.begin + "\n" + strings.Trim(body, "\n") + "\n" + m.end + "\n"
+}
+
+// mergeManaged replaces the managed block and frontmatter of existing with
+// those of generated, as managed returns it, keeping the rest of existing.
+// It fails when existing has no managed block to replace.
+func mergeManaged(existing, generated string, m markers) (string, error) {
+	_, rest := splitFrontmatter(existing)
+	begin := strings.Index(rest, m.begin)
+	end := strings.LastIndex(rest, m.end)
+	if begin < 0 || end < begin {
+		return "", errors.New("no devlica markers")
+	}
+	fm, body := splitFrontmatter(generated)
+	block := body[len(m.begin):strings.LastIndex(body, m.end)]
+	return fm + rest[:begin] + m.begin + block + m.end + rest[end+len(m.end):], nil
+}
+
+// write writes generated content to path and returns the path written.
+// In update mode an existing file keeps everything outside its managed
+// block; one without markers is left alone, and the new content goes to
+// path.new for the user to merge by hand. A streaming Generator only
+// collects the content, under its path relative to the output directory.
+func (g *Generator) write(path string, content []byte) (string, error) {
+	m := markersFor(path)
+	generated := managed(content, m)
+	if g.stream != nil {
+		path = g.streamPath(path)
+		g.streamed = append(g.streamed, streamFile{path: path, content: []byte(generated)})
+		return path, nil
+	}
+	if g.update && m != (markers{}) {
+		existing, err := os.ReadFile(path)
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+		case err != nil:
+			return "", fmt.Errorf("reading %s: %w", path, err)
+		default:
+			merged, err := mergeManaged(string(existing), generated, m)
+			if err != nil {
+				slog.Warn("file has no devlica markers, so edits cannot be told apart; writing the update next to it", "path", path)
+				path += ".new"
+			} else {
+				generated = merged
+			}
+		}
+	}
+	if err := writeFile(path, []byte(generated)); err != nil {
+		return "", err
+	}
+	return path, nil
+}
@@ -0,0 +1,121 @@
+package skill
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/drpaneas/devlica/internal/analyzer"
+)
+
+func TestMergeManaged(t *testing.T) {
+	generated := managed([]byte("---\nname: new\n---\n\n# New\n\nFresh rules.\n"), htmlMarkers)
+	tests := []struct {
+		name     string
+		existing string
+		want     string
+		wantErr  bool
+	}{
+		{
+			name:     "keeps text around the block",
+			existing: "---\nname: old\n---\nMy intro.\n" + beginMarker + "\nOld rules.\n" + endMarker + "\n\n## My notes\n\nKeep this.\n",
+			want:     "---\nname: new\n---\nMy intro.\n" + beginMarker + "\n# New\n\nFresh rules.\n" + endMarker + "\n\n## My notes\n\nKeep this.\n",
+		},
+		{
+			name:     "no frontmatter in the existing file",
+			existing: beginMarker + "\nOld.\n" + endMarker + "\n",
+			want:     "---\nname: new\n---\n" + beginMarker + "\n# New\n\nFresh rules.\n" + endMarker + "\n",
+		},
+		{
+			name:     "no markers",
+			existing: "---\nname: old\n---\nHand-written.\n",
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := mergeManaged(tt.existing, generated, htmlMarkers)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("mergeManaged() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("mergeManaged() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerate_Update(t *testing.T) {
+	dir := t.TempDir()
+	persona := &analyzer.Persona{
+		Synthesis: &analyzer.SynthesisResult{CodeStyleRules: "- Wrap errors with context"},
+	}
+	if _, err := NewGenerator(dir).Generate("testdev", persona); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "testdev-coding-style", "SKILL.md")
+	first, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(first), beginMarker) || !strings.HasSuffix(string(first), endMarker+"\n") {
+		t.Fatalf("generated skill has no managed block:\n%s", first)
+	}
+	edited := string(first) + "\n## Team notes\n\nWe vendor dependencies.\n"
+	if err := os.WriteFile(path, []byte(edited), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	persona.Synthesis.CodeStyleRules = "- Return early"
+	gen := NewGenerator(dir)
+	gen.SetUpdate(true)
+	if _, err := gen.Generate("testdev", persona); err != nil {
+		t.Fatal(err)
+	}
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(got), "Return early") || strings.Contains(string(got), "Wrap errors") {
+		t.Errorf("managed block was not refreshed:\n%s", got)
+	}
+	if !strings.HasSuffix(string(got), "## Team notes\n\nWe vendor dependencies.\n") {
+		t.Errorf("edits outside the managed block were lost:\n%s", got)
+	}
+
+	// Without -update the file is overwritten as before.
+	if _, err := NewGenerator(dir).Generate("testdev", persona); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := os.ReadFile(path); strings.Contains(string(got), "Team notes") {
+		t.Errorf("a plain run should overwrite the file:\n%s", got)
+	}
+}
+
+func TestGenerate_UpdateWithoutMarkers(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "testdev", "AGENTS.md")
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("# Hand-written\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	gen := NewGenerator(dir)
+	gen.SetFormats([]Format{FormatAgents})
+	gen.SetUpdate(true)
+	paths, err := gen.Generate("testdev", &analyzer.Persona{Synthesis: &analyzer.SynthesisResult{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) != 1 || paths[0] != path+".new" {
+		t.Fatalf("paths = %v, want %s.new", paths, path)
+	}
+	if got, _ := os.ReadFile(path); string(got) != "# Hand-written\n" {
+		t.Errorf("file without markers was changed:\n%s", got)
+	}
+	if got, _ := os.ReadFile(path + ".new"); !strings.Contains(string(got), beginMarker) {
+		t.Errorf("update was not written next to the file:\n%s", got)
+	}
+}
@@ -0,0 +1,92 @@
+package skill
+
+import (
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"strings"
+
+	"github.com/drpaneas/devlica/internal/analyzer"
+	"github.com/drpaneas/devlica/internal/stats"
+)
+
+// PRTemplateFile is GitHub's pull request template, under .github.
+const PRTemplateFile = "PULL_REQUEST_TEMPLATE.md"
+
+type prTemplateSection struct {
+	Title string
+	// Hint is shown as an HTML comment, which GitHub does not render.
+	Hint  string
+	Items []string
+}
+
+type prTemplateData struct {
+	Username string
+	Total    int
+	Sections []prTemplateSection
+	// Hint stands in for sections when they write descriptions as prose.
+	Hint string
+}
+
+// generatePRTemplate writes .github/PULL_REQUEST_TEMPLATE.md with the
+// sections the developer's pull request descriptions recur with, in their
+// usual order, their recurring checklist, and a testing section when they
+// usually say how a change was tested. It needs the persona's PR
+// statistics, which personas saved by an older devlica do not have.
+func (g *Generator) generatePRTemplate(username string, persona *analyzer.Persona) ([]string, error) {
+	ps := persona.PRStats
+	if ps.Total == 0 || ps.BodyRate == 0 {
+		slog.Warn("persona has no pull request descriptions, so no pull request template was written")
+		return nil, nil
+	}
+	data := prTemplateData{Username: username, Total: ps.Total, Sections: prTemplateSections(ps)}
+	if len(data.Sections) == 0 {
+		data.Hint = fmt.Sprintf("What the change does and why. %s's descriptions run about %d characters.", username, ps.BodyLenMedian)
+	}
+	content, err := render("pr-template", prTemplateTemplate, data)
+	if err != nil {
+		return nil, fmt.Errorf("generating pull request template: %w", err)
+	}
+	path, err := g.write(filepath.Join(g.outputDir, username, ".github", PRTemplateFile), content)
+	if err != nil {
+		return nil, fmt.Errorf("generating pull request template: %w", err)
+	}
+	slog.Info("wrote pull request template", "path", path, "sections", len(data.Sections))
+	return []string{path}, nil
+}
+
+// prTemplateSections turns the recurring headings into template sections.
+// The recurring checklist items go under a heading that names a checklist,
+// or a Checklist section of their own, and a Testing section is added when
+// most descriptions mention testing without a heading for it.
+func prTemplateSections(ps stats.PRBodyStats) []prTemplateSection {
+	var sections []prTemplateSection
+	checklist, testing := -1, false
+	for _, part := range ps.Sections {
+		sections = append(sections, prTemplateSection{
+			Title: part.Text,
+			Hint:  fmt.Sprintf("In %d of %d descriptions.", part.Uses, ps.Total),
+		})
+		lower := strings.ToLower(part.Text)
+		if checklist < 0 && strings.Contains(lower, "checklist") {
+			checklist = len(sections) - 1
+		}
+		testing = testing || strings.Contains(lower, "test")
+	}
+	if ps.TestingRate >= majority && !testing {
+		sections = append(sections, prTemplateSection{
+			Title: "Testing",
+			Hint:  fmt.Sprintf("How the change was tested; %.0f%% of descriptions say.", ps.TestingRate*100),
+		})
+	}
+	if len(ps.ChecklistItems) > 0 {
+		if checklist < 0 {
+			sections = append(sections, prTemplateSection{Title: "Checklist"})
+			checklist = len(sections) - 1
+		}
+		for _, item := range ps.ChecklistItems {
+			sections[checklist].Items = append(sections[checklist].Items, item.Text)
+		}
+	}
+	return sections
+}
@@ -0,0 +1,73 @@
+package skill
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/drpaneas/devlica/internal/analyzer"
+	"github.com/drpaneas/devlica/internal/stats"
+)
+
+func TestGenerate_PRTemplate(t *testing.T) {
+	dir := t.TempDir()
+	gen := NewGenerator(dir)
+	gen.SetFormats([]Format{FormatPRTemplate})
+	persona := &analyzer.Persona{
+		Synthesis: &analyzer.SynthesisResult{},
+		PRStats: stats.PRBodyStats{
+			Total:          10,
+			BodyRate:       1,
+			Sections:       []stats.PRPart{{Text: "Summary", Uses: 9}, {Text: "Why", Uses: 4}},
+			ChecklistItems: []stats.PRPart{{Text: "Docs updated", Uses: 3}},
+			TestingRate:    0.7,
+		},
+	}
+	paths, err := gen.Generate("testdev", persona)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := filepath.Join(dir, "testdev", ".github", PRTemplateFile)
+	if len(paths) != 1 || paths[0] != want {
+		t.Fatalf("paths = %v, want [%s]", paths, want)
+	}
+	content, err := os.ReadFile(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := string(content)
+	for _, s := range []string{
+		"## Summary\n\n<!-- In 9 of 10 descriptions. -->\n",
+		"## Why\n",
+		"## Testing\n\n<!-- How the change was tested; 70% of descriptions say. -->\n",
+		"## Checklist\n\n- [ ] Docs updated\n",
+	} {
+		if !strings.Contains(got, s) {
+			t.Errorf("template is missing %q:\n%s", s, got)
+		}
+	}
+	if strings.Index(got, "## Summary") > strings.Index(got, "## Why") {
+		t.Errorf("sections should keep their usual order:\n%s", got)
+	}
+}
+
+func TestPRTemplateSections(t *testing.T) {
+	ps := stats.PRBodyStats{
+		Total:          4,
+		Sections:       []stats.PRPart{{Text: "How to test", Uses: 3}, {Text: "Release checklist", Uses: 2}},
+		ChecklistItems: []stats.PRPart{{Text: "Changelog", Uses: 2}},
+		TestingRate:    1,
+	}
+	got := prTemplateSections(ps)
+	if len(got) != 2 {
+		t.Fatalf("prTemplateSections() = %+v, want no extra Testing or Checklist section", got)
+	}
+	if len(got[1].Items) != 1 || got[1].Items[0] != "Changelog" {
+		t.Errorf("checklist items should go under their checklist heading: %+v", got)
+	}
+
+	if got := prTemplateSections(stats.PRBodyStats{Total: 4}); len(got) != 0 {
+		t.Errorf("prose descriptions should get no sections: %+v", got)
+	}
+}
@@ -0,0 +1,275 @@
+package skill
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"maps"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/drpaneas/devlica/internal/analyzer"
+)
+
+// DataFormat names a machine-readable file written next to each SKILL.md.
+type DataFormat string
+
+// The supported skill data formats.
+const (
+	// DataJSON writes skill.json.
+	DataJSON DataFormat = "json"
+	// DataYAML writes skill.yaml.
+	DataYAML DataFormat = "yaml"
+)
+
+// Files written by the skill data formats.
+const (
+	SkillJSONFile = "skill.json"
+	SkillYAMLFile = "skill.yaml"
+)
+
+// ParseDataFormats parses a comma-separated list of skill data formats,
+// such as "json,yaml".
+func ParseDataFormats(s string) ([]DataFormat, error) {
+	var formats []DataFormat
+	for _, f := range strings.Split(s, ",") {
+		switch df := DataFormat(strings.ToLower(strings.TrimSpace(f))); df {
+		case DataJSON, DataYAML:
+			if !slices.Contains(formats, df) {
+				formats = append(formats, df)
+			}
+		default:
+			return nil, fmt.Errorf("unknown skill data format %q: must be json or yaml", f)
+		}
+	}
+	return formats, nil
+}
+
+// SetSkillData makes every skill also write its SKILL.md as structured
+// data in each of formats, for tools that should not parse Markdown.
+func (g *Generator) SetSkillData(formats []DataFormat) {
+	g.skillData = formats
+}
+
+// SkillDocument is the structured form of a SKILL.md: its frontmatter, and
+// its body split into the sections under its level-2 headings.
+type SkillDocument struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// Frontmatter holds the SetFrontmatter keys, each a string or, for
+	// list keys such as tags, a list of strings.
+	Frontmatter map[string]any                 `json:"frontmatter,omitempty"`
+	Metadata    map[string]string              `json:"metadata,omitempty"`
+	Confidence  map[string]analyzer.Confidence `json:"confidence,omitempty"`
+	Title       string                         `json:"title"`
+	// Intro is the text between the title and the first section.
+	Intro    string         `json:"intro,omitempty"`
+	Sections []SkillSection `json:"sections"`
+}
+
+// SkillSection is one level-2 section of a SKILL.md, its content as
+// Markdown.
+type SkillSection struct {
+	Title   string `json:"title"`
+	Content string `json:"content"`
+}
+
+// writeSkillData writes the SetSkillData files of the named skill from
+// its rendered SKILL.md.
+func (g *Generator) writeSkillData(name string, content []byte) ([]string, error) {
+	if len(g.skillData) == 0 {
+		return nil, nil
+	}
+	doc := parseSkill(content)
+	var paths []string
+	for _, f := range g.skillData {
+		file, data := SkillJSONFile, []byte(nil)
+		switch f {
+		case DataJSON:
+			encoded, err := json.MarshalIndent(doc, "", "  ")
+			if err != nil {
+				return nil, fmt.Errorf("encoding %s data: %w", name, err)
+			}
+			data = append(encoded, '\n')
+		case DataYAML:
+			file, data = SkillYAMLFile, skillYAML(doc)
+		}
+		path, err := g.write(filepath.Join(g.skillDir(name), file), data)
+		if err != nil {
+			return nil, fmt.Errorf("generating %s data: %w", name, err)
+		}
+		slog.Info("wrote skill data", "path", path)
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
+// parseSkill reads a rendered SKILL.md back into a SkillDocument. It knows
+// only the frontmatter devlica writes: top-level scalars and flow lists,
+// the metadata map, and the confidence map of level and rationale, with
+// values plain or quoted by strconv.Quote.
+func parseSkill(content []byte) SkillDocument {
+	fm, body := splitFrontmatter(string(content))
+	var doc SkillDocument
+	var block, field string
+	for _, line := range strings.Split(strings.TrimSuffix(strings.TrimPrefix(fm, "---\n"), "---\n"), "\n") {
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+		key, raw, _ := strings.Cut(strings.TrimSpace(line), ":")
+		raw = strings.TrimSpace(raw)
+		value := unquoteYAML(raw)
+		switch {
+		case indent == 0:
+			block = key
+			switch {
+			case key == "name":
+				doc.Name = value
+			case key == "description":
+				doc.Description = value
+			case raw != "":
+				if doc.Frontmatter == nil {
+					doc.Frontmatter = make(map[string]any)
+				}
+				doc.Frontmatter[key] = frontmatterData(raw)
+			}
+		case block == "metadata":
+			if doc.Metadata == nil {
+				doc.Metadata = make(map[string]string)
+			}
+			doc.Metadata[key] = value
+		case block == "confidence" && indent == 2:
+			field = key
+			if doc.Confidence == nil {
+				doc.Confidence = make(map[string]analyzer.Confidence)
+			}
+			doc.Confidence[field] = analyzer.Confidence{}
+		case block == "confidence":
+			c := doc.Confidence[field]
+			switch key {
+			case "level":
+				c.Level = value
+			case "rationale":
+				c.Rationale = value
+			}
+			doc.Confidence[field] = c
+		}
+	}
+
+	var intro []string
+	var section *SkillSection
+	flush := func(lines []string) string { return strings.TrimSpace(strings.Join(lines, "\n")) }
+	var lines []string
+	fenced := false
+	for _, line := range strings.Split(body, "\n") {
+		if isFence(line) {
+			fenced = !fenced
+		}
+		switch {
+		case !fenced && doc.Title == "" && strings.HasPrefix(line, "# "):
+			doc.Title = strings.TrimPrefix(line, "# ")
+		case !fenced && strings.HasPrefix(line, "## "):
+			if section == nil {
+				intro = lines
+			} else {
+				section.Content = flush(lines)
+			}
+			doc.Sections = append(doc.Sections, SkillSection{Title: strings.TrimPrefix(line, "## ")})
+			section = &doc.Sections[len(doc.Sections)-1]
+			lines = nil
+		default:
+			lines = append(lines, line)
+		}
+	}
+	if section == nil {
+		intro = lines
+	} else {
+		section.Content = flush(lines)
+	}
+	doc.Intro = flush(intro)
+	return doc
+}
+
+// frontmatterData reads a value frontmatterValue wrote.
+func frontmatterData(raw string) any {
+	list, ok := strings.CutPrefix(raw, "[")
+	if !ok || !strings.HasSuffix(list, "]") {
+		return unquoteYAML(raw)
+	}
+	items := []string{}
+	for _, item := range strings.Split(strings.TrimSuffix(list, "]"), ", ") {
+		if item != "" {
+			items = append(items, unquoteYAML(item))
+		}
+	}
+	return items
+}
+
+// unquoteYAML undoes strconv.Quote on a frontmatter value.
+func unquoteYAML(s string) string {
+	if u, err := strconv.Unquote(s); err == nil {
+		return u
+	}
+	return s
+}
+
+// skillYAML writes doc as YAML: one-line strings double-quoted as the
+// frontmatter is, and Markdown as literal blocks, so it reads as written.
+func skillYAML(doc SkillDocument) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "name: %s\n", yamlText(doc.Name, 1))
+	fmt.Fprintf(&b, "description: %s\n", yamlText(doc.Description, 1))
+	if len(doc.Frontmatter) > 0 {
+		b.WriteString("frontmatter:\n")
+		for _, k := range slices.Sorted(maps.Keys(doc.Frontmatter)) {
+			switch v := doc.Frontmatter[k].(type) {
+			case []string:
+				fmt.Fprintf(&b, "  %s:\n", k)
+				for _, item := range v {
+					fmt.Fprintf(&b, "    - %s\n", strconv.Quote(item))
+				}
+			case string:
+				fmt.Fprintf(&b, "  %s: %s\n", k, yamlText(v, 2))
+			}
+		}
+	}
+	if len(doc.Metadata) > 0 {
+		b.WriteString("metadata:\n")
+		for _, k := range slices.Sorted(maps.Keys(doc.Metadata)) {
+			fmt.Fprintf(&b, "  %s: %s\n", k, yamlText(doc.Metadata[k], 2))
+		}
+	}
+	if len(doc.Confidence) > 0 {
+		b.WriteString("confidence:\n")
+		for _, k := range slices.Sorted(maps.Keys(doc.Confidence)) {
+			c := doc.Confidence[k]
+			fmt.Fprintf(&b, "  %s:\n    level: %s\n    rationale: %s\n", k, yamlText(c.Level, 3), yamlText(c.Rationale, 3))
+		}
+	}
+	fmt.Fprintf(&b, "title: %s\n", yamlText(doc.Title, 1))
+	if doc.Intro != "" {
+		fmt.Fprintf(&b, "intro: %s\n", yamlText(doc.Intro, 1))
+	}
+	b.WriteString("sections:\n")
+	for _, s := range doc.Sections {
+		fmt.Fprintf(&b, "  - title: %s\n    content: %s\n", yamlText(s.Title, 3), yamlText(s.Content, 3))
+	}
+	return []byte(b.String())
+}
+
+// yamlText renders s as a YAML scalar whose lines are indented depth
+// levels: a literal block for multi-line text that one can hold, and a
+// double-quoted string otherwise.
+func yamlText(s string, depth int) string {
+	if !strings.Contains(s, "\n") || strings.HasPrefix(s, " ") || strings.HasPrefix(s, "\t") || strings.Contains(s, "\r") {
+		return strconv.Quote(s)
+	}
+	indent := strings.Repeat("  ", depth)
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		if line != "" {
+			lines[i] = indent + line
+		}
+	}
+	return "|-\n" + strings.Join(lines, "\n")
+}
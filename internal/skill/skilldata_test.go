@@ -0,0 +1,118 @@
+package skill
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/drpaneas/devlica/internal/analyzer"
+)
+
+func TestParseDataFormats(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    []DataFormat
+		wantErr bool
+	}{
+		{"json", []DataFormat{DataJSON}, false},
+		{" YAML , json,yaml", []DataFormat{DataYAML, DataJSON}, false},
+		{"toml", nil, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseDataFormats(tt.in)
+		if (err != nil) != tt.wantErr || strings.Join(dataStrings(got), ",") != strings.Join(dataStrings(tt.want), ",") {
+			t.Errorf("ParseDataFormats(%q) = %v, %v; want %v, error %v", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func dataStrings(formats []DataFormat) []string {
+	var s []string
+	for _, f := range formats {
+		s = append(s, string(f))
+	}
+	return s
+}
+
+func TestGenerate_SkillData(t *testing.T) {
+	dir := t.TempDir()
+	persona := &analyzer.Persona{
+		Synthesis: &analyzer.SynthesisResult{
+			CodeStyleRules: "Wrap errors.\n\n```md\n## not a section\n```",
+			ReviewVoice:    "Terse: \"why?\"",
+			Confidence:     map[string]analyzer.Confidence{"review_voice": {Level: "high", Rationale: "40 comments: consistent"}},
+		},
+		Metadata: analyzer.PersonaMetadata{Model: "m", GeneratedAt: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	gen := NewGenerator(dir)
+	gen.SetSkills([]string{SkillCodingStyle, SkillCodeReviewer})
+	gen.SetSkillData([]DataFormat{DataJSON, DataYAML})
+	paths, err := gen.Generate("testdev", persona)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reviewer := filepath.Join(dir, "testdev-code-reviewer")
+	want := []string{filepath.Join(reviewer, SkillFile), filepath.Join(reviewer, SkillJSONFile), filepath.Join(reviewer, SkillYAMLFile)}
+	if got := strings.Join(paths, "\n"); !strings.Contains(got, strings.Join(want, "\n")) {
+		t.Errorf("paths = %v, want the data files after their SKILL.md", paths)
+	}
+
+	var doc SkillDocument
+	readJSON(t, filepath.Join(reviewer, SkillJSONFile), &doc)
+	if doc.Name != "testdev-code-reviewer" || doc.Title != "testdev's Code Review Style" || doc.Metadata["model"] != "m" {
+		t.Errorf("skill.json = %+v", doc)
+	}
+	if c := doc.Confidence["review_voice"]; c.Level != "high" || c.Rationale != "40 comments: consistent" {
+		t.Errorf("confidence = %+v, want the unquoted rationale", doc.Confidence)
+	}
+	var voice string
+	for _, s := range doc.Sections {
+		if s.Title == "Feedback Style" {
+			voice = s.Content
+		}
+	}
+	if voice != `Terse: "why?"` {
+		t.Errorf("Feedback Style = %q, want the section's Markdown", voice)
+	}
+
+	var style SkillDocument
+	readJSON(t, filepath.Join(dir, "testdev-coding-style", SkillJSONFile), &style)
+	for _, s := range style.Sections {
+		if s.Title == "not a section" {
+			t.Error("a heading inside a code block should not start a section")
+		}
+	}
+
+	yaml, err := os.ReadFile(filepath.Join(reviewer, SkillYAMLFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{
+		"# devlica:begin",
+		"name: \"testdev-code-reviewer\"\n",
+		"  model: \"m\"\n",
+		"  review_voice:\n    level: \"high\"\n",
+		"  - title: \"Feedback Style\"\n    content: \"Terse: \\\"why?\\\"\"\n",
+	} {
+		if !strings.Contains(string(yaml), s) {
+			t.Errorf("skill.yaml is missing %q:\n%s", s, yaml)
+		}
+	}
+}
+
+func TestYAMLText(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"one line", `"one line"`},
+		{"- a\n\n\tb", "|-\n    - a\n\n    \tb"},
+		{" leading\nspace", `" leading\nspace"`},
+	}
+	for _, tt := range tests {
+		if got := yamlText(tt.in, 2); got != tt.want {
+			t.Errorf("yamlText(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package skill
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/drpaneas/devlica/internal/analyzer"
+)
+
+// The skills the Cursor format can write, each to <username>-<name>.
+const (
+	SkillCodingStyle         = "coding-style"
+	SkillCodeReviewer        = "code-reviewer"
+	SkillDeveloperProfile    = "developer-profile"
+	SkillCommitMessageWriter = "commit-message-writer"
+	SkillPRAuthor            = "pr-author"
+)
+
+// Skills lists every skill, in the order they are written.
+var Skills = []string{SkillCodingStyle, SkillCodeReviewer, SkillDeveloperProfile, SkillCommitMessageWriter, SkillPRAuthor}
+
+// DefaultSkills are written unless SetSkills says otherwise.
+var DefaultSkills = []string{SkillCodingStyle, SkillCodeReviewer, SkillDeveloperProfile}
+
+// ParseSkills parses a comma-separated list of skills. Names replace the
+// DefaultSkills, so "code-reviewer" writes only that one, while names
+// prefixed with "+" add to them, so "+pr-author" writes four. Skills are
+// returned in Skills order.
+func ParseSkills(s string) ([]string, error) {
+	var named, added []string
+	for _, name := range strings.Split(s, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		add := strings.HasPrefix(name, "+")
+		name = strings.TrimPrefix(name, "+")
+		if !slices.Contains(Skills, name) {
+			return nil, fmt.Errorf("unknown skill %q: must be one of %s", name, strings.Join(Skills, ", "))
+		}
+		if add {
+			added = append(added, name)
+		} else {
+			named = append(named, name)
+		}
+	}
+	if len(named) == 0 {
+		named = DefaultSkills
+	}
+	var skills []string
+	for _, name := range Skills {
+		if slices.Contains(named, name) || slices.Contains(added, name) {
+			skills = append(skills, name)
+		}
+	}
+	return skills, nil
+}
+
+// SkillsUsage describes the -skills flag, listing the skills.
+func SkillsUsage() string {
+	return "Comma-separated Cursor skills to write, or +name to add one to the defaults: " + strings.Join(Skills, ", ") +
+		" (default " + strings.Join(DefaultSkills, ",") + ")"
+}
+
+type commitMessageWriterData struct {
+	Username       string
+	CommitMessages string
+	skillMeta
+}
+
+type prAuthorData struct {
+	Username      string
+	Communication string
+	Testing       string
+	Collaboration string
+	skillMeta
+}
+
+// Synthesis fields the optional skills are built from, in section order.
+var (
+	commitMessageWriterFields = []string{"commit_message_style"}
+	prAuthorFields            = []string{"communication_patterns", "testing_philosophy", "collaboration_style"}
+)
+
+func (g *Generator) commitMessageWriterSkill(username string, persona *analyzer.Persona) ([]string, error) {
+	s := persona.Synthesis
+	data := commitMessageWriterData{
+		Username:       username,
+		CommitMessages: s.CommitMessageStyle,
+		skillMeta:      newSkillMeta(persona, commitMessageWriterFields),
+	}
+	if data.CommitMessages == "" {
+		data.CommitMessages = "No specific commit message data was identified."
+	}
+	paths, err := g.writeSkill(skillName(username, SkillCommitMessageWriter), commitMessageWriterTemplate, data)
+	if err != nil {
+		return nil, fmt.Errorf("generating commit message writer skill: %w", err)
+	}
+	return paths, nil
+}
+
+func (g *Generator) prAuthorSkill(username string, persona *analyzer.Persona) ([]string, error) {
+	s := persona.Synthesis
+	data := prAuthorData{
+		Username:      username,
+		Communication: s.CommunicationPatterns,
+		Testing:       s.TestingPhilosophy,
+		Collaboration: s.CollaborationStyle,
+		skillMeta:     newSkillMeta(persona, prAuthorFields),
+	}
+	if data.Communication == "" {
+		data.Communication = persona.Communication
+	}
+	if data.Communication == "" {
+		data.Communication = "No specific communication data was identified."
+	}
+	if data.Testing == "" {
+		data.Testing = "No specific testing data was identified."
+	}
+	if data.Collaboration == "" {
+		data.Collaboration = "No specific collaboration data was identified."
+	}
+	paths, err := g.writeSkill(skillName(username, SkillPRAuthor), prAuthorTemplate, data)
+	if err != nil {
+		return nil, fmt.Errorf("generating PR author skill: %w", err)
+	}
+	return paths, nil
+}
+
+// SkillFields maps each Cursor skill to the synthesis fields, by JSON name,
+// it is built from.
+func SkillFields() map[string][]string {
+	return maps.Clone(skillFields)
+}
@@ -0,0 +1,79 @@
+package skill
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/drpaneas/devlica/internal/analyzer"
+)
+
+func TestParseSkills(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    []string
+		wantErr bool
+	}{
+		{in: "code-reviewer", want: []string{SkillCodeReviewer}},
+		{in: "+pr-author", want: []string{SkillCodingStyle, SkillCodeReviewer, SkillDeveloperProfile, SkillPRAuthor}},
+		{in: "pr-author, Coding-Style,+commit-message-writer", want: []string{SkillCodingStyle, SkillCommitMessageWriter, SkillPRAuthor}},
+		{in: "linter", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseSkills(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseSkills(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("ParseSkills(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestGenerate_SelectedSkills(t *testing.T) {
+	dir := t.TempDir()
+	gen := NewGenerator(dir)
+	gen.SetSkills([]string{SkillCodeReviewer, SkillCommitMessageWriter, SkillPRAuthor})
+	persona := &analyzer.Persona{
+		Communication: "Writes terse PR descriptions.",
+		Synthesis: &analyzer.SynthesisResult{
+			CommitMessageStyle: "Conventional commits with a scope.",
+			TestingPhilosophy:  "Lists the commands run.",
+			Evidence:           map[string][]string{"commit_message_style": {"https://github.com/a/b/commit/1"}},
+		},
+	}
+
+	paths, err := gen.Generate("testdev", persona)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, p := range paths {
+		names = append(names, filepath.Base(filepath.Dir(p)))
+	}
+	if want := "testdev-code-reviewer testdev-commit-message-writer testdev-pr-author"; strings.Join(names, " ") != want {
+		t.Fatalf("wrote %v, want %s", names, want)
+	}
+
+	commit, err := os.ReadFile(paths[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{"name: testdev-commit-message-writer", "Conventional commits with a scope.", "### Commit Messages\n\n- https://github.com/a/b/commit/1"} {
+		if !strings.Contains(string(commit), s) {
+			t.Errorf("commit message writer skill is missing %q:\n%s", s, commit)
+		}
+	}
+	pr, err := os.ReadFile(paths[2])
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{"name: testdev-pr-author", "Writes terse PR descriptions.", "Lists the commands run.", "No specific collaboration data was identified."} {
+		if !strings.Contains(string(pr), s) {
+			t.Errorf("PR author skill is missing %q:\n%s", s, pr)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package skill
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+// StreamFormat is how a streaming Generator writes its files.
+type StreamFormat string
+
+// The supported stream formats.
+const (
+	// StreamText writes each file after a "==> path <==" header line.
+	StreamText StreamFormat = "text"
+	// StreamJSON writes one JSON object mapping each path to its content.
+	StreamJSON StreamFormat = "json"
+	// StreamPreview writes a list of the files and then each file under a
+	// ruled banner, plain text to read in a terminal or a pager.
+	StreamPreview StreamFormat = "preview"
+)
+
+// previewRule frames each file's path in a preview.
+var previewRule = strings.Repeat("=", 72)
+
+// ParseStreamFormat parses "text", "json", or "preview".
+func ParseStreamFormat(s string) (StreamFormat, error) {
+	switch f := StreamFormat(strings.ToLower(strings.TrimSpace(s))); f {
+	case StreamText, StreamJSON, StreamPreview:
+		return f, nil
+	}
+	return "", fmt.Errorf("unknown stream format %q: must be text, json, or preview", s)
+}
+
+// streamFile is a generated file held back for the stream.
+type streamFile struct {
+	path    string
+	content []byte
+}
+
+// SetStream makes Generate write every file to w, in format, instead of
+// under the output directory. Paths in the stream, and those Generate
+// returns, are relative to the output directory.
+func (g *Generator) SetStream(w io.Writer, format StreamFormat) {
+	g.stream = w
+	g.streamFormat = format
+}
+
+// flushStream writes the files collected by one Generate call to the
+// stream, all at once so a failed run leaves no partial JSON behind.
+func (g *Generator) flushStream() error {
+	files := g.streamed
+	g.streamed = nil
+	var err error
+	switch g.streamFormat {
+	case StreamJSON:
+		m := make(map[string]string, len(files))
+		for _, f := range files {
+			m[f.path] = string(f.content)
+		}
+		enc := json.NewEncoder(g.stream)
+		enc.SetEscapeHTML(false)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(m)
+	case StreamPreview:
+		_, err = io.WriteString(g.stream, preview(files))
+	default:
+		var b strings.Builder
+		for i, f := range files {
+			if i > 0 {
+				b.WriteString("\n")
+			}
+			fmt.Fprintf(&b, "==> %s <==\n%s", f.path, f.content)
+		}
+		_, err = io.WriteString(g.stream, b.String())
+	}
+	if err != nil {
+		return fmt.Errorf("writing generated files to stream: %w", err)
+	}
+	return nil
+}
+
+// preview lays out files for reading: a contents list with each file's
+// line count, then every file under a banner with its path.
+func preview(files []streamFile) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Preview of %d generated files; nothing was written.\n\n", len(files))
+	width := 0
+	for _, f := range files {
+		width = max(width, len(f.path))
+	}
+	for _, f := range files {
+		fmt.Fprintf(&b, "  %-*s  %4d lines\n", width, f.path, bytes.Count(f.content, []byte("\n")))
+	}
+	for _, f := range files {
+		fmt.Fprintf(&b, "\n%s\n%s\n%s\n\n%s", previewRule, f.path, previewRule, f.content)
+		if len(f.content) > 0 && f.content[len(f.content)-1] != '\n' {
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
+
+// streamPath is path relative to the output directory, slash-separated.
+func (g *Generator) streamPath(path string) string {
+	if rel, err := filepath.Rel(g.outputDir, path); err == nil {
+		path = rel
+	}
+	return filepath.ToSlash(path)
+}
@@ -0,0 +1,109 @@
+package skill
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/drpaneas/devlica/internal/analyzer"
+)
+
+func TestGenerate_Stream(t *testing.T) {
+	persona := &analyzer.Persona{
+		Synthesis: &analyzer.SynthesisResult{CodeStyleRules: "- Wrap errors with context"},
+	}
+	wantPaths := []string{
+		"testdev-coding-style/SKILL.md",
+		"testdev-code-reviewer/SKILL.md",
+		"testdev-developer-profile/SKILL.md",
+		"testdev/AGENTS.md",
+	}
+
+	t.Run("text", func(t *testing.T) {
+		dir := t.TempDir()
+		var out bytes.Buffer
+		gen := NewGenerator(dir)
+		gen.SetFormats([]Format{FormatCursor, FormatAgents})
+		gen.SetStream(&out, StreamText)
+		paths, err := gen.Generate("testdev", persona)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.Join(paths, "\n") != strings.Join(wantPaths, "\n") {
+			t.Errorf("paths = %v, want %v", paths, wantPaths)
+		}
+		if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+			t.Errorf("streaming wrote %d entries to the output directory", len(entries))
+		}
+		got := out.String()
+		if !strings.HasPrefix(got, "==> testdev-coding-style/SKILL.md <==\n---\n") {
+			t.Errorf("stream should open with the first file's header:\n%.200s", got)
+		}
+		for _, p := range wantPaths {
+			if !strings.Contains(got, "==> "+p+" <==\n") {
+				t.Errorf("stream is missing %s", p)
+			}
+		}
+		if !strings.Contains(got, "Wrap errors with context") {
+			t.Error("stream is missing the generated content")
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		var out bytes.Buffer
+		gen := NewGenerator("")
+		gen.SetFormats([]Format{FormatCursor, FormatAgents})
+		gen.SetStream(&out, StreamJSON)
+		if _, err := gen.Generate("testdev", persona); err != nil {
+			t.Fatal(err)
+		}
+		var files map[string]string
+		if err := json.Unmarshal(out.Bytes(), &files); err != nil {
+			t.Fatalf("stream is not a JSON object: %v", err)
+		}
+		if len(files) != len(wantPaths) {
+			t.Errorf("got %d files, want %d", len(files), len(wantPaths))
+		}
+		if !strings.Contains(files["testdev-coding-style/SKILL.md"], "Wrap errors with context") {
+			t.Errorf("coding style skill = %q", files["testdev-coding-style/SKILL.md"])
+		}
+	})
+}
+
+func TestGenerate_Preview(t *testing.T) {
+	persona := &analyzer.Persona{
+		Synthesis: &analyzer.SynthesisResult{CodeStyleRules: "- Wrap errors with context"},
+	}
+	var out bytes.Buffer
+	gen := NewGenerator("")
+	gen.SetFormats([]Format{FormatAgents, FormatAider})
+	gen.SetStream(&out, StreamPreview)
+	if _, err := gen.Generate("testdev", persona); err != nil {
+		t.Fatal(err)
+	}
+	got := out.String()
+	if !strings.HasPrefix(got, "Preview of 2 generated files; nothing was written.\n\n  testdev/AGENTS.md      ") {
+		t.Errorf("preview should open with the list of files:\n%.300s", got)
+	}
+	for _, p := range []string{"testdev/AGENTS.md", "testdev/CONVENTIONS.md"} {
+		if !strings.Contains(got, previewRule+"\n"+p+"\n"+previewRule+"\n\n") {
+			t.Errorf("preview is missing the banner for %s", p)
+		}
+	}
+	if !strings.Contains(got, "Wrap errors with context") {
+		t.Error("preview is missing the generated content")
+	}
+}
+
+func TestParseStreamFormat(t *testing.T) {
+	for in, want := range map[string]StreamFormat{"text": StreamText, "JSON": StreamJSON, "preview": StreamPreview} {
+		if got, err := ParseStreamFormat(in); err != nil || got != want {
+			t.Errorf("ParseStreamFormat(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	if _, err := ParseStreamFormat("yaml"); err == nil {
+		t.Error("ParseStreamFormat(\"yaml\") should fail")
+	}
+}
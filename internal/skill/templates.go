@@ -1,95 +1,565 @@
 package skill
 
+// partialsTemplate defines the blocks the templates share: a skill's
+// frontmatter metadata, the sparse profile caveat, in full for skills and
+// brief for instruction files, and a skill's evidence appendix.
+const partialsTemplate = `{{define "frontmatter-meta"}}
+{{- if .Provenance}}
+metadata:
+{{- range .Provenance}}
+  {{.Key}}: {{.Value}}
+{{- end}}
+{{- end}}
+{{- if .Confidence}}
+confidence:
+{{- range .Confidence}}
+  {{.Field}}:
+    level: {{.Level}}
+    rationale: {{.Rationale}}
+{{- end}}
+{{- end}}
+{{- end}}
+{{define "sparse"}}
+{{- if .Sparse}}
+
+> **Sparse profile:** {{.Sparse}} Treat every section as a best-effort sketch rather than an established habit, and fall back to the project's own conventions where a section says there was not enough activity to tell.
+{{- end}}
+{{- end}}
+{{define "sparse-brief"}}
+{{- if .Sparse}}
+
+> **Sparse profile:** {{.Sparse}} Treat every section as a best-effort sketch rather than an established habit.
+{{- end}}
+{{- end}}
+{{define "evidence"}}{{if .Evidence}}
+## Appendix: Evidence
+
+Activity each section above is based on.{{if .Examples}} EXAMPLES.md next to this file quotes it.{{end}}
+{{range .Evidence}}
+### {{.Title}}
+
+{{range .URLs}}- {{.}}
+{{end}}{{end}}{{end}}{{end}}`
+
 const codingStyleTemplate = `---
-name: {{.Username}}-coding-style
+name: {{skill .Username "coding-style"}}
 description: Write code in {{.Username}}'s style - captures their naming conventions, code organization, error handling, testing patterns, and coding philosophy. Use when asked to write code like {{.Username}} or to emulate their coding approach.
+{{- template "frontmatter-meta" .}}
 ---
 
 # {{.Username}}'s Coding Style
 
 This skill was auto-generated by Devlica from {{.Username}}'s GitHub activity.
+{{- template "sparse" .}}
 
 ## Coding Philosophy
 
-{{.Philosophy}}
+{{demote 3 .Philosophy}}
 
 ## Code Style Rules
 
-{{.CodeStyle}}
+{{demote 3 .CodeStyle}}
+{{if .LanguageStyles}}
+## Language-Specific Style
+
+The rules above apply everywhere. These refine them per language.
+{{range .LanguageStyles}}
+### {{.Language}}
+
+{{demote 4 .Rules}}
+{{end}}{{end}}
+## Commit Messages
+
+{{demote 3 .CommitMessages}}
 
 ## Testing Approach
 
-{{.Testing}}
+{{demote 3 .Testing}}
+
+## Project Patterns
 
-## Automation And Project Patterns
+{{demote 3 .ProjectPatterns}}
 
-{{.ProjectPatterns}}
+## Automation
+
+{{demote 3 .Automation}}
 
 ## Code Examples
 
-{{.CodeExamples}}
+{{demote 3 .CodeExamples}}
+
+## Style Evolution
+
+{{demote 3 .StyleEvolution}}
 
 ## Distinctive Traits
 
-{{.Traits}}
-`
+{{demote 3 .Traits}}
+
+## Never Do
+
+{{demote 3 .AntiPatterns}}
+{{template "evidence" .}}`
 
 const codeReviewerTemplate = `---
-name: {{.Username}}-code-reviewer
+name: {{skill .Username "code-reviewer"}}
 description: Review code like {{.Username}} - captures their review priorities, feedback style, and what they look for in pull requests. Use when asked to review code as {{.Username}} or to emulate their review approach.
+{{- template "frontmatter-meta" .}}
 ---
 
 # {{.Username}}'s Code Review Style
 
 This skill was auto-generated by Devlica from {{.Username}}'s GitHub activity.
+{{- template "sparse" .}}
 
 ## Review Priorities
 
-{{.ReviewPriorities}}
+{{demote 3 .ReviewPriorities}}
 
 ## Approval Thresholds
 
-{{.ReviewDecision}}
+{{demote 3 .ReviewDecision}}
 
 ## Non-Blocking Nits
 
-{{.ReviewNits}}
+{{demote 3 .ReviewNits}}
 
 ## Context Sensitivity
 
-{{.ReviewContext}}
+{{demote 3 .ReviewContext}}
 
 ## Feedback Style
 
-{{.ReviewVoice}}
+{{demote 3 .ReviewVoice}}
+{{if or .Phrases .AvoidPhrases}}
+## Signature Phrases
+{{if .Phrases}}
+Counted across {{.PhraseComments}} of {{.Username}}'s comments. Use them where they fit, at about these rates; do not force one into every comment.
+
+{{range .Phrases}}- {{.Text}} ({{.Kind}}, {{.Share}} of comments)
+{{end}}{{end}}{{if .AvoidPhrases}}
+Avoid these; {{.Username}} never or almost never writes them: {{.AvoidPhrases}}
+{{end}}{{end}}
+## Never Do
+
+Flag any of these when you see them; {{.Username}} does.
+
+{{demote 3 .AntiPatterns}}
 
 ## Collaboration Style
 
-{{.CollaborationStyle}}
-`
+{{demote 3 .CollaborationStyle}}
+{{template "evidence" .}}`
 
 const developerProfileTemplate = `---
-name: {{.Username}}-developer-profile
+name: {{skill .Username "developer-profile"}}
 description: Understand {{.Username}}'s developer identity - their interests, community engagement, and what drives them as an engineer. Use when you need context on what {{.Username}} cares about professionally.
+{{- template "frontmatter-meta" .}}
 ---
 
 # {{.Username}}'s Developer Profile
 
 This skill was auto-generated by Devlica from {{.Username}}'s GitHub activity.
+{{- template "sparse" .}}
 
 ## Interests and Focus Areas
 
-{{.DeveloperInterests}}
+{{demote 3 .DeveloperInterests}}
 
 ## Activity Patterns
 
-{{.ActivityPatterns}}
+{{demote 3 .ActivityPatterns}}
 
 ## Collaboration Style
 
-{{.CollaborationStyle}}
+{{demote 3 .CollaborationStyle}}
+
+## Maintainer Behavior
+
+{{demote 3 .MaintainerBehavior}}
 
 ## Distinctive Traits
 
-{{.Traits}}
+{{demote 3 .Traits}}
+{{template "evidence" .}}`
+
+const copilotTemplate = `# Coding like {{.Username}}
+
+Write and review code the way {{.Username}} does. These instructions were auto-generated by Devlica from {{.Username}}'s GitHub activity; where the repository's own conventions differ, follow the repository.
+{{- template "sparse-brief" .}}
+{{range .Sections}}
+## {{.Title}}
+
+{{.Body}}
+{{end}}`
+
+const copilotPathTemplate = `---
+applyTo: "{{.ApplyTo}}"
+---
+
+# {{.Language}} like {{.Username}}
+
+These refine the repository-wide instructions for {{.Language}} files.
+
+{{.Rules}}
+`
+
+const agentsTemplate = `# Working like {{.Username}}
+
+Write, review, and discuss code the way {{.Username}} does. These instructions were auto-generated by Devlica from {{.Username}}'s GitHub activity; where this repository's own conventions differ, follow the repository.
+{{- template "sparse-brief" .}}
+
+## Writing Code
+{{if .Philosophy}}
+### Philosophy
+
+{{.Philosophy}}
+{{end}}{{if .CodeStyle}}
+### Style Rules
+
+{{.CodeStyle}}
+{{end}}{{range .LanguageStyles}}
+### {{.Language}}
+
+{{.Rules}}
+{{end}}{{if .Testing}}
+### Testing
+
+{{.Testing}}
+{{end}}{{if .AntiPatterns}}
+### Never Do
+
+{{.AntiPatterns}}
+{{end}}{{if .CommitMessages}}
+## Commit Messages
+
+{{.CommitMessages}}
+{{end}}{{if or .ReviewPriorities .ReviewDecision .ReviewNits .ReviewVoice}}
+## Reviewing Code
+{{if .ReviewPriorities}}
+### Priorities
+
+{{.ReviewPriorities}}
+{{end}}{{if .ReviewDecision}}
+### Approval Thresholds
+
+{{.ReviewDecision}}
+{{end}}{{if .ReviewNits}}
+### Non-Blocking Nits
+
+{{.ReviewNits}}
+{{end}}{{if .ReviewVoice}}
+### Feedback Style
+
+{{.ReviewVoice}}
+{{end}}{{end}}{{if or .Collaboration .Communication .Maintainer}}
+## Collaboration
+{{if .Communication}}
+### Communication
+
+{{.Communication}}
+{{end}}{{if .Collaboration}}
+### Working With Others
+
+{{.Collaboration}}
+{{end}}{{if .Maintainer}}
+### Issues and Maintenance
+
+{{.Maintainer}}
+{{end}}{{end}}`
+
+const commitMessageWriterTemplate = `---
+name: {{skill .Username "commit-message-writer"}}
+description: Write commit messages like {{.Username}} - captures their subject line format, body conventions, and references. Use when asked to write or review a commit message as {{.Username}} would.
+{{- template "frontmatter-meta" .}}
+---
+
+# {{.Username}}'s Commit Messages
+
+This skill was auto-generated by Devlica from {{.Username}}'s GitHub activity.
+{{- template "sparse" .}}
+
+Read the staged diff, then write the message {{.Username}} would: describe what the change does, in their format, and nothing the diff does not support.
+
+## Commit Messages
+
+{{demote 3 .CommitMessages}}
+{{template "evidence" .}}`
+
+const prAuthorTemplate = `---
+name: {{skill .Username "pr-author"}}
+description: Write pull request descriptions and answer review feedback like {{.Username}} - captures how they explain a change, what they say about testing, and how they respond to reviewers. Use when opening or updating a pull request as {{.Username}} would.
+{{- template "frontmatter-meta" .}}
+---
+
+# {{.Username}}'s Pull Requests
+
+This skill was auto-generated by Devlica from {{.Username}}'s GitHub activity.
+{{- template "sparse" .}}
+
+## Communication Patterns
+
+{{demote 3 .Communication}}
+
+## Testing Approach
+
+Say how the change was tested the way {{.Username}} would.
+
+{{demote 3 .Testing}}
+
+## Collaboration Style
+
+{{demote 3 .Collaboration}}
+{{template "evidence" .}}`
+
+const examplesTemplate = `# {{.Skill}}: Examples
+
+Real activity by {{.Username}} behind the sections of SKILL.md, quoted as written. Use it to match their tone, phrasing, and level of detail; do not reuse its content.
+{{range .Sections}}
+## {{.Title}}
+{{range .Examples}}
+**{{.Kind}}**{{if .Context}} in {{.Context}}{{end}} ([source]({{.URL}}))
+
+{{.Quote}}
+{{end}}{{end}}`
+
+const continueCodingStyleTemplate = `---
+name: {{.Username}} coding style
+description: Write code the way {{.Username}} does, from their philosophy, style rules, testing habits, and commit messages.
+alwaysApply: true
+---
+
+# Coding like {{.Username}}
+
+These rules were auto-generated by Devlica from {{.Username}}'s GitHub activity; where this repository's own conventions differ, follow the repository.
+{{- template "sparse-brief" .}}
+{{if .Philosophy}}
+## Philosophy
+
+{{.Philosophy}}
+{{end}}{{if .CodeStyle}}
+## Style Rules
+
+{{.CodeStyle}}
+{{end}}{{if .Testing}}
+## Testing
+
+{{.Testing}}
+{{end}}{{if .AntiPatterns}}
+## Never Do
+
+{{.AntiPatterns}}
+{{end}}{{if .CommitMessages}}
+## Commit Messages
+
+{{.CommitMessages}}
+{{end}}`
+
+const continueReviewTemplate = `---
+name: {{.Username}} code review
+description: Review code the way {{.Username}} does. Use when asked to review a diff, pull request, or change.
+alwaysApply: false
+---
+
+# Reviewing like {{.Username}}
+
+These rules were auto-generated by Devlica from {{.Username}}'s review history.
+{{if .ReviewPriorities}}
+## Priorities
+
+{{.ReviewPriorities}}
+{{end}}{{if .ReviewDecision}}
+## Approval Thresholds
+
+{{.ReviewDecision}}
+{{end}}{{if .ReviewNits}}
+## Non-Blocking Nits
+
+{{.ReviewNits}}
+{{end}}{{if .ReviewVoice}}
+## Feedback Style
+
+{{.ReviewVoice}}
+{{end}}`
+
+const continueLanguageTemplate = `---
+name: {{.Username}} {{.Language}} style
+description: How {{.Username}} writes {{.Language}}.
+globs:
+{{- range .Globs}}
+  - "{{.}}"
+{{- end}}
+---
+
+# {{.Language}} like {{.Username}}
+
+These refine the coding style rule for {{.Language}} files.
+
+{{.Rules}}
+`
+
+const aiderTemplate = `# Conventions of {{.Username}}
+
+Write code and commits the way {{.Username}} does. These conventions were auto-generated by Devlica from {{.Username}}'s GitHub activity; where this repository's own conventions differ, follow the repository.
+{{- template "sparse-brief" .}}
+{{if .Philosophy}}
+## Philosophy
+
+{{.Philosophy}}
+{{end}}{{if .CodeStyle}}
+## Style Rules
+
+{{.CodeStyle}}
+{{end}}{{range .LanguageStyles}}
+## {{.Language}}
+
+{{.Rules}}
+{{end}}{{if .Testing}}
+## Testing
+
+{{.Testing}}
+{{end}}{{if .AntiPatterns}}
+## Never Do
+
+{{.AntiPatterns}}
+{{end}}{{if .CommitMessages}}
+## Commit Messages
+
+{{.CommitMessages}}
+{{end}}`
+
+const languageStyleTemplate = `---
+name: {{.Name}}
+description: Write {{.Language}} in {{.Username}}'s style - captures the {{.Language}}-specific conventions they follow on top of their general coding style. Use when writing or reviewing {{.Language}} code as {{.Username}} would.
+{{- template "frontmatter-meta" .}}
+---
+
+# {{.Username}}'s {{.Language}} Style
+
+This skill was auto-generated by Devlica from {{.Username}}'s GitHub activity. It refines {{skill .Username "coding-style"}} for {{.Language}} files; follow that skill for everything not covered here.
+{{- template "sparse" .}}
+
+## {{.Language}} Rules
+
+{{demote 3 .Rules}}
+{{- if .Examples}}
+
+## Examples
+
+EXAMPLES.md next to this file quotes {{.Username}}'s review comments on {{.Language}} files.
+{{- end}}
+`
+
+const checklistTemplate = `# Review checklist
+
+What {{.Username}} checks when reviewing a pull request, most important first. Auto-generated by Devlica from {{.Username}}'s review history; use it as a pull request template or as a review bot's rubric.
+
+## Before approving
+
+{{range .Items}}- [ ] {{.}}
+{{end}}{{if .Nits}}
+## Worth a comment, not a block
+
+{{range .Nits}}- [ ] {{.}}
+{{end}}{{end}}`
+
+const commitStyleTemplate = `# Commit messages of {{.Username}}
+
+How {{.Username}} writes commit messages, auto-generated by Devlica from their GitHub activity. ` + "`.gitmessage`" + ` next to this file puts the same conventions in your editor: ` + "`git config commit.template .gitmessage`" + `.
+{{if .Stats}}
+## At a glance
+
+Measured over {{.Commits}} commits, merges excluded:
+
+{{range .Stats}}- {{.}}
+{{end}}{{end}}{{if .Guide}}
+## Guide
+
+{{.Guide}}
+{{end}}`
+
+const prTemplateTemplate = `<!-- Pull request template modeled on how {{.Username}} writes descriptions, auto-generated by Devlica from {{.Total}} of their pull requests. -->
+{{- if .Hint}}
+
+<!-- {{.Hint}} -->
+{{- end}}
+{{- range .Sections}}
+
+## {{.Title}}
+{{if .Hint}}
+<!-- {{.Hint}} -->
+{{end}}{{if .Items}}
+{{range .Items}}- [ ] {{.}}
+{{end}}{{end}}{{end}}
+`
+
+const cardTemplate = `# {{.Username}}
+{{if .Headline}}
+> {{.Headline}}
+{{end}}{{if .Sparse}}
+*{{.Sparse}}*
+{{end}}{{if .Traits}}
+## Top traits
+
+{{range .Traits}}- {{.}}
+{{end}}{{end}}{{if .Stats}}
+## Key stats
+
+{{range .Stats}}- **{{.Label}}:** {{.Value}}
+{{end}}{{end}}{{if .Score}}
+## Benchmark
+
+Scored **{{.Score}}** at predicting review comments, pull request descriptions, commit messages, and issue replies held out from the analysis.
+{{end}}
+---
+
+{{.Footer}}
+`
+
+const cardHTMLTemplate = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Username}}</title>
+<style>
+body { font-family: system-ui, sans-serif; max-width: 40rem; margin: 2rem auto; padding: 0 1rem; line-height: 1.5; color: #222; }
+blockquote { font-size: 1.2rem; margin: 1rem 0; padding-left: 1rem; border-left: 4px solid #888; }
+dt { font-weight: bold; }
+dd { margin: 0 0 0.5rem 0; }
+.score { font-size: 2rem; font-weight: bold; }
+footer { margin-top: 2rem; color: #666; font-size: 0.9rem; }
+</style>
+</head>
+<body>
+<h1>{{.Username}}</h1>
+{{- if .Headline}}
+<blockquote>{{.Headline}}</blockquote>
+{{- end}}
+{{- if .Sparse}}
+<p><em>{{.Sparse}}</em></p>
+{{- end}}
+{{- if .Traits}}
+<h2>Top traits</h2>
+<ul>
+{{- range .Traits}}
+<li>{{.}}</li>
+{{- end}}
+</ul>
+{{- end}}
+{{- if .Stats}}
+<h2>Key stats</h2>
+<dl>
+{{- range .Stats}}
+<dt>{{.Label}}</dt><dd>{{.Value}}</dd>
+{{- end}}
+</dl>
+{{- end}}
+{{- if .Score}}
+<h2>Benchmark</h2>
+<p><span class="score">{{.Score}}</span> at predicting review comments, pull request descriptions, commit messages, and issue replies held out from the analysis.</p>
+{{- end}}
+<footer>{{.Footer}}</footer>
+</body>
+</html>
 `
@@ -0,0 +1,71 @@
+package skill
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/drpaneas/devlica/internal/analyzer"
+)
+
+// LatestLink is the symlink in a user's output directory that points at
+// the newest version directory.
+const LatestLink = "latest"
+
+// versionLayout names a version directory. It sorts by time and has no
+// characters that are awkward in paths on any platform.
+const versionLayout = "20060102T150405Z"
+
+// VersionDir returns <outputDir>/<username>/<timestamp>, the directory a
+// versioned run generated at t writes into, so successive runs sit side by
+// side instead of overwriting each other.
+func VersionDir(outputDir, username string, t time.Time) string {
+	return filepath.Join(outputDir, username, t.UTC().Format(versionLayout))
+}
+
+// FinishVersion writes a ManifestName listing the generated files at paths,
+// which must be under versionDir, with the persona provenance in meta, then
+// points the LatestLink next to versionDir at it. It returns the manifest's
+// path.
+func FinishVersion(versionDir, username string, meta analyzer.PersonaMetadata, paths []string) (string, error) {
+	manifest, _, err := newManifest(versionDir, username, meta, paths)
+	if err != nil {
+		return "", fmt.Errorf("writing version manifest: %w", err)
+	}
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("encoding version manifest: %w", err)
+	}
+	path := filepath.Join(versionDir, ManifestName)
+	if err := writeFile(path, append(data, '\n')); err != nil {
+		return "", err
+	}
+	if err := linkLatest(versionDir); err != nil {
+		return "", fmt.Errorf("linking latest version: %w", err)
+	}
+	return path, nil
+}
+
+// linkLatest points the LatestLink next to versionDir at it, relative so
+// the output directory can be moved. The new link is renamed over the old
+// one so the link is never missing. A LatestLink that is not a symlink is
+// left alone.
+func linkLatest(versionDir string) error {
+	parent := filepath.Dir(versionDir)
+	link := filepath.Join(parent, LatestLink)
+	if fi, err := os.Lstat(link); err == nil && fi.Mode()&os.ModeSymlink == 0 {
+		return fmt.Errorf("%s exists and is not a symlink", link)
+	}
+	tmp := link + ".tmp"
+	os.Remove(tmp)
+	if err := os.Symlink(filepath.Base(versionDir), tmp); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, link); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return nil
+}
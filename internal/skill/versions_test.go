@@ -0,0 +1,74 @@
+package skill
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/drpaneas/devlica/internal/analyzer"
+)
+
+func TestVersionDir(t *testing.T) {
+	at := time.Date(2026, 3, 4, 5, 6, 7, 0, time.FixedZone("CET", 3600))
+	if got, want := VersionDir("out", "octocat", at), filepath.Join("out", "octocat", "20260304T040607Z"); got != want {
+		t.Errorf("VersionDir() = %q, want %q", got, want)
+	}
+}
+
+func TestFinishVersion(t *testing.T) {
+	out := t.TempDir()
+	persona := &analyzer.Persona{Synthesis: &analyzer.SynthesisResult{CodeStyleRules: "- Wrap errors"}}
+	meta := analyzer.PersonaMetadata{Username: "octocat", Model: "m1"}
+
+	var versions []string
+	for i, at := range []time.Time{time.Unix(1000, 0), time.Unix(2000, 0)} {
+		dir := VersionDir(out, "octocat", at)
+		gen := NewGenerator(dir)
+		gen.SetFormats([]Format{FormatAgents})
+		paths, err := gen.Generate("octocat", persona)
+		if err != nil {
+			t.Fatal(err)
+		}
+		manifestPath, err := FinishVersion(dir, "octocat", meta, paths)
+		if err != nil {
+			t.Fatalf("FinishVersion() #%d: %v", i, err)
+		}
+		data, err := os.ReadFile(manifestPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var manifest Manifest
+		if err := json.Unmarshal(data, &manifest); err != nil {
+			t.Fatal(err)
+		}
+		if manifest.Persona.Model != "m1" || len(manifest.Files) != 1 || manifest.Files[0].Path != "octocat/AGENTS.md" {
+			t.Errorf("manifest = %+v, want the persona provenance and one file", manifest)
+		}
+		versions = append(versions, dir)
+	}
+
+	for _, dir := range versions {
+		if _, err := os.Stat(filepath.Join(dir, "octocat", "AGENTS.md")); err != nil {
+			t.Errorf("every version should be kept: %v", err)
+		}
+	}
+	target, err := os.Readlink(filepath.Join(out, "octocat", LatestLink))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if target != filepath.Base(versions[1]) {
+		t.Errorf("latest -> %q, want the newest version %q", target, filepath.Base(versions[1]))
+	}
+}
+
+func TestFinishVersionKeepsLatestDir(t *testing.T) {
+	out := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(out, "octocat", LatestLink), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := FinishVersion(VersionDir(out, "octocat", time.Now()), "octocat", analyzer.PersonaMetadata{}, nil); err == nil {
+		t.Error("a latest directory that is not a symlink should not be replaced")
+	}
+}
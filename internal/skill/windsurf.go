@@ -0,0 +1,35 @@
+package skill
+
+import (
+	"fmt"
+	"log/slog"
+	"path/filepath"
+
+	"github.com/drpaneas/devlica/internal/analyzer"
+)
+
+// WindsurfRulesFile is the workspace rules file Windsurf reads from the
+// root of a repository.
+const WindsurfRulesFile = ".windsurfrules"
+
+// windsurfMaxChars is Windsurf's limit on a workspace rules file; the rest
+// of a longer file is ignored.
+const windsurfMaxChars = 6000
+
+// generateWindsurf writes the persona as a .windsurfrules file. Windsurf
+// has no path-scoped rules in this file, so each language style becomes a
+// section of its own, and like the Copilot instructions the lowest-priority
+// sections are trimmed to fit windsurfMaxChars.
+func (g *Generator) generateWindsurf(username string, persona *analyzer.Persona) ([]string, error) {
+	data := copilotData{Username: username, Sparse: sparseCaveat(persona.Sparse), Sections: copilotSections(persona, true)}
+	content, err := renderCopilot(data, "windsurf", windsurfMaxChars)
+	if err != nil {
+		return nil, fmt.Errorf("generating Windsurf rules: %w", err)
+	}
+	path, err := g.write(filepath.Join(g.outputDir, username, WindsurfRulesFile), content)
+	if err != nil {
+		return nil, fmt.Errorf("generating Windsurf rules: %w", err)
+	}
+	slog.Info("wrote Windsurf rules", "path", path)
+	return []string{path}, nil
+}
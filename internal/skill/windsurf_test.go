@@ -0,0 +1,47 @@
+package skill
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/drpaneas/devlica/internal/analyzer"
+)
+
+func TestGenerate_Windsurf(t *testing.T) {
+	dir := t.TempDir()
+	gen := NewGenerator(dir)
+	gen.SetFormats([]Format{FormatWindsurf})
+	persona := &analyzer.Persona{
+		Synthesis: &analyzer.SynthesisResult{
+			CodeStyleRules:  "- Wrap errors with context",
+			AntiPatterns:    "- Never panic in library code",
+			ProjectPatterns: strings.Repeat("- Keep packages small\n", 400),
+		},
+		LanguageStyles: []analyzer.LanguageStyle{{Language: "Go", Rules: "- Table-driven tests"}},
+	}
+
+	paths, err := gen.Generate("testdev", persona)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := filepath.Join(dir, "testdev", WindsurfRulesFile)
+	if len(paths) != 1 || paths[0] != want {
+		t.Fatalf("paths = %v, want [%s]", paths, want)
+	}
+	content, err := os.ReadFile(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(content) > windsurfMaxChars {
+		t.Errorf(".windsurfrules is %d chars, want at most %d", len(content), windsurfMaxChars)
+	}
+	got := string(content)
+	neverDo := strings.Index(got, "## Never Do")
+	goRules := strings.Index(got, "## Go\n\n- Table-driven tests")
+	patterns := strings.Index(got, "## Project Patterns\n\n- Keep packages small")
+	if neverDo < 0 || goRules < neverDo || patterns < goRules {
+		t.Errorf("want the Go rules after Never Do and the trimmed project patterns last:\n%s", got)
+	}
+}
@@ -0,0 +1,238 @@
+// Package stats computes hard, non-LLM metrics from crawled GitHub data so
+// the persona analysis can be grounded in numbers rather than impressions.
+package stats
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/drpaneas/devlica/internal/ghcrawl"
+)
+
+// CommitMessageStats describes the shape of a developer's commit messages.
+// Rates are fractions in [0, 1] of the non-merge commits.
+type CommitMessageStats struct {
+	Total              int            `json:"total"`
+	Merges             int            `json:"merges"`
+	SubjectLenMedian   int            `json:"subject_len_median"`
+	SubjectLenP90      int            `json:"subject_len_p90"`
+	SubjectLenMean     float64        `json:"subject_len_mean"`
+	BodyRate           float64        `json:"body_rate"`
+	ConventionalRate   float64        `json:"conventional_rate"`
+	ConventionalTypes  map[string]int `json:"conventional_types,omitempty"`
+	ScopeRate          float64        `json:"scope_rate"`
+	EmojiRate          float64        `json:"emoji_rate"`
+	ImperativeRate     float64        `json:"imperative_rate"`
+	CapitalizedRate    float64        `json:"capitalized_rate"`
+	TrailingPeriodRate float64        `json:"trailing_period_rate"`
+	IssueRefRate       float64        `json:"issue_ref_rate"`
+}
+
+var (
+	conventionalSubject = regexp.MustCompile(`^([a-z]+)(\([^)]*\))?!?: \S`)
+	gitmojiShortcode    = regexp.MustCompile(`:[a-z0-9_+-]+:`)
+	issueRef            = regexp.MustCompile(`(^|[\s(])([\w.-]+/[\w.-]+)?#\d+\b`)
+)
+
+// CommitMessages computes commit message statistics over every crawled
+// commit. Merge commits are counted but excluded from the style rates since
+// their messages are usually generated.
+func CommitMessages(data *ghcrawl.CrawlResult) CommitMessageStats {
+	var messages []string
+	for _, repo := range data.Repos {
+		for _, cm := range repo.Commits {
+			messages = append(messages, cm.Message)
+		}
+	}
+	return commitMessageStats(messages)
+}
+
+func commitMessageStats(messages []string) CommitMessageStats {
+	s := CommitMessageStats{ConventionalTypes: make(map[string]int)}
+	var lengths []int
+	var body, conventional, scoped, emoji, imperative, capitalized, period, refs int
+	for _, msg := range messages {
+		s.Total++
+		subject, rest, _ := strings.Cut(strings.TrimSpace(msg), "\n")
+		subject = strings.TrimSpace(subject)
+		if IsMergeSubject(subject) {
+			s.Merges++
+			continue
+		}
+		lengths = append(lengths, len([]rune(subject)))
+		if strings.TrimSpace(rest) != "" {
+			body++
+		}
+
+		description := subject
+		if m := conventionalSubject.FindStringSubmatch(subject); m != nil {
+			conventional++
+			s.ConventionalTypes[m[1]]++
+			if m[2] != "" {
+				scoped++
+			}
+			_, description, _ = strings.Cut(subject, ": ")
+		}
+		if hasEmoji(subject) || gitmojiShortcode.MatchString(subject) {
+			emoji++
+		}
+		description = stripLeadingEmoji(description)
+		if isImperative(description) {
+			imperative++
+		}
+		if r := firstLetter(description); r != 0 && unicode.IsUpper(r) {
+			capitalized++
+		}
+		if strings.HasSuffix(subject, ".") && !strings.HasSuffix(subject, "...") {
+			period++
+		}
+		if issueRef.MatchString(msg) {
+			refs++
+		}
+	}
+
+	n := len(lengths)
+	if n == 0 {
+		return s
+	}
+	slices.Sort(lengths)
+	sum := 0
+	for _, l := range lengths {
+		sum += l
+	}
+	s.SubjectLenMedian = lengths[n/2]
+	s.SubjectLenP90 = lengths[min(n-1, n*9/10)]
+	s.SubjectLenMean = float64(sum) / float64(n)
+	rate := func(count int) float64 { return float64(count) / float64(n) }
+	s.BodyRate = rate(body)
+	s.ConventionalRate = rate(conventional)
+	s.ScopeRate = rate(scoped)
+	s.EmojiRate = rate(emoji)
+	s.ImperativeRate = rate(imperative)
+	s.CapitalizedRate = rate(capitalized)
+	s.TrailingPeriodRate = rate(period)
+	s.IssueRefRate = rate(refs)
+	return s
+}
+
+// Format renders the statistics as plain text for prompts and reports. It
+// returns "" when there are no commits.
+func (s CommitMessageStats) Format() string {
+	analyzed := s.Total - s.Merges
+	if analyzed <= 0 {
+		return ""
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "Commits analyzed: %d (%d merge commits excluded)\n", analyzed, s.Merges)
+	fmt.Fprintf(&b, "Subject length: median %d, p90 %d, mean %.1f characters\n",
+		s.SubjectLenMedian, s.SubjectLenP90, s.SubjectLenMean)
+	fmt.Fprintf(&b, "Has a body: %s\n", percent(s.BodyRate))
+	fmt.Fprintf(&b, "Conventional commits: %s (with scope: %s)\n", percent(s.ConventionalRate), percent(s.ScopeRate))
+	if types := s.Types(); len(types) > 0 {
+		parts := make([]string, 0, len(types))
+		for _, t := range types {
+			parts = append(parts, fmt.Sprintf("%s %d", t, s.ConventionalTypes[t]))
+		}
+		fmt.Fprintf(&b, "Conventional types: %s\n", strings.Join(parts, ", "))
+	}
+	fmt.Fprintf(&b, "Emoji/gitmoji: %s\n", percent(s.EmojiRate))
+	fmt.Fprintf(&b, "Imperative mood: %s\n", percent(s.ImperativeRate))
+	fmt.Fprintf(&b, "Capitalized subject: %s\n", percent(s.CapitalizedRate))
+	fmt.Fprintf(&b, "Trailing period: %s\n", percent(s.TrailingPeriodRate))
+	fmt.Fprintf(&b, "References an issue or PR: %s\n", percent(s.IssueRefRate))
+	return b.String()
+}
+
+// Types returns the conventional commit types used, most used first.
+func (s CommitMessageStats) Types() []string {
+	types := make([]string, 0, len(s.ConventionalTypes))
+	for t := range s.ConventionalTypes {
+		types = append(types, t)
+	}
+	slices.SortFunc(types, func(a, b string) int {
+		if n := s.ConventionalTypes[b] - s.ConventionalTypes[a]; n != 0 {
+			return n
+		}
+		return strings.Compare(a, b)
+	})
+	return types
+}
+
+func percent(rate float64) string {
+	return fmt.Sprintf("%.0f%%", rate*100)
+}
+
+// IsMergeSubject reports whether subject is the subject line git or GitHub
+// writes for a merge commit.
+func IsMergeSubject(subject string) bool {
+	return strings.HasPrefix(subject, "Merge pull request ") ||
+		strings.HasPrefix(subject, "Merge branch ") ||
+		strings.HasPrefix(subject, "Merge remote-tracking branch ")
+}
+
+func hasEmoji(s string) bool {
+	for _, r := range s {
+		if isEmoji(r) {
+			return true
+		}
+	}
+	return false
+}
+
+func isEmoji(r rune) bool {
+	return (r >= 0x1F300 && r <= 0x1FAFF) || (r >= 0x2600 && r <= 0x27BF)
+}
+
+// stripLeadingEmoji removes a leading gitmoji (unicode or :shortcode:) so the
+// mood check sees the first real word.
+func stripLeadingEmoji(s string) string {
+	s = strings.TrimSpace(s)
+	if loc := gitmojiShortcode.FindStringIndex(s); loc != nil && loc[0] == 0 {
+		s = s[loc[1]:]
+	}
+	return strings.TrimLeftFunc(s, func(r rune) bool {
+		return isEmoji(r) || unicode.IsSpace(r) || r == 0xFE0F
+	})
+}
+
+func firstLetter(s string) rune {
+	for _, r := range s {
+		if unicode.IsLetter(r) {
+			return r
+		}
+	}
+	return 0
+}
+
+// bareVerbs are imperative verbs that happen to end in -s, -ed, or
+// -ing.
+var bareVerbs = map[string]bool{
+	"bring": true, "embed": true, "feed": true, "focus": true, "need": true,
+	"seed": true, "shed": true, "speed": true,
+}
+
+// isImperative is a heuristic: the first word of an imperative subject is a
+// bare verb, so past tense (-ed), gerunds (-ing), and third person (-s)
+// count against it.
+func isImperative(description string) bool {
+	fields := strings.Fields(description)
+	if len(fields) == 0 {
+		return false
+	}
+	word := strings.ToLower(strings.TrimFunc(fields[0], func(r rune) bool { return !unicode.IsLetter(r) }))
+	if word == "" {
+		return false
+	}
+	if bareVerbs[word] || strings.HasSuffix(word, "ss") {
+		return true
+	}
+	for _, suffix := range []string{"ed", "ing", "s"} {
+		if strings.HasSuffix(word, suffix) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,82 @@
+package stats
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCommitMessageStats(t *testing.T) {
+	messages := []string{
+		"feat(api): add pagination support\n\nCloses #12",
+		"fix: handle nil pointer",
+		"Updated readme.",
+		"✨ Add sparkles",
+		"Merge pull request #3 from acme/feature",
+	}
+	s := commitMessageStats(messages)
+
+	if s.Total != 5 || s.Merges != 1 {
+		t.Fatalf("Total, Merges = %d, %d, want 5, 1", s.Total, s.Merges)
+	}
+	checks := []struct {
+		name string
+		got  float64
+		want float64
+	}{
+		{"BodyRate", s.BodyRate, 0.25},
+		{"ConventionalRate", s.ConventionalRate, 0.5},
+		{"ScopeRate", s.ScopeRate, 0.25},
+		{"EmojiRate", s.EmojiRate, 0.25},
+		{"ImperativeRate", s.ImperativeRate, 0.75},
+		{"CapitalizedRate", s.CapitalizedRate, 0.5},
+		{"TrailingPeriodRate", s.TrailingPeriodRate, 0.25},
+		{"IssueRefRate", s.IssueRefRate, 0.25},
+	}
+	for _, c := range checks {
+		if c.got != c.want {
+			t.Errorf("%s = %v, want %v", c.name, c.got, c.want)
+		}
+	}
+	if s.ConventionalTypes["feat"] != 1 || s.ConventionalTypes["fix"] != 1 {
+		t.Errorf("ConventionalTypes = %v", s.ConventionalTypes)
+	}
+}
+
+func TestCommitMessageStatsEmpty(t *testing.T) {
+	s := commitMessageStats(nil)
+	if got := s.Format(); got != "" {
+		t.Errorf("Format() with no commits = %q, want empty", got)
+	}
+}
+
+func TestCommitMessageStatsFormat(t *testing.T) {
+	got := commitMessageStats([]string{"fix: a", "feat: b", "fix: c"}).Format()
+	for _, want := range []string{"Commits analyzed: 3", "Conventional commits: 100%", "Conventional types: fix 2, feat 1"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q in %q", want, got)
+		}
+	}
+}
+
+func TestIsImperative(t *testing.T) {
+	tests := []struct {
+		subject string
+		want    bool
+	}{
+		{"Add retries", true},
+		{"fix race in pool", true},
+		{"Address review feedback", true},
+		{"Bring back flag", true},
+		{"Added retries", false},
+		{"Fixes race", false},
+		{"Adding retries", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.subject, func(t *testing.T) {
+			if got := isImperative(tt.subject); got != tt.want {
+				t.Errorf("isImperative(%q) = %v, want %v", tt.subject, got, tt.want)
+			}
+		})
+	}
+}
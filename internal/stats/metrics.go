@@ -0,0 +1,233 @@
+package stats
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/drpaneas/devlica/internal/ghcrawl"
+)
+
+// Metrics holds hard numbers about how a developer reviews, writes, and
+// sizes changes. Rates are fractions in [0, 1].
+type Metrics struct {
+	// Review comments are inline comments plus non-empty review summaries.
+	ReviewComments  int     `json:"review_comments"`
+	ReviewLenMean   float64 `json:"review_len_mean"`
+	ReviewLenMedian int     `json:"review_len_median"`
+	QuestionRate    float64 `json:"question_rate"`
+	// HedgeRate, HarshRate, and WarmRate are the shares of review comments
+	// with hedging ("maybe", "I think"), harsh ("wrong", "makes no sense"),
+	// and warm ("thanks", "nice", "please") wording.
+	HedgeRate float64 `json:"hedge_rate"`
+	HarshRate float64 `json:"harsh_rate"`
+	WarmRate  float64 `json:"warm_rate"`
+
+	// Comments are every comment the developer wrote: review comments, PR
+	// conversation comments, and issue comments.
+	Comments  int     `json:"comments"`
+	EmojiRate float64 `json:"emoji_rate"`
+
+	PRs           int `json:"prs"`
+	PRSizeMedian  int `json:"pr_size_median"`
+	PRSizeP90     int `json:"pr_size_p90"`
+	PRFilesMedian int `json:"pr_files_median"`
+
+	// ChangedFiles counts source files touched by crawled commits;
+	// TestFileRate is the share of them that are tests.
+	ChangedFiles int     `json:"changed_files"`
+	TestFileRate float64 `json:"test_file_rate"`
+}
+
+// Compute derives Metrics from crawled data.
+func Compute(data *ghcrawl.CrawlResult) Metrics {
+	var reviews, comments []string
+	var prs []ghcrawl.PullRequestData
+	var files []string
+	for _, repo := range data.Repos {
+		for _, r := range repo.Reviews {
+			if strings.TrimSpace(r.Body) != "" {
+				reviews = append(reviews, r.Body)
+			}
+		}
+		for _, rc := range repo.ReviewComments {
+			reviews = append(reviews, rc.Body)
+		}
+		for _, cm := range repo.PRComments {
+			comments = append(comments, cm.Body)
+		}
+		for _, cm := range repo.CommitComments {
+			comments = append(comments, cm.Body)
+		}
+		prs = append(prs, repo.PRs...)
+		for _, cm := range repo.Commits {
+			files = append(files, patchFiles(cm.Patch)...)
+		}
+	}
+	for _, cm := range data.IssueComments {
+		comments = append(comments, cm.Body)
+	}
+	prs = append(prs, data.ExternalPRs...)
+	return computeMetrics(reviews, append(comments, reviews...), prs, files)
+}
+
+func computeMetrics(reviews, comments []string, prs []ghcrawl.PullRequestData, files []string) Metrics {
+	var m Metrics
+
+	m.ReviewComments = len(reviews)
+	if len(reviews) > 0 {
+		lengths := make([]int, 0, len(reviews))
+		sum, questions, hedged, harsh, warm := 0, 0, 0, 0, 0
+		for _, r := range reviews {
+			n := len([]rune(strings.TrimSpace(r)))
+			lengths = append(lengths, n)
+			sum += n
+			if isQuestion(r) {
+				questions++
+			}
+			text := prose(r)
+			if hedgeWords.MatchString(text) {
+				hedged++
+			}
+			if harshWords.MatchString(text) {
+				harsh++
+			}
+			if warmWords.MatchString(text) {
+				warm++
+			}
+		}
+		slices.Sort(lengths)
+		m.ReviewLenMean = float64(sum) / float64(len(reviews))
+		m.ReviewLenMedian = lengths[len(lengths)/2]
+		m.QuestionRate = float64(questions) / float64(len(reviews))
+		m.HedgeRate = float64(hedged) / float64(len(reviews))
+		m.HarshRate = float64(harsh) / float64(len(reviews))
+		m.WarmRate = float64(warm) / float64(len(reviews))
+	}
+
+	m.Comments = len(comments)
+	if len(comments) > 0 {
+		emoji := 0
+		for _, c := range comments {
+			if hasEmoji(c) || gitmojiShortcode.MatchString(c) {
+				emoji++
+			}
+		}
+		m.EmojiRate = float64(emoji) / float64(len(comments))
+	}
+
+	m.PRs = len(prs)
+	if len(prs) > 0 {
+		sizes := make([]int, 0, len(prs))
+		changed := make([]int, 0, len(prs))
+		for _, pr := range prs {
+			sizes = append(sizes, pr.Additions+pr.Deletions)
+			changed = append(changed, pr.ChangedFiles)
+		}
+		slices.Sort(sizes)
+		slices.Sort(changed)
+		m.PRSizeMedian = sizes[len(sizes)/2]
+		m.PRSizeP90 = sizes[min(len(sizes)-1, len(sizes)*9/10)]
+		m.PRFilesMedian = changed[len(changed)/2]
+	}
+
+	tests := 0
+	for _, f := range files {
+		if !isSourcePath(f) {
+			continue
+		}
+		m.ChangedFiles++
+		if isTestPath(f) {
+			tests++
+		}
+	}
+	if m.ChangedFiles > 0 {
+		m.TestFileRate = float64(tests) / float64(m.ChangedFiles)
+	}
+	return m
+}
+
+// Format renders the metrics as plain text for prompts and reports,
+// omitting groups without data. It returns "" when nothing was measured.
+func (m Metrics) Format() string {
+	var b strings.Builder
+	if m.ReviewComments > 0 {
+		fmt.Fprintf(&b, "Review comments: %d, length mean %.0f / median %d characters\n", m.ReviewComments, m.ReviewLenMean, m.ReviewLenMedian)
+		b.WriteString(m.Tone())
+	}
+	if m.Comments > 0 {
+		fmt.Fprintf(&b, "Comments with emoji: %s of %d\n", percent(m.EmojiRate), m.Comments)
+	}
+	if m.PRs > 0 {
+		fmt.Fprintf(&b, "Authored PR size (lines added + deleted): median %d, p90 %d; median files changed %d (%d PRs)\n",
+			m.PRSizeMedian, m.PRSizeP90, m.PRFilesMedian, m.PRs)
+	}
+	if m.ChangedFiles > 0 {
+		fmt.Fprintf(&b, "Test files among changed source files: %s of %d\n", percent(m.TestFileRate), m.ChangedFiles)
+	}
+	return b.String()
+}
+
+// isQuestion reports whether a comment asks something: any sentence ending
+// in a question mark, ignoring quoted lines and code blocks.
+func isQuestion(body string) bool {
+	inFence := false
+	for _, line := range strings.Split(body, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") {
+			inFence = !inFence
+			continue
+		}
+		if inFence || strings.HasPrefix(trimmed, ">") {
+			continue
+		}
+		if strings.Contains(trimmed, "? ") || strings.HasSuffix(trimmed, "?") {
+			return true
+		}
+	}
+	return false
+}
+
+// patchFiles returns the file paths in a crawled commit patch, which marks
+// each file with a "--- path ---" header line.
+func patchFiles(patch string) []string {
+	var files []string
+	for _, line := range strings.Split(patch, "\n") {
+		if strings.HasPrefix(line, "--- ") && strings.HasSuffix(line, " ---") && len(line) > 8 {
+			files = append(files, line[4:len(line)-4])
+		}
+	}
+	return files
+}
+
+var sourceExts = map[string]bool{
+	".go": true, ".py": true, ".rs": true, ".ts": true, ".tsx": true, ".js": true, ".jsx": true,
+	".java": true, ".kt": true, ".rb": true, ".c": true, ".cc": true, ".cpp": true, ".h": true,
+	".cs": true, ".swift": true, ".php": true, ".scala": true, ".ex": true, ".exs": true,
+}
+
+func isSourcePath(p string) bool {
+	return sourceExts[strings.ToLower(path.Ext(p))]
+}
+
+// isTestPath recognizes the common test file conventions: Go's _test.go,
+// pytest's test_*.py and *_test.py, JS/TS *.test.* and *.spec.*, Ruby's
+// _spec.rb, Java/Kotlin *Test.*, and anything under a test(s) directory.
+func isTestPath(p string) bool {
+	lower := strings.ToLower(p)
+	base := path.Base(lower)
+	stem := strings.TrimSuffix(base, path.Ext(base))
+	for _, dir := range strings.Split(path.Dir(lower), "/") {
+		if dir == "test" || dir == "tests" || dir == "__tests__" || dir == "spec" {
+			return true
+		}
+	}
+	return strings.HasSuffix(stem, "_test") ||
+		strings.HasPrefix(stem, "test_") ||
+		strings.HasSuffix(stem, ".test") ||
+		strings.HasSuffix(stem, ".spec") ||
+		strings.HasSuffix(stem, "_spec") ||
+		strings.HasSuffix(path.Base(p), "Test"+path.Ext(p)) ||
+		strings.HasSuffix(path.Base(p), "Tests"+path.Ext(p))
+}
@@ -0,0 +1,106 @@
+package stats
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/drpaneas/devlica/internal/ghcrawl"
+)
+
+func TestCompute(t *testing.T) {
+	data := &ghcrawl.CrawlResult{
+		Repos: []ghcrawl.RepoData{{
+			Reviews: []ghcrawl.ReviewData{{Body: "LGTM 🚀"}, {Body: ""}},
+			ReviewComments: []ghcrawl.ReviewComment{
+				{Body: "Why not use a map here?"},
+				{Body: "> is this safe?\nIt is fine."},
+				{Body: "Rename to count"},
+			},
+			PRComments: []ghcrawl.Comment{{Body: "thanks :tada:"}},
+			PRs: []ghcrawl.PullRequestData{
+				{Additions: 10, Deletions: 0, ChangedFiles: 1},
+				{Additions: 100, Deletions: 50, ChangedFiles: 5},
+				{Additions: 30, Deletions: 10, ChangedFiles: 2},
+			},
+			Commits: []ghcrawl.CommitData{
+				{Patch: "--- cmd/main.go ---\n+x\n--- cmd/main_test.go ---\n+y\n--- README.md ---\n+z\n"},
+				{Patch: "--- web/app.ts ---\n+a\n--- web/app.spec.ts ---\n+b\n"},
+			},
+		}},
+		IssueComments: []ghcrawl.Comment{{Body: "Same here"}},
+	}
+	m := Compute(data)
+	if m.ReviewComments != 4 || m.ReviewLenMedian != len("Why not use a map here?") {
+		t.Errorf("review counts: %+v", m)
+	}
+	if m.QuestionRate != 0.25 {
+		t.Errorf("QuestionRate = %v, want 0.25 (quoted questions do not count)", m.QuestionRate)
+	}
+	if m.Comments != 6 || m.EmojiRate != 2.0/6 {
+		t.Errorf("Comments = %d, EmojiRate = %v", m.Comments, m.EmojiRate)
+	}
+	if m.PRs != 3 || m.PRSizeMedian != 40 || m.PRSizeP90 != 150 || m.PRFilesMedian != 2 {
+		t.Errorf("PR sizes: %+v", m)
+	}
+	if m.ChangedFiles != 4 || m.TestFileRate != 0.5 {
+		t.Errorf("ChangedFiles = %d, TestFileRate = %v", m.ChangedFiles, m.TestFileRate)
+	}
+
+	got := m.Format()
+	for _, want := range []string{"phrased as questions: 25%", "0% hedged, 0% harsh, 25% warm", "Comments with emoji: 33% of 6", "median 40, p90 150", "Test files among changed source files: 50% of 4"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q in %q", want, got)
+		}
+	}
+}
+
+func TestMetricsFormatEmpty(t *testing.T) {
+	if got := Compute(&ghcrawl.CrawlResult{}).Format(); got != "" {
+		t.Errorf("Format() with no data = %q, want empty", got)
+	}
+}
+
+func TestIsTestPath(t *testing.T) {
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"pkg/foo_test.go", true},
+		{"tests/test_api.py", true},
+		{"src/api_test.py", true},
+		{"web/app.test.tsx", true},
+		{"src/main/java/FooTest.java", true},
+		{"spec/models/user_spec.rb", true},
+		{"pkg/testutil/helpers.go", false},
+		{"pkg/latest.go", false},
+		{"src/contest.py", false},
+	}
+	for _, tt := range tests {
+		if got := isTestPath(tt.path); got != tt.want {
+			t.Errorf("isTestPath(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestReviewTone(t *testing.T) {
+	reviews := []string{
+		"Maybe we could cache this?",
+		"This is wrong, it makes no sense.",
+		"Nice, thanks!",
+		"> obviously broken\nI think the quote is unfair.",
+		"```go\n// this is wrong\n```\nRename to count",
+	}
+	m := computeMetrics(reviews, reviews, nil, nil)
+	if m.HedgeRate != 0.4 {
+		t.Errorf("HedgeRate = %v, want 0.4", m.HedgeRate)
+	}
+	if m.HarshRate != 0.2 {
+		t.Errorf("HarshRate = %v, want 0.2 (quotes and code do not count)", m.HarshRate)
+	}
+	if m.WarmRate != 0.2 {
+		t.Errorf("WarmRate = %v, want 0.2", m.WarmRate)
+	}
+	if got := m.Tone(); !strings.Contains(got, "40% hedged, 20% harsh, 20% warm") {
+		t.Errorf("Tone() = %q", got)
+	}
+}
@@ -0,0 +1,261 @@
+package stats
+
+import (
+	"cmp"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/drpaneas/devlica/internal/ghcrawl"
+)
+
+const (
+	// maxPhraseWords is the longest word n-gram considered a phrase.
+	maxPhraseWords = 4
+	// minPhraseComments is the fewest comments a phrase must appear in; a
+	// phrase must also appear in at least one comment in phraseShare.
+	minPhraseComments = 3
+	phraseShare       = 50
+	// maxSignaturePhrases bounds the phrases reported.
+	maxSignaturePhrases = 15
+	// minAvoidComments is how many comments it takes before a generic phrase
+	// that never appears says something about the developer.
+	minAvoidComments = 20
+)
+
+// Phrase kinds.
+const (
+	PhraseWords   = "phrase"
+	PhraseEmoji   = "emoji"
+	PhraseSignOff = "sign-off"
+)
+
+// Phrase is a characteristic expression and how many comments use it.
+type Phrase struct {
+	Text     string `json:"text"`
+	Kind     string `json:"kind"`
+	Comments int    `json:"comments"`
+}
+
+// PhraseStats holds the developer's signature phrases, and generic
+// reviewer phrases they never or almost never use.
+type PhraseStats struct {
+	Comments  int      `json:"comments"`
+	Signature []Phrase `json:"signature,omitempty"`
+	Avoid     []string `json:"avoid,omitempty"`
+}
+
+var (
+	phraseWord     = regexp.MustCompile(`[\p{L}\p{N}][\p{L}\p{N}'+-]*`)
+	phraseBoundary = regexp.MustCompile(`[.!?;,()"\n]+|:(\s|$)|\s[-–—]\s`)
+
+	// chatAbbreviations are the single words worth reporting on their own;
+	// any other single word is too common to be a signature.
+	chatAbbreviations = set("lgtm", "sgtm", "ptal", "wdyt", "nit", "nits", "imo", "imho", "afaict", "afaik",
+		"iiuc", "fwiw", "tbh", "nbd", "ack", "nack", "ty", "thx", "ftw", "btw", "cheers", "meh")
+	// glueWords cannot start or end a phrase: "of the" or "fix the" are
+	// fragments, not expressions.
+	glueWords = set("a", "an", "the", "of", "to", "in", "on", "for", "and", "or", "but", "is", "are", "was",
+		"it", "its", "this", "that", "these", "be", "with", "as", "at", "by", "from", "if", "so", "than")
+	// stopWords are common enough that a phrase made only of them says
+	// nothing about its author.
+	stopWords = set("i", "we", "you", "he", "she", "they", "me", "us", "my", "our", "your", "do", "does",
+		"did", "have", "has", "had", "can", "could", "would", "should", "will", "not", "no", "yes", "there",
+		"here", "what", "which", "who", "how", "why", "when", "where", "also", "just", "all", "some", "any",
+		"more", "about", "into", "out", "up", "then", "now", "one", "am", "been", "being", "were")
+
+	// genericReviewPhrases are what a generic AI reviewer tends to write.
+	// The ones a developer never uses go on the avoid list.
+	genericReviewPhrases = []string{
+		"great job", "great work", "nice work", "looks good to me", "i'd suggest", "consider using",
+		"it might be worth", "overall", "thanks for the pr", "thanks for your contribution",
+		"feel free to", "just a suggestion", "awesome", "kudos", "happy to help", "let me know if",
+	}
+)
+
+var genericPatterns = func() []*regexp.Regexp {
+	patterns := make([]*regexp.Regexp, len(genericReviewPhrases))
+	for i, g := range genericReviewPhrases {
+		patterns[i] = regexp.MustCompile(`\b` + regexp.QuoteMeta(g) + `\b`)
+	}
+	return patterns
+}()
+
+func set(words ...string) map[string]bool {
+	m := make(map[string]bool, len(words))
+	for _, w := range words {
+		m[w] = true
+	}
+	return m
+}
+
+// SignaturePhrases mines every comment the developer wrote for recurring
+// expressions, emoji, and sign-offs, counting each at most once per comment.
+func SignaturePhrases(data *ghcrawl.CrawlResult) PhraseStats {
+	var comments []string
+	for _, repo := range data.Repos {
+		for _, r := range repo.Reviews {
+			if strings.TrimSpace(r.Body) != "" {
+				comments = append(comments, r.Body)
+			}
+		}
+		for _, rc := range repo.ReviewComments {
+			comments = append(comments, rc.Body)
+		}
+		for _, cm := range repo.PRComments {
+			comments = append(comments, cm.Body)
+		}
+		for _, cm := range repo.CommitComments {
+			comments = append(comments, cm.Body)
+		}
+	}
+	for _, cm := range data.IssueComments {
+		comments = append(comments, cm.Body)
+	}
+	return signaturePhrases(comments)
+}
+
+type phraseKey struct{ text, kind string }
+
+func signaturePhrases(comments []string) PhraseStats {
+	ps := PhraseStats{Comments: len(comments)}
+	counts := make(map[phraseKey]int)
+	generic := make([]int, len(genericReviewPhrases))
+	for _, c := range comments {
+		text := prose(c)
+		for k := range commentPhrases(text) {
+			counts[k]++
+		}
+		for i, re := range genericPatterns {
+			if re.MatchString(text) {
+				generic[i]++
+			}
+		}
+	}
+
+	threshold := max(minPhraseComments, (len(comments)+phraseShare-1)/phraseShare)
+	var phrases []Phrase
+	for k, n := range counts {
+		if n >= threshold {
+			phrases = append(phrases, Phrase{Text: k.text, Kind: k.kind, Comments: n})
+		}
+	}
+	phrases = dropSubsumed(phrases)
+	slices.SortFunc(phrases, func(a, b Phrase) int {
+		return cmp.Or(cmp.Compare(b.Comments, a.Comments), cmp.Compare(len(b.Text), len(a.Text)), cmp.Compare(a.Text, b.Text))
+	})
+	ps.Signature = phrases[:min(len(phrases), maxSignaturePhrases)]
+
+	if len(comments) >= minAvoidComments {
+		for i, g := range genericReviewPhrases {
+			// Under 1% of comments counts as "almost never".
+			if generic[i]*100 < len(comments) {
+				ps.Avoid = append(ps.Avoid, g)
+			}
+		}
+	}
+	return ps
+}
+
+// commentPhrases returns the distinct phrase candidates in one comment's
+// lowercased prose.
+func commentPhrases(text string) map[phraseKey]bool {
+	found := make(map[phraseKey]bool)
+	for _, code := range gitmojiShortcode.FindAllString(text, -1) {
+		found[phraseKey{code, PhraseEmoji}] = true
+	}
+	text = gitmojiShortcode.ReplaceAllString(text, " ")
+	var plain strings.Builder
+	for _, r := range text {
+		if isEmoji(r) {
+			found[phraseKey{string(r), PhraseEmoji}] = true
+			plain.WriteByte(' ')
+			continue
+		}
+		plain.WriteRune(r)
+	}
+	text = plain.String()
+
+	lines := strings.Split(strings.TrimSpace(text), "\n")
+	if last := phraseWord.FindAllString(lines[len(lines)-1], -1); len(lines) > 1 && len(last) > 0 && len(last) <= 3 {
+		found[phraseKey{strings.Join(last, " "), PhraseSignOff}] = true
+	}
+
+	for _, segment := range phraseBoundary.Split(text, -1) {
+		words := phraseWord.FindAllString(segment, -1)
+		for i := range words {
+			if chatAbbreviations[words[i]] {
+				found[phraseKey{words[i], PhraseWords}] = true
+			}
+			for n := 2; n <= maxPhraseWords && i+n <= len(words); n++ {
+				gram := words[i : i+n]
+				if isPhrase(gram) {
+					found[phraseKey{strings.Join(gram, " "), PhraseWords}] = true
+				}
+			}
+		}
+	}
+	return found
+}
+
+// isPhrase reports whether an n-gram can be an expression: it neither
+// starts nor ends with a glue word and is not made of stop words only.
+func isPhrase(words []string) bool {
+	if glueWords[words[0]] || glueWords[words[len(words)-1]] {
+		return false
+	}
+	for _, w := range words {
+		if !stopWords[w] && !glueWords[w] {
+			return true
+		}
+	}
+	return false
+}
+
+// dropSubsumed removes word phrases contained in a longer one, or in a
+// sign-off, that appears in nearly as many comments: "good to me" adds
+// nothing next to "looks good to me" when both come from the same comments.
+func dropSubsumed(phrases []Phrase) []Phrase {
+	return slices.DeleteFunc(slices.Clone(phrases), func(p Phrase) bool {
+		if p.Kind != PhraseWords {
+			return false
+		}
+		for _, q := range phrases {
+			longer := q.Kind == PhraseWords && len(q.Text) > len(p.Text) || q.Kind == PhraseSignOff
+			if longer && q.Comments*5 >= p.Comments*4 && strings.Contains(" "+q.Text+" ", " "+p.Text+" ") {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// Format renders the phrases as plain text for prompts and reports. It
+// returns "" when there are no comments.
+func (ps PhraseStats) Format() string {
+	if ps.Comments == 0 {
+		return ""
+	}
+	var b strings.Builder
+	if len(ps.Signature) == 0 {
+		fmt.Fprintf(&b, "No phrase recurs across their %d comments.\n", ps.Comments)
+	} else {
+		fmt.Fprintf(&b, "Recurring phrases, emoji, and sign-offs across their %d comments:\n", ps.Comments)
+		for _, p := range ps.Signature {
+			fmt.Fprintf(&b, "- %q (%s): %d comments, %s\n", p.Text, p.Kind, p.Comments, percent(float64(p.Comments)/float64(ps.Comments)))
+		}
+	}
+	if len(ps.Avoid) > 0 {
+		fmt.Fprintf(&b, "Generic reviewer phrases they never or almost never use: %s\n", strings.Join(quoteAll(ps.Avoid), ", "))
+	}
+	return b.String()
+}
+
+func quoteAll(items []string) []string {
+	quoted := make([]string, len(items))
+	for i, s := range items {
+		quoted[i] = fmt.Sprintf("%q", s)
+	}
+	return quoted
+}
@@ -0,0 +1,66 @@
+package stats
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestSignaturePhrases(t *testing.T) {
+	comments := []string{
+		"LGTM with nits, wdyt?\n\ncheers",
+		"lgtm with nits :rocket:\n\nCheers!",
+		"Small thing: lgtm with nits.\n\ncheers",
+		"> lgtm with nits in the quote does not count\nWhy is this here? 🎉",
+		"Please rename this 🎉",
+		"Please rename this. 🎉",
+		"```go\nlgtm with nits\n```",
+	}
+	got := signaturePhrases(comments)
+
+	find := func(text, kind string) int {
+		i := slices.IndexFunc(got.Signature, func(p Phrase) bool { return p.Text == text && p.Kind == kind })
+		if i < 0 {
+			return 0
+		}
+		return got.Signature[i].Comments
+	}
+	if n := find("lgtm with nits", PhraseWords); n != 3 {
+		t.Errorf("lgtm with nits in %d comments, want 3 (quotes and code excluded): %+v", n, got.Signature)
+	}
+	if n := find("cheers", PhraseSignOff); n != 3 {
+		t.Errorf("cheers sign-off in %d comments, want 3: %+v", n, got.Signature)
+	}
+	if n := find("🎉", PhraseEmoji); n != 3 {
+		t.Errorf("🎉 in %d comments, want 3: %+v", n, got.Signature)
+	}
+	if n := find("with nits", PhraseWords); n != 0 {
+		t.Errorf("with nits should not be a phrase: %+v", got.Signature)
+	}
+	if find("lgtm", PhraseWords) != 0 || find("cheers", PhraseWords) != 0 {
+		t.Errorf("phrases inside a longer phrase or sign-off from the same comments should be dropped: %+v", got.Signature)
+	}
+	if find("rename this", PhraseWords) != 0 {
+		t.Errorf("a phrase in only 2 comments should not be reported: %+v", got.Signature)
+	}
+	if got.Avoid != nil {
+		t.Errorf("Avoid = %v, want none for so few comments", got.Avoid)
+	}
+}
+
+func TestSignaturePhrasesAvoid(t *testing.T) {
+	comments := make([]string, 0, 40)
+	for range 20 {
+		comments = append(comments, "Great work, this is awesome", "Fix the nil check")
+	}
+	got := signaturePhrases(comments)
+	if slices.Contains(got.Avoid, "great work") || slices.Contains(got.Avoid, "awesome") {
+		t.Errorf("Avoid = %v should not list phrases they use", got.Avoid)
+	}
+	if !slices.Contains(got.Avoid, "looks good to me") {
+		t.Errorf("Avoid = %v, want the generic phrases they never use", got.Avoid)
+	}
+	if !strings.Contains(got.Format(), `"great work" (phrase): 20 comments, 50%`) {
+		t.Errorf("Format() =\n%s", got.Format())
+	}
+}
@@ -0,0 +1,164 @@
+package stats
+
+import (
+	"cmp"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/drpaneas/devlica/internal/ghcrawl"
+)
+
+const (
+	// minPRPartUses is the fewest descriptions a heading or checklist item
+	// must appear in to count as a habit rather than a one-off.
+	minPRPartUses = 2
+	// maxPRParts bounds the headings and checklist items reported.
+	maxPRParts = 8
+)
+
+var (
+	markdownHeading = regexp.MustCompile(`^#{1,6}\s+(.+?)\s*#*$`)
+	// boldHeading is a line that is only bold text, which many use as a
+	// heading in descriptions.
+	boldHeading   = regexp.MustCompile(`^\*\*([^*]+)\*\*:?$`)
+	checklistItem = regexp.MustCompile(`^\s*[-*+]\s+\[[ xX]\]\s+(.+)$`)
+	mentionsTests = regexp.MustCompile(`(?i)\btest(s|ed|ing)?\b`)
+)
+
+// PRPart is a heading or checklist item and how many descriptions use it.
+type PRPart struct {
+	Text string `json:"text"`
+	Uses int    `json:"uses"`
+}
+
+// PRBodyStats describes how a developer structures the descriptions of the
+// pull requests they author. Rates are fractions in [0, 1] of the PRs.
+type PRBodyStats struct {
+	Total         int     `json:"total"`
+	BodyRate      float64 `json:"body_rate"`
+	BodyLenMedian int     `json:"body_len_median"`
+	// Sections are the recurring headings, in the order they usually
+	// appear in a description.
+	Sections []PRPart `json:"sections,omitempty"`
+	// ChecklistRate is the share with a task list, and ChecklistItems the
+	// recurring items, most used first.
+	ChecklistRate  float64  `json:"checklist_rate"`
+	ChecklistItems []PRPart `json:"checklist_items,omitempty"`
+	// TestingRate is the share that mention testing.
+	TestingRate  float64 `json:"testing_rate"`
+	IssueRefRate float64 `json:"issue_ref_rate"`
+}
+
+// PRBodies computes description statistics over every authored PR.
+func PRBodies(data *ghcrawl.CrawlResult) PRBodyStats {
+	var bodies []string
+	for _, repo := range data.Repos {
+		for _, pr := range repo.PRs {
+			bodies = append(bodies, pr.Body)
+		}
+	}
+	for _, pr := range data.ExternalPRs {
+		bodies = append(bodies, pr.Body)
+	}
+	return prBodyStats(bodies)
+}
+
+// prPartTally counts the descriptions using a heading or item, under its
+// first spelling, and sums where in them it appears.
+type prPartTally struct {
+	text     string
+	uses     int
+	position float64
+}
+
+func prBodyStats(bodies []string) PRBodyStats {
+	s := PRBodyStats{Total: len(bodies)}
+	if len(bodies) == 0 {
+		return s
+	}
+	sections := make(map[string]*prPartTally)
+	items := make(map[string]*prPartTally)
+	tally := func(m map[string]*prPartTally, seen map[string]bool, text string, position float64) {
+		key := strings.ToLower(strings.TrimRight(strings.TrimSpace(text), ":"))
+		if key == "" || seen[key] {
+			return
+		}
+		seen[key] = true
+		t := m[key]
+		if t == nil {
+			t = &prPartTally{text: strings.TrimRight(strings.TrimSpace(text), ":")}
+			m[key] = t
+		}
+		t.uses++
+		t.position += position
+	}
+
+	var lengths []int
+	var withBody, checklists, testing, refs int
+	for _, body := range bodies {
+		body = strings.TrimSpace(body)
+		if body == "" {
+			continue
+		}
+		withBody++
+		lengths = append(lengths, len([]rune(body)))
+		if mentionsTests.MatchString(body) {
+			testing++
+		}
+		if issueRef.MatchString(body) {
+			refs++
+		}
+		lines := strings.Split(body, "\n")
+		seenSections, seenItems := make(map[string]bool), make(map[string]bool)
+		hasChecklist := false
+		for i, line := range lines {
+			line = strings.TrimRight(line, " \r")
+			position := float64(i) / float64(len(lines))
+			if m := checklistItem.FindStringSubmatch(line); m != nil {
+				hasChecklist = true
+				tally(items, seenItems, m[1], position)
+			} else if m := markdownHeading.FindStringSubmatch(line); m != nil {
+				tally(sections, seenSections, m[1], position)
+			} else if m := boldHeading.FindStringSubmatch(strings.TrimSpace(line)); m != nil {
+				tally(sections, seenSections, m[1], position)
+			}
+		}
+		if hasChecklist {
+			checklists++
+		}
+	}
+	if withBody == 0 {
+		return s
+	}
+	slices.Sort(lengths)
+	s.BodyRate = float64(withBody) / float64(len(bodies))
+	s.BodyLenMedian = lengths[len(lengths)/2]
+	s.ChecklistRate = float64(checklists) / float64(len(bodies))
+	s.TestingRate = float64(testing) / float64(len(bodies))
+	s.IssueRefRate = float64(refs) / float64(len(bodies))
+
+	recurring := func(m map[string]*prPartTally) []*prPartTally {
+		var kept []*prPartTally
+		for _, t := range m {
+			if t.uses >= minPRPartUses {
+				kept = append(kept, t)
+			}
+		}
+		slices.SortFunc(kept, func(a, b *prPartTally) int {
+			return cmp.Or(b.uses-a.uses, strings.Compare(a.text, b.text))
+		})
+		return kept[:min(len(kept), maxPRParts)]
+	}
+	kept := recurring(sections)
+	slices.SortStableFunc(kept, func(a, b *prPartTally) int {
+		return cmp.Compare(a.position/float64(a.uses), b.position/float64(b.uses))
+	})
+	for _, t := range kept {
+		s.Sections = append(s.Sections, PRPart{Text: t.text, Uses: t.uses})
+	}
+	for _, t := range recurring(items) {
+		s.ChecklistItems = append(s.ChecklistItems, PRPart{Text: t.text, Uses: t.uses})
+	}
+	return s
+}
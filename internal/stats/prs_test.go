@@ -0,0 +1,34 @@
+package stats
+
+import (
+	"testing"
+)
+
+func TestPRBodyStats(t *testing.T) {
+	bodies := []string{
+		"## Summary\nAdds retries.\n\n## Testing\nRan the tests.\n\n- [x] Docs updated\n- [ ] Changelog\n\nFixes #12",
+		"## summary:\nFixes a leak.\n\n**Testing**\nUnit test added.\n\n- [ ] Docs updated\n",
+		"Bumps a dependency.",
+		"",
+	}
+	s := prBodyStats(bodies)
+	if s.Total != 4 || s.BodyRate != 0.75 {
+		t.Errorf("Total, BodyRate = %d, %v; want 4, 0.75", s.Total, s.BodyRate)
+	}
+	wantSections := []PRPart{{Text: "Summary", Uses: 2}, {Text: "Testing", Uses: 2}}
+	if len(s.Sections) != len(wantSections) || s.Sections[0] != wantSections[0] || s.Sections[1] != wantSections[1] {
+		t.Errorf("Sections = %v, want %v in the order they appear", s.Sections, wantSections)
+	}
+	if len(s.ChecklistItems) != 1 || s.ChecklistItems[0] != (PRPart{Text: "Docs updated", Uses: 2}) {
+		t.Errorf("ChecklistItems = %v, want only the recurring item", s.ChecklistItems)
+	}
+	if s.ChecklistRate != 0.5 || s.TestingRate != 0.5 || s.IssueRefRate != 0.25 {
+		t.Errorf("rates = checklist %v, testing %v, refs %v", s.ChecklistRate, s.TestingRate, s.IssueRefRate)
+	}
+}
+
+func TestPRBodyStatsEmpty(t *testing.T) {
+	if s := prBodyStats(nil); s.Total != 0 || s.Sections != nil {
+		t.Errorf("prBodyStats(nil) = %+v", s)
+	}
+}
@@ -0,0 +1,86 @@
+package stats
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/drpaneas/devlica/internal/ghcrawl"
+)
+
+// SuggestionStats describes how often a developer's inline review comments
+// propose a concrete patch with a ```suggestion block instead of prose only.
+type SuggestionStats struct {
+	Comments       int `json:"comments"`
+	WithSuggestion int `json:"with_suggestion"`
+	Blocks         int `json:"blocks"`
+}
+
+// ReviewSuggestions counts ```suggestion blocks across every crawled inline
+// review comment.
+func ReviewSuggestions(data *ghcrawl.CrawlResult) SuggestionStats {
+	var s SuggestionStats
+	for _, repo := range data.Repos {
+		for _, rc := range repo.ReviewComments {
+			s.add(rc.Body)
+		}
+	}
+	return s
+}
+
+func (s *SuggestionStats) add(body string) {
+	s.Comments++
+	if n := countSuggestionBlocks(body); n > 0 {
+		s.WithSuggestion++
+		s.Blocks += n
+	}
+}
+
+// Rate is the fraction of inline comments with at least one suggestion.
+func (s SuggestionStats) Rate() float64 {
+	if s.Comments == 0 {
+		return 0
+	}
+	return float64(s.WithSuggestion) / float64(s.Comments)
+}
+
+// Format renders the statistics as plain text for prompts. It returns ""
+// when there are no inline review comments.
+func (s SuggestionStats) Format() string {
+	if s.Comments == 0 {
+		return ""
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "Inline review comments: %d\n", s.Comments)
+	fmt.Fprintf(&b, "With a suggestion block (concrete patch): %d (%s)\n", s.WithSuggestion, percent(s.Rate()))
+	fmt.Fprintf(&b, "Prose only: %d (%s)\n", s.Comments-s.WithSuggestion, percent(1-s.Rate()))
+	if s.WithSuggestion > 0 {
+		fmt.Fprintf(&b, "Suggestion blocks per suggesting comment: %.1f\n", float64(s.Blocks)/float64(s.WithSuggestion))
+	}
+	return b.String()
+}
+
+// countSuggestionBlocks counts fenced blocks whose info string is
+// "suggestion". Fences may be indented up to three spaces and use three or
+// more backticks, as in GitHub Flavored Markdown.
+func countSuggestionBlocks(body string) int {
+	n := 0
+	fence := ""
+	for _, line := range strings.Split(body, "\n") {
+		trimmed := strings.TrimRight(strings.TrimLeft(line, " "), " \r")
+		if len(line)-len(strings.TrimLeft(line, " ")) > 3 || !strings.HasPrefix(trimmed, "```") {
+			continue
+		}
+		ticks := len(trimmed) - len(strings.TrimLeft(trimmed, "`"))
+		if fence != "" {
+			if ticks >= len(fence) && strings.Trim(trimmed, "`") == "" {
+				fence = ""
+			}
+			continue
+		}
+		fence = trimmed[:ticks]
+		if strings.TrimSpace(trimmed[ticks:]) == "suggestion" {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,45 @@
+package stats
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCountSuggestionBlocks(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want int
+	}{
+		{"prose", "Can we rename this?", 0},
+		{"one", "Nit:\n```suggestion\n\treturn nil\n```", 1},
+		{"two", "```suggestion\na\n```\nand\n```suggestion\nb\n```", 2},
+		{"other language", "```go\nreturn nil\n```", 0},
+		{"nested in longer fence", "````markdown\n```suggestion\nx\n```\n````", 0},
+		{"crlf", "```suggestion\r\nx\r\n```\r\n", 1},
+		{"unterminated", "```suggestion\nx", 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := countSuggestionBlocks(tt.body); got != tt.want {
+				t.Errorf("countSuggestionBlocks() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSuggestionStatsFormat(t *testing.T) {
+	var s SuggestionStats
+	if got := s.Format(); got != "" {
+		t.Errorf("Format() with no comments = %q, want empty", got)
+	}
+	for _, body := range []string{"```suggestion\na\n```\n```suggestion\nb\n```", "looks good", "why?", "```suggestion\nc\n```"} {
+		s.add(body)
+	}
+	got := s.Format()
+	for _, want := range []string{"Inline review comments: 4", "concrete patch): 2 (50%)", "Prose only: 2 (50%)", "per suggesting comment: 1.5"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q in %q", want, got)
+		}
+	}
+}
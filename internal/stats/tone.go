@@ -0,0 +1,47 @@
+package stats
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Tone lexicons. Each matches whole words or phrases in the lowercased
+// prose of a comment. They are deliberately short: a word that is only
+// sometimes a hedge or a put-down would blur the numbers more than the
+// misses do.
+var (
+	hedgeWords = regexp.MustCompile(`\b(maybe|perhaps|possibly|probably|might|i think|i guess|i suspect|i wonder|not sure|could we|would it make sense|it seems|seems like|afaict|imo|imho)\b`)
+	harshWords = regexp.MustCompile(`\b(wrong|broken|terrible|horrible|awful|ugly|nonsense|pointless|useless|no way|makes no sense|why would you|obviously)\b|!!`)
+	warmWords  = regexp.MustCompile(`\b(please|thanks|thank you|sorry|appreciate|nice|great|good catch|well done|lgtm)\b`)
+)
+
+// prose returns the lowercased text of a comment without quoted lines and
+// code blocks, which hold other people's words and code, not tone.
+func prose(body string) string {
+	var b strings.Builder
+	inFence := false
+	for _, line := range strings.Split(body, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") {
+			inFence = !inFence
+			continue
+		}
+		if inFence || strings.HasPrefix(trimmed, ">") {
+			continue
+		}
+		b.WriteString(strings.ToLower(trimmed))
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// Tone renders the question and tone rates of review comments, one line
+// each, or "" without review comments.
+func (m Metrics) Tone() string {
+	if m.ReviewComments == 0 {
+		return ""
+	}
+	return fmt.Sprintf("Review comments phrased as questions: %s\nReview comment tone (lexicon-based): %s hedged, %s harsh, %s warm\n",
+		percent(m.QuestionRate), percent(m.HedgeRate), percent(m.HarshRate), percent(m.WarmRate))
+}
@@ -2,18 +2,27 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"log"
 	"log/slog"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/drpaneas/devlica/internal/analyzer"
 	"github.com/drpaneas/devlica/internal/benchmark"
 	"github.com/drpaneas/devlica/internal/config"
 	"github.com/drpaneas/devlica/internal/ghcrawl"
 	"github.com/drpaneas/devlica/internal/llm"
+	"github.com/drpaneas/devlica/internal/progress"
+	"github.com/drpaneas/devlica/internal/prompts"
+	"github.com/drpaneas/devlica/internal/redact"
 	"github.com/drpaneas/devlica/internal/skill"
 )
 
@@ -22,55 +31,331 @@ const (
 	githubEventsWindow  = 300
 )
 
+// exitSparse is the exit status of a run that wrote skills from a sparse
+// profile, so scripts can tell a best-effort persona from a confident one.
+const exitSparse = 3
+
+// errSparse reports that the skills were written from a sparse profile.
+var errSparse = errors.New("sparse profile")
+
+// fatal exits with exitSparse for errSparse, whose details finish has
+// already printed, and logs any other error.
+func fatal(err error) {
+	if errors.Is(err, errSparse) {
+		os.Exit(exitSparse)
+	}
+	log.Fatal(err)
+}
+
 func main() {
+	args := os.Args[1:]
+	if len(args) > 0 && args[0] == "generate" {
+		if err := runGenerate(args[1:]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+	if len(args) > 0 && args[0] == "lint" {
+		if err := runLint(args[1:]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+	estimate := len(args) > 0 && args[0] == "estimate"
+	reanalyze := len(args) > 0 && args[0] == "analyze"
+	bench := len(args) > 0 && args[0] == "bench"
+	if estimate || reanalyze || bench {
+		args = args[1:]
+	}
+
 	var cfg config.Config
 	var provider string
+	var opts analyzeOptions
+	var benchOpts benchOptions
 	configureFlags(flag.CommandLine, &cfg, &provider)
+	if reanalyze {
+		configureAnalyzeFlags(flag.CommandLine, &opts)
+	}
+	if bench {
+		configureBenchFlags(flag.CommandLine, &benchOpts)
+	}
 	flag.Usage = func() {
-		fmt.Fprintf(os.Stderr, "Usage: devlica [flags] <username>\n\nFlags:\n")
+		fmt.Fprintf(os.Stderr, "Usage: devlica [flags] <username>\n       devlica estimate [flags] <username>\n       devlica analyze -crawl crawl.json -persona persona.json -only dimension[,dimension] [flags]\n       devlica bench -persona persona.json [-crawl crawl.json] [flags]\n       devlica generate -persona persona.json [flags]\n       devlica lint <dir>\n\nFlags:\n")
 		flag.PrintDefaults()
 	}
-	flag.Parse()
+	if err := flag.CommandLine.Parse(args); err != nil {
+		os.Exit(2)
+	}
 
 	cfg.Provider = llm.ProviderName(provider)
+	if cfg.OutputDir == "-" && cfg.Stream == "" {
+		cfg.Stream = skill.StreamText
+	}
 
-	if flag.NArg() != 1 {
-		flag.Usage()
-		os.Exit(1)
+	switch {
+	case reanalyze:
+		if opts.crawl == "" || opts.persona == "" || len(opts.only) == 0 || flag.NArg() != 0 {
+			flag.Usage()
+			os.Exit(1)
+		}
+	case bench:
+		if benchOpts.persona == "" || flag.NArg() != 0 {
+			flag.Usage()
+			os.Exit(1)
+		}
+	default:
+		if flag.NArg() != 1 {
+			flag.Usage()
+			os.Exit(1)
+		}
+		cfg.Username = flag.Arg(0)
 	}
-	cfg.Username = flag.Arg(0)
 
 	cfg.LoadFromEnv()
 	if cfg.Model == "" {
 		cfg.Model = config.DefaultModel(cfg.Provider)
 	}
-	if err := cfg.Validate(); err != nil {
-		log.Fatal(err)
+	if cfg.EmbedModel == "" {
+		cfg.EmbedModel = config.DefaultEmbedModel(cfg.Provider)
+	}
+	if cfg.EnsembleProvider != "" && cfg.EnsembleModel == "" {
+		cfg.EnsembleModel = config.DefaultModel(cfg.EnsembleProvider)
+	}
+	if cfg.JudgeProvider != "" && cfg.JudgeModel == "" {
+		cfg.JudgeModel = config.DefaultModel(cfg.JudgeProvider)
+	}
+	for i, j := range cfg.ExtraJudges {
+		if j.Model == "" {
+			cfg.ExtraJudges[i].Model = config.DefaultModel(j.Provider)
+		}
 	}
 
 	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
 	defer cancel()
 
-	if err := run(ctx, &cfg); err != nil {
+	if estimate {
+		if err := cfg.ValidateCrawl(); err != nil {
+			log.Fatal(err)
+		}
+		if err := runEstimate(ctx, &cfg); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	if reanalyze {
+		if err := runAnalyze(ctx, &cfg, opts); err != nil {
+			fatal(err)
+		}
+		return
+	}
+
+	if bench {
+		if err := runBench(ctx, &cfg, benchOpts); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	if err := cfg.Validate(); err != nil {
 		log.Fatal(err)
 	}
+	if err := run(ctx, &cfg); err != nil {
+		fatal(err)
+	}
 }
 
 func configureFlags(fs *flag.FlagSet, cfg *config.Config, provider *string) {
-	fs.StringVar(provider, "provider", "anthropic", "LLM provider: openai, anthropic, ollama")
+	fs.StringVar(provider, "provider", "anthropic", "LLM provider: openai, anthropic, ollama, openrouter, deepseek")
 	fs.StringVar(&cfg.Model, "model", "", "LLM model (default: per-provider)")
-	fs.StringVar(&cfg.OutputDir, "output", "./output", "Output directory for generated skills")
+	fs.Func("ensemble-provider", "Also run every analysis on this provider and reconcile the results: openai, anthropic, ollama, openrouter, deepseek", func(s string) error {
+		cfg.EnsembleProvider = llm.ProviderName(s)
+		return nil
+	})
+	fs.StringVar(&cfg.EnsembleModel, "ensemble-model", "", "Model for -ensemble-provider (default: per-provider)")
+	fs.Func("openrouter-order", "Comma-separated upstream providers OpenRouter tries first, in order, such as anthropic,amazon-bedrock", func(s string) error {
+		cfg.OpenRouter.Order = commaList(s)
+		return nil
+	})
+	fs.BoolVar(&cfg.OpenRouter.OnlyOrder, "openrouter-only", false, "Only let OpenRouter use the -openrouter-order providers instead of falling back to others")
+	fs.Func("fallback-models", "Comma-separated OpenRouter models tried in order when -model is down, rate limited, or refuses a request", func(s string) error {
+		cfg.OpenRouter.FallbackModels = commaList(s)
+		return nil
+	})
+	fs.IntVar(&cfg.LLMRetries, "llm-retries", config.DefaultLLMRetries, "Times an LLM call is retried, after a growing, jittered wait, when it hits a rate limit, an overloaded server, or a network error (0 disables)")
+	fs.IntVar(&cfg.ContextWindow, "context-window", 0, "Model context window in tokens used to size prompts (default: per-model)")
+	fs.StringVar(&cfg.EmbedModel, "embed-model", "", "Embedding model used to fold near-identical review comments and cluster interest areas (default: per-provider; \"none\" disables)")
+	fs.StringVar(&cfg.OutputDir, "output", "./output", "Output directory for generated skills, or - for stdout")
+	fs.Var(streamFlag{&cfg.Stream}, "stdout", "Write the generated files to stdout, each after a \"==> path <==\" line, instead of -output; -stdout=json writes one JSON object of path to content. Same as -output -")
+	fs.Var(previewFlag{&cfg.Stream}, "preview", "Show the generated files on stdout, listed and then one after another, without writing anything. Same as -stdout=preview")
+	fs.Func("format", skill.FormatUsage(), func(s string) error {
+		formats, err := skill.ParseFormats(s)
+		cfg.Formats = formats
+		return err
+	})
+	fs.Func("skills", skill.SkillsUsage(), func(s string) error {
+		skills, err := skill.ParseSkills(s)
+		cfg.Skills = skills
+		return err
+	})
+	fs.Func("skill-data", "Also write each skill's frontmatter and sections as structured data next to its SKILL.md: json (skill.json), yaml (skill.yaml), or both", func(s string) error {
+		formats, err := skill.ParseDataFormats(s)
+		cfg.SkillData = formats
+		return err
+	})
+	fs.Func("frontmatter", "Extra key=value for every skill's frontmatter, such as license=MIT, version=1.2.0, tags=go,review, or allowed-tools=Read,Grep (repeatable; tags is written as a list)", func(s string) error {
+		field, err := skill.ParseFrontmatterField(s)
+		cfg.Frontmatter = append(cfg.Frontmatter, field)
+		return err
+	})
+	fs.Func("archive", "Also pack the generated files, with a manifest, into <output>/<username>-skills.zip or .tar.gz: zip or tar.gz", func(s string) error {
+		format, err := skill.ParseArchiveFormat(s)
+		cfg.Archive = format
+		return err
+	})
+	fs.BoolVar(&cfg.Update, "update", false, "Refresh only the devlica-managed block of files already in -output, keeping edits made outside it")
+	fs.BoolVar(&cfg.Versioned, "versioned", false, "Write into a new <output>/<username>/<timestamp> directory with a manifest, and point <output>/<username>/latest at it, instead of overwriting the last run")
+	fs.Func("compare-eras", "Analyze two year ranges separately (e.g. 2019-2021,2022-2024) and write a report on how the developer changed instead of skills", func(s string) error {
+		windows, err := config.ParseEraWindows(s)
+		cfg.CompareEras = windows
+		return err
+	})
+	fs.Func("recency", "Sample recent commits, reviews, and comments more heavily for the analyses: exp:HALF_LIFE, linear:SPAN, or step:AGE, with spans such as 2y, 18mo, 8w, or 30d", func(s string) error {
+		curve, err := config.ParseRecencyCurve(s)
+		cfg.Recency = curve
+		return err
+	})
+	fs.IntVar(&cfg.BenchSamples, "bench-samples", benchmark.MaxHeldOut, "Maximum review comments, PR descriptions, commit messages, and issue replies each held out to benchmark and refine the persona; 10% of each are, but at least 3 (0 skips the benchmark)")
+	fs.Func("judge-provider", "Score the benchmark and refine the persona with this provider instead of -provider: openai, anthropic, ollama, openrouter, deepseek", func(s string) error {
+		cfg.JudgeProvider = llm.ProviderName(s)
+		return nil
+	})
+	fs.StringVar(&cfg.JudgeModel, "judge-model", "", "Model for -judge-provider (default: per-provider)")
+	fs.Func("extra-judge", "Another benchmark judge as provider or provider:model, whose scores are combined with -judge-aggregate (repeatable, up to 2)", func(s string) error {
+		judge, err := config.ParseJudge(s)
+		cfg.ExtraJudges = append(cfg.ExtraJudges, judge)
+		return err
+	})
+	fs.StringVar(&cfg.JudgeAggregate, "judge-aggregate", string(benchmark.AggregateMedian), "How several judges' scores are combined: median or trimmed-mean")
+	fs.Float64Var(&cfg.BenchPlateau, "bench-plateau", benchmark.PlateauGain, "Stop refining when the benchmark score gains less than this over two iterations (0 never stops early)")
+	fs.Func("bench-rubric", "Weights of the review comparison dimensions, as concern=2,tone=0.5 (dimensions: "+strings.Join(benchmark.Dimensions, ", ")+"; default 1 each)", func(s string) error {
+		r, err := benchmark.ParseRubric(s)
+		cfg.BenchRubric = r
+		return err
+	})
+	fs.BoolVar(&cfg.BenchBaseline, "bench-baseline", false, "Also score a generic senior engineer persona on the held-out samples and report the persona's lift over it")
+	fs.BoolVar(&cfg.BenchHuman, "bench-human", false, "Show each benchmark sample side by side and ask for your score and a note for refinement")
+	fs.Float64Var(&cfg.BenchHumanWeight, "bench-human-weight", 0.5, "Weight of your -bench-human scores against the judges', from 0 to 1")
+	fs.Float64Var(&cfg.BenchTarget, "bench-target", benchmark.TargetScore, "Benchmark score out of 100 at which refinement stops")
+	fs.IntVar(&cfg.BenchIterations, "bench-iterations", benchmark.MaxIterations, "Maximum benchmark iterations; all but the last refine the persona")
+	fs.IntVar(&cfg.MaxSkillTokens, "max-skill-tokens", 0, "Condense the persona with one more LLM call when any skill's synthesized sections exceed this many tokens (default: no limit)")
+	fs.BoolVar(&cfg.Grounding, "grounding", false, "After synthesis, check every persona statement against the crawled data and drop unsupported ones (one extra LLM call)")
+	fs.StringVar(&cfg.PersonaOut, "persona-out", "", "Also write the full persona (analyses, synthesis, metrics, metadata) as JSON to this file")
+	fs.StringVar(&cfg.CrawlOut, "crawl-out", "", "Also save the crawled data as JSON to this file, for devlica analyze")
 	fs.IntVar(&cfg.MaxRepos, "max-repos", 10, "Maximum repositories to deep-crawl (commits, PRs, code samples)")
+	fs.IntVar(&cfg.Concurrency, "concurrency", 5, "Maximum repositories crawled in parallel (throttled automatically as rate limit drops)")
 	fs.BoolVar(&cfg.Exhaustive, "exhaustive", false, "Crawl exhaustive public GitHub activity data (disables sampling caps)")
+	fs.BoolVar(&cfg.UseGitClone, "use-git-clone", false, "Read commit patches from a shallow git clone instead of one API call per commit (requires git)")
+	fs.StringVar(&cfg.GHArchive, "gharchive", "", "Directory or glob of GH Archive dumps (.json.gz) to backfill activity older than the Events API window")
+	fs.StringVar(&cfg.RepoStrategy, "repo-strategy", ghcrawl.StrategyDiverse, "How to pick repos to deep-crawl: "+strings.Join(ghcrawl.RepoStrategies, ", "))
+	fs.BoolVar(&cfg.ShowSelection, "show-selection", false, "Print which repos were selected for deep crawling and why before crawling them")
+	fs.StringVar(&cfg.HTTPCacheDir, "http-cache", "", "Directory for a persistent cache of GitHub API responses (disabled when empty)")
+	fs.DurationVar(&cfg.HTTPCacheTTL, "http-cache-ttl", time.Hour, "Reuse cached responses younger than this without asking GitHub; older ones are revalidated")
+	fs.BoolVar(&cfg.Anonymize, "anonymize", false, "Redact emails, name, company, location, and other profile details before LLM calls and in skill files")
+	fs.StringVar(&cfg.PromptsDir, "prompts-dir", "", "Directory of <name>.tmpl files that override the built-in LLM prompts")
 	fs.BoolVar(&cfg.Verbose, "verbose", false, "Enable verbose logging")
 }
 
-func run(ctx context.Context, cfg *config.Config) error {
+// loadPrompts reads prompt overrides from dir. It returns nil, meaning the
+// built-in prompts, when dir is empty.
+func loadPrompts(dir string) (*prompts.Set, error) {
+	if dir == "" {
+		return nil, nil
+	}
+	known := append(slices.Clone(analyzer.PromptNames), benchmark.PromptNames...)
+	set, err := prompts.Load(dir, known)
+	if err != nil {
+		return nil, fmt.Errorf("loading prompt overrides: %w", err)
+	}
+	slog.Info("using prompt overrides", "dir", dir, "prompts", strings.Join(set.Names(), ", "))
+	return set, nil
+}
+
+// streamFlag is -stdout, a boolean flag that also takes a stream format:
+// bare it streams text, and -stdout=json streams JSON.
+type streamFlag struct{ format *skill.StreamFormat }
+
+func (f streamFlag) String() string {
+	if f.format == nil {
+		return ""
+	}
+	return string(*f.format)
+}
+
+func (f streamFlag) Set(s string) error {
+	switch s {
+	case "true":
+		*f.format = skill.StreamText
+	case "false":
+		*f.format = ""
+	default:
+		format, err := skill.ParseStreamFormat(s)
+		if err != nil {
+			return err
+		}
+		*f.format = format
+	}
+	return nil
+}
+
+func (f streamFlag) IsBoolFlag() bool { return true }
+
+// previewFlag is -preview, a boolean flag for -stdout=preview.
+type previewFlag struct{ format *skill.StreamFormat }
+
+func (f previewFlag) String() string {
+	if f.format == nil || *f.format != skill.StreamPreview {
+		return "false"
+	}
+	return "true"
+}
+
+func (f previewFlag) Set(s string) error {
+	on, err := strconv.ParseBool(s)
+	if err != nil {
+		return err
+	}
+	if on {
+		*f.format = skill.StreamPreview
+	} else if *f.format == skill.StreamPreview {
+		*f.format = ""
+	}
+	return nil
+}
+
+func (f previewFlag) IsBoolFlag() bool { return true }
+
+// newGenerator returns a skill generator for -output, streaming to stdout
+// when stream is set.
+func newGenerator(outputDir string, stream skill.StreamFormat) *skill.Generator {
+	if stream == "" {
+		return skill.NewGenerator(outputDir)
+	}
+	gen := skill.NewGenerator("")
+	gen.SetStream(os.Stdout, stream)
+	return gen
+}
+
+func setupLogging(verbose bool) {
 	level := slog.LevelInfo
-	if cfg.Verbose {
+	if verbose {
 		level = slog.LevelDebug
 	}
 	slog.SetDefault(slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: level})))
+}
+
+func run(ctx context.Context, cfg *config.Config) error {
+	setupLogging(cfg.Verbose)
 
 	slog.Info("starting devlica", "username", cfg.Username, "provider", cfg.Provider, "model", cfg.Model)
 	if cfg.Provider == llm.ProviderAnthropic {
@@ -91,79 +376,967 @@ func run(ctx context.Context, cfg *config.Config) error {
 	}
 
 	slog.Info("token pool", "tokens", len(cfg.GitHubTokens), "private_token", cfg.PrivateToken != "")
-	crawler := ghcrawl.NewCrawler(cfg.GitHubTokens, cfg.PrivateToken, cfg.MaxRepos, cfg.Exhaustive)
+	if len(cfg.GitHubTokens) == 0 {
+		slog.Warn("GITHUB_TOKEN is not set, crawl will run in degraded mode")
+	}
+	if err := enableHTTPCache(cfg); err != nil {
+		return err
+	}
+	promptSet, err := loadPrompts(cfg.PromptsDir)
+	if err != nil {
+		return err
+	}
+	crawler := newCrawler(cfg)
 	slog.Info("crawling github activity")
 	result, err := crawler.Crawl(ctx, cfg.Username)
 	if err != nil {
 		return fmt.Errorf("crawling github: %w", err)
 	}
+	crawledAt := time.Now()
+	crawlHash, err := ghcrawl.Hash(result)
+	if err != nil {
+		return err
+	}
 	slog.Info("crawl complete",
-		"repos", len(result.Repos),
+		"repos", result.TotalRepos(),
 		"commits", result.TotalCommits(),
 		"reviews", result.TotalReviews(),
 		"issue_comments", len(result.IssueComments),
 		"authored_issues", result.TotalIssues(),
 		"external_prs", result.TotalExternalPRs(),
 		"starred_repos", result.TotalStarred(),
+		"watched_repos", len(result.WatchedRepos),
+		"starred_gists", len(result.StarredGists),
 		"gists", result.TotalGists(),
 		"releases", result.TotalReleases(),
+		"dependency_manifests", result.TotalDependencies(),
+		"docs", result.TotalDocs(),
+		"triaged_issues", result.TotalTriage(),
 		"events", len(result.Events),
 		"orgs", len(result.Orgs),
 		"discussions", result.TotalDiscussions(),
 		"projects", result.TotalProjects(),
 	)
+	if len(result.Skipped) > 0 {
+		fmt.Fprintf(os.Stderr, "\nSkipped during crawl:\n")
+		for _, s := range result.Skipped {
+			fmt.Fprintf(os.Stderr, "  - %s: %s\n", s.Source, s.Reason)
+		}
+		if crawler.Degraded() {
+			fmt.Fprintf(os.Stderr, "Set GITHUB_TOKEN for a full crawl.\n\n")
+		}
+	}
+	if cfg.HTTPCacheDir != "" {
+		hits, revalidated, misses := ghcrawl.HTTPCacheStats()
+		slog.Info("http cache", "hits", hits, "revalidated", revalidated, "downloaded", misses)
+	}
+	if len(result.Warnings) > 0 {
+		fmt.Fprintf(os.Stderr, "\nIncomplete data (%d failed fetches; the persona is built without them):\n", len(result.Warnings))
+		ghcrawl.WriteWarnings(os.Stderr, result.Warnings)
+		fmt.Fprintln(os.Stderr)
+	}
+	signed, verified, _ := result.SigningStats()
+	slog.Info("commit signing", "signed", signed, "verified", verified, "total", result.TotalCommits())
 	logLikelyUpstreamTruncation(result, cfg.Exhaustive)
 
-	heldOut := benchmark.SplitReviews(result, benchmark.MaxHeldOut)
-	slog.Info("held out reviews for benchmark", "count", len(heldOut), "remaining_reviews", result.TotalReviews())
+	// The crawl is saved unredacted so that devlica analyze can apply
+	// -anonymize itself; the file is only readable by its owner.
+	if cfg.CrawlOut != "" {
+		if err := ghcrawl.WriteCrawl(cfg.CrawlOut, result); err != nil {
+			return err
+		}
+		slog.Info("saved crawl", "path", cfg.CrawlOut)
+	}
+
+	// The redactor captures the profile before scrubbing, since scrubbing
+	// replaces the very values it looks for.
+	var redactor *redact.Redactor
+	if cfg.Anonymize {
+		redactor = redact.ForProfile(result.User)
+		redactor.Scrub(result)
+		slog.Info("anonymized crawled data")
+	}
+
+	a, provider, err := newAnalyzer(cfg, promptSet)
+	if err != nil {
+		return err
+	}
+	if len(cfg.CompareEras) > 0 {
+		return compareEras(ctx, cfg, a, result, redactor)
+	}
+	saveAnalyses(cfg, a, redactor)
+
+	counts := analyzer.CountData(result)
+	heldOut := benchmark.SplitHeldOut(result, cfg.BenchSamples)
+	slog.Info("held out samples for benchmark",
+		"reviews", len(heldOut.Reviews),
+		"pr_descriptions", len(heldOut.PRs),
+		"commit_messages", len(heldOut.Commits),
+		"remaining_reviews", result.TotalReviews(),
+	)
+
+	slog.Info("analyzing developer persona")
+	persona, err := a.Analyze(ctx, cfg.Username, result)
+	if err != nil {
+		return fmt.Errorf("analyzing persona: %w", err)
+	}
+	persona.Usage.Log()
+
+	if heldOut.Len() > 0 {
+		persona, err = benchmarkPersona(ctx, cfg, provider, promptSet, persona, heldOut, redactor)
+		if err != nil {
+			return err
+		}
+		// Refinement rewrites the synthesis, so it may need condensing again.
+		if err := a.FitSkillBudget(ctx, persona); err != nil {
+			return err
+		}
+	} else if cfg.BenchSamples > 0 {
+		slog.Warn("no reviews, PR descriptions, or commits to hold out, skipping benchmark")
+	}
+
+	meta := analyzer.PersonaMetadata{
+		CrawledAt:  crawledAt,
+		CrawlHash:  crawlHash,
+		DataCounts: counts,
+	}
+	meta.BenchmarkScore = persona.Metadata.BenchmarkScore
+	if heldOut.Len() > 0 && cfg.JudgeProvider != "" {
+		meta.JudgeProvider = string(cfg.JudgeProvider)
+		meta.JudgeModel = cfg.JudgeModel
+	}
+	return finish(cfg, persona, redactor, meta)
+}
+
+// newCrawler creates a crawler with the crawl flags in cfg applied.
+func newCrawler(cfg *config.Config) *ghcrawl.Crawler {
+	crawler := ghcrawl.NewCrawler(cfg.GitHubTokens, cfg.PrivateToken, cfg.MaxRepos, cfg.Exhaustive, cfg.Concurrency)
+	if cfg.UseGitClone {
+		crawler.UseGitClone()
+	}
+	if cfg.GHArchive != "" {
+		crawler.UseGHArchive(cfg.GHArchive)
+	}
+	crawler.SetRepoStrategy(cfg.RepoStrategy)
+	if cfg.ShowSelection {
+		crawler.ShowSelection(os.Stderr)
+	}
+	return crawler
+}
 
+// newAnalyzer creates the LLM provider named by cfg and an analyzer using
+// it, with the ensemble provider and context window applied.
+func newAnalyzer(cfg *config.Config, promptSet *prompts.Set) (*analyzer.Analyzer, llm.Provider, error) {
+	embedModel := cfg.EmbedModel
+	if embedModel == config.EmbedModelNone {
+		embedModel = ""
+	}
 	provider, err := llm.NewProvider(llm.ProviderConfig{
 		Name:            cfg.Provider,
 		APIKey:          cfg.APIKey,
 		Model:           cfg.Model,
+		EmbedModel:      embedModel,
 		OllamaHost:      cfg.OllamaHost,
 		UseVertexAI:     cfg.UseVertexAI,
 		VertexRegion:    cfg.VertexRegion,
 		VertexProjectID: cfg.VertexProjectID,
+		OpenRouter:      cfg.OpenRouter,
+		Retry:           llm.RetryPolicy{MaxRetries: cfg.LLMRetries},
 	})
 	if err != nil {
-		return fmt.Errorf("creating LLM provider: %w", err)
+		return nil, nil, fmt.Errorf("creating LLM provider: %w", err)
 	}
 	a := analyzer.New(provider)
-	slog.Info("analyzing developer persona")
-	persona, err := a.Analyze(ctx, cfg.Username, result)
+	a.SetPrompts(promptSet)
+	a.SetProgress(printProgress)
+	a.SetGrounding(cfg.Grounding)
+	a.SetSkillBudget(cfg.MaxSkillTokens, skill.SkillFields())
+	if cfg.Recency.Enabled() {
+		a.SetRecency(cfg.Recency.Weight)
+		slog.Info("recency weighting enabled", "shape", cfg.Recency.Shape, "span", cfg.Recency.Span)
+	}
+	window := cfg.ContextWindow
+	if window == 0 {
+		window = llm.ContextWindow(cfg.Provider, cfg.Model)
+	}
+	if cfg.EnsembleProvider != "" {
+		second, err := llm.NewProvider(llm.ProviderConfig{
+			Name:            cfg.EnsembleProvider,
+			APIKey:          cfg.EnsembleAPIKey,
+			Model:           cfg.EnsembleModel,
+			OllamaHost:      cfg.OllamaHost,
+			UseVertexAI:     cfg.UseVertexAI,
+			VertexRegion:    cfg.VertexRegion,
+			VertexProjectID: cfg.VertexProjectID,
+			OpenRouter:      secondaryRouting(cfg),
+			Retry:           llm.RetryPolicy{MaxRetries: cfg.LLMRetries},
+		})
+		if err != nil {
+			return nil, nil, fmt.Errorf("creating ensemble LLM provider: %w", err)
+		}
+		a.SetEnsemble(second)
+		// Both models see the same prompts, so the smaller window decides.
+		if cfg.ContextWindow == 0 {
+			window = min(window, llm.ContextWindow(cfg.EnsembleProvider, cfg.EnsembleModel))
+		}
+		slog.Info("ensemble analysis enabled", "provider", cfg.EnsembleProvider, "model", cfg.EnsembleModel)
+	}
+	a.SetContextWindow(window)
+	slog.Info("context window", "tokens", window)
+	return a, provider, nil
+}
+
+// newJudge creates the provider that scores benchmark dry runs and refines
+// the persona.
+func newJudge(cfg *config.Config, j config.JudgeConfig) (benchmark.Judge, error) {
+	judge, err := llm.NewProvider(llm.ProviderConfig{
+		Name:            j.Provider,
+		APIKey:          j.APIKey,
+		Model:           j.Model,
+		OllamaHost:      cfg.OllamaHost,
+		UseVertexAI:     cfg.UseVertexAI,
+		VertexRegion:    cfg.VertexRegion,
+		VertexProjectID: cfg.VertexProjectID,
+		OpenRouter:      secondaryRouting(cfg),
+		Retry:           llm.RetryPolicy{MaxRetries: cfg.LLMRetries},
+	})
 	if err != nil {
-		return fmt.Errorf("analyzing persona: %w", err)
+		return benchmark.Judge{}, fmt.Errorf("creating judge LLM provider %s: %w", j.Name(), err)
+	}
+	return benchmark.Judge{Name: j.Name(), Provider: judge}, nil
+}
+
+// secondaryRouting is the OpenRouter routing of the ensemble and judge
+// providers: the provider order, without the primary model's fallbacks.
+func secondaryRouting(cfg *config.Config) llm.OpenRouterRouting {
+	routing := cfg.OpenRouter
+	routing.FallbackModels = nil
+	return routing
+}
+
+// commaList splits a comma-separated flag value, dropping empty entries.
+func commaList(s string) []string {
+	var list []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			list = append(list, v)
+		}
 	}
+	return list
+}
 
-	if len(heldOut) > 0 {
-		bench := benchmark.New(provider)
-		slog.Info("benchmarking persona quality")
-		benchResult, refined, err := bench.Run(ctx, persona, heldOut)
+// newJudges returns the benchmark judges: -judge-provider, or the primary
+// provider when extra judges need company, followed by each -extra-judge.
+// It returns none when the primary provider judges alone.
+func newJudges(cfg *config.Config, primary llm.Provider) ([]benchmark.Judge, error) {
+	if cfg.JudgeProvider == "" && len(cfg.ExtraJudges) == 0 {
+		return nil, nil
+	}
+	var judges []benchmark.Judge
+	if cfg.JudgeProvider == "" {
+		judges = append(judges, benchmark.Judge{Name: string(cfg.Provider) + "/" + cfg.Model, Provider: primary})
+	} else {
+		judge, err := newJudge(cfg, config.JudgeConfig{Provider: cfg.JudgeProvider, Model: cfg.JudgeModel, APIKey: cfg.JudgeAPIKey})
+		if err != nil {
+			return nil, err
+		}
+		judges = append(judges, judge)
+	}
+	for _, j := range cfg.ExtraJudges {
+		judge, err := newJudge(cfg, j)
 		if err != nil {
-			return fmt.Errorf("benchmarking persona: %w", err)
+			return nil, err
 		}
-		persona = refined
-		fmt.Fprintf(os.Stderr, "\nBenchmark: score=%.1f/100 iterations=%d\n", benchResult.FinalScore, benchResult.Iterations)
-		for _, iter := range benchResult.History {
-			fmt.Fprintf(os.Stderr, "  iteration %d: score=%.1f\n", iter.Iteration, iter.Score)
+		judges = append(judges, judge)
+	}
+	return judges, nil
+}
+
+// benchmarkPersona scores persona on heldOut and refines it as the
+// benchmark flags say, prints the scores, and writes the benchmark report
+// under <output>/<username>. It returns the refined persona.
+func benchmarkPersona(ctx context.Context, cfg *config.Config, provider llm.Provider, promptSet *prompts.Set, persona *analyzer.Persona, heldOut benchmark.HeldOut, redactor *redact.Redactor) (*analyzer.Persona, error) {
+	bench := benchmark.New(provider)
+	bench.SetPrompts(promptSet)
+	bench.SetProgress(printProgress)
+	bench.SetTarget(cfg.BenchTarget)
+	bench.SetMaxIterations(cfg.BenchIterations)
+	bench.SetPlateau(cfg.BenchPlateau)
+	bench.SetRubric(cfg.BenchRubric)
+	bench.SetBaseline(cfg.BenchBaseline)
+	if cfg.BenchHuman {
+		if fi, err := os.Stdin.Stat(); err != nil || fi.Mode()&os.ModeCharDevice == 0 {
+			return nil, fmt.Errorf("-bench-human needs a terminal on stdin")
+		}
+		bench.SetHuman(benchmark.TerminalRater(os.Stdin, os.Stderr), cfg.BenchHumanWeight)
+	}
+	judges, err := newJudges(cfg, provider)
+	if err != nil {
+		return nil, err
+	}
+	bench.SetJudges(judges, benchmark.Aggregate(cfg.JudgeAggregate))
+	for _, j := range judges {
+		slog.Info("benchmark judge enabled", "judge", j.Name)
+	}
+	slog.Info("benchmarking persona quality")
+	benchResult, refined, err := bench.Run(ctx, persona, heldOut)
+	if err != nil {
+		return nil, fmt.Errorf("benchmarking persona: %w", err)
+	}
+	fmt.Fprintf(os.Stderr, "\nBenchmark: score=%.1f/100 iterations=%d", benchResult.FinalScore, benchResult.Iterations)
+	if benchResult.Selected != benchResult.Iterations {
+		fmt.Fprintf(os.Stderr, " selected=%d", benchResult.Selected)
+	}
+	if benchResult.StopReason == benchmark.StopPlateau {
+		fmt.Fprintf(os.Stderr, " stopped=plateau")
+	}
+	if baseline := benchResult.FormatBaseline(); baseline != "" {
+		fmt.Fprintf(os.Stderr, " %s", baseline)
+	}
+	if calibration := benchResult.FormatCalibration(); calibration != "" {
+		fmt.Fprintf(os.Stderr, " %s", calibration)
+	}
+	fmt.Fprintln(os.Stderr)
+	for _, iter := range benchResult.History {
+		fmt.Fprintf(os.Stderr, "  iteration %d: score=%.1f", iter.Iteration, iter.Score)
+		if facets := iter.FormatFacets(); facets != "" {
+			fmt.Fprintf(os.Stderr, " (%s)", facets)
+		}
+		if byJudge := iter.FormatJudges(judges); byJudge != "" {
+			fmt.Fprintf(os.Stderr, " judges: %s", byJudge)
 		}
 		fmt.Fprintln(os.Stderr)
-	} else {
-		slog.Warn("no reviews with diff context available, skipping benchmark")
 	}
+	fmt.Fprintln(os.Stderr)
+	refined.Metadata.BenchmarkScore = &benchResult.FinalScore
+	if redactor != nil {
+		redactor.Scrub(benchResult)
+	}
+	if cfg.Stream != "" {
+		slog.Info("streaming to stdout, so the benchmark report and history are not written")
+		return refined, nil
+	}
+	paths, err := benchmark.WriteReport(filepath.Join(cfg.OutputDir, cfg.Username), cfg.Username, benchResult)
+	if err != nil {
+		return nil, err
+	}
+	slog.Info("wrote benchmark report", "paths", strings.Join(paths, ","))
+
+	rec := benchmark.NewRunRecord(benchResult, heldOut.Len())
+	rec.Provider = string(cfg.Provider)
+	rec.Model = cfg.Model
+	for _, j := range judges {
+		rec.Judges = append(rec.Judges, j.Name)
+	}
+	rec.Prompts = promptSet.Hash()
+	rec.DevlicaVersion = config.Version()
+	trackBenchmark(filepath.Join(cfg.OutputDir, cfg.Username, benchmark.HistoryFile), rec)
+	return refined, nil
+}
 
-	gen := skill.NewGenerator(cfg.OutputDir)
+// trackBenchmark appends rec to the benchmark history at path and prints
+// how it compares with the previous run, flagging a regression. History
+// is a convenience, so failures are only logged.
+func trackBenchmark(path string, rec benchmark.RunRecord) {
+	runs, err := benchmark.ReadHistory(path)
+	if err != nil {
+		slog.Warn("skipping benchmark history", "err", err)
+		return
+	}
+	if err := benchmark.AppendHistory(path, rec); err != nil {
+		slog.Warn("could not record benchmark history", "err", err)
+	}
+	if len(runs) == 0 {
+		return
+	}
+	prev := runs[len(runs)-1]
+	delta, changes := benchmark.Delta(prev, rec)
+	fmt.Fprintf(os.Stderr, "Benchmark vs previous run (%s): %+.1f", prev.Time.Format(time.DateOnly), delta)
+	if len(changes) > 0 {
+		fmt.Fprintf(os.Stderr, "; changed: %s", strings.Join(changes, ", "))
+	}
+	fmt.Fprintln(os.Stderr)
+	if delta <= -benchmark.RegressionDrop {
+		slog.Warn("benchmark regression: the persona scores worse than the previous run", "previous", prev.Score, "score", rec.Score, "changed", strings.Join(changes, ", "))
+	}
+	fmt.Fprintln(os.Stderr)
+}
+
+// saveAnalyses streams each analysis to <output>/<username>/analysis as it
+// completes, redacted the same way as the persona when anonymizing.
+func saveAnalyses(cfg *config.Config, a *analyzer.Analyzer, redactor *redact.Redactor) {
+	if cfg.Stream != "" {
+		return
+	}
+	var filter func(string) string
+	if redactor != nil {
+		filter = redactor.String
+	}
+	dir := filepath.Join(cfg.OutputDir, cfg.Username, "analysis")
+	a.SetArtifactDir(dir, filter)
+	slog.Info("writing analyses as they complete", "dir", dir)
+}
+
+// printProgress prints each finished analysis or benchmark step with its
+// duration and estimated token use.
+func printProgress(e progress.Event) {
+	if e.Phase != progress.Finished {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "  done %-28s %6s  ~%d tokens\n", e.Step, e.Elapsed.Round(100*time.Millisecond), e.Tokens)
+}
+
+// finish prints the persona metrics, scrubs the persona when anonymizing,
+// writes -persona-out, and generates the skill files. meta carries the
+// crawl-specific fields; the rest is filled in from cfg.
+func finish(cfg *config.Config, persona *analyzer.Persona, redactor *redact.Redactor, meta analyzer.PersonaMetadata) error {
+	if metrics := persona.Metrics.Format(); metrics != "" {
+		fmt.Fprintf(os.Stderr, "Persona metrics:\n")
+		for _, line := range strings.Split(strings.TrimSuffix(metrics, "\n"), "\n") {
+			fmt.Fprintf(os.Stderr, "  %s\n", line)
+		}
+		fmt.Fprintln(os.Stderr)
+	}
+
+	// The model can reintroduce identifying details it inferred or
+	// remembered, so the persona is scrubbed again before it is written.
+	if redactor != nil {
+		redactor.Scrub(persona)
+	}
+
+	meta.Username = cfg.Username
+	meta.Provider = string(cfg.Provider)
+	meta.Model = cfg.Model
+	meta.EnsembleProvider = string(cfg.EnsembleProvider)
+	meta.EnsembleModel = cfg.EnsembleModel
+	meta.GeneratedAt = time.Now()
+	meta.Anonymized = cfg.Anonymize
+	meta.DevlicaVersion = config.Version()
+	persona.Metadata = meta
+
+	if cfg.PersonaOut != "" {
+		if err := analyzer.WritePersona(cfg.PersonaOut, analyzer.NewPersonaDocument(persona, meta)); err != nil {
+			return err
+		}
+		slog.Info("wrote persona", "path", cfg.PersonaOut)
+	}
+
+	outputDir := cfg.OutputDir
+	if cfg.Versioned {
+		outputDir = skill.VersionDir(cfg.OutputDir, cfg.Username, meta.GeneratedAt)
+	}
+	gen := newGenerator(outputDir, cfg.Stream)
+	gen.SetFormats(cfg.Formats)
+	gen.SetSkills(cfg.Skills)
+	gen.SetSkillData(cfg.SkillData)
+	gen.SetFrontmatter(cfg.Frontmatter)
+	gen.SetUpdate(cfg.Update)
 	slog.Info("generating skill files")
 	paths, err := gen.Generate(cfg.Username, persona)
 	if err != nil {
 		return fmt.Errorf("generating skills: %w", err)
 	}
+	if cfg.Versioned {
+		manifest, err := skill.FinishVersion(outputDir, cfg.Username, meta, paths)
+		if err != nil {
+			return err
+		}
+		slog.Info("wrote version", "dir", outputDir)
+		paths = append(paths, manifest)
+	}
+	if cfg.Archive != "" {
+		archive, err := skill.WriteArchive(outputDir, cfg.Username, meta, paths, cfg.Archive)
+		if err != nil {
+			return err
+		}
+		slog.Info("wrote archive", "path", archive)
+		paths = append(paths, archive)
+	}
 
-	for _, p := range paths {
-		fmt.Println(p)
+	if cfg.Stream == "" {
+		for _, p := range paths {
+			fmt.Println(p)
+		}
 	}
 	slog.Info("done", "skills_generated", len(paths))
+	if len(persona.Sparse) > 0 {
+		fmt.Fprintf(os.Stderr, "\nSparse profile: %s.\n", strings.Join(persona.Sparse, "; "))
+		fmt.Fprintf(os.Stderr, "The skills are a best-effort sketch with caveats, not a confident persona.\n")
+		fmt.Fprintf(os.Stderr, "Try -exhaustive, or GITHUB_PRIVATE_TOKEN if most of their work is in private repos.\n")
+		return errSparse
+	}
+	return nil
+}
+
+// analyzeOptions are the flags only devlica analyze takes.
+type analyzeOptions struct {
+	crawl   string
+	persona string
+	only    []string
+}
+
+func configureAnalyzeFlags(fs *flag.FlagSet, opts *analyzeOptions) {
+	fs.StringVar(&opts.crawl, "crawl", "", "Crawl JSON saved with -crawl-out (required)")
+	fs.StringVar(&opts.persona, "persona", "", "Persona JSON written by -persona-out whose other analyses are kept (required)")
+	fs.Func("only", "Comma-separated dimensions to re-run: "+strings.Join(analyzer.Dimensions, ", ")+" (required)", func(s string) error {
+		for _, d := range strings.Split(s, ",") {
+			d = strings.TrimSpace(d)
+			if !slices.Contains(analyzer.Dimensions, d) {
+				return fmt.Errorf("unknown dimension %q: must be one of %s", d, strings.Join(analyzer.Dimensions, ", "))
+			}
+			if !slices.Contains(opts.only, d) {
+				opts.only = append(opts.only, d)
+			}
+		}
+		return nil
+	})
+}
+
+// runAnalyze re-runs the dimensions in opts.only on a saved crawl, keeps
+// every other analysis from the saved persona, and synthesizes again. No
+// benchmark is run: the crawl still holds the reviews it would hold out.
+func runAnalyze(ctx context.Context, cfg *config.Config, opts analyzeOptions) error {
+	doc, err := analyzer.ReadPersona(opts.persona)
+	if err != nil {
+		return err
+	}
+	cfg.Username = doc.Metadata.Username
+	if cfg.Username == "" {
+		return fmt.Errorf("persona %s has no metadata.username", opts.persona)
+	}
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	setupLogging(cfg.Verbose)
+
+	result, err := ghcrawl.ReadCrawl(opts.crawl)
+	if err != nil {
+		return err
+	}
+	if !strings.EqualFold(result.User.Login, cfg.Username) {
+		return fmt.Errorf("crawl %s does not belong to %q", opts.crawl, cfg.Username)
+	}
+	crawlHash, err := ghcrawl.Hash(result)
+	if err != nil {
+		return err
+	}
+	if doc.Metadata.CrawlHash != "" && doc.Metadata.CrawlHash != crawlHash {
+		slog.Warn("the crawl differs from the one the persona was built from; kept analyses describe the older crawl", "crawl", opts.crawl)
+	}
+	promptSet, err := loadPrompts(cfg.PromptsDir)
+	if err != nil {
+		return err
+	}
+	var redactor *redact.Redactor
+	if cfg.Anonymize {
+		redactor = redact.ForProfile(result.User)
+		redactor.Scrub(result)
+		slog.Info("anonymized crawled data")
+	}
+	a, _, err := newAnalyzer(cfg, promptSet)
+	if err != nil {
+		return err
+	}
+	saveAnalyses(cfg, a, redactor)
+
+	slog.Info("re-analyzing developer persona", "username", cfg.Username, "dimensions", strings.Join(opts.only, ","))
+	persona, err := a.Reanalyze(ctx, cfg.Username, result, doc.Persona(), opts.only)
+	if err != nil {
+		return fmt.Errorf("analyzing persona: %w", err)
+	}
+	persona.Usage.Log()
+
+	return finish(cfg, persona, redactor, analyzer.PersonaMetadata{
+		CrawledAt:  doc.Metadata.CrawledAt,
+		CrawlHash:  crawlHash,
+		DataCounts: doc.Metadata.DataCounts,
+	})
+}
+
+// benchOptions are the flags only devlica bench takes.
+type benchOptions struct {
+	crawl   string
+	persona string
+}
+
+func configureBenchFlags(fs *flag.FlagSet, opts *benchOptions) {
+	fs.StringVar(&opts.persona, "persona", "", "Persona JSON written by -persona-out to benchmark (required)")
+	fs.StringVar(&opts.crawl, "crawl", "", "Crawl JSON saved with -crawl-out to hold samples out of (default: crawl GitHub again)")
+}
+
+// runBench runs only the benchmark loop on a saved persona, holding samples
+// out of a saved crawl or a fresh one. Held-out samples are picked the same
+// way as in a full run, so on the crawl the persona was built from they are
+// ones it never saw. The report goes to <output>/<username>, and the
+// refined persona to -persona-out.
+func runBench(ctx context.Context, cfg *config.Config, opts benchOptions) error {
+	doc, err := analyzer.ReadPersona(opts.persona)
+	if err != nil {
+		return err
+	}
+	cfg.Username = doc.Metadata.Username
+	if cfg.Username == "" {
+		return fmt.Errorf("persona %s has no metadata.username", opts.persona)
+	}
+	if doc.Synthesis == nil {
+		return fmt.Errorf("persona %s has no synthesis to benchmark", opts.persona)
+	}
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	if cfg.BenchSamples == 0 {
+		return fmt.Errorf("-bench-samples 0 leaves nothing to benchmark")
+	}
+	setupLogging(cfg.Verbose)
+
+	var result *ghcrawl.CrawlResult
+	if opts.crawl != "" {
+		result, err = ghcrawl.ReadCrawl(opts.crawl)
+		if err != nil {
+			return err
+		}
+	} else {
+		if err := enableHTTPCache(cfg); err != nil {
+			return err
+		}
+		slog.Info("crawling github activity for held-out samples")
+		result, err = newCrawler(cfg).Crawl(ctx, cfg.Username)
+		if err != nil {
+			return fmt.Errorf("crawling github: %w", err)
+		}
+	}
+	if !strings.EqualFold(result.User.Login, cfg.Username) {
+		return fmt.Errorf("crawl of %q does not belong to %q", result.User.Login, cfg.Username)
+	}
+	crawlHash, err := ghcrawl.Hash(result)
+	if err != nil {
+		return err
+	}
+	if doc.Metadata.CrawlHash != "" && crawlHash != doc.Metadata.CrawlHash {
+		slog.Warn("the crawl differs from the one the persona was built from; some held-out samples may have shaped the persona")
+	}
+	promptSet, err := loadPrompts(cfg.PromptsDir)
+	if err != nil {
+		return err
+	}
+	var redactor *redact.Redactor
+	if cfg.Anonymize {
+		redactor = redact.ForProfile(result.User)
+		redactor.Scrub(result)
+		slog.Info("anonymized crawled data")
+	}
+
+	heldOut := benchmark.SplitHeldOut(result, cfg.BenchSamples)
+	slog.Info("held out samples for benchmark",
+		"reviews", len(heldOut.Reviews),
+		"pr_descriptions", len(heldOut.PRs),
+		"commit_messages", len(heldOut.Commits),
+	)
+	if heldOut.Len() == 0 {
+		return fmt.Errorf("no reviews, PR descriptions, or commits to hold out for %s", cfg.Username)
+	}
+	a, provider, err := newAnalyzer(cfg, promptSet)
+	if err != nil {
+		return err
+	}
+	persona, err := benchmarkPersona(ctx, cfg, provider, promptSet, doc.Persona(), heldOut, redactor)
+	if err != nil {
+		return err
+	}
+	if err := a.FitSkillBudget(ctx, persona); err != nil {
+		return err
+	}
+
+	if cfg.PersonaOut != "" {
+		if redactor != nil {
+			redactor.Scrub(persona)
+		}
+		meta := doc.Metadata
+		meta.BenchmarkScore = persona.Metadata.BenchmarkScore
+		meta.JudgeProvider = string(cfg.JudgeProvider)
+		meta.JudgeModel = cfg.JudgeModel
+		if err := analyzer.WritePersona(cfg.PersonaOut, analyzer.NewPersonaDocument(persona, meta)); err != nil {
+			return err
+		}
+		slog.Info("wrote persona", "path", cfg.PersonaOut)
+	}
+	return nil
+}
+
+// compareEras analyzes each --compare-eras window of the crawl on its own
+// and writes a report on how the developer changed between them.
+func compareEras(ctx context.Context, cfg *config.Config, a *analyzer.Analyzer, result *ghcrawl.CrawlResult, redactor *redact.Redactor) error {
+	var eras []analyzer.Era
+	for _, w := range cfg.CompareEras {
+		data := result.Window(w.From, w.To)
+		counts := analyzer.CountData(data)
+		slog.Info("analyzing era", "era", w.Label, "commits", counts.Commits, "reviews", counts.Reviews, "issue_comments", counts.IssueComments)
+		if counts.Commits+counts.Reviews+counts.IssueComments == 0 {
+			slog.Warn("no commits, reviews, or comments in era; -exhaustive crawls older activity", "era", w.Label)
+		}
+		persona, err := a.Analyze(ctx, cfg.Username, data)
+		if err != nil {
+			return fmt.Errorf("analyzing era %s: %w", w.Label, err)
+		}
+		eras = append(eras, analyzer.Era{Label: w.Label, Persona: persona})
+	}
+	report, err := a.CompareEras(ctx, cfg.Username, eras[0], eras[1])
+	if err != nil {
+		return err
+	}
+	if redactor != nil {
+		report = redactor.String(report)
+	}
+
+	content := fmt.Sprintf("# %s: %s vs %s\n\n%s\n", cfg.Username, eras[0].Label, eras[1].Label, strings.TrimSpace(report))
+	if cfg.Stream != "" {
+		fmt.Print(content)
+		return nil
+	}
+	if err := os.MkdirAll(cfg.OutputDir, 0o755); err != nil {
+		return fmt.Errorf("creating output directory: %w", err)
+	}
+	path := filepath.Join(cfg.OutputDir, cfg.Username+"-era-comparison.md")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		return fmt.Errorf("writing era comparison: %w", err)
+	}
+	fmt.Println(path)
+	slog.Info("done", "report", path)
+	return nil
+}
+
+// runLint checks every SKILL.md under the given directories against the
+// skill spec, as generation does before writing one, so skills edited by
+// hand can be checked before they are shared.
+func runLint(args []string) error {
+	fs := flag.NewFlagSet("devlica lint", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: devlica lint <dir> [dir ...]\n\nChecks every SKILL.md under each directory against the skill spec.\n")
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		os.Exit(1)
+	}
+	for _, dir := range fs.Args() {
+		n, err := skill.LintDir(dir)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s: %d skills pass\n", dir, n)
+	}
+	return nil
+}
+
+// runGenerate writes skill files from persona JSON instead of crawling and
+// analyzing. Each -merge document is applied over -persona in order, so a
+// partial analysis refreshes only the fields it carries.
+func runGenerate(args []string) error {
+	fs := flag.NewFlagSet("devlica generate", flag.ExitOnError)
+	var personaPath, outputDir, personaOut string
+	var merges []string
+	var formats []skill.Format
+	var skills []string
+	var skillData []skill.DataFormat
+	var frontmatter []skill.FrontmatterField
+	var archive skill.ArchiveFormat
+	var stream skill.StreamFormat
+	var updateOutput, versioned, verbose bool
+	fs.StringVar(&personaPath, "persona", "", "Persona JSON written by -persona-out (required)")
+	fs.Func("merge", "Partial persona JSON whose non-empty fields replace those of -persona (repeatable)", func(s string) error {
+		merges = append(merges, s)
+		return nil
+	})
+	fs.StringVar(&outputDir, "output", "./output", "Output directory for generated skills, or - for stdout")
+	fs.Var(streamFlag{&stream}, "stdout", "Write the generated files to stdout, each after a \"==> path <==\" line, instead of -output; -stdout=json writes one JSON object of path to content. Same as -output -")
+	fs.Var(previewFlag{&stream}, "preview", "Show the generated files on stdout, listed and then one after another, without writing anything. Same as -stdout=preview")
+	fs.Func("format", skill.FormatUsage(), func(s string) error {
+		var err error
+		formats, err = skill.ParseFormats(s)
+		return err
+	})
+	fs.Func("skills", skill.SkillsUsage(), func(s string) error {
+		var err error
+		skills, err = skill.ParseSkills(s)
+		return err
+	})
+	fs.Func("skill-data", "Also write each skill's frontmatter and sections as structured data next to its SKILL.md: json (skill.json), yaml (skill.yaml), or both", func(s string) error {
+		var err error
+		skillData, err = skill.ParseDataFormats(s)
+		return err
+	})
+	fs.Func("frontmatter", "Extra key=value for every skill's frontmatter, such as license=MIT, version=1.2.0, tags=go,review, or allowed-tools=Read,Grep (repeatable; tags is written as a list)", func(s string) error {
+		field, err := skill.ParseFrontmatterField(s)
+		frontmatter = append(frontmatter, field)
+		return err
+	})
+	fs.Func("archive", "Also pack the generated files, with a manifest, into <output>/<username>-skills.zip or .tar.gz: zip or tar.gz", func(s string) error {
+		var err error
+		archive, err = skill.ParseArchiveFormat(s)
+		return err
+	})
+	fs.BoolVar(&updateOutput, "update", false, "Refresh only the devlica-managed block of files already in -output, keeping edits made outside it")
+	fs.BoolVar(&versioned, "versioned", false, "Write into a new <output>/<username>/<timestamp> directory with a manifest, and point <output>/<username>/latest at it, instead of overwriting the last run")
+	fs.StringVar(&personaOut, "persona-out", "", "Also write the merged persona as JSON to this file")
+	fs.BoolVar(&verbose, "verbose", false, "Enable verbose logging")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if personaPath == "" || fs.NArg() != 0 {
+		fs.Usage()
+		os.Exit(1)
+	}
+	if outputDir == "-" && stream == "" {
+		stream = skill.StreamText
+	}
+	if err := config.ValidateOutput(stream, archive, updateOutput, versioned); err != nil {
+		return err
+	}
+	setupLogging(verbose)
+
+	doc, err := analyzer.ReadPersona(personaPath)
+	if err != nil {
+		return err
+	}
+	username := doc.Metadata.Username
+	if username == "" {
+		return fmt.Errorf("persona %s has no metadata.username", personaPath)
+	}
+	persona := doc.Persona()
+	for _, p := range merges {
+		update, err := analyzer.ReadPersona(p)
+		if err != nil {
+			return err
+		}
+		if !strings.EqualFold(update.Metadata.Username, username) {
+			return fmt.Errorf("cannot merge %s: it describes %q, not %q", p, update.Metadata.Username, username)
+		}
+		if mixed := mixedProvenance(doc.Metadata, update.Metadata); mixed != "" {
+			slog.Warn("merged persona was produced differently; the skills will mix them", "path", p, "differs_in", mixed)
+		}
+		persona = analyzer.MergePersona(persona, update.Persona())
+		doc.Metadata.Anonymized = doc.Metadata.Anonymized || update.Metadata.Anonymized
+		// The score was for the persona before the merge.
+		doc.Metadata.BenchmarkScore = nil
+		slog.Info("merged persona", "path", p)
+	}
+	if persona.Synthesis == nil {
+		return fmt.Errorf("persona %s has no synthesis to generate skills from", personaPath)
+	}
+
+	meta := doc.Metadata
+	meta.GeneratedAt = time.Now()
+	persona.Metadata = meta
+	if personaOut != "" {
+		if err := analyzer.WritePersona(personaOut, analyzer.NewPersonaDocument(persona, meta)); err != nil {
+			return err
+		}
+		slog.Info("wrote persona", "path", personaOut)
+	}
+
+	if versioned {
+		outputDir = skill.VersionDir(outputDir, username, meta.GeneratedAt)
+	}
+	gen := newGenerator(outputDir, stream)
+	gen.SetFormats(formats)
+	gen.SetSkills(skills)
+	gen.SetSkillData(skillData)
+	gen.SetFrontmatter(frontmatter)
+	gen.SetUpdate(updateOutput)
+	paths, err := gen.Generate(username, persona)
+	if err != nil {
+		return fmt.Errorf("generating skills: %w", err)
+	}
+	if versioned {
+		manifest, err := skill.FinishVersion(outputDir, username, meta, paths)
+		if err != nil {
+			return err
+		}
+		slog.Info("wrote version", "dir", outputDir)
+		paths = append(paths, manifest)
+	}
+	if archive != "" {
+		path, err := skill.WriteArchive(outputDir, username, meta, paths, archive)
+		if err != nil {
+			return err
+		}
+		slog.Info("wrote archive", "path", path)
+		paths = append(paths, path)
+	}
+	if stream == "" {
+		for _, p := range paths {
+			fmt.Println(p)
+		}
+	}
+	slog.Info("done", "skills_generated", len(paths))
+	return nil
+}
+
+// mixedProvenance names what differs between how two personas of the same
+// user were produced, or returns "" when they came from the same models and
+// crawl. Unrecorded values, as in older persona files, are not compared.
+func mixedProvenance(base, update analyzer.PersonaMetadata) string {
+	var differs []string
+	for _, f := range []struct{ name, a, b string }{
+		{"provider", base.Provider, update.Provider},
+		{"model", base.Model, update.Model},
+		{"ensemble model", base.EnsembleModel, update.EnsembleModel},
+		{"crawl", base.CrawlHash, update.CrawlHash},
+	} {
+		if f.a != "" && f.b != "" && f.a != f.b {
+			differs = append(differs, f.name)
+		}
+	}
+	return strings.Join(differs, ", ")
+}
+
+func enableHTTPCache(cfg *config.Config) error {
+	if cfg.HTTPCacheDir == "" {
+		return nil
+	}
+	if err := ghcrawl.UseHTTPCache(cfg.HTTPCacheDir, cfg.HTTPCacheTTL); err != nil {
+		return fmt.Errorf("enabling http cache: %w", err)
+	}
+	slog.Info("http cache enabled", "dir", cfg.HTTPCacheDir, "ttl", cfg.HTTPCacheTTL)
+	return nil
+}
+
+// runEstimate prints a pre-flight approximation of the API calls and time a
+// crawl would need, without calling any LLM.
+func runEstimate(ctx context.Context, cfg *config.Config) error {
+	setupLogging(cfg.Verbose)
+
+	if err := enableHTTPCache(cfg); err != nil {
+		return err
+	}
+	crawler := ghcrawl.NewCrawler(cfg.GitHubTokens, cfg.PrivateToken, cfg.MaxRepos, cfg.Exhaustive, cfg.Concurrency)
+	crawler.SetRepoStrategy(cfg.RepoStrategy)
+	slog.Info("estimating crawl cost", "username", cfg.Username)
+	est, err := crawler.Estimate(ctx, cfg.Username)
+	if err != nil {
+		return fmt.Errorf("estimating crawl: %w", err)
+	}
+
+	fmt.Printf("Crawl estimate for %s\n", cfg.Username)
+	fmt.Printf("  repos:                %d (deep-crawled: %d)\n", est.Repos, est.DeepRepos)
+	fmt.Printf("  pull requests:        %d (in deep-crawled repos)\n", est.PullRequests)
+	fmt.Printf("  commits:              %d (default branches of deep-crawled repos)\n", est.Commits)
+	fmt.Printf("  commented issues/PRs: %d\n", est.IssueSearchHits)
+	fmt.Printf("  external PRs:         %d\n", est.ExternalPRs)
+	fmt.Printf("  external reviews:     %d\n", est.ExternalReviews)
+	fmt.Printf("  API calls:            ~%d\n", est.APICalls)
+	fmt.Printf("  remaining quota:      %d across %d token(s)\n", est.RemainingQuota, est.Tokens)
+	fmt.Printf("  estimated time:       ~%s\n", est.Duration.Round(time.Second))
+	if len(est.Selection) > 0 {
+		fmt.Printf("\nRepos selected for deep crawling (--repo-strategy %s):\n", cfg.RepoStrategy)
+		ghcrawl.WriteSelection(os.Stdout, est.Selection)
+	}
+	if est.RateLimitedWaits > 0 {
+		fmt.Printf("\nThe crawl exceeds the remaining quota and will wait for %d rate-limit reset(s).\n", est.RateLimitedWaits)
+		fmt.Printf("Lower --max-repos, drop --exhaustive, or add tokens (GITHUB_TOKEN_1, ...) to avoid waiting.\n")
+	}
 	return nil
 }
 
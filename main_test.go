@@ -1,11 +1,18 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"io"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/drpaneas/devlica/internal/analyzer"
 	"github.com/drpaneas/devlica/internal/config"
+	"github.com/drpaneas/devlica/internal/ghcrawl"
+	"github.com/drpaneas/devlica/internal/llm"
 )
 
 func TestConfigureFlags_ExhaustiveDefaultIsFalse(t *testing.T) {
@@ -39,3 +46,207 @@ func TestConfigureFlags_ExhaustiveCanBeEnabled(t *testing.T) {
 		t.Fatalf("expected --exhaustive to enable exhaustive mode")
 	}
 }
+
+func TestConfigureFlags_UseGitClone(t *testing.T) {
+	var cfg config.Config
+	var provider string
+	fs := flag.NewFlagSet("devlica-test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+
+	configureFlags(fs, &cfg, &provider)
+	if err := fs.Parse([]string{"--use-git-clone"}); err != nil {
+		t.Fatalf("parse flags: %v", err)
+	}
+
+	if !cfg.UseGitClone {
+		t.Fatalf("expected --use-git-clone to enable clone-based commit collection")
+	}
+}
+
+func TestConfigureFlags_RepoStrategy(t *testing.T) {
+	var cfg config.Config
+	var provider string
+	fs := flag.NewFlagSet("devlica-test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+
+	configureFlags(fs, &cfg, &provider)
+	if err := fs.Parse(nil); err != nil {
+		t.Fatalf("parse flags: %v", err)
+	}
+	if cfg.RepoStrategy != "diverse" {
+		t.Fatalf("expected --repo-strategy default to be diverse, got %q", cfg.RepoStrategy)
+	}
+
+	if err := fs.Parse([]string{"--repo-strategy", "popular", "--show-selection"}); err != nil {
+		t.Fatalf("parse flags: %v", err)
+	}
+	if cfg.RepoStrategy != "popular" || !cfg.ShowSelection {
+		t.Fatalf("expected popular strategy with selection shown, got %q, %v", cfg.RepoStrategy, cfg.ShowSelection)
+	}
+}
+
+func TestConfigureFlags_BenchKnobs(t *testing.T) {
+	var cfg config.Config
+	var provider string
+	fs := flag.NewFlagSet("devlica-test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+
+	configureFlags(fs, &cfg, &provider)
+	if err := fs.Parse(nil); err != nil {
+		t.Fatalf("parse flags: %v", err)
+	}
+	if cfg.BenchSamples != 10 || cfg.BenchTarget != 80 || cfg.BenchIterations != 5 {
+		t.Fatalf("expected benchmark defaults 10/80/5, got %d/%v/%d", cfg.BenchSamples, cfg.BenchTarget, cfg.BenchIterations)
+	}
+
+	if err := fs.Parse([]string{"--bench-samples", "10", "--bench-target", "90", "--bench-iterations", "2"}); err != nil {
+		t.Fatalf("parse flags: %v", err)
+	}
+	if cfg.BenchSamples != 10 || cfg.BenchTarget != 90 || cfg.BenchIterations != 2 {
+		t.Fatalf("expected benchmark knobs 10/90/2, got %d/%v/%d", cfg.BenchSamples, cfg.BenchTarget, cfg.BenchIterations)
+	}
+}
+
+func TestConfigureFlags_CompareEras(t *testing.T) {
+	var cfg config.Config
+	var provider string
+	fs := flag.NewFlagSet("devlica-test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+
+	configureFlags(fs, &cfg, &provider)
+	if err := fs.Parse([]string{"--compare-eras", "2019-2021,2022-2024"}); err != nil {
+		t.Fatalf("parse flags: %v", err)
+	}
+	if len(cfg.CompareEras) != 2 || cfg.CompareEras[1].Label != "2022-2024" {
+		t.Fatalf("expected two era windows, got %+v", cfg.CompareEras)
+	}
+
+	fs = flag.NewFlagSet("devlica-test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	configureFlags(fs, &cfg, &provider)
+	if err := fs.Parse([]string{"--compare-eras", "2019-2021"}); err == nil {
+		t.Fatal("expected an error for a single era")
+	}
+}
+
+func TestConfigureAnalyzeFlags_Only(t *testing.T) {
+	var opts analyzeOptions
+	fs := flag.NewFlagSet("devlica-test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+
+	configureAnalyzeFlags(fs, &opts)
+	if err := fs.Parse([]string{"-only", "review-style, communication", "-only", "review-style"}); err != nil {
+		t.Fatalf("parse flags: %v", err)
+	}
+	if strings.Join(opts.only, ",") != "review-style,communication" {
+		t.Fatalf("expected deduplicated dimensions, got %v", opts.only)
+	}
+
+	fs = flag.NewFlagSet("devlica-test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	configureAnalyzeFlags(fs, &opts)
+	if err := fs.Parse([]string{"-only", "review-tone"}); err == nil {
+		t.Fatal("expected an error for an unknown dimension")
+	}
+}
+
+func TestRunGenerateMergesPersona(t *testing.T) {
+	dir := t.TempDir()
+	base := &analyzer.Persona{Synthesis: &analyzer.SynthesisResult{
+		CodeStyleRules: "- Wrap every error.",
+		ReviewVoice:    "Old voice.",
+	}}
+	update := &analyzer.Persona{Synthesis: &analyzer.SynthesisResult{ReviewVoice: "New voice."}}
+	basePath := filepath.Join(dir, "base.json")
+	updatePath := filepath.Join(dir, "update.json")
+	if err := analyzer.WritePersona(basePath, analyzer.NewPersonaDocument(base, analyzer.PersonaMetadata{Username: "alice"})); err != nil {
+		t.Fatal(err)
+	}
+	if err := analyzer.WritePersona(updatePath, analyzer.NewPersonaDocument(update, analyzer.PersonaMetadata{Username: "alice"})); err != nil {
+		t.Fatal(err)
+	}
+
+	out := filepath.Join(dir, "skills")
+	merged := filepath.Join(dir, "merged.json")
+	if err := runGenerate([]string{"-persona", basePath, "-merge", updatePath, "-output", out, "-persona-out", merged}); err != nil {
+		t.Fatal(err)
+	}
+
+	reviewer, err := os.ReadFile(filepath.Join(out, "alice-code-reviewer", "SKILL.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(reviewer), "New voice.") {
+		t.Error("reviewer skill should use the merged review voice")
+	}
+	coding, err := os.ReadFile(filepath.Join(out, "alice-coding-style", "SKILL.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(coding), "Wrap every error.") {
+		t.Error("coding style skill should keep fields the merge did not touch")
+	}
+	doc, err := analyzer.ReadPersona(merged)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc.Synthesis.ReviewVoice != "New voice." || doc.Synthesis.CodeStyleRules != "- Wrap every error." {
+		t.Errorf("merged persona = %+v", doc.Synthesis)
+	}
+}
+
+func TestRunGenerateRejectsOtherUser(t *testing.T) {
+	dir := t.TempDir()
+	p := &analyzer.Persona{Synthesis: &analyzer.SynthesisResult{ReviewVoice: "x"}}
+	alice := filepath.Join(dir, "alice.json")
+	bob := filepath.Join(dir, "bob.json")
+	if err := analyzer.WritePersona(alice, analyzer.NewPersonaDocument(p, analyzer.PersonaMetadata{Username: "alice"})); err != nil {
+		t.Fatal(err)
+	}
+	if err := analyzer.WritePersona(bob, analyzer.NewPersonaDocument(p, analyzer.PersonaMetadata{Username: "bob"})); err != nil {
+		t.Fatal(err)
+	}
+	err := runGenerate([]string{"-persona", alice, "-merge", bob, "-output", filepath.Join(dir, "out")})
+	if err == nil {
+		t.Fatal("merging another user's persona should fail")
+	}
+}
+
+func TestRunBenchRejectsOtherUsersCrawl(t *testing.T) {
+	dir := t.TempDir()
+	personaPath := filepath.Join(dir, "alice.json")
+	p := &analyzer.Persona{Synthesis: &analyzer.SynthesisResult{ReviewVoice: "x"}}
+	if err := analyzer.WritePersona(personaPath, analyzer.NewPersonaDocument(p, analyzer.PersonaMetadata{Username: "alice"})); err != nil {
+		t.Fatal(err)
+	}
+	crawlPath := filepath.Join(dir, "bob-crawl.json")
+	if err := ghcrawl.WriteCrawl(crawlPath, &ghcrawl.CrawlResult{User: ghcrawl.UserProfile{Login: "bob"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &config.Config{Provider: llm.ProviderOllama, Model: "llama3.1", MaxRepos: 10, BenchSamples: 3, BenchTarget: 80, BenchIterations: 1, OutputDir: dir}
+	err := runBench(context.Background(), cfg, benchOptions{persona: personaPath, crawl: crawlPath})
+	if err == nil || !strings.Contains(err.Error(), "does not belong") {
+		t.Fatalf("runBench() error = %v, want a crawl ownership error", err)
+	}
+}
+
+func TestMixedProvenance(t *testing.T) {
+	base := analyzer.PersonaMetadata{Provider: "openai", Model: "gpt-4o", CrawlHash: "sha256:a"}
+	for _, tt := range []struct {
+		name   string
+		update analyzer.PersonaMetadata
+		want   string
+	}{
+		{"same", base, ""},
+		{"unrecorded", analyzer.PersonaMetadata{}, ""},
+		{"other model", analyzer.PersonaMetadata{Provider: "openai", Model: "gpt-4.1", CrawlHash: "sha256:a"}, "model"},
+		{"other provider and crawl", analyzer.PersonaMetadata{Provider: "anthropic", Model: "gpt-4o", CrawlHash: "sha256:b"}, "provider, crawl"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := mixedProvenance(base, tt.update); got != tt.want {
+				t.Errorf("mixedProvenance() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
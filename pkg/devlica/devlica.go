@@ -0,0 +1,467 @@
+// Package devlica exposes devlica's crawl, analyze, and generate pipeline
+// to other Go programs. It is a thin facade over the internal packages the
+// devlica command is built from: the types below are aliases, so values move
+// freely between this package and the persona JSON files the command reads
+// and writes. Being aliases, their fields change along with the internal
+// types; only the functions and option structs defined here are kept
+// compatible.
+//
+// A typical embedding crawls a user, analyzes the crawl, and writes skills:
+//
+//	data, err := devlica.Crawl(ctx, "octocat", devlica.CrawlOptions{Tokens: tokens})
+//	persona, err := devlica.Analyze(ctx, "octocat", data, devlica.AnalyzeOptions{
+//		LLM: devlica.LLMConfig{Name: devlica.ProviderOpenAI, APIKey: key},
+//	})
+//	paths, err := devlica.GenerateSkills("./output", "octocat", persona)
+package devlica
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/drpaneas/devlica/internal/analyzer"
+	"github.com/drpaneas/devlica/internal/benchmark"
+	"github.com/drpaneas/devlica/internal/config"
+	"github.com/drpaneas/devlica/internal/ghcrawl"
+	"github.com/drpaneas/devlica/internal/llm"
+	"github.com/drpaneas/devlica/internal/progress"
+	"github.com/drpaneas/devlica/internal/skill"
+)
+
+type (
+	// CrawlResult is everything collected about a GitHub user.
+	CrawlResult = ghcrawl.CrawlResult
+	// Persona is the analyzed developer persona skills are generated from.
+	Persona = analyzer.Persona
+	// SynthesisResult is the persona's synthesized, skill-ready fields.
+	SynthesisResult = analyzer.SynthesisResult
+	// PersonaDocument is the versioned JSON form of a persona.
+	PersonaDocument = analyzer.PersonaDocument
+	// PersonaMetadata records who a persona describes and how it was made.
+	PersonaMetadata = analyzer.PersonaMetadata
+	// ProgressEvent reports a pipeline step starting, finishing, or failing.
+	ProgressEvent = progress.Event
+	// ProgressFunc receives progress events; it may be called concurrently.
+	ProgressFunc = progress.Func
+	// LLMConfig selects and configures an LLM provider.
+	LLMConfig = llm.ProviderConfig
+	// ProviderName names a built-in LLM provider.
+	ProviderName = llm.ProviderName
+	// OpenRouterRouting picks the upstream providers and fallback models
+	// of an OpenRouter LLMConfig.
+	OpenRouterRouting = llm.OpenRouterRouting
+	// RetryPolicy retries the failed calls of an LLMConfig's provider.
+	// Zero MaxRetries takes the command's default; a negative one never
+	// retries.
+	RetryPolicy = llm.RetryPolicy
+	// Provider is an LLM completion backend. Implement it to run the
+	// analysis on a backend devlica has no built-in support for.
+	Provider = llm.Provider
+	// CompleteOptions tunes a single Provider completion.
+	CompleteOptions = llm.CompleteOptions
+	// Rater asks a person to score a benchmark sample.
+	Rater = benchmark.Rater
+	// HumanScore is a person's score of a benchmark sample.
+	HumanScore = benchmark.HumanScore
+)
+
+// Built-in LLM providers.
+const (
+	ProviderOpenAI     = llm.ProviderOpenAI
+	ProviderAnthropic  = llm.ProviderAnthropic
+	ProviderOllama     = llm.ProviderOllama
+	ProviderOpenRouter = llm.ProviderOpenRouter
+	ProviderDeepSeek   = llm.ProviderDeepSeek
+)
+
+// defaultMaxRepos matches the devlica command's -max-repos default.
+const defaultMaxRepos = 10
+
+// CrawlOptions configures Crawl. The zero value crawls public data
+// unauthenticated, which GitHub rate-limits heavily.
+type CrawlOptions struct {
+	// Tokens are GitHub tokens used round-robin for public data.
+	Tokens []string
+	// PrivateToken, when set, also crawls the user's private repos.
+	PrivateToken string
+	// MaxRepos bounds how many repos are deep-crawled. 0 uses the
+	// command's default of 10, or no bound when Exhaustive is set.
+	MaxRepos int
+	// Concurrency bounds how many repos are crawled in parallel; 0 uses
+	// the default.
+	Concurrency int
+	// Exhaustive lifts the per-source caps of a normal crawl.
+	Exhaustive bool
+	// UseGitClone reads commit history from shallow clones instead of the
+	// API.
+	UseGitClone bool
+	// GHArchive, when set, backfills activity older than the events API
+	// window from GH Archive dumps: a directory or glob of .json.gz files.
+	GHArchive string
+	// RepoStrategy picks which repos are deep-crawled; "" uses the default.
+	RepoStrategy string
+}
+
+// Crawl collects the GitHub activity of username.
+func Crawl(ctx context.Context, username string, opts CrawlOptions) (*CrawlResult, error) {
+	maxRepos := opts.MaxRepos
+	if maxRepos == 0 && !opts.Exhaustive {
+		maxRepos = defaultMaxRepos
+	}
+	crawler := ghcrawl.NewCrawler(opts.Tokens, opts.PrivateToken, maxRepos, opts.Exhaustive, opts.Concurrency)
+	if opts.UseGitClone {
+		crawler.UseGitClone()
+	}
+	if opts.GHArchive != "" {
+		crawler.UseGHArchive(opts.GHArchive)
+	}
+	if opts.RepoStrategy != "" {
+		crawler.SetRepoStrategy(opts.RepoStrategy)
+	}
+	result, err := crawler.Crawl(ctx, username)
+	if err != nil {
+		return nil, fmt.Errorf("crawling github: %w", err)
+	}
+	return result, nil
+}
+
+// AnalyzeOptions configures Analyze.
+type AnalyzeOptions struct {
+	// LLM selects the provider. Model and EmbedModel default to the
+	// provider's defaults when empty.
+	LLM LLMConfig
+	// Provider, when set, is used instead of LLM.
+	Provider Provider
+	// Ensemble, when set, runs every analysis on a second provider too and
+	// reconciles the two.
+	Ensemble *LLMConfig
+	// ContextWindow is the model's context window in tokens; 0 looks it up
+	// from LLM.
+	ContextWindow int
+	// Grounding drops persona statements the crawled data does not support.
+	Grounding bool
+	// MaxSkillTokens, when positive, condenses the persona as for
+	// -max-skill-tokens when any skill's synthesized sections exceed it.
+	MaxSkillTokens int
+	// Recency, when set, samples recent activity more heavily, with a curve
+	// written as for -recency: "exp:2y", "linear:10y", or "step:18mo".
+	Recency string
+	// Benchmark holds out a few reviews, PR descriptions, and commit
+	// messages, scores the persona on predicting them, and refines it. It
+	// removes the held-out samples from data.
+	Benchmark bool
+	// BenchSamples, BenchTarget, and BenchIterations tune the benchmark as
+	// -bench-samples, -bench-target, and -bench-iterations do; 0 uses the
+	// command's default.
+	BenchSamples    int
+	BenchTarget     float64
+	BenchIterations int
+	// BenchPlateau is -bench-plateau; nil uses the command's default, and
+	// a pointer to 0 never stops early on a plateau.
+	BenchPlateau *float64
+	// BenchRubric weights the review comparison dimensions, written as for
+	// -bench-rubric: "concern=2,tone=0.5".
+	BenchRubric string
+	// BenchBaseline also scores a generic senior engineer persona on the
+	// same samples; the lift over it is logged.
+	BenchBaseline bool
+	// Human, when set, is asked to score every benchmark sample too; its
+	// scores count HumanWeight, from 0 to 1, against the judges'.
+	Human       Rater
+	HumanWeight float64
+	// Judges, when set, score the benchmark instead of the provider that
+	// wrote the dry runs, their scores combined with JudgeAggregate
+	// ("median", the default, or "trimmed-mean"). The first also refines
+	// the persona.
+	Judges         []LLMConfig
+	JudgeAggregate string
+	// AnalysisDir, when set, receives each raw analysis as it completes.
+	AnalysisDir string
+	// Progress, when set, receives an event as each step starts and ends.
+	Progress ProgressFunc
+}
+
+// Analyze turns crawled data into a persona.
+func Analyze(ctx context.Context, username string, data *CrawlResult, opts AnalyzeOptions) (*Persona, error) {
+	provider := opts.Provider
+	window := opts.ContextWindow
+	if provider == nil {
+		cfg := withDefaults(opts.LLM)
+		var err error
+		provider, err = llm.NewProvider(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("creating LLM provider: %w", err)
+		}
+		if window == 0 {
+			window = llm.ContextWindow(cfg.Name, cfg.Model)
+		}
+	}
+	a := analyzer.New(provider)
+	a.SetProgress(opts.Progress)
+	a.SetGrounding(opts.Grounding)
+	if opts.MaxSkillTokens != 0 {
+		if opts.MaxSkillTokens < config.MinSkillTokens {
+			return nil, fmt.Errorf("MaxSkillTokens must be at least %d", config.MinSkillTokens)
+		}
+		a.SetSkillBudget(opts.MaxSkillTokens, skill.SkillFields())
+	}
+	if opts.Recency != "" {
+		curve, err := config.ParseRecencyCurve(opts.Recency)
+		if err != nil {
+			return nil, err
+		}
+		a.SetRecency(curve.Weight)
+	}
+	if opts.Ensemble != nil {
+		cfg := withDefaults(*opts.Ensemble)
+		cfg.EmbedModel = ""
+		second, err := llm.NewProvider(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("creating ensemble LLM provider: %w", err)
+		}
+		a.SetEnsemble(second)
+		// Both models see the same prompts, so the smaller window decides.
+		if opts.ContextWindow == 0 && window != 0 {
+			window = min(window, llm.ContextWindow(cfg.Name, cfg.Model))
+		}
+	}
+	if window != 0 {
+		a.SetContextWindow(window)
+	}
+	if opts.AnalysisDir != "" {
+		a.SetArtifactDir(opts.AnalysisDir, nil)
+	}
+
+	var heldOut benchmark.HeldOut
+	if opts.Benchmark {
+		heldOut = benchmark.SplitHeldOut(data, cmp.Or(opts.BenchSamples, benchmark.MaxHeldOut))
+	}
+	persona, err := a.Analyze(ctx, username, data)
+	if err != nil {
+		return nil, fmt.Errorf("analyzing persona: %w", err)
+	}
+	if heldOut.Len() == 0 {
+		return persona, nil
+	}
+	bench := benchmark.New(provider)
+	bench.SetProgress(opts.Progress)
+	bench.SetTarget(cmp.Or(opts.BenchTarget, benchmark.TargetScore))
+	bench.SetMaxIterations(cmp.Or(opts.BenchIterations, benchmark.MaxIterations))
+	if opts.BenchRubric != "" {
+		rubric, err := benchmark.ParseRubric(opts.BenchRubric)
+		if err != nil {
+			return nil, err
+		}
+		bench.SetRubric(rubric)
+	}
+	if opts.BenchPlateau != nil {
+		bench.SetPlateau(*opts.BenchPlateau)
+	}
+	bench.SetBaseline(opts.BenchBaseline)
+	if opts.Human != nil {
+		bench.SetHuman(opts.Human, opts.HumanWeight)
+	}
+	var judges []benchmark.Judge
+	for _, j := range opts.Judges {
+		cfg := withDefaults(j)
+		cfg.EmbedModel = ""
+		judge, err := llm.NewProvider(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("creating judge LLM provider: %w", err)
+		}
+		judges = append(judges, benchmark.Judge{Name: string(cfg.Name) + "/" + cfg.Model, Provider: judge})
+	}
+	bench.SetJudges(judges, benchmark.Aggregate(cmp.Or(opts.JudgeAggregate, string(benchmark.AggregateMedian))))
+	result, refined, err := bench.Run(ctx, persona, heldOut)
+	if err != nil {
+		return nil, fmt.Errorf("benchmarking persona: %w", err)
+	}
+	slog.Info("benchmarked persona", "score", result.FinalScore, "iterations", result.Iterations)
+	refined.Metadata.BenchmarkScore = &result.FinalScore
+	if result.Baseline != nil {
+		slog.Info("benchmark baseline", "score", result.Baseline.Score, "lift", result.Lift())
+	}
+	if result.Calibration != nil {
+		slog.Info("benchmark calibration", "calibrated_score", result.Calibration.Score, "judge_spread", result.Calibration.Spread)
+	}
+	if err := a.FitSkillBudget(ctx, refined); err != nil {
+		return nil, err
+	}
+	return refined, nil
+}
+
+// withDefaults fills in the provider's default models and retries.
+func withDefaults(cfg LLMConfig) LLMConfig {
+	if cfg.Model == "" {
+		cfg.Model = config.DefaultModel(cfg.Name)
+	}
+	if cfg.Retry.MaxRetries == 0 {
+		cfg.Retry.MaxRetries = config.DefaultLLMRetries
+	}
+	switch cfg.EmbedModel {
+	case "":
+		cfg.EmbedModel = config.DefaultEmbedModel(cfg.Name)
+	case config.EmbedModelNone:
+		cfg.EmbedModel = ""
+	}
+	return cfg
+}
+
+// SkillOptions selects what GenerateSkills writes. The zero value writes
+// the default Cursor skills.
+type SkillOptions struct {
+	// Formats are written as for -format: "cursor", "copilot", "agents",
+	// "claude", "continue", "aider", "windsurf", and so on.
+	Formats []string
+	// Skills selects the Cursor skills as for -skills: "code-reviewer"
+	// writes only that one, "+pr-author" adds one to the defaults.
+	Skills string
+	// SkillData also writes each skill as structured data, as for
+	// -skill-data: "json", "yaml", or both.
+	SkillData []string
+	// Frontmatter adds keys to every skill's frontmatter, as -frontmatter
+	// does, such as "license": "MIT" or "tags": "go,review".
+	Frontmatter map[string]string
+	// Update refreshes only the devlica-managed block of files that
+	// already exist, as for -update, keeping edits made outside it.
+	Update bool
+	// Archive also packs the written files, with a manifest, into
+	// outputDir/username-skills.zip or .tar.gz, as for -archive: "zip" or
+	// "tar.gz". The archive's path is returned last.
+	Archive string
+	// Versioned writes into a new outputDir/username/<timestamp>
+	// directory with a manifest, as for -versioned, and points
+	// outputDir/username/latest at it. The manifest's path follows the
+	// generated files.
+	Versioned bool
+	// Stream, when set, receives the files instead of outputDir, which is
+	// left untouched; the returned paths are relative to it. StreamFormat
+	// is "text", the default, or "json", as for -stdout.
+	Stream       io.Writer
+	StreamFormat string
+}
+
+// GenerateSkills writes the persona's skills under outputDir/username and
+// returns the paths written. At most one opts is used.
+func GenerateSkills(outputDir, username string, p *Persona, opts ...SkillOptions) ([]string, error) {
+	if p.Synthesis == nil {
+		return nil, fmt.Errorf("persona for %s has no synthesis to generate skills from", username)
+	}
+	versioned := len(opts) > 0 && opts[0].Versioned
+	if versioned {
+		outputDir = skill.VersionDir(outputDir, username, time.Now())
+	}
+	gen := skill.NewGenerator(outputDir)
+	var archive skill.ArchiveFormat
+	if len(opts) > 0 {
+		if len(opts[0].Formats) > 0 {
+			formats, err := skill.ParseFormats(strings.Join(opts[0].Formats, ","))
+			if err != nil {
+				return nil, err
+			}
+			gen.SetFormats(formats)
+		}
+		if opts[0].Skills != "" {
+			skills, err := skill.ParseSkills(opts[0].Skills)
+			if err != nil {
+				return nil, err
+			}
+			gen.SetSkills(skills)
+		}
+		if len(opts[0].SkillData) > 0 {
+			formats, err := skill.ParseDataFormats(strings.Join(opts[0].SkillData, ","))
+			if err != nil {
+				return nil, err
+			}
+			gen.SetSkillData(formats)
+		}
+		var frontmatter []skill.FrontmatterField
+		for _, k := range slices.Sorted(maps.Keys(opts[0].Frontmatter)) {
+			field, err := skill.ParseFrontmatterField(k + "=" + opts[0].Frontmatter[k])
+			if err != nil {
+				return nil, err
+			}
+			frontmatter = append(frontmatter, field)
+		}
+		gen.SetFrontmatter(frontmatter)
+		gen.SetUpdate(opts[0].Update)
+		if opts[0].Archive != "" {
+			format, err := skill.ParseArchiveFormat(opts[0].Archive)
+			if err != nil {
+				return nil, err
+			}
+			archive = format
+		}
+		if opts[0].Stream != nil {
+			format := skill.StreamText
+			if opts[0].StreamFormat != "" {
+				var err error
+				if format, err = skill.ParseStreamFormat(opts[0].StreamFormat); err != nil {
+					return nil, err
+				}
+			}
+			if err := config.ValidateOutput(format, archive, opts[0].Update, opts[0].Versioned); err != nil {
+				return nil, err
+			}
+			gen.SetStream(opts[0].Stream, format)
+		} else if err := config.ValidateOutput("", archive, opts[0].Update, opts[0].Versioned); err != nil {
+			return nil, err
+		}
+	}
+	paths, err := gen.Generate(username, p)
+	if err != nil {
+		return nil, fmt.Errorf("generating skills: %w", err)
+	}
+	if versioned {
+		path, err := skill.FinishVersion(outputDir, username, p.Metadata, paths)
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, path)
+	}
+	if archive != "" {
+		path, err := skill.WriteArchive(outputDir, username, p.Metadata, paths, archive)
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
+// NewPersonaDocument wraps a persona and its metadata for WritePersona.
+func NewPersonaDocument(p *Persona, meta PersonaMetadata) PersonaDocument {
+	return analyzer.NewPersonaDocument(p, meta)
+}
+
+// ReadPersona reads a persona JSON file, as written by devlica -persona-out.
+func ReadPersona(path string) (PersonaDocument, error) {
+	return analyzer.ReadPersona(path)
+}
+
+// WritePersona writes a persona JSON file that devlica generate can read.
+func WritePersona(path string, doc PersonaDocument) error {
+	return analyzer.WritePersona(path, doc)
+}
+
+// MergePersona returns base with every non-empty field of update applied.
+func MergePersona(base, update *Persona) *Persona {
+	return analyzer.MergePersona(base, update)
+}
+
+// ReadCrawl reads a crawl saved by devlica -crawl-out.
+func ReadCrawl(path string) (*CrawlResult, error) {
+	return ghcrawl.ReadCrawl(path)
+}
+
+// WriteCrawl saves a crawl for a later Analyze or devlica analyze.
+func WriteCrawl(path string, data *CrawlResult) error {
+	return ghcrawl.WriteCrawl(path, data)
+}
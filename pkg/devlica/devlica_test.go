@@ -0,0 +1,81 @@
+package devlica
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/drpaneas/devlica/internal/config"
+)
+
+func TestWithDefaults(t *testing.T) {
+	tests := []struct {
+		name      string
+		cfg       LLMConfig
+		wantModel string
+		wantEmbed string
+	}{
+		{"openai defaults", LLMConfig{Name: ProviderOpenAI}, "gpt-4o", "text-embedding-3-small"},
+		{"explicit model kept", LLMConfig{Name: ProviderOllama, Model: "qwen2.5"}, "qwen2.5", "nomic-embed-text"},
+		{"embeddings disabled", LLMConfig{Name: ProviderOpenAI, EmbedModel: "none"}, "gpt-4o", ""},
+		{"anthropic has no embeddings", LLMConfig{Name: ProviderAnthropic}, "claude-opus-4-6", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := withDefaults(tt.cfg)
+			if got.Retry.MaxRetries != config.DefaultLLMRetries {
+				t.Errorf("Retry.MaxRetries = %d, want the command's default", got.Retry.MaxRetries)
+			}
+			if got.Model != tt.wantModel || got.EmbedModel != tt.wantEmbed {
+				t.Errorf("withDefaults() = model %q embed %q, want %q %q", got.Model, got.EmbedModel, tt.wantModel, tt.wantEmbed)
+			}
+		})
+	}
+}
+
+func TestAnalyzeUnknownProvider(t *testing.T) {
+	_, err := Analyze(context.Background(), "testdev", &CrawlResult{}, AnalyzeOptions{LLM: LLMConfig{Name: "nope"}})
+	if err == nil || !strings.Contains(err.Error(), "creating LLM provider") {
+		t.Fatalf("Analyze() error = %v, want a provider error", err)
+	}
+}
+
+func TestGenerateSkillsFromSavedPersona(t *testing.T) {
+	dir := t.TempDir()
+	persona := &Persona{
+		Username:  "testdev",
+		CodeStyle: "Uses snake_case everywhere.",
+		Synthesis: &SynthesisResult{
+			CodingPhilosophy: "Values performance over readability.",
+			ReviewPriorities: "1. Performance\n2. Correctness",
+		},
+	}
+	path := filepath.Join(dir, "persona.json")
+	if err := WritePersona(path, NewPersonaDocument(persona, PersonaMetadata{Username: "testdev"})); err != nil {
+		t.Fatalf("WritePersona() error = %v", err)
+	}
+	doc, err := ReadPersona(path)
+	if err != nil {
+		t.Fatalf("ReadPersona() error = %v", err)
+	}
+
+	paths, err := GenerateSkills(filepath.Join(dir, "out"), doc.Metadata.Username, doc.Persona())
+	if err != nil {
+		t.Fatalf("GenerateSkills() error = %v", err)
+	}
+	if len(paths) == 0 {
+		t.Fatal("GenerateSkills() wrote no skills")
+	}
+	for _, p := range paths {
+		if !strings.HasPrefix(p, filepath.Join(dir, "out", "testdev")) {
+			t.Errorf("skill %s is outside the user's output directory", p)
+		}
+	}
+}
+
+func TestGenerateSkillsRequiresSynthesis(t *testing.T) {
+	if _, err := GenerateSkills(t.TempDir(), "testdev", &Persona{Username: "testdev"}); err == nil {
+		t.Fatal("GenerateSkills() without a synthesis succeeded")
+	}
+}